@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,6 +15,25 @@ type Minio struct {
 	BucketName string
 }
 
+const (
+	StorageBackendMinio   = "minio"
+	StorageBackendLocalFS = "localfs"
+)
+
+type Storage struct {
+	// Backend is where objects are stored: minio, or localfs for the local
+	// disk below LocalPath.
+	Backend   string
+	LocalPath string
+
+	// ColdBackend enables the cold tier lifecycle rules move files to. It is
+	// off when empty; minio keeps cold objects in ColdBucketName of the same
+	// server, localfs below ColdLocalPath.
+	ColdBackend    string
+	ColdBucketName string
+	ColdLocalPath  string
+}
+
 type Db struct {
 	Host     string
 	Port     string
@@ -28,6 +48,10 @@ type App struct {
 	JwtSecret string
 	LogToFile string
 	LogLevel  string
+
+	// TrustedProxies are the addresses and CIDR ranges whose X-Forwarded-For
+	// header is believed when working out the client address.
+	TrustedProxies []string
 }
 
 type FileServer struct {
@@ -43,6 +67,7 @@ type FileServer struct {
 	ChunkSize          int64
 	ChunkUploadTimeout time.Duration
 	ChunkedSessionTTL  time.Duration
+	JanitorInterval    time.Duration
 
 	BufferSize      int
 	DownloadTimeout time.Duration
@@ -52,13 +77,53 @@ type FileServer struct {
 
 	MaxFailuresBeforeOpen int
 	CircuitBreakerTimeout time.Duration
+
+	MaxFileVersions int
+
+	TrashRetentionDays int
+	TrashPurgeInterval time.Duration
+
+	PresignedURLExpiry time.Duration
+
+	ReconcileGracePeriod time.Duration
+
+	ArchiveMaxSize int64
+
+	FolderDeleteAsyncThreshold int
+	FolderCopyAsyncThreshold   int
+	FolderJobWorkerInterval    time.Duration
+
+	ContentIndexInterval time.Duration
+	ContentIndexMaxSize  int64
+	ContentIndexLanguage string
+
+	ThumbnailMaxSourceSize int64
+	ThumbnailMaxPixels     int
+	ThumbnailWorkers       int
+
+	ScanBackend        string
+	ScanClamdAddress   string
+	ScanTimeout        time.Duration
+	ScanWorkers        int
+	ScanWorkerInterval time.Duration
+	ScanBlockDownloads bool
+
+	LifecycleInterval time.Duration
+}
+
+type Encryption struct {
+	// MasterKeys wrap the data keys of the companies, given as id:base64-key
+	// with the current key first. Stored objects are encrypted when set.
+	MasterKeys []string
 }
 
 type Config struct {
 	Minio      Minio
+	Storage    Storage
 	Db         Db
 	App        App
 	FileServer FileServer
+	Encryption Encryption
 }
 
 func NewConfig() *Config {
@@ -70,6 +135,14 @@ func NewConfig() *Config {
 			Host:       GetEnv("MINIO_ROOT_HOST", "localhost"),
 			BucketName: GetEnv("MINIO_BUCKET_NAME", "go-storage"),
 		},
+		Storage: Storage{
+			Backend:   GetEnv("STORAGE_BACKEND", StorageBackendMinio),
+			LocalPath: GetEnv("STORAGE_LOCAL_PATH", "./data/storage"),
+
+			ColdBackend:    GetEnv("STORAGE_COLD_BACKEND", ""),
+			ColdBucketName: GetEnv("STORAGE_COLD_BUCKET", "go-storage-cold"),
+			ColdLocalPath:  GetEnv("STORAGE_COLD_LOCAL_PATH", "./data/cold"),
+		},
 		Db: Db{
 			Host:     GetEnv("POSTGRES_HOST", "localhost"),
 			Port:     GetEnv("POSTGRES_PORT", "5432"),
@@ -83,6 +156,8 @@ func NewConfig() *Config {
 			JwtSecret: GetEnv("APP_JWT_SECRET", "secret"),
 			LogToFile: GetEnv("APP_LOG_FILE", "true"),
 			LogLevel:  GetEnv("APP_LOG_LEVEL", "info"),
+
+			TrustedProxies: GetEnvList("APP_TRUSTED_PROXIES", []string{"127.0.0.1", "::1", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}),
 		},
 		FileServer: FileServer{
 			SmallFileThreshold:  GetEnvInt64("FILE_SMALL_THRESHOLD", 10*1024*1024),
@@ -97,6 +172,7 @@ func NewConfig() *Config {
 			ChunkSize:          GetEnvInt64("FILE_CHUNK_SIZE", 5*1024*1024),
 			ChunkUploadTimeout: GetEnvDuration("FILE_CHUNK_TIMEOUT", 30*time.Second),
 			ChunkedSessionTTL:  GetEnvDuration("FILE_CHUNK_SESSION_TTL", 24*time.Hour),
+			JanitorInterval:    GetEnvDuration("FILE_JANITOR_INTERVAL", 15*time.Minute),
 
 			BufferSize:      GetEnvInt("FILE_BUFFER_SIZE", 64*1024),
 			DownloadTimeout: GetEnvDuration("FILE_DOWNLOAD_TIMEOUT", 10*time.Minute),
@@ -106,6 +182,41 @@ func NewConfig() *Config {
 
 			MaxFailuresBeforeOpen: GetEnvInt("FILE_CIRCUIT_MAX_FAILURES", 5),
 			CircuitBreakerTimeout: GetEnvDuration("FILE_CIRCUIT_TIMEOUT", 1*time.Minute),
+
+			MaxFileVersions: GetEnvInt("FILE_MAX_VERSIONS", 10),
+
+			TrashRetentionDays: GetEnvInt("FILE_TRASH_RETENTION_DAYS", 30),
+			TrashPurgeInterval: GetEnvDuration("FILE_TRASH_PURGE_INTERVAL", 1*time.Hour),
+
+			PresignedURLExpiry: GetEnvDuration("FILE_PRESIGNED_URL_EXPIRY", 15*time.Minute),
+
+			ReconcileGracePeriod: GetEnvDuration("FILE_RECONCILE_GRACE_PERIOD", 1*time.Hour),
+
+			ArchiveMaxSize: GetEnvInt64("FILE_ARCHIVE_MAX_SIZE", 10*1024*1024*1024),
+
+			FolderDeleteAsyncThreshold: GetEnvInt("FILE_FOLDER_DELETE_ASYNC_THRESHOLD", 1000),
+			FolderCopyAsyncThreshold:   GetEnvInt("FILE_FOLDER_COPY_ASYNC_THRESHOLD", 200),
+			FolderJobWorkerInterval:    GetEnvDuration("FILE_FOLDER_JOB_WORKER_INTERVAL", 30*time.Second),
+
+			ContentIndexInterval: GetEnvDuration("FILE_CONTENT_INDEX_INTERVAL", 30*time.Second),
+			ContentIndexMaxSize:  GetEnvInt64("FILE_CONTENT_INDEX_MAX_SIZE", 50*1024*1024),
+			ContentIndexLanguage: GetEnv("FILE_CONTENT_INDEX_LANGUAGE", "simple"),
+
+			ThumbnailMaxSourceSize: GetEnvInt64("FILE_THUMBNAIL_MAX_SOURCE_SIZE", 25*1024*1024),
+			ThumbnailMaxPixels:     GetEnvInt("FILE_THUMBNAIL_MAX_PIXELS", 40_000_000),
+			ThumbnailWorkers:       GetEnvInt("FILE_THUMBNAIL_WORKERS", 2),
+
+			ScanBackend:        GetEnv("FILE_SCAN_BACKEND", "none"),
+			ScanClamdAddress:   GetEnv("FILE_SCAN_CLAMD_ADDRESS", "unix:///var/run/clamav/clamd.ctl"),
+			ScanTimeout:        GetEnvDuration("FILE_SCAN_TIMEOUT", 1*time.Minute),
+			ScanWorkers:        GetEnvInt("FILE_SCAN_WORKERS", 2),
+			ScanWorkerInterval: GetEnvDuration("FILE_SCAN_WORKER_INTERVAL", 30*time.Second),
+			ScanBlockDownloads: GetEnvBool("FILE_SCAN_BLOCK_DOWNLOADS", false),
+
+			LifecycleInterval: GetEnvDuration("FILE_LIFECYCLE_INTERVAL", 1*time.Hour),
+		},
+		Encryption: Encryption{
+			MasterKeys: GetEnvList("STORAGE_ENCRYPTION_MASTER_KEYS", []string{}),
 		},
 	}
 }
@@ -135,6 +246,15 @@ func GetEnvInt(key string, fallback int) int {
 	return fallback
 }
 
+func GetEnvBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
 func GetEnvFloat64(key string, fallback float64) float64 {
 	if value, ok := os.LookupEnv(key); ok {
 		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
@@ -144,6 +264,22 @@ func GetEnvFloat64(key string, fallback float64) float64 {
 	return fallback
 }
 
+// GetEnvList reads a comma separated list, an empty value gives an empty list.
+func GetEnvList(key string, fallback []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 func GetEnvDuration(key string, fallback time.Duration) time.Duration {
 	if value, ok := os.LookupEnv(key); ok {
 		if parsed, err := time.ParseDuration(value); err == nil {
@@ -0,0 +1,63 @@
+package hdFileAccess
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+// AccessEntryDTO has no user_id when the entry applies to the whole company.
+type AccessEntryDTO struct {
+	ID        string            `json:"id"`
+	FileID    string            `json:"file_id"`
+	UserID    *string           `json:"user_id"`
+	Role      domain.AccessRole `json:"role"`
+	GrantedBy string            `json:"granted_by"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type SharingSettingsDTO struct {
+	ShareNewFiles bool `json:"share_new_files"`
+}
+
+type RequestID struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// RequestGrantAccess grants the role to the whole company when UserID is empty.
+type RequestGrantAccess struct {
+	UserID string            `json:"user_id" binding:"omitempty,uuid"`
+	Role   domain.AccessRole `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+// RequestRevokeAccess removes the company-wide entry when UserID is empty.
+type RequestRevokeAccess struct {
+	UserID string `form:"user_id" binding:"omitempty,uuid"`
+}
+
+type RequestUpdateSharingSettings struct {
+	ShareNewFiles *bool `json:"share_new_files" binding:"required"`
+}
+
+type ResponseAccessEntry struct {
+	Status string          `json:"status"`
+	Time   time.Time       `json:"time"`
+	Entry  *AccessEntryDTO `json:"entry"`
+}
+
+type ResponseAccessEntries struct {
+	Status  string            `json:"status"`
+	Time    time.Time         `json:"time"`
+	Entries []*AccessEntryDTO `json:"entries"`
+}
+
+type ResponseSharingSettings struct {
+	Status   string              `json:"status"`
+	Time     time.Time           `json:"time"`
+	Settings *SharingSettingsDTO `json:"settings"`
+}
+
+type ResponseSuccess struct {
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
@@ -0,0 +1,221 @@
+package hdFileAccess
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerFileAccess struct {
+	userCase UseCaseFileAccess
+}
+
+func NewHandlerFileAccess(useCase UseCaseFileAccess) *HandlerFileAccess {
+	return &HandlerFileAccess{
+		userCase: useCase,
+	}
+}
+
+// ListAccess
+// @Summary      List access entries
+// @Description  Returns the roles granted on a file or folder. Roles granted on the folders above it are listed there
+// @Tags         access
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "File or folder ID"
+// @Success      200          {object}  ResponseAccessEntries
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/access [get]
+func (h *HandlerFileAccess) ListAccess(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func ListAccess: Company ID is required", "func", "ListAccess", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var uriData RequestID
+	if err := ctx.ShouldBindUri(&uriData); err != nil {
+		log.Error("func ListAccess: Error in parse URI param", "func", "ListAccess", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	entries, errUc := h.userCase.ListAccess(ctx, companyID, userID, uriData.ID)
+	if errUc != nil {
+		log.Error("func ListAccess: Error work UseCase/Repository", "func", "ListAccess", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseAccessEntries(entries))
+}
+
+// GrantAccess
+// @Summary      Grant access
+// @Description  Gives a user, or the whole company when user_id is omitted, a role on a file or folder and everything below it. Requires the owner role
+// @Tags         access
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string              true  "File or folder ID"
+// @Param        access  body      RequestGrantAccess  true  "Grantee and role"
+// @Success      200          {object}  ResponseAccessEntry
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/access [put]
+func (h *HandlerFileAccess) GrantAccess(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GrantAccess: Company ID is required", "func", "GrantAccess", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var uriData RequestID
+	if err := ctx.ShouldBindUri(&uriData); err != nil {
+		log.Error("func GrantAccess: Error in parse URI param", "func", "GrantAccess", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	var inputData RequestGrantAccess
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func GrantAccess: Error in parse input param", "func", "GrantAccess", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	entry, errUc := h.userCase.GrantAccess(ctx, companyID, userID, uriData.ID, granteeID(inputData.UserID), inputData.Role)
+	if errUc != nil {
+		log.Error("func GrantAccess: Error work UseCase/Repository", "func", "GrantAccess", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseAccessEntry(entry))
+}
+
+// RevokeAccess
+// @Summary      Revoke access
+// @Description  Removes the role of a user, or the company-wide role when user_id is omitted. Requires the owner role
+// @Tags         access
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id       path      string  true   "File or folder ID"
+// @Param        user_id  query     string  false  "User whose role is removed"
+// @Success      200          {object}  ResponseSuccess
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/access [delete]
+func (h *HandlerFileAccess) RevokeAccess(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func RevokeAccess: Company ID is required", "func", "RevokeAccess", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var uriData RequestID
+	if err := ctx.ShouldBindUri(&uriData); err != nil {
+		log.Error("func RevokeAccess: Error in parse URI param", "func", "RevokeAccess", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	var inputData RequestRevokeAccess
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func RevokeAccess: Error in parse query param", "func", "RevokeAccess", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	if errUc := h.userCase.RevokeAccess(ctx, companyID, userID, uriData.ID, granteeID(inputData.UserID)); errUc != nil {
+		log.Error("func RevokeAccess: Error work UseCase/Repository", "func", "RevokeAccess", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSuccess("Access revoked successfully"))
+}
+
+// GetSettings
+// @Summary      Get sharing settings
+// @Description  Returns whether new top-level files and folders are shared with the whole company
+// @Tags         access
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200          {object}  ResponseSharingSettings
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /access/settings [get]
+func (h *HandlerFileAccess) GetSettings(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func GetSettings: Company ID is required", "func", "GetSettings", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	settings, errUc := h.userCase.GetSettings(ctx, companyID)
+	if errUc != nil {
+		log.Error("func GetSettings: Error work UseCase/Repository", "func", "GetSettings", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSharingSettings(settings))
+}
+
+// UpdateSettings
+// @Summary      Update sharing settings
+// @Description  Chooses whether new top-level files and folders are shared with the whole company as editors or stay private to their creator
+// @Tags         access
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        settings  body      RequestUpdateSharingSettings  true  "Sharing settings"
+// @Success      200       {object}  ResponseSharingSettings
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /access/settings [put]
+func (h *HandlerFileAccess) UpdateSettings(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func UpdateSettings: Company ID is required", "func", "UpdateSettings", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestUpdateSharingSettings
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func UpdateSettings: Error in parse input param", "func", "UpdateSettings", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	settings, errUc := h.userCase.UpdateSettings(ctx, companyID, *inputData.ShareNewFiles)
+	if errUc != nil {
+		log.Error("func UpdateSettings: Error work UseCase/Repository", "func", "UpdateSettings", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSharingSettings(settings))
+}
@@ -0,0 +1,203 @@
+package hdFileAccess
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseFileAccess struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseFileAccess) ListAccess(ctx context.Context, companyID, userID, fileID string) ([]*domain.AccessEntry, error) {
+	args := m.Called(ctx, companyID, userID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessEntry), args.Error(1)
+}
+
+func (m *mockUseCaseFileAccess) GrantAccess(ctx context.Context, companyID, userID, fileID string, granteeID *string, role domain.AccessRole) (*domain.AccessEntry, error) {
+	args := m.Called(ctx, companyID, userID, fileID, granteeID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessEntry), args.Error(1)
+}
+
+func (m *mockUseCaseFileAccess) RevokeAccess(ctx context.Context, companyID, userID, fileID string, granteeID *string) error {
+	args := m.Called(ctx, companyID, userID, fileID, granteeID)
+	return args.Error(0)
+}
+
+func (m *mockUseCaseFileAccess) GetSettings(ctx context.Context, companyID string) (*domain.SharingSettings, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SharingSettings), args.Error(1)
+}
+
+func (m *mockUseCaseFileAccess) UpdateSettings(ctx context.Context, companyID string, shareNewFiles bool) (*domain.SharingSettings, error) {
+	args := m.Called(ctx, companyID, shareNewFiles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SharingSettings), args.Error(1)
+}
+
+const (
+	testFileID    = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	testGranteeID = "9b2f3c1e-8d4a-4b6f-9a1e-2c3d4e5f6a7b"
+)
+
+func createTestContext(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: testFileID}}
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func TestGrantAccess_User(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	granteeID := testGranteeID
+	entry := &domain.AccessEntry{ID: "entry-id", FileID: testFileID, UserID: &granteeID, Role: domain.AccessEditor, GrantedBy: "user-123"}
+	mockUC.On("GrantAccess", mock.Anything, "company-123", "user-123", testFileID, &granteeID, domain.AccessEditor).Return(entry, nil)
+
+	c, w := createTestContext("PUT", "/files/"+testFileID+"/access", []byte(`{"user_id":"`+testGranteeID+`","role":"editor"}`))
+	handler.GrantAccess(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseAccessEntry
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, testGranteeID, *response.Entry.UserID)
+	assert.Equal(t, domain.AccessEditor, response.Entry.Role)
+}
+
+func TestGrantAccess_Company(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	entry := &domain.AccessEntry{ID: "entry-id", FileID: testFileID, Role: domain.AccessViewer}
+	mockUC.On("GrantAccess", mock.Anything, "company-123", "user-123", testFileID, (*string)(nil), domain.AccessViewer).Return(entry, nil)
+
+	c, w := createTestContext("PUT", "/files/"+testFileID+"/access", []byte(`{"role":"viewer"}`))
+	handler.GrantAccess(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseAccessEntry
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Entry.UserID)
+}
+
+func TestGrantAccess_InvalidRole(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	c, w := createTestContext("PUT", "/files/"+testFileID+"/access", []byte(`{"role":"admin"}`))
+	handler.GrantAccess(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "GrantAccess", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGrantAccess_NotOwner(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	mockUC.On("GrantAccess", mock.Anything, "company-123", "user-123", testFileID, (*string)(nil), domain.AccessEditor).
+		Return(nil, errors.Forbidden("owner access is required"))
+
+	c, w := createTestContext("PUT", "/files/"+testFileID+"/access", []byte(`{"role":"editor"}`))
+	handler.GrantAccess(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestListAccess_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	mockUC.On("ListAccess", mock.Anything, "company-123", "user-123", testFileID).
+		Return([]*domain.AccessEntry{{ID: "entry-id", FileID: testFileID, Role: domain.AccessEditor}}, nil)
+
+	c, w := createTestContext("GET", "/files/"+testFileID+"/access", nil)
+	handler.ListAccess(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponseAccessEntries
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Entries, 1)
+}
+
+func TestRevokeAccess_User(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	granteeID := testGranteeID
+	mockUC.On("RevokeAccess", mock.Anything, "company-123", "user-123", testFileID, &granteeID).Return(nil)
+
+	c, w := createTestContext("DELETE", "/files/"+testFileID+"/access?user_id="+testGranteeID, nil)
+	handler.RevokeAccess(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestRevokeAccess_InvalidUserID(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	c, w := createTestContext("DELETE", "/files/"+testFileID+"/access?user_id=someone", nil)
+	handler.RevokeAccess(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "RevokeAccess", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateSettings_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	mockUC.On("UpdateSettings", mock.Anything, "company-123", false).
+		Return(&domain.SharingSettings{CompanyID: "company-123", ShareNewFiles: false}, nil)
+
+	c, w := createTestContext("PUT", "/access/settings", []byte(`{"share_new_files":false}`))
+	handler.UpdateSettings(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUpdateSettings_MissingValue(t *testing.T) {
+	mockUC := new(mockUseCaseFileAccess)
+	handler := NewHandlerFileAccess(mockUC)
+
+	c, w := createTestContext("PUT", "/access/settings", []byte(`{}`))
+	handler.UpdateSettings(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything, mock.Anything)
+}
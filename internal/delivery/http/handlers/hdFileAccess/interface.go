@@ -0,0 +1,16 @@
+package hdFileAccess
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+)
+
+type UseCaseFileAccess interface {
+	ListAccess(ctx context.Context, companyID, userID, fileID string) ([]*domain.AccessEntry, error)
+	GrantAccess(ctx context.Context, companyID, userID, fileID string, granteeID *string, role domain.AccessRole) (*domain.AccessEntry, error)
+	RevokeAccess(ctx context.Context, companyID, userID, fileID string, granteeID *string) error
+
+	GetSettings(ctx context.Context, companyID string) (*domain.SharingSettings, error)
+	UpdateSettings(ctx context.Context, companyID string, shareNewFiles bool) (*domain.SharingSettings, error)
+}
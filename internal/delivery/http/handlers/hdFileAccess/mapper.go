@@ -0,0 +1,62 @@
+package hdFileAccess
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+// granteeID returns nil for an empty user ID, which stands for the whole company.
+func granteeID(userID string) *string {
+	if userID == "" {
+		return nil
+	}
+	return &userID
+}
+
+func DtoAccessEntry(entry *domain.AccessEntry) *AccessEntryDTO {
+	return &AccessEntryDTO{
+		ID:        entry.ID,
+		FileID:    entry.FileID,
+		UserID:    entry.UserID,
+		Role:      entry.Role,
+		GrantedBy: entry.GrantedBy,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func ToResponseAccessEntry(entry *domain.AccessEntry) *ResponseAccessEntry {
+	return &ResponseAccessEntry{
+		Status: "success",
+		Time:   time.Now(),
+		Entry:  DtoAccessEntry(entry),
+	}
+}
+
+func ToResponseAccessEntries(entries []*domain.AccessEntry) *ResponseAccessEntries {
+	var answer = make([]*AccessEntryDTO, len(entries))
+	for index, value := range entries {
+		answer[index] = DtoAccessEntry(value)
+	}
+
+	return &ResponseAccessEntries{
+		Status:  "success",
+		Time:    time.Now(),
+		Entries: answer,
+	}
+}
+
+func ToResponseSharingSettings(settings *domain.SharingSettings) *ResponseSharingSettings {
+	return &ResponseSharingSettings{
+		Status:   "success",
+		Time:     time.Now(),
+		Settings: &SharingSettingsDTO{ShareNewFiles: settings.ShareNewFiles},
+	}
+}
+
+func ToResponseSuccess(message string) *ResponseSuccess {
+	return &ResponseSuccess{
+		Status:  "success",
+		Time:    time.Now(),
+		Message: message,
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 )
 
+// HeaderChecksumSHA256 carries the hex encoded SHA-256 of an upload.
+const HeaderChecksumSHA256 = "X-Checksum-SHA256"
+
 type FolderFileDTO struct {
 	ID           string          `json:"id"`
 	Name         string          `json:"name"`
@@ -16,8 +19,12 @@ type FolderFileDTO struct {
 
 	MimeType    *string `json:"mime_type,omitempty"`
 	Size        *int64  `json:"size,omitempty"`
+	Hash        *string `json:"hash,omitempty"`
 	StoragePath *string `json:"storage_path,omitempty"`
 
+	ScanStatus   domain.ScanStatus   `json:"scan_status,omitempty"`
+	StorageClass domain.StorageClass `json:"storage_class,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -27,15 +34,54 @@ type RequestCreateFolder struct {
 	ParentPath string `json:"parentPath" binding:"required"`
 }
 
+// RequestGetFolder selects a page of a folder listing. Direct children are
+// returned unless Recursive is set; Cursor comes from the previous page.
 type RequestGetFolder struct {
-	Path string          `json:"path" binding:"required"`
-	Type domain.FileType `json:"type,omitempty"`
+	Path      string          `json:"path" binding:"required"`
+	Type      domain.FileType `json:"type,omitempty"`
+	Recursive bool            `json:"recursive,omitempty"`
+
+	MimeType       string     `json:"mimeType,omitempty"`
+	MinSize        *int64     `json:"minSize,omitempty" binding:"omitempty,min=0"`
+	MaxSize        *int64     `json:"maxSize,omitempty" binding:"omitempty,min=0"`
+	ModifiedAfter  *time.Time `json:"modifiedAfter,omitempty"`
+	ModifiedBefore *time.Time `json:"modifiedBefore,omitempty"`
+
+	// Tags and Metadata keep items carrying all the given tags and key/value pairs.
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	Sort   string `json:"sort,omitempty" binding:"omitempty,oneof=name size updated_at type"`
+	Order  string `json:"order,omitempty" binding:"omitempty,oneof=asc desc"`
+	Limit  int    `json:"limit,omitempty" binding:"omitempty,min=1,max=1000"`
+	Cursor string `json:"cursor,omitempty"`
 }
 
 type RequestRenameFolder struct {
 	Name string `json:"name" binding:"required"`
 }
 
+type RequestDeleteFolder struct {
+	Recursive bool `form:"recursive"`
+	Permanent bool `form:"permanent"`
+}
+
+type RequestFolderDeleteJob struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type RequestFolderCopyJob struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// RequestCopy describes the destination of a copied file or folder. Name
+// defaults to the source name, OnConflict to fail.
+type RequestCopy struct {
+	ParentPath string `json:"parentPath" binding:"required"`
+	Name       string `json:"name,omitempty"`
+	OnConflict string `json:"onConflict,omitempty" binding:"omitempty,oneof=fail rename"`
+}
+
 type RequestMoveFolder struct {
 	ParentPath string `json:"parentPath" binding:"required"`
 }
@@ -47,9 +93,10 @@ type ResponseFolder struct {
 }
 
 type ResponseGetFolder struct {
-	Status string           `json:"status"`
-	Time   time.Time        `json:"time"`
-	Files  []*FolderFileDTO `json:"files"`
+	Status     string           `json:"status"`
+	Time       time.Time        `json:"time"`
+	Files      []*FolderFileDTO `json:"files"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 type ResponsePath struct {
@@ -60,6 +107,34 @@ type ResponsePath struct {
 
 type RequestUploadFile struct {
 	ParentPath string `form:"parentPath" binding:"required"`
+	Mode       string `form:"mode" binding:"omitempty,oneof=create version"`
+}
+
+type RequestFileVersion struct {
+	ID      string `uri:"id" binding:"required,uuid"`
+	Version int    `uri:"version" binding:"required,min=1"`
+	Inline  bool   `form:"inline"`
+}
+
+type FileVersionDTO struct {
+	ID           string    `json:"id"`
+	FileID       string    `json:"file_id"`
+	Version      int       `json:"version"`
+	UserCreateID string    `json:"user_created_id"`
+	MimeType     string    `json:"mime_type"`
+	Size         int64     `json:"size"`
+	Hash         *string   `json:"hash,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ResponseFileVersions struct {
+	Status   string            `json:"status"`
+	Time     time.Time         `json:"time"`
+	Versions []*FileVersionDTO `json:"versions"`
+}
+
+type RequestFolderArchive struct {
+	Format string `form:"format" binding:"omitempty,oneof=zip tar.gz"`
 }
 
 type RequestDownloadFile struct {
@@ -67,6 +142,19 @@ type RequestDownloadFile struct {
 	Inline bool   `form:"inline"`
 }
 
+type RequestGetThumbnail struct {
+	ID   string `uri:"id" binding:"required,uuid"`
+	Size string `form:"size" binding:"omitempty,oneof=small medium large"`
+}
+
+type RequestScanFindings struct {
+	Status string `form:"status" binding:"omitempty,oneof=infected error pending"`
+}
+
+type RequestScanFile struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
 type RequestGetFileInfo struct {
 	ID string `uri:"id" binding:"required,uuid"`
 }
@@ -99,6 +187,17 @@ type RequestGetChunkedUploadStatus struct {
 	UploadID string `uri:"uploadId" binding:"required,uuid"`
 }
 
+type RequestInitPresignedUpload struct {
+	FileName   string `json:"fileName" binding:"required"`
+	FileSize   int64  `json:"fileSize" binding:"min=0"`
+	ParentPath string `json:"parentPath" binding:"required"`
+	Checksum   string `json:"checksum,omitempty"`
+}
+
+type RequestFinalizePresignedUpload struct {
+	UploadID string `uri:"uploadId" binding:"required,uuid"`
+}
+
 type ResponseFile struct {
 	Status string         `json:"status"`
 	Time   time.Time      `json:"time"`
@@ -114,6 +213,23 @@ type ResponseInitChunkedUpload struct {
 	Strategy    *domain.StrategyInfo `json:"strategy"`
 }
 
+type ResponseDownloadURL struct {
+	Status    string    `json:"status"`
+	Time      time.Time `json:"time"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ResponseInitPresignedUpload struct {
+	Status    string            `json:"status"`
+	Time      time.Time         `json:"time"`
+	UploadID  string            `json:"upload_id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
 type ResponseUploadChunk struct {
 	Status         string    `json:"status"`
 	Time           time.Time `json:"time"`
@@ -161,3 +277,55 @@ type ResponseResourceStats struct {
 	Time   time.Time             `json:"time"`
 	Stats  *domain.ResourceStats `json:"stats"`
 }
+
+type ResponseJanitorStats struct {
+	Status string               `json:"status"`
+	Time   time.Time            `json:"time"`
+	Stats  *domain.JanitorStats `json:"stats"`
+}
+
+type ResponseFolderDeleteJob struct {
+	Status       string           `json:"status"`
+	Time         time.Time        `json:"time"`
+	JobID        string           `json:"job_id"`
+	FolderPath   string           `json:"folder_path"`
+	Permanent    bool             `json:"permanent"`
+	JobStatus    domain.JobStatus `json:"job_status"`
+	Progress     float64          `json:"progress"`
+	TotalItems   int              `json:"total_items"`
+	DeletedItems int              `json:"deleted_items"`
+	PurgedItems  int              `json:"purged_items"`
+	Error        *string          `json:"error,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	FinishedAt   *time.Time       `json:"finished_at,omitempty"`
+}
+
+type ResponseFolderCopyJob struct {
+	Status      string           `json:"status"`
+	Time        time.Time        `json:"time"`
+	JobID       string           `json:"job_id"`
+	SourcePath  string           `json:"source_path"`
+	TargetPath  string           `json:"target_path"`
+	TargetID    *string          `json:"target_id,omitempty"`
+	JobStatus   domain.JobStatus `json:"job_status"`
+	Progress    float64          `json:"progress"`
+	TotalItems  int              `json:"total_items"`
+	CopiedItems int              `json:"copied_items"`
+	Error       *string          `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+}
+
+type ScanFindingDTO struct {
+	File      *FolderFileDTO `json:"file"`
+	InTrash   bool           `json:"in_trash"`
+	Detail    *string        `json:"detail,omitempty"`
+	ScannedAt *time.Time     `json:"scanned_at,omitempty"`
+}
+
+type ResponseScanFindings struct {
+	Status   string            `json:"status"`
+	Time     time.Time         `json:"time"`
+	Findings []*ScanFindingDTO `json:"findings"`
+	Total    int               `json:"total"`
+}
@@ -1,10 +1,14 @@
 package hdFileFolder
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go-storage/internal/domain"
@@ -12,6 +16,10 @@ import (
 	"go-storage/pkg/logger"
 )
 
+// thumbnailCacheControl lets browsers reuse a thumbnail for a day. The ETag
+// changes whenever a new version of the image is uploaded.
+const thumbnailCacheControl = "private, max-age=86400"
+
 type HandlerFileFolder struct {
 	userCase UseCaseFileFolder
 }
@@ -59,6 +67,7 @@ func (h *HandlerFileFolder) CreateFolder(ctx *gin.Context) {
 	}
 
 	domainObj.CompanyId = companyID
+	domainObj.UserCreateID = ctx.GetString("user_id")
 
 	folder, errUc := h.userCase.CreateFolder(ctx, domainObj)
 	if errUc != nil {
@@ -72,7 +81,7 @@ func (h *HandlerFileFolder) CreateFolder(ctx *gin.Context) {
 
 // GetFolderContents
 // @Summary      Get folder contents
-// @Description  Returns list of files and folders in specified path
+// @Description  Returns a page of the direct children of a folder, or of its whole subtree with recursive=true. Sorts by name, size, updated_at or type (folders first) and filters by type, mime type (image/* style prefixes allowed), size range and modification date. Pass next_cursor as cursor to get the following page
 // @Tags         folders
 // @Security     BearerAuth
 // @Accept       json
@@ -85,6 +94,7 @@ func (h *HandlerFileFolder) CreateFolder(ctx *gin.Context) {
 func (h *HandlerFileFolder) GetFolderContents(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func getFolderContents: Company ID is required", "func", "getFolderContents", "err", "empty companyId from JWT")
@@ -99,21 +109,21 @@ func (h *HandlerFileFolder) GetFolderContents(ctx *gin.Context) {
 		return
 	}
 
-	path, fileType, err := ToDomainGetFolder(&inputData)
+	query, err := ToDomainGetFolder(&inputData)
 	if err != nil {
 		log.Error("func getFolderContents: Error in valid param", "func", "getFolderContents", "err", err.Error())
 		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
 		return
 	}
 
-	files, errUc := h.userCase.GetFolderContents(ctx, companyID, path, fileType)
+	page, errUc := h.userCase.ListFolder(ctx, companyID, userID, query)
 	if errUc != nil {
 		log.Error("func getFolderContents: Error work UseCase/Repository", "func", "getFolderContents", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, ToResponseFolders(files))
+	ctx.JSON(http.StatusOK, ToResponseFolders(page))
 }
 
 // FolderRename
@@ -132,6 +142,7 @@ func (h *HandlerFileFolder) GetFolderContents(ctx *gin.Context) {
 func (h *HandlerFileFolder) FolderRename(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func FolderRename: Company ID is required", "func", "FolderRename", "err", "empty companyId from JWT")
@@ -157,7 +168,7 @@ func (h *HandlerFileFolder) FolderRename(ctx *gin.Context) {
 
 	newName := path.GetParent().Join(inputData.Name)
 
-	newPath, errUc := h.userCase.MoveFolder(ctx, companyID, &path, &newName)
+	newPath, errUc := h.userCase.MoveFolder(ctx, companyID, userID, &path, &newName)
 	if errUc != nil {
 		log.Error("func FolderRename: Error work UseCase/Repository", "func", "FolderRename", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -183,6 +194,7 @@ func (h *HandlerFileFolder) FolderRename(ctx *gin.Context) {
 func (h *HandlerFileFolder) MoveFolder(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func MoveFolder: Company ID is required", "func", "MoveFolder", "err", "empty companyId from JWT")
@@ -214,7 +226,7 @@ func (h *HandlerFileFolder) MoveFolder(ctx *gin.Context) {
 	}
 
 	newPath := newParentPath.Join(path.GetName())
-	_, errUc := h.userCase.MoveFolder(ctx, companyID, &path, &newPath)
+	_, errUc := h.userCase.MoveFolder(ctx, companyID, userID, &path, &newPath)
 	if errUc != nil {
 		log.Error("func MoveFolder: Error work UseCase/Repository", "func", "MoveFolder", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -226,18 +238,22 @@ func (h *HandlerFileFolder) MoveFolder(ctx *gin.Context) {
 
 // DeleteFolder
 // @Summary      Delete folder
-// @Description  Deletes a folder and all its contents
+// @Description  Deletes an empty folder. With recursive=true the folder goes to the trash with all its contents; large trees are handled by a background job and answered with 202. permanent=true purges the tree and its stored files instead of keeping them in the trash
 // @Tags         folders
 // @Security     BearerAuth
 // @Produce      json
-// @Param        path  path      string  true  "Folder path"
+// @Param        path       path      string  true   "Folder path"
+// @Param        recursive  query     bool    false  "Delete the folder with its contents"
+// @Param        permanent  query     bool    false  "Skip the trash, only with recursive"
 // @Success      200   {object}  ResponsePath
-// @Failure      400,500  {object}  errors.ErrorResponse
-// @Failure      401,403  {object}  errors.ErrorResponse
+// @Success      202   {object}  ResponseFolderDeleteJob
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
 // @Router       /folders/{path} [delete]
 func (h *HandlerFileFolder) DeleteFolder(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func DeleteFolder: Company ID is required", "func", "DeleteFolder", "err", "empty companyId from JWT")
@@ -253,7 +269,30 @@ func (h *HandlerFileFolder) DeleteFolder(ctx *gin.Context) {
 		return
 	}
 
-	errUc := h.userCase.DeleteFolder(ctx, companyID, &path)
+	var inputData RequestDeleteFolder
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func DeleteFolder: Error in parse query param", "func", "DeleteFolder", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	if inputData.Recursive {
+		job, errUc := h.userCase.DeleteFolderRecursive(ctx, companyID, userID, &path, inputData.Permanent)
+		if errUc != nil {
+			log.Error("func DeleteFolder: Error work UseCase/Repository", "func", "DeleteFolder", "err", errUc.Error())
+			errors.HandleError(ctx, errUc)
+			return
+		}
+
+		status := http.StatusOK
+		if !job.IsFinished() {
+			status = http.StatusAccepted
+		}
+		ctx.JSON(status, ToResponseFolderDeleteJob(job))
+		return
+	}
+
+	errUc := h.userCase.DeleteFolder(ctx, companyID, userID, &path)
 	if errUc != nil {
 		log.Error("func DeleteFolder: Error work UseCase/Repository", "func", "DeleteFolder", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -263,6 +302,283 @@ func (h *HandlerFileFolder) DeleteFolder(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, ToResponsePath(&path))
 }
 
+// GetFolderDeleteJob
+// @Summary      Get folder delete job
+// @Description  Returns the status and progress of a recursive folder deletion
+// @Tags         folders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "Job ID"
+// @Success      200 {object}  ResponseFolderDeleteJob
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /jobs/folder-delete/{id} [get]
+func (h *HandlerFileFolder) GetFolderDeleteJob(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetFolderDeleteJob: Company ID is required", "func", "GetFolderDeleteJob", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestFolderDeleteJob
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func GetFolderDeleteJob: Error in parse URI param", "func", "GetFolderDeleteJob", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid job ID"))
+		return
+	}
+
+	job, errUc := h.userCase.GetFolderDeleteJob(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func GetFolderDeleteJob: Error work UseCase/Repository", "func", "GetFolderDeleteJob", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFolderDeleteJob(job))
+}
+
+// CancelFolderDeleteJob
+// @Summary      Cancel folder delete job
+// @Description  Stops a recursive folder deletion. Items already purged stay purged, the rest remains in the trash
+// @Tags         folders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "Job ID"
+// @Success      200 {object}  ResponseFolderDeleteJob
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /jobs/folder-delete/{id}/cancel [post]
+func (h *HandlerFileFolder) CancelFolderDeleteJob(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func CancelFolderDeleteJob: Company ID is required", "func", "CancelFolderDeleteJob", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestFolderDeleteJob
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func CancelFolderDeleteJob: Error in parse URI param", "func", "CancelFolderDeleteJob", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid job ID"))
+		return
+	}
+
+	job, errUc := h.userCase.CancelFolderDeleteJob(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func CancelFolderDeleteJob: Error work UseCase/Repository", "func", "CancelFolderDeleteJob", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFolderDeleteJob(job))
+}
+
+// CopyFolder
+// @Summary      Copy folder
+// @Description  Copies a folder with its contents into parentPath. Stored files are duplicated inside storage. Large trees are copied by a background job and answered with 202. onConflict=rename picks a free name instead of failing when the target exists
+// @Tags         folders
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        path     path      string       true  "Folder path"
+// @Param        request  body      RequestCopy  true  "Destination"
+// @Success      201      {object}  ResponseFolderCopyJob
+// @Success      202      {object}  ResponseFolderCopyJob
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /folders/{path}/copy [post]
+func (h *HandlerFileFolder) CopyFolder(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func CopyFolder: Company ID is required", "func", "CopyFolder", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	path, errPath := domain.NewPath(ctx.Param("path"))
+	if errPath != nil {
+		log.Error("func CopyFolder: Error in parse input param", "func", "CopyFolder", "err", errPath.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid path"))
+		return
+	}
+
+	var inputData RequestCopy
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func CopyFolder: Error in parse input param", "func", "CopyFolder", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	parentPath, err := domain.NewPath(inputData.ParentPath)
+	if err != nil {
+		log.Error("func CopyFolder: Error in parse parent path", "func", "CopyFolder", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid parent path"))
+		return
+	}
+
+	job, errUc := h.userCase.CopyFolder(ctx, companyID, userID, &path, &parentPath, inputData.Name, conflictPolicy(inputData.OnConflict))
+	if errUc != nil {
+		log.Error("func CopyFolder: Error work UseCase/Repository", "func", "CopyFolder", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	status := http.StatusCreated
+	if !job.IsFinished() {
+		status = http.StatusAccepted
+	}
+	ctx.JSON(status, ToResponseFolderCopyJob(job))
+}
+
+// GetFolderCopyJob
+// @Summary      Get folder copy job
+// @Description  Returns the status and progress of a folder copy
+// @Tags         folders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "Job ID"
+// @Success      200 {object}  ResponseFolderCopyJob
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /jobs/folder-copy/{id} [get]
+func (h *HandlerFileFolder) GetFolderCopyJob(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetFolderCopyJob: Company ID is required", "func", "GetFolderCopyJob", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestFolderCopyJob
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func GetFolderCopyJob: Error in parse URI param", "func", "GetFolderCopyJob", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid job ID"))
+		return
+	}
+
+	job, errUc := h.userCase.GetFolderCopyJob(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func GetFolderCopyJob: Error work UseCase/Repository", "func", "GetFolderCopyJob", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFolderCopyJob(job))
+}
+
+// CancelFolderCopyJob
+// @Summary      Cancel folder copy job
+// @Description  Stops a folder copy. The partially copied folder is moved to the trash
+// @Tags         folders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "Job ID"
+// @Success      200 {object}  ResponseFolderCopyJob
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /jobs/folder-copy/{id}/cancel [post]
+func (h *HandlerFileFolder) CancelFolderCopyJob(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func CancelFolderCopyJob: Company ID is required", "func", "CancelFolderCopyJob", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestFolderCopyJob
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func CancelFolderCopyJob: Error in parse URI param", "func", "CancelFolderCopyJob", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid job ID"))
+		return
+	}
+
+	job, errUc := h.userCase.CancelFolderCopyJob(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func CancelFolderCopyJob: Error work UseCase/Repository", "func", "CancelFolderCopyJob", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFolderCopyJob(job))
+}
+
+// DownloadFolderArchive
+// @Summary      Download folder as archive
+// @Description  Streams the folder with all its subfolders and files as a single zip or tar.gz archive
+// @Tags         folders
+// @Security     BearerAuth
+// @Produce      application/zip
+// @Produce      application/gzip
+// @Param        path    path      string  true   "Folder path"
+// @Param        format  query     string  false  "zip (default) or tar.gz"
+// @Success      200     {file}    binary
+// @Failure      400,404,413,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /folders/{path}/archive [get]
+func (h *HandlerFileFolder) DownloadFolderArchive(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func DownloadFolderArchive: Company ID is required", "func", "DownloadFolderArchive", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	path, errPath := domain.NewPath(ctx.Param("path"))
+	if errPath != nil {
+		log.Error("func DownloadFolderArchive: Error in parse input param", "func", "DownloadFolderArchive", "err", errPath.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid path"))
+		return
+	}
+
+	var inputData RequestFolderArchive
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func DownloadFolderArchive: Error in parse query param", "func", "DownloadFolderArchive", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Format must be zip or tar.gz"))
+		return
+	}
+
+	format := domain.ArchiveFormatZip
+	if inputData.Format != "" {
+		format = domain.ArchiveFormat(inputData.Format)
+	}
+
+	archive, errUc := h.userCase.PrepareFolderArchive(ctx, companyID, userID, &path, format)
+	if errUc != nil {
+		log.Error("func DownloadFolderArchive: Error work UseCase/Repository", "func", "DownloadFolderArchive", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, archive.FileName()))
+	ctx.Header("Content-Type", archive.Format.ContentType())
+	ctx.Status(http.StatusOK)
+
+	// The status is already sent, a failure here can only cut the archive short.
+	if err := h.userCase.WriteFolderArchive(ctx, archive, ctx.Writer); err != nil {
+		log.Error("func DownloadFolderArchive: Error streaming archive", "func", "DownloadFolderArchive", "err", err.Error())
+		return
+	}
+}
+
 // UploadFile
 // @Summary      Upload file
 // @Description  Uploads a file to the specified folder
@@ -270,8 +586,10 @@ func (h *HandlerFileFolder) DeleteFolder(ctx *gin.Context) {
 // @Security     BearerAuth
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        parentPath  formData  string  true  "Parent folder path"
-// @Param        file        formData  file    true  "File to upload"
+// @Param        parentPath  formData  string  true   "Parent folder path"
+// @Param        mode        formData  string  false  "create (default) or version to append a new version to an existing file"
+// @Param        file        formData  file    true   "File to upload"
+// @Param        X-Checksum-SHA256  header  string  false  "Hex encoded SHA-256 of the file, the upload is rejected on mismatch"
 // @Success      201         {object}  ResponseFile
 // @Failure      400,500     {object}  errors.ErrorResponse
 // @Failure      401,403     {object}  errors.ErrorResponse
@@ -307,6 +625,13 @@ func (h *HandlerFileFolder) UploadFile(ctx *gin.Context) {
 		return
 	}
 
+	checksum, err := checksumFromRequest(ctx)
+	if err != nil {
+		log.Error("func UploadFile: Error in parse checksum header", "func", "UploadFile", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid checksum header"))
+		return
+	}
+
 	fileHeader, err := ctx.FormFile("file")
 	if err != nil {
 		log.Error("func UploadFile: Error getting file from form", "func", "UploadFile", "err", err.Error())
@@ -322,7 +647,12 @@ func (h *HandlerFileFolder) UploadFile(ctx *gin.Context) {
 	}
 	defer file.Close()
 
-	uploadedFile, errUc := h.userCase.UploadFile(ctx, companyID, userID, &parentPath, fileHeader.Filename, fileHeader.Size, file)
+	upload := h.userCase.UploadFile
+	if inputData.Mode == "version" {
+		upload = h.userCase.UploadFileVersion
+	}
+
+	uploadedFile, errUc := upload(ctx, companyID, userID, &parentPath, fileHeader.Filename, fileHeader.Size, file, checksum)
 	if errUc != nil {
 		log.Error("func UploadFile: Error work UseCase/Repository", "func", "UploadFile", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -334,19 +664,25 @@ func (h *HandlerFileFolder) UploadFile(ctx *gin.Context) {
 
 // DownloadFile
 // @Summary      Download file
-// @Description  Downloads a file by ID
+// @Description  Downloads a file by ID. Supports Range (single and multiple ranges), If-Range, If-None-Match and If-Modified-Since
 // @Tags         files
 // @Security     BearerAuth
 // @Produce      application/octet-stream
 // @Param        id      path   string  true   "File ID"
 // @Param        inline  query  bool    false  "Display inline instead of attachment"
+// @Param        Range   header string  false  "Byte ranges, e.g. bytes=0-1023"
 // @Success      200     {file}  binary
-// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Success      206     {file}  binary
+// @Success      304     "Not Modified"
+// @Header       200     {string}  ETag    "Quoted hex SHA-256 of the content"
+// @Header       200     {string}  Digest  "sha-256 digest of the content, base64 encoded"
+// @Failure      400,404,416,500  {object}  errors.ErrorResponse
 // @Failure      401,403      {object}  errors.ErrorResponse
 // @Router       /files/{id}/download [get]
 func (h *HandlerFileFolder) DownloadFile(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func DownloadFile: Company ID is required", "func", "DownloadFile", "err", "empty companyId from JWT")
@@ -367,7 +703,47 @@ func (h *HandlerFileFolder) DownloadFile(ctx *gin.Context) {
 		return
 	}
 
-	reader, fileInfo, errUc := h.userCase.DownloadFile(ctx, companyID, inputData.ID)
+	fileInfo, errUc := h.userCase.StatFile(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func DownloadFile: Error work UseCase/Repository", "func", "DownloadFile", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	etag := fileETag(fileInfo)
+	setValidators(ctx, etag, fileInfo.UpdatedAt)
+
+	if checkNotModified(ctx.Request, etag, fileInfo.UpdatedAt) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	var ranges []domain.ByteRange
+	if rangeAllowed(ctx.Request, etag, fileInfo.UpdatedAt) {
+		var err error
+		ranges, err = parseRange(ctx.GetHeader("Range"), *fileInfo.Size)
+		if err != nil {
+			log.Error("func DownloadFile: Error in parse range header", "func", "DownloadFile", "err", err.Error())
+			ctx.Header("Content-Range", fmt.Sprintf("bytes */%d", *fileInfo.Size))
+			errors.HandleError(ctx, errors.RangeNotSatisfiable("Requested range not satisfiable"))
+			return
+		}
+	}
+
+	if len(ranges) > 0 {
+		open := func(r domain.ByteRange) (io.ReadCloser, error) {
+			return h.userCase.OpenFile(ctx, fileInfo, &r)
+		}
+		if err := streamRanges(ctx, open, fileInfo, ranges, inputData.Inline); err != nil {
+			log.Error("func DownloadFile: Error streaming file ranges", "func", "DownloadFile", "err", err.Error())
+			if !ctx.Writer.Written() {
+				errors.HandleError(ctx, err)
+			}
+		}
+		return
+	}
+
+	reader, errUc := h.userCase.OpenFile(ctx, fileInfo, nil)
 	if errUc != nil {
 		log.Error("func DownloadFile: Error work UseCase/Repository", "func", "DownloadFile", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -375,19 +751,224 @@ func (h *HandlerFileFolder) DownloadFile(ctx *gin.Context) {
 	}
 	defer reader.Close()
 
-	disposition := "attachment"
-	if inputData.Inline {
-		disposition = "inline"
+	if err := streamFile(ctx, reader, fileInfo, inputData.Inline); err != nil {
+		log.Error("func DownloadFile: Error streaming file", "func", "DownloadFile", "err", err.Error())
+		return
 	}
+}
 
-	ctx.Header("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, fileInfo.Name))
-	ctx.Header("Content-Type", *fileInfo.MimeType)
-	ctx.Header("Content-Length", strconv.FormatInt(*fileInfo.Size, 10))
+// GetThumbnail
+// @Summary      Get image thumbnail
+// @Description  Returns a scaled down JPEG or PNG preview of a JPEG, PNG or GIF image. Missing thumbnails are generated on request. Supports If-None-Match
+// @Tags         files
+// @Security     BearerAuth
+// @Produce      image/jpeg
+// @Produce      image/png
+// @Param        id    path   string  true   "File ID"
+// @Param        size  query  string  false  "Thumbnail size" Enums(small, medium, large) default(medium)
+// @Success      200   {file}  binary
+// @Success      304   "Not Modified"
+// @Header       200   {string}  ETag           "Quoted hex SHA-256 of the thumbnail"
+// @Header       200   {string}  Cache-Control  "private, max-age=86400"
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/thumbnail [get]
+func (h *HandlerFileFolder) GetThumbnail(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetThumbnail: Company ID is required", "func", "GetThumbnail", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestGetThumbnail
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func GetThumbnail: Error in parse URI param", "func", "GetThumbnail", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func GetThumbnail: Error in parse query param", "func", "GetThumbnail", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Size must be small, medium or large"))
+		return
+	}
+
+	size := domain.RenditionMedium
+	if inputData.Size != "" {
+		size = domain.RenditionSize(inputData.Size)
+	}
+
+	rendition, errUc := h.userCase.GetThumbnail(ctx, companyID, userID, inputData.ID, size)
+	if errUc != nil {
+		log.Error("func GetThumbnail: Error work UseCase/Repository", "func", "GetThumbnail", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	etag := strconv.Quote(rendition.Hash)
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", thumbnailCacheControl)
+
+	if checkNotModified(ctx.Request, etag, time.Time{}) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	reader, errUc := h.userCase.OpenThumbnail(ctx, rendition)
+	if errUc != nil {
+		log.Error("func GetThumbnail: Error work UseCase/Repository", "func", "GetThumbnail", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+	defer reader.Close()
+
+	ctx.Header("Content-Type", rendition.MimeType)
+	ctx.Header("Content-Length", strconv.FormatInt(rendition.ByteSize, 10))
 
 	if _, err := io.Copy(ctx.Writer, reader); err != nil {
-		log.Error("func DownloadFile: Error streaming file", "func", "DownloadFile", "err", err.Error())
+		log.Error("func GetThumbnail: Error streaming thumbnail", "func", "GetThumbnail", "err", err.Error())
+		return
+	}
+}
+
+// GetFileVersions
+// @Summary      List file versions
+// @Description  Returns the version history of a file, newest first
+// @Tags         files
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "File ID"
+// @Success      200 {object}  ResponseFileVersions
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/versions [get]
+func (h *HandlerFileFolder) GetFileVersions(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetFileVersions: Company ID is required", "func", "GetFileVersions", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestGetFileInfo
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func GetFileVersions: Error in parse URI param", "func", "GetFileVersions", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	versions, errUc := h.userCase.GetFileVersions(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func GetFileVersions: Error work UseCase/Repository", "func", "GetFileVersions", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFileVersions(versions))
+}
+
+// DownloadFileVersion
+// @Summary      Download file version
+// @Description  Downloads a specific version of a file
+// @Tags         files
+// @Security     BearerAuth
+// @Produce      application/octet-stream
+// @Param        id       path   string  true   "File ID"
+// @Param        version  path   int     true   "Version number"
+// @Param        inline   query  bool    false  "Display inline instead of attachment"
+// @Success      200      {file}  binary
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/versions/{version}/download [get]
+func (h *HandlerFileFolder) DownloadFileVersion(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func DownloadFileVersion: Company ID is required", "func", "DownloadFileVersion", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestFileVersion
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func DownloadFileVersion: Error in parse URI param", "func", "DownloadFileVersion", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID or version"))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func DownloadFileVersion: Error in parse query param", "func", "DownloadFileVersion", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	reader, fileInfo, errUc := h.userCase.DownloadFileVersion(ctx, companyID, userID, inputData.ID, inputData.Version)
+	if errUc != nil {
+		log.Error("func DownloadFileVersion: Error work UseCase/Repository", "func", "DownloadFileVersion", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+	defer reader.Close()
+
+	if err := streamFile(ctx, reader, fileInfo, inputData.Inline); err != nil {
+		log.Error("func DownloadFileVersion: Error streaming file", "func", "DownloadFileVersion", "err", err.Error())
+		return
+	}
+}
+
+// RestoreFileVersion
+// @Summary      Restore file version
+// @Description  Makes an old version the current content of the file
+// @Tags         files
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id       path      string  true  "File ID"
+// @Param        version  path      int     true  "Version number"
+// @Success      200      {object}  ResponseFile
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/versions/{version}/restore [post]
+func (h *HandlerFileFolder) RestoreFileVersion(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func RestoreFileVersion: Company ID is required", "func", "RestoreFileVersion", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func RestoreFileVersion: User ID is required", "func", "RestoreFileVersion", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var inputData RequestFileVersion
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func RestoreFileVersion: Error in parse URI param", "func", "RestoreFileVersion", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID or version"))
+		return
+	}
+
+	restoredFile, errUc := h.userCase.RestoreFileVersion(ctx, companyID, userID, inputData.ID, inputData.Version)
+	if errUc != nil {
+		log.Error("func RestoreFileVersion: Error work UseCase/Repository", "func", "RestoreFileVersion", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
 		return
 	}
+
+	ctx.JSON(http.StatusOK, ToResponseFile(restoredFile))
 }
 
 // GetFileInfo
@@ -404,6 +985,7 @@ func (h *HandlerFileFolder) DownloadFile(ctx *gin.Context) {
 func (h *HandlerFileFolder) GetFileInfo(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func GetFileInfo: Company ID is required", "func", "GetFileInfo", "err", "empty companyId from JWT")
@@ -418,7 +1000,7 @@ func (h *HandlerFileFolder) GetFileInfo(ctx *gin.Context) {
 		return
 	}
 
-	fileInfo, errUc := h.userCase.GetFileInfo(ctx, companyID, inputData.ID)
+	fileInfo, errUc := h.userCase.GetFileInfo(ctx, companyID, userID, inputData.ID)
 	if errUc != nil {
 		log.Error("func GetFileInfo: Error work UseCase/Repository", "func", "GetFileInfo", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -444,6 +1026,7 @@ func (h *HandlerFileFolder) GetFileInfo(ctx *gin.Context) {
 func (h *HandlerFileFolder) RenameFile(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func RenameFile: Company ID is required", "func", "RenameFile", "err", "empty companyId from JWT")
@@ -465,7 +1048,7 @@ func (h *HandlerFileFolder) RenameFile(ctx *gin.Context) {
 		return
 	}
 
-	renamedFile, errUc := h.userCase.RenameFile(ctx, companyID, fileID, inputData.Name)
+	renamedFile, errUc := h.userCase.RenameFile(ctx, companyID, userID, fileID, inputData.Name)
 	if errUc != nil {
 		log.Error("func RenameFile: Error work UseCase/Repository", "func", "RenameFile", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -491,6 +1074,7 @@ func (h *HandlerFileFolder) RenameFile(ctx *gin.Context) {
 func (h *HandlerFileFolder) MoveFile(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func MoveFile: Company ID is required", "func", "MoveFile", "err", "empty companyId from JWT")
@@ -519,7 +1103,7 @@ func (h *HandlerFileFolder) MoveFile(ctx *gin.Context) {
 		return
 	}
 
-	movedFile, errUc := h.userCase.MoveFile(ctx, companyID, fileID, &newParentPath)
+	movedFile, errUc := h.userCase.MoveFile(ctx, companyID, userID, fileID, &newParentPath)
 	if errUc != nil {
 		log.Error("func MoveFile: Error work UseCase/Repository", "func", "MoveFile", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -529,6 +1113,61 @@ func (h *HandlerFileFolder) MoveFile(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, ToResponseFile(movedFile))
 }
 
+// CopyFile
+// @Summary      Copy file
+// @Description  Copies a file into parentPath, optionally under a new name. The stored content is duplicated inside storage. onConflict=rename picks a free name instead of failing when the target exists
+// @Tags         files
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string       true  "File ID"
+// @Param        request  body      RequestCopy  true  "Destination"
+// @Success      201      {object}  ResponseFile
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /files/{id}/copy [post]
+func (h *HandlerFileFolder) CopyFile(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func CopyFile: Company ID is required", "func", "CopyFile", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	fileID := ctx.Param("id")
+	if fileID == "" {
+		log.Error("func CopyFile: File ID is required", "func", "CopyFile", "err", "empty file ID")
+		errors.HandleError(ctx, errors.BadRequest("File ID is required"))
+		return
+	}
+
+	var inputData RequestCopy
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func CopyFile: Error in parse input param", "func", "CopyFile", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	parentPath, err := domain.NewPath(inputData.ParentPath)
+	if err != nil {
+		log.Error("func CopyFile: Error in parse parent path", "func", "CopyFile", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid parent path"))
+		return
+	}
+
+	copiedFile, errUc := h.userCase.CopyFile(ctx, companyID, userID, fileID, &parentPath, inputData.Name, conflictPolicy(inputData.OnConflict))
+	if errUc != nil {
+		log.Error("func CopyFile: Error work UseCase/Repository", "func", "CopyFile", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ToResponseFile(copiedFile))
+}
+
 // DeleteFile
 // @Summary      Delete file
 // @Description  Deletes a file from the system
@@ -543,6 +1182,7 @@ func (h *HandlerFileFolder) MoveFile(ctx *gin.Context) {
 func (h *HandlerFileFolder) DeleteFile(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func DeleteFile: Company ID is required", "func", "DeleteFile", "err", "empty companyId from JWT")
@@ -557,7 +1197,7 @@ func (h *HandlerFileFolder) DeleteFile(ctx *gin.Context) {
 		return
 	}
 
-	errUc := h.userCase.DeleteFile(ctx, companyID, fileID)
+	errUc := h.userCase.DeleteFile(ctx, companyID, userID, fileID)
 	if errUc != nil {
 		log.Error("func DeleteFile: Error work UseCase/Repository", "func", "DeleteFile", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -567,6 +1207,137 @@ func (h *HandlerFileFolder) DeleteFile(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, ToResponseSuccess("File deleted successfully"))
 }
 
+// GetDownloadURL
+// @Summary      Get presigned download URL
+// @Description  Returns a short-lived URL from which the file can be downloaded directly from storage
+// @Tags         files
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "File ID"
+// @Success      200 {object}  ResponseDownloadURL
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/download-url [get]
+func (h *HandlerFileFolder) GetDownloadURL(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetDownloadURL: Company ID is required", "func", "GetDownloadURL", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestGetFileInfo
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func GetDownloadURL: Error in parse URI param", "func", "GetDownloadURL", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	presigned, errUc := h.userCase.GetDownloadURL(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func GetDownloadURL: Error work UseCase/Repository", "func", "GetDownloadURL", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseDownloadURL(presigned))
+}
+
+// InitPresignedUpload
+// @Summary      Initialize presigned upload
+// @Description  Returns a short-lived URL to which the file is uploaded directly with a PUT request, sending the returned headers along. The upload must then be finalized.
+// @Tags         presigned-upload
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RequestInitPresignedUpload  true  "Upload details, checksum is an optional hex encoded SHA-256"
+// @Success      201      {object}  ResponseInitPresignedUpload
+// @Failure      400,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/presigned/init [post]
+func (h *HandlerFileFolder) InitPresignedUpload(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func InitPresignedUpload: Company ID is required", "func", "InitPresignedUpload", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func InitPresignedUpload: User ID is required", "func", "InitPresignedUpload", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var inputData RequestInitPresignedUpload
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func InitPresignedUpload: Error in parse input param", "func", "InitPresignedUpload", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	parentPath, err := domain.NewPath(inputData.ParentPath)
+	if err != nil {
+		log.Error("func InitPresignedUpload: Error in parse parent path", "func", "InitPresignedUpload", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid parent path"))
+		return
+	}
+
+	upload, errUc := h.userCase.InitPresignedUpload(ctx, companyID, userID, &parentPath, inputData.FileName, inputData.FileSize, inputData.Checksum)
+	if errUc != nil {
+		log.Error("func InitPresignedUpload: Error work UseCase/Repository", "func", "InitPresignedUpload", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ToResponseInitPresignedUpload(upload))
+}
+
+// FinalizePresignedUpload
+// @Summary      Finalize presigned upload
+// @Description  Verifies the object uploaded through a presigned URL and creates the file
+// @Tags         presigned-upload
+// @Security     BearerAuth
+// @Produce      json
+// @Param        uploadId  path      string  true  "Upload session ID"
+// @Success      200       {object}  ResponseFile
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /files/presigned/{uploadId}/finalize [post]
+func (h *HandlerFileFolder) FinalizePresignedUpload(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func FinalizePresignedUpload: Company ID is required", "func", "FinalizePresignedUpload", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestFinalizePresignedUpload
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func FinalizePresignedUpload: Error in parse URI param", "func", "FinalizePresignedUpload", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid upload ID"))
+		return
+	}
+
+	file, errUc := h.userCase.FinalizePresignedUpload(ctx, companyID, userID, inputData.UploadID)
+	if errUc != nil {
+		log.Error("func FinalizePresignedUpload: Error work UseCase/Repository", "func", "FinalizePresignedUpload", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFile(file))
+}
+
 // GetUploadStrategy
 // @Summary      Get upload strategy
 // @Description  Returns recommended upload strategy based on file size
@@ -670,6 +1441,7 @@ func (h *HandlerFileFolder) InitChunkedUpload(ctx *gin.Context) {
 // @Param        uploadId    path      string  true  "Upload session ID"
 // @Param        chunkIndex  path      string  true  "Chunk index (0-based)"
 // @Param        chunk       formData  file    true  "Chunk data"
+// @Param        X-Checksum-SHA256  header  string  false  "Hex encoded SHA-256 of the chunk, the chunk is rejected on mismatch"
 // @Success      200         {object}  ResponseUploadChunk
 // @Failure      400,500     {object}  errors.ErrorResponse
 // @Failure      401,403     {object}  errors.ErrorResponse
@@ -677,6 +1449,7 @@ func (h *HandlerFileFolder) InitChunkedUpload(ctx *gin.Context) {
 func (h *HandlerFileFolder) UploadChunk(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func UploadChunk: Company ID is required", "func", "UploadChunk", "err", "empty companyId from JWT")
@@ -713,7 +1486,14 @@ func (h *HandlerFileFolder) UploadChunk(ctx *gin.Context) {
 		return
 	}
 
-	chunkedUpload, errUc := h.userCase.UploadChunk(ctx, companyID, inputData.UploadID, chunkIndex, file, fileHeader.Size)
+	checksum, err := checksumFromRequest(ctx)
+	if err != nil {
+		log.Error("func UploadChunk: Error in parse checksum header", "func", "UploadChunk", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid checksum header"))
+		return
+	}
+
+	chunkedUpload, errUc := h.userCase.UploadChunk(ctx, companyID, userID, inputData.UploadID, chunkIndex, file, fileHeader.Size, checksum)
 	if errUc != nil {
 		log.Error("func UploadChunk: Error work UseCase/Repository", "func", "UploadChunk", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -737,6 +1517,7 @@ func (h *HandlerFileFolder) UploadChunk(ctx *gin.Context) {
 func (h *HandlerFileFolder) GetChunkedUploadStatus(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func GetChunkedUploadStatus: Company ID is required", "func", "GetChunkedUploadStatus", "err", "empty companyId from JWT")
@@ -751,7 +1532,7 @@ func (h *HandlerFileFolder) GetChunkedUploadStatus(ctx *gin.Context) {
 		return
 	}
 
-	chunkedUpload, errUc := h.userCase.GetChunkedUploadStatus(ctx, companyID, inputData.UploadID)
+	chunkedUpload, errUc := h.userCase.GetChunkedUploadStatus(ctx, companyID, userID, inputData.UploadID)
 	if errUc != nil {
 		log.Error("func GetChunkedUploadStatus: Error work UseCase/Repository", "func", "GetChunkedUploadStatus", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -768,6 +1549,7 @@ func (h *HandlerFileFolder) GetChunkedUploadStatus(ctx *gin.Context) {
 // @Security     BearerAuth
 // @Produce      json
 // @Param        uploadId  path      string  true  "Upload session ID"
+// @Param        X-Checksum-SHA256  header  string  false  "Hex encoded SHA-256 of the whole file, the upload is rejected on mismatch"
 // @Success      200       {object}  ResponseCompleteChunkedUpload
 // @Failure      400,404,500  {object}  errors.ErrorResponse
 // @Failure      401,403      {object}  errors.ErrorResponse
@@ -775,6 +1557,7 @@ func (h *HandlerFileFolder) GetChunkedUploadStatus(ctx *gin.Context) {
 func (h *HandlerFileFolder) CompleteChunkedUpload(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func CompleteChunkedUpload: Company ID is required", "func", "CompleteChunkedUpload", "err", "empty companyId from JWT")
@@ -789,7 +1572,14 @@ func (h *HandlerFileFolder) CompleteChunkedUpload(ctx *gin.Context) {
 		return
 	}
 
-	completedFile, errUc := h.userCase.CompleteChunkedUpload(ctx, companyID, inputData.UploadID)
+	checksum, err := checksumFromRequest(ctx)
+	if err != nil {
+		log.Error("func CompleteChunkedUpload: Error in parse checksum header", "func", "CompleteChunkedUpload", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid checksum header"))
+		return
+	}
+
+	completedFile, errUc := h.userCase.CompleteChunkedUpload(ctx, companyID, userID, inputData.UploadID, checksum)
 	if errUc != nil {
 		log.Error("func CompleteChunkedUpload: Error work UseCase/Repository", "func", "CompleteChunkedUpload", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -813,6 +1603,7 @@ func (h *HandlerFileFolder) CompleteChunkedUpload(ctx *gin.Context) {
 func (h *HandlerFileFolder) AbortChunkedUpload(ctx *gin.Context) {
 	log := logger.FromContext(ctx)
 	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
 
 	if companyID == "" {
 		log.Error("func AbortChunkedUpload: Company ID is required", "func", "AbortChunkedUpload", "err", "empty companyId from JWT")
@@ -827,7 +1618,7 @@ func (h *HandlerFileFolder) AbortChunkedUpload(ctx *gin.Context) {
 		return
 	}
 
-	errUc := h.userCase.AbortChunkedUpload(ctx, companyID, uploadID)
+	errUc := h.userCase.AbortChunkedUpload(ctx, companyID, userID, uploadID)
 	if errUc != nil {
 		log.Error("func AbortChunkedUpload: Error work UseCase/Repository", "func", "AbortChunkedUpload", "err", errUc.Error())
 		errors.HandleError(ctx, errUc)
@@ -859,3 +1650,195 @@ func (h *HandlerFileFolder) GetResourceStats(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, ToResponseResourceStats(stats))
 }
+
+func streamFile(ctx *gin.Context, reader io.Reader, fileInfo *domain.File, inline bool) error {
+	ctx.Header("Content-Disposition", contentDisposition(fileInfo, inline))
+	ctx.Header("Content-Type", *fileInfo.MimeType)
+	ctx.Header("Content-Length", strconv.FormatInt(*fileInfo.Size, 10))
+
+	if fileInfo.Hash != nil {
+		ctx.Header("ETag", strconv.Quote(*fileInfo.Hash))
+		if digest, err := hex.DecodeString(*fileInfo.Hash); err == nil {
+			ctx.Header("Digest", "sha-256="+base64.StdEncoding.EncodeToString(digest))
+		}
+	}
+
+	_, err := io.Copy(ctx.Writer, reader)
+	return err
+}
+
+func contentDisposition(fileInfo *domain.File, inline bool) string {
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+
+	return fmt.Sprintf(`%s; filename="%s"`, disposition, fileInfo.Name)
+}
+
+// checksumFromRequest returns the hex encoded SHA-256 the client expects the
+// uploaded content to have, taken from X-Checksum-SHA256 or an RFC 3230 Digest header.
+func checksumFromRequest(ctx *gin.Context) (string, error) {
+	if checksum := ctx.GetHeader(HeaderChecksumSHA256); checksum != "" {
+		return checksum, nil
+	}
+
+	for _, value := range strings.Split(ctx.GetHeader("Digest"), ",") {
+		algorithm, encoded, found := strings.Cut(strings.TrimSpace(value), "=")
+		if !found || !strings.EqualFold(algorithm, "sha-256") {
+			continue
+		}
+
+		digest, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", fmt.Errorf("invalid sha-256 digest: %w", err)
+		}
+		return hex.EncodeToString(digest), nil
+	}
+
+	return "", nil
+}
+
+// GetJanitorStats
+// @Summary      Get chunked upload janitor statistics
+// @Description  Returns what the background janitor cleaned up in its last run and since startup
+// @Tags         monitoring
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200     {object}  ResponseJanitorStats
+// @Failure      500     {object}  errors.ErrorResponse
+// @Failure      401,403 {object}  errors.ErrorResponse
+// @Router       /files/janitor/stats [get]
+func (h *HandlerFileFolder) GetJanitorStats(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+
+	stats, errUc := h.userCase.GetJanitorStats(ctx)
+	if errUc != nil {
+		log.Error("func GetJanitorStats: Error work UseCase", "func", "GetJanitorStats", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseJanitorStats(stats))
+}
+
+// GetScanFindings
+// @Summary      List malware scan findings
+// @Description  Lists the company files with the given scan status for review, the infected ones by default
+// @Tags         scans
+// @Security     BearerAuth
+// @Produce      json
+// @Param        status  query     string  false  "Scan status"  Enums(infected, error, pending)
+// @Success      200     {object}  ResponseScanFindings
+// @Failure      400,500 {object}  errors.ErrorResponse
+// @Failure      401,403 {object}  errors.ErrorResponse
+// @Router       /scans [get]
+func (h *HandlerFileFolder) GetScanFindings(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func GetScanFindings: Company ID is required", "func", "GetScanFindings", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestScanFindings
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func GetScanFindings: Error in parse query params", "func", "GetScanFindings", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid scan status"))
+		return
+	}
+
+	findings, errUc := h.userCase.GetScanFindings(ctx, companyID, domain.ScanStatus(inputData.Status))
+	if errUc != nil {
+		log.Error("func GetScanFindings: Error work UseCase", "func", "GetScanFindings", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseScanFindings(findings))
+}
+
+// RescanFile
+// @Summary      Rescan file
+// @Description  Queues the file for another malware scan
+// @Tags         scans
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "File ID"
+// @Success      200 {object}  ResponseFile
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /scans/{id}/rescan [post]
+func (h *HandlerFileFolder) RescanFile(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func RescanFile: Company ID is required", "func", "RescanFile", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestScanFile
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func RescanFile: Error in parse URI param", "func", "RescanFile", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	file, errUc := h.userCase.RescanFile(ctx, companyID, inputData.ID)
+	if errUc != nil {
+		log.Error("func RescanFile: Error work UseCase", "func", "RescanFile", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFile(file))
+}
+
+// ReleaseFile
+// @Summary      Release file from quarantine
+// @Description  Clears an infected file or a failed scan after review so the file is served again
+// @Tags         scans
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "File ID"
+// @Success      200 {object}  ResponseFile
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /scans/{id}/release [post]
+func (h *HandlerFileFolder) ReleaseFile(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func ReleaseFile: Company ID is required", "func", "ReleaseFile", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func ReleaseFile: User ID is required", "func", "ReleaseFile", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var inputData RequestScanFile
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func ReleaseFile: Error in parse URI param", "func", "ReleaseFile", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	file, errUc := h.userCase.ReleaseFile(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func ReleaseFile: Error work UseCase", "func", "ReleaseFile", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseFile(file))
+}
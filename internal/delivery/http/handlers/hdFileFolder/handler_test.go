@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
 )
 
 type mockUseCaseFileFolder struct {
@@ -31,72 +32,224 @@ func (m *mockUseCaseFileFolder) CreateFolder(ctx context.Context, folder *domain
 	return args.Get(0).(*domain.File), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) GetFolderContents(ctx context.Context, companyID string, path *domain.Path, fileType *domain.FileType) ([]*domain.File, error) {
-	args := m.Called(ctx, companyID, path, fileType)
+func (m *mockUseCaseFileFolder) ListFolder(ctx context.Context, companyID, userID string, query *domain.FolderListQuery) (*domain.FolderPage, error) {
+	args := m.Called(ctx, companyID, userID, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*domain.File), args.Error(1)
+	return args.Get(0).(*domain.FolderPage), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) MoveFolder(ctx context.Context, companyID string, folderPath *domain.Path, newPath *domain.Path) (*domain.Path, error) {
-	args := m.Called(ctx, companyID, folderPath, newPath)
+func (m *mockUseCaseFileFolder) MoveFolder(ctx context.Context, companyID, userID string, folderPath *domain.Path, newPath *domain.Path) (*domain.Path, error) {
+	args := m.Called(ctx, companyID, userID, folderPath, newPath)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Path), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) DeleteFolder(ctx context.Context, companyID string, folderPath *domain.Path) error {
-	args := m.Called(ctx, companyID, folderPath)
+func (m *mockUseCaseFileFolder) DeleteFolder(ctx context.Context, companyID, userID string, folderPath *domain.Path) error {
+	args := m.Called(ctx, companyID, userID, folderPath)
 	return args.Error(0)
 }
 
-func (m *mockUseCaseFileFolder) UploadFile(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader) (*domain.File, error) {
-	args := m.Called(ctx, companyID, userID, parentPath, filename, size, reader)
+func (m *mockUseCaseFileFolder) DeleteFolderRecursive(ctx context.Context, companyID, userID string, folderPath *domain.Path, permanent bool) (*domain.FolderDeleteJob, error) {
+	args := m.Called(ctx, companyID, userID, folderPath, permanent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FolderDeleteJob), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) GetFolderDeleteJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderDeleteJob, error) {
+	args := m.Called(ctx, companyID, userID, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FolderDeleteJob), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) CancelFolderDeleteJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderDeleteJob, error) {
+	args := m.Called(ctx, companyID, userID, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FolderDeleteJob), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) CopyFolder(ctx context.Context, companyID, userID string, folderPath, parentPath *domain.Path, name string, policy domain.ConflictPolicy) (*domain.FolderCopyJob, error) {
+	args := m.Called(ctx, companyID, userID, folderPath, parentPath, name, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FolderCopyJob), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) GetFolderCopyJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderCopyJob, error) {
+	args := m.Called(ctx, companyID, userID, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FolderCopyJob), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) CancelFolderCopyJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderCopyJob, error) {
+	args := m.Called(ctx, companyID, userID, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FolderCopyJob), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) PrepareFolderArchive(ctx context.Context, companyID, userID string, folderPath *domain.Path, format domain.ArchiveFormat) (*domain.FolderArchive, error) {
+	args := m.Called(ctx, companyID, userID, folderPath, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FolderArchive), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) WriteFolderArchive(ctx context.Context, archive *domain.FolderArchive, w io.Writer) error {
+	args := m.Called(ctx, archive, w)
+	if content, ok := args.Get(1).(string); ok {
+		_, _ = io.WriteString(w, content)
+	}
+	return args.Error(0)
+}
+
+func (m *mockUseCaseFileFolder) UploadFile(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader, checksum string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, parentPath, filename, size, reader, checksum)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.File), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) DownloadFile(ctx context.Context, companyID, fileID string) (io.ReadCloser, *domain.File, error) {
-	args := m.Called(ctx, companyID, fileID)
+func (m *mockUseCaseFileFolder) StatFile(ctx context.Context, companyID, userID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID)
 	if args.Get(0) == nil {
-		return nil, nil, args.Error(2)
+		return nil, args.Error(1)
 	}
-	return args.Get(0).(io.ReadCloser), args.Get(1).(*domain.File), args.Error(2)
+	return args.Get(0).(*domain.File), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) GetFileInfo(ctx context.Context, companyID, fileID string) (*domain.File, error) {
-	args := m.Called(ctx, companyID, fileID)
+func (m *mockUseCaseFileFolder) OpenFile(ctx context.Context, file *domain.File, byteRange *domain.ByteRange) (io.ReadCloser, error) {
+	args := m.Called(ctx, file, byteRange)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) GetFileInfo(ctx context.Context, companyID, userID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.File), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) RenameFile(ctx context.Context, companyID, fileID, newName string) (*domain.File, error) {
-	args := m.Called(ctx, companyID, fileID, newName)
+func (m *mockUseCaseFileFolder) RenameFile(ctx context.Context, companyID, userID, fileID, newName string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID, newName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.File), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) MoveFile(ctx context.Context, companyID, fileID string, newParentPath *domain.Path) (*domain.File, error) {
-	args := m.Called(ctx, companyID, fileID, newParentPath)
+func (m *mockUseCaseFileFolder) MoveFile(ctx context.Context, companyID, userID, fileID string, newParentPath *domain.Path) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID, newParentPath)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.File), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) DeleteFile(ctx context.Context, companyID, fileID string) error {
-	args := m.Called(ctx, companyID, fileID)
+func (m *mockUseCaseFileFolder) CopyFile(ctx context.Context, companyID, userID, fileID string, parentPath *domain.Path, name string, policy domain.ConflictPolicy) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID, parentPath, name, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) DeleteFile(ctx context.Context, companyID, userID, fileID string) error {
+	args := m.Called(ctx, companyID, userID, fileID)
 	return args.Error(0)
 }
 
+func (m *mockUseCaseFileFolder) GetThumbnail(ctx context.Context, companyID, userID, fileID string, size domain.RenditionSize) (*domain.FileRendition, error) {
+	args := m.Called(ctx, companyID, userID, fileID, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileRendition), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) OpenThumbnail(ctx context.Context, rendition *domain.FileRendition) (io.ReadCloser, error) {
+	args := m.Called(ctx, rendition)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) UploadFileVersion(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader, checksum string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, parentPath, filename, size, reader, checksum)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) GetFileVersions(ctx context.Context, companyID, userID, fileID string) ([]*domain.FileVersion, error) {
+	args := m.Called(ctx, companyID, userID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FileVersion), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) DownloadFileVersion(ctx context.Context, companyID, userID, fileID string, version int) (io.ReadCloser, *domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID, version)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(io.ReadCloser), args.Get(1).(*domain.File), args.Error(2)
+}
+
+func (m *mockUseCaseFileFolder) RestoreFileVersion(ctx context.Context, companyID, userID, fileID string, version int) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) GetDownloadURL(ctx context.Context, companyID, userID, fileID string) (*domain.PresignedURL, error) {
+	args := m.Called(ctx, companyID, userID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PresignedURL), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) InitPresignedUpload(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, checksum string) (*domain.PresignedUpload, error) {
+	args := m.Called(ctx, companyID, userID, parentPath, filename, size, checksum)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PresignedUpload), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) FinalizePresignedUpload(ctx context.Context, companyID, userID, uploadID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, uploadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
 func (m *mockUseCaseFileFolder) GetUploadStrategy(ctx context.Context, fileSize int64) (*domain.StrategyInfo, error) {
 	args := m.Called(ctx, fileSize)
 	if args.Get(0) == nil {
@@ -113,32 +266,32 @@ func (m *mockUseCaseFileFolder) InitChunkedUpload(ctx context.Context, companyID
 	return args.Get(0).(*domain.ChunkedUpload), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) UploadChunk(ctx context.Context, companyID, uploadID string, chunkIndex int, chunkData io.Reader, chunkSize int64) (*domain.ChunkedUpload, error) {
-	args := m.Called(ctx, companyID, uploadID, chunkIndex, chunkData, chunkSize)
+func (m *mockUseCaseFileFolder) UploadChunk(ctx context.Context, companyID, userID, uploadID string, chunkIndex int, chunkData io.Reader, chunkSize int64, checksum string) (*domain.ChunkedUpload, error) {
+	args := m.Called(ctx, companyID, userID, uploadID, chunkIndex, chunkData, chunkSize, checksum)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.ChunkedUpload), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) GetChunkedUploadStatus(ctx context.Context, companyID, uploadID string) (*domain.ChunkedUpload, error) {
-	args := m.Called(ctx, companyID, uploadID)
+func (m *mockUseCaseFileFolder) GetChunkedUploadStatus(ctx context.Context, companyID, userID, uploadID string) (*domain.ChunkedUpload, error) {
+	args := m.Called(ctx, companyID, userID, uploadID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.ChunkedUpload), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) CompleteChunkedUpload(ctx context.Context, companyID, uploadID string) (*domain.File, error) {
-	args := m.Called(ctx, companyID, uploadID)
+func (m *mockUseCaseFileFolder) CompleteChunkedUpload(ctx context.Context, companyID, userID, uploadID, checksum string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, uploadID, checksum)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.File), args.Error(1)
 }
 
-func (m *mockUseCaseFileFolder) AbortChunkedUpload(ctx context.Context, companyID, uploadID string) error {
-	args := m.Called(ctx, companyID, uploadID)
+func (m *mockUseCaseFileFolder) AbortChunkedUpload(ctx context.Context, companyID, userID, uploadID string) error {
+	args := m.Called(ctx, companyID, userID, uploadID)
 	return args.Error(0)
 }
 
@@ -150,6 +303,38 @@ func (m *mockUseCaseFileFolder) GetResourceStats(ctx context.Context) (*domain.R
 	return args.Get(0).(*domain.ResourceStats), args.Error(1)
 }
 
+func (m *mockUseCaseFileFolder) GetJanitorStats(ctx context.Context) (*domain.JanitorStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.JanitorStats), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) GetScanFindings(ctx context.Context, companyID string, status domain.ScanStatus) ([]*domain.ScanFinding, error) {
+	args := m.Called(ctx, companyID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ScanFinding), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) RescanFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *mockUseCaseFileFolder) ReleaseFile(ctx context.Context, companyID, userID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
@@ -291,6 +476,7 @@ func TestCreateFolder_Success(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
 
 	handler.CreateFolder(c)
 
@@ -335,6 +521,7 @@ func TestCreateFolder_InvalidJSON(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
 
 	handler.CreateFolder(c)
 
@@ -346,8 +533,11 @@ func TestGetFolderContents_Success(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	expectedFiles := []*domain.File{createTestFolder(), createTestFile()}
-	mockUC.On("GetFolderContents", mock.Anything, "company-123", mock.AnythingOfType("*domain.Path"), mock.AnythingOfType("*domain.FileType")).Return(expectedFiles, nil)
+	page := &domain.FolderPage{Items: []*domain.File{createTestFolder(), createTestFile()}, NextCursor: "next"}
+	mockUC.On("ListFolder", mock.Anything, "company-123", "user-123", mock.MatchedBy(func(query *domain.FolderListQuery) bool {
+		return query.Path == "/test" && *query.Type == domain.FileTypeFolder && !query.Recursive &&
+			query.Sort == domain.FolderSortType && query.Order == domain.SortOrderAsc
+	})).Return(page, nil)
 
 	reqBody := `{"path":"/test","type":"folder"}`
 	req := httptest.NewRequest("POST", "/folders/contents", strings.NewReader(reqBody))
@@ -357,6 +547,7 @@ func TestGetFolderContents_Success(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
 
 	handler.GetFolderContents(c)
 
@@ -368,17 +559,24 @@ func TestGetFolderContents_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "success", response.Status)
 	assert.Len(t, response.Files, 2)
+	assert.Equal(t, "next", response.NextCursor)
 }
 
-func TestUploadFile_Success(t *testing.T) {
+func TestGetFolderContents_WithCursorAndFilters(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	expectedFile := createTestFile()
-	mockUC.On("UploadFile", mock.Anything, "company-123", "user-123", mock.AnythingOfType("*domain.Path"), "test.txt", int64(12), mock.AnythingOfType("multipart.sectionReadCloser")).Return(expectedFile, nil)
+	cursor := domain.NewFolderCursor(createTestFile(), domain.FolderSortSize, domain.SortOrderDesc).Encode()
+	mockUC.On("ListFolder", mock.Anything, "company-123", "user-123", mock.MatchedBy(func(query *domain.FolderListQuery) bool {
+		return query.Recursive && query.MimeType == "image/*" && *query.MinSize == 1024 &&
+			query.Sort == domain.FolderSortSize && query.Order == domain.SortOrderDesc &&
+			query.Limit == 50 && query.Cursor != nil && query.Cursor.ID == createTestFile().ID &&
+			len(query.Tags) == 1 && query.Tags[0] == "q3" && query.Metadata["project"] == "PRJ-42"
+	})).Return(&domain.FolderPage{}, nil)
 
-	req, err := createMultipartRequest("file", "test.txt", "test content")
-	assert.NoError(t, err)
+	reqBody := `{"path":"/test","recursive":true,"mimeType":"image/*","minSize":1024,"tags":["Q3"],"metadata":{"project":"PRJ-42"},"sort":"size","order":"desc","limit":50,"cursor":"` + cursor + `"}`
+	req := httptest.NewRequest("POST", "/folders/contents", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -386,19 +584,19 @@ func TestUploadFile_Success(t *testing.T) {
 	c.Set("company_id", "company-123")
 	c.Set("user_id", "user-123")
 
-	handler.UploadFile(c)
+	handler.GetFolderContents(c)
 
-	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
 	mockUC.AssertExpectations(t)
 }
 
-func TestUploadFile_MissingFile(t *testing.T) {
+func TestGetFolderContents_InvalidCursor(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	reqBody := `parentPath=/test`
-	req := httptest.NewRequest("POST", "/files/upload", strings.NewReader(reqBody))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	reqBody := `{"path":"/test","cursor":"not-a-cursor"}`
+	req := httptest.NewRequest("POST", "/folders/contents", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -406,204 +604,1355 @@ func TestUploadFile_MissingFile(t *testing.T) {
 	c.Set("company_id", "company-123")
 	c.Set("user_id", "user-123")
 
-	handler.UploadFile(c)
+	handler.GetFolderContents(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	mockUC.AssertNotCalled(t, "UploadFile")
+	mockUC.AssertNotCalled(t, "ListFolder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestDownloadFile_Success(t *testing.T) {
-	mockUC := new(mockUseCaseFileFolder)
-	handler := NewHandlerFileFolder(mockUC)
+func TestDeleteFolder_Recursive(t *testing.T) {
+	t.Run("small tree finishes inline", func(t *testing.T) {
+		mockUC := new(mockUseCaseFileFolder)
+		handler := NewHandlerFileFolder(mockUC)
 
-	testFile := createTestFile()
-	mockReader := io.NopCloser(strings.NewReader("test file content"))
-	mockUC.On("DownloadFile", mock.Anything, "company-123", "123e4567-e89b-12d3-a456-426614174000").Return(mockReader, testFile, nil)
+		path := domain.Path("/projects")
+		job := &domain.FolderDeleteJob{ID: "job-id", FolderPath: path, TotalItems: 3, DeletedItems: 3}
+		job.Finish(domain.JobStatusCompleted, nil)
+		mockUC.On("DeleteFolderRecursive", mock.Anything, "company-123", "user-123", &path, false).Return(job, nil)
 
-	req := httptest.NewRequest("GET", "/files/123e4567-e89b-12d3-a456-426614174000/download", nil)
+		req := httptest.NewRequest("DELETE", "/folders/projects?recursive=true", nil)
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request = req
-	c.Set("company_id", "company-123")
-	c.Params = []gin.Param{{Key: "id", Value: "123e4567-e89b-12d3-a456-426614174000"}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("company_id", "company-123")
+		c.Set("user_id", "user-123")
+		c.Params = []gin.Param{{Key: "path", Value: "projects"}}
 
-	handler.DownloadFile(c)
+		handler.DeleteFolder(c)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
-	assert.Contains(t, w.Header().Get("Content-Disposition"), "test.txt")
-	mockUC.AssertExpectations(t)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUC.AssertExpectations(t)
+
+		var response ResponseFolderDeleteJob
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, domain.JobStatusCompleted, response.JobStatus)
+		assert.Equal(t, float64(100), response.Progress)
+	})
+
+	t.Run("large tree is queued", func(t *testing.T) {
+		mockUC := new(mockUseCaseFileFolder)
+		handler := NewHandlerFileFolder(mockUC)
+
+		path := domain.Path("/projects")
+		job := &domain.FolderDeleteJob{ID: "job-id", FolderPath: path, Permanent: true, Status: domain.JobStatusPending, TotalItems: 5000}
+		mockUC.On("DeleteFolderRecursive", mock.Anything, "company-123", "user-123", &path, true).Return(job, nil)
+
+		req := httptest.NewRequest("DELETE", "/folders/projects?recursive=true&permanent=true", nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("company_id", "company-123")
+		c.Set("user_id", "user-123")
+		c.Params = []gin.Param{{Key: "path", Value: "projects"}}
+
+		handler.DeleteFolder(c)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockUC.AssertExpectations(t)
+		mockUC.AssertNotCalled(t, "DeleteFolder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
-func TestGetUploadStrategy_Success(t *testing.T) {
+func TestGetFolderDeleteJob_Success(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	expectedStrategy := createTestStrategyInfo()
-	mockUC.On("GetUploadStrategy", mock.Anything, int64(1073741824)).Return(expectedStrategy, nil)
+	jobID := "123e4567-e89b-12d3-a456-426614174000"
+	job := &domain.FolderDeleteJob{ID: jobID, Permanent: true, Status: domain.JobStatusRunning, TotalItems: 10, DeletedItems: 10, PurgedItems: 5}
+	mockUC.On("GetFolderDeleteJob", mock.Anything, "company-123", "user-123", jobID).Return(job, nil)
 
-	req := httptest.NewRequest("GET", "/files/upload-strategy?fileSize=1073741824", nil)
+	req := httptest.NewRequest("GET", "/jobs/folder-delete/"+jobID, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: jobID}}
 
-	handler.GetUploadStrategy(c)
+	handler.GetFolderDeleteJob(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	mockUC.AssertExpectations(t)
 
-	var response ResponseUploadStrategy
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, "success", response.Status)
-	assert.Equal(t, domain.UploadStrategyChunked, response.Strategy.Strategy)
+	var response ResponseFolderDeleteJob
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(75), response.Progress)
 }
 
-func TestGetUploadStrategy_MissingFileSize(t *testing.T) {
+func TestCancelFolderDeleteJob_AlreadyFinished(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	req := httptest.NewRequest("GET", "/files/upload-strategy", nil)
+	jobID := "123e4567-e89b-12d3-a456-426614174000"
+	mockUC.On("CancelFolderDeleteJob", mock.Anything, "company-123", "user-123", jobID).
+		Return(nil, pkgErrors.Conflict("folder delete job is already finished"))
+
+	req := httptest.NewRequest("POST", "/jobs/folder-delete/"+jobID+"/cancel", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: jobID}}
 
-	handler.GetUploadStrategy(c)
+	handler.CancelFolderDeleteJob(c)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-	mockUC.AssertNotCalled(t, "GetUploadStrategy")
+	assert.Equal(t, http.StatusConflict, w.Code)
 }
 
-func TestInitChunkedUpload_Success(t *testing.T) {
+func TestCopyFolder(t *testing.T) {
+	t.Run("small tree finishes inline", func(t *testing.T) {
+		mockUC := new(mockUseCaseFileFolder)
+		handler := NewHandlerFileFolder(mockUC)
+
+		path := domain.Path("/projects")
+		parentPath := domain.Path("/archive")
+		targetID := "copy-id"
+		job := &domain.FolderCopyJob{ID: "job-id", SourcePath: path, TargetPath: "/archive/projects", TargetID: &targetID, TotalItems: 3, CopiedItems: 3}
+		job.Finish(domain.JobStatusCompleted, nil)
+		mockUC.On("CopyFolder", mock.Anything, "company-123", "user-123", &path, &parentPath, "", domain.ConflictPolicyFail).Return(job, nil)
+
+		req := httptest.NewRequest("POST", "/folders/projects/copy", strings.NewReader(`{"parentPath":"/archive"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("company_id", "company-123")
+		c.Set("user_id", "user-123")
+		c.Params = []gin.Param{{Key: "path", Value: "projects"}}
+
+		handler.CopyFolder(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUC.AssertExpectations(t)
+
+		var response ResponseFolderCopyJob
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, domain.JobStatusCompleted, response.JobStatus)
+		assert.Equal(t, "copy-id", *response.TargetID)
+	})
+
+	t.Run("large tree is queued", func(t *testing.T) {
+		mockUC := new(mockUseCaseFileFolder)
+		handler := NewHandlerFileFolder(mockUC)
+
+		path := domain.Path("/projects")
+		parentPath := domain.Path("/")
+		job := &domain.FolderCopyJob{ID: "job-id", SourcePath: path, TargetPath: "/projects (1)", Status: domain.JobStatusPending, TotalItems: 5000}
+		mockUC.On("CopyFolder", mock.Anything, "company-123", "user-123", &path, &parentPath, "", domain.ConflictPolicyRename).Return(job, nil)
+
+		req := httptest.NewRequest("POST", "/folders/projects/copy", strings.NewReader(`{"parentPath":"/","onConflict":"rename"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("company_id", "company-123")
+		c.Set("user_id", "user-123")
+		c.Params = []gin.Param{{Key: "path", Value: "projects"}}
+
+		handler.CopyFolder(c)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid conflict policy", func(t *testing.T) {
+		mockUC := new(mockUseCaseFileFolder)
+		handler := NewHandlerFileFolder(mockUC)
+
+		req := httptest.NewRequest("POST", "/folders/projects/copy", strings.NewReader(`{"parentPath":"/","onConflict":"overwrite"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("company_id", "company-123")
+		c.Set("user_id", "user-123")
+		c.Params = []gin.Param{{Key: "path", Value: "projects"}}
+
+		handler.CopyFolder(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUC.AssertNotCalled(t, "CopyFolder", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetFolderCopyJob_Success(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	expectedUpload := createTestChunkedUpload()
-	expectedStrategy := createTestStrategyInfo()
-
-	mockUC.On("InitChunkedUpload", mock.Anything, "company-123", "user-123", "large-file.zip", int64(1073741824), mock.AnythingOfType("*domain.Path"), "application/zip").Return(expectedUpload, nil)
-	mockUC.On("GetUploadStrategy", mock.Anything, int64(1073741824)).Return(expectedStrategy, nil)
+	jobID := "123e4567-e89b-12d3-a456-426614174000"
+	job := &domain.FolderCopyJob{ID: jobID, Status: domain.JobStatusRunning, TotalItems: 8, CopiedItems: 2}
+	mockUC.On("GetFolderCopyJob", mock.Anything, "company-123", "user-123", jobID).Return(job, nil)
 
-	reqBody := `{"fileName":"large-file.zip","fileSize":1073741824,"parentPath":"/test","mimeType":"application/zip"}`
-	req := httptest.NewRequest("POST", "/files/chunked/init", strings.NewReader(reqBody))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest("GET", "/jobs/folder-copy/"+jobID, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Set("company_id", "company-123")
 	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: jobID}}
 
-	handler.InitChunkedUpload(c)
+	handler.GetFolderCopyJob(c)
 
-	assert.Equal(t, http.StatusCreated, w.Code)
-	mockUC.AssertExpectations(t)
+	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response ResponseInitChunkedUpload
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, "success", response.Status)
-	assert.Equal(t, expectedUpload.ID, response.UploadID)
+	var response ResponseFolderCopyJob
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(25), response.Progress)
 }
 
-func TestUploadChunk_Success(t *testing.T) {
+func TestCancelFolderCopyJob_Success(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	expectedUpload := createTestChunkedUpload()
-	expectedUpload.UploadedChunks = 1
-
-	mockUC.On("UploadChunk", mock.Anything, "company-123", "123e4567-e89b-12d3-a456-426614174001", 0, mock.AnythingOfType("multipart.sectionReadCloser"), int64(5242880)).Return(expectedUpload, nil)
+	jobID := "123e4567-e89b-12d3-a456-426614174000"
+	job := &domain.FolderCopyJob{ID: jobID, TotalItems: 8}
+	job.Finish(domain.JobStatusCancelled, nil)
+	mockUC.On("CancelFolderCopyJob", mock.Anything, "company-123", "user-123", jobID).Return(job, nil)
 
-	req, err := createMultipartRequest("chunk", "chunk-0", strings.Repeat("a", 5242880))
-	assert.NoError(t, err)
+	req := httptest.NewRequest("POST", "/jobs/folder-copy/"+jobID+"/cancel", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Set("company_id", "company-123")
-	c.Params = []gin.Param{
-		{Key: "uploadId", Value: "123e4567-e89b-12d3-a456-426614174001"},
-		{Key: "chunkIndex", Value: "0"},
-	}
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: jobID}}
 
-	handler.UploadChunk(c)
+	handler.CancelFolderCopyJob(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockUC.AssertExpectations(t)
 }
 
-func TestGetResourceStats_Success(t *testing.T) {
+func TestDownloadFolderArchive_Success(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	expectedStats := createTestResourceStats()
-	mockUC.On("GetResourceStats", mock.Anything).Return(expectedStats, nil)
+	path := domain.Path("/docs")
+	archive := &domain.FolderArchive{Name: "docs", Format: domain.ArchiveFormatTarGz}
+	mockUC.On("PrepareFolderArchive", mock.Anything, "company-123", "user-123", &path, domain.ArchiveFormatTarGz).Return(archive, nil)
+	mockUC.On("WriteFolderArchive", mock.Anything, archive, mock.Anything).Return(nil, "archive content")
 
-	req := httptest.NewRequest("GET", "/files/stats", nil)
+	req := httptest.NewRequest("GET", "/folders/docs/archive?format=tar.gz", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "path", Value: "docs"}}
 
-	handler.GetResourceStats(c)
+	handler.DownloadFolderArchive(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/gzip", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "docs.tar.gz")
+	assert.Equal(t, "archive content", w.Body.String())
 	mockUC.AssertExpectations(t)
-
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, "success", response["status"])
-	assert.NotNil(t, response["stats"])
 }
 
-func TestDeleteFile_UseCaseError(t *testing.T) {
+func TestDownloadFolderArchive_InvalidFormat(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	mockUC.On("DeleteFile", mock.Anything, "company-123", "123e4567-e89b-12d3-a456-426614174000").Return(errors.New("file not found"))
-
-	req := httptest.NewRequest("DELETE", "/files/123e4567-e89b-12d3-a456-426614174000", nil)
+	req := httptest.NewRequest("GET", "/folders/docs/archive?format=rar", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Set("company_id", "company-123")
-	c.Params = []gin.Param{{Key: "id", Value: "123e4567-e89b-12d3-a456-426614174000"}}
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "path", Value: "docs"}}
 
-	handler.DeleteFile(c)
+	handler.DownloadFolderArchive(c)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	mockUC.AssertExpectations(t)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "PrepareFolderArchive", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestCompleteChunkedUpload_Success(t *testing.T) {
+func TestDownloadFolderArchive_TooLarge(t *testing.T) {
 	mockUC := new(mockUseCaseFileFolder)
 	handler := NewHandlerFileFolder(mockUC)
 
-	expectedFile := createTestFile()
-	mockUC.On("CompleteChunkedUpload", mock.Anything, "company-123", "123e4567-e89b-12d3-a456-426614174001").Return(expectedFile, nil)
+	path := domain.Path("/docs")
+	mockUC.On("PrepareFolderArchive", mock.Anything, "company-123", "user-123", &path, domain.ArchiveFormatZip).
+		Return(nil, pkgErrors.FileTooLarge("folder exceeds the archive limit"))
 
-	req := httptest.NewRequest("POST", "/files/chunked/123e4567-e89b-12d3-a456-426614174001/complete", nil)
+	req := httptest.NewRequest("GET", "/folders/docs/archive", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Set("company_id", "company-123")
-	c.Params = []gin.Param{{Key: "uploadId", Value: "123e4567-e89b-12d3-a456-426614174001"}}
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "path", Value: "docs"}}
 
-	handler.CompleteChunkedUpload(c)
+	handler.DownloadFolderArchive(c)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockUC.AssertNotCalled(t, "WriteFolderArchive", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUploadFile_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedFile := createTestFile()
+	mockUC.On("UploadFile", mock.Anything, "company-123", "user-123", mock.AnythingOfType("*domain.Path"), "test.txt", int64(12), mock.AnythingOfType("multipart.sectionReadCloser"), "").Return(expectedFile, nil)
+
+	req, err := createMultipartRequest("file", "test.txt", "test content")
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.UploadFile(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUploadFile_MissingFile(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	reqBody := `parentPath=/test`
+	req := httptest.NewRequest("POST", "/files/upload", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.UploadFile(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "UploadFile")
+}
+
+func TestDownloadFile_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	testFile := createTestFile()
+	mockReader := io.NopCloser(strings.NewReader("test file content"))
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+	mockUC.On("OpenFile", mock.Anything, testFile, (*domain.ByteRange)(nil)).Return(mockReader, nil)
+
+	req := httptest.NewRequest("GET", "/files/123e4567-e89b-12d3-a456-426614174000/download", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: "123e4567-e89b-12d3-a456-426614174000"}}
+
+	handler.DownloadFile(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "test.txt")
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetUploadStrategy_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedStrategy := createTestStrategyInfo()
+	mockUC.On("GetUploadStrategy", mock.Anything, int64(1073741824)).Return(expectedStrategy, nil)
+
+	req := httptest.NewRequest("GET", "/files/upload-strategy?fileSize=1073741824", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.GetUploadStrategy(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockUC.AssertExpectations(t)
 
-	var response ResponseCompleteChunkedUpload
+	var response ResponseUploadStrategy
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, "success", response.Status)
-	assert.Equal(t, expectedFile.ID, response.File.ID)
+	assert.Equal(t, domain.UploadStrategyChunked, response.Strategy.Strategy)
+}
+
+func TestGetUploadStrategy_MissingFileSize(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	req := httptest.NewRequest("GET", "/files/upload-strategy", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.GetUploadStrategy(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "GetUploadStrategy")
+}
+
+func TestInitChunkedUpload_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedUpload := createTestChunkedUpload()
+	expectedStrategy := createTestStrategyInfo()
+
+	mockUC.On("InitChunkedUpload", mock.Anything, "company-123", "user-123", "large-file.zip", int64(1073741824), mock.AnythingOfType("*domain.Path"), "application/zip").Return(expectedUpload, nil)
+	mockUC.On("GetUploadStrategy", mock.Anything, int64(1073741824)).Return(expectedStrategy, nil)
+
+	reqBody := `{"fileName":"large-file.zip","fileSize":1073741824,"parentPath":"/test","mimeType":"application/zip"}`
+	req := httptest.NewRequest("POST", "/files/chunked/init", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.InitChunkedUpload(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseInitChunkedUpload
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", response.Status)
+	assert.Equal(t, expectedUpload.ID, response.UploadID)
+}
+
+func TestUploadChunk_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedUpload := createTestChunkedUpload()
+	expectedUpload.UploadedChunks = 1
+
+	mockUC.On("UploadChunk", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174001", 0, mock.AnythingOfType("multipart.sectionReadCloser"), int64(5242880), "").Return(expectedUpload, nil)
+
+	req, err := createMultipartRequest("chunk", "chunk-0", strings.Repeat("a", 5242880))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{
+		{Key: "uploadId", Value: "123e4567-e89b-12d3-a456-426614174001"},
+		{Key: "chunkIndex", Value: "0"},
+	}
+
+	handler.UploadChunk(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUploadChunk_WithChecksumHeader(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	checksum := "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+	expectedUpload := createTestChunkedUpload()
+
+	mockUC.On("UploadChunk", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174001", 1, mock.AnythingOfType("multipart.sectionReadCloser"), int64(12), checksum).Return(expectedUpload, nil)
+
+	req, err := createMultipartRequest("chunk", "chunk-1", "test content")
+	assert.NoError(t, err)
+	req.Header.Set(HeaderChecksumSHA256, checksum)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{
+		{Key: "uploadId", Value: "123e4567-e89b-12d3-a456-426614174001"},
+		{Key: "chunkIndex", Value: "1"},
+	}
+
+	handler.UploadChunk(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetResourceStats_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedStats := createTestResourceStats()
+	mockUC.On("GetResourceStats", mock.Anything).Return(expectedStats, nil)
+
+	req := httptest.NewRequest("GET", "/files/stats", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.GetResourceStats(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", response["status"])
+	assert.NotNil(t, response["stats"])
+}
+
+func TestGetJanitorStats_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	stats := &domain.JanitorStats{}
+	stats.Record(domain.JanitorRun{StartedAt: time.Now(), ExpiredSessions: 2, AbortedUploads: 3})
+	mockUC.On("GetJanitorStats", mock.Anything).Return(stats, nil)
+
+	req := httptest.NewRequest("GET", "/files/janitor/stats", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.GetJanitorStats(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseJanitorStats
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Stats.Runs)
+	assert.Equal(t, 2, response.Stats.LastRun.ExpiredSessions)
+	assert.Equal(t, 3, response.Stats.Total.AbortedUploads)
+}
+
+func TestCopyFile_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	parentPath := domain.Path("/backup")
+	copied := createTestFile()
+	mockUC.On("CopyFile", mock.Anything, "company-123", "user-123", fileID, &parentPath, "report.pdf", domain.ConflictPolicyFail).Return(copied, nil)
+
+	req := httptest.NewRequest("POST", "/files/"+fileID+"/copy", strings.NewReader(`{"parentPath":"/backup","name":"report.pdf"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}}
+
+	handler.CopyFile(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestCopyFile_NameConflict(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	parentPath := domain.Path("/backup")
+	mockUC.On("CopyFile", mock.Anything, "company-123", "user-123", fileID, &parentPath, "", domain.ConflictPolicyFail).
+		Return(nil, pkgErrors.FileExists("an item with this name already exists at the destination"))
+
+	req := httptest.NewRequest("POST", "/files/"+fileID+"/copy", strings.NewReader(`{"parentPath":"/backup"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}}
+
+	handler.CopyFile(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestDeleteFile_UseCaseError(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	mockUC.On("DeleteFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(errors.New("file not found"))
+
+	req := httptest.NewRequest("DELETE", "/files/123e4567-e89b-12d3-a456-426614174000", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: "123e4567-e89b-12d3-a456-426614174000"}}
+
+	handler.DeleteFile(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestCompleteChunkedUpload_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedFile := createTestFile()
+	mockUC.On("CompleteChunkedUpload", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174001", "").Return(expectedFile, nil)
+
+	req := httptest.NewRequest("POST", "/files/chunked/123e4567-e89b-12d3-a456-426614174001/complete", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "uploadId", Value: "123e4567-e89b-12d3-a456-426614174001"}}
+
+	handler.CompleteChunkedUpload(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseCompleteChunkedUpload
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", response.Status)
+	assert.Equal(t, expectedFile.ID, response.File.ID)
+}
+
+func TestUploadFile_VersionMode(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedFile := createTestFile()
+	mockUC.On("UploadFileVersion", mock.Anything, "company-123", "user-123", mock.AnythingOfType("*domain.Path"), "test.txt", int64(12), mock.AnythingOfType("multipart.sectionReadCloser"), "").Return(expectedFile, nil)
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	assert.NoError(t, writer.WriteField("parentPath", "/test"))
+	assert.NoError(t, writer.WriteField("mode", "version"))
+	part, err := writer.CreateFormFile("file", "test.txt")
+	assert.NoError(t, err)
+	_, err = part.Write([]byte("test content"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/files/upload", &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.UploadFile(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+	mockUC.AssertNotCalled(t, "UploadFile")
+}
+
+func TestGetFileVersions_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	versions := []*domain.FileVersion{
+		{ID: "v2", FileID: fileID, Version: 2, MimeType: "text/plain", Size: 2048, CreatedAt: time.Now()},
+		{ID: "v1", FileID: fileID, Version: 1, MimeType: "text/plain", Size: 1024, CreatedAt: time.Now()},
+	}
+	mockUC.On("GetFileVersions", mock.Anything, "company-123", "user-123", fileID).Return(versions, nil)
+
+	req := httptest.NewRequest("GET", "/files/"+fileID+"/versions", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}}
+
+	handler.GetFileVersions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseFileVersions
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Versions, 2)
+	assert.Equal(t, 2, response.Versions[0].Version)
+}
+
+func TestDownloadFileVersion_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	testFile := createTestFile()
+	mockReader := io.NopCloser(strings.NewReader("old content"))
+	mockUC.On("DownloadFileVersion", mock.Anything, "company-123", "user-123", fileID, 1).Return(mockReader, testFile, nil)
+
+	req := httptest.NewRequest("GET", "/files/"+fileID+"/versions/1/download", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}, {Key: "version", Value: "1"}}
+
+	handler.DownloadFileVersion(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "old content", w.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestDownloadFileVersion_InvalidVersion(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	req := httptest.NewRequest("GET", "/files/123e4567-e89b-12d3-a456-426614174000/versions/0/download", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: "123e4567-e89b-12d3-a456-426614174000"}, {Key: "version", Value: "0"}}
+
+	handler.DownloadFileVersion(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "DownloadFileVersion")
+}
+
+func TestRestoreFileVersion_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	expectedFile := createTestFile()
+	mockUC.On("RestoreFileVersion", mock.Anything, "company-123", "user-123", fileID, 3).Return(expectedFile, nil)
+
+	req := httptest.NewRequest("POST", "/files/"+fileID+"/versions/3/restore", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}, {Key: "version", Value: "3"}}
+
+	handler.RestoreFileVersion(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUploadFile_WithChecksumHeader(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	checksum := "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+	expectedFile := createTestFile()
+	mockUC.On("UploadFile", mock.Anything, "company-123", "user-123", mock.AnythingOfType("*domain.Path"), "test.txt", int64(12), mock.AnythingOfType("multipart.sectionReadCloser"), checksum).Return(expectedFile, nil)
+
+	req, err := createMultipartRequest("file", "test.txt", "test content")
+	assert.NoError(t, err)
+	req.Header.Set(HeaderChecksumSHA256, checksum)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.UploadFile(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUploadFile_WithDigestHeader(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	expectedFile := createTestFile()
+	mockUC.On("UploadFile", mock.Anything, "company-123", "user-123", mock.AnythingOfType("*domain.Path"), "test.txt", int64(12), mock.AnythingOfType("multipart.sectionReadCloser"), "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72").Return(expectedFile, nil)
+
+	req, err := createMultipartRequest("file", "test.txt", "test content")
+	assert.NoError(t, err)
+	req.Header.Set("Digest", "md5=HUXZLQLMuI/KZ5KDcJPcOA==, SHA-256=auinVVUgn9bEQVfArtgBbnY/9DWhnPGG92hjFAFD/3I=")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.UploadFile(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUploadFile_InvalidDigestHeader(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	req, err := createMultipartRequest("file", "test.txt", "test content")
+	assert.NoError(t, err)
+	req.Header.Set("Digest", "sha-256=%%%")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.UploadFile(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "UploadFile")
+}
+
+func TestDownloadFile_DigestHeaders(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	hash := "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+	testFile := createTestFile()
+	testFile.Hash = &hash
+	mockReader := io.NopCloser(strings.NewReader("test content"))
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+	mockUC.On("OpenFile", mock.Anything, testFile, (*domain.ByteRange)(nil)).Return(mockReader, nil)
+
+	req := httptest.NewRequest("GET", "/files/123e4567-e89b-12d3-a456-426614174000/download", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: "123e4567-e89b-12d3-a456-426614174000"}}
+
+	handler.DownloadFile(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"`+hash+`"`, w.Header().Get("ETag"))
+	assert.Equal(t, "sha-256=auinVVUgn9bEQVfArtgBbnY/9DWhnPGG92hjFAFD/3I=", w.Header().Get("Digest"))
+	mockUC.AssertExpectations(t)
+}
+
+func newDownloadContext(headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest("GET", "/files/123e4567-e89b-12d3-a456-426614174000/download", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: "123e4567-e89b-12d3-a456-426614174000"}}
+	return c, w
+}
+
+func createHashedTestFile(content string) *domain.File {
+	hash := "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+	size := int64(len(content))
+	file := createTestFile()
+	file.Hash = &hash
+	file.Size = &size
+	file.UpdatedAt = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	return file
+}
+
+func TestDownloadFile_SingleRange(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	testFile := createHashedTestFile("test content")
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+	mockUC.On("OpenFile", mock.Anything, testFile, &domain.ByteRange{Start: 5, End: 11}).
+		Return(io.NopCloser(strings.NewReader("content")), nil)
+
+	c, w := newDownloadContext(map[string]string{"Range": "bytes=5-"})
+	handler.DownloadFile(c)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes 5-11/12", w.Header().Get("Content-Range"))
+	assert.Equal(t, "7", w.Header().Get("Content-Length"))
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "content", w.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestDownloadFile_MultipleRanges(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	testFile := createHashedTestFile("test content")
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+	mockUC.On("OpenFile", mock.Anything, testFile, &domain.ByteRange{Start: 0, End: 3}).
+		Return(io.NopCloser(strings.NewReader("test")), nil)
+	mockUC.On("OpenFile", mock.Anything, testFile, &domain.ByteRange{Start: 9, End: 11}).
+		Return(io.NopCloser(strings.NewReader("ent")), nil)
+
+	c, w := newDownloadContext(map[string]string{"Range": "bytes=0-3,-3"})
+	handler.DownloadFile(c)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "multipart/byteranges; boundary=")
+	assert.Contains(t, w.Body.String(), "Content-Range: bytes 0-3/12")
+	assert.Contains(t, w.Body.String(), "Content-Range: bytes 9-11/12")
+	mockUC.AssertExpectations(t)
+}
+
+func TestDownloadFile_RangeNotSatisfiable(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	testFile := createHashedTestFile("test content")
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+
+	c, w := newDownloadContext(map[string]string{"Range": "bytes=100-200"})
+	handler.DownloadFile(c)
+
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	assert.Equal(t, "bytes */12", w.Header().Get("Content-Range"))
+	mockUC.AssertNotCalled(t, "OpenFile")
+}
+
+func TestDownloadFile_IfNoneMatch(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	testFile := createHashedTestFile("test content")
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+
+	c, w := newDownloadContext(map[string]string{"If-None-Match": `W/"other", "` + *testFile.Hash + `"`})
+	handler.DownloadFile(c)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	mockUC.AssertNotCalled(t, "OpenFile")
+}
+
+func TestDownloadFile_IfModifiedSince(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	testFile := createHashedTestFile("test content")
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+
+	c, w := newDownloadContext(map[string]string{"If-Modified-Since": "Tue, 01 Jul 2025 12:00:00 GMT"})
+	handler.DownloadFile(c)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	mockUC.AssertNotCalled(t, "OpenFile")
+}
+
+func TestDownloadFile_IfRangeMismatchServesFullContent(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	testFile := createHashedTestFile("test content")
+	mockUC.On("StatFile", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000").Return(testFile, nil)
+	mockUC.On("OpenFile", mock.Anything, testFile, (*domain.ByteRange)(nil)).
+		Return(io.NopCloser(strings.NewReader("test content")), nil)
+
+	c, w := newDownloadContext(map[string]string{"Range": "bytes=0-3", "If-Range": `"stale"`})
+	handler.DownloadFile(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "test content", w.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetDownloadURL_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	presigned := &domain.PresignedURL{URL: "http://minio:9000/bucket/key?X-Amz-Signature=abc", ExpiresAt: time.Now().Add(15 * time.Minute)}
+	mockUC.On("GetDownloadURL", mock.Anything, "company-123", "user-123", fileID).Return(presigned, nil)
+
+	req := httptest.NewRequest("GET", "/files/"+fileID+"/download-url", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}}
+
+	handler.GetDownloadURL(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseDownloadURL
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, presigned.URL, response.URL)
+}
+
+func TestGetDownloadURL_InvalidID(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	req := httptest.NewRequest("GET", "/files/invalid/download-url", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: "invalid"}}
+
+	handler.GetDownloadURL(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "GetDownloadURL")
+}
+
+func TestInitPresignedUpload_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	checksum := "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+	upload := &domain.PresignedUpload{
+		ID:        "123e4567-e89b-12d3-a456-426614174111",
+		URL:       "http://minio:9000/bucket/key?X-Amz-Signature=abc",
+		Headers:   map[string]string{"Content-Type": "text/plain"},
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+	}
+	mockUC.On("InitPresignedUpload", mock.Anything, "company-123", "user-123", mock.AnythingOfType("*domain.Path"), "test.txt", int64(12), checksum).Return(upload, nil)
+
+	body := `{"fileName":"test.txt","fileSize":12,"parentPath":"/","checksum":"` + checksum + `"}`
+	req := httptest.NewRequest("POST", "/files/presigned/init", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.InitPresignedUpload(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseInitPresignedUpload
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, upload.ID, response.UploadID)
+	assert.Equal(t, http.MethodPut, response.Method)
+	assert.Equal(t, "text/plain", response.Headers["Content-Type"])
+}
+
+func TestInitPresignedUpload_MissingFileName(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	req := httptest.NewRequest("POST", "/files/presigned/init", strings.NewReader(`{"fileSize":12,"parentPath":"/"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+
+	handler.InitPresignedUpload(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "InitPresignedUpload")
+}
+
+func TestFinalizePresignedUpload_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	uploadID := "123e4567-e89b-12d3-a456-426614174111"
+	expectedFile := createTestFile()
+	mockUC.On("FinalizePresignedUpload", mock.Anything, "company-123", "user-123", uploadID).Return(expectedFile, nil)
+
+	req := httptest.NewRequest("POST", "/files/presigned/"+uploadID+"/finalize", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "uploadId", Value: uploadID}}
+
+	handler.FinalizePresignedUpload(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestFinalizePresignedUpload_NotUploaded(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	uploadID := "123e4567-e89b-12d3-a456-426614174111"
+	mockUC.On("FinalizePresignedUpload", mock.Anything, "company-123", "user-123", uploadID).Return(nil, pkgErrors.BadRequest("file has not been uploaded to storage yet"))
+
+	req := httptest.NewRequest("POST", "/files/presigned/"+uploadID+"/finalize", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "uploadId", Value: uploadID}}
+
+	handler.FinalizePresignedUpload(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func newThumbnailContext(query string, headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	c, w := newDownloadContext(headers)
+	c.Request.URL.RawQuery = query
+	return c, w
+}
+
+func createTestRendition() *domain.FileRendition {
+	return &domain.FileRendition{
+		FileID:      "123e4567-e89b-12d3-a456-426614174000",
+		CompanyID:   "company-123",
+		Size:        domain.RenditionSmall,
+		StoragePath: "companies/company-123/renditions/123e4567-e89b-12d3-a456-426614174000/small",
+		MimeType:    "image/jpeg",
+		Width:       128,
+		Height:      96,
+		ByteSize:    9,
+		Hash:        "5d41402abc4b2a76b9719d911017c592",
+	}
+}
+
+func TestGetThumbnail_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	rendition := createTestRendition()
+	mockUC.On("GetThumbnail", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000", domain.RenditionSmall).
+		Return(rendition, nil)
+	mockUC.On("OpenThumbnail", mock.Anything, rendition).
+		Return(io.NopCloser(strings.NewReader("jpeg data")), nil)
+
+	c, w := newThumbnailContext("size=small", nil)
+	handler.GetThumbnail(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	assert.Equal(t, `"`+rendition.Hash+`"`, w.Header().Get("ETag"))
+	assert.Equal(t, "private, max-age=86400", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "jpeg data", w.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetThumbnail_DefaultsToMedium(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	rendition := createTestRendition()
+	rendition.Size = domain.RenditionMedium
+	mockUC.On("GetThumbnail", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000", domain.RenditionMedium).
+		Return(rendition, nil)
+	mockUC.On("OpenThumbnail", mock.Anything, rendition).
+		Return(io.NopCloser(strings.NewReader("jpeg data")), nil)
+
+	c, w := newThumbnailContext("", nil)
+	handler.GetThumbnail(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetThumbnail_InvalidSize(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	c, w := newThumbnailContext("size=huge", nil)
+	handler.GetThumbnail(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "GetThumbnail")
+}
+
+func TestGetThumbnail_IfNoneMatch(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	rendition := createTestRendition()
+	mockUC.On("GetThumbnail", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000", domain.RenditionSmall).
+		Return(rendition, nil)
+
+	c, w := newThumbnailContext("size=small", map[string]string{"If-None-Match": `"` + rendition.Hash + `"`})
+	handler.GetThumbnail(c)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, "private, max-age=86400", w.Header().Get("Cache-Control"))
+	mockUC.AssertNotCalled(t, "OpenThumbnail")
+}
+
+func TestGetThumbnail_NotAnImage(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	mockUC.On("GetThumbnail", mock.Anything, "company-123", "user-123", "123e4567-e89b-12d3-a456-426614174000", domain.RenditionMedium).
+		Return(nil, pkgErrors.InvalidFileType("thumbnails are only available for JPEG, PNG and GIF images"))
+
+	c, w := newThumbnailContext("", nil)
+	handler.GetThumbnail(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "OpenThumbnail")
+}
+
+func TestGetScanFindings_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	file := createTestFile()
+	file.ScanStatus = domain.ScanStatusInfected
+	detail := "Eicar-Test-Signature"
+	mockUC.On("GetScanFindings", mock.Anything, "company-123", domain.ScanStatus("")).
+		Return([]*domain.ScanFinding{{File: file, Detail: &detail}}, nil)
+
+	req := httptest.NewRequest("GET", "/scans", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+
+	handler.GetScanFindings(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseScanFindings
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, domain.ScanStatusInfected, response.Findings[0].File.ScanStatus)
+	assert.Equal(t, detail, *response.Findings[0].Detail)
+}
+
+func TestGetScanFindings_InvalidStatus(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	req := httptest.NewRequest("GET", "/scans?status=clean", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+
+	handler.GetScanFindings(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "GetScanFindings")
+}
+
+func TestRescanFile_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	expectedFile := createTestFile()
+	expectedFile.ScanStatus = domain.ScanStatusPending
+	mockUC.On("RescanFile", mock.Anything, "company-123", fileID).Return(expectedFile, nil)
+
+	req := httptest.NewRequest("POST", "/scans/"+fileID+"/rescan", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}}
+
+	handler.RescanFile(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestReleaseFile_Conflict(t *testing.T) {
+	mockUC := new(mockUseCaseFileFolder)
+	handler := NewHandlerFileFolder(mockUC)
+
+	fileID := "123e4567-e89b-12d3-a456-426614174000"
+	mockUC.On("ReleaseFile", mock.Anything, "company-123", "user-123", fileID).
+		Return(nil, pkgErrors.Conflict("file content changed during the review"))
+
+	req := httptest.NewRequest("POST", "/scans/"+fileID+"/release", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	c.Params = []gin.Param{{Key: "id", Value: fileID}}
+
+	handler.ReleaseFile(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUC.AssertExpectations(t)
 }
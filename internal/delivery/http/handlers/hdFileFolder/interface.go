@@ -9,28 +9,57 @@ import (
 type UseCaseFileFolder interface {
 	// Folder operations
 	CreateFolder(ctx context.Context, folder *domain.File) (*domain.File, error)
-	GetFolderContents(ctx context.Context, companyID string, path *domain.Path, fileType *domain.FileType) ([]*domain.File, error)
-	MoveFolder(ctx context.Context, companyID string, folderPath *domain.Path, newPath *domain.Path) (*domain.Path, error)
-	DeleteFolder(ctx context.Context, companyID string, folderPath *domain.Path) error
+	ListFolder(ctx context.Context, companyID, userID string, query *domain.FolderListQuery) (*domain.FolderPage, error)
+	MoveFolder(ctx context.Context, companyID, userID string, folderPath *domain.Path, newPath *domain.Path) (*domain.Path, error)
+	DeleteFolder(ctx context.Context, companyID, userID string, folderPath *domain.Path) error
+	DeleteFolderRecursive(ctx context.Context, companyID, userID string, folderPath *domain.Path, permanent bool) (*domain.FolderDeleteJob, error)
+	GetFolderDeleteJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderDeleteJob, error)
+	CancelFolderDeleteJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderDeleteJob, error)
+	CopyFolder(ctx context.Context, companyID, userID string, folderPath, parentPath *domain.Path, name string, policy domain.ConflictPolicy) (*domain.FolderCopyJob, error)
+	GetFolderCopyJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderCopyJob, error)
+	CancelFolderCopyJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderCopyJob, error)
+	PrepareFolderArchive(ctx context.Context, companyID, userID string, folderPath *domain.Path, format domain.ArchiveFormat) (*domain.FolderArchive, error)
+	WriteFolderArchive(ctx context.Context, archive *domain.FolderArchive, w io.Writer) error
 
 	// File operations
-	UploadFile(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader) (*domain.File, error)
-	DownloadFile(ctx context.Context, companyID, fileID string) (io.ReadCloser, *domain.File, error)
-	GetFileInfo(ctx context.Context, companyID, fileID string) (*domain.File, error)
-	RenameFile(ctx context.Context, companyID, fileID, newName string) (*domain.File, error)
-	MoveFile(ctx context.Context, companyID, fileID string, newParentPath *domain.Path) (*domain.File, error)
-	DeleteFile(ctx context.Context, companyID, fileID string) error
+	UploadFile(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader, checksum string) (*domain.File, error)
+	StatFile(ctx context.Context, companyID, userID, fileID string) (*domain.File, error)
+	OpenFile(ctx context.Context, file *domain.File, byteRange *domain.ByteRange) (io.ReadCloser, error)
+	GetFileInfo(ctx context.Context, companyID, userID, fileID string) (*domain.File, error)
+	RenameFile(ctx context.Context, companyID, userID, fileID, newName string) (*domain.File, error)
+	MoveFile(ctx context.Context, companyID, userID, fileID string, newParentPath *domain.Path) (*domain.File, error)
+	CopyFile(ctx context.Context, companyID, userID, fileID string, parentPath *domain.Path, name string, policy domain.ConflictPolicy) (*domain.File, error)
+	DeleteFile(ctx context.Context, companyID, userID, fileID string) error
+	GetThumbnail(ctx context.Context, companyID, userID, fileID string, size domain.RenditionSize) (*domain.FileRendition, error)
+	OpenThumbnail(ctx context.Context, rendition *domain.FileRendition) (io.ReadCloser, error)
+
+	// File version operations
+	UploadFileVersion(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader, checksum string) (*domain.File, error)
+	GetFileVersions(ctx context.Context, companyID, userID, fileID string) ([]*domain.FileVersion, error)
+	DownloadFileVersion(ctx context.Context, companyID, userID, fileID string, version int) (io.ReadCloser, *domain.File, error)
+	RestoreFileVersion(ctx context.Context, companyID, userID, fileID string, version int) (*domain.File, error)
+
+	// Presigned URL operations
+	GetDownloadURL(ctx context.Context, companyID, userID, fileID string) (*domain.PresignedURL, error)
+	InitPresignedUpload(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, checksum string) (*domain.PresignedUpload, error)
+	FinalizePresignedUpload(ctx context.Context, companyID, userID, uploadID string) (*domain.File, error)
 
 	// Upload strategy
 	GetUploadStrategy(ctx context.Context, fileSize int64) (*domain.StrategyInfo, error)
 
 	// Chunked upload operations
 	InitChunkedUpload(ctx context.Context, companyID, userID, filename string, fileSize int64, parentPath *domain.Path, mimeType string) (*domain.ChunkedUpload, error)
-	UploadChunk(ctx context.Context, companyID, uploadID string, chunkIndex int, chunkData io.Reader, chunkSize int64) (*domain.ChunkedUpload, error)
-	GetChunkedUploadStatus(ctx context.Context, companyID, uploadID string) (*domain.ChunkedUpload, error)
-	CompleteChunkedUpload(ctx context.Context, companyID, uploadID string) (*domain.File, error)
-	AbortChunkedUpload(ctx context.Context, companyID, uploadID string) error
+	UploadChunk(ctx context.Context, companyID, userID, uploadID string, chunkIndex int, chunkData io.Reader, chunkSize int64, checksum string) (*domain.ChunkedUpload, error)
+	GetChunkedUploadStatus(ctx context.Context, companyID, userID, uploadID string) (*domain.ChunkedUpload, error)
+	CompleteChunkedUpload(ctx context.Context, companyID, userID, uploadID, checksum string) (*domain.File, error)
+	AbortChunkedUpload(ctx context.Context, companyID, userID, uploadID string) error
 
 	// Resource monitoring
 	GetResourceStats(ctx context.Context) (*domain.ResourceStats, error)
+	GetJanitorStats(ctx context.Context) (*domain.JanitorStats, error)
+
+	// Malware scanning
+	GetScanFindings(ctx context.Context, companyID string, status domain.ScanStatus) ([]*domain.ScanFinding, error)
+	RescanFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
+	ReleaseFile(ctx context.Context, companyID, userID, fileID string) (*domain.File, error)
 }
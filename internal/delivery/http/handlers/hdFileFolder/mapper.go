@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go-storage/internal/domain"
 	"go-storage/internal/utils/valid"
+	"net/http"
 	"time"
 )
 
@@ -24,22 +25,58 @@ func ToDomainCreateFolder(dto *RequestCreateFolder) (*domain.File, error) {
 	}, nil
 }
 
-func ToDomainGetFolder(dto *RequestGetFolder) (*domain.Path, *domain.FileType, error) {
+// ToDomainGetFolder builds the listing query, folders first and by name unless
+// another sort is requested.
+func ToDomainGetFolder(dto *RequestGetFolder) (*domain.FolderListQuery, error) {
 	path, err := domain.NewPath(dto.Path)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	if dto.Type != "" && !dto.Type.IsValid() {
-		return nil, nil, fmt.Errorf("invalid file type")
+		return nil, fmt.Errorf("invalid file type")
+	}
+
+	query := &domain.FolderListQuery{
+		Path:      path,
+		Recursive: dto.Recursive,
+		FileFilter: domain.FileFilter{
+			MimeType:       dto.MimeType,
+			MinSize:        dto.MinSize,
+			MaxSize:        dto.MaxSize,
+			ModifiedAfter:  dto.ModifiedAfter,
+			ModifiedBefore: dto.ModifiedBefore,
+			Metadata:       dto.Metadata,
+		},
+		Sort:  domain.FolderSortType,
+		Order: domain.SortOrderAsc,
+		Limit: dto.Limit,
 	}
 
-	var fileTypePtr *domain.FileType
 	if dto.Type != "" {
-		fileTypePtr = &dto.Type
+		query.Type = &dto.Type
+	}
+	if len(dto.Tags) > 0 {
+		query.Tags, err = domain.NormalizeTags(dto.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dto.Sort != "" {
+		query.Sort = domain.FolderSort(dto.Sort)
+	}
+	if dto.Order != "" {
+		query.Order = domain.SortOrder(dto.Order)
 	}
 
-	return &path, fileTypePtr, nil
+	if dto.Cursor != "" {
+		query.Cursor, err = domain.DecodeFolderCursor(dto.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
 }
 
 func ToResponseFolder(dto *domain.File) *ResponseFolder {
@@ -50,16 +87,17 @@ func ToResponseFolder(dto *domain.File) *ResponseFolder {
 	}
 }
 
-func ToResponseFolders(dto []*domain.File) *ResponseGetFolder {
-	var answer = make([]*FolderFileDTO, len(dto))
-	for index, value := range dto {
+func ToResponseFolders(page *domain.FolderPage) *ResponseGetFolder {
+	var answer = make([]*FolderFileDTO, len(page.Items))
+	for index, value := range page.Items {
 		answer[index] = DtoFileToFolder(value)
 	}
 
 	return &ResponseGetFolder{
-		Status: "success",
-		Time:   time.Now(),
-		Files:  answer,
+		Status:     "success",
+		Time:       time.Now(),
+		Files:      answer,
+		NextCursor: page.NextCursor,
 	}
 }
 
@@ -72,7 +110,7 @@ func ToResponsePath(path *domain.Path) *ResponsePath {
 }
 
 func DtoFileToFolder(dto *domain.File) *FolderFileDTO {
-	return &FolderFileDTO{
+	result := &FolderFileDTO{
 		ID:           dto.ID,
 		Name:         dto.Name,
 		Type:         dto.Type,
@@ -83,11 +121,19 @@ func DtoFileToFolder(dto *domain.File) *FolderFileDTO {
 
 		MimeType:    dto.MimeType,
 		Size:        dto.Size,
+		Hash:        dto.Hash,
 		StoragePath: dto.StoragePath,
 
 		CreatedAt: dto.CreatedAt,
 		UpdatedAt: dto.UpdatedAt,
 	}
+
+	if dto.IsFile() {
+		result.ScanStatus = dto.ScanStatus
+		result.StorageClass = dto.StorageClass
+	}
+
+	return result
 }
 
 func ToResponseFile(file *domain.File) *ResponseFile {
@@ -102,6 +148,32 @@ func ToFileDTO(file *domain.File) *FolderFileDTO {
 	return DtoFileToFolder(file)
 }
 
+func DtoFileVersion(version *domain.FileVersion) *FileVersionDTO {
+	return &FileVersionDTO{
+		ID:           version.ID,
+		FileID:       version.FileID,
+		Version:      version.Version,
+		UserCreateID: version.UserCreateID,
+		MimeType:     version.MimeType,
+		Size:         version.Size,
+		Hash:         version.Hash,
+		CreatedAt:    version.CreatedAt,
+	}
+}
+
+func ToResponseFileVersions(versions []*domain.FileVersion) *ResponseFileVersions {
+	var answer = make([]*FileVersionDTO, len(versions))
+	for index, value := range versions {
+		answer[index] = DtoFileVersion(value)
+	}
+
+	return &ResponseFileVersions{
+		Status:   "success",
+		Time:     time.Now(),
+		Versions: answer,
+	}
+}
+
 func ToResponseUploadStrategy(strategy *domain.StrategyInfo) *ResponseUploadStrategy {
 	return &ResponseUploadStrategy{
 		Status:   "success",
@@ -121,6 +193,27 @@ func ToResponseInitChunkedUpload(chunkedUpload *domain.ChunkedUpload, strategy *
 	}
 }
 
+func ToResponseDownloadURL(presigned *domain.PresignedURL) *ResponseDownloadURL {
+	return &ResponseDownloadURL{
+		Status:    "success",
+		Time:      time.Now(),
+		URL:       presigned.URL,
+		ExpiresAt: presigned.ExpiresAt,
+	}
+}
+
+func ToResponseInitPresignedUpload(upload *domain.PresignedUpload) *ResponseInitPresignedUpload {
+	return &ResponseInitPresignedUpload{
+		Status:    "success",
+		Time:      time.Now(),
+		UploadID:  upload.ID,
+		URL:       upload.URL,
+		Method:    http.MethodPut,
+		Headers:   upload.Headers,
+		ExpiresAt: upload.ExpiresAt,
+	}
+}
+
 func ToResponseUploadChunk(chunkedUpload *domain.ChunkedUpload, chunkIndex int) *ResponseUploadChunk {
 	return &ResponseUploadChunk{
 		Status:         "success",
@@ -172,3 +265,74 @@ func ToResponseResourceStats(stats *domain.ResourceStats) map[string]interface{}
 		"stats":  stats,
 	}
 }
+
+func ToResponseJanitorStats(stats *domain.JanitorStats) *ResponseJanitorStats {
+	return &ResponseJanitorStats{
+		Status: "success",
+		Time:   time.Now(),
+		Stats:  stats,
+	}
+}
+
+func ToResponseScanFindings(findings []*domain.ScanFinding) *ResponseScanFindings {
+	dtos := make([]*ScanFindingDTO, 0, len(findings))
+	for _, finding := range findings {
+		dtos = append(dtos, &ScanFindingDTO{
+			File:      DtoFileToFolder(finding.File),
+			InTrash:   !finding.File.IsActive,
+			Detail:    finding.Detail,
+			ScannedAt: finding.ScannedAt,
+		})
+	}
+
+	return &ResponseScanFindings{
+		Status:   "success",
+		Time:     time.Now(),
+		Findings: dtos,
+		Total:    len(dtos),
+	}
+}
+
+func ToResponseFolderDeleteJob(job *domain.FolderDeleteJob) *ResponseFolderDeleteJob {
+	return &ResponseFolderDeleteJob{
+		Status:       "success",
+		Time:         time.Now(),
+		JobID:        job.ID,
+		FolderPath:   job.FolderPath.String(),
+		Permanent:    job.Permanent,
+		JobStatus:    job.Status,
+		Progress:     job.Progress(),
+		TotalItems:   job.TotalItems,
+		DeletedItems: job.DeletedItems,
+		PurgedItems:  job.PurgedItems,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
+
+func ToResponseFolderCopyJob(job *domain.FolderCopyJob) *ResponseFolderCopyJob {
+	return &ResponseFolderCopyJob{
+		Status:      "success",
+		Time:        time.Now(),
+		JobID:       job.ID,
+		SourcePath:  job.SourcePath.String(),
+		TargetPath:  job.TargetPath.String(),
+		TargetID:    job.TargetID,
+		JobStatus:   job.Status,
+		Progress:    job.Progress(),
+		TotalItems:  job.TotalItems,
+		CopiedItems: job.CopiedItems,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		FinishedAt:  job.FinishedAt,
+	}
+}
+
+// conflictPolicy maps the onConflict field of a copy request, fail by default.
+func conflictPolicy(value string) domain.ConflictPolicy {
+	if value == "" {
+		return domain.ConflictPolicyFail
+	}
+	return domain.ConflictPolicy(value)
+}
@@ -0,0 +1,227 @@
+package hdFileFolder
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/internal/domain"
+)
+
+// maxRanges bounds the number of parts served for a single multi-range request.
+const maxRanges = 32
+
+// errRangeNotSatisfiable means the Range header is valid but none of its ranges
+// overlap the content, which is answered with 416.
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// fileETag returns the strong entity tag of a file, or an empty string for
+// files uploaded before content hashes were recorded.
+func fileETag(file *domain.File) string {
+	if file.Hash == nil || *file.Hash == "" {
+		return ""
+	}
+	return strconv.Quote(*file.Hash)
+}
+
+// checkNotModified evaluates If-None-Match and If-Modified-Since and reports
+// whether the client copy is still current (RFC 7232, section 6).
+func checkNotModified(r *http.Request, etag string, modTime time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagListMatches(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modTime.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	return !modTime.Truncate(time.Second).After(since)
+}
+
+// rangeAllowed evaluates If-Range: a Range header is only honoured when the
+// validator still identifies the current representation.
+func rangeAllowed(r *http.Request, etag string, modTime time.Time) bool {
+	ir := r.Header.Get("If-Range")
+	if ir == "" {
+		return true
+	}
+
+	if strings.HasPrefix(ir, `"`) || strings.HasPrefix(ir, "W/") {
+		return etag != "" && ir == etag
+	}
+
+	date, err := http.ParseTime(ir)
+	if err != nil || modTime.IsZero() {
+		return false
+	}
+
+	return modTime.Truncate(time.Second).Equal(date)
+}
+
+// etagListMatches weakly compares a comma separated If-None-Match list with etag.
+func etagListMatches(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = textproto.TrimString(candidate)
+		if candidate == "*" {
+			return etag != ""
+		}
+		if etag != "" && strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRange parses a Range header against a representation of the given size
+// (RFC 7233, section 2.1). A nil result without error means the header should
+// be ignored and the whole content served.
+func parseRange(header string, size int64) ([]domain.ByteRange, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	unit, specs, found := strings.Cut(header, "=")
+	if !found || strings.TrimSpace(unit) != "bytes" {
+		return nil, nil
+	}
+
+	var ranges []domain.ByteRange
+	var total int64
+	for _, spec := range strings.Split(specs, ",") {
+		spec = textproto.TrimString(spec)
+		if spec == "" {
+			continue
+		}
+
+		first, last, found := strings.Cut(spec, "-")
+		if !found {
+			return nil, nil
+		}
+		first, last = textproto.TrimString(first), textproto.TrimString(last)
+
+		var r domain.ByteRange
+		if first == "" {
+			// Suffix range: the final N bytes.
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, nil
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r = domain.ByteRange{Start: size - n, End: size - 1}
+		} else {
+			start, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || start < 0 {
+				return nil, nil
+			}
+
+			end := size - 1
+			if last != "" {
+				end, err = strconv.ParseInt(last, 10, 64)
+				if err != nil || end < start {
+					return nil, nil
+				}
+			}
+
+			if start >= size {
+				continue
+			}
+			if end >= size {
+				end = size - 1
+			}
+			r = domain.ByteRange{Start: start, End: end}
+		}
+
+		ranges = append(ranges, r)
+		total += r.Length()
+	}
+
+	if len(ranges) == 0 {
+		return nil, errRangeNotSatisfiable
+	}
+
+	// Like net/http, fall back to the full content for abusive or pointless
+	// requests whose parts add up to more than the whole representation.
+	if len(ranges) > maxRanges || total > size {
+		return nil, nil
+	}
+
+	return ranges, nil
+}
+
+// setValidators writes the caching validators shared by full and partial responses.
+func setValidators(ctx *gin.Context, etag string, modTime time.Time) {
+	ctx.Header("Accept-Ranges", "bytes")
+	if etag != "" {
+		ctx.Header("ETag", etag)
+	}
+	if !modTime.IsZero() {
+		ctx.Header("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+}
+
+// streamRanges writes a 206 response for one or more ranges. Single ranges are
+// sent as is, several ranges as multipart/byteranges.
+func streamRanges(ctx *gin.Context, open func(domain.ByteRange) (io.ReadCloser, error), fileInfo *domain.File, ranges []domain.ByteRange, inline bool) error {
+	size := *fileInfo.Size
+	ctx.Header("Content-Disposition", contentDisposition(fileInfo, inline))
+
+	if len(ranges) == 1 {
+		r := ranges[0]
+		reader, err := open(r)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		ctx.Header("Content-Type", *fileInfo.MimeType)
+		ctx.Header("Content-Range", r.ContentRange(size))
+		ctx.Header("Content-Length", strconv.FormatInt(r.Length(), 10))
+		ctx.Status(http.StatusPartialContent)
+
+		_, err = io.Copy(ctx.Writer, reader)
+		return err
+	}
+
+	mw := multipart.NewWriter(ctx.Writer)
+	ctx.Header("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	ctx.Status(http.StatusPartialContent)
+
+	for _, r := range ranges {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":  {*fileInfo.MimeType},
+			"Content-Range": {r.ContentRange(size)},
+		})
+		if err != nil {
+			return err
+		}
+
+		reader, err := open(r)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(part, reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
@@ -0,0 +1,58 @@
+package hdFileMetadata
+
+import "time"
+
+type RequestReplaceMetadata struct {
+	Tags     []string          `json:"tags"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// RequestUpdateMetadata changes part of the metadata. A metadata key set to
+// null is removed, keys not mentioned are kept.
+type RequestUpdateMetadata struct {
+	AddTags    []string           `json:"add_tags"`
+	RemoveTags []string           `json:"remove_tags"`
+	Metadata   map[string]*string `json:"metadata"`
+}
+
+type RequestBulkTags struct {
+	IDs    []string `json:"ids" binding:"required,min=1,max=1000,dive,uuid"`
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+type MetadataDTO struct {
+	FileID    string            `json:"file_id"`
+	Tags      []string          `json:"tags"`
+	Metadata  map[string]string `json:"metadata"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+}
+
+type ResponseMetadata struct {
+	Status   string       `json:"status"`
+	Time     time.Time    `json:"time"`
+	Metadata *MetadataDTO `json:"metadata"`
+}
+
+type ResponseBulkTags struct {
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Updated int       `json:"updated"`
+}
+
+type TagDTO struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type ResponseTags struct {
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+	Tags   []*TagDTO `json:"tags"`
+}
+
+type ResponseSuccess struct {
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
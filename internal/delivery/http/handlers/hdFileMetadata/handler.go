@@ -0,0 +1,292 @@
+package hdFileMetadata
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerFileMetadata struct {
+	userCase UseCaseFileMetadata
+}
+
+func NewHandlerFileMetadata(useCase UseCaseFileMetadata) *HandlerFileMetadata {
+	return &HandlerFileMetadata{
+		userCase: useCase,
+	}
+}
+
+// GetMetadata
+// @Summary      Get tags and metadata
+// @Description  Returns the tags and custom key/value metadata of a file or folder
+// @Tags         metadata
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id       path      string  true  "File or folder ID"
+// @Success      200      {object}  ResponseMetadata
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/metadata [get]
+func (h *HandlerFileMetadata) GetMetadata(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetMetadata: Company ID is required", "func", "GetMetadata", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func GetMetadata: User ID is required", "func", "GetMetadata", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	fileID := ctx.Param("id")
+	if fileID == "" {
+		log.Error("func GetMetadata: File ID is required", "func", "GetMetadata", "err", "empty file ID")
+		errors.HandleError(ctx, errors.BadRequest("File ID is required"))
+		return
+	}
+
+	metadata, errUc := h.userCase.GetMetadata(ctx, companyID, userID, fileID)
+	if errUc != nil {
+		log.Error("func GetMetadata: Error work UseCase/Repository", "func", "GetMetadata", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseMetadata(metadata))
+}
+
+// ReplaceMetadata
+// @Summary      Replace tags and metadata
+// @Description  Sets the tags and key/value metadata of a file or folder, dropping the previous ones. Tags are lowercased, at most 50 tags and 50 keys per item
+// @Tags         metadata
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "File or folder ID"
+// @Param        request  body      RequestReplaceMetadata  true  "Tags and metadata"
+// @Success      200      {object}  ResponseMetadata
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/metadata [put]
+func (h *HandlerFileMetadata) ReplaceMetadata(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func ReplaceMetadata: Company ID is required", "func", "ReplaceMetadata", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func ReplaceMetadata: User ID is required", "func", "ReplaceMetadata", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	fileID := ctx.Param("id")
+	if fileID == "" {
+		log.Error("func ReplaceMetadata: File ID is required", "func", "ReplaceMetadata", "err", "empty file ID")
+		errors.HandleError(ctx, errors.BadRequest("File ID is required"))
+		return
+	}
+
+	var inputData RequestReplaceMetadata
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func ReplaceMetadata: Error in parse input param", "func", "ReplaceMetadata", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	metadata, errUc := h.userCase.ReplaceMetadata(ctx, companyID, userID, fileID, ToDomainMetadata(&inputData))
+	if errUc != nil {
+		log.Error("func ReplaceMetadata: Error work UseCase/Repository", "func", "ReplaceMetadata", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseMetadata(metadata))
+}
+
+// UpdateMetadata
+// @Summary      Update tags and metadata
+// @Description  Adds and removes tags and sets metadata keys, a key set to null is removed. Everything else is kept
+// @Tags         metadata
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true  "File or folder ID"
+// @Param        request  body      RequestUpdateMetadata  true  "Changes"
+// @Success      200      {object}  ResponseMetadata
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/metadata [patch]
+func (h *HandlerFileMetadata) UpdateMetadata(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func UpdateMetadata: Company ID is required", "func", "UpdateMetadata", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func UpdateMetadata: User ID is required", "func", "UpdateMetadata", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	fileID := ctx.Param("id")
+	if fileID == "" {
+		log.Error("func UpdateMetadata: File ID is required", "func", "UpdateMetadata", "err", "empty file ID")
+		errors.HandleError(ctx, errors.BadRequest("File ID is required"))
+		return
+	}
+
+	var inputData RequestUpdateMetadata
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func UpdateMetadata: Error in parse input param", "func", "UpdateMetadata", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	metadata, errUc := h.userCase.UpdateMetadata(ctx, companyID, userID, fileID, ToDomainMetadataPatch(&inputData))
+	if errUc != nil {
+		log.Error("func UpdateMetadata: Error work UseCase/Repository", "func", "UpdateMetadata", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseMetadata(metadata))
+}
+
+// DeleteMetadata
+// @Summary      Clear tags and metadata
+// @Description  Removes all tags and metadata of a file or folder
+// @Tags         metadata
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id       path      string  true  "File or folder ID"
+// @Success      200      {object}  ResponseSuccess
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/metadata [delete]
+func (h *HandlerFileMetadata) DeleteMetadata(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func DeleteMetadata: Company ID is required", "func", "DeleteMetadata", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func DeleteMetadata: User ID is required", "func", "DeleteMetadata", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	fileID := ctx.Param("id")
+	if fileID == "" {
+		log.Error("func DeleteMetadata: File ID is required", "func", "DeleteMetadata", "err", "empty file ID")
+		errors.HandleError(ctx, errors.BadRequest("File ID is required"))
+		return
+	}
+
+	if errUc := h.userCase.DeleteMetadata(ctx, companyID, userID, fileID); errUc != nil {
+		log.Error("func DeleteMetadata: Error work UseCase/Repository", "func", "DeleteMetadata", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSuccess("Metadata deleted successfully"))
+}
+
+// BulkUpdateTags
+// @Summary      Tag several items
+// @Description  Adds and removes tags on up to 1000 files and folders at once. Unknown IDs, items the user may not edit and items that would exceed 50 tags are skipped, updated tells how many were changed
+// @Tags         metadata
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RequestBulkTags  true  "Items and tags"
+// @Success      200      {object}  ResponseBulkTags
+// @Failure      400,500  {object}  errors.ErrorResponse
+// @Failure      401,403  {object}  errors.ErrorResponse
+// @Router       /files/tags/bulk [post]
+func (h *HandlerFileMetadata) BulkUpdateTags(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func BulkUpdateTags: Company ID is required", "func", "BulkUpdateTags", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func BulkUpdateTags: User ID is required", "func", "BulkUpdateTags", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var inputData RequestBulkTags
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func BulkUpdateTags: Error in parse input param", "func", "BulkUpdateTags", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	updated, errUc := h.userCase.BulkUpdateTags(ctx, companyID, userID, inputData.IDs, inputData.Add, inputData.Remove)
+	if errUc != nil {
+		log.Error("func BulkUpdateTags: Error work UseCase/Repository", "func", "BulkUpdateTags", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseBulkTags(updated))
+}
+
+// ListTags
+// @Summary      List tags
+// @Description  Returns the tags used in the company with the number of items carrying each, most used first
+// @Tags         metadata
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200      {object}  ResponseTags
+// @Failure      400,500  {object}  errors.ErrorResponse
+// @Failure      401,403  {object}  errors.ErrorResponse
+// @Router       /files/tags [get]
+func (h *HandlerFileMetadata) ListTags(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func ListTags: Company ID is required", "func", "ListTags", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	tags, errUc := h.userCase.ListTags(ctx, companyID)
+	if errUc != nil {
+		log.Error("func ListTags: Error work UseCase/Repository", "func", "ListTags", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseTags(tags))
+}
@@ -0,0 +1,203 @@
+package hdFileMetadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseFileMetadata struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseFileMetadata) GetMetadata(ctx context.Context, companyID, userID, fileID string) (*domain.FileMetadata, error) {
+	args := m.Called(ctx, companyID, userID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileMetadata), args.Error(1)
+}
+
+func (m *mockUseCaseFileMetadata) ReplaceMetadata(ctx context.Context, companyID, userID, fileID string, metadata *domain.FileMetadata) (*domain.FileMetadata, error) {
+	args := m.Called(ctx, companyID, userID, fileID, metadata)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileMetadata), args.Error(1)
+}
+
+func (m *mockUseCaseFileMetadata) UpdateMetadata(ctx context.Context, companyID, userID, fileID string, patch *domain.MetadataPatch) (*domain.FileMetadata, error) {
+	args := m.Called(ctx, companyID, userID, fileID, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileMetadata), args.Error(1)
+}
+
+func (m *mockUseCaseFileMetadata) DeleteMetadata(ctx context.Context, companyID, userID, fileID string) error {
+	args := m.Called(ctx, companyID, userID, fileID)
+	return args.Error(0)
+}
+
+func (m *mockUseCaseFileMetadata) BulkUpdateTags(ctx context.Context, companyID, userID string, fileIDs, add, remove []string) (int, error) {
+	args := m.Called(ctx, companyID, userID, fileIDs, add, remove)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockUseCaseFileMetadata) ListTags(ctx context.Context, companyID string) ([]*domain.TagCount, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TagCount), args.Error(1)
+}
+
+func createTestContext(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func TestGetMetadata_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	mockUC.On("GetMetadata", mock.Anything, "company-123", "user-123", "file-id").Return(&domain.FileMetadata{
+		FileID:   "file-id",
+		Tags:     []string{"q3"},
+		Metadata: map[string]string{"project": "PRJ-42"},
+	}, nil)
+
+	c, w := createTestContext("GET", "/files/file-id/metadata", nil)
+	c.Params = gin.Params{{Key: "id", Value: "file-id"}}
+	handler.GetMetadata(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponseMetadata
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"q3"}, response.Metadata.Tags)
+	assert.Equal(t, "PRJ-42", response.Metadata.Metadata["project"])
+	assert.Nil(t, response.Metadata.UpdatedAt)
+}
+
+func TestGetMetadata_NotFound(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	mockUC.On("GetMetadata", mock.Anything, "company-123", "user-123", "missing").Return(nil, errors.NotFound("file not found"))
+
+	c, w := createTestContext("GET", "/files/missing/metadata", nil)
+	c.Params = gin.Params{{Key: "id", Value: "missing"}}
+	handler.GetMetadata(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestReplaceMetadata_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	mockUC.On("ReplaceMetadata", mock.Anything, "company-123", "user-123", "file-id", mock.MatchedBy(func(m *domain.FileMetadata) bool {
+		return len(m.Tags) == 2 && m.Metadata["status"] == "draft"
+	})).Return(&domain.FileMetadata{FileID: "file-id", Tags: []string{"q3", "review"}, Metadata: map[string]string{"status": "draft"}}, nil)
+
+	body := []byte(`{"tags":["Q3","review"],"metadata":{"status":"draft"}}`)
+	c, w := createTestContext("PUT", "/files/file-id/metadata", body)
+	c.Params = gin.Params{{Key: "id", Value: "file-id"}}
+	handler.ReplaceMetadata(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestReplaceMetadata_InvalidJSON(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	body := []byte(`{"metadata":{"count":3}}`)
+	c, w := createTestContext("PUT", "/files/file-id/metadata", body)
+	c.Params = gin.Params{{Key: "id", Value: "file-id"}}
+	handler.ReplaceMetadata(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "ReplaceMetadata", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateMetadata_NullRemovesKey(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	mockUC.On("UpdateMetadata", mock.Anything, "company-123", "user-123", "file-id", mock.MatchedBy(func(patch *domain.MetadataPatch) bool {
+		value, ok := patch.Metadata["owner"]
+		return ok && value == nil && *patch.Metadata["status"] == "final" && patch.RemoveTags[0] == "draft"
+	})).Return(&domain.FileMetadata{FileID: "file-id", Tags: []string{}, Metadata: map[string]string{"status": "final"}}, nil)
+
+	body := []byte(`{"remove_tags":["draft"],"metadata":{"status":"final","owner":null}}`)
+	c, w := createTestContext("PATCH", "/files/file-id/metadata", body)
+	c.Params = gin.Params{{Key: "id", Value: "file-id"}}
+	handler.UpdateMetadata(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestBulkUpdateTags_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	ids := []string{"9b2f3c1e-8d4a-4b6f-9a1e-2c3d4e5f6a7b", "1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"}
+	mockUC.On("BulkUpdateTags", mock.Anything, "company-123", "user-123", ids, []string{"q3"}, []string(nil)).Return(2, nil)
+
+	body, _ := json.Marshal(map[string]any{"ids": ids, "add": []string{"q3"}})
+	c, w := createTestContext("POST", "/files/tags/bulk", body)
+	handler.BulkUpdateTags(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponseBulkTags
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Updated)
+}
+
+func TestBulkUpdateTags_InvalidID(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	body := []byte(`{"ids":["not-a-uuid"],"add":["q3"]}`)
+	c, w := createTestContext("POST", "/files/tags/bulk", body)
+	handler.BulkUpdateTags(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "BulkUpdateTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListTags_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileMetadata)
+	handler := NewHandlerFileMetadata(mockUC)
+
+	mockUC.On("ListTags", mock.Anything, "company-123").Return([]*domain.TagCount{{Tag: "q3", Count: 12}}, nil)
+
+	c, w := createTestContext("GET", "/files/tags", nil)
+	handler.ListTags(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponseTags
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []*TagDTO{{Tag: "q3", Count: 12}}, response.Tags)
+}
@@ -0,0 +1,15 @@
+package hdFileMetadata
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type UseCaseFileMetadata interface {
+	GetMetadata(ctx context.Context, companyID, userID, fileID string) (*domain.FileMetadata, error)
+	ReplaceMetadata(ctx context.Context, companyID, userID, fileID string, metadata *domain.FileMetadata) (*domain.FileMetadata, error)
+	UpdateMetadata(ctx context.Context, companyID, userID, fileID string, patch *domain.MetadataPatch) (*domain.FileMetadata, error)
+	DeleteMetadata(ctx context.Context, companyID, userID, fileID string) error
+	BulkUpdateTags(ctx context.Context, companyID, userID string, fileIDs, add, remove []string) (int, error)
+	ListTags(ctx context.Context, companyID string) ([]*domain.TagCount, error)
+}
@@ -0,0 +1,72 @@
+package hdFileMetadata
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func ToDomainMetadata(dto *RequestReplaceMetadata) *domain.FileMetadata {
+	return &domain.FileMetadata{
+		Tags:     dto.Tags,
+		Metadata: dto.Metadata,
+	}
+}
+
+func ToDomainMetadataPatch(dto *RequestUpdateMetadata) *domain.MetadataPatch {
+	return &domain.MetadataPatch{
+		AddTags:    dto.AddTags,
+		RemoveTags: dto.RemoveTags,
+		Metadata:   dto.Metadata,
+	}
+}
+
+func DtoMetadata(metadata *domain.FileMetadata) *MetadataDTO {
+	dto := &MetadataDTO{
+		FileID:   metadata.FileID,
+		Tags:     metadata.Tags,
+		Metadata: metadata.Metadata,
+	}
+	// Items that were never tagged have no update time.
+	if !metadata.UpdatedAt.IsZero() {
+		dto.UpdatedAt = &metadata.UpdatedAt
+	}
+
+	return dto
+}
+
+func ToResponseMetadata(metadata *domain.FileMetadata) *ResponseMetadata {
+	return &ResponseMetadata{
+		Status:   "success",
+		Time:     time.Now(),
+		Metadata: DtoMetadata(metadata),
+	}
+}
+
+func ToResponseBulkTags(updated int) *ResponseBulkTags {
+	return &ResponseBulkTags{
+		Status:  "success",
+		Time:    time.Now(),
+		Updated: updated,
+	}
+}
+
+func ToResponseTags(tags []*domain.TagCount) *ResponseTags {
+	var answer = make([]*TagDTO, len(tags))
+	for index, value := range tags {
+		answer[index] = &TagDTO{Tag: value.Tag, Count: value.Count}
+	}
+
+	return &ResponseTags{
+		Status: "success",
+		Time:   time.Now(),
+		Tags:   answer,
+	}
+}
+
+func ToResponseSuccess(message string) *ResponseSuccess {
+	return &ResponseSuccess{
+		Status:  "success",
+		Time:    time.Now(),
+		Message: message,
+	}
+}
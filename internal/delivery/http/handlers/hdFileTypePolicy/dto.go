@@ -0,0 +1,24 @@
+package hdFileTypePolicy
+
+import "time"
+
+type FileTypePolicyDTO struct {
+	AllowedMimeTypes  []string `json:"allowed_mime_types"`
+	DeniedMimeTypes   []string `json:"denied_mime_types"`
+	AllowedExtensions []string `json:"allowed_extensions"`
+	DeniedExtensions  []string `json:"denied_extensions"`
+}
+
+// RequestUpdatePolicy replaces the whole policy. Omitted lists are emptied.
+type RequestUpdatePolicy struct {
+	AllowedMimeTypes  []string `json:"allowed_mime_types"`
+	DeniedMimeTypes   []string `json:"denied_mime_types"`
+	AllowedExtensions []string `json:"allowed_extensions"`
+	DeniedExtensions  []string `json:"denied_extensions"`
+}
+
+type ResponsePolicy struct {
+	Status string             `json:"status"`
+	Time   time.Time          `json:"time"`
+	Policy *FileTypePolicyDTO `json:"policy"`
+}
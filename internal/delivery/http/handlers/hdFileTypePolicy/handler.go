@@ -0,0 +1,88 @@
+package hdFileTypePolicy
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerFileTypePolicy struct {
+	userCase UseCaseFileTypePolicy
+}
+
+func NewHandlerFileTypePolicy(useCase UseCaseFileTypePolicy) *HandlerFileTypePolicy {
+	return &HandlerFileTypePolicy{
+		userCase: useCase,
+	}
+}
+
+// GetPolicy
+// @Summary      Get file type policy
+// @Description  Returns the MIME types and extensions the company allows or denies for uploads
+// @Tags         file-types
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200          {object}  ResponsePolicy
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /file-types/policy [get]
+func (h *HandlerFileTypePolicy) GetPolicy(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func GetPolicy: Company ID is required", "func", "GetPolicy", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	policy, errUc := h.userCase.GetPolicy(ctx, companyID)
+	if errUc != nil {
+		log.Error("func GetPolicy: Error work UseCase/Repository", "func", "GetPolicy", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponsePolicy(policy))
+}
+
+// UpdatePolicy
+// @Summary      Update file type policy
+// @Description  Replaces the allow and deny lists checked on upload. MIME types may end in /* to match a family. Denied entries win over allowed ones, and empty allow lists allow everything not denied
+// @Tags         file-types
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        policy  body      RequestUpdatePolicy  true  "File type policy"
+// @Success      200          {object}  ResponsePolicy
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /file-types/policy [put]
+func (h *HandlerFileTypePolicy) UpdatePolicy(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func UpdatePolicy: Company ID is required", "func", "UpdatePolicy", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestUpdatePolicy
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func UpdatePolicy: Error in parse input param", "func", "UpdatePolicy", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	policy, errUc := h.userCase.UpdatePolicy(ctx, ToDomainPolicy(companyID, &inputData))
+	if errUc != nil {
+		log.Error("func UpdatePolicy: Error work UseCase/Repository", "func", "UpdatePolicy", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponsePolicy(policy))
+}
@@ -0,0 +1,107 @@
+package hdFileTypePolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseFileTypePolicy struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseFileTypePolicy) GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileTypePolicy), args.Error(1)
+}
+
+func (m *mockUseCaseFileTypePolicy) UpdatePolicy(ctx context.Context, policy *domain.FileTypePolicy) (*domain.FileTypePolicy, error) {
+	args := m.Called(ctx, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileTypePolicy), args.Error(1)
+}
+
+func createTestContext(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func TestGetPolicy_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileTypePolicy)
+	handler := NewHandlerFileTypePolicy(mockUC)
+
+	mockUC.On("GetPolicy", mock.Anything, "company-123").
+		Return(&domain.FileTypePolicy{CompanyID: "company-123", DeniedExtensions: []string{"exe"}}, nil)
+
+	c, w := createTestContext("GET", "/file-types/policy", nil)
+	handler.GetPolicy(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponsePolicy
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"exe"}, response.Policy.DeniedExtensions)
+	assert.Equal(t, []string{}, response.Policy.AllowedMimeTypes)
+}
+
+func TestUpdatePolicy_Success(t *testing.T) {
+	mockUC := new(mockUseCaseFileTypePolicy)
+	handler := NewHandlerFileTypePolicy(mockUC)
+
+	expected := &domain.FileTypePolicy{
+		CompanyID:        "company-123",
+		AllowedMimeTypes: []string{"image/*", "application/pdf"},
+		DeniedExtensions: []string{"exe"},
+	}
+	mockUC.On("UpdatePolicy", mock.Anything, expected).Return(expected, nil)
+
+	c, w := createTestContext("PUT", "/file-types/policy",
+		[]byte(`{"allowed_mime_types":["image/*","application/pdf"],"denied_extensions":["exe"]}`))
+	handler.UpdatePolicy(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUpdatePolicy_InvalidJSON(t *testing.T) {
+	mockUC := new(mockUseCaseFileTypePolicy)
+	handler := NewHandlerFileTypePolicy(mockUC)
+
+	c, w := createTestContext("PUT", "/file-types/policy", []byte(`{"denied_extensions":"exe"}`))
+	handler.UpdatePolicy(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "UpdatePolicy", mock.Anything, mock.Anything)
+}
+
+func TestUpdatePolicy_InvalidEntry(t *testing.T) {
+	mockUC := new(mockUseCaseFileTypePolicy)
+	handler := NewHandlerFileTypePolicy(mockUC)
+
+	mockUC.On("UpdatePolicy", mock.Anything, mock.Anything).Return(nil, errors.BadRequest(`invalid MIME type "executable"`))
+
+	c, w := createTestContext("PUT", "/file-types/policy", []byte(`{"denied_mime_types":["executable"]}`))
+	handler.UpdatePolicy(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,12 @@
+package hdFileTypePolicy
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+)
+
+type UseCaseFileTypePolicy interface {
+	GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error)
+	UpdatePolicy(ctx context.Context, policy *domain.FileTypePolicy) (*domain.FileTypePolicy, error)
+}
@@ -0,0 +1,36 @@
+package hdFileTypePolicy
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func ToDomainPolicy(companyID string, req *RequestUpdatePolicy) *domain.FileTypePolicy {
+	return &domain.FileTypePolicy{
+		CompanyID:         companyID,
+		AllowedMimeTypes:  req.AllowedMimeTypes,
+		DeniedMimeTypes:   req.DeniedMimeTypes,
+		AllowedExtensions: req.AllowedExtensions,
+		DeniedExtensions:  req.DeniedExtensions,
+	}
+}
+
+func ToResponsePolicy(policy *domain.FileTypePolicy) *ResponsePolicy {
+	return &ResponsePolicy{
+		Status: "success",
+		Time:   time.Now(),
+		Policy: &FileTypePolicyDTO{
+			AllowedMimeTypes:  nonNil(policy.AllowedMimeTypes),
+			DeniedMimeTypes:   nonNil(policy.DeniedMimeTypes),
+			AllowedExtensions: nonNil(policy.AllowedExtensions),
+			DeniedExtensions:  nonNil(policy.DeniedExtensions),
+		},
+	}
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
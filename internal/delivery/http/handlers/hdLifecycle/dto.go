@@ -0,0 +1,51 @@
+package hdLifecycle
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+// LifecycleRuleDTO has no folder_id when the rule applies to the whole company.
+type LifecycleRuleDTO struct {
+	ID         string                 `json:"id"`
+	FolderID   *string                `json:"folder_id"`
+	FolderPath *string                `json:"folder_path"`
+	Action     domain.LifecycleAction `json:"action"`
+	Days       int                    `json:"days"`
+	CreatedBy  string                 `json:"created_by"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+type RequestID struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// RequestCreateRule applies the rule to the whole company when FolderID is empty.
+type RequestCreateRule struct {
+	FolderID string                 `json:"folder_id" binding:"omitempty,uuid"`
+	Action   domain.LifecycleAction `json:"action" binding:"required,oneof=transition delete"`
+	Days     int                    `json:"days" binding:"required,min=1,max=3650"`
+}
+
+type RequestUpdateRule struct {
+	Days int `json:"days" binding:"required,min=1,max=3650"`
+}
+
+type ResponseRule struct {
+	Status string            `json:"status"`
+	Time   time.Time         `json:"time"`
+	Rule   *LifecycleRuleDTO `json:"rule"`
+}
+
+type ResponseRules struct {
+	Status string              `json:"status"`
+	Time   time.Time           `json:"time"`
+	Rules  []*LifecycleRuleDTO `json:"rules"`
+}
+
+type ResponseSuccess struct {
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
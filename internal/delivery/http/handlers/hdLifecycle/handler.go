@@ -0,0 +1,173 @@
+package hdLifecycle
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerLifecycle struct {
+	userCase UseCaseLifecycle
+}
+
+func NewHandlerLifecycle(useCase UseCaseLifecycle) *HandlerLifecycle {
+	return &HandlerLifecycle{
+		userCase: useCase,
+	}
+}
+
+// ListRules
+// @Summary      List lifecycle rules
+// @Description  Returns the lifecycle rules of the company, for the whole company and for single folders
+// @Tags         lifecycle
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200          {object}  ResponseRules
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /lifecycle/rules [get]
+func (h *HandlerLifecycle) ListRules(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func ListRules: Company ID is required", "func", "ListRules", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	rules, errUc := h.userCase.ListRules(ctx, companyID)
+	if errUc != nil {
+		log.Error("func ListRules: Error work UseCase/Repository", "func", "ListRules", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseRules(rules))
+}
+
+// CreateRule
+// @Summary      Create lifecycle rule
+// @Description  Adds a rule for the whole company, or for a folder and everything below it when folder_id is set. transition moves files not downloaded or changed for the given days to cold storage, delete moves files not changed for the given days to the trash
+// @Tags         lifecycle
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        rule  body      RequestCreateRule  true  "Scope, action and age in days"
+// @Success      201              {object}  ResponseRule
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /lifecycle/rules [post]
+func (h *HandlerLifecycle) CreateRule(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func CreateRule: Company ID is required", "func", "CreateRule", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestCreateRule
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func CreateRule: Error in parse input param", "func", "CreateRule", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	rule, errUc := h.userCase.CreateRule(ctx, ToDomainRule(companyID, userID, &inputData))
+	if errUc != nil {
+		log.Error("func CreateRule: Error work UseCase/Repository", "func", "CreateRule", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ToResponseRule(rule))
+}
+
+// UpdateRule
+// @Summary      Update lifecycle rule
+// @Description  Changes the age in days at which the rule applies
+// @Tags         lifecycle
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string             true  "Rule ID"
+// @Param        rule  body      RequestUpdateRule  true  "Age in days"
+// @Success      200          {object}  ResponseRule
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /lifecycle/rules/{id} [put]
+func (h *HandlerLifecycle) UpdateRule(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func UpdateRule: Company ID is required", "func", "UpdateRule", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var uriData RequestID
+	if err := ctx.ShouldBindUri(&uriData); err != nil {
+		log.Error("func UpdateRule: Error in parse URI param", "func", "UpdateRule", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid rule ID"))
+		return
+	}
+
+	var inputData RequestUpdateRule
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func UpdateRule: Error in parse input param", "func", "UpdateRule", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	rule, errUc := h.userCase.UpdateRule(ctx, companyID, uriData.ID, inputData.Days)
+	if errUc != nil {
+		log.Error("func UpdateRule: Error work UseCase/Repository", "func", "UpdateRule", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseRule(rule))
+}
+
+// DeleteRule
+// @Summary      Delete lifecycle rule
+// @Description  Removes the rule. Files already moved to cold storage or the trash stay there
+// @Tags         lifecycle
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Rule ID"
+// @Success      200          {object}  ResponseSuccess
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /lifecycle/rules/{id} [delete]
+func (h *HandlerLifecycle) DeleteRule(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func DeleteRule: Company ID is required", "func", "DeleteRule", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var uriData RequestID
+	if err := ctx.ShouldBindUri(&uriData); err != nil {
+		log.Error("func DeleteRule: Error in parse URI param", "func", "DeleteRule", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid rule ID"))
+		return
+	}
+
+	if errUc := h.userCase.DeleteRule(ctx, companyID, uriData.ID); errUc != nil {
+		log.Error("func DeleteRule: Error work UseCase/Repository", "func", "DeleteRule", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSuccess("Lifecycle rule deleted successfully"))
+}
@@ -0,0 +1,187 @@
+package hdLifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseLifecycle struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseLifecycle) ListRules(ctx context.Context, companyID string) ([]*domain.LifecycleRule, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.LifecycleRule), args.Error(1)
+}
+
+func (m *mockUseCaseLifecycle) CreateRule(ctx context.Context, rule *domain.LifecycleRule) (*domain.LifecycleRule, error) {
+	args := m.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LifecycleRule), args.Error(1)
+}
+
+func (m *mockUseCaseLifecycle) UpdateRule(ctx context.Context, companyID, ruleID string, days int) (*domain.LifecycleRule, error) {
+	args := m.Called(ctx, companyID, ruleID, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LifecycleRule), args.Error(1)
+}
+
+func (m *mockUseCaseLifecycle) DeleteRule(ctx context.Context, companyID, ruleID string) error {
+	args := m.Called(ctx, companyID, ruleID)
+	return args.Error(0)
+}
+
+const (
+	testRuleID   = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	testFolderID = "9b2f3c1e-8d4a-4b6f-9a1e-2c3d4e5f6a7b"
+)
+
+func createTestContext(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: testRuleID}}
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func TestCreateRule_Folder(t *testing.T) {
+	mockUC := new(mockUseCaseLifecycle)
+	handler := NewHandlerLifecycle(mockUC)
+
+	folderID := testFolderID
+	folderPath := domain.Path("/tmp")
+	request := &domain.LifecycleRule{CompanyID: "company-123", FolderID: &folderID, Action: domain.LifecycleActionDelete, Days: 7, CreatedBy: "user-123"}
+	created := &domain.LifecycleRule{ID: testRuleID, CompanyID: "company-123", FolderID: &folderID, FolderPath: &folderPath, Action: domain.LifecycleActionDelete, Days: 7, CreatedBy: "user-123"}
+	mockUC.On("CreateRule", mock.Anything, request).Return(created, nil)
+
+	c, w := createTestContext("POST", "/lifecycle/rules", []byte(`{"folder_id":"`+testFolderID+`","action":"delete","days":7}`))
+	handler.CreateRule(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseRule
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, testFolderID, *response.Rule.FolderID)
+	assert.Equal(t, "/tmp", *response.Rule.FolderPath)
+}
+
+func TestCreateRule_Company(t *testing.T) {
+	mockUC := new(mockUseCaseLifecycle)
+	handler := NewHandlerLifecycle(mockUC)
+
+	request := &domain.LifecycleRule{CompanyID: "company-123", Action: domain.LifecycleActionTransition, Days: 90, CreatedBy: "user-123"}
+	mockUC.On("CreateRule", mock.Anything, request).Return(&domain.LifecycleRule{ID: testRuleID, Action: domain.LifecycleActionTransition, Days: 90}, nil)
+
+	c, w := createTestContext("POST", "/lifecycle/rules", []byte(`{"action":"transition","days":90}`))
+	handler.CreateRule(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseRule
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Rule.FolderID)
+	assert.Nil(t, response.Rule.FolderPath)
+}
+
+func TestCreateRule_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "unknown action", body: `{"action":"archive","days":7}`},
+		{name: "days out of range", body: `{"action":"delete","days":0}`},
+		{name: "invalid folder ID", body: `{"folder_id":"tmp","action":"delete","days":7}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockUseCaseLifecycle)
+			handler := NewHandlerLifecycle(mockUC)
+
+			c, w := createTestContext("POST", "/lifecycle/rules", []byte(tt.body))
+			handler.CreateRule(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUC.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateRule_Conflict(t *testing.T) {
+	mockUC := new(mockUseCaseLifecycle)
+	handler := NewHandlerLifecycle(mockUC)
+
+	mockUC.On("CreateRule", mock.Anything, mock.Anything).
+		Return(nil, errors.Conflict("a lifecycle rule with this action already exists here"))
+
+	c, w := createTestContext("POST", "/lifecycle/rules", []byte(`{"action":"delete","days":30}`))
+	handler.CreateRule(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestListRules_Success(t *testing.T) {
+	mockUC := new(mockUseCaseLifecycle)
+	handler := NewHandlerLifecycle(mockUC)
+
+	mockUC.On("ListRules", mock.Anything, "company-123").
+		Return([]*domain.LifecycleRule{{ID: testRuleID, Action: domain.LifecycleActionTransition, Days: 90}}, nil)
+
+	c, w := createTestContext("GET", "/lifecycle/rules", nil)
+	handler.ListRules(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponseRules
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Rules, 1)
+}
+
+func TestUpdateRule_Success(t *testing.T) {
+	mockUC := new(mockUseCaseLifecycle)
+	handler := NewHandlerLifecycle(mockUC)
+
+	mockUC.On("UpdateRule", mock.Anything, "company-123", testRuleID, 30).
+		Return(&domain.LifecycleRule{ID: testRuleID, Action: domain.LifecycleActionDelete, Days: 30}, nil)
+
+	c, w := createTestContext("PUT", "/lifecycle/rules/"+testRuleID, []byte(`{"days":30}`))
+	handler.UpdateRule(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestDeleteRule_NotFound(t *testing.T) {
+	mockUC := new(mockUseCaseLifecycle)
+	handler := NewHandlerLifecycle(mockUC)
+
+	mockUC.On("DeleteRule", mock.Anything, "company-123", testRuleID).Return(errors.NotFound("lifecycle rule not found"))
+
+	c, w := createTestContext("DELETE", "/lifecycle/rules/"+testRuleID, nil)
+	handler.DeleteRule(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
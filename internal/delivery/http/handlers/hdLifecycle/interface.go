@@ -0,0 +1,14 @@
+package hdLifecycle
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+)
+
+type UseCaseLifecycle interface {
+	ListRules(ctx context.Context, companyID string) ([]*domain.LifecycleRule, error)
+	CreateRule(ctx context.Context, rule *domain.LifecycleRule) (*domain.LifecycleRule, error)
+	UpdateRule(ctx context.Context, companyID, ruleID string, days int) (*domain.LifecycleRule, error)
+	DeleteRule(ctx context.Context, companyID, ruleID string) error
+}
@@ -0,0 +1,65 @@
+package hdLifecycle
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func ToDomainRule(companyID, userID string, req *RequestCreateRule) *domain.LifecycleRule {
+	rule := &domain.LifecycleRule{
+		CompanyID: companyID,
+		Action:    req.Action,
+		Days:      req.Days,
+		CreatedBy: userID,
+	}
+	if req.FolderID != "" {
+		rule.FolderID = &req.FolderID
+	}
+	return rule
+}
+
+func DtoRule(rule *domain.LifecycleRule) *LifecycleRuleDTO {
+	dto := &LifecycleRuleDTO{
+		ID:        rule.ID,
+		FolderID:  rule.FolderID,
+		Action:    rule.Action,
+		Days:      rule.Days,
+		CreatedBy: rule.CreatedBy,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+	if rule.FolderPath != nil {
+		folderPath := rule.FolderPath.String()
+		dto.FolderPath = &folderPath
+	}
+	return dto
+}
+
+func ToResponseRule(rule *domain.LifecycleRule) *ResponseRule {
+	return &ResponseRule{
+		Status: "success",
+		Time:   time.Now(),
+		Rule:   DtoRule(rule),
+	}
+}
+
+func ToResponseRules(rules []*domain.LifecycleRule) *ResponseRules {
+	var answer = make([]*LifecycleRuleDTO, len(rules))
+	for index, value := range rules {
+		answer[index] = DtoRule(value)
+	}
+
+	return &ResponseRules{
+		Status: "success",
+		Time:   time.Now(),
+		Rules:  answer,
+	}
+}
+
+func ToResponseSuccess(message string) *ResponseSuccess {
+	return &ResponseSuccess{
+		Status:  "success",
+		Time:    time.Now(),
+		Message: message,
+	}
+}
@@ -0,0 +1,35 @@
+package hdQuota
+
+import "time"
+
+// UsageDTO reports storage in use against its limits. Quota and available
+// fields are null when unlimited.
+type UsageDTO struct {
+	UsedBytes      int64  `json:"used_bytes"`
+	UsedFiles      int64  `json:"used_files"`
+	ReservedBytes  int64  `json:"reserved_bytes"`
+	ReservedFiles  int64  `json:"reserved_files"`
+	QuotaBytes     *int64 `json:"quota_bytes"`
+	QuotaFiles     *int64 `json:"quota_files"`
+	AvailableBytes *int64 `json:"available_bytes"`
+	AvailableFiles *int64 `json:"available_files"`
+}
+
+// RequestSetQuota replaces both limits, a missing or null limit removes it.
+type RequestSetQuota struct {
+	MaxBytes *int64 `json:"max_bytes" binding:"omitempty,min=0"`
+	MaxFiles *int64 `json:"max_files" binding:"omitempty,min=0"`
+}
+
+type ResponseUsage struct {
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Company *UsageDTO `json:"company"`
+	User    *UsageDTO `json:"user"`
+}
+
+type ResponseQuota struct {
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+	Usage  *UsageDTO `json:"usage"`
+}
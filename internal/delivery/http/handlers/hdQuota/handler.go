@@ -0,0 +1,137 @@
+package hdQuota
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerQuota struct {
+	userCase UseCaseQuota
+}
+
+func NewHandlerQuota(useCase UseCaseQuota) *HandlerQuota {
+	return &HandlerQuota{
+		userCase: useCase,
+	}
+}
+
+// GetMyUsage
+// @Summary      Get storage usage
+// @Description  Returns the storage used and reserved by the company and by the current user against their quotas
+// @Tags         companies
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200          {object}  ResponseUsage
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /companies/me/usage [get]
+func (h *HandlerQuota) GetMyUsage(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetMyUsage: Company ID is required", "func", "GetMyUsage", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	report, errUc := h.userCase.GetUsage(ctx, companyID, userID)
+	if errUc != nil {
+		log.Error("func GetMyUsage: Error work UseCase/Repository", "func", "GetMyUsage", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseUsage(report))
+}
+
+// SetCompanyQuota
+// @Summary      Set company quota
+// @Description  Replaces the storage limits of a company, a missing or null limit makes it unlimited
+// @Tags         companies
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string           true  "Company ID (UUID)"
+// @Param        quota  body      RequestSetQuota  true  "Storage limits"
+// @Success      200    {object}  ResponseQuota
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /companies/{id}/quota [put]
+func (h *HandlerQuota) SetCompanyQuota(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.Param("id")
+
+	if companyID == "" {
+		log.Error("func SetCompanyQuota: Company ID is required", "func", "SetCompanyQuota", "err", "empty companyId from param")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestSetQuota
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func SetCompanyQuota: Error in parse input param", "func", "SetCompanyQuota", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	usage, errUc := h.userCase.SetCompanyQuota(ctx, companyID, ToDomainQuota(&inputData))
+	if errUc != nil {
+		log.Error("func SetCompanyQuota: Error work UseCase/Repository", "func", "SetCompanyQuota", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseQuota(usage))
+}
+
+// SetUserQuota
+// @Summary      Set user quota
+// @Description  Replaces the storage limits of a user of your company, a missing or null limit makes it unlimited
+// @Tags         users
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string           true  "User ID (UUID)"
+// @Param        quota  body      RequestSetQuota  true  "Storage limits"
+// @Success      200    {object}  ResponseQuota
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /users/{id}/quota [put]
+func (h *HandlerQuota) SetUserQuota(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.Param("id")
+
+	if companyID == "" {
+		log.Error("func SetUserQuota: Company ID is required", "func", "SetUserQuota", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func SetUserQuota: User ID is required", "func", "SetUserQuota", "err", "empty userId from param")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var inputData RequestSetQuota
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func SetUserQuota: Error in parse input param", "func", "SetUserQuota", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	usage, errUc := h.userCase.SetUserQuota(ctx, companyID, userID, ToDomainQuota(&inputData))
+	if errUc != nil {
+		log.Error("func SetUserQuota: Error work UseCase/Repository", "func", "SetUserQuota", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseQuota(usage))
+}
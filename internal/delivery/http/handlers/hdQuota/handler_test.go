@@ -0,0 +1,149 @@
+package hdQuota
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseQuota struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseQuota) GetUsage(ctx context.Context, companyID, userID string) (*domain.UsageReport, error) {
+	args := m.Called(ctx, companyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UsageReport), args.Error(1)
+}
+
+func (m *mockUseCaseQuota) SetCompanyQuota(ctx context.Context, companyID string, quota domain.StorageQuota) (*domain.StorageUsage, error) {
+	args := m.Called(ctx, companyID, quota)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StorageUsage), args.Error(1)
+}
+
+func (m *mockUseCaseQuota) SetUserQuota(ctx context.Context, companyID, userID string, quota domain.StorageQuota) (*domain.StorageUsage, error) {
+	args := m.Called(ctx, companyID, userID, quota)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StorageUsage), args.Error(1)
+}
+
+func createTestContext(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestGetMyUsage_Success(t *testing.T) {
+	mockUC := new(mockUseCaseQuota)
+	handler := NewHandlerQuota(mockUC)
+
+	mockUC.On("GetUsage", mock.Anything, "company-123", "user-123").Return(&domain.UsageReport{
+		Company: &domain.StorageUsage{
+			UsedBytes:     700,
+			UsedFiles:     4,
+			ReservedBytes: 200,
+			ReservedFiles: 1,
+			Quota:         domain.StorageQuota{MaxBytes: int64Ptr(1000)},
+		},
+		User: &domain.StorageUsage{UsedBytes: 1200, UsedFiles: 2, Quota: domain.StorageQuota{MaxBytes: int64Ptr(1000)}},
+	}, nil)
+
+	c, w := createTestContext("GET", "/companies/me/usage", nil)
+	handler.GetMyUsage(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponseUsage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(700), response.Company.UsedBytes)
+	assert.Equal(t, int64(100), *response.Company.AvailableBytes)
+	assert.Nil(t, response.Company.QuotaFiles)
+	assert.Nil(t, response.Company.AvailableFiles)
+	assert.Equal(t, int64(0), *response.User.AvailableBytes)
+}
+
+func TestGetMyUsage_MissingCompany(t *testing.T) {
+	mockUC := new(mockUseCaseQuota)
+	handler := NewHandlerQuota(mockUC)
+
+	c, w := createTestContext("GET", "/companies/me/usage", nil)
+	c.Set("company_id", "")
+	handler.GetMyUsage(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "GetUsage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetCompanyQuota_Success(t *testing.T) {
+	mockUC := new(mockUseCaseQuota)
+	handler := NewHandlerQuota(mockUC)
+
+	quota := domain.StorageQuota{MaxBytes: int64Ptr(1 << 30)}
+	mockUC.On("SetCompanyQuota", mock.Anything, "company-456", quota).
+		Return(&domain.StorageUsage{UsedBytes: 10, Quota: quota}, nil)
+
+	c, w := createTestContext("PUT", "/companies/company-456/quota", []byte(`{"max_bytes":1073741824,"max_files":null}`))
+	c.Params = gin.Params{{Key: "id", Value: "company-456"}}
+	handler.SetCompanyQuota(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseQuota
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(1<<30), *response.Usage.QuotaBytes)
+	assert.Nil(t, response.Usage.QuotaFiles)
+}
+
+func TestSetCompanyQuota_NegativeLimit(t *testing.T) {
+	mockUC := new(mockUseCaseQuota)
+	handler := NewHandlerQuota(mockUC)
+
+	c, w := createTestContext("PUT", "/companies/company-456/quota", []byte(`{"max_files":-5}`))
+	c.Params = gin.Params{{Key: "id", Value: "company-456"}}
+	handler.SetCompanyQuota(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "SetCompanyQuota", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetUserQuota_UserNotFound(t *testing.T) {
+	mockUC := new(mockUseCaseQuota)
+	handler := NewHandlerQuota(mockUC)
+
+	quota := domain.StorageQuota{MaxFiles: int64Ptr(500)}
+	mockUC.On("SetUserQuota", mock.Anything, "company-123", "user-456", quota).
+		Return(nil, errors.NotFound("user not found"))
+
+	c, w := createTestContext("PUT", "/users/user-456/quota", []byte(`{"max_files":500}`))
+	c.Params = gin.Params{{Key: "id", Value: "user-456"}}
+	handler.SetUserQuota(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUC.AssertExpectations(t)
+}
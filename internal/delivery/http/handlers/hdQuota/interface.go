@@ -0,0 +1,12 @@
+package hdQuota
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type UseCaseQuota interface {
+	GetUsage(ctx context.Context, companyID, userID string) (*domain.UsageReport, error)
+	SetCompanyQuota(ctx context.Context, companyID string, quota domain.StorageQuota) (*domain.StorageUsage, error)
+	SetUserQuota(ctx context.Context, companyID, userID string, quota domain.StorageQuota) (*domain.StorageUsage, error)
+}
@@ -0,0 +1,51 @@
+package hdQuota
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func DtoUsage(usage *domain.StorageUsage) *UsageDTO {
+	return &UsageDTO{
+		UsedBytes:      usage.UsedBytes,
+		UsedFiles:      usage.UsedFiles,
+		ReservedBytes:  usage.ReservedBytes,
+		ReservedFiles:  usage.ReservedFiles,
+		QuotaBytes:     usage.Quota.MaxBytes,
+		QuotaFiles:     usage.Quota.MaxFiles,
+		AvailableBytes: available(usage.Quota.MaxBytes, usage.TotalBytes()),
+		AvailableFiles: available(usage.Quota.MaxFiles, usage.TotalFiles()),
+	}
+}
+
+func available(limit *int64, total int64) *int64 {
+	if limit == nil {
+		return nil
+	}
+	left := max(*limit-total, 0)
+	return &left
+}
+
+func ToDomainQuota(input *RequestSetQuota) domain.StorageQuota {
+	return domain.StorageQuota{
+		MaxBytes: input.MaxBytes,
+		MaxFiles: input.MaxFiles,
+	}
+}
+
+func ToResponseUsage(report *domain.UsageReport) *ResponseUsage {
+	return &ResponseUsage{
+		Status:  "success",
+		Time:    time.Now(),
+		Company: DtoUsage(report.Company),
+		User:    DtoUsage(report.User),
+	}
+}
+
+func ToResponseQuota(usage *domain.StorageUsage) *ResponseQuota {
+	return &ResponseQuota{
+		Status: "success",
+		Time:   time.Now(),
+		Usage:  DtoUsage(usage),
+	}
+}
@@ -0,0 +1,18 @@
+package hdReconcile
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+type RequestReconcile struct {
+	// DryRun defaults to true so that nothing is changed unless asked for.
+	DryRun *bool  `json:"dry_run"`
+	Action string `json:"action" binding:"omitempty,oneof=delete quarantine"`
+}
+
+type ResponseReconcile struct {
+	Status string                  `json:"status"`
+	Time   time.Time               `json:"time"`
+	Report *domain.ReconcileReport `json:"report"`
+}
@@ -0,0 +1,71 @@
+package hdReconcile
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerReconcile struct {
+	userCase UseCaseReconcile
+}
+
+func NewHandlerReconcile(useCase UseCaseReconcile) *HandlerReconcile {
+	return &HandlerReconcile{
+		userCase: useCase,
+	}
+}
+
+// Reconcile
+// @Summary      Reconcile storage with the database
+// @Description  Reports objects without a matching row, rows without an object and size mismatches. With dry_run=false orphaned objects are deleted or quarantined
+// @Tags         storage
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RequestReconcile  false  "Run mode"
+// @Success      200      {object}  ResponseReconcile
+// @Failure      400,500  {object}  errors.ErrorResponse
+// @Failure      401,403  {object}  errors.ErrorResponse
+// @Router       /storage/reconcile [post]
+func (h *HandlerReconcile) Reconcile(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func Reconcile: Company ID is required", "func", "Reconcile", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestReconcile
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&inputData); err != nil {
+			log.Error("func Reconcile: Error in parse input param", "func", "Reconcile", "err", err.Error())
+			errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+			return
+		}
+	}
+
+	dryRun := true
+	if inputData.DryRun != nil {
+		dryRun = *inputData.DryRun
+	}
+
+	action := domain.OrphanActionQuarantine
+	if inputData.Action != "" {
+		action = domain.OrphanAction(inputData.Action)
+	}
+
+	report, errUc := h.userCase.Reconcile(ctx, companyID, dryRun, action)
+	if errUc != nil {
+		log.Error("func Reconcile: Error work UseCase/Repository", "func", "Reconcile", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseReconcile(report))
+}
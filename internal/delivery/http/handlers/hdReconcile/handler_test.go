@@ -0,0 +1,95 @@
+package hdReconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseReconcile struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseReconcile) Reconcile(ctx context.Context, companyID string, dryRun bool, action domain.OrphanAction) (*domain.ReconcileReport, error) {
+	args := m.Called(ctx, companyID, dryRun, action)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReconcileReport), args.Error(1)
+}
+
+func createTestContext(body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/storage/reconcile", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func TestReconcile_DefaultsToDryRun(t *testing.T) {
+	mockUC := new(mockUseCaseReconcile)
+	handler := NewHandlerReconcile(mockUC)
+
+	report := &domain.ReconcileReport{CompanyID: "company-123", DryRun: true, Action: domain.OrphanActionQuarantine}
+	mockUC.On("Reconcile", mock.Anything, "company-123", true, domain.OrphanActionQuarantine).Return(report, nil)
+
+	c, w := createTestContext(nil)
+	handler.Reconcile(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseReconcile
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Report.DryRun)
+}
+
+func TestReconcile_Apply(t *testing.T) {
+	mockUC := new(mockUseCaseReconcile)
+	handler := NewHandlerReconcile(mockUC)
+
+	report := &domain.ReconcileReport{CompanyID: "company-123", Action: domain.OrphanActionDelete, Resolved: 2}
+	mockUC.On("Reconcile", mock.Anything, "company-123", false, domain.OrphanActionDelete).Return(report, nil)
+
+	c, w := createTestContext([]byte(`{"dry_run":false,"action":"delete"}`))
+	handler.Reconcile(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestReconcile_InvalidAction(t *testing.T) {
+	mockUC := new(mockUseCaseReconcile)
+	handler := NewHandlerReconcile(mockUC)
+
+	c, w := createTestContext([]byte(`{"action":"archive"}`))
+	handler.Reconcile(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReconcile_UseCaseError(t *testing.T) {
+	mockUC := new(mockUseCaseReconcile)
+	handler := NewHandlerReconcile(mockUC)
+
+	mockUC.On("Reconcile", mock.Anything, "company-123", true, domain.OrphanActionQuarantine).
+		Return(nil, errors.InternalServer("failed to list files in storage"))
+
+	c, w := createTestContext(nil)
+	handler.Reconcile(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,10 @@
+package hdReconcile
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type UseCaseReconcile interface {
+	Reconcile(ctx context.Context, companyID string, dryRun bool, action domain.OrphanAction) (*domain.ReconcileReport, error)
+}
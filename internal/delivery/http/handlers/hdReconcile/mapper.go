@@ -0,0 +1,14 @@
+package hdReconcile
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func ToResponseReconcile(report *domain.ReconcileReport) *ResponseReconcile {
+	return &ResponseReconcile{
+		Status: "success",
+		Time:   time.Now(),
+		Report: report,
+	}
+}
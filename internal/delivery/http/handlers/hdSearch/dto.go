@@ -0,0 +1,68 @@
+package hdSearch
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+type RequestSearch struct {
+	Query string `form:"q" binding:"required"`
+	Path  string `form:"path"`
+
+	Type           domain.FileType `form:"type" binding:"omitempty,oneof=file folder"`
+	MimeType       string          `form:"mimeType"`
+	MinSize        *int64          `form:"minSize" binding:"omitempty,min=0"`
+	MaxSize        *int64          `form:"maxSize" binding:"omitempty,min=0"`
+	CreatedBy      string          `form:"createdBy" binding:"omitempty,uuid"`
+	CreatedAfter   *time.Time      `form:"createdAfter"`
+	CreatedBefore  *time.Time      `form:"createdBefore"`
+	ModifiedAfter  *time.Time      `form:"modifiedAfter"`
+	ModifiedBefore *time.Time      `form:"modifiedBefore"`
+	Tags           []string        `form:"tag"`
+	// Metadata is read from metadata[key]=value query parameters by the handler.
+	Metadata map[string]string `form:"-"`
+
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=200"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type SearchResultDTO struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Type         domain.FileType `json:"type"`
+	FullPath     string          `json:"full_path"`
+	ParentID     *string         `json:"parent_id,omitempty"`
+	UserCreateID string          `json:"user_created_id"`
+	MimeType     *string         `json:"mime_type,omitempty"`
+	Size         *int64          `json:"size,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	Score        float64         `json:"score"`
+	Snippet      string          `json:"snippet,omitempty"`
+}
+
+type ResponseSearch struct {
+	Status  string             `json:"status"`
+	Time    time.Time          `json:"time"`
+	Results []*SearchResultDTO `json:"results"`
+	HasMore bool               `json:"has_more"`
+}
+
+type ResponseReindex struct {
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+	Queued int       `json:"queued"`
+}
+
+type ContentIndexStatusDTO struct {
+	Pending int `json:"pending"`
+	Indexed int `json:"indexed"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+type ResponseContentIndexStatus struct {
+	Status string                 `json:"status"`
+	Time   time.Time              `json:"time"`
+	Index  *ContentIndexStatusDTO `json:"index"`
+}
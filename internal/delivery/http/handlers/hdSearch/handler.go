@@ -0,0 +1,200 @@
+package hdSearch
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerSearch struct {
+	userCase UseCaseSearch
+}
+
+func NewHandlerSearch(useCase UseCaseSearch) *HandlerSearch {
+	return &HandlerSearch{
+		userCase: useCase,
+	}
+}
+
+// Search
+// @Summary      Search files and folders
+// @Description  Finds items of the company by name: exact, prefix, substring and similar names, best matches first. Dates use RFC 3339
+// @Tags         search
+// @Security     BearerAuth
+// @Produce      json
+// @Param        q               query     string  true   "Text to search for"
+// @Param        path            query     string  false  "Search only under this folder"
+// @Param        type            query     string  false  "file or folder"
+// @Param        mimeType        query     string  false  "MIME type, image/* matches a whole family"
+// @Param        minSize         query     int     false  "Minimum size in bytes"
+// @Param        maxSize         query     int     false  "Maximum size in bytes"
+// @Param        createdBy       query     string  false  "ID of the user who created the item"
+// @Param        createdAfter    query     string  false  "Created at or after"
+// @Param        createdBefore   query     string  false  "Created before"
+// @Param        modifiedAfter   query     string  false  "Modified at or after"
+// @Param        modifiedBefore  query     string  false  "Modified before"
+// @Param        tag             query     []string  false  "Tag the results must carry, repeat for several"  collectionFormat(multi)
+// @Param        metadata        query     string  false  "Metadata the results must carry, as metadata[key]=value"
+// @Param        limit           query     int     false  "Page size, 50 by default"
+// @Param        offset          query     int     false  "Number of results to skip"
+// @Success      200      {object}  ResponseSearch
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /search [get]
+func (h *HandlerSearch) Search(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func Search: Company ID is required", "func", "Search", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestSearch
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func Search: Error in parse query param", "func", "Search", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+	inputData.Metadata = ctx.QueryMap("metadata")
+
+	query, errValid := ToDomainSearch(&inputData)
+	if errValid != nil {
+		log.Error("func Search: Error in valid param", "func", "Search", "err", errValid.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	page, errUc := h.userCase.Search(ctx, companyID, userID, query)
+	if errUc != nil {
+		log.Error("func Search: Error work UseCase/Repository", "func", "Search", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSearch(page))
+}
+
+// SearchContent
+// @Summary      Search inside documents
+// @Description  Finds files of the company whose text matches the query, best matches first, with the matching passages wrapped in <mark> tags. Supports quoted phrases, "or" and -word. Plain text, Markdown, JSON, XML and PDF files are indexed. Snippets are not HTML-escaped
+// @Tags         search
+// @Security     BearerAuth
+// @Produce      json
+// @Param        q               query     string  true   "Text to search for"
+// @Param        path            query     string  false  "Search only under this folder"
+// @Param        mimeType        query     string  false  "MIME type, application/* matches a whole family"
+// @Param        minSize         query     int     false  "Minimum size in bytes"
+// @Param        maxSize         query     int     false  "Maximum size in bytes"
+// @Param        createdBy       query     string  false  "ID of the user who created the file"
+// @Param        createdAfter    query     string  false  "Created at or after"
+// @Param        createdBefore   query     string  false  "Created before"
+// @Param        modifiedAfter   query     string  false  "Modified at or after"
+// @Param        modifiedBefore  query     string  false  "Modified before"
+// @Param        tag             query     []string  false  "Tag the results must carry, repeat for several"  collectionFormat(multi)
+// @Param        metadata        query     string  false  "Metadata the results must carry, as metadata[key]=value"
+// @Param        limit           query     int     false  "Page size, 50 by default"
+// @Param        offset          query     int     false  "Number of results to skip"
+// @Success      200      {object}  ResponseSearch
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /search/content [get]
+func (h *HandlerSearch) SearchContent(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func SearchContent: Company ID is required", "func", "SearchContent", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestSearch
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func SearchContent: Error in parse query param", "func", "SearchContent", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+	inputData.Metadata = ctx.QueryMap("metadata")
+
+	query, errValid := ToDomainSearch(&inputData)
+	if errValid != nil {
+		log.Error("func SearchContent: Error in valid param", "func", "SearchContent", "err", errValid.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	page, errUc := h.userCase.SearchContent(ctx, companyID, userID, query)
+	if errUc != nil {
+		log.Error("func SearchContent: Error work UseCase/Repository", "func", "SearchContent", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSearch(page))
+}
+
+// ReindexContent
+// @Summary      Rebuild the content index
+// @Description  Queues every file of the company for text extraction. Files keep their current index entry until they are processed again
+// @Tags         search
+// @Security     BearerAuth
+// @Produce      json
+// @Success      202      {object}  ResponseReindex
+// @Failure      400,500  {object}  errors.ErrorResponse
+// @Failure      401,403  {object}  errors.ErrorResponse
+// @Router       /search/content/reindex [post]
+func (h *HandlerSearch) ReindexContent(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func ReindexContent: Company ID is required", "func", "ReindexContent", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	queued, errUc := h.userCase.ReindexCompany(ctx, companyID)
+	if errUc != nil {
+		log.Error("func ReindexContent: Error work UseCase/Repository", "func", "ReindexContent", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, ToResponseReindex(queued))
+}
+
+// GetContentIndexStatus
+// @Summary      Content index status
+// @Description  Number of company files waiting for indexing, indexed, skipped as unsupported or too large, and failed
+// @Tags         search
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200      {object}  ResponseContentIndexStatus
+// @Failure      400,500  {object}  errors.ErrorResponse
+// @Failure      401,403  {object}  errors.ErrorResponse
+// @Router       /search/content/status [get]
+func (h *HandlerSearch) GetContentIndexStatus(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func GetContentIndexStatus: Company ID is required", "func", "GetContentIndexStatus", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	stats, errUc := h.userCase.GetContentIndexStats(ctx, companyID)
+	if errUc != nil {
+		log.Error("func GetContentIndexStatus: Error work UseCase/Repository", "func", "GetContentIndexStatus", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseContentIndexStatus(stats))
+}
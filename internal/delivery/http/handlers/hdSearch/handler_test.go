@@ -0,0 +1,214 @@
+package hdSearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseSearch struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseSearch) Search(ctx context.Context, companyID, userID string, query *domain.SearchQuery) (*domain.SearchPage, error) {
+	args := m.Called(ctx, companyID, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchPage), args.Error(1)
+}
+
+func (m *mockUseCaseSearch) SearchContent(ctx context.Context, companyID, userID string, query *domain.SearchQuery) (*domain.SearchPage, error) {
+	args := m.Called(ctx, companyID, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchPage), args.Error(1)
+}
+
+func (m *mockUseCaseSearch) ReindexCompany(ctx context.Context, companyID string) (int, error) {
+	args := m.Called(ctx, companyID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockUseCaseSearch) GetContentIndexStats(ctx context.Context, companyID string) (*domain.ContentIndexStats, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ContentIndexStats), args.Error(1)
+}
+
+func createTestContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", target, nil)
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func TestSearch_Success(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	page := &domain.SearchPage{
+		Hits: []*domain.SearchHit{{
+			File:  &domain.File{ID: "file-id", Name: "report.pdf", Type: domain.FileTypeFile, FullPath: domain.Path("/docs/report.pdf")},
+			Score: 2.4,
+		}},
+		HasMore: true,
+	}
+	mockUC.On("Search", mock.Anything, "company-123", "user-123", mock.MatchedBy(func(query *domain.SearchQuery) bool {
+		return query.Text == "report" && query.Path == "/docs" && *query.Type == domain.FileTypeFile &&
+			query.MimeType == "application/pdf" && *query.MinSize == 10 &&
+			query.UserCreated == "9b2f3c1e-8d4a-4b6f-9a1e-2c3d4e5f6a7b" &&
+			query.ModifiedAfter != nil && query.ModifiedAfter.Year() == 2025 &&
+			query.Limit == 20 && query.Offset == 40
+	})).Return(page, nil)
+
+	c, w := createTestContext("/search?q=report&path=/docs&type=file&mimeType=application/pdf&minSize=10" +
+		"&createdBy=9b2f3c1e-8d4a-4b6f-9a1e-2c3d4e5f6a7b&modifiedAfter=2025-07-01T00:00:00Z&limit=20&offset=40")
+	handler.Search(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseSearch
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 1)
+	assert.Equal(t, "/docs/report.pdf", response.Results[0].FullPath)
+	assert.Equal(t, 2.4, response.Results[0].Score)
+	assert.True(t, response.HasMore)
+}
+
+func TestSearch_MissingQuery(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	c, w := createTestContext("/search?path=/docs")
+	handler.Search(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearch_InvalidCreator(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	c, w := createTestContext("/search?q=report&createdBy=someone")
+	handler.Search(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearch_FolderNotFound(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	mockUC.On("Search", mock.Anything, "company-123", "user-123", mock.Anything).Return(nil, errors.NotFound("folder not found"))
+
+	c, w := createTestContext("/search?q=report&path=/missing")
+	handler.Search(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSearchContent_Success(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	page := &domain.SearchPage{
+		Hits: []*domain.SearchHit{{
+			File:    &domain.File{ID: "file-id", Name: "budget.pdf", Type: domain.FileTypeFile, FullPath: domain.Path("/docs/budget.pdf")},
+			Score:   0.8,
+			Snippet: "the <mark>budget</mark> for 2025",
+		}},
+	}
+	mockUC.On("SearchContent", mock.Anything, "company-123", "user-123", mock.MatchedBy(func(query *domain.SearchQuery) bool {
+		return query.Text == "budget" && query.Path == "/docs"
+	})).Return(page, nil)
+
+	c, w := createTestContext("/search/content?q=budget&path=/docs")
+	handler.SearchContent(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseSearch
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 1)
+	assert.Equal(t, "the <mark>budget</mark> for 2025", response.Results[0].Snippet)
+	assert.False(t, response.HasMore)
+}
+
+func TestSearchContent_MissingQuery(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	c, w := createTestContext("/search/content")
+	handler.SearchContent(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "SearchContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReindexContent_Success(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	mockUC.On("ReindexCompany", mock.Anything, "company-123").Return(42, nil)
+
+	c, w := createTestContext("/search/content/reindex")
+	handler.ReindexContent(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var response ResponseReindex
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 42, response.Queued)
+}
+
+func TestGetContentIndexStatus_Success(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	mockUC.On("GetContentIndexStats", mock.Anything, "company-123").
+		Return(&domain.ContentIndexStats{Pending: 3, Indexed: 10, Skipped: 4, Failed: 1}, nil)
+
+	c, w := createTestContext("/search/content/status")
+	handler.GetContentIndexStatus(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ResponseContentIndexStatus
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, &ContentIndexStatusDTO{Pending: 3, Indexed: 10, Skipped: 4, Failed: 1}, response.Index)
+}
+
+func TestSearch_TagAndMetadataFilters(t *testing.T) {
+	mockUC := new(mockUseCaseSearch)
+	handler := NewHandlerSearch(mockUC)
+
+	mockUC.On("Search", mock.Anything, "company-123", "user-123", mock.MatchedBy(func(query *domain.SearchQuery) bool {
+		return assert.ObjectsAreEqual([]string{"q3", "review"}, query.Tags) &&
+			assert.ObjectsAreEqual(map[string]string{"project": "PRJ-42"}, query.Metadata)
+	})).Return(&domain.SearchPage{}, nil)
+
+	c, w := createTestContext("/search?q=report&tag=Review&tag=q3&metadata[project]=PRJ-42")
+	handler.Search(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
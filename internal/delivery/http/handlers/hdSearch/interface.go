@@ -0,0 +1,13 @@
+package hdSearch
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type UseCaseSearch interface {
+	Search(ctx context.Context, companyID, userID string, query *domain.SearchQuery) (*domain.SearchPage, error)
+	SearchContent(ctx context.Context, companyID, userID string, query *domain.SearchQuery) (*domain.SearchPage, error)
+	ReindexCompany(ctx context.Context, companyID string) (int, error)
+	GetContentIndexStats(ctx context.Context, companyID string) (*domain.ContentIndexStats, error)
+}
@@ -0,0 +1,99 @@
+package hdSearch
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func ToDomainSearch(dto *RequestSearch) (*domain.SearchQuery, error) {
+	path := domain.Path("/")
+	if dto.Path != "" {
+		var err error
+		path, err = domain.NewPath(dto.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := &domain.SearchQuery{
+		Text: dto.Query,
+		Path: path,
+		FileFilter: domain.FileFilter{
+			MimeType:       dto.MimeType,
+			MinSize:        dto.MinSize,
+			MaxSize:        dto.MaxSize,
+			UserCreated:    dto.CreatedBy,
+			CreatedAfter:   dto.CreatedAfter,
+			CreatedBefore:  dto.CreatedBefore,
+			ModifiedAfter:  dto.ModifiedAfter,
+			ModifiedBefore: dto.ModifiedBefore,
+			Metadata:       dto.Metadata,
+		},
+		Limit:  dto.Limit,
+		Offset: dto.Offset,
+	}
+	if dto.Type != "" {
+		query.Type = &dto.Type
+	}
+	if len(dto.Tags) > 0 {
+		var err error
+		query.Tags, err = domain.NormalizeTags(dto.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
+}
+
+func DtoSearchHit(hit *domain.SearchHit) *SearchResultDTO {
+	return &SearchResultDTO{
+		ID:           hit.File.ID,
+		Name:         hit.File.Name,
+		Type:         hit.File.Type,
+		FullPath:     hit.File.FullPath.String(),
+		ParentID:     hit.File.ParentID,
+		UserCreateID: hit.File.UserCreateID,
+		MimeType:     hit.File.MimeType,
+		Size:         hit.File.Size,
+		CreatedAt:    hit.File.CreatedAt,
+		UpdatedAt:    hit.File.UpdatedAt,
+		Score:        hit.Score,
+		Snippet:      hit.Snippet,
+	}
+}
+
+func ToResponseSearch(page *domain.SearchPage) *ResponseSearch {
+	var answer = make([]*SearchResultDTO, len(page.Hits))
+	for index, value := range page.Hits {
+		answer[index] = DtoSearchHit(value)
+	}
+
+	return &ResponseSearch{
+		Status:  "success",
+		Time:    time.Now(),
+		Results: answer,
+		HasMore: page.HasMore,
+	}
+}
+
+func ToResponseReindex(queued int) *ResponseReindex {
+	return &ResponseReindex{
+		Status: "success",
+		Time:   time.Now(),
+		Queued: queued,
+	}
+}
+
+func ToResponseContentIndexStatus(stats *domain.ContentIndexStats) *ResponseContentIndexStatus {
+	return &ResponseContentIndexStatus{
+		Status: "success",
+		Time:   time.Now(),
+		Index: &ContentIndexStatusDTO{
+			Pending: stats.Pending,
+			Indexed: stats.Indexed,
+			Skipped: stats.Skipped,
+			Failed:  stats.Failed,
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package hdShare
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+const HeaderSharePassword = "X-Share-Password"
+
+type ShareLinkDTO struct {
+	ID     string `json:"id"`
+	FileID string `json:"file_id"`
+
+	// Token and URL are only returned when the link is created.
+	Token string `json:"token,omitempty"`
+	URL   string `json:"url,omitempty"`
+
+	HasPassword   bool       `json:"has_password"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	MaxDownloads  *int       `json:"max_downloads,omitempty"`
+	DownloadCount int        `json:"download_count"`
+	AllowedIPs    []string   `json:"allowed_ips"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type SharedItemDTO struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Type      domain.FileType `json:"type"`
+	Path      string          `json:"path"`
+	MimeType  *string         `json:"mime_type,omitempty"`
+	Size      *int64          `json:"size,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type RequestID struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type RequestCreateShareLink struct {
+	Password     string     `json:"password" binding:"omitempty,max=72"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	MaxDownloads *int       `json:"max_downloads" binding:"omitempty,min=1"`
+	AllowedIPs   []string   `json:"allowed_ips" binding:"omitempty,max=50"`
+}
+
+type RequestOpenShare struct {
+	Token string `uri:"token" binding:"required"`
+
+	Path   string `form:"path"`
+	File   string `form:"file" binding:"omitempty,uuid"`
+	Inline bool   `form:"inline"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Cursor string `form:"cursor"`
+}
+
+type ResponseShareLink struct {
+	Status string        `json:"status"`
+	Time   time.Time     `json:"time"`
+	Link   *ShareLinkDTO `json:"link"`
+}
+
+type ResponseShareLinks struct {
+	Status string          `json:"status"`
+	Time   time.Time       `json:"time"`
+	Links  []*ShareLinkDTO `json:"links"`
+}
+
+type ResponseSharedFolder struct {
+	Status     string           `json:"status"`
+	Time       time.Time        `json:"time"`
+	Name       string           `json:"name"`
+	Path       string           `json:"path"`
+	Items      []*SharedItemDTO `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type ResponseSuccess struct {
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
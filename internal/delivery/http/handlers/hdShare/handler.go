@@ -0,0 +1,277 @@
+package hdShare
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+	"go-storage/pkg/mimetype"
+)
+
+// inlineMimeTypes may be displayed by the browser. Shared content is served
+// from the API origin, so types that can run script, such as HTML and SVG,
+// are always downloaded.
+var inlineMimeTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/avif":      true,
+	"image/bmp":       true,
+	"application/pdf": true,
+	"text/plain":      true,
+}
+
+type HandlerShare struct {
+	userCase UseCaseShare
+}
+
+func NewHandlerShare(useCase UseCaseShare) *HandlerShare {
+	return &HandlerShare{
+		userCase: useCase,
+	}
+}
+
+// CreateLink
+// @Summary      Create share link
+// @Description  Creates a public link to a file or folder. The token is only returned in this response
+// @Tags         shares
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                  true  "File or folder ID"
+// @Param        share  body      RequestCreateShareLink  true  "Link restrictions"
+// @Success      201    {object}  ResponseShareLink
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /files/{id}/share [post]
+func (h *HandlerShare) CreateLink(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func CreateLink: Company ID is required", "func", "CreateLink", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func CreateLink: User ID is required", "func", "CreateLink", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var uriData RequestID
+	if err := ctx.ShouldBindUri(&uriData); err != nil {
+		log.Error("func CreateLink: Error in parse URI param", "func", "CreateLink", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	var inputData RequestCreateShareLink
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func CreateLink: Error in parse input param", "func", "CreateLink", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	link, errUc := h.userCase.CreateLink(ctx, companyID, userID, uriData.ID, ToDomainShareLinkOptions(&inputData))
+	if errUc != nil {
+		log.Error("func CreateLink: Error work UseCase/Repository", "func", "CreateLink", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ToResponseShareLink(link))
+}
+
+// ListLinks
+// @Summary      List share links
+// @Description  Returns the share links created by the current user, newest first
+// @Tags         shares
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200          {object}  ResponseShareLinks
+// @Failure      400,500      {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /shares [get]
+func (h *HandlerShare) ListLinks(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func ListLinks: Company ID is required", "func", "ListLinks", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func ListLinks: User ID is required", "func", "ListLinks", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	links, errUc := h.userCase.ListLinks(ctx, companyID, userID)
+	if errUc != nil {
+		log.Error("func ListLinks: Error work UseCase/Repository", "func", "ListLinks", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseShareLinks(links))
+}
+
+// RevokeLink
+// @Summary      Revoke share link
+// @Description  Deletes one of the current user's share links, it stops working immediately
+// @Tags         shares
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Share link ID"
+// @Success      200          {object}  ResponseSuccess
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /shares/{id} [delete]
+func (h *HandlerShare) RevokeLink(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func RevokeLink: Company ID is required", "func", "RevokeLink", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func RevokeLink: User ID is required", "func", "RevokeLink", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var uriData RequestID
+	if err := ctx.ShouldBindUri(&uriData); err != nil {
+		log.Error("func RevokeLink: Error in parse URI param", "func", "RevokeLink", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid share link ID"))
+		return
+	}
+
+	if errUc := h.userCase.RevokeLink(ctx, companyID, userID, uriData.ID); errUc != nil {
+		log.Error("func RevokeLink: Error work UseCase/Repository", "func", "RevokeLink", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSuccess("Share link revoked successfully"))
+}
+
+// OpenShare
+// @Summary      Open share link
+// @Description  Streams a shared file, or lists a shared folder. Files inside a shared folder are downloaded with the file parameter.
+// @Description  A password is sent in the X-Share-Password header or as the password of HTTP Basic authentication
+// @Tags         shares
+// @Produce      json
+// @Produce      octet-stream
+// @Param        token             path      string  true   "Share token"
+// @Param        X-Share-Password  header    string  false  "Link password"
+// @Param        path              query     string  false  "Folder below the shared folder"
+// @Param        file              query     string  false  "ID of a file inside the shared folder to download"
+// @Param        inline            query     bool    false  "Display images, PDF and plain text in the browser instead of downloading them"
+// @Param        limit             query     int     false  "Page size, at most 1000"
+// @Param        cursor            query     string  false  "Cursor returned by the previous page"
+// @Success      200               {object}  ResponseSharedFolder
+// @Failure      400,404,500       {object}  errors.ErrorResponse
+// @Failure      401,403,410       {object}  errors.ErrorResponse
+// @Router       /s/{token} [get]
+func (h *HandlerShare) OpenShare(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+
+	var inputData RequestOpenShare
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func OpenShare: Error in parse URI param", "func", "OpenShare", "err", err.Error())
+		errors.HandleError(ctx, errors.NotFound("share link not found"))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&inputData); err != nil {
+		log.Error("func OpenShare: Error in parse query param", "func", "OpenShare", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	password := ctx.GetHeader(HeaderSharePassword)
+	if password == "" {
+		_, password, _ = ctx.Request.BasicAuth()
+	}
+
+	link, target, errUc := h.userCase.AccessLink(ctx, &domain.ShareAccess{
+		Token:    inputData.Token,
+		Password: password,
+		ClientIP: ctx.ClientIP(),
+	})
+	if errUc != nil {
+		log.Error("func OpenShare: Error work UseCase/Repository", "func", "OpenShare", "err", errUc.Error())
+		if appErr, ok := errUc.(*errors.AppError); ok && appErr.Code == http.StatusUnauthorized {
+			ctx.Header("WWW-Authenticate", `Basic realm="share"`)
+		}
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	if target.Type == domain.FileTypeFile || inputData.File != "" {
+		file, reader, errUc := h.userCase.OpenSharedFile(ctx, link, target, inputData.File)
+		if errUc != nil {
+			log.Error("func OpenShare: Error work UseCase/Repository", "func", "OpenShare", "err", errUc.Error())
+			errors.HandleError(ctx, errUc)
+			return
+		}
+		defer reader.Close()
+
+		if err := streamFile(ctx, reader, file, inputData.Inline); err != nil {
+			log.Error("func OpenShare: Error streaming file", "func", "OpenShare", "err", err.Error())
+		}
+		return
+	}
+
+	query, err := ToDomainOpenShare(&inputData)
+	if err != nil {
+		log.Error("func OpenShare: Error in parse query param", "func", "OpenShare", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	folder, errUc := h.userCase.ListSharedFolder(ctx, link, target, query)
+	if errUc != nil {
+		log.Error("func OpenShare: Error work UseCase/Repository", "func", "OpenShare", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSharedFolder(folder))
+}
+
+func streamFile(ctx *gin.Context, reader io.Reader, fileInfo *domain.File, inline bool) error {
+	disposition := "attachment"
+	if inline && inlineMimeTypes[mimetype.Normalize(*fileInfo.MimeType)] {
+		disposition = "inline"
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, fileInfo.Name))
+	ctx.Header("Content-Type", *fileInfo.MimeType)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Header("Content-Security-Policy", "sandbox")
+	ctx.Header("Content-Length", strconv.FormatInt(*fileInfo.Size, 10))
+	if fileInfo.Hash != nil {
+		ctx.Header("ETag", strconv.Quote(*fileInfo.Hash))
+	}
+
+	_, err := io.Copy(ctx.Writer, reader)
+	return err
+}
@@ -0,0 +1,290 @@
+package hdShare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseShare struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseShare) CreateLink(ctx context.Context, companyID, userID, fileID string, options *domain.ShareLinkOptions) (*domain.ShareLink, error) {
+	args := m.Called(ctx, companyID, userID, fileID, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ShareLink), args.Error(1)
+}
+
+func (m *mockUseCaseShare) ListLinks(ctx context.Context, companyID, userID string) ([]*domain.ShareLink, error) {
+	args := m.Called(ctx, companyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ShareLink), args.Error(1)
+}
+
+func (m *mockUseCaseShare) RevokeLink(ctx context.Context, companyID, userID, linkID string) error {
+	args := m.Called(ctx, companyID, userID, linkID)
+	return args.Error(0)
+}
+
+func (m *mockUseCaseShare) AccessLink(ctx context.Context, access *domain.ShareAccess) (*domain.ShareLink, *domain.File, error) {
+	args := m.Called(ctx, access)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.ShareLink), args.Get(1).(*domain.File), args.Error(2)
+}
+
+func (m *mockUseCaseShare) ListSharedFolder(ctx context.Context, link *domain.ShareLink, target *domain.File, query *domain.FolderListQuery) (*domain.SharedFolder, error) {
+	args := m.Called(ctx, link, target, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SharedFolder), args.Error(1)
+}
+
+func (m *mockUseCaseShare) OpenSharedFile(ctx context.Context, link *domain.ShareLink, target *domain.File, fileID string) (*domain.File, io.ReadCloser, error) {
+	args := m.Called(ctx, link, target, fileID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.File), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+const (
+	testFileID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	testLinkID = "9b2f3c1e-8d4a-4b6f-9a1e-2c3d4e5f6a7b"
+)
+
+func createTestContext(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func createPublicContext(target, token string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", target, nil)
+	c.Request.RemoteAddr = "203.0.113.7:52000"
+	c.Params = gin.Params{{Key: "token", Value: token}}
+	return c, w
+}
+
+func TestCreateLink_Success(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	maxDownloads := 3
+	link := &domain.ShareLink{ID: testLinkID, FileID: testFileID, Token: "secret-token", MaxDownloads: &maxDownloads, AllowedIPs: []string{}}
+	mockUC.On("CreateLink", mock.Anything, "company-123", "user-123", testFileID, mock.MatchedBy(func(options *domain.ShareLinkOptions) bool {
+		return options.Password == "hunter2" && *options.MaxDownloads == 3 && options.ExpiresAt != nil
+	})).Return(link, nil)
+
+	body, _ := json.Marshal(map[string]any{
+		"password":      "hunter2",
+		"expires_at":    time.Now().Add(time.Hour),
+		"max_downloads": 3,
+	})
+	c, w := createTestContext("POST", "/files/"+testFileID+"/share", body)
+	c.Params = gin.Params{{Key: "id", Value: testFileID}}
+	handler.CreateLink(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+
+	var response ResponseShareLink
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "secret-token", response.Link.Token)
+	assert.Equal(t, "/s/secret-token", response.Link.URL)
+}
+
+func TestCreateLink_InvalidMaxDownloads(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	c, w := createTestContext("POST", "/files/"+testFileID+"/share", []byte(`{"max_downloads":-1}`))
+	c.Params = gin.Params{{Key: "id", Value: testFileID}}
+	handler.CreateLink(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "CreateLink", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListLinks_HidesToken(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	hash := "hash"
+	mockUC.On("ListLinks", mock.Anything, "company-123", "user-123").
+		Return([]*domain.ShareLink{{ID: testLinkID, FileID: testFileID, PasswordHash: &hash, DownloadCount: 2}}, nil)
+
+	c, w := createTestContext("GET", "/shares", nil)
+	handler.ListLinks(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"token"`)
+
+	var response ResponseShareLinks
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Links, 1)
+	assert.True(t, response.Links[0].HasPassword)
+	assert.Equal(t, 2, response.Links[0].DownloadCount)
+}
+
+func TestRevokeLink_NotFound(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	mockUC.On("RevokeLink", mock.Anything, "company-123", "user-123", testLinkID).Return(errors.NotFound("share link not found"))
+
+	c, w := createTestContext("DELETE", "/shares/"+testLinkID, nil)
+	c.Params = gin.Params{{Key: "id", Value: testLinkID}}
+	handler.RevokeLink(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOpenShare_StreamsFile(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	mimeType := "text/plain"
+	size := int64(5)
+	link := &domain.ShareLink{ID: testLinkID}
+	file := &domain.File{ID: testFileID, Name: "hello.txt", Type: domain.FileTypeFile, MimeType: &mimeType, Size: &size}
+
+	mockUC.On("AccessLink", mock.Anything, &domain.ShareAccess{Token: "tok", Password: "hunter2", ClientIP: "203.0.113.7"}).
+		Return(link, file, nil)
+	mockUC.On("OpenSharedFile", mock.Anything, link, file, "").
+		Return(file, io.NopCloser(strings.NewReader("hello")), nil)
+
+	c, w := createPublicContext("/s/tok", "tok")
+	c.Request.SetBasicAuth("", "hunter2")
+	handler.OpenShare(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+	assert.Equal(t, `attachment; filename="hello.txt"`, w.Header().Get("Content-Disposition"))
+	mockUC.AssertExpectations(t)
+}
+
+func TestOpenShare_Inline(t *testing.T) {
+	tests := []struct {
+		mimeType    string
+		disposition string
+	}{
+		{mimeType: "image/png", disposition: "inline"},
+		{mimeType: "application/pdf", disposition: "inline"},
+		{mimeType: "text/plain; charset=utf-8", disposition: "inline"},
+		{mimeType: "text/html", disposition: "attachment"},
+		{mimeType: "image/svg+xml", disposition: "attachment"},
+		{mimeType: "application/xhtml+xml", disposition: "attachment"},
+		{mimeType: "application/octet-stream", disposition: "attachment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			mockUC := new(mockUseCaseShare)
+			handler := NewHandlerShare(mockUC)
+
+			mimeType := tt.mimeType
+			size := int64(5)
+			link := &domain.ShareLink{ID: testLinkID}
+			file := &domain.File{ID: testFileID, Name: "page", Type: domain.FileTypeFile, MimeType: &mimeType, Size: &size}
+
+			mockUC.On("AccessLink", mock.Anything, mock.Anything).Return(link, file, nil)
+			mockUC.On("OpenSharedFile", mock.Anything, link, file, "").
+				Return(file, io.NopCloser(strings.NewReader("hello")), nil)
+
+			c, w := createPublicContext("/s/tok?inline=true", "tok")
+			handler.OpenShare(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.disposition+`; filename="page"`, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+			assert.Equal(t, "sandbox", w.Header().Get("Content-Security-Policy"))
+		})
+	}
+}
+
+func TestOpenShare_ListsFolder(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	link := &domain.ShareLink{ID: testLinkID}
+	root := &domain.File{ID: testFileID, Name: "docs", Type: domain.FileTypeFolder, FullPath: domain.Path("/team/docs")}
+	sub := &domain.File{ID: "sub", Name: "q3", Type: domain.FileTypeFolder, FullPath: domain.Path("/team/docs/q3")}
+	folder := &domain.SharedFolder{
+		Root:   root,
+		Folder: sub,
+		Page: &domain.FolderPage{Items: []*domain.File{
+			{ID: "file", Name: "report.pdf", Type: domain.FileTypeFile, FullPath: domain.Path("/team/docs/q3/report.pdf")},
+		}},
+	}
+
+	mockUC.On("AccessLink", mock.Anything, mock.Anything).Return(link, root, nil)
+	mockUC.On("ListSharedFolder", mock.Anything, link, root, mock.MatchedBy(func(query *domain.FolderListQuery) bool {
+		return query.Path == "/q3" && query.Limit == 10
+	})).Return(folder, nil)
+
+	c, w := createPublicContext("/s/tok?path=/q3&limit=10", "tok")
+	handler.OpenShare(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "/team")
+
+	var response ResponseSharedFolder
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "/q3", response.Path)
+	assert.Equal(t, "/q3/report.pdf", response.Items[0].Path)
+}
+
+func TestOpenShare_PasswordRequired(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	mockUC.On("AccessLink", mock.Anything, mock.Anything).Return(nil, nil, errors.Unauthorized("share link requires a password"))
+
+	c, w := createPublicContext("/s/tok", "tok")
+	handler.OpenShare(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="share"`, w.Header().Get("WWW-Authenticate"))
+	mockUC.AssertNotCalled(t, "OpenSharedFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOpenShare_Expired(t *testing.T) {
+	mockUC := new(mockUseCaseShare)
+	handler := NewHandlerShare(mockUC)
+
+	mockUC.On("AccessLink", mock.Anything, mock.Anything).Return(nil, nil, errors.Gone("share link has expired"))
+
+	c, w := createPublicContext("/s/tok", "tok")
+	handler.OpenShare(c)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Empty(t, w.Header().Get("WWW-Authenticate"))
+}
@@ -0,0 +1,18 @@
+package hdShare
+
+import (
+	"context"
+	"io"
+
+	"go-storage/internal/domain"
+)
+
+type UseCaseShare interface {
+	CreateLink(ctx context.Context, companyID, userID, fileID string, options *domain.ShareLinkOptions) (*domain.ShareLink, error)
+	ListLinks(ctx context.Context, companyID, userID string) ([]*domain.ShareLink, error)
+	RevokeLink(ctx context.Context, companyID, userID, linkID string) error
+
+	AccessLink(ctx context.Context, access *domain.ShareAccess) (*domain.ShareLink, *domain.File, error)
+	ListSharedFolder(ctx context.Context, link *domain.ShareLink, target *domain.File, query *domain.FolderListQuery) (*domain.SharedFolder, error)
+	OpenSharedFile(ctx context.Context, link *domain.ShareLink, target *domain.File, fileID string) (*domain.File, io.ReadCloser, error)
+}
@@ -0,0 +1,115 @@
+package hdShare
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func ToDomainShareLinkOptions(dto *RequestCreateShareLink) *domain.ShareLinkOptions {
+	return &domain.ShareLinkOptions{
+		Password:     dto.Password,
+		ExpiresAt:    dto.ExpiresAt,
+		MaxDownloads: dto.MaxDownloads,
+		AllowedIPs:   dto.AllowedIPs,
+	}
+}
+
+func ToDomainOpenShare(dto *RequestOpenShare) (*domain.FolderListQuery, error) {
+	path, err := domain.NewPath(dto.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &domain.FolderListQuery{
+		Path:  path,
+		Sort:  domain.FolderSortType,
+		Order: domain.SortOrderAsc,
+		Limit: dto.Limit,
+	}
+
+	if dto.Cursor != "" {
+		query.Cursor, err = domain.DecodeFolderCursor(dto.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
+}
+
+func DtoShareLink(link *domain.ShareLink) *ShareLinkDTO {
+	dto := &ShareLinkDTO{
+		ID:            link.ID,
+		FileID:        link.FileID,
+		Token:         link.Token,
+		HasPassword:   link.HasPassword(),
+		ExpiresAt:     link.ExpiresAt,
+		MaxDownloads:  link.MaxDownloads,
+		DownloadCount: link.DownloadCount,
+		AllowedIPs:    link.AllowedIPs,
+		CreatedAt:     link.CreatedAt,
+	}
+
+	if link.Token != "" {
+		dto.URL = "/s/" + link.Token
+	}
+
+	return dto
+}
+
+func DtoSharedItem(folder *domain.SharedFolder, file *domain.File) *SharedItemDTO {
+	return &SharedItemDTO{
+		ID:        file.ID,
+		Name:      file.Name,
+		Type:      file.Type,
+		Path:      folder.RelativePath(file.FullPath).String(),
+		MimeType:  file.MimeType,
+		Size:      file.Size,
+		UpdatedAt: file.UpdatedAt,
+	}
+}
+
+func ToResponseShareLink(link *domain.ShareLink) *ResponseShareLink {
+	return &ResponseShareLink{
+		Status: "success",
+		Time:   time.Now(),
+		Link:   DtoShareLink(link),
+	}
+}
+
+func ToResponseShareLinks(links []*domain.ShareLink) *ResponseShareLinks {
+	var answer = make([]*ShareLinkDTO, len(links))
+	for index, value := range links {
+		answer[index] = DtoShareLink(value)
+	}
+
+	return &ResponseShareLinks{
+		Status: "success",
+		Time:   time.Now(),
+		Links:  answer,
+	}
+}
+
+func ToResponseSharedFolder(folder *domain.SharedFolder) *ResponseSharedFolder {
+	var answer = make([]*SharedItemDTO, len(folder.Page.Items))
+	for index, value := range folder.Page.Items {
+		answer[index] = DtoSharedItem(folder, value)
+	}
+
+	return &ResponseSharedFolder{
+		Status:     "success",
+		Time:       time.Now(),
+		Name:       folder.Root.Name,
+		Path:       folder.RelativePath(folder.Folder.FullPath).String(),
+		Items:      answer,
+		NextCursor: folder.Page.NextCursor,
+	}
+}
+
+func ToResponseSuccess(message string) *ResponseSuccess {
+	return &ResponseSuccess{
+		Status:  "success",
+		Time:    time.Now(),
+		Message: message,
+	}
+}
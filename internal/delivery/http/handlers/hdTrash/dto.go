@@ -0,0 +1,70 @@
+package hdTrash
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+type TrashItemDTO struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Type         domain.FileType `json:"type"`
+	FullPath     string          `json:"full_path"`
+	ParentID     *string         `json:"parent_id,omitempty"`
+	UserCreateID string          `json:"user_created_id"`
+
+	MimeType *string `json:"mime_type,omitempty"`
+	Size     *int64  `json:"size,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type FileDTO struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Type      domain.FileType `json:"type"`
+	FullPath  string          `json:"full_path"`
+	ParentID  *string         `json:"parent_id,omitempty"`
+	MimeType  *string         `json:"mime_type,omitempty"`
+	Size      *int64          `json:"size,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type TrashSettingsDTO struct {
+	RetentionDays int `json:"retention_days"`
+}
+
+type RequestTrashItem struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type RequestUpdateTrashSettings struct {
+	RetentionDays int `json:"retention_days" binding:"required,min=1"`
+}
+
+type ResponseTrash struct {
+	Status string          `json:"status"`
+	Time   time.Time       `json:"time"`
+	Items  []*TrashItemDTO `json:"items"`
+}
+
+type ResponseRestoredItem struct {
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+	File   *FileDTO  `json:"file"`
+}
+
+type ResponseTrashSettings struct {
+	Status   string            `json:"status"`
+	Time     time.Time         `json:"time"`
+	Settings *TrashSettingsDTO `json:"settings"`
+}
+
+type ResponseSuccess struct {
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
@@ -0,0 +1,215 @@
+package hdTrash
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+type HandlerTrash struct {
+	userCase UseCaseTrash
+}
+
+func NewHandlerTrash(useCase UseCaseTrash) *HandlerTrash {
+	return &HandlerTrash{
+		userCase: useCase,
+	}
+}
+
+// GetTrash
+// @Summary      List trash
+// @Description  Returns the deleted files and folders of the company the user had access to, most recently deleted first
+// @Tags         trash
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200      {object}  ResponseTrash
+// @Failure      400,500  {object}  errors.ErrorResponse
+// @Failure      401,403  {object}  errors.ErrorResponse
+// @Router       /trash [get]
+func (h *HandlerTrash) GetTrash(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func GetTrash: Company ID is required", "func", "GetTrash", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func GetTrash: User ID is required", "func", "GetTrash", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	items, errUc := h.userCase.ListTrash(ctx, companyID, userID)
+	if errUc != nil {
+		log.Error("func GetTrash: Error work UseCase/Repository", "func", "GetTrash", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseTrash(items))
+}
+
+// RestoreItem
+// @Summary      Restore item from trash
+// @Description  Restores a deleted file or folder. A deleted parent is restored first, a missing parent restores the item to the root, and a taken name gets a numeric suffix. Requires the editor role on the item
+// @Tags         trash
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "Item ID"
+// @Success      200 {object}  ResponseRestoredItem
+// @Failure      400,404,409,500  {object}  errors.ErrorResponse
+// @Failure      401,403          {object}  errors.ErrorResponse
+// @Router       /trash/{id}/restore [post]
+func (h *HandlerTrash) RestoreItem(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func RestoreItem: Company ID is required", "func", "RestoreItem", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func RestoreItem: User ID is required", "func", "RestoreItem", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var inputData RequestTrashItem
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func RestoreItem: Error in parse URI param", "func", "RestoreItem", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid item ID"))
+		return
+	}
+
+	file, errUc := h.userCase.RestoreItem(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func RestoreItem: Error work UseCase/Repository", "func", "RestoreItem", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseRestoredItem(file))
+}
+
+// PurgeItem
+// @Summary      Delete item permanently
+// @Description  Permanently deletes a trashed file or folder together with its contents and stored objects. Requires the editor role on the item
+// @Tags         trash
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path      string  true  "Item ID"
+// @Success      200 {object}  ResponseSuccess
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /trash/{id} [delete]
+func (h *HandlerTrash) PurgeItem(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+	userID := ctx.GetString("user_id")
+
+	if companyID == "" {
+		log.Error("func PurgeItem: Company ID is required", "func", "PurgeItem", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	if userID == "" {
+		log.Error("func PurgeItem: User ID is required", "func", "PurgeItem", "err", "empty userId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("User ID is required"))
+		return
+	}
+
+	var inputData RequestTrashItem
+	if err := ctx.ShouldBindUri(&inputData); err != nil {
+		log.Error("func PurgeItem: Error in parse URI param", "func", "PurgeItem", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid item ID"))
+		return
+	}
+
+	errUc := h.userCase.PurgeItem(ctx, companyID, userID, inputData.ID)
+	if errUc != nil {
+		log.Error("func PurgeItem: Error work UseCase/Repository", "func", "PurgeItem", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseSuccess("Item deleted permanently"))
+}
+
+// GetSettings
+// @Summary      Get trash settings
+// @Description  Returns how many days deleted items are kept before being purged
+// @Tags         trash
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200          {object}  ResponseTrashSettings
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /trash/settings [get]
+func (h *HandlerTrash) GetSettings(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func GetSettings: Company ID is required", "func", "GetSettings", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	settings, errUc := h.userCase.GetSettings(ctx, companyID)
+	if errUc != nil {
+		log.Error("func GetSettings: Error work UseCase/Repository", "func", "GetSettings", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseTrashSettings(settings))
+}
+
+// UpdateSettings
+// @Summary      Update trash settings
+// @Description  Changes how many days deleted items are kept before being purged
+// @Tags         trash
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        settings  body      RequestUpdateTrashSettings  true  "Retention settings"
+// @Success      200       {object}  ResponseTrashSettings
+// @Failure      400,404,500  {object}  errors.ErrorResponse
+// @Failure      401,403      {object}  errors.ErrorResponse
+// @Router       /trash/settings [put]
+func (h *HandlerTrash) UpdateSettings(ctx *gin.Context) {
+	log := logger.FromContext(ctx)
+	companyID := ctx.GetString("company_id")
+
+	if companyID == "" {
+		log.Error("func UpdateSettings: Company ID is required", "func", "UpdateSettings", "err", "empty companyId from JWT")
+		errors.HandleError(ctx, errors.BadRequest("Company ID is required"))
+		return
+	}
+
+	var inputData RequestUpdateTrashSettings
+	if err := ctx.ShouldBindJSON(&inputData); err != nil {
+		log.Error("func UpdateSettings: Error in parse input param", "func", "UpdateSettings", "err", err.Error())
+		errors.HandleError(ctx, errors.BadRequest("Invalid JSON"))
+		return
+	}
+
+	settings, errUc := h.userCase.UpdateSettings(ctx, companyID, inputData.RetentionDays)
+	if errUc != nil {
+		log.Error("func UpdateSettings: Error work UseCase/Repository", "func", "UpdateSettings", "err", errUc.Error())
+		errors.HandleError(ctx, errUc)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ToResponseTrashSettings(settings))
+}
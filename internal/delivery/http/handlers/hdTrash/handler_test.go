@@ -0,0 +1,182 @@
+package hdTrash
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type mockUseCaseTrash struct {
+	mock.Mock
+}
+
+func (m *mockUseCaseTrash) ListTrash(ctx context.Context, companyID, userID string) ([]*domain.TrashItem, error) {
+	args := m.Called(ctx, companyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TrashItem), args.Error(1)
+}
+
+func (m *mockUseCaseTrash) RestoreItem(ctx context.Context, companyID, userID, itemID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, userID, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *mockUseCaseTrash) PurgeItem(ctx context.Context, companyID, userID, itemID string) error {
+	args := m.Called(ctx, companyID, userID, itemID)
+	return args.Error(0)
+}
+
+func (m *mockUseCaseTrash) GetSettings(ctx context.Context, companyID string) (*domain.TrashSettings, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TrashSettings), args.Error(1)
+}
+
+func (m *mockUseCaseTrash) UpdateSettings(ctx context.Context, companyID string, retentionDays int) (*domain.TrashSettings, error) {
+	args := m.Called(ctx, companyID, retentionDays)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TrashSettings), args.Error(1)
+}
+
+const testItemID = "123e4567-e89b-12d3-a456-426614174000"
+
+func createTestContext(method, target string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("company_id", "company-123")
+	c.Set("user_id", "user-123")
+	return c, w
+}
+
+func TestGetTrash_Success(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	deletedAt := time.Now().Add(-time.Hour)
+	file := &domain.File{ID: testItemID, Name: "test.txt", Type: domain.FileTypeFile, FullPath: "/test.txt", UpdatedAt: deletedAt}
+	mockUC.On("ListTrash", mock.Anything, "company-123", "user-123").
+		Return([]*domain.TrashItem{domain.NewTrashItem(file, 24*time.Hour)}, nil)
+
+	c, w := createTestContext("GET", "/trash", nil)
+	handler.GetTrash(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "test.txt")
+	assert.Contains(t, w.Body.String(), "expires_at")
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetTrash_MissingCompanyID(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	c, w := createTestContext("GET", "/trash", nil)
+	c.Set("company_id", "")
+	handler.GetTrash(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "ListTrash")
+}
+
+func TestRestoreItem_Success(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	restored := &domain.File{ID: testItemID, Name: "test (1).txt", Type: domain.FileTypeFile, FullPath: "/test (1).txt"}
+	mockUC.On("RestoreItem", mock.Anything, "company-123", "user-123", testItemID).Return(restored, nil)
+
+	c, w := createTestContext("POST", "/trash/"+testItemID+"/restore", nil)
+	c.Params = []gin.Param{{Key: "id", Value: testItemID}}
+	handler.RestoreItem(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "test (1).txt")
+	mockUC.AssertExpectations(t)
+}
+
+func TestRestoreItem_InvalidID(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	c, w := createTestContext("POST", "/trash/not-a-uuid/restore", nil)
+	c.Params = []gin.Param{{Key: "id", Value: "not-a-uuid"}}
+	handler.RestoreItem(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "RestoreItem")
+}
+
+func TestPurgeItem_Success(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	mockUC.On("PurgeItem", mock.Anything, "company-123", "user-123", testItemID).Return(nil)
+
+	c, w := createTestContext("DELETE", "/trash/"+testItemID, nil)
+	c.Params = []gin.Param{{Key: "id", Value: testItemID}}
+	handler.PurgeItem(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestPurgeItem_NotFound(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	mockUC.On("PurgeItem", mock.Anything, "company-123", "user-123", testItemID).Return(errors.NotFound("item not found in trash"))
+
+	c, w := createTestContext("DELETE", "/trash/"+testItemID, nil)
+	c.Params = []gin.Param{{Key: "id", Value: testItemID}}
+	handler.PurgeItem(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUpdateSettings_Success(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	mockUC.On("UpdateSettings", mock.Anything, "company-123", 14).
+		Return(&domain.TrashSettings{CompanyID: "company-123", RetentionDays: 14}, nil)
+
+	c, w := createTestContext("PUT", "/trash/settings", []byte(`{"retention_days": 14}`))
+	handler.UpdateSettings(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"retention_days":14`)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUpdateSettings_InvalidBody(t *testing.T) {
+	mockUC := new(mockUseCaseTrash)
+	handler := NewHandlerTrash(mockUC)
+
+	c, w := createTestContext("PUT", "/trash/settings", []byte(`{"retention_days": 0}`))
+	handler.UpdateSettings(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "UpdateSettings")
+}
@@ -0,0 +1,15 @@
+package hdTrash
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type UseCaseTrash interface {
+	ListTrash(ctx context.Context, companyID, userID string) ([]*domain.TrashItem, error)
+	RestoreItem(ctx context.Context, companyID, userID, itemID string) (*domain.File, error)
+	PurgeItem(ctx context.Context, companyID, userID, itemID string) error
+
+	GetSettings(ctx context.Context, companyID string) (*domain.TrashSettings, error)
+	UpdateSettings(ctx context.Context, companyID string, retentionDays int) (*domain.TrashSettings, error)
+}
@@ -0,0 +1,75 @@
+package hdTrash
+
+import (
+	"go-storage/internal/domain"
+	"time"
+)
+
+func DtoTrashItem(item *domain.TrashItem) *TrashItemDTO {
+	return &TrashItemDTO{
+		ID:           item.File.ID,
+		Name:         item.File.Name,
+		Type:         item.File.Type,
+		FullPath:     item.File.FullPath.String(),
+		ParentID:     item.File.ParentID,
+		UserCreateID: item.File.UserCreateID,
+
+		MimeType: item.File.MimeType,
+		Size:     item.File.Size,
+
+		CreatedAt: item.File.CreatedAt,
+		DeletedAt: item.DeletedAt,
+		ExpiresAt: item.ExpiresAt,
+	}
+}
+
+func DtoFile(file *domain.File) *FileDTO {
+	return &FileDTO{
+		ID:        file.ID,
+		Name:      file.Name,
+		Type:      file.Type,
+		FullPath:  file.FullPath.String(),
+		ParentID:  file.ParentID,
+		MimeType:  file.MimeType,
+		Size:      file.Size,
+		CreatedAt: file.CreatedAt,
+		UpdatedAt: file.UpdatedAt,
+	}
+}
+
+func ToResponseTrash(items []*domain.TrashItem) *ResponseTrash {
+	var answer = make([]*TrashItemDTO, len(items))
+	for index, value := range items {
+		answer[index] = DtoTrashItem(value)
+	}
+
+	return &ResponseTrash{
+		Status: "success",
+		Time:   time.Now(),
+		Items:  answer,
+	}
+}
+
+func ToResponseRestoredItem(file *domain.File) *ResponseRestoredItem {
+	return &ResponseRestoredItem{
+		Status: "success",
+		Time:   time.Now(),
+		File:   DtoFile(file),
+	}
+}
+
+func ToResponseTrashSettings(settings *domain.TrashSettings) *ResponseTrashSettings {
+	return &ResponseTrashSettings{
+		Status:   "success",
+		Time:     time.Now(),
+		Settings: &TrashSettingsDTO{RetentionDays: settings.RetentionDays},
+	}
+}
+
+func ToResponseSuccess(message string) *ResponseSuccess {
+	return &ResponseSuccess{
+		Status:  "success",
+		Time:    time.Now(),
+		Message: message,
+	}
+}
@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"path/filepath"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -11,25 +13,68 @@ import (
 	"go-storage/internal/config"
 	"go-storage/internal/delivery/http/handlers/hdAuth"
 	"go-storage/internal/delivery/http/handlers/hdCompany"
+	"go-storage/internal/delivery/http/handlers/hdFileAccess"
 	"go-storage/internal/delivery/http/handlers/hdFileFolder"
+	"go-storage/internal/delivery/http/handlers/hdFileMetadata"
+	"go-storage/internal/delivery/http/handlers/hdFileTypePolicy"
+	"go-storage/internal/delivery/http/handlers/hdLifecycle"
+	"go-storage/internal/delivery/http/handlers/hdQuota"
+	"go-storage/internal/delivery/http/handlers/hdReconcile"
+	"go-storage/internal/delivery/http/handlers/hdSearch"
+	"go-storage/internal/delivery/http/handlers/hdShare"
+	"go-storage/internal/delivery/http/handlers/hdTrash"
 	"go-storage/internal/delivery/http/handlers/hdUser"
 	"go-storage/internal/delivery/http/middleware"
+	"go-storage/internal/repository/encrypted"
+	"go-storage/internal/repository/localfs"
 	"go-storage/internal/repository/minio"
 	"go-storage/internal/repository/postgres/rpAuth"
 	"go-storage/internal/repository/postgres/rpChunkedUpload"
 	"go-storage/internal/repository/postgres/rpCompany"
+	"go-storage/internal/repository/postgres/rpCompanyKeys"
+	"go-storage/internal/repository/postgres/rpFileAccess"
+	"go-storage/internal/repository/postgres/rpFileContents"
+	"go-storage/internal/repository/postgres/rpFileMetadata"
+	"go-storage/internal/repository/postgres/rpFileRenditions"
+	"go-storage/internal/repository/postgres/rpFileTypePolicy"
+	"go-storage/internal/repository/postgres/rpFileVersions"
 	"go-storage/internal/repository/postgres/rpFiles"
+	"go-storage/internal/repository/postgres/rpFolderCopyJobs"
+	"go-storage/internal/repository/postgres/rpFolderDeleteJobs"
+	"go-storage/internal/repository/postgres/rpLifecycleRules"
+	"go-storage/internal/repository/postgres/rpPresignedUploads"
+	"go-storage/internal/repository/postgres/rpQuota"
+	"go-storage/internal/repository/postgres/rpShareLinks"
+	"go-storage/internal/repository/postgres/rpTrash"
 	"go-storage/internal/repository/postgres/rpUser"
+	"go-storage/internal/repository/tiered"
 	"go-storage/internal/usecase/ucAuthUser"
 	"go-storage/internal/usecase/ucCompany"
+	"go-storage/internal/usecase/ucFileAccess"
 	"go-storage/internal/usecase/ucFileFolder"
+	"go-storage/internal/usecase/ucFileMetadata"
+	"go-storage/internal/usecase/ucFileTypePolicy"
+	"go-storage/internal/usecase/ucLifecycle"
+	"go-storage/internal/usecase/ucQuota"
+	"go-storage/internal/usecase/ucReconcile"
+	"go-storage/internal/usecase/ucSearch"
+	"go-storage/internal/usecase/ucShare"
+	"go-storage/internal/usecase/ucTrash"
 	"go-storage/internal/usecase/ucUser"
 	"go-storage/pkg/logger"
+	"go-storage/pkg/scanner"
 	"go-storage/pkg/storage"
 )
 
 func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 	r := gin.Default()
+
+	// Client addresses are checked by share links, so X-Forwarded-For is only
+	// believed when it comes from our own proxy.
+	if err := r.SetTrustedProxies(cnf.App.TrustedProxies); err != nil {
+		panic("Failed to set trusted proxies: " + err.Error())
+	}
+
 	r.Use(middleware.Logger(log), middleware.Config(cnf))
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -37,34 +82,117 @@ func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 	var CompanyRepo = rpCompany.NewRepository(db)
 	var AuthRepo = rpAuth.NewRepositoryAuth(db)
 	var UserRepo = rpUser.NewRepository(db)
-	// Initialize MinIO client
-	minioClient, err := storage.NewMinIOClient(cnf.Minio)
+	// Initialize object storage, MinIO or the local disk
+	StorageRepo, err := newObjectStorage(cnf.Storage.Backend, cnf.Minio.BucketName, cnf.Storage.LocalPath, cnf.Minio)
 	if err != nil {
-		panic("Failed to initialize MinIO client: " + err.Error())
+		panic("Failed to initialize storage: " + err.Error())
+	}
+
+	// Add the cold tier lifecycle rules move files to when one is configured
+	var TierStorage ucLifecycle.TierStorage
+	if cnf.Storage.ColdBackend != "" {
+		// Moving an object onto the tier it is on would delete it
+		sameBucket := cnf.Storage.Backend == config.StorageBackendMinio && cnf.Storage.ColdBucketName == cnf.Minio.BucketName
+		sameDir := cnf.Storage.Backend == config.StorageBackendLocalFS && filepath.Clean(cnf.Storage.ColdLocalPath) == filepath.Clean(cnf.Storage.LocalPath)
+		if cnf.Storage.ColdBackend == cnf.Storage.Backend && (sameBucket || sameDir) {
+			panic("Cold storage must not share the bucket or directory of the primary storage")
+		}
+
+		coldStorage, err := newObjectStorage(cnf.Storage.ColdBackend, cnf.Storage.ColdBucketName, cnf.Storage.ColdLocalPath, cnf.Minio)
+		if err != nil {
+			panic("Failed to initialize cold storage: " + err.Error())
+		}
+		tieredStorage := tiered.NewStorageRepository(StorageRepo, coldStorage)
+		StorageRepo, TierStorage = tieredStorage, tieredStorage
 	}
 
-	// Ensure bucket exists
-	if err := storage.EnsureBucket(context.Background(), minioClient, cnf.Minio.BucketName); err != nil {
-		panic("Failed to ensure bucket exists: " + err.Error())
+	// Initialize malware scanner, nil when scanning is turned off
+	fileScanner, err := scanner.New(cnf.FileServer.ScanBackend, cnf.FileServer.ScanClamdAddress, cnf.FileServer.ScanTimeout)
+	if err != nil {
+		panic("Failed to initialize file scanner: " + err.Error())
 	}
 
 	var FilesRepo = rpFiles.NewRepository(db)
 	var ChunkedUploadRepo = rpChunkedUpload.NewRepository(db)
-	var StorageRepo = minio.NewStorageRepository(minioClient, cnf.Minio.BucketName)
+	var FileVersionRepo = rpFileVersions.NewRepository(db)
+	var PresignedUploadRepo = rpPresignedUploads.NewRepository(db)
+	var TrashRepo = rpTrash.NewRepository(db)
+	var FolderDeleteJobRepo = rpFolderDeleteJobs.NewRepository(db)
+	var FolderCopyJobRepo = rpFolderCopyJobs.NewRepository(db)
+	var FileContentsRepo = rpFileContents.NewRepository(db)
+	var FileMetadataRepo = rpFileMetadata.NewRepository(db)
+	var QuotaRepo = rpQuota.NewRepository(db)
+	var ShareLinkRepo = rpShareLinks.NewRepository(db)
+	var FileAccessRepo = rpFileAccess.NewRepository(db)
+	var FileRenditionRepo = rpFileRenditions.NewRepository(db)
+	var FileTypePolicyRepo = rpFileTypePolicy.NewRepository(db)
+	var CompanyKeysRepo = rpCompanyKeys.NewRepository(db)
+	var LifecycleRulesRepo = rpLifecycleRules.NewRepository(db)
+
+	// Encrypt stored objects with a data key per company when master keys are set
+	if len(cnf.Encryption.MasterKeys) > 0 {
+		keyring, err := encrypted.NewKeyring(CompanyKeysRepo, cnf.Encryption.MasterKeys)
+		if err != nil {
+			panic("Failed to initialize storage encryption: " + err.Error())
+		}
+		StorageRepo = encrypted.NewStorageRepository(StorageRepo, keyring)
+
+		// Rewrap the data keys after the master key was rotated
+		go keyring.StartRewrap(context.Background(), log)
+	}
 
 	var CompanyUseCase = ucCompany.NewUseCase(CompanyRepo)
 	var AuthUseCase = ucAuthUser.NewUseCaseAuth(AuthRepo)
 	var UserUseCase = ucUser.NewUseCaseUser(UserRepo, AuthRepo)
 	// Initialize file system UseCase
-	var FileFolderUseCase = ucFileFolder.NewUseCaseFileFolder(FilesRepo, StorageRepo, ChunkedUploadRepo, &cnf.FileServer)
+	var FileFolderUseCase = ucFileFolder.NewUseCaseFileFolder(FilesRepo, StorageRepo, ChunkedUploadRepo, FileVersionRepo, PresignedUploadRepo, FolderDeleteJobRepo, FolderCopyJobRepo, TrashRepo, FileContentsRepo, FileMetadataRepo, QuotaRepo, FileAccessRepo, FileRenditionRepo, FileTypePolicyRepo, fileScanner, &cnf.FileServer)
+	var TrashUseCase = ucTrash.NewUseCaseTrash(TrashRepo, FilesRepo, StorageRepo, FileVersionRepo, &cnf.FileServer)
+	var ReconcileUseCase = ucReconcile.NewUseCaseReconcile(FilesRepo, StorageRepo, &cnf.FileServer)
+	var FileMetadataUseCase = ucFileMetadata.NewUseCaseFileMetadata(FilesRepo, FileMetadataRepo, FileAccessRepo)
+	var QuotaUseCase = ucQuota.NewUseCaseQuota(QuotaRepo)
+	var ShareUseCase = ucShare.NewUseCaseShare(ShareLinkRepo, FilesRepo, FileAccessRepo, StorageRepo)
+	var FileAccessUseCase = ucFileAccess.NewUseCaseFileAccess(FileAccessRepo, FilesRepo)
+	var FileTypePolicyUseCase = ucFileTypePolicy.NewUseCaseFileTypePolicy(FileTypePolicyRepo)
+	var SearchUseCase = ucSearch.NewUseCaseSearch(FilesRepo, FileContentsRepo, StorageRepo, &cnf.FileServer)
+	var LifecycleUseCase = ucLifecycle.NewUseCaseLifecycle(LifecycleRulesRepo, FilesRepo, TierStorage, &cnf.FileServer)
+
+	// Expire stale chunked upload sessions and release their storage
+	go FileFolderUseCase.StartJanitor(context.Background(), log)
+
+	// Run recursive folder deletions that were too large to finish within the request
+	go FileFolderUseCase.StartFolderJobWorker(context.Background(), log)
+
+	// Scan new content for malware and quarantine infected files
+	go FileFolderUseCase.StartScanWorker(context.Background(), log)
+
+	// Permanently delete trash items older than the company retention window
+	go TrashUseCase.StartPurger(context.Background(), log)
+
+	// Extract the text of new and changed files for content search
+	go SearchUseCase.StartContentIndexer(context.Background(), log)
+
+	// Move idle files to cold storage and old files to the trash by the lifecycle rules
+	go LifecycleUseCase.StartWorker(context.Background(), log)
 
 	var CompanyHandler = hdCompany.NewHandlerCompany(CompanyUseCase)
 	var AuthHandler = hdAuth.NewHandlerAuth(UserUseCase, AuthUseCase)
 	var UserHandler = hdUser.NewHandlerUser(UserUseCase, AuthUseCase)
 	var FileFolderHandler = hdFileFolder.NewHandlerFileFolder(FileFolderUseCase)
+	var TrashHandler = hdTrash.NewHandlerTrash(TrashUseCase)
+	var ReconcileHandler = hdReconcile.NewHandlerReconcile(ReconcileUseCase)
+	var SearchHandler = hdSearch.NewHandlerSearch(SearchUseCase)
+	var FileMetadataHandler = hdFileMetadata.NewHandlerFileMetadata(FileMetadataUseCase)
+	var QuotaHandler = hdQuota.NewHandlerQuota(QuotaUseCase)
+	var ShareHandler = hdShare.NewHandlerShare(ShareUseCase)
+	var FileAccessHandler = hdFileAccess.NewHandlerFileAccess(FileAccessUseCase)
+	var FileTypePolicyHandler = hdFileTypePolicy.NewHandlerFileTypePolicy(FileTypePolicyUseCase)
+	var LifecycleHandler = hdLifecycle.NewHandlerLifecycle(LifecycleUseCase)
 
 	authMiddleware := middleware.NewAuthMiddleware(AuthUseCase)
 
+	// Public share links, opened without an account
+	r.GET("/s/:token", ShareHandler.OpenShare)
+
 	api := r.Group("/api/v1/")
 
 	auth := api.Group("/auth")
@@ -88,6 +216,7 @@ func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 			companyAdmin.GET("/:id", CompanyHandler.GetCompanyById)
 			companyAdmin.DELETE("/:id", CompanyHandler.DeleteCompany)
 			companyAdmin.PUT("/:id", CompanyHandler.UpdateCompany)
+			companyAdmin.PUT("/:id/quota", QuotaHandler.SetCompanyQuota)
 		}
 
 		companyOwn := companies.Group("/")
@@ -95,6 +224,7 @@ func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 		{
 			companyOwn.GET("/me", CompanyHandler.GetMyCompany)
 			companyOwn.PUT("/me", CompanyHandler.UpdateMyCompany)
+			companyOwn.GET("/me/usage", QuotaHandler.GetMyUsage)
 		}
 	}
 
@@ -115,6 +245,13 @@ func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 			userCompany.GET("/company", UserHandler.GetAllUsersOfYourCompany)
 		}
 
+		// Storage quotas of users in your company
+		userQuota := users.Group("/")
+		userQuota.Use(authMiddleware.RequireAnyPermission([]string{"user:manage_company"}))
+		{
+			userQuota.PUT("/:id/quota", QuotaHandler.SetUserQuota)
+		}
+
 		// Super admin user management endpoints
 		userAdmin := users.Group("/")
 		userAdmin.Use(authMiddleware.RequireAnyPermission([]string{"user:create", "user:read", "user:update", "user:delete"}))
@@ -139,10 +276,34 @@ func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 		files.POST("/upload", FileFolderHandler.UploadFile)
 		files.GET("/:id", FileFolderHandler.GetFileInfo)
 		files.GET("/:id/download", FileFolderHandler.DownloadFile)
+		files.GET("/:id/download-url", FileFolderHandler.GetDownloadURL)
+		files.GET("/:id/thumbnail", FileFolderHandler.GetThumbnail)
 		files.PUT("/:id/rename", FileFolderHandler.RenameFile)
 		files.PUT("/:id/move", FileFolderHandler.MoveFile)
+		files.POST("/:id/copy", FileFolderHandler.CopyFile)
 		files.DELETE("/:id", FileFolderHandler.DeleteFile)
 
+		// File versions
+		files.GET("/:id/versions", FileFolderHandler.GetFileVersions)
+		files.GET("/:id/versions/:version/download", FileFolderHandler.DownloadFileVersion)
+		files.POST("/:id/versions/:version/restore", FileFolderHandler.RestoreFileVersion)
+
+		// Tags and custom metadata, for files and folders alike
+		files.GET("/:id/metadata", FileMetadataHandler.GetMetadata)
+		files.PUT("/:id/metadata", FileMetadataHandler.ReplaceMetadata)
+		files.PATCH("/:id/metadata", FileMetadataHandler.UpdateMetadata)
+		files.DELETE("/:id/metadata", FileMetadataHandler.DeleteMetadata)
+		files.GET("/tags", FileMetadataHandler.ListTags)
+		files.POST("/tags/bulk", FileMetadataHandler.BulkUpdateTags)
+
+		// Public share links to files and folders
+		files.POST("/:id/share", ShareHandler.CreateLink)
+
+		// Per-item access, inherited by everything below a folder
+		files.GET("/:id/access", FileAccessHandler.ListAccess)
+		files.PUT("/:id/access", FileAccessHandler.GrantAccess)
+		files.DELETE("/:id/access", FileAccessHandler.RevokeAccess)
+
 		// Upload strategy
 		files.GET("/upload-strategy", FileFolderHandler.GetUploadStrategy)
 
@@ -156,10 +317,24 @@ func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 			chunked.DELETE("/:uploadId/abort", FileFolderHandler.AbortChunkedUpload)
 		}
 
+		// Direct-to-storage upload through a presigned URL
+		presigned := files.Group("/presigned")
+		{
+			presigned.POST("/init", FileFolderHandler.InitPresignedUpload)
+			presigned.POST("/:uploadId/finalize", FileFolderHandler.FinalizePresignedUpload)
+		}
+
 		// Resource monitoring
 		files.GET("/stats", FileFolderHandler.GetResourceStats)
 	}
 
+	// The janitor cleans up after every company, so its stats are for system admins
+	janitor := protected.Group("/files/janitor")
+	janitor.Use(authMiddleware.RequireAnyPermission([]string{"company:read:all", "company:update:all"}))
+	{
+		janitor.GET("/stats", FileFolderHandler.GetJanitorStats)
+	}
+
 	folders := protected.Group("/folders")
 	folders.Use(authMiddleware.RequireAnyPermission([]string{"file:read", "file:write", "file:delete"}))
 	{
@@ -167,8 +342,128 @@ func Router(log logger.Logger, db *sql.DB, cnf config.Config) *gin.Engine {
 		folders.POST("/contents", FileFolderHandler.GetFolderContents)
 		folders.PUT("/:path/rename", FileFolderHandler.FolderRename)
 		folders.PUT("/:path/move", FileFolderHandler.MoveFolder)
+		folders.POST("/:path/copy", FileFolderHandler.CopyFolder)
 		folders.DELETE("/:path", FileFolderHandler.DeleteFolder)
+		folders.GET("/:path/archive", FileFolderHandler.DownloadFolderArchive)
+	}
+
+	shares := protected.Group("/shares")
+	shares.Use(authMiddleware.RequireAnyPermission([]string{"file:read", "file:write", "file:delete"}))
+	{
+		shares.GET("", ShareHandler.ListLinks)
+		shares.DELETE("/:id", ShareHandler.RevokeLink)
+	}
+
+	accessSettings := protected.Group("/access/settings")
+	accessSettings.Use(authMiddleware.RequireAnyPermission([]string{"company:update:own", "company:update:all"}))
+	{
+		accessSettings.GET("", FileAccessHandler.GetSettings)
+		accessSettings.PUT("", FileAccessHandler.UpdateSettings)
+	}
+
+	fileTypePolicy := protected.Group("/file-types/policy")
+	fileTypePolicy.Use(authMiddleware.RequireAnyPermission([]string{"company:update:own", "company:update:all"}))
+	{
+		fileTypePolicy.GET("", FileTypePolicyHandler.GetPolicy)
+		fileTypePolicy.PUT("", FileTypePolicyHandler.UpdatePolicy)
+	}
+
+	scans := protected.Group("/scans")
+	scans.Use(authMiddleware.RequireAnyPermission([]string{"company:update:own", "company:update:all"}))
+	{
+		scans.GET("", FileFolderHandler.GetScanFindings)
+		scans.POST("/:id/rescan", FileFolderHandler.RescanFile)
+		scans.POST("/:id/release", FileFolderHandler.ReleaseFile)
+	}
+
+	search := protected.Group("/search")
+	search.Use(authMiddleware.RequireAnyPermission([]string{"file:read", "file:write", "file:delete"}))
+	{
+		search.GET("", SearchHandler.Search)
+		search.GET("/content", SearchHandler.SearchContent)
+	}
+
+	contentIndex := protected.Group("/search/content")
+	contentIndex.Use(authMiddleware.RequireAnyPermission([]string{"company:update:own", "company:update:all"}))
+	{
+		contentIndex.POST("/reindex", SearchHandler.ReindexContent)
+		contentIndex.GET("/status", SearchHandler.GetContentIndexStatus)
+	}
+
+	jobs := protected.Group("/jobs")
+	jobs.Use(authMiddleware.RequireAnyPermission([]string{"file:read", "file:write", "file:delete"}))
+	{
+		jobs.GET("/folder-delete/:id", FileFolderHandler.GetFolderDeleteJob)
+		jobs.POST("/folder-delete/:id/cancel", FileFolderHandler.CancelFolderDeleteJob)
+		jobs.GET("/folder-copy/:id", FileFolderHandler.GetFolderCopyJob)
+		jobs.POST("/folder-copy/:id/cancel", FileFolderHandler.CancelFolderCopyJob)
+	}
+
+	trash := protected.Group("/trash")
+	{
+		trashList := trash.Group("/")
+		trashList.Use(authMiddleware.RequireAnyPermission([]string{"file:read", "file:write", "file:delete"}))
+		{
+			trashList.GET("/", TrashHandler.GetTrash)
+		}
+
+		trashRestore := trash.Group("/")
+		trashRestore.Use(authMiddleware.RequireAnyPermission([]string{"file:write"}))
+		{
+			trashRestore.POST("/:id/restore", TrashHandler.RestoreItem)
+		}
+
+		trashPurge := trash.Group("/")
+		trashPurge.Use(authMiddleware.RequireAnyPermission([]string{"file:delete"}))
+		{
+			trashPurge.DELETE("/:id", TrashHandler.PurgeItem)
+		}
+
+		trashSettings := trash.Group("/settings")
+		trashSettings.Use(authMiddleware.RequireAnyPermission([]string{"company:update:own", "company:update:all"}))
+		{
+			trashSettings.GET("", TrashHandler.GetSettings)
+			trashSettings.PUT("", TrashHandler.UpdateSettings)
+		}
+	}
+
+	// Storage maintenance endpoints
+	storageAdmin := protected.Group("/storage")
+	storageAdmin.Use(authMiddleware.RequireAnyPermission([]string{"company:update:own", "company:update:all"}))
+	{
+		storageAdmin.POST("/reconcile", ReconcileHandler.Reconcile)
+	}
+
+	lifecycleRules := protected.Group("/lifecycle/rules")
+	lifecycleRules.Use(authMiddleware.RequireAnyPermission([]string{"company:update:own", "company:update:all"}))
+	{
+		lifecycleRules.GET("", LifecycleHandler.ListRules)
+		lifecycleRules.POST("", LifecycleHandler.CreateRule)
+		lifecycleRules.PUT("/:id", LifecycleHandler.UpdateRule)
+		lifecycleRules.DELETE("/:id", LifecycleHandler.DeleteRule)
 	}
 
 	return r
 }
+
+// newObjectStorage opens the storage of a tier, a bucket on the MinIO server
+// or a directory on the local disk.
+func newObjectStorage(backend, bucketName, localPath string, minioCnf config.Minio) (encrypted.Storage, error) {
+	switch backend {
+	case config.StorageBackendMinio:
+		minioClient, err := storage.NewMinIOClient(minioCnf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize MinIO client: %w", err)
+		}
+
+		// Ensure bucket exists
+		if err := storage.EnsureBucket(context.Background(), minioClient, bucketName); err != nil {
+			return nil, fmt.Errorf("failed to ensure bucket exists: %w", err)
+		}
+		return minio.NewStorageRepository(minioClient, bucketName), nil
+	case config.StorageBackendLocalFS:
+		return localfs.NewStorageRepository(localPath)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+type ArchiveFormat string
+
+const (
+	ArchiveFormatZip   ArchiveFormat = "zip"
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+)
+
+func (f ArchiveFormat) IsValid() bool {
+	return f == ArchiveFormatZip || f == ArchiveFormatTarGz
+}
+
+func (f ArchiveFormat) ContentType() string {
+	if f == ArchiveFormatTarGz {
+		return "application/gzip"
+	}
+	return "application/zip"
+}
+
+// ArchiveEntry is a file or folder placed in an archive under Name, a slash
+// separated path relative to the archive root.
+type ArchiveEntry struct {
+	Name string
+	File *File
+}
+
+// FolderArchive is a folder subtree ready to be streamed as a single archive.
+type FolderArchive struct {
+	Name      string
+	Format    ArchiveFormat
+	Entries   []*ArchiveEntry
+	TotalSize int64
+}
+
+func (a *FolderArchive) FileName() string {
+	return a.Name + "." + string(a.Format)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "fmt"
+
+// ByteRange is an inclusive range of byte offsets within a stored object.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+func (r ByteRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// ContentRange formats the range as the value of a Content-Range header.
+func (r ByteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End, size)
+}
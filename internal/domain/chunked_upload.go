@@ -14,6 +14,13 @@ const (
 	ChunkedUploadStatusExpired   ChunkedUploadStatus = "expired"
 )
 
+// Limits of S3 multipart uploads, which back chunked uploads: every part but
+// the last must be at least MinMultipartPartSize bytes.
+const (
+	MinMultipartPartSize int64 = 5 * 1024 * 1024
+	MaxMultipartParts          = 10000
+)
+
 type ChunkedUpload struct {
 	ID             string
 	FileName       string
@@ -30,6 +37,11 @@ type ChunkedUpload struct {
 
 	MimeType string
 
+	// StorageKey is the object key the upload is assembled into and
+	// StorageUploadID the multipart upload ID assigned by storage.
+	StorageKey      string
+	StorageUploadID string
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	ExpiresAt time.Time
@@ -41,6 +53,7 @@ type ChunkInfo struct {
 	Index      int
 	Size       int64
 	ETag       string
+	Checksum   string
 	Uploaded   bool
 	UploadedAt time.Time
 	Retries    int
@@ -104,6 +117,15 @@ func (cu *ChunkedUpload) AddChunk(chunkIndex int, size int64, etag string) {
 	cu.UpdatedAt = time.Now()
 }
 
+// ExpectedChunkSize returns the size chunk chunkIndex must have: ChunkSize for
+// every chunk except the last one, which holds the remainder.
+func (cu *ChunkedUpload) ExpectedChunkSize(chunkIndex int) int64 {
+	if chunkIndex == cu.TotalChunks-1 {
+		return cu.TotalSize - int64(chunkIndex)*cu.ChunkSize
+	}
+	return cu.ChunkSize
+}
+
 func (cu *ChunkedUpload) GetMissingChunks() []int {
 	missing := make([]int, 0)
 
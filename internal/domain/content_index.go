@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type ContentIndexStatus string
+
+const (
+	ContentIndexPending ContentIndexStatus = "pending"
+	ContentIndexRunning ContentIndexStatus = "running"
+	ContentIndexIndexed ContentIndexStatus = "indexed"
+	// ContentIndexSkipped marks files without extractable text, such as images or oversized files.
+	ContentIndexSkipped ContentIndexStatus = "skipped"
+	ContentIndexFailed  ContentIndexStatus = "failed"
+)
+
+// ContentIndexTask is a file whose text is to be extracted. QueuedAt tells
+// the claimed request apart from a newer one made while it was running.
+type ContentIndexTask struct {
+	FileID    string
+	CompanyID string
+	QueuedAt  time.Time
+
+	Status  ContentIndexStatus
+	Content *string
+	Error   *string
+}
+
+// ContentIndexStats counts the company files in each indexing state.
+type ContentIndexStats struct {
+	Pending int
+	Indexed int
+	Skipped int
+	Failed  int
+}
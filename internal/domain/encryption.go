@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// CompanyKey is the data key encrypting the stored objects of a company,
+// wrapped by the master key MasterKeyID.
+type CompanyKey struct {
+	CompanyID   string
+	WrappedKey  []byte
+	MasterKeyID string
+	CreatedAt   time.Time
+	RotatedAt   *time.Time
+}
@@ -19,6 +19,9 @@ type File struct {
 	Size        *int64
 	Hash        *string
 	StoragePath *string
+	ScanStatus  ScanStatus
+	// StorageClass is the tier holding the content at StoragePath.
+	StorageClass StorageClass
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -31,6 +34,9 @@ type StorageFileInfo struct {
 	MimeType     string
 	ETag         string
 	LastModified time.Time
+
+	// ChecksumSHA256 is the base64 encoded SHA-256 verified by storage, if any.
+	ChecksumSHA256 string
 }
 
 func (f *File) Validate() error {
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// AccessRole is what a user may do with a file or folder. Roles granted on a
+// folder apply to everything below it.
+type AccessRole string
+
+const (
+	AccessNone   AccessRole = ""
+	AccessViewer AccessRole = "viewer"
+	AccessEditor AccessRole = "editor"
+	AccessOwner  AccessRole = "owner"
+)
+
+var accessRoleRanks = map[AccessRole]int{
+	AccessNone:   0,
+	AccessViewer: 1,
+	AccessEditor: 2,
+	AccessOwner:  3,
+}
+
+func (r AccessRole) IsValid() bool {
+	return r == AccessViewer || r == AccessEditor || r == AccessOwner
+}
+
+// Allows reports whether the role includes everything required may do.
+func (r AccessRole) Allows(required AccessRole) bool {
+	return accessRoleRanks[r] >= accessRoleRanks[required]
+}
+
+// AccessRoleFromRank is the inverse of the ranking used to compare roles.
+func AccessRoleFromRank(rank int) AccessRole {
+	for role, value := range accessRoleRanks {
+		if value == rank {
+			return role
+		}
+	}
+	return AccessNone
+}
+
+// AccessEntry grants a role on a file or folder to a user, or to everyone in
+// the company when UserID is nil. The creator of an item owns it without an
+// entry.
+type AccessEntry struct {
+	ID        string
+	FileID    string
+	CompanyID string
+	UserID    *string
+	Role      AccessRole
+	GrantedBy string
+	CreatedAt time.Time
+}
+
+// SharingSettings decide who may see what a company's users create.
+type SharingSettings struct {
+	CompanyID string
+
+	// ShareNewFiles gives everyone in the company editor access to new
+	// top-level files and folders. Items are otherwise private to their creator.
+	ShareNewFiles bool
+}
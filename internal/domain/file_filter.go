@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// FileFilter narrows a listing or search down to matching items. Unset
+// fields are not applied.
+type FileFilter struct {
+	Type        *FileType
+	MimeType    string
+	MinSize     *int64
+	MaxSize     *int64
+	UserCreated string
+
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	ModifiedAfter  *time.Time
+	ModifiedBefore *time.Time
+
+	// Tags and Metadata match items carrying all the given tags and key/value pairs.
+	Tags     []string
+	Metadata map[string]string
+
+	// VisibleTo limits the results to items the user has access to, and to
+	// the folders leading to such items.
+	VisibleTo string
+}
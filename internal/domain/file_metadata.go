@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxFileTags          = 50
+	MaxTagLength         = 64
+	MaxMetadataKeys      = 50
+	MaxMetadataKeyLength = 64
+	MaxMetadataValueSize = 1024
+)
+
+// FileMetadata holds the user-defined tags and key/value pairs of a file or
+// folder. It stays with the item across moves and renames and is copied
+// along with it.
+type FileMetadata struct {
+	FileID    string
+	CompanyID string
+	Tags      []string
+	Metadata  map[string]string
+	UpdatedAt time.Time
+}
+
+// MetadataPatch changes part of the metadata of an item. Metadata keys set
+// to nil are removed.
+type MetadataPatch struct {
+	AddTags    []string
+	RemoveTags []string
+	Metadata   map[string]*string
+}
+
+// TagCount is a tag in use and the number of items carrying it.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// NormalizeTags trims and lowercases tags so that "Q3 " and "q3" are the
+// same tag, drops duplicates and sorts the result.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, errors.New("tag cannot be empty")
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+// ValidateMetadataKey checks a metadata key. Keys are case sensitive.
+func ValidateMetadataKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return errors.New("metadata key cannot be empty")
+	}
+	if utf8.RuneCountInString(key) > MaxMetadataKeyLength {
+		return fmt.Errorf("metadata key %q is longer than %d characters", key, MaxMetadataKeyLength)
+	}
+	return nil
+}
+
+// Normalize validates the metadata and normalizes its tags.
+func (m *FileMetadata) Normalize() error {
+	tags, err := NormalizeTags(m.Tags)
+	if err != nil {
+		return err
+	}
+	if len(tags) > MaxFileTags {
+		return fmt.Errorf("an item can have at most %d tags", MaxFileTags)
+	}
+	m.Tags = tags
+
+	if m.Metadata == nil {
+		m.Metadata = map[string]string{}
+	}
+	if len(m.Metadata) > MaxMetadataKeys {
+		return fmt.Errorf("an item can have at most %d metadata keys", MaxMetadataKeys)
+	}
+	for key, value := range m.Metadata {
+		if err := ValidateMetadataKey(key); err != nil {
+			return err
+		}
+		if len(value) > MaxMetadataValueSize {
+			return fmt.Errorf("value of metadata key %q is larger than %d bytes", key, MaxMetadataValueSize)
+		}
+	}
+
+	return nil
+}
+
+// Apply changes the metadata as described by the patch and validates the result.
+func (m *FileMetadata) Apply(patch *MetadataPatch) error {
+	remove, err := NormalizeTags(patch.RemoveTags)
+	if err != nil {
+		return err
+	}
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+
+	tags := make([]string, 0, len(m.Tags)+len(patch.AddTags))
+	tags = append(tags, m.Tags...)
+	tags = append(tags, patch.AddTags...)
+
+	m.Tags = tags[:0]
+	for _, tag := range tags {
+		if !removed[strings.ToLower(strings.TrimSpace(tag))] {
+			m.Tags = append(m.Tags, tag)
+		}
+	}
+
+	if m.Metadata == nil {
+		m.Metadata = map[string]string{}
+	}
+	for key, value := range patch.Metadata {
+		if value == nil {
+			delete(m.Metadata, key)
+		} else {
+			m.Metadata[key] = *value
+		}
+	}
+
+	return m.Normalize()
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const MaxFileTypePolicyEntries = 200
+
+// FileTypePolicy limits the kinds of files a company's users can upload.
+// MIME types may end in "/*" to match a whole family, such as "image/*".
+// Extensions are stored in lower case without the leading dot. Empty allow
+// lists allow everything that is not denied.
+type FileTypePolicy struct {
+	CompanyID string
+
+	AllowedMimeTypes  []string
+	DeniedMimeTypes   []string
+	AllowedExtensions []string
+	DeniedExtensions  []string
+}
+
+// Normalize validates the policy and brings its entries into stored form.
+func (p *FileTypePolicy) Normalize() error {
+	lists := []*[]string{&p.AllowedMimeTypes, &p.DeniedMimeTypes}
+	for _, list := range lists {
+		normalized, err := normalizePolicyList(*list, normalizeMimePattern)
+		if err != nil {
+			return err
+		}
+		*list = normalized
+	}
+
+	lists = []*[]string{&p.AllowedExtensions, &p.DeniedExtensions}
+	for _, list := range lists {
+		normalized, err := normalizePolicyList(*list, normalizeExtension)
+		if err != nil {
+			return err
+		}
+		*list = normalized
+	}
+
+	return nil
+}
+
+// Check tells whether a file may be uploaded under filename. The first MIME
+// type is the one the file is stored as and must be allowed; none of the
+// others, such as the type declared by the client, may be denied.
+func (p *FileTypePolicy) Check(filename string, mimeTypes ...string) error {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	if slices.Contains(p.DeniedExtensions, ext) {
+		return fmt.Errorf("files with the extension .%s are not allowed", ext)
+	}
+	if len(p.AllowedExtensions) > 0 && !slices.Contains(p.AllowedExtensions, ext) {
+		if ext == "" {
+			return fmt.Errorf("files without an extension are not allowed")
+		}
+		return fmt.Errorf("files with the extension .%s are not allowed", ext)
+	}
+
+	for i, mimeType := range mimeTypes {
+		if mimeType == "" {
+			continue
+		}
+		if matchesMimePattern(p.DeniedMimeTypes, mimeType) {
+			return fmt.Errorf("files of type %s are not allowed", mimeType)
+		}
+		if i == 0 && len(p.AllowedMimeTypes) > 0 && !matchesMimePattern(p.AllowedMimeTypes, mimeType) {
+			return fmt.Errorf("files of type %s are not allowed", mimeType)
+		}
+	}
+
+	return nil
+}
+
+func normalizePolicyList(entries []string, normalize func(string) (string, error)) ([]string, error) {
+	if len(entries) > MaxFileTypePolicyEntries {
+		return nil, fmt.Errorf("a policy list can have at most %d entries", MaxFileTypePolicyEntries)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		normalized, err := normalize(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+func normalizeMimePattern(pattern string) (string, error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if family, ok := strings.CutSuffix(pattern, "/*"); ok {
+		if family == "" || strings.ContainsAny(family, "/*; ") {
+			return "", fmt.Errorf("invalid MIME type %q", pattern)
+		}
+		return pattern, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(pattern)
+	if err != nil || len(params) > 0 || !strings.Contains(mediaType, "/") {
+		return "", fmt.Errorf("invalid MIME type %q", pattern)
+	}
+	return mediaType, nil
+}
+
+func normalizeExtension(ext string) (string, error) {
+	ext = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(ext)), ".")
+	if ext == "" || len(ext) > 32 || strings.ContainsAny(ext, "./\\ ") {
+		return "", fmt.Errorf("invalid extension %q", ext)
+	}
+	return ext, nil
+}
+
+func matchesMimePattern(patterns []string, mimeType string) bool {
+	for _, pattern := range patterns {
+		if family, ok := strings.CutSuffix(pattern, "/*"); ok {
+			if strings.HasPrefix(mimeType, family+"/") {
+				return true
+			}
+		} else if pattern == mimeType {
+			return true
+		}
+	}
+	return false
+}
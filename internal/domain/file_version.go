@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+type FileVersion struct {
+	ID           string
+	FileID       string
+	Version      int
+	CompanyID    string
+	UserCreateID string
+
+	MimeType    string
+	Size        int64
+	Hash        *string
+	StoragePath string
+
+	CreatedAt time.Time
+}
+
+func NewFileVersion(file *File, userID string) *FileVersion {
+	version := &FileVersion{
+		FileID:       file.ID,
+		CompanyID:    file.CompanyId,
+		UserCreateID: userID,
+		Hash:         file.Hash,
+		CreatedAt:    time.Now(),
+	}
+
+	if file.MimeType != nil {
+		version.MimeType = *file.MimeType
+	}
+	if file.Size != nil {
+		version.Size = *file.Size
+	}
+	if file.StoragePath != nil {
+		version.StoragePath = *file.StoragePath
+	}
+
+	return version
+}
+
+// ApplyTo makes the version the current content of the file.
+func (v *FileVersion) ApplyTo(file *File) {
+	mimeType := v.MimeType
+	size := v.Size
+	storagePath := v.StoragePath
+
+	file.MimeType = &mimeType
+	file.Size = &size
+	file.Hash = v.Hash
+	file.StoragePath = &storagePath
+	file.UpdatedAt = time.Now()
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// ConflictPolicy decides what a copy does when the target name is taken.
+type ConflictPolicy string
+
+const (
+	ConflictPolicyFail   ConflictPolicy = "fail"
+	ConflictPolicyRename ConflictPolicy = "rename"
+)
+
+func (p ConflictPolicy) IsValid() bool {
+	return p == ConflictPolicyFail || p == ConflictPolicyRename
+}
+
+// FolderCopyJob tracks the copy of a folder tree. Items are copied parents
+// first, so a cancelled or failed job leaves a consistent partial tree, which
+// is moved to the trash.
+type FolderCopyJob struct {
+	ID           string
+	CompanyID    string
+	UserCreateID string
+	SourceID     string
+	SourcePath   Path
+	TargetPath   Path
+	Status       JobStatus
+
+	// TargetID is the copied top folder, set once it is created.
+	TargetID *string
+
+	TotalItems  int
+	CopiedItems int
+	Error       *string
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+func (j *FolderCopyJob) IsFinished() bool {
+	return j.Status.IsFinished()
+}
+
+// Progress returns the completed share of the job between 0 and 100.
+func (j *FolderCopyJob) Progress() float64 {
+	if j.Status == JobStatusCompleted {
+		return 100
+	}
+	if j.TotalItems == 0 {
+		return 0
+	}
+
+	return float64(j.CopiedItems) / float64(j.TotalItems) * 100
+}
+
+func (j *FolderCopyJob) Finish(status JobStatus, err error) {
+	now := time.Now()
+	j.Status = status
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+	if err != nil {
+		message := err.Error()
+		j.Error = &message
+	}
+}
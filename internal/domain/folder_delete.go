@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// FolderDeleteJob tracks a recursive folder deletion. The subtree is first moved
+// to the trash in one step; permanent jobs then purge it item by item, which is
+// where progress and cancellation matter.
+type FolderDeleteJob struct {
+	ID           string
+	CompanyID    string
+	UserCreateID string
+	FolderID     string
+	FolderPath   Path
+	Permanent    bool
+	Status       JobStatus
+
+	TotalItems   int
+	DeletedItems int
+	PurgedItems  int
+	Error        *string
+
+	// DeletedAt is the deletion time shared by the whole subtree, set once it is in the trash.
+	DeletedAt *time.Time
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+func (j *FolderDeleteJob) IsFinished() bool {
+	return j.Status.IsFinished()
+}
+
+// Progress returns the completed share of the job between 0 and 100.
+func (j *FolderDeleteJob) Progress() float64 {
+	if j.Status == JobStatusCompleted {
+		return 100
+	}
+
+	total := j.TotalItems
+	done := j.DeletedItems
+	if j.Permanent {
+		total *= 2
+		done += j.PurgedItems
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return float64(done) / float64(total) * 100
+}
+
+func (j *FolderDeleteJob) Finish(status JobStatus, err error) {
+	now := time.Now()
+	j.Status = status
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+	if err != nil {
+		message := err.Error()
+		j.Error = &message
+	}
+}
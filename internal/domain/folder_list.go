@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultFolderListLimit = 100
+	MaxFolderListLimit     = 1000
+)
+
+type FolderSort string
+
+const (
+	FolderSortName      FolderSort = "name"
+	FolderSortSize      FolderSort = "size"
+	FolderSortUpdatedAt FolderSort = "updated_at"
+	FolderSortType      FolderSort = "type"
+)
+
+func (s FolderSort) IsValid() bool {
+	switch s {
+	case FolderSortName, FolderSortSize, FolderSortUpdatedAt, FolderSortType:
+		return true
+	}
+	return false
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+func (o SortOrder) IsValid() bool {
+	return o == SortOrderAsc || o == SortOrderDesc
+}
+
+// FolderListQuery selects one page of a folder listing. Only direct children
+// are listed unless Recursive is set.
+type FolderListQuery struct {
+	Path      Path
+	Recursive bool
+	FileFilter
+
+	Sort   FolderSort
+	Order  SortOrder
+	Limit  int
+	Cursor *FolderCursor
+}
+
+// FolderCursor is the position after the last item of a page. It holds every
+// sort key of that item, so the next page continues from it whatever the sort.
+type FolderCursor struct {
+	Sort      FolderSort `json:"s"`
+	Order     SortOrder  `json:"o"`
+	ID        string     `json:"id"`
+	Name      string     `json:"n"`
+	Type      FileType   `json:"t"`
+	Size      int64      `json:"z"`
+	UpdatedAt time.Time  `json:"u"`
+}
+
+func NewFolderCursor(file *File, sort FolderSort, order SortOrder) *FolderCursor {
+	cursor := &FolderCursor{
+		Sort:      sort,
+		Order:     order,
+		ID:        file.ID,
+		Name:      file.Name,
+		Type:      file.Type,
+		UpdatedAt: file.UpdatedAt,
+	}
+	if file.Size != nil {
+		cursor.Size = *file.Size
+	}
+	return cursor
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c *FolderCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeFolderCursor(token string) (*FolderCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor FolderCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+type FolderPage struct {
+	Items      []*File
+	NextCursor string
+}
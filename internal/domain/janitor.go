@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// JanitorRun describes what a single pass of the chunked upload janitor cleaned.
+type JanitorRun struct {
+	StartedAt           time.Time     `json:"started_at"`
+	Duration            time.Duration `json:"duration"`
+	ExpiredSessions     int           `json:"expired_sessions"`
+	DeletedSessions     int64         `json:"deleted_sessions"`
+	AbortedUploads      int           `json:"aborted_uploads"`
+	DeletedChunkObjects int           `json:"deleted_chunk_objects"`
+	Errors              int           `json:"errors"`
+}
+
+// Cleaned reports whether the run removed anything.
+func (r *JanitorRun) Cleaned() bool {
+	return r.ExpiredSessions > 0 || r.DeletedSessions > 0 || r.AbortedUploads > 0 || r.DeletedChunkObjects > 0
+}
+
+// JanitorStats accumulates the janitor runs since the process started.
+type JanitorStats struct {
+	Runs    int         `json:"runs"`
+	LastRun *JanitorRun `json:"last_run,omitempty"`
+	Total   JanitorRun  `json:"total"`
+}
+
+func (s *JanitorStats) Record(run JanitorRun) {
+	s.Runs++
+	s.LastRun = &run
+
+	s.Total.Duration += run.Duration
+	s.Total.ExpiredSessions += run.ExpiredSessions
+	s.Total.DeletedSessions += run.DeletedSessions
+	s.Total.AbortedUploads += run.AbortedUploads
+	s.Total.DeletedChunkObjects += run.DeletedChunkObjects
+	s.Total.Errors += run.Errors
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxLifecycleDays bounds the age a lifecycle rule waits for.
+const MaxLifecycleDays = 3650
+
+// StorageClass is the storage tier that holds the current content of a file.
+type StorageClass string
+
+const (
+	StorageClassHot  StorageClass = "hot"
+	StorageClassCold StorageClass = "cold"
+)
+
+func (c StorageClass) IsValid() bool {
+	return c == StorageClassHot || c == StorageClassCold
+}
+
+// LifecycleAction is what a lifecycle rule does with the files it matches.
+type LifecycleAction string
+
+const (
+	// LifecycleActionTransition moves files not downloaded or changed for
+	// the rule's days to cold storage.
+	LifecycleActionTransition LifecycleAction = "transition"
+	// LifecycleActionDelete moves files not changed for the rule's days to
+	// the trash.
+	LifecycleActionDelete LifecycleAction = "delete"
+)
+
+func (a LifecycleAction) IsValid() bool {
+	return a == LifecycleActionTransition || a == LifecycleActionDelete
+}
+
+// LifecycleRule applies to every file of the company, or to the files below
+// a folder when FolderID is set. A company has at most one rule per action
+// and scope; rules apply independently, so the one matching first wins.
+type LifecycleRule struct {
+	ID        string
+	CompanyID string
+	FolderID  *string
+	// FolderPath is the current path of the folder, read with the rule.
+	FolderPath *Path
+	Action     LifecycleAction
+	Days       int
+
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *LifecycleRule) Validate() error {
+	if !r.Action.IsValid() {
+		return fmt.Errorf("unknown lifecycle action %q", r.Action)
+	}
+	if r.Days < 1 || r.Days > MaxLifecycleDays {
+		return fmt.Errorf("days must be between 1 and %d", MaxLifecycleDays)
+	}
+	return nil
+}
+
+// Cutoff is the time before which files were last used for the rule to
+// apply to them.
+func (r *LifecycleRule) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -r.Days)
+}
+
+// LifecycleStats counts what one pass over the lifecycle rules did.
+type LifecycleStats struct {
+	Transitioned int
+	Trashed      int
+	Failed       int
+}
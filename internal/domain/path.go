@@ -52,6 +52,12 @@ func (p Path) Clean() Path {
 	return Path(path.Clean(string(p)))
 }
 
+// IsValidName reports whether name can be the last element of a path: it is
+// not a dot entry and holds no separator that would lead into another folder.
+func IsValidName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
 func NewPath(str string) (Path, error) {
 	if str == "" {
 		str = "/"
@@ -70,3 +76,18 @@ func NewPath(str string) (Path, error) {
 
 	return p, nil
 }
+
+// Lineage returns the path and the paths of all folders above it, top-most
+// first. The root is not part of it.
+func (p Path) Lineage() []string {
+	if p.IsRoot() {
+		return []string{}
+	}
+
+	segments := strings.Split(strings.TrimPrefix(string(p), "/"), "/")
+	lineage := make([]string, len(segments))
+	for i := range segments {
+		lineage[i] = "/" + strings.Join(segments[:i+1], "/")
+	}
+	return lineage
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// PresignedURL is a short-lived URL granting direct access to a storage object.
+type PresignedURL struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// PresignedUpload is a pending direct-to-storage upload. The client PUTs the
+// content to URL with Headers set and then finalizes the session, which
+// creates the file. URL and Headers are not persisted.
+type PresignedUpload struct {
+	ID           string
+	FileName     string
+	CompanyID    string
+	UserCreateID string
+	TargetPath   Path
+	StorageKey   string
+	MimeType     string
+	Size         int64
+	Checksum     *string
+
+	URL     string
+	Headers map[string]string
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func (u *PresignedUpload) IsExpired() bool {
+	return time.Now().After(u.ExpiresAt)
+}
@@ -0,0 +1,45 @@
+package domain
+
+// StorageQuota limits the storage of a company or user. Unset limits are unlimited.
+type StorageQuota struct {
+	MaxBytes *int64
+	MaxFiles *int64
+}
+
+// StorageUsage is the storage taken by active files and reserved by uploads
+// in progress, along with the quota it counts against.
+type StorageUsage struct {
+	UsedBytes     int64
+	UsedFiles     int64
+	ReservedBytes int64
+	ReservedFiles int64
+	Quota         StorageQuota
+}
+
+// TotalBytes is the used and reserved storage together.
+func (u *StorageUsage) TotalBytes() int64 {
+	return u.UsedBytes + u.ReservedBytes
+}
+
+// TotalFiles is the used and reserved file count together.
+func (u *StorageUsage) TotalFiles() int64 {
+	return u.UsedFiles + u.ReservedFiles
+}
+
+// Allows reports whether bytes more storage and files more files still fit
+// within the quota.
+func (u *StorageUsage) Allows(bytes, files int64) bool {
+	if u.Quota.MaxBytes != nil && u.TotalBytes()+bytes > *u.Quota.MaxBytes {
+		return false
+	}
+	if u.Quota.MaxFiles != nil && u.TotalFiles()+files > *u.Quota.MaxFiles {
+		return false
+	}
+	return true
+}
+
+// UsageReport is the storage usage of a company and of one of its users.
+type UsageReport struct {
+	Company *StorageUsage
+	User    *StorageUsage
+}
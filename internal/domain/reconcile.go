@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// StorageRefSource tells which table holds a reference to a storage object.
+type StorageRefSource string
+
+const (
+	StorageRefFile            StorageRefSource = "file"
+	StorageRefVersion         StorageRefSource = "version"
+	StorageRefPresignedUpload StorageRefSource = "presigned_upload"
+	StorageRefChunkedUpload   StorageRefSource = "chunked_upload"
+	StorageRefRendition       StorageRefSource = "rendition"
+)
+
+// StorageRef is a database row that points at an object in storage.
+type StorageRef struct {
+	Source StorageRefSource
+	ID     string
+	Key    string
+	Size   int64
+}
+
+// ExpectsObject reports whether the object must already exist in storage.
+// Pending uploads reserve their key before anything is written.
+func (r *StorageRef) ExpectsObject() bool {
+	return r.Source == StorageRefFile || r.Source == StorageRefVersion || r.Source == StorageRefRendition
+}
+
+type OrphanAction string
+
+const (
+	OrphanActionDelete     OrphanAction = "delete"
+	OrphanActionQuarantine OrphanAction = "quarantine"
+)
+
+func (a OrphanAction) IsValid() bool {
+	return a == OrphanActionDelete || a == OrphanActionQuarantine
+}
+
+type OrphanObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Resolved     bool      `json:"resolved"`
+	Error        string    `json:"error,omitempty"`
+}
+
+type DanglingRow struct {
+	Source StorageRefSource `json:"source"`
+	ID     string           `json:"id"`
+	Key    string           `json:"key"`
+}
+
+type SizeMismatch struct {
+	Source       StorageRefSource `json:"source"`
+	ID           string           `json:"id"`
+	Key          string           `json:"key"`
+	ExpectedSize int64            `json:"expected_size"`
+	ActualSize   int64            `json:"actual_size"`
+}
+
+// ReconcileReport describes how storage and the database disagree for a company.
+// Only orphaned objects are acted upon in apply mode, rows are reported as is.
+type ReconcileReport struct {
+	CompanyID string        `json:"company_id"`
+	DryRun    bool          `json:"dry_run"`
+	Action    OrphanAction  `json:"action"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+
+	ScannedObjects int `json:"scanned_objects"`
+	ScannedRefs    int `json:"scanned_refs"`
+	SkippedRecent  int `json:"skipped_recent"`
+
+	Orphans        []*OrphanObject `json:"orphans"`
+	DanglingRows   []*DanglingRow  `json:"dangling_rows"`
+	SizeMismatches []*SizeMismatch `json:"size_mismatches"`
+
+	Resolved int `json:"resolved"`
+	Errors   int `json:"errors"`
+}
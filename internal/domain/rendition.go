@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"mime"
+	"time"
+)
+
+// RenditionSize names a preview size. Each size fits the image into a square
+// of MaxEdge pixels.
+type RenditionSize string
+
+const (
+	RenditionSmall  RenditionSize = "small"
+	RenditionMedium RenditionSize = "medium"
+	RenditionLarge  RenditionSize = "large"
+)
+
+// RenditionSizes lists the sizes generated for every image, smallest first.
+var RenditionSizes = []RenditionSize{RenditionSmall, RenditionMedium, RenditionLarge}
+
+func (s RenditionSize) IsValid() bool {
+	return s.MaxEdge() > 0
+}
+
+// MaxEdge returns the longest side in pixels of a rendition of this size.
+func (s RenditionSize) MaxEdge() int {
+	switch s {
+	case RenditionSmall:
+		return 128
+	case RenditionMedium:
+		return 320
+	case RenditionLarge:
+		return 1024
+	}
+	return 0
+}
+
+// IsRenderableImage reports whether thumbnails can be generated for files of
+// this MIME type.
+func IsRenderableImage(mimeType *string) bool {
+	if mimeType == nil {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(*mimeType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "image/jpeg" || mediaType == "image/png" || mediaType == "image/gif"
+}
+
+// FileRendition is a scaled down copy of an image file. SourcePath is the
+// storage object it was generated from, so uploading a new version makes
+// the rendition stale.
+type FileRendition struct {
+	FileID      string
+	CompanyID   string
+	Size        RenditionSize
+	StoragePath string
+	MimeType    string
+	Width       int
+	Height      int
+	ByteSize    int64
+	Hash        string
+	SourcePath  string
+	CreatedAt   time.Time
+}
+
+// IsCurrent reports whether the rendition was generated from the current
+// content of file.
+func (r *FileRendition) IsCurrent(file *File) bool {
+	return file.StoragePath != nil && r.SourcePath == *file.StoragePath
+}
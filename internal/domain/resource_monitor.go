@@ -223,11 +223,4 @@ func (rm *ResourceMonitor) performHealthCheck() {
 	stats := rm.GetResourceStats()
 
 	_ = stats
-
-	rm.cleanupExpiredResources()
-}
-
-func (rm *ResourceMonitor) cleanupExpiredResources() {
-	// Cleanup logic for expired chunked upload sessions
-	// This would typically interact with a repository to clean up old sessions
 }
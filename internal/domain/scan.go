@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ScanStatus is the outcome of the malware scan of the current content of a file.
+type ScanStatus string
+
+const (
+	ScanStatusPending  ScanStatus = "pending"
+	ScanStatusClean    ScanStatus = "clean"
+	ScanStatusInfected ScanStatus = "infected"
+	// ScanStatusError marks content the scanner could not check, such as
+	// files over the size limit of the scanner.
+	ScanStatusError ScanStatus = "error"
+)
+
+func (s ScanStatus) IsValid() bool {
+	switch s {
+	case ScanStatusPending, ScanStatusClean, ScanStatusInfected, ScanStatusError:
+		return true
+	}
+	return false
+}
+
+// Downloadable reports whether content with this status may be served.
+// Infected content never is, content that has not been found clean only when
+// allowUnscanned is set.
+func (s ScanStatus) Downloadable(allowUnscanned bool) bool {
+	switch s {
+	case ScanStatusClean:
+		return true
+	case ScanStatusInfected:
+		return false
+	}
+	return allowUnscanned
+}
+
+// QuarantinePrefix is put in front of the storage key of infected content.
+const QuarantinePrefix = "quarantine/"
+
+func QuarantineKey(key string) string {
+	if IsQuarantineKey(key) {
+		return key
+	}
+	return QuarantinePrefix + key
+}
+
+func IsQuarantineKey(key string) bool {
+	return strings.HasPrefix(key, QuarantinePrefix)
+}
+
+// ReleasedKey is the key quarantined content is moved back to once released.
+func ReleasedKey(key string) string {
+	return strings.TrimPrefix(key, QuarantinePrefix)
+}
+
+// ScanResult is what scanning the object at StoragePath found. Detail holds
+// the signature name of infected content or the reason the scan failed.
+type ScanResult struct {
+	StoragePath string
+	Status      ScanStatus
+	Detail      *string
+	ScannedAt   time.Time
+}
+
+// ScanFinding is a file whose content was not found clean, listed for review.
+type ScanFinding struct {
+	File      *File
+	Detail    *string
+	ScannedAt *time.Time
+}
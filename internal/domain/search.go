@@ -0,0 +1,31 @@
+package domain
+
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 200
+)
+
+// SearchQuery finds items by name or content under Path. Results are ranked by relevance
+// and paged by offset, since relevance scores do not make a stable cursor.
+type SearchQuery struct {
+	Text string
+	Path Path
+	FileFilter
+
+	Limit  int
+	Offset int
+}
+
+type SearchHit struct {
+	File *File
+	// Score grows with the match quality: exact name, prefix, substring, then
+	// similarity for name search, the text search rank for content search.
+	Score float64
+	// Snippet shows the matching passages of a content search hit.
+	Snippet string
+}
+
+type SearchPage struct {
+	Hits    []*SearchHit
+	HasMore bool
+}
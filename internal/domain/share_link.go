@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+const (
+	shareTokenBytes       = 32
+	MaxSharePasswordBytes = 72
+	MaxShareAllowedIPs    = 50
+)
+
+// ShareLink gives people outside the company access to a file or folder
+// without an account. Only the SHA-256 of the token is stored, the token
+// itself is handed out once when the link is created.
+type ShareLink struct {
+	ID            string
+	Token         string
+	TokenHash     string
+	CompanyID     string
+	UserCreateID  string
+	FileID        string
+	PasswordHash  *string
+	ExpiresAt     *time.Time
+	MaxDownloads  *int
+	DownloadCount int
+	AllowedIPs    []string
+	CreatedAt     time.Time
+}
+
+// ShareLinkOptions are the restrictions of a new link. Unset options do not restrict it.
+type ShareLinkOptions struct {
+	Password     string
+	ExpiresAt    *time.Time
+	MaxDownloads *int
+	AllowedIPs   []string
+}
+
+// ShareAccess is what an anonymous visitor presents when opening a link.
+type ShareAccess struct {
+	Token    string
+	Password string
+	ClientIP string
+}
+
+// SharedFolder is one page of Folder, opened through a link sharing Root.
+type SharedFolder struct {
+	Root   *File
+	Folder *File
+	Page   *FolderPage
+}
+
+// NewShareToken returns a random URL-safe token and its hash.
+func NewShareToken() (string, string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashShareToken(token), nil
+}
+
+func HashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeAllowedIPs turns addresses and CIDR ranges into ranges, so
+// "10.0.0.1" becomes "10.0.0.1/32".
+func NormalizeAllowedIPs(values []string) ([]string, error) {
+	if len(values) > MaxShareAllowedIPs {
+		return nil, fmt.Errorf("at most %d allowed IPs can be set", MaxShareAllowedIPs)
+	}
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, errAddr := netip.ParseAddr(value)
+			if errAddr != nil {
+				return nil, fmt.Errorf("%q is not an IP address or CIDR range", value)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		result = append(result, prefix.Masked().String())
+	}
+
+	return result, nil
+}
+
+// RelativePath returns the path of an item below the shared folder as seen by
+// the visitor, so the location of the folder in the company stays private.
+func (f *SharedFolder) RelativePath(path Path) Path {
+	relative := strings.TrimPrefix(path.String(), f.Root.FullPath.String())
+	if relative == "" {
+		return Path("/")
+	}
+	return Path(relative)
+}
+
+func (l *ShareLink) HasPassword() bool {
+	return l.PasswordHash != nil
+}
+
+func (l *ShareLink) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// AllowsIP reports whether the link may be opened from ip. A link without
+// allowed IPs may be opened from anywhere.
+func (l *ShareLink) AllowsIP(ip string) bool {
+	if len(l.AllowedIPs) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, value := range l.AllowedIPs {
+		if prefix, err := netip.ParsePrefix(value); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
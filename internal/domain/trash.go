@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+type TrashItem struct {
+	File      *File
+	DeletedAt time.Time
+	ExpiresAt time.Time
+}
+
+type TrashSettings struct {
+	CompanyID     string
+	RetentionDays int
+}
+
+func NewTrashItem(file *File, retention time.Duration) *TrashItem {
+	return &TrashItem{
+		File:      file,
+		DeletedAt: file.UpdatedAt,
+		ExpiresAt: file.UpdatedAt.Add(retention),
+	}
+}
+
+func (s *TrashSettings) Retention() time.Duration {
+	return time.Duration(s.RetentionDays) * 24 * time.Hour
+}
@@ -0,0 +1,180 @@
+package encrypted
+
+import (
+	"context"
+	stdErrors "errors"
+	"sync"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/encryption"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+const rewrapBatchSize = 100
+
+type KeyRepository interface {
+	GetCompanyKey(ctx context.Context, companyID string) (*domain.CompanyKey, error)
+	CreateCompanyKey(ctx context.Context, key *domain.CompanyKey) error
+	GetKeysToRewrap(ctx context.Context, masterKeyID string, limit int) ([]*domain.CompanyKey, error)
+	RewrapCompanyKey(ctx context.Context, key *domain.CompanyKey, previousMasterKeyID string) (bool, error)
+}
+
+// Keyring hands out the data key of every company, creating it on first use.
+// Data keys are stored wrapped by the current master key and kept unwrapped in
+// memory once used.
+type Keyring struct {
+	repo KeyRepository
+	// masterKeys holds the current master key first.
+	masterKeys []encryption.MasterKey
+
+	mu       sync.RWMutex
+	dataKeys map[string][]byte
+}
+
+// NewKeyring parses the master keys, given as id:base64-key with the current
+// one first.
+func NewKeyring(repo KeyRepository, masterKeys []string) (*Keyring, error) {
+	keys, err := encryption.ParseMasterKeys(masterKeys)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, stdErrors.New("at least one master key is required")
+	}
+
+	return &Keyring{
+		repo:       repo,
+		masterKeys: keys,
+		dataKeys:   make(map[string][]byte),
+	}, nil
+}
+
+// DataKey returns the data key of the company.
+func (k *Keyring) DataKey(ctx context.Context, companyID string) ([]byte, error) {
+	k.mu.RLock()
+	dataKey, ok := k.dataKeys[companyID]
+	k.mu.RUnlock()
+	if ok {
+		return dataKey, nil
+	}
+
+	stored, err := k.repo.GetCompanyKey(ctx, companyID)
+	if stdErrors.Is(err, errors.ErrNotFound) {
+		stored, err = k.createKey(ctx, companyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err = k.unwrap(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.dataKeys[companyID] = dataKey
+	k.mu.Unlock()
+
+	return dataKey, nil
+}
+
+// createKey stores a new data key for the company and returns the stored one,
+// which is another when a concurrent request created it first.
+func (k *Keyring) createKey(ctx context.Context, companyID string) (*domain.CompanyKey, error) {
+	dataKey, err := encryption.NewDataKey()
+	if err != nil {
+		return nil, errors.InternalServer("failed to generate data key")
+	}
+
+	wrapped, err := encryption.WrapKey(k.masterKeys[0], dataKey, companyID)
+	if err != nil {
+		return nil, errors.InternalServer("failed to wrap data key")
+	}
+
+	err = k.repo.CreateCompanyKey(ctx, &domain.CompanyKey{
+		CompanyID:   companyID,
+		WrappedKey:  wrapped,
+		MasterKeyID: k.masterKeys[0].ID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return k.repo.GetCompanyKey(ctx, companyID)
+}
+
+func (k *Keyring) unwrap(stored *domain.CompanyKey) ([]byte, error) {
+	for _, master := range k.masterKeys {
+		if master.ID != stored.MasterKeyID {
+			continue
+		}
+		dataKey, err := encryption.UnwrapKey(master, stored.WrappedKey, stored.CompanyID)
+		if err != nil {
+			return nil, errors.InternalServer("failed to unwrap data key")
+		}
+		return dataKey, nil
+	}
+
+	return nil, errors.InternalServer("master key " + stored.MasterKeyID + " is not configured")
+}
+
+// StartRewrap rewraps, once, the data keys still wrapped by an older master
+// key with the current one, which rotates the master key without touching
+// stored objects. The older key can be removed once no keys are left.
+func (k *Keyring) StartRewrap(ctx context.Context, log logger.Logger) {
+	rewrapped, err := k.RewrapKeys(ctx)
+	if err != nil {
+		log.Error("func StartRewrap: failed to rewrap data keys", "func", "StartRewrap", "rewrapped", rewrapped, "err", err)
+		return
+	}
+
+	if rewrapped > 0 {
+		log.Info("rewrapped data keys", "count", rewrapped, "master_key_id", k.masterKeys[0].ID)
+	}
+}
+
+// RewrapKeys rewraps every data key wrapped by an older master key and returns
+// how many were rewrapped.
+func (k *Keyring) RewrapKeys(ctx context.Context) (int, error) {
+	current := k.masterKeys[0]
+	rewrapped := 0
+
+	for {
+		keys, err := k.repo.GetKeysToRewrap(ctx, current.ID, rewrapBatchSize)
+		if err != nil {
+			return rewrapped, err
+		}
+		if len(keys) == 0 {
+			return rewrapped, nil
+		}
+
+		for _, stored := range keys {
+			dataKey, err := k.unwrap(stored)
+			if err != nil {
+				return rewrapped, err
+			}
+
+			wrapped, err := encryption.WrapKey(current, dataKey, stored.CompanyID)
+			if err != nil {
+				return rewrapped, errors.InternalServer("failed to wrap data key")
+			}
+
+			now := time.Now()
+			ok, err := k.repo.RewrapCompanyKey(ctx, &domain.CompanyKey{
+				CompanyID:   stored.CompanyID,
+				WrappedKey:  wrapped,
+				MasterKeyID: current.ID,
+				RotatedAt:   &now,
+			}, stored.MasterKeyID)
+			if err != nil {
+				return rewrapped, err
+			}
+			if ok {
+				rewrapped++
+			}
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Package encrypted encrypts stored objects on their way to and from another
+// storage repository, with a data key per company. Objects stored before
+// encryption was turned on are still read as they are.
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/encryption"
+	"go-storage/pkg/errors"
+)
+
+// Storage is the storage repository objects are stored in.
+type Storage interface {
+	StoreFile(ctx context.Context, key string, reader io.Reader, size int64, mimeType string) (string, error)
+	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
+	GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error)
+	CopyFile(ctx context.Context, srcKey, dstKey string) error
+	DeleteFile(ctx context.Context, key string) error
+	GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error)
+	ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error)
+
+	InitChunkedUpload(ctx context.Context, key string, mimeType string) (string, error)
+	UploadChunk(ctx context.Context, uploadID, key string, chunkIndex int, reader io.Reader, size int64) (string, error)
+	CompleteChunkedUpload(ctx context.Context, uploadID, key string, parts []string) error
+	AbortChunkedUpload(ctx context.Context, uploadID, key string) error
+	AbortStaleChunkedUploads(ctx context.Context, olderThan time.Time) (int, error)
+
+	GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error)
+	GetPresignedUploadURL(ctx context.Context, key string, expiry time.Duration, headers http.Header) (string, error)
+}
+
+type DataKeys interface {
+	DataKey(ctx context.Context, companyID string) ([]byte, error)
+}
+
+// StorageRepository stores objects encrypted in the wrapped storage. Storage
+// never sees the plaintext, so it cannot hand out presigned URLs.
+type StorageRepository struct {
+	Storage
+	keys DataKeys
+}
+
+func NewStorageRepository(storage Storage, keys DataKeys) *StorageRepository {
+	return &StorageRepository{Storage: storage, keys: keys}
+}
+
+func (r *StorageRepository) StoreFile(ctx context.Context, key string, reader io.Reader, size int64, mimeType string) (string, error) {
+	dataKey, err := r.dataKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := encryption.NewHeader()
+	if err != nil {
+		return "", errors.InternalServer("failed to encrypt file")
+	}
+
+	sealed, err := encryption.NewEncryptReader(reader, dataKey, header, 0)
+	if err != nil {
+		return "", errors.InternalServer("failed to encrypt file")
+	}
+
+	storedSize := int64(-1)
+	if size >= 0 {
+		storedSize = header.CiphertextSize(size)
+	}
+
+	return r.Storage.StoreFile(ctx, key, sealed, storedSize, mimeType)
+}
+
+// UploadChunk encrypts every chunk as a part of its own. The first one carries
+// the header, which records the chunk size so the parts can be told apart.
+func (r *StorageRepository) UploadChunk(ctx context.Context, uploadID, key string, chunkIndex int, reader io.Reader, size int64) (string, error) {
+	dataKey, err := r.dataKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	header := encryption.NewMultipartHeader(uploadID, size)
+	sealed, err := encryption.NewEncryptReader(reader, dataKey, header, int64(chunkIndex))
+	if err != nil {
+		return "", errors.InternalServer("failed to encrypt chunk")
+	}
+
+	storedSize := header.PartCiphertextSize(size)
+	if chunkIndex == 0 {
+		storedSize += int64(encryption.HeaderSize)
+	}
+
+	return r.Storage.UploadChunk(ctx, uploadID, key, chunkIndex, sealed, storedSize)
+}
+
+// GetFile decrypts the object, or returns it as it is when it was stored
+// unencrypted. Like the wrapped storage, errors show when reading.
+func (r *StorageRepository) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := r.Storage.GetFile(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objectReader{ctx: ctx, repo: r, key: key, object: object}, nil
+}
+
+func (r *StorageRepository) GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	header, err := r.readHeader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return r.Storage.GetFileRange(ctx, key, start, end)
+	}
+
+	if start < 0 || end < start {
+		return nil, errors.BadRequest("invalid byte range")
+	}
+
+	dataKey, err := r.dataKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	storedStart, storedEnd := header.CiphertextRange(start, end)
+	object, err := r.Storage.GetFileRange(ctx, key, storedStart, storedEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := encryption.NewDecryptReader(object, dataKey, header, start, end)
+	if err != nil {
+		object.Close()
+		return nil, errors.InternalServer("failed to decrypt file")
+	}
+
+	return readCloser{Reader: plain, Closer: object}, nil
+}
+
+// GetFileInfo reports the size of the plaintext. Checksums computed by storage
+// cover the ciphertext and are left out.
+func (r *StorageRepository) GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error) {
+	info, err := r.Storage.GetFileInfo(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.plaintextInfo(ctx, info)
+}
+
+// ListFiles reports the size of the plaintext of every object, which takes a
+// read of every header.
+func (r *StorageRepository) ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error) {
+	files, err := r.Storage.ListFiles(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, info := range files {
+		if files[i], err = r.plaintextInfo(ctx, info); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// CopyFile copies the ciphertext, which stays readable as long as both keys
+// belong to the same company.
+func (r *StorageRepository) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	srcCompany, srcErr := companyOf(srcKey)
+	dstCompany, dstErr := companyOf(dstKey)
+	if srcErr == nil && dstErr == nil && srcCompany != dstCompany {
+		return errors.InvalidOperation("encrypted files cannot be copied between companies")
+	}
+
+	return r.Storage.CopyFile(ctx, srcKey, dstKey)
+}
+
+func (r *StorageRepository) GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error) {
+	return "", errors.InvalidOperation("presigned URLs are not available with storage encryption")
+}
+
+func (r *StorageRepository) GetPresignedUploadURL(ctx context.Context, key string, expiry time.Duration, headers http.Header) (string, error) {
+	return "", errors.InvalidOperation("presigned URLs are not available with storage encryption")
+}
+
+func (r *StorageRepository) plaintextInfo(ctx context.Context, info *domain.StorageFileInfo) (*domain.StorageFileInfo, error) {
+	if info.Size < int64(encryption.HeaderSize) {
+		return info, nil
+	}
+
+	header, err := r.readHeader(ctx, info.Key)
+	if err != nil || header == nil {
+		return info, err
+	}
+
+	size, err := header.PlaintextSize(info.Size)
+	if err != nil {
+		return nil, errors.StorageError("encrypted file is corrupt")
+	}
+
+	plain := *info
+	plain.Size = size
+	plain.ChecksumSHA256 = ""
+	return &plain, nil
+}
+
+// readHeader returns the header of the object, or nil when it is not encrypted.
+func (r *StorageRepository) readHeader(ctx context.Context, key string) (*encryption.Header, error) {
+	object, err := r.Storage.GetFileRange(ctx, key, 0, int64(encryption.HeaderSize)-1)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	data := make([]byte, encryption.HeaderSize)
+	n, err := io.ReadFull(object, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, errors.NotFound("file not found in storage")
+	}
+
+	header, _ := encryption.ParseHeader(data[:n])
+	return header, nil
+}
+
+func (r *StorageRepository) dataKey(ctx context.Context, key string) ([]byte, error) {
+	companyID, err := companyOf(key)
+	if err != nil {
+		return nil, err
+	}
+	return r.keys.DataKey(ctx, companyID)
+}
+
+// companyOf returns the company an object key belongs to, also in quarantine.
+func companyOf(key string) (string, error) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(key, domain.QuarantinePrefix), "companies/")
+	companyID, _, found := strings.Cut(rest, "/")
+	if !ok || !found || companyID == "" {
+		return "", errors.InternalServer("storage key does not belong to a company")
+	}
+	return companyID, nil
+}
+
+// objectReader tells encrypted objects from unencrypted ones by their first
+// bytes, on the first read.
+type objectReader struct {
+	ctx    context.Context
+	repo   *StorageRepository
+	key    string
+	object io.ReadCloser
+	reader io.Reader
+}
+
+func (o *objectReader) Read(p []byte) (int, error) {
+	if o.reader == nil {
+		reader, err := o.open()
+		if err != nil {
+			return 0, err
+		}
+		o.reader = reader
+	}
+
+	return o.reader.Read(p)
+}
+
+func (o *objectReader) open() (io.Reader, error) {
+	data := make([]byte, encryption.HeaderSize)
+	n, err := io.ReadFull(o.object, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	header, ok := encryption.ParseHeader(data[:n])
+	if !ok {
+		return io.MultiReader(bytes.NewReader(data[:n]), o.object), nil
+	}
+
+	dataKey, err := o.repo.dataKey(o.ctx, o.key)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.NewDecryptReader(o.object, dataKey, header, 0, -1)
+}
+
+func (o *objectReader) Close() error {
+	return o.object.Close()
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
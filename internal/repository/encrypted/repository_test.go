@@ -0,0 +1,420 @@
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-storage/internal/domain"
+	"go-storage/pkg/encryption"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type memoryStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[string]map[int][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: map[string][]byte{}, parts: map[string]map[int][]byte{}}
+}
+
+func (m *memoryStorage) StoreFile(ctx context.Context, key string, reader io.Reader, size int64, mimeType string) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return "", fmt.Errorf("stored %d bytes, announced %d", len(data), size)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = data
+	return "etag", nil
+}
+
+func (m *memoryStorage) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, pkgErrors.NotFound("file not found in storage")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryStorage) GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok || start >= int64(len(data)) {
+		return nil, pkgErrors.NotFound("file not found in storage")
+	}
+	return io.NopCloser(bytes.NewReader(data[start:min(end+1, int64(len(data)))])), nil
+}
+
+func (m *memoryStorage) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[dstKey] = m.objects[srcKey]
+	return nil
+}
+
+func (m *memoryStorage) DeleteFile(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memoryStorage) GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, pkgErrors.NotFound("file not found in storage")
+	}
+	return &domain.StorageFileInfo{Key: key, Size: int64(len(data)), ChecksumSHA256: "checksum"}, nil
+}
+
+func (m *memoryStorage) ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error) {
+	m.mu.Lock()
+	keys := make([]string, 0, len(m.objects))
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	m.mu.Unlock()
+	sort.Strings(keys)
+
+	files := make([]*domain.StorageFileInfo, 0, len(keys))
+	for _, key := range keys {
+		info, _ := m.GetFileInfo(ctx, key)
+		files = append(files, info)
+	}
+	return files, nil
+}
+
+func (m *memoryStorage) InitChunkedUpload(ctx context.Context, key string, mimeType string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parts[key] = map[int][]byte{}
+	return "upload-" + key, nil
+}
+
+func (m *memoryStorage) UploadChunk(ctx context.Context, uploadID, key string, chunkIndex int, reader io.Reader, size int64) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) != size {
+		return "", fmt.Errorf("uploaded %d bytes, announced %d", len(data), size)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parts[key][chunkIndex] = data
+	return fmt.Sprintf("etag-%d", chunkIndex), nil
+}
+
+func (m *memoryStorage) CompleteChunkedUpload(ctx context.Context, uploadID, key string, parts []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var data []byte
+	for i := range parts {
+		data = append(data, m.parts[key][i]...)
+	}
+	m.objects[key] = data
+	delete(m.parts, key)
+	return nil
+}
+
+func (m *memoryStorage) AbortChunkedUpload(ctx context.Context, uploadID, key string) error {
+	return nil
+}
+
+func (m *memoryStorage) AbortStaleChunkedUploads(ctx context.Context, olderThan time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *memoryStorage) GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error) {
+	return "https://storage/" + key, nil
+}
+
+func (m *memoryStorage) GetPresignedUploadURL(ctx context.Context, key string, expiry time.Duration, headers http.Header) (string, error) {
+	return "https://storage/" + key, nil
+}
+
+type memoryKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]*domain.CompanyKey
+}
+
+func newMemoryKeyRepository() *memoryKeyRepository {
+	return &memoryKeyRepository{keys: map[string]*domain.CompanyKey{}}
+}
+
+func (m *memoryKeyRepository) GetCompanyKey(ctx context.Context, companyID string) (*domain.CompanyKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[companyID]
+	if !ok {
+		return nil, pkgErrors.NotFound("company key not found")
+	}
+	stored := *key
+	return &stored, nil
+}
+
+func (m *memoryKeyRepository) CreateCompanyKey(ctx context.Context, key *domain.CompanyKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.keys[key.CompanyID]; !ok {
+		stored := *key
+		m.keys[key.CompanyID] = &stored
+	}
+	return nil
+}
+
+func (m *memoryKeyRepository) GetKeysToRewrap(ctx context.Context, masterKeyID string, limit int) ([]*domain.CompanyKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := []*domain.CompanyKey{}
+	for _, key := range m.keys {
+		if key.MasterKeyID != masterKeyID && len(keys) < limit {
+			stored := *key
+			keys = append(keys, &stored)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memoryKeyRepository) RewrapCompanyKey(ctx context.Context, key *domain.CompanyKey, previousMasterKeyID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.keys[key.CompanyID]
+	if !ok || stored.MasterKeyID != previousMasterKeyID {
+		return false, nil
+	}
+	stored.WrappedKey, stored.MasterKeyID, stored.RotatedAt = key.WrappedKey, key.MasterKeyID, key.RotatedAt
+	return true, nil
+}
+
+func masterKey(t *testing.T, id string) string {
+	key := make([]byte, encryption.KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return id + ":" + base64.StdEncoding.EncodeToString(key)
+}
+
+func setupRepository(t *testing.T) (*StorageRepository, *memoryStorage, *memoryKeyRepository) {
+	storage := newMemoryStorage()
+	keys := newMemoryKeyRepository()
+
+	keyring, err := NewKeyring(keys, []string{masterKey(t, "2025-07")})
+	require.NoError(t, err)
+
+	return NewStorageRepository(storage, keyring), storage, keys
+}
+
+func randomContent(t *testing.T, size int) []byte {
+	content := make([]byte, size)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+	return content
+}
+
+func readRange(t *testing.T, repo *StorageRepository, key string, start, end int64) []byte {
+	reader, err := repo.GetFileRange(context.Background(), key, start, end)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return data
+}
+
+func TestStoreFile_Encrypted(t *testing.T) {
+	repo, storage, keys := setupRepository(t)
+	ctx := context.Background()
+	key := "companies/company-1/files/file-1/report.pdf"
+	content := randomContent(t, 3*encryption.SegmentSize+123)
+
+	_, err := repo.StoreFile(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf")
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(storage.objects[key]), string(content[:64]))
+	assert.Contains(t, keys.keys, "company-1")
+
+	reader, err := repo.GetFile(ctx, key)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, stored)
+
+	info, err := repo.GetFileInfo(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), info.Size)
+	assert.Empty(t, info.ChecksumSHA256)
+
+	assert.Equal(t, content[100:200], readRange(t, repo, key, 100, 199))
+	assert.Equal(t, content[encryption.SegmentSize-10:2*encryption.SegmentSize+10], readRange(t, repo, key, encryption.SegmentSize-10, 2*encryption.SegmentSize+9))
+	assert.Equal(t, content[len(content)-1:], readRange(t, repo, key, int64(len(content)-1), int64(len(content)-1)))
+}
+
+func TestStoreFile_EmptyFile(t *testing.T) {
+	repo, _, _ := setupRepository(t)
+	ctx := context.Background()
+	key := "companies/company-1/files/file-1/empty.txt"
+
+	_, err := repo.StoreFile(ctx, key, bytes.NewReader(nil), 0, "text/plain")
+	require.NoError(t, err)
+
+	reader, err := repo.GetFile(ctx, key)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestGetFile_Tampered(t *testing.T) {
+	repo, storage, _ := setupRepository(t)
+	ctx := context.Background()
+	key := "companies/company-1/files/file-1/report.pdf"
+	content := randomContent(t, 1000)
+
+	_, err := repo.StoreFile(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf")
+	require.NoError(t, err)
+	storage.objects[key][encryption.HeaderSize+100] ^= 1
+
+	reader, err := repo.GetFile(ctx, key)
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, encryption.ErrCorrupt)
+}
+
+func TestGetFile_Unencrypted(t *testing.T) {
+	repo, storage, keys := setupRepository(t)
+	ctx := context.Background()
+	key := "companies/company-1/files/file-1/notes.txt"
+	content := []byte("stored before encryption was turned on")
+	storage.objects[key] = content
+
+	reader, err := repo.GetFile(ctx, key)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, stored)
+
+	assert.Equal(t, content[7:13], readRange(t, repo, key, 7, 12))
+
+	info, err := repo.GetFileInfo(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), info.Size)
+	assert.Equal(t, "checksum", info.ChecksumSHA256)
+	assert.Empty(t, keys.keys)
+}
+
+func TestChunkedUpload_Encrypted(t *testing.T) {
+	repo, _, _ := setupRepository(t)
+	ctx := context.Background()
+	key := "companies/company-1/files/file-1/video.mp4"
+	chunkSize := 2*encryption.SegmentSize + 100
+	content := randomContent(t, 3*chunkSize+50)
+
+	uploadID, err := repo.InitChunkedUpload(ctx, key, "video/mp4")
+	require.NoError(t, err)
+
+	parts := make([]string, 4)
+	for _, index := range []int{2, 0, 3, 1} {
+		chunk := content[index*chunkSize : min((index+1)*chunkSize, len(content))]
+		parts[index], err = repo.UploadChunk(ctx, uploadID, key, index, bytes.NewReader(chunk), int64(len(chunk)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CompleteChunkedUpload(ctx, uploadID, key, parts))
+
+	reader, err := repo.GetFile(ctx, key)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, stored)
+
+	files, err := repo.ListFiles(ctx, "companies/company-1/")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, int64(len(content)), files[0].Size)
+
+	assert.Equal(t, content[chunkSize-5:2*chunkSize+5], readRange(t, repo, key, int64(chunkSize-5), int64(2*chunkSize+4)))
+}
+
+func TestCopyFile_Quarantine(t *testing.T) {
+	repo, _, _ := setupRepository(t)
+	ctx := context.Background()
+	key := "companies/company-1/files/file-1/report.pdf"
+	content := randomContent(t, 1000)
+
+	_, err := repo.StoreFile(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf")
+	require.NoError(t, err)
+	require.NoError(t, repo.CopyFile(ctx, key, domain.QuarantineKey(key)))
+
+	reader, err := repo.GetFile(ctx, domain.QuarantineKey(key))
+	require.NoError(t, err)
+	stored, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, stored)
+
+	err = repo.CopyFile(ctx, key, "companies/company-2/files/file-1/report.pdf")
+	assert.ErrorIs(t, err, pkgErrors.ErrInvalidOperation)
+}
+
+func TestGetPresignedURL_Unavailable(t *testing.T) {
+	repo, _, _ := setupRepository(t)
+
+	_, err := repo.GetPresignedURL(context.Background(), "companies/company-1/files/file-1/report.pdf", time.Minute, "report.pdf")
+	assert.ErrorIs(t, err, pkgErrors.ErrInvalidOperation)
+}
+
+func TestKeyring_RewrapKeys(t *testing.T) {
+	keys := newMemoryKeyRepository()
+	previous, current := masterKey(t, "2025-01"), masterKey(t, "2025-07")
+
+	oldKeyring, err := NewKeyring(keys, []string{previous})
+	require.NoError(t, err)
+	dataKey, err := oldKeyring.DataKey(context.Background(), "company-1")
+	require.NoError(t, err)
+
+	keyring, err := NewKeyring(keys, []string{current, previous})
+	require.NoError(t, err)
+
+	rewrapped, err := keyring.RewrapKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, rewrapped)
+	assert.Equal(t, "2025-07", keys.keys["company-1"].MasterKeyID)
+	assert.NotNil(t, keys.keys["company-1"].RotatedAt)
+
+	// The previous master key is no longer needed.
+	rotated, err := NewKeyring(keys, []string{current})
+	require.NoError(t, err)
+	rotatedKey, err := rotated.DataKey(context.Background(), "company-1")
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, rotatedKey)
+}
+
+func TestNewKeyring_InvalidMasterKey(t *testing.T) {
+	_, err := NewKeyring(newMemoryKeyRepository(), []string{"2025-07:c2hvcnQ="})
+	assert.Error(t, err)
+
+	_, err = NewKeyring(newMemoryKeyRepository(), []string{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,485 @@
+// Package localfs stores objects as files on the local disk, for deployments
+// that run without MinIO.
+package localfs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+// objectSuffix ends the name of every object file. The key of one object may
+// be a directory in the key of another, such as a file named "versions" next
+// to the versions of its neighbour, and the suffix keeps them apart.
+const objectSuffix = ".object"
+
+// uploadKeyFile holds the object key of a chunked upload. Its modification
+// time is when the upload started.
+const uploadKeyFile = "key"
+
+// StorageRepository keeps every object in a file below objects, named after
+// its key. Writes go to a temporary file first, which is renamed into place
+// once complete, so readers never see a partial object. Chunks of chunked
+// uploads are kept below uploads until the upload completes.
+type StorageRepository struct {
+	objects string
+	uploads string
+	tmp     string
+}
+
+// NewStorageRepository stores objects below root, creating it when missing.
+func NewStorageRepository(root string) (*StorageRepository, error) {
+	r := &StorageRepository{
+		objects: filepath.Join(root, "objects"),
+		uploads: filepath.Join(root, "uploads"),
+		tmp:     filepath.Join(root, "tmp"),
+	}
+
+	for _, dir := range []string{r.objects, r.uploads, r.tmp} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *StorageRepository) StoreFile(ctx context.Context, key string, reader io.Reader, size int64, mimeType string) (string, error) {
+	objectPath, err := r.objectPath(key)
+	if err != nil {
+		return "", err
+	}
+
+	tmpPath, etag, err := r.writeTemp(reader, size)
+	if err != nil {
+		return "", errors.InternalServer("failed to store file in storage")
+	}
+
+	if err := r.moveIntoPlace(tmpPath, objectPath); err != nil {
+		return "", errors.InternalServer("failed to store file in storage")
+	}
+
+	return etag, nil
+}
+
+func (r *StorageRepository) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	objectPath, err := r.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return nil, objectError(err, "failed to read file from storage")
+	}
+
+	return file, nil
+}
+
+func (r *StorageRepository) GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	if start < 0 || end < start {
+		return nil, errors.BadRequest("invalid byte range")
+	}
+
+	objectPath, err := r.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return nil, objectError(err, "failed to read file from storage")
+	}
+
+	return readCloser{Reader: io.NewSectionReader(file, start, end-start+1), Closer: file}, nil
+}
+
+// DeleteFile removes the object and the directories left empty by it.
+// Objects that do not exist are treated as deleted.
+func (r *StorageRepository) DeleteFile(ctx context.Context, key string) error {
+	objectPath, err := r.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(objectPath); err != nil && !os.IsNotExist(err) {
+		return errors.InternalServer("failed to delete file from storage")
+	}
+
+	r.removeEmptyDirs(filepath.Dir(objectPath))
+	return nil
+}
+
+// GetFileInfo reports the object as the file system knows it. The content
+// type is not kept and is derived from the key; checksums are not available.
+func (r *StorageRepository) GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error) {
+	objectPath, err := r.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(objectPath)
+	if err != nil {
+		return nil, objectError(err, "failed to get file info from storage")
+	}
+	if !stat.Mode().IsRegular() {
+		return nil, errors.NotFound("file not found in storage")
+	}
+
+	return fileInfo(key, stat), nil
+}
+
+// InitChunkedUpload starts a chunked upload for key and returns its upload ID.
+func (r *StorageRepository) InitChunkedUpload(ctx context.Context, key string, mimeType string) (string, error) {
+	if _, err := r.objectPath(key); err != nil {
+		return "", err
+	}
+
+	uploadID := uuid.NewString()
+	uploadDir := filepath.Join(r.uploads, uploadID)
+
+	if err := os.Mkdir(uploadDir, 0o750); err != nil {
+		return "", errors.InternalServer("failed to initialize multipart upload")
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, uploadKeyFile), []byte(key), 0o640); err != nil {
+		_ = os.RemoveAll(uploadDir)
+		return "", errors.InternalServer("failed to initialize multipart upload")
+	}
+
+	return uploadID, nil
+}
+
+// UploadChunk stores a chunk as part chunkIndex+1 of the upload and returns the
+// part ETag. A chunk uploaded again is kept next to the earlier one, the ETag
+// given on completion picks the part to use.
+func (r *StorageRepository) UploadChunk(ctx context.Context, uploadID, key string, chunkIndex int, reader io.Reader, size int64) (string, error) {
+	uploadDir, err := r.uploadDir(uploadID, key)
+	if err != nil {
+		return "", err
+	}
+
+	tmpPath, etag, err := r.writeTemp(reader, size)
+	if err != nil {
+		return "", errors.InternalServer("failed to upload chunk")
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(uploadDir, partName(chunkIndex+1, etag))); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", errors.InternalServer("failed to upload chunk")
+	}
+
+	return etag, nil
+}
+
+// CompleteChunkedUpload joins the parts, given as ETags ordered by chunk index,
+// into the final object and removes the upload.
+func (r *StorageRepository) CompleteChunkedUpload(ctx context.Context, uploadID, key string, parts []string) error {
+	uploadDir, err := r.uploadDir(uploadID, key)
+	if err != nil {
+		return err
+	}
+
+	objectPath, err := r.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(r.tmp, "object-*")
+	if err != nil {
+		return errors.InternalServer("failed to complete multipart upload")
+	}
+	defer os.Remove(tmp.Name())
+
+	for i, etag := range parts {
+		if err := appendPart(tmp, filepath.Join(uploadDir, partName(i+1, etag))); err != nil {
+			tmp.Close()
+			return errors.InternalServer("failed to complete multipart upload")
+		}
+	}
+
+	if err := closeSynced(tmp); err != nil {
+		return errors.InternalServer("failed to complete multipart upload")
+	}
+	if err := r.moveIntoPlace(tmp.Name(), objectPath); err != nil {
+		return errors.InternalServer("failed to complete multipart upload")
+	}
+
+	_ = os.RemoveAll(uploadDir)
+	return nil
+}
+
+// AbortChunkedUpload discards the upload with all uploaded parts. Uploads that
+// no longer exist are treated as aborted.
+func (r *StorageRepository) AbortChunkedUpload(ctx context.Context, uploadID, key string) error {
+	uploadDir, err := r.uploadDir(uploadID, key)
+	if err != nil {
+		return nil
+	}
+
+	if err := os.RemoveAll(uploadDir); err != nil {
+		return errors.InternalServer("failed to abort multipart upload")
+	}
+
+	return nil
+}
+
+// AbortStaleChunkedUploads aborts every upload started before olderThan and
+// returns how many were aborted. Temporary files left behind by writes that
+// were interrupted before olderThan are removed as well.
+func (r *StorageRepository) AbortStaleChunkedUploads(ctx context.Context, olderThan time.Time) (int, error) {
+	entries, err := os.ReadDir(r.uploads)
+	if err != nil {
+		return 0, errors.InternalServer("failed to list multipart uploads")
+	}
+
+	aborted := 0
+	for _, entry := range entries {
+		uploadDir := filepath.Join(r.uploads, entry.Name())
+		stat, err := os.Stat(filepath.Join(uploadDir, uploadKeyFile))
+		if err == nil && !stat.ModTime().Before(olderThan) {
+			continue
+		}
+		if err := os.RemoveAll(uploadDir); err != nil {
+			continue
+		}
+		aborted++
+	}
+
+	if entries, err := os.ReadDir(r.tmp); err == nil {
+		for _, entry := range entries {
+			if info, err := entry.Info(); err == nil && info.ModTime().Before(olderThan) {
+				_ = os.Remove(filepath.Join(r.tmp, entry.Name()))
+			}
+		}
+	}
+
+	return aborted, nil
+}
+
+// ListFiles returns every object stored under prefix.
+func (r *StorageRepository) ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error) {
+	var files []*domain.StorageFileInfo
+
+	// Only the directory the prefix ends in needs to be walked.
+	dir := filepath.Join(r.objects, filepath.FromSlash(prefix[:strings.LastIndex(prefix, "/")+1]))
+	if dir != r.objects && !strings.HasPrefix(dir, r.objects+string(filepath.Separator)) {
+		return nil, errors.BadRequest("invalid storage prefix")
+	}
+
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), objectSuffix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(r.objects, filePath)
+		if err != nil {
+			return err
+		}
+		key := strings.TrimSuffix(filepath.ToSlash(rel), objectSuffix)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, fileInfo(key, stat))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.InternalServer("failed to list files in storage")
+	}
+
+	return files, nil
+}
+
+// CopyFile copies an object to dstKey, written atomically like any other.
+func (r *StorageRepository) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	srcPath, err := r.objectPath(srcKey)
+	if err != nil {
+		return err
+	}
+	dstPath, err := r.objectPath(dstKey)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return objectError(err, "failed to copy file in storage")
+	}
+	defer src.Close()
+
+	tmpPath, _, err := r.writeTemp(src, -1)
+	if err != nil {
+		return errors.InternalServer("failed to copy file in storage")
+	}
+
+	if err := r.moveIntoPlace(tmpPath, dstPath); err != nil {
+		return errors.InternalServer("failed to copy file in storage")
+	}
+
+	return nil
+}
+
+func (r *StorageRepository) GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error) {
+	return "", errors.InvalidOperation("presigned URLs are not available with local storage")
+}
+
+func (r *StorageRepository) GetPresignedUploadURL(ctx context.Context, key string, expiry time.Duration, headers http.Header) (string, error) {
+	return "", errors.InvalidOperation("presigned URLs are not available with local storage")
+}
+
+// objectPath returns the file of the object. Keys must be clean relative
+// paths, so that no key leads out of the storage directory.
+// objectError reports a missing object as not found. Other failures, such as
+// missing permissions or I/O errors, say nothing about whether it exists.
+func objectError(err error, msg string) error {
+	if stdErrors.Is(err, fs.ErrNotExist) || stdErrors.Is(err, syscall.ENOTDIR) {
+		return errors.NotFound("file not found in storage")
+	}
+	return errors.StorageError(msg)
+}
+
+func (r *StorageRepository) objectPath(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if path.Clean(key) != key || !filepath.IsLocal(name) {
+		return "", errors.BadRequest("invalid storage key")
+	}
+
+	return filepath.Join(r.objects, name) + objectSuffix, nil
+}
+
+// uploadDir returns the directory of the upload, provided it belongs to key.
+func (r *StorageRepository) uploadDir(uploadID, key string) (string, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return "", errors.NotFound("multipart upload not found")
+	}
+
+	uploadDir := filepath.Join(r.uploads, uploadID)
+	uploadKey, err := os.ReadFile(filepath.Join(uploadDir, uploadKeyFile))
+	if err != nil || string(uploadKey) != key {
+		return "", errors.NotFound("multipart upload not found")
+	}
+
+	return uploadDir, nil
+}
+
+// writeTemp writes the content to a new temporary file and returns its path
+// and the MD5 ETag of the content. A size that is not negative must match.
+func (r *StorageRepository) writeTemp(reader io.Reader, size int64) (string, string, error) {
+	tmp, err := os.CreateTemp(r.tmp, "object-*")
+	if err != nil {
+		return "", "", err
+	}
+
+	hash := md5.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash), reader)
+	if err == nil && size >= 0 && written != size {
+		err = fmt.Errorf("wrote %d bytes, expected %d", written, size)
+	}
+	if err == nil {
+		err = closeSynced(tmp)
+	} else {
+		tmp.Close()
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", "", err
+	}
+
+	return tmp.Name(), hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// moveIntoPlace renames the temporary file to the object file, replacing the
+// object atomically.
+func (r *StorageRepository) moveIntoPlace(tmpPath, objectPath string) error {
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0o750); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, objectPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// removeEmptyDirs removes dir and its parents up to the objects directory for
+// as long as they are empty.
+func (r *StorageRepository) removeEmptyDirs(dir string) {
+	for dir != r.objects && strings.HasPrefix(dir, r.objects) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func partName(partNumber int, etag string) string {
+	return strconv.Itoa(partNumber) + "-" + etag
+}
+
+func appendPart(dst *os.File, partPath string) error {
+	part, err := os.Open(partPath)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+
+	_, err = io.Copy(dst, part)
+	return err
+}
+
+func closeSynced(file *os.File) error {
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func fileInfo(key string, stat fs.FileInfo) *domain.StorageFileInfo {
+	mimeType := mime.TypeByExtension(path.Ext(key))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	return &domain.StorageFileInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		MimeType:     mimeType,
+		LastModified: stat.ModTime(),
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
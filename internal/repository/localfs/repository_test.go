@@ -0,0 +1,268 @@
+package localfs
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+func newTestRepository(t *testing.T) *StorageRepository {
+	repo, err := NewStorageRepository(t.TempDir())
+	require.NoError(t, err)
+	return repo
+}
+
+func readObject(t *testing.T, repo *StorageRepository, key string) string {
+	object, err := repo.GetFile(context.Background(), key)
+	require.NoError(t, err)
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestStoreFile(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	key := "companies/c1/files/f1/report.txt"
+
+	etag, err := repo.StoreFile(ctx, key, strings.NewReader("hello world"), 11, "text/plain")
+	require.NoError(t, err)
+	assert.Equal(t, "5eb63bbbe01eeed093cb22bb8f5acdc3", etag)
+	assert.Equal(t, "hello world", readObject(t, repo, key))
+
+	info, err := repo.GetFileInfo(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, key, info.Key)
+	assert.Equal(t, int64(11), info.Size)
+	assert.True(t, strings.HasPrefix(info.MimeType, "text/plain"))
+
+	_, err = repo.StoreFile(ctx, key, strings.NewReader("short"), 11, "text/plain")
+	assert.Error(t, err)
+	assert.Equal(t, "hello world", readObject(t, repo, key))
+
+	tmp, err := os.ReadDir(repo.tmp)
+	require.NoError(t, err)
+	assert.Empty(t, tmp)
+}
+
+func TestStoreFile_InvalidKey(t *testing.T) {
+	repo := newTestRepository(t)
+
+	for _, key := range []string{"", "../outside", "companies/c1/../../outside", "/absolute", "companies/c1/"} {
+		_, err := repo.StoreFile(context.Background(), key, strings.NewReader("data"), 4, "text/plain")
+		var appErr *pkgErrors.AppError
+		require.ErrorAs(t, err, &appErr, key)
+		assert.Equal(t, "invalid storage key", appErr.Message, key)
+	}
+}
+
+func TestGetFile_NotFound(t *testing.T) {
+	repo := newTestRepository(t)
+
+	_, err := repo.GetFile(context.Background(), "companies/c1/files/f1/missing.txt")
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+
+	_, err = repo.GetFileInfo(context.Background(), "companies/c1/files/f1")
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestGetFile_StorageError(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	key := "companies/c1/files/f1/report.txt"
+
+	// An object that exists but cannot be read, here through a symlink loop.
+	objectPath, err := repo.objectPath(key)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(objectPath), 0o750))
+	require.NoError(t, os.Symlink(objectPath, objectPath))
+
+	_, err = repo.GetFile(ctx, key)
+	assert.ErrorIs(t, err, pkgErrors.ErrStorageError)
+
+	_, err = repo.GetFileRange(ctx, key, 0, 1)
+	assert.ErrorIs(t, err, pkgErrors.ErrStorageError)
+
+	_, err = repo.GetFileInfo(ctx, key)
+	assert.ErrorIs(t, err, pkgErrors.ErrStorageError)
+	assert.NotErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestGetFileRange(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	key := "companies/c1/files/f1/data.bin"
+
+	_, err := repo.StoreFile(ctx, key, strings.NewReader("0123456789"), 10, "application/octet-stream")
+	require.NoError(t, err)
+
+	object, err := repo.GetFileRange(ctx, key, 2, 5)
+	require.NoError(t, err)
+	data, err := io.ReadAll(object)
+	require.NoError(t, err)
+	require.NoError(t, object.Close())
+	assert.Equal(t, "2345", string(data))
+
+	_, err = repo.GetFileRange(ctx, key, 5, 2)
+	assert.Error(t, err)
+}
+
+func TestDeleteFile(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	key := "companies/c1/files/f1/report.txt"
+
+	_, err := repo.StoreFile(ctx, key, strings.NewReader("data"), 4, "text/plain")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteFile(ctx, key))
+	require.NoError(t, repo.DeleteFile(ctx, key))
+
+	_, err = repo.GetFile(ctx, key)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+
+	entries, err := os.ReadDir(repo.objects)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestListFiles(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	// A file named like the versions directory of its file must not clash with it.
+	keys := []string{
+		"companies/c1/files/f1/versions",
+		"companies/c1/files/f1/versions/v1/versions",
+		"companies/c1/files/f2/photo.png",
+		"companies/c10/files/f3/other.txt",
+		"companies/c2/files/f4/other.txt",
+	}
+	for _, key := range keys {
+		_, err := repo.StoreFile(ctx, key, strings.NewReader(key), int64(len(key)), "")
+		require.NoError(t, err)
+	}
+
+	files, err := repo.ListFiles(ctx, "companies/c1/")
+	require.NoError(t, err)
+
+	listed := make([]string, len(files))
+	for i, file := range files {
+		listed[i] = file.Key
+		assert.Equal(t, int64(len(file.Key)), file.Size)
+	}
+	assert.ElementsMatch(t, keys[:3], listed)
+
+	files, err = repo.ListFiles(ctx, "companies/c1")
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+
+	files, err = repo.ListFiles(ctx, "companies/c3/")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestCopyFile(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	_, err := repo.StoreFile(ctx, "companies/c1/files/f1/a.txt", strings.NewReader("data"), 4, "text/plain")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.CopyFile(ctx, "companies/c1/files/f1/a.txt", "companies/c1/files/f2/b.txt"))
+	assert.Equal(t, "data", readObject(t, repo, "companies/c1/files/f2/b.txt"))
+
+	err = repo.CopyFile(ctx, "companies/c1/files/f9/missing.txt", "companies/c1/files/f2/c.txt")
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestChunkedUpload(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	key := "companies/c1/files/f1/large.bin"
+
+	uploadID, err := repo.InitChunkedUpload(ctx, key, "application/octet-stream")
+	require.NoError(t, err)
+
+	_, err = repo.UploadChunk(ctx, uploadID, "companies/c1/files/f2/other.bin", 0, strings.NewReader("aaa"), 3)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+
+	first, err := repo.UploadChunk(ctx, uploadID, key, 0, strings.NewReader("aaa"), 3)
+	require.NoError(t, err)
+	// A retried chunk is completed with the ETag it was acknowledged with.
+	_, err = repo.UploadChunk(ctx, uploadID, key, 1, strings.NewReader("xxx"), 3)
+	require.NoError(t, err)
+	second, err := repo.UploadChunk(ctx, uploadID, key, 1, strings.NewReader("bbb"), 3)
+	require.NoError(t, err)
+	third, err := repo.UploadChunk(ctx, uploadID, key, 2, strings.NewReader("c"), 1)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.CompleteChunkedUpload(ctx, uploadID, key, []string{first, second, third}))
+	assert.Equal(t, "aaabbbc", readObject(t, repo, key))
+
+	_, err = os.Stat(filepath.Join(repo.uploads, uploadID))
+	assert.True(t, os.IsNotExist(err))
+
+	err = repo.CompleteChunkedUpload(ctx, uploadID, key, []string{first, second, third})
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestChunkedUpload_MissingPart(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	key := "companies/c1/files/f1/large.bin"
+
+	uploadID, err := repo.InitChunkedUpload(ctx, key, "application/octet-stream")
+	require.NoError(t, err)
+	first, err := repo.UploadChunk(ctx, uploadID, key, 0, strings.NewReader("aaa"), 3)
+	require.NoError(t, err)
+
+	err = repo.CompleteChunkedUpload(ctx, uploadID, key, []string{first, "missing"})
+	assert.Error(t, err)
+
+	_, err = repo.GetFile(ctx, key)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+
+	require.NoError(t, repo.AbortChunkedUpload(ctx, uploadID, key))
+	_, err = os.Stat(filepath.Join(repo.uploads, uploadID))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAbortStaleChunkedUploads(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	stale, err := repo.InitChunkedUpload(ctx, "companies/c1/files/f1/a.bin", "")
+	require.NoError(t, err)
+	fresh, err := repo.InitChunkedUpload(ctx, "companies/c1/files/f2/b.bin", "")
+	require.NoError(t, err)
+
+	past := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(repo.uploads, stale, uploadKeyFile), past, past))
+
+	aborted, err := repo.AbortStaleChunkedUploads(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, aborted)
+
+	_, err = os.Stat(filepath.Join(repo.uploads, stale))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(repo.uploads, fresh))
+	assert.NoError(t, err)
+}
+
+func TestGetPresignedURL_Unavailable(t *testing.T) {
+	repo := newTestRepository(t)
+
+	_, err := repo.GetPresignedURL(context.Background(), "companies/c1/files/f1/a.txt", time.Minute, "a.txt")
+	assert.ErrorIs(t, err, pkgErrors.ErrInvalidOperation)
+}
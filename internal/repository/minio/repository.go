@@ -2,11 +2,12 @@ package minio
 
 import (
 	"context"
-	"fmt"
 	"io"
+	"mime"
+	"net/http"
+	"net/url"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"go-storage/internal/domain"
 	"go-storage/pkg/errors"
@@ -14,12 +15,14 @@ import (
 
 type StorageRepository struct {
 	client     *minio.Client
+	core       *minio.Core
 	bucketName string
 }
 
 func NewStorageRepository(client *minio.Client, bucketName string) *StorageRepository {
 	return &StorageRepository{
 		client:     client,
+		core:       &minio.Core{Client: client},
 		bucketName: bucketName,
 	}
 }
@@ -46,6 +49,20 @@ func (r *StorageRepository) GetFile(ctx context.Context, key string) (io.ReadClo
 	return object, nil
 }
 
+func (r *StorageRepository) GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(start, end); err != nil {
+		return nil, errors.BadRequest("invalid byte range")
+	}
+
+	object, err := r.client.GetObject(ctx, r.bucketName, key, opts)
+	if err != nil {
+		return nil, errors.NotFound("file not found in storage")
+	}
+
+	return object, nil
+}
+
 func (r *StorageRepository) DeleteFile(ctx context.Context, key string) error {
 	err := r.client.RemoveObject(ctx, r.bucketName, key, minio.RemoveObjectOptions{})
 	if err != nil {
@@ -56,81 +73,92 @@ func (r *StorageRepository) DeleteFile(ctx context.Context, key string) error {
 }
 
 func (r *StorageRepository) GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error) {
-	info, err := r.client.StatObject(ctx, r.bucketName, key, minio.StatObjectOptions{})
+	info, err := r.client.StatObject(ctx, r.bucketName, key, minio.StatObjectOptions{Checksum: true})
 	if err != nil {
-		return nil, errors.NotFound("file not found in storage")
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, errors.NotFound("file not found in storage")
+		}
+		return nil, errors.InternalServer("failed to get file info from storage")
 	}
 
 	return &domain.StorageFileInfo{
-		Key:          key,
-		Size:         info.Size,
-		MimeType:     info.ContentType,
-		ETag:         info.ETag,
-		LastModified: info.LastModified,
+		Key:            key,
+		Size:           info.Size,
+		MimeType:       info.ContentType,
+		ETag:           info.ETag,
+		ChecksumSHA256: info.ChecksumSHA256,
+		LastModified:   info.LastModified,
 	}, nil
 }
 
+// InitChunkedUpload starts an S3 multipart upload for key and returns its upload ID.
 func (r *StorageRepository) InitChunkedUpload(ctx context.Context, key string, mimeType string) (string, error) {
-	return uuid.NewString(), nil
+	uploadID, err := r.core.NewMultipartUpload(ctx, r.bucketName, key, minio.PutObjectOptions{
+		ContentType: mimeType,
+	})
+	if err != nil {
+		return "", errors.InternalServer("failed to initialize multipart upload")
+	}
+
+	return uploadID, nil
 }
 
+// UploadChunk uploads a chunk as part chunkIndex+1 of the multipart upload and
+// returns the part ETag.
 func (r *StorageRepository) UploadChunk(ctx context.Context, uploadID, key string, chunkIndex int, reader io.Reader, size int64) (string, error) {
-	chunkKey := fmt.Sprintf("%s.chunk.%d", key, chunkIndex)
-	_, err := r.client.PutObject(ctx, r.bucketName, chunkKey, reader, size, minio.PutObjectOptions{})
+	part, err := r.core.PutObjectPart(ctx, r.bucketName, key, uploadID, chunkIndex+1, reader, size, minio.PutObjectPartOptions{})
 	if err != nil {
 		return "", errors.InternalServer("failed to upload chunk")
 	}
 
-	return fmt.Sprintf("chunk-%d", chunkIndex), nil
+	return part.ETag, nil
 }
 
+// CompleteChunkedUpload lets storage assemble the parts, given as ETags ordered
+// by chunk index, into the final object.
 func (r *StorageRepository) CompleteChunkedUpload(ctx context.Context, uploadID, key string, parts []string) error {
-	pr, pw := io.Pipe()
-
-	go func() {
-		defer pw.Close()
-		for i := range parts {
-			chunkKey := fmt.Sprintf("%s.chunk.%d", key, i)
-			obj, err := r.client.GetObject(ctx, r.bucketName, chunkKey, minio.GetObjectOptions{})
-			if err != nil {
-				pw.CloseWithError(err)
-				return
-			}
-			_, err = io.Copy(pw, obj)
-			obj.Close()
-			if err != nil {
-				pw.CloseWithError(err)
-				return
-			}
-		}
-	}()
-
-	_, err := r.client.PutObject(ctx, r.bucketName, key, pr, -1, minio.PutObjectOptions{})
-	if err != nil {
-		return errors.InternalServer("failed to combine chunks")
+	completeParts := make([]minio.CompletePart, len(parts))
+	for i, etag := range parts {
+		completeParts[i] = minio.CompletePart{PartNumber: i + 1, ETag: etag}
 	}
 
-	for i := range parts {
-		chunkKey := fmt.Sprintf("%s.chunk.%d", key, i)
-		r.client.RemoveObject(ctx, r.bucketName, chunkKey, minio.RemoveObjectOptions{})
+	_, err := r.core.CompleteMultipartUpload(ctx, r.bucketName, key, uploadID, completeParts, minio.PutObjectOptions{})
+	if err != nil {
+		return errors.InternalServer("failed to complete multipart upload")
 	}
 
 	return nil
 }
 
+// AbortChunkedUpload aborts the multipart upload, which discards all uploaded
+// parts. Uploads that no longer exist are treated as aborted.
 func (r *StorageRepository) AbortChunkedUpload(ctx context.Context, uploadID, key string) error {
-	obj := r.client.ListObjects(ctx, r.bucketName, minio.ListObjectsOptions{
-		Prefix: key + ".chunk.",
-	})
+	err := r.core.AbortMultipartUpload(ctx, r.bucketName, key, uploadID)
+	if err != nil && minio.ToErrorResponse(err).Code != "NoSuchUpload" {
+		return errors.InternalServer("failed to abort multipart upload")
+	}
 
-	for object := range obj {
-		if object.Err != nil {
+	return nil
+}
+
+// AbortStaleChunkedUploads aborts every multipart upload initiated before
+// olderThan and returns how many were aborted.
+func (r *StorageRepository) AbortStaleChunkedUploads(ctx context.Context, olderThan time.Time) (int, error) {
+	aborted := 0
+	for upload := range r.client.ListIncompleteUploads(ctx, r.bucketName, "", true) {
+		if upload.Err != nil {
+			return aborted, errors.InternalServer("failed to list multipart uploads")
+		}
+		if !upload.Initiated.Before(olderThan) {
+			continue
+		}
+		if err := r.core.AbortMultipartUpload(ctx, r.bucketName, upload.Key, upload.UploadID); err != nil {
 			continue
 		}
-		r.client.RemoveObject(ctx, r.bucketName, object.Key, minio.RemoveObjectOptions{})
+		aborted++
 	}
 
-	return nil
+	return aborted, nil
 }
 
 func (r *StorageRepository) EnsureBucket(ctx context.Context) error {
@@ -149,20 +177,69 @@ func (r *StorageRepository) EnsureBucket(ctx context.Context) error {
 	return nil
 }
 
-func (r *StorageRepository) ListFiles(ctx context.Context, prefix string) (<-chan minio.ObjectInfo, error) {
-	objectCh := r.client.ListObjects(ctx, r.bucketName, minio.ListObjectsOptions{
+// ListFiles returns every object stored under prefix.
+func (r *StorageRepository) ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error) {
+	var files []*domain.StorageFileInfo
+
+	for object := range r.client.ListObjects(ctx, r.bucketName, minio.ListObjectsOptions{
 		Prefix:    prefix,
 		Recursive: true,
-	})
+	}) {
+		if object.Err != nil {
+			return nil, errors.InternalServer("failed to list files in storage")
+		}
+
+		files = append(files, &domain.StorageFileInfo{
+			Key:          object.Key,
+			Size:         object.Size,
+			MimeType:     object.ContentType,
+			ETag:         object.ETag,
+			LastModified: object.LastModified,
+		})
+	}
 
-	return objectCh, nil
+	return files, nil
 }
 
-func (r *StorageRepository) GetPresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
-	url, err := r.client.PresignedGetObject(ctx, r.bucketName, key, expiry, nil)
+// CopyFile copies an object inside the bucket without passing it through the service.
+func (r *StorageRepository) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	_, err := r.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: r.bucketName, Object: dstKey},
+		minio.CopySrcOptions{Bucket: r.bucketName, Object: srcKey},
+	)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return errors.NotFound("file not found in storage")
+		}
+		return errors.InternalServer("failed to copy file in storage")
+	}
+
+	return nil
+}
+
+// GetPresignedURL returns a GET URL for the object that stays valid for expiry.
+// A non-empty filename makes storage serve the object as an attachment with that name.
+func (r *StorageRepository) GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error) {
+	reqParams := url.Values{}
+	if filename != "" {
+		reqParams.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+
+	presigned, err := r.client.PresignedGetObject(ctx, r.bucketName, key, expiry, reqParams)
 	if err != nil {
 		return "", errors.InternalServer("failed to generate presigned URL")
 	}
 
-	return url.String(), nil
+	return presigned.String(), nil
+}
+
+// GetPresignedUploadURL returns a PUT URL for the object that stays valid for
+// expiry. The headers are part of the signature and must be sent with the upload.
+func (r *StorageRepository) GetPresignedUploadURL(ctx context.Context, key string, expiry time.Duration, headers http.Header) (string, error) {
+	presigned, err := r.client.PresignHeader(ctx, http.MethodPut, r.bucketName, key, expiry, nil, headers)
+	if err != nil {
+		return "", errors.InternalServer("failed to generate presigned upload URL")
+	}
+
+	return presigned.String(), nil
 }
@@ -0,0 +1,74 @@
+package minio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-storage/pkg/errors"
+)
+
+// setupRepository points a repository at a fake S3 endpoint that answers every
+// request with handler.
+func setupRepository(t *testing.T, handler http.HandlerFunc) *StorageRepository {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	return NewStorageRepository(client, "bucket")
+}
+
+func TestGetFileInfo_Success(t *testing.T) {
+	repo := setupRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "4")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	})
+
+	info, err := repo.GetFileInfo(context.Background(), "companies/c1/files/f1/report.txt")
+
+	require.NoError(t, err)
+	assert.Equal(t, "companies/c1/files/f1/report.txt", info.Key)
+	assert.Equal(t, int64(4), info.Size)
+	assert.Equal(t, "text/plain", info.MimeType)
+	assert.Equal(t, "etag", info.ETag)
+}
+
+func TestGetFileInfo_NotFound(t *testing.T) {
+	repo := setupRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	info, err := repo.GetFileInfo(context.Background(), "companies/c1/files/f1/report.txt")
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.Nil(t, info)
+}
+
+func TestGetFileInfo_StorageError(t *testing.T) {
+	repo := setupRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	info, err := repo.GetFileInfo(context.Background(), "companies/c1/files/f1/report.txt")
+
+	// Only a missing object is reported as missing, callers give up on it.
+	assert.ErrorIs(t, err, errors.ErrInternalServer)
+	assert.NotErrorIs(t, err, errors.ErrNotFound)
+	assert.Nil(t, info)
+}
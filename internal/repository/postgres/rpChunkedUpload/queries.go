@@ -4,15 +4,16 @@ const QueryCreateChunkedUpload = `
 INSERT INTO chunked_uploads (
     id, file_name, total_size, chunk_size, total_chunks, 
     uploaded_chunks, uploaded_size, status, company_id, user_created,
-    parent_path, target_path, mime_type,
+    parent_path, target_path, mime_type, storage_key, storage_upload_id,
     created_at, updated_at, expires_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 `
 
 const QueryGetChunkedUpload = `
 SELECT id, file_name, total_size, chunk_size, total_chunks,
        uploaded_chunks, uploaded_size, status, company_id, user_created,
        parent_path, target_path, mime_type,
+       COALESCE(storage_key, ''), COALESCE(storage_upload_id, ''),
        created_at, updated_at, expires_at
 FROM chunked_uploads 
 WHERE id = $1 AND company_id = $2
@@ -20,8 +21,8 @@ WHERE id = $1 AND company_id = $2
 
 const QueryUpdateChunkedUpload = `
 UPDATE chunked_uploads 
-SET uploaded_chunks = $2, uploaded_size = $3, status = $4, updated_at = $5
-WHERE id = $1 AND company_id = $6
+SET status = $2, updated_at = $3
+WHERE id = $1 AND company_id = $4
 `
 
 const QueryDeleteChunkedUpload = `
@@ -29,23 +30,50 @@ DELETE FROM chunked_uploads
 WHERE id = $1 AND company_id = $2
 `
 
+const QueryLockChunkedUpload = `
+SELECT id FROM chunked_uploads
+WHERE id = $1
+FOR UPDATE
+`
+
 const QueryAddChunk = `
 INSERT INTO upload_chunks (
-    upload_id, chunk_index, size, etag, uploaded, uploaded_at, retries
-) VALUES ($1, $2, $3, $4, $5, $6, $7)
+    upload_id, chunk_index, size, etag, checksum, uploaded, uploaded_at, retries
+) VALUES ($1, $2, $3, $4, $5, $6, $7, 0)
 ON CONFLICT (upload_id, chunk_index) 
 DO UPDATE SET 
     size = EXCLUDED.size,
     etag = EXCLUDED.etag,
+    checksum = EXCLUDED.checksum,
     uploaded = EXCLUDED.uploaded,
     uploaded_at = EXCLUDED.uploaded_at,
-    retries = EXCLUDED.retries
+    retries = upload_chunks.retries + 1
+`
+
+const QueryRefreshUploadCounters = `
+UPDATE chunked_uploads
+SET uploaded_chunks = (
+        SELECT COUNT(*) FROM upload_chunks WHERE upload_id = $1 AND uploaded
+    ),
+    uploaded_size = (
+        SELECT COALESCE(SUM(size), 0) FROM upload_chunks WHERE upload_id = $1 AND uploaded
+    ),
+    updated_at = $2
+WHERE id = $1
+`
+
+const QueryGetChunks = `
+SELECT chunk_index, size, etag, COALESCE(checksum, ''), uploaded, uploaded_at, retries
+FROM upload_chunks
+WHERE upload_id = $1
+ORDER BY chunk_index
 `
 
 const QueryGetUploadProgress = `
 SELECT cu.id, cu.file_name, cu.total_size, cu.chunk_size, cu.total_chunks,
        cu.uploaded_chunks, cu.uploaded_size, cu.status, cu.company_id, cu.user_created,
        cu.parent_path, cu.target_path, cu.mime_type,
+       COALESCE(cu.storage_key, ''), COALESCE(cu.storage_upload_id, ''),
        cu.created_at, cu.updated_at, cu.expires_at,
        COALESCE(
            json_agg(
@@ -53,6 +81,7 @@ SELECT cu.id, cu.file_name, cu.total_size, cu.chunk_size, cu.total_chunks,
                    'index', uc.chunk_index,
                    'size', uc.size,
                    'etag', uc.etag,
+                   'checksum', COALESCE(uc.checksum, ''),
                    'uploaded', uc.uploaded,
                    'uploaded_at', uc.uploaded_at,
                    'retries', uc.retries
@@ -68,15 +97,30 @@ GROUP BY cu.id
 
 const QueryCleanupExpiredUploads = `
 DELETE FROM chunked_uploads 
-WHERE expires_at < NOW() 
+WHERE chunks_swept
+  AND (expires_at < NOW() AND status <> 'active'
    OR (status = 'failed' AND updated_at < NOW() - INTERVAL '1 day')
-   OR (status = 'completed' AND updated_at < NOW() - INTERVAL '7 days')
+   OR (status = 'completed' AND updated_at < NOW() - INTERVAL '7 days'))
 `
 
 const QueryGetExpiredUploads = `
-SELECT id, company_id, file_name 
+SELECT id, company_id, file_name, COALESCE(storage_key, ''), COALESCE(storage_upload_id, '')
 FROM chunked_uploads 
-WHERE expires_at < NOW() 
-   OR (status = 'failed' AND updated_at < NOW() - INTERVAL '1 day')
-LIMIT 100
+WHERE status = 'active' AND expires_at < NOW()
+ORDER BY expires_at
+LIMIT $1
+`
+
+// QueryGetUnsweptUploads returns sessions from before multipart uploads whose
+// chunk objects have not been removed yet.
+const QueryGetUnsweptUploads = `
+SELECT id, company_id, file_name
+FROM chunked_uploads
+WHERE chunks_swept = false
+ORDER BY created_at
+LIMIT $1
+`
+
+const QueryMarkChunksSwept = `
+UPDATE chunked_uploads SET chunks_swept = true WHERE id = $1
 `
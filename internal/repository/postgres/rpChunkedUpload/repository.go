@@ -23,7 +23,7 @@ func (r *RepositoryChunkedUpload) CreateChunkedUpload(ctx context.Context, uploa
 	_, err := r.db.ExecContext(ctx, QueryCreateChunkedUpload,
 		upload.ID, upload.FileName, upload.TotalSize, upload.ChunkSize, upload.TotalChunks,
 		upload.UploadedChunks, upload.UploadedSize, upload.Status, upload.CompanyID, upload.UserCreateID,
-		upload.ParentPath.String(), upload.TargetPath.String(), upload.MimeType,
+		upload.ParentPath.String(), upload.TargetPath.String(), upload.MimeType, upload.StorageKey, upload.StorageUploadID,
 		upload.CreatedAt, upload.UpdatedAt, upload.ExpiresAt,
 	)
 	if err != nil {
@@ -42,7 +42,7 @@ func (r *RepositoryChunkedUpload) GetChunkedUpload(ctx context.Context, companyI
 	err := row.Scan(
 		&upload.ID, &upload.FileName, &upload.TotalSize, &upload.ChunkSize, &upload.TotalChunks,
 		&upload.UploadedChunks, &upload.UploadedSize, &upload.Status, &upload.CompanyID, &upload.UserCreateID,
-		&parentPathStr, &targetPathStr, &upload.MimeType,
+		&parentPathStr, &targetPathStr, &upload.MimeType, &upload.StorageKey, &upload.StorageUploadID,
 		&upload.CreatedAt, &upload.UpdatedAt, &upload.ExpiresAt,
 	)
 
@@ -65,16 +65,47 @@ func (r *RepositoryChunkedUpload) GetChunkedUpload(ctx context.Context, companyI
 	}
 	upload.TargetPath = targetPath
 
-	upload.Chunks = make(map[int]*domain.ChunkInfo)
+	upload.Chunks, err = r.getChunks(ctx, upload.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &upload, nil
 }
 
+func (r *RepositoryChunkedUpload) getChunks(ctx context.Context, uploadID string) (map[int]*domain.ChunkInfo, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetChunks, uploadID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get upload chunks")
+	}
+	defer rows.Close()
+
+	chunks := make(map[int]*domain.ChunkInfo)
+	for rows.Next() {
+		var chunk domain.ChunkInfo
+		var uploadedAt sql.NullTime
+
+		err := rows.Scan(&chunk.Index, &chunk.Size, &chunk.ETag, &chunk.Checksum, &chunk.Uploaded, &uploadedAt, &chunk.Retries)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan upload chunk")
+		}
+		chunk.UploadedAt = uploadedAt.Time
+
+		chunks[chunk.Index] = &chunk
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get upload chunks")
+	}
+
+	return chunks, nil
+}
+
 func (r *RepositoryChunkedUpload) UpdateChunkedUpload(ctx context.Context, upload *domain.ChunkedUpload) (*domain.ChunkedUpload, error) {
 	upload.UpdatedAt = time.Now()
 
 	_, err := r.db.ExecContext(ctx, QueryUpdateChunkedUpload,
-		upload.ID, upload.UploadedChunks, upload.UploadedSize, upload.Status, upload.UpdatedAt, upload.CompanyID,
+		upload.ID, upload.Status, upload.UpdatedAt, upload.CompanyID,
 	)
 	if err != nil {
 		return nil, pkgErrors.Database("unable to update chunked upload session")
@@ -92,14 +123,49 @@ func (r *RepositoryChunkedUpload) DeleteChunkedUpload(ctx context.Context, compa
 	return nil
 }
 
-func (r *RepositoryChunkedUpload) AddChunk(ctx context.Context, uploadID string, chunkIndex int, etag string, size int64) error {
-	_, err := r.db.ExecContext(ctx, QueryAddChunk,
-		uploadID, chunkIndex, size, etag, true, time.Now(), 0,
+// AddChunk records an upload attempt of a chunk and recounts the session
+// progress from the stored chunks. The session row is locked first, so chunks
+// arriving concurrently on any instance never lose counter updates.
+func (r *RepositoryChunkedUpload) AddChunk(ctx context.Context, uploadID string, chunk *domain.ChunkInfo) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkgErrors.Database("unable to add chunk info")
+	}
+	defer tx.Rollback()
+
+	var lockedID string
+	if err := tx.QueryRowContext(ctx, QueryLockChunkedUpload, uploadID).Scan(&lockedID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pkgErrors.NotFound("chunked upload session not found")
+		}
+		return pkgErrors.Database("unable to add chunk info")
+	}
+
+	var uploadedAt *time.Time
+	if chunk.Uploaded {
+		uploadedAt = &chunk.UploadedAt
+	}
+
+	var checksum *string
+	if chunk.Checksum != "" {
+		checksum = &chunk.Checksum
+	}
+
+	_, err = tx.ExecContext(ctx, QueryAddChunk,
+		uploadID, chunk.Index, chunk.Size, chunk.ETag, checksum, chunk.Uploaded, uploadedAt,
 	)
 	if err != nil {
 		return pkgErrors.Database("unable to add chunk info")
 	}
 
+	if _, err := tx.ExecContext(ctx, QueryRefreshUploadCounters, uploadID, time.Now()); err != nil {
+		return pkgErrors.Database("unable to update upload progress")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return pkgErrors.Database("unable to add chunk info")
+	}
+
 	return nil
 }
 
@@ -113,7 +179,7 @@ func (r *RepositoryChunkedUpload) GetUploadProgress(ctx context.Context, uploadI
 	err := row.Scan(
 		&upload.ID, &upload.FileName, &upload.TotalSize, &upload.ChunkSize, &upload.TotalChunks,
 		&upload.UploadedChunks, &upload.UploadedSize, &upload.Status, &upload.CompanyID, &upload.UserCreateID,
-		&parentPathStr, &targetPathStr, &upload.MimeType,
+		&parentPathStr, &targetPathStr, &upload.MimeType, &upload.StorageKey, &upload.StorageUploadID,
 		&upload.CreatedAt, &upload.UpdatedAt, &upload.ExpiresAt,
 		&chunksJSON,
 	)
@@ -144,6 +210,7 @@ func (r *RepositoryChunkedUpload) GetUploadProgress(ctx context.Context, uploadI
 			Index      int       `json:"index"`
 			Size       int64     `json:"size"`
 			ETag       string    `json:"etag"`
+			Checksum   string    `json:"checksum"`
 			Uploaded   bool      `json:"uploaded"`
 			UploadedAt time.Time `json:"uploaded_at"`
 			Retries    int       `json:"retries"`
@@ -158,6 +225,7 @@ func (r *RepositoryChunkedUpload) GetUploadProgress(ctx context.Context, uploadI
 				Index:      chunk.Index,
 				Size:       chunk.Size,
 				ETag:       chunk.ETag,
+				Checksum:   chunk.Checksum,
 				Uploaded:   chunk.Uploaded,
 				UploadedAt: chunk.UploadedAt,
 				Retries:    chunk.Retries,
@@ -168,34 +236,78 @@ func (r *RepositoryChunkedUpload) GetUploadProgress(ctx context.Context, uploadI
 	return &upload, nil
 }
 
-func (r *RepositoryChunkedUpload) CleanupExpiredUploads(ctx context.Context) error {
-	rows, err := r.db.QueryContext(ctx, QueryGetExpiredUploads)
+// GetExpiredUploads returns active sessions past their expiry, whose storage
+// side upload still has to be aborted.
+func (r *RepositoryChunkedUpload) GetExpiredUploads(ctx context.Context, limit int) ([]*domain.ChunkedUpload, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetExpiredUploads, limit)
 	if err != nil {
-		return pkgErrors.Database("unable to get expired uploads")
+		return nil, pkgErrors.Database("unable to get expired uploads")
 	}
 	defer rows.Close()
 
-	var expiredUploads []struct {
-		ID        string
-		CompanyID string
-		FileName  string
+	var uploads []*domain.ChunkedUpload
+	for rows.Next() {
+		upload := &domain.ChunkedUpload{Status: domain.ChunkedUploadStatusActive}
+		if err := rows.Scan(&upload.ID, &upload.CompanyID, &upload.FileName, &upload.StorageKey, &upload.StorageUploadID); err != nil {
+			return nil, pkgErrors.Database("unable to scan expired upload")
+		}
+		uploads = append(uploads, upload)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get expired uploads")
+	}
+
+	return uploads, nil
+}
+
+// CleanupExpiredUploads deletes finished and expired sessions together with
+// their chunk records and returns how many sessions were deleted. Active
+// sessions are kept until they have been marked expired.
+func (r *RepositoryChunkedUpload) CleanupExpiredUploads(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryCleanupExpiredUploads)
+	if err != nil {
+		return 0, pkgErrors.Database("unable to cleanup expired uploads")
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, pkgErrors.Database("unable to cleanup expired uploads")
+	}
+
+	return deleted, nil
+}
+
+// GetUnsweptUploads returns sessions from before multipart uploads whose
+// key.chunk.N objects are still to be removed.
+func (r *RepositoryChunkedUpload) GetUnsweptUploads(ctx context.Context, limit int) ([]*domain.ChunkedUpload, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetUnsweptUploads, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get unswept uploads")
+	}
+	defer rows.Close()
+
+	var uploads []*domain.ChunkedUpload
 	for rows.Next() {
-		var upload struct {
-			ID        string
-			CompanyID string
-			FileName  string
-		}
+		upload := &domain.ChunkedUpload{}
 		if err := rows.Scan(&upload.ID, &upload.CompanyID, &upload.FileName); err != nil {
-			continue // Skip this one, continue cleanup
+			return nil, pkgErrors.Database("unable to scan unswept upload")
 		}
-		expiredUploads = append(expiredUploads, upload)
+		uploads = append(uploads, upload)
 	}
 
-	_, err = r.db.ExecContext(ctx, QueryCleanupExpiredUploads)
-	if err != nil {
-		return pkgErrors.Database("unable to cleanup expired uploads")
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get unswept uploads")
+	}
+
+	return uploads, nil
+}
+
+// MarkChunksSwept records that the chunk objects of the session are gone, so
+// that it is swept once and cleaned up like any other session.
+func (r *RepositoryChunkedUpload) MarkChunksSwept(ctx context.Context, uploadID string) error {
+	if _, err := r.db.ExecContext(ctx, QueryMarkChunksSwept, uploadID); err != nil {
+		return pkgErrors.Database("unable to mark upload chunks swept")
 	}
 
 	return nil
@@ -208,6 +320,7 @@ func (r *RepositoryChunkedUpload) EnsureChunksTable(ctx context.Context) error {
 		chunk_index INTEGER NOT NULL,
 		size BIGINT NOT NULL,
 		etag VARCHAR(255) NOT NULL,
+		checksum VARCHAR(64),
 		uploaded BOOLEAN DEFAULT false,
 		uploaded_at TIMESTAMP,
 		retries INTEGER DEFAULT 0,
@@ -239,6 +352,9 @@ func (r *RepositoryChunkedUpload) EnsureChunkedUploadsTable(ctx context.Context)
 		parent_path VARCHAR(1000) NOT NULL,
 		target_path VARCHAR(1000) NOT NULL,
 		mime_type VARCHAR(255),
+		storage_key VARCHAR(1000),
+		storage_upload_id VARCHAR(1024),
+		chunks_swept BOOLEAN NOT NULL DEFAULT true,
 		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
 		expires_at TIMESTAMP NOT NULL,
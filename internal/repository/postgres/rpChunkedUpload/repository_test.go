@@ -38,30 +38,32 @@ func TestCreateChunkedUpload_Success(t *testing.T) {
 	targetPath, _ := domain.NewPath("/test.zip")
 
 	upload := &domain.ChunkedUpload{
-		ID:             "upload-id",
-		FileName:       "test.zip",
-		TotalSize:      1024000,
-		ChunkSize:      5242880,
-		TotalChunks:    1,
-		UploadedChunks: 0,
-		UploadedSize:   0,
-		Status:         "active",
-		CompanyID:      "company-id",
-		UserCreateID:   "user-id",
-		ParentPath:     parentPath,
-		TargetPath:     targetPath,
-		MimeType:       "application/zip",
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-		ExpiresAt:      time.Now().Add(24 * time.Hour),
-		Chunks:         make(map[int]*domain.ChunkInfo),
+		ID:              "upload-id",
+		FileName:        "test.zip",
+		TotalSize:       1024000,
+		ChunkSize:       5242880,
+		TotalChunks:     1,
+		UploadedChunks:  0,
+		UploadedSize:    0,
+		Status:          "active",
+		CompanyID:       "company-id",
+		UserCreateID:    "user-id",
+		ParentPath:      parentPath,
+		TargetPath:      targetPath,
+		MimeType:        "application/zip",
+		StorageKey:      "companies/company-id/files/upload-id/test.zip",
+		StorageUploadID: "multipart-upload-id",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		ExpiresAt:       time.Now().Add(24 * time.Hour),
+		Chunks:          make(map[int]*domain.ChunkInfo),
 	}
 
 	mock.ExpectExec(`INSERT INTO chunked_uploads`).
 		WithArgs(
 			upload.ID, upload.FileName, upload.TotalSize, upload.ChunkSize, upload.TotalChunks,
 			upload.UploadedChunks, upload.UploadedSize, upload.Status, upload.CompanyID, upload.UserCreateID,
-			upload.ParentPath.String(), upload.TargetPath.String(), upload.MimeType,
+			upload.ParentPath.String(), upload.TargetPath.String(), upload.MimeType, upload.StorageKey, upload.StorageUploadID,
 			upload.CreatedAt, upload.UpdatedAt, upload.ExpiresAt,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -93,7 +95,7 @@ func TestCreateChunkedUpload_DatabaseError(t *testing.T) {
 	mock.ExpectExec(`INSERT INTO chunked_uploads`).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnError(sql.ErrConnDone)
 
@@ -115,12 +117,12 @@ func TestGetChunkedUpload_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "file_name", "total_size", "chunk_size", "total_chunks",
 		"uploaded_chunks", "uploaded_size", "status", "company_id", "user_created",
-		"parent_path", "target_path", "mime_type",
+		"parent_path", "target_path", "mime_type", "storage_key", "storage_upload_id",
 		"created_at", "updated_at", "expires_at",
 	}).AddRow(
 		uploadID, "test.zip", 1024000, 5242880, 1,
 		0, 0, "active", companyID, "user-id",
-		"/", "/test.zip", "application/zip",
+		"/", "/test.zip", "application/zip", "companies/company-id/files/upload-id/test.zip", "multipart-upload-id",
 		time.Now(), time.Now(), time.Now().Add(24*time.Hour),
 	)
 
@@ -128,13 +130,28 @@ func TestGetChunkedUpload_Success(t *testing.T) {
 		WithArgs(uploadID, companyID).
 		WillReturnRows(rows)
 
+	chunkRows := sqlmock.NewRows([]string{
+		"chunk_index", "size", "etag", "checksum", "uploaded", "uploaded_at", "retries",
+	}).
+		AddRow(0, 5242880, "etag-0", "checksum-0", true, time.Now(), 0).
+		AddRow(1, 5242880, "", "", false, nil, 2)
+
+	mock.ExpectQuery(`SELECT chunk_index, .+ FROM upload_chunks WHERE upload_id = \$1`).
+		WithArgs(uploadID).
+		WillReturnRows(chunkRows)
+
 	result, err := repo.GetChunkedUpload(context.Background(), companyID, uploadID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, uploadID, result.ID)
 	assert.Equal(t, "test.zip", result.FileName)
-	assert.NotNil(t, result.Chunks)
+	assert.Equal(t, "multipart-upload-id", result.StorageUploadID)
+	assert.Len(t, result.Chunks, 2)
+	assert.Equal(t, "etag-0", result.Chunks[0].ETag)
+	assert.Equal(t, "checksum-0", result.Chunks[0].Checksum)
+	assert.False(t, result.Chunks[1].Uploaded)
+	assert.Equal(t, 2, result.Chunks[1].Retries)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -174,9 +191,9 @@ func TestUpdateChunkedUpload_Success(t *testing.T) {
 		TargetPath:     targetPath,
 	}
 
-	mock.ExpectExec(`UPDATE chunked_uploads SET`).
+	mock.ExpectExec(`UPDATE chunked_uploads SET status`).
 		WithArgs(
-			upload.ID, upload.UploadedChunks, upload.UploadedSize, upload.Status, sqlmock.AnyArg(), upload.CompanyID,
+			upload.ID, upload.Status, sqlmock.AnyArg(), upload.CompanyID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -210,38 +227,91 @@ func TestAddChunk_Success(t *testing.T) {
 	defer db.Close()
 
 	uploadID := "upload-id"
-	chunkIndex := 0
-	etag := "etag-123"
-	size := int64(5242880)
+	chunk := &domain.ChunkInfo{
+		Index:      0,
+		Size:       5242880,
+		ETag:       "etag-123",
+		Checksum:   "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72",
+		Uploaded:   true,
+		UploadedAt: time.Now(),
+	}
 
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM chunked_uploads WHERE id = \$1 FOR UPDATE`).
+		WithArgs(uploadID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uploadID))
 	mock.ExpectExec(`INSERT INTO upload_chunks`).
-		WithArgs(
-			uploadID, chunkIndex, size, etag, true, sqlmock.AnyArg(), 0,
-		).
+		WithArgs(uploadID, chunk.Index, chunk.Size, chunk.ETag, chunk.Checksum, true, chunk.UploadedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE chunked_uploads SET uploaded_chunks`).
+		WithArgs(uploadID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	err := repo.AddChunk(context.Background(), uploadID, chunkIndex, etag, size)
+	err := repo.AddChunk(context.Background(), uploadID, chunk)
 
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestAddChunk_FailedAttempt(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	uploadID := "upload-id"
+	chunk := &domain.ChunkInfo{Index: 2, Size: 5242880}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM chunked_uploads WHERE id = \$1 FOR UPDATE`).
+		WithArgs(uploadID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uploadID))
+	mock.ExpectExec(`INSERT INTO upload_chunks`).
+		WithArgs(uploadID, 2, chunk.Size, "", nil, false, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE chunked_uploads SET uploaded_chunks`).
+		WithArgs(uploadID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.AddChunk(context.Background(), uploadID, chunk)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddChunk_SessionNotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM chunked_uploads WHERE id = \$1 FOR UPDATE`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err := repo.AddChunk(context.Background(), "missing", &domain.ChunkInfo{Index: 0})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "chunked upload session not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestAddChunk_DatabaseError(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
 
 	uploadID := "upload-id"
-	chunkIndex := 0
-	etag := "etag-123"
-	size := int64(5242880)
+	chunk := &domain.ChunkInfo{Index: 0, Size: 5242880, ETag: "etag-123", Uploaded: true, UploadedAt: time.Now()}
 
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM chunked_uploads WHERE id = \$1 FOR UPDATE`).
+		WithArgs(uploadID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uploadID))
 	mock.ExpectExec(`INSERT INTO upload_chunks`).
-		WithArgs(
-			uploadID, chunkIndex, size, etag, true, sqlmock.AnyArg(), 0,
-		).
 		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
 
-	err := repo.AddChunk(context.Background(), uploadID, chunkIndex, etag, size)
+	err := repo.AddChunk(context.Background(), uploadID, chunk)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to add chunk info")
@@ -258,12 +328,12 @@ func TestGetUploadProgress_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "file_name", "total_size", "chunk_size", "total_chunks",
 		"uploaded_chunks", "uploaded_size", "status", "company_id", "user_created",
-		"parent_path", "target_path", "mime_type",
+		"parent_path", "target_path", "mime_type", "storage_key", "storage_upload_id",
 		"created_at", "updated_at", "expires_at", "chunks",
 	}).AddRow(
 		uploadID, "test.zip", 5242880, 5242880, 1,
 		1, 5242880, "completed", "company-id", "user-id",
-		"/", "/test.zip", "application/zip",
+		"/", "/test.zip", "application/zip", "companies/company-id/files/upload-id/test.zip", "multipart-upload-id",
 		time.Now(), time.Now(), time.Now().Add(24*time.Hour), chunksJSON,
 	)
 
@@ -293,12 +363,12 @@ func TestGetUploadProgress_EmptyChunks(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "file_name", "total_size", "chunk_size", "total_chunks",
 		"uploaded_chunks", "uploaded_size", "status", "company_id", "user_created",
-		"parent_path", "target_path", "mime_type",
+		"parent_path", "target_path", "mime_type", "storage_key", "storage_upload_id",
 		"created_at", "updated_at", "expires_at", "chunks",
 	}).AddRow(
 		uploadID, "test.zip", 5242880, 5242880, 1,
 		0, 0, "active", "company-id", "user-id",
-		"/", "/test.zip", "application/zip",
+		"/", "/test.zip", "application/zip", "companies/company-id/files/upload-id/test.zip", "multipart-upload-id",
 		time.Now(), time.Now(), time.Now().Add(24*time.Hour), chunksJSON,
 	)
 
@@ -334,64 +404,105 @@ func TestGetUploadProgress_NotFound(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestCleanupExpiredUploads_Success(t *testing.T) {
+func TestGetExpiredUploads_Success(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
 
-	// Mock getting expired uploads
-	expiredRows := sqlmock.NewRows([]string{"id", "company_id", "file_name"}).
-		AddRow("expired-1", "company-1", "expired1.zip").
-		AddRow("expired-2", "company-1", "expired2.zip")
+	expiredRows := sqlmock.NewRows([]string{"id", "company_id", "file_name", "storage_key", "storage_upload_id"}).
+		AddRow("expired-1", "company-1", "expired1.zip", "companies/company-1/files/expired-1/expired1.zip", "multipart-1").
+		AddRow("expired-2", "company-1", "expired2.zip", "", "")
 
-	mock.ExpectQuery(`SELECT id, company_id, file_name FROM chunked_uploads WHERE expires_at < NOW\(\)`).
+	mock.ExpectQuery(`SELECT id, company_id, file_name, .+ FROM chunked_uploads WHERE status = 'active' AND expires_at < NOW\(\)`).
+		WithArgs(100).
 		WillReturnRows(expiredRows)
 
-	// Mock deletion
-	mock.ExpectExec(`DELETE FROM chunked_uploads WHERE expires_at < NOW\(\)`).
-		WillReturnResult(sqlmock.NewResult(2, 2))
-
-	err := repo.CleanupExpiredUploads(context.Background())
+	uploads, err := repo.GetExpiredUploads(context.Background(), 100)
 
 	assert.NoError(t, err)
+	assert.Len(t, uploads, 2)
+	assert.Equal(t, "multipart-1", uploads[0].StorageUploadID)
+	assert.Equal(t, domain.ChunkedUploadStatusActive, uploads[1].Status)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestCleanupExpiredUploads_QueryError(t *testing.T) {
+func TestGetExpiredUploads_QueryError(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
 
-	mock.ExpectQuery(`SELECT id, company_id, file_name FROM chunked_uploads WHERE expires_at < NOW\(\)`).
+	mock.ExpectQuery(`SELECT id, company_id, file_name, .+ FROM chunked_uploads`).
+		WithArgs(100).
 		WillReturnError(sql.ErrConnDone)
 
-	err := repo.CleanupExpiredUploads(context.Background())
+	uploads, err := repo.GetExpiredUploads(context.Background(), 100)
 
 	assert.Error(t, err)
+	assert.Nil(t, uploads)
 	assert.Contains(t, err.Error(), "unable to get expired uploads")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestCleanupExpiredUploads_DeleteError(t *testing.T) {
+func TestCleanupExpiredUploads_Success(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
 
-	// Mock getting expired uploads
-	expiredRows := sqlmock.NewRows([]string{"id", "company_id", "file_name"}).
-		AddRow("expired-1", "company-1", "expired1.zip")
+	mock.ExpectExec(`DELETE FROM chunked_uploads WHERE chunks_swept AND \(expires_at < NOW\(\) AND status <> 'active'`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
 
-	mock.ExpectQuery(`SELECT id, company_id, file_name FROM chunked_uploads WHERE expires_at < NOW\(\)`).
-		WillReturnRows(expiredRows)
+	deleted, err := repo.CleanupExpiredUploads(context.Background())
 
-	// Mock deletion error
-	mock.ExpectExec(`DELETE FROM chunked_uploads WHERE expires_at < NOW\(\)`).
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCleanupExpiredUploads_DeleteError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM chunked_uploads WHERE chunks_swept`).
 		WillReturnError(sql.ErrConnDone)
 
-	err := repo.CleanupExpiredUploads(context.Background())
+	deleted, err := repo.CleanupExpiredUploads(context.Background())
 
 	assert.Error(t, err)
+	assert.Zero(t, deleted)
 	assert.Contains(t, err.Error(), "unable to cleanup expired uploads")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetUnsweptUploads_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "company_id", "file_name"}).
+		AddRow("legacy-1", "company-1", "video.mp4")
+
+	mock.ExpectQuery(`SELECT id, company_id, file_name FROM chunked_uploads WHERE chunks_swept = false`).
+		WithArgs(100).
+		WillReturnRows(rows)
+
+	uploads, err := repo.GetUnsweptUploads(context.Background(), 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, uploads, 1)
+	assert.Equal(t, "video.mp4", uploads[0].FileName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkChunksSwept_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE chunked_uploads SET chunks_swept = true WHERE id = \$1`).
+		WithArgs("legacy-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.MarkChunksSwept(context.Background(), "legacy-1")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestEnsureChunksTable_Success(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
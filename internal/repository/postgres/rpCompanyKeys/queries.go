@@ -0,0 +1,31 @@
+package rpCompanyKeys
+
+const QueryGetCompanyKey = `
+SELECT company_id, wrapped_key, master_key_id, created_at, rotated_at
+FROM company_keys
+WHERE company_id = $1
+`
+
+// QueryCreateCompanyKey keeps the key of a concurrent first upload, which is
+// then read back by the caller.
+const QueryCreateCompanyKey = `
+INSERT INTO company_keys (company_id, wrapped_key, master_key_id, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (company_id) DO NOTHING
+`
+
+const QueryGetKeysToRewrap = `
+SELECT company_id, wrapped_key, master_key_id, created_at, rotated_at
+FROM company_keys
+WHERE master_key_id <> $1
+ORDER BY company_id
+LIMIT $2
+`
+
+// QueryRewrapCompanyKey only replaces the key still wrapped by the master key
+// it was read with.
+const QueryRewrapCompanyKey = `
+UPDATE company_keys
+SET wrapped_key = $2, master_key_id = $3, rotated_at = $4
+WHERE company_id = $1 AND master_key_id = $5
+`
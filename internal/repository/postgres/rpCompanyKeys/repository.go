@@ -0,0 +1,84 @@
+package rpCompanyKeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryCompanyKeys struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryCompanyKeys {
+	return &RepositoryCompanyKeys{db: db}
+}
+
+func (r *RepositoryCompanyKeys) GetCompanyKey(ctx context.Context, companyID string) (*domain.CompanyKey, error) {
+	key := &domain.CompanyKey{}
+
+	err := r.db.QueryRowContext(ctx, QueryGetCompanyKey, companyID).Scan(
+		&key.CompanyID, &key.WrappedKey, &key.MasterKeyID, &key.CreatedAt, &key.RotatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, pkgErrors.NotFound("company key not found")
+	}
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get company key")
+	}
+
+	return key, nil
+}
+
+// CreateCompanyKey stores the key unless the company already has one.
+func (r *RepositoryCompanyKeys) CreateCompanyKey(ctx context.Context, key *domain.CompanyKey) error {
+	_, err := r.db.ExecContext(ctx, QueryCreateCompanyKey, key.CompanyID, key.WrappedKey, key.MasterKeyID, key.CreatedAt)
+	if err != nil {
+		return pkgErrors.Database("unable to create company key")
+	}
+
+	return nil
+}
+
+// GetKeysToRewrap returns up to limit keys wrapped by another master key than
+// masterKeyID.
+func (r *RepositoryCompanyKeys) GetKeysToRewrap(ctx context.Context, masterKeyID string, limit int) ([]*domain.CompanyKey, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetKeysToRewrap, masterKeyID, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get company keys")
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.CompanyKey, 0)
+	for rows.Next() {
+		key := &domain.CompanyKey{}
+		if err := rows.Scan(&key.CompanyID, &key.WrappedKey, &key.MasterKeyID, &key.CreatedAt, &key.RotatedAt); err != nil {
+			return nil, pkgErrors.Database("unable to scan company key")
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get company keys")
+	}
+
+	return keys, nil
+}
+
+// RewrapCompanyKey replaces the wrapped key of the company, provided it is
+// still wrapped by previousMasterKeyID. It reports whether it was replaced.
+func (r *RepositoryCompanyKeys) RewrapCompanyKey(ctx context.Context, key *domain.CompanyKey, previousMasterKeyID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, QueryRewrapCompanyKey, key.CompanyID, key.WrappedKey, key.MasterKeyID, key.RotatedAt, previousMasterKeyID)
+	if err != nil {
+		return false, pkgErrors.Database("unable to rewrap company key")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, pkgErrors.Database("unable to rewrap company key")
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,112 @@
+package rpCompanyKeys
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryCompanyKeys) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func TestGetCompanyKey_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"company_id", "wrapped_key", "master_key_id", "created_at", "rotated_at"}).
+		AddRow("company-id", []byte("wrapped"), "2025-07", createdAt, nil)
+
+	mock.ExpectQuery(`SELECT .+ FROM company_keys WHERE company_id = \$1`).
+		WithArgs("company-id").
+		WillReturnRows(rows)
+
+	key, err := repo.GetCompanyKey(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("wrapped"), key.WrappedKey)
+	assert.Equal(t, "2025-07", key.MasterKeyID)
+	assert.Nil(t, key.RotatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCompanyKey_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT .+ FROM company_keys`).
+		WithArgs("company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	key, err := repo.GetCompanyKey(context.Background(), "company-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, key)
+}
+
+func TestCreateCompanyKey_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	key := &domain.CompanyKey{CompanyID: "company-id", WrappedKey: []byte("wrapped"), MasterKeyID: "2025-07", CreatedAt: time.Now()}
+
+	mock.ExpectExec(`INSERT INTO company_keys .+ ON CONFLICT \(company_id\) DO NOTHING`).
+		WithArgs(key.CompanyID, key.WrappedKey, key.MasterKeyID, key.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.CreateCompanyKey(context.Background(), key)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetKeysToRewrap_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"company_id", "wrapped_key", "master_key_id", "created_at", "rotated_at"}).
+		AddRow("company-1", []byte("wrapped-1"), "2025-01", time.Now(), nil).
+		AddRow("company-2", []byte("wrapped-2"), "2025-01", time.Now(), time.Now())
+
+	mock.ExpectQuery(`SELECT .+ FROM company_keys WHERE master_key_id <> \$1 ORDER BY company_id LIMIT \$2`).
+		WithArgs("2025-07", 100).
+		WillReturnRows(rows)
+
+	keys, err := repo.GetKeysToRewrap(context.Background(), "2025-07", 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "company-2", keys[1].CompanyID)
+	assert.NotNil(t, keys[1].RotatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRewrapCompanyKey_AlreadyRewrapped(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rotatedAt := time.Now()
+	key := &domain.CompanyKey{CompanyID: "company-id", WrappedKey: []byte("rewrapped"), MasterKeyID: "2025-07", RotatedAt: &rotatedAt}
+
+	mock.ExpectExec(`UPDATE company_keys SET wrapped_key = \$2, master_key_id = \$3, rotated_at = \$4 WHERE company_id = \$1 AND master_key_id = \$5`).
+		WithArgs(key.CompanyID, key.WrappedKey, key.MasterKeyID, key.RotatedAt, "2025-01").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rewrapped, err := repo.RewrapCompanyKey(context.Background(), key, "2025-01")
+
+	assert.NoError(t, err)
+	assert.False(t, rewrapped)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,90 @@
+package rpFileAccess
+
+// QueryGetAccessRank returns the highest role, ranked 0 to 3, that user $2 has
+// through the items at the paths $3: ownership of an item they created, or an
+// entry granted to them or to the whole company.
+const QueryGetAccessRank = `
+SELECT COALESCE(MAX(rank), 0) FROM (
+    SELECT 3 AS rank
+    FROM files
+    WHERE company_id = $1 AND is_active = true AND full_path = ANY($3) AND user_created = $2
+    UNION ALL
+    SELECT CASE a.role WHEN 'owner' THEN 3 WHEN 'editor' THEN 2 ELSE 1 END
+    FROM file_access a
+    JOIN files f ON f.id = a.file_id
+    WHERE f.company_id = $1 AND f.is_active = true AND f.full_path = ANY($3)
+      AND (a.user_id = $2 OR a.user_id IS NULL)
+) AS grants
+`
+
+// QueryHasAccessBelow tells whether user $2 has access to any item inside the
+// folder whose contents match the LIKE pattern $3.
+const QueryHasAccessBelow = `
+SELECT EXISTS (
+    SELECT 1
+    FROM files f
+    WHERE f.company_id = $1 AND f.is_active = true AND f.full_path LIKE $3
+      AND (f.user_created = $2 OR EXISTS (
+          SELECT 1 FROM file_access a WHERE a.file_id = f.id AND (a.user_id = $2 OR a.user_id IS NULL)
+      ))
+)
+`
+
+// QueryListGrantedPaths returns the paths matching the LIKE pattern $3 of the
+// items user $2 created or was granted access to.
+const QueryListGrantedPaths = `
+SELECT f.full_path
+FROM files f
+WHERE f.company_id = $1 AND f.is_active = true AND f.full_path LIKE $3
+  AND (f.user_created = $2 OR EXISTS (
+      SELECT 1 FROM file_access a WHERE a.file_id = f.id AND (a.user_id = $2 OR a.user_id IS NULL)
+  ))
+ORDER BY f.full_path ASC
+`
+
+const QueryListEntries = `
+SELECT id, file_id, company_id, user_id, role, granted_by, created_at
+FROM file_access
+WHERE company_id = $1 AND file_id = $2
+ORDER BY user_id NULLS FIRST, created_at ASC
+`
+
+// QueryGrantUserAccess only grants roles to users of the item's company.
+const QueryGrantUserAccess = `
+INSERT INTO file_access (id, file_id, company_id, user_id, role, granted_by, created_at)
+SELECT $1, $2, $3, $4, $5, $6, $7
+WHERE EXISTS (SELECT 1 FROM users WHERE id = $4 AND company_id = $3 AND is_active = true)
+ON CONFLICT (file_id, user_id) WHERE user_id IS NOT NULL
+DO UPDATE SET role = EXCLUDED.role, granted_by = EXCLUDED.granted_by, created_at = EXCLUDED.created_at
+RETURNING id
+`
+
+const QueryGrantCompanyAccess = `
+INSERT INTO file_access (id, file_id, company_id, user_id, role, granted_by, created_at)
+VALUES ($1, $2, $3, NULL, $4, $5, $6)
+ON CONFLICT (file_id) WHERE user_id IS NULL
+DO UPDATE SET role = EXCLUDED.role, granted_by = EXCLUDED.granted_by, created_at = EXCLUDED.created_at
+RETURNING id
+`
+
+const QueryRevokeUserAccess = `
+DELETE FROM file_access
+WHERE company_id = $1 AND file_id = $2 AND user_id = $3
+`
+
+const QueryRevokeCompanyAccess = `
+DELETE FROM file_access
+WHERE company_id = $1 AND file_id = $2 AND user_id IS NULL
+`
+
+const QueryGetSharingSettings = `
+SELECT id, share_new_files
+FROM companies
+WHERE id = $1 AND is_active = true
+`
+
+const QueryUpdateSharingSettings = `
+UPDATE companies
+SET share_new_files = $2
+WHERE id = $1 AND is_active = true
+`
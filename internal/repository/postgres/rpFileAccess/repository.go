@@ -0,0 +1,175 @@
+package rpFileAccess
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"go-storage/internal/domain"
+	"go-storage/pkg/db"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryFileAccess struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFileAccess {
+	return &RepositoryFileAccess{db: db}
+}
+
+// GetAccessRole returns the role of the user on the item at path, taking
+// the roles granted on the folders above it into account.
+func (r *RepositoryFileAccess) GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error) {
+	lineage := path.Lineage()
+	if len(lineage) == 0 {
+		return domain.AccessNone, nil
+	}
+
+	var rank int
+	err := r.db.QueryRowContext(ctx, QueryGetAccessRank, companyID, userID, pq.Array(lineage)).Scan(&rank)
+	if err != nil {
+		return domain.AccessNone, pkgErrors.Database("unable to get access role")
+	}
+
+	return domain.AccessRoleFromRank(rank), nil
+}
+
+// HasAccessBelow reports whether the user has access to anything inside the
+// folder at path, so the folder leads them somewhere.
+func (r *RepositoryFileAccess) HasAccessBelow(ctx context.Context, companyID, userID string, path domain.Path) (bool, error) {
+	var found bool
+	err := r.db.QueryRowContext(ctx, QueryHasAccessBelow, companyID, userID, db.EscapeLike(path.String())+"/%").Scan(&found)
+	if err != nil {
+		return false, pkgErrors.Database("unable to check access")
+	}
+
+	return found, nil
+}
+
+// ListGrantedPaths returns the paths of the items inside the folder at path
+// that the user created or was granted access to. Access inherited from the
+// folders above path is not included.
+func (r *RepositoryFileAccess) ListGrantedPaths(ctx context.Context, companyID, userID string, path domain.Path) ([]domain.Path, error) {
+	pattern := "/%"
+	if !path.IsRoot() {
+		pattern = db.EscapeLike(path.String()) + "/%"
+	}
+
+	rows, err := r.db.QueryContext(ctx, QueryListGrantedPaths, companyID, userID, pattern)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to list granted items")
+	}
+	defer rows.Close()
+
+	paths := []domain.Path{}
+	for rows.Next() {
+		var p domain.Path
+		if err := rows.Scan(&p); err != nil {
+			return nil, pkgErrors.Database("unable to scan granted item")
+		}
+		paths = append(paths, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to list granted items")
+	}
+
+	return paths, nil
+}
+
+func (r *RepositoryFileAccess) ListEntries(ctx context.Context, companyID, fileID string) ([]*domain.AccessEntry, error) {
+	rows, err := r.db.QueryContext(ctx, QueryListEntries, companyID, fileID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to list access entries")
+	}
+	defer rows.Close()
+
+	entries := []*domain.AccessEntry{}
+	for rows.Next() {
+		var entry domain.AccessEntry
+		err := rows.Scan(&entry.ID, &entry.FileID, &entry.CompanyID, &entry.UserID, &entry.Role, &entry.GrantedBy, &entry.CreatedAt)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan access entry")
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to list access entries")
+	}
+
+	return entries, nil
+}
+
+// GrantAccess creates the entry, or changes the role of the existing entry of
+// the same grantee. Users of other companies are not found.
+func (r *RepositoryFileAccess) GrantAccess(ctx context.Context, entry *domain.AccessEntry) (*domain.AccessEntry, error) {
+	var row *sql.Row
+	if entry.UserID == nil {
+		row = r.db.QueryRowContext(ctx, QueryGrantCompanyAccess,
+			entry.ID, entry.FileID, entry.CompanyID, entry.Role, entry.GrantedBy, entry.CreatedAt)
+	} else {
+		row = r.db.QueryRowContext(ctx, QueryGrantUserAccess,
+			entry.ID, entry.FileID, entry.CompanyID, *entry.UserID, entry.Role, entry.GrantedBy, entry.CreatedAt)
+	}
+
+	if err := row.Scan(&entry.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("user not found")
+		}
+		return nil, pkgErrors.Database("unable to grant access")
+	}
+
+	return entry, nil
+}
+
+// RevokeAccess removes the entry of a user, or the company-wide entry when
+// userID is nil.
+func (r *RepositoryFileAccess) RevokeAccess(ctx context.Context, companyID, fileID string, userID *string) error {
+	var res sql.Result
+	var err error
+	if userID == nil {
+		res, err = r.db.ExecContext(ctx, QueryRevokeCompanyAccess, companyID, fileID)
+	} else {
+		res, err = r.db.ExecContext(ctx, QueryRevokeUserAccess, companyID, fileID, *userID)
+	}
+	if err != nil {
+		return pkgErrors.Database("unable to revoke access")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("access entry not found")
+	}
+
+	return nil
+}
+
+func (r *RepositoryFileAccess) GetSettings(ctx context.Context, companyID string) (*domain.SharingSettings, error) {
+	var settings domain.SharingSettings
+
+	err := r.db.QueryRowContext(ctx, QueryGetSharingSettings, companyID).
+		Scan(&settings.CompanyID, &settings.ShareNewFiles)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("company not found")
+		}
+		return nil, pkgErrors.Database("unable to get sharing settings")
+	}
+
+	return &settings, nil
+}
+
+func (r *RepositoryFileAccess) UpdateSettings(ctx context.Context, settings *domain.SharingSettings) (*domain.SharingSettings, error) {
+	res, err := r.db.ExecContext(ctx, QueryUpdateSharingSettings, settings.CompanyID, settings.ShareNewFiles)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to update sharing settings")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, pkgErrors.NotFound("company not found")
+	}
+
+	return settings, nil
+}
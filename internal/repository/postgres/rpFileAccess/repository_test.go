@@ -0,0 +1,216 @@
+package rpFileAccess
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFileAccess) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func TestGetAccessRole_ChecksLineage(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), 0\)`).
+		WithArgs("company-id", "user-id", pq.Array([]string{"/docs", "/docs/q3", "/docs/q3/report.pdf"})).
+		WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow(2))
+
+	role, err := repo.GetAccessRole(context.Background(), "company-id", "user-id", domain.Path("/docs/q3/report.pdf"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AccessEditor, role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAccessRole_RootHasNoRole(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	role, err := repo.GetAccessRole(context.Background(), "company-id", "user-id", domain.Path("/"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AccessNone, role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHasAccessBelow_EscapesPath(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs("company-id", "user-id", `/team\_docs/%`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	found, err := repo.HasAccessBelow(context.Background(), "company-id", "user-id", domain.Path("/team_docs"))
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListGrantedPaths_Root(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT f.full_path`).
+		WithArgs("company-id", "user-id", "/%").
+		WillReturnRows(sqlmock.NewRows([]string{"full_path"}).AddRow("/docs/q3").AddRow("/notes.txt"))
+
+	paths, err := repo.ListGrantedPaths(context.Background(), "company-id", "user-id", domain.Path("/"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Path{"/docs/q3", "/notes.txt"}, paths)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEntries_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "file_id", "company_id", "user_id", "role", "granted_by", "created_at"}).
+		AddRow("entry-1", "file-id", "company-id", nil, "editor", "owner-id", now).
+		AddRow("entry-2", "file-id", "company-id", "user-id", "viewer", "owner-id", now)
+
+	mock.ExpectQuery(`SELECT id, file_id, company_id, user_id, role, granted_by, created_at`).
+		WithArgs("company-id", "file-id").
+		WillReturnRows(rows)
+
+	entries, err := repo.ListEntries(context.Background(), "company-id", "file-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Nil(t, entries[0].UserID)
+	assert.Equal(t, "user-id", *entries[1].UserID)
+	assert.Equal(t, domain.AccessViewer, entries[1].Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGrantAccess_User(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	userID := "user-id"
+	entry := &domain.AccessEntry{ID: "new-id", FileID: "file-id", CompanyID: "company-id", UserID: &userID, Role: domain.AccessEditor, GrantedBy: "owner-id", CreatedAt: now}
+
+	mock.ExpectQuery(`INSERT INTO file_access`).
+		WithArgs("new-id", "file-id", "company-id", "user-id", domain.AccessEditor, "owner-id", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("existing-id"))
+
+	result, err := repo.GrantAccess(context.Background(), entry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "existing-id", result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGrantAccess_UserOfOtherCompany(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	userID := "stranger-id"
+	entry := &domain.AccessEntry{ID: "new-id", FileID: "file-id", CompanyID: "company-id", UserID: &userID, Role: domain.AccessViewer, GrantedBy: "owner-id"}
+
+	mock.ExpectQuery(`INSERT INTO file_access`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	result, err := repo.GrantAccess(context.Background(), entry)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "user not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGrantAccess_Company(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	entry := &domain.AccessEntry{ID: "new-id", FileID: "file-id", CompanyID: "company-id", Role: domain.AccessViewer, GrantedBy: "owner-id", CreatedAt: now}
+
+	mock.ExpectQuery(`ON CONFLICT \(file_id\) WHERE user_id IS NULL`).
+		WithArgs("new-id", "file-id", "company-id", domain.AccessViewer, "owner-id", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("new-id"))
+
+	_, err := repo.GrantAccess(context.Background(), entry)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokeAccess_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	userID := "user-id"
+	mock.ExpectExec(`DELETE FROM file_access`).
+		WithArgs("company-id", "file-id", "user-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.RevokeAccess(context.Background(), "company-id", "file-id", &userID)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "access entry not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokeAccess_Company(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM file_access\s+WHERE company_id = \$1 AND file_id = \$2 AND user_id IS NULL`).
+		WithArgs("company-id", "file-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.RevokeAccess(context.Background(), "company-id", "file-id", nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSettings_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, share_new_files`).
+		WithArgs("company-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "share_new_files"}).AddRow("company-id", true))
+
+	settings, err := repo.GetSettings(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.True(t, settings.ShareNewFiles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSettings_CompanyNotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE companies`).
+		WithArgs("company-id", true).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := repo.UpdateSettings(context.Background(), &domain.SharingSettings{CompanyID: "company-id", ShareNewFiles: true})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "company not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
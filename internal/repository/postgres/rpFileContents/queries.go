@@ -0,0 +1,49 @@
+package rpFileContents
+
+// Queuing a file again keeps its current text searchable until the new one is indexed.
+const QueryEnqueueFile = `
+INSERT INTO file_contents (file_id, company_id, status, queued_at, updated_at)
+VALUES ($1, $2, 'pending', $3, $3)
+ON CONFLICT (file_id) DO UPDATE
+SET status = 'pending', error = NULL, queued_at = EXCLUDED.queued_at, updated_at = EXCLUDED.updated_at
+`
+
+const QueryEnqueueCompany = `
+INSERT INTO file_contents (file_id, company_id, status, queued_at, updated_at)
+SELECT id, company_id, 'pending', $2, $2
+FROM files
+WHERE company_id = $1 AND type = 'file' AND is_active = true AND scan_status = 'clean'
+ON CONFLICT (file_id) DO UPDATE
+SET status = 'pending', error = NULL, queued_at = EXCLUDED.queued_at, updated_at = EXCLUDED.updated_at
+`
+
+const QueryClaimTasks = `
+UPDATE file_contents
+SET status = 'running', updated_at = NOW()
+WHERE file_id IN (
+    SELECT file_id FROM file_contents
+    WHERE status = 'pending' OR (status = 'running' AND updated_at < $1)
+    ORDER BY queued_at ASC
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING file_id, company_id, queued_at
+`
+
+// QuerySaveTask only applies to the claimed request: a file queued again
+// meanwhile stays pending.
+const QuerySaveTask = `
+UPDATE file_contents
+SET status = $3, content = $4::TEXT,
+    search_vector = CASE WHEN $4::TEXT IS NULL THEN NULL ELSE to_tsvector($5::REGCONFIG, $4::TEXT) END,
+    error = $6, indexed_at = $7, updated_at = $7
+WHERE file_id = $1 AND queued_at = $2 AND status = 'running'
+`
+
+const QueryGetStats = `
+SELECT c.status, COUNT(*)
+FROM file_contents c
+JOIN files f ON f.id = c.file_id AND f.is_active = true
+WHERE c.company_id = $1
+GROUP BY c.status
+`
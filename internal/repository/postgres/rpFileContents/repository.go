@@ -0,0 +1,111 @@
+package rpFileContents
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryFileContents struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFileContents {
+	return &RepositoryFileContents{db: db}
+}
+
+// EnqueueFile requests the text of a file to be extracted again.
+func (r *RepositoryFileContents) EnqueueFile(ctx context.Context, companyID, fileID string) error {
+	_, err := r.db.ExecContext(ctx, QueryEnqueueFile, fileID, companyID, time.Now())
+	if err != nil {
+		return pkgErrors.Database("unable to queue file for indexing")
+	}
+
+	return nil
+}
+
+// EnqueueCompany queues every file of the company and returns how many were queued.
+func (r *RepositoryFileContents) EnqueueCompany(ctx context.Context, companyID string) (int, error) {
+	res, err := r.db.ExecContext(ctx, QueryEnqueueCompany, companyID, time.Now())
+	if err != nil {
+		return 0, pkgErrors.Database("unable to queue company files for indexing")
+	}
+
+	affected, _ := res.RowsAffected()
+	return int(affected), nil
+}
+
+// ClaimTasks marks up to limit queued files as running and returns them. Files
+// running since before staleBefore are claimed again, their worker is gone.
+func (r *RepositoryFileContents) ClaimTasks(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.ContentIndexTask, error) {
+	rows, err := r.db.QueryContext(ctx, QueryClaimTasks, staleBefore, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to claim indexing tasks")
+	}
+	defer rows.Close()
+
+	var tasks []*domain.ContentIndexTask
+	for rows.Next() {
+		task := &domain.ContentIndexTask{Status: domain.ContentIndexRunning}
+		if err := rows.Scan(&task.FileID, &task.CompanyID, &task.QueuedAt); err != nil {
+			return nil, pkgErrors.Database("unable to scan indexing task")
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to claim indexing tasks")
+	}
+
+	return tasks, nil
+}
+
+// SaveTask stores the outcome of a task. The content is indexed with the
+// given text search configuration.
+func (r *RepositoryFileContents) SaveTask(ctx context.Context, task *domain.ContentIndexTask, language string) error {
+	_, err := r.db.ExecContext(ctx, QuerySaveTask,
+		task.FileID, task.QueuedAt, task.Status, task.Content, language, task.Error, time.Now(),
+	)
+	if err != nil {
+		return pkgErrors.Database("unable to save file content")
+	}
+
+	return nil
+}
+
+func (r *RepositoryFileContents) GetStats(ctx context.Context, companyID string) (*domain.ContentIndexStats, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetStats, companyID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get indexing stats")
+	}
+	defer rows.Close()
+
+	stats := &domain.ContentIndexStats{}
+	for rows.Next() {
+		var status domain.ContentIndexStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, pkgErrors.Database("unable to scan indexing stats")
+		}
+
+		switch status {
+		case domain.ContentIndexPending, domain.ContentIndexRunning:
+			stats.Pending += count
+		case domain.ContentIndexIndexed:
+			stats.Indexed += count
+		case domain.ContentIndexSkipped:
+			stats.Skipped += count
+		case domain.ContentIndexFailed:
+			stats.Failed += count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get indexing stats")
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,130 @@
+package rpFileContents
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFileContents) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func TestEnqueueFile_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO file_contents .+ ON CONFLICT \(file_id\) DO UPDATE SET status = 'pending'`).
+		WithArgs("file-id", "company-id", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.EnqueueFile(context.Background(), "company-id", "file-id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnqueueCompany_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO file_contents .+ SELECT id, company_id, 'pending', \$2, \$2 FROM files WHERE company_id = \$1 AND type = 'file' AND is_active = true`).
+		WithArgs("company-id", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+
+	count, err := repo.EnqueueCompany(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimTasks_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	staleBefore := time.Now().Add(-10 * time.Minute)
+	queuedAt := time.Now().Add(-time.Minute)
+	rows := sqlmock.NewRows([]string{"file_id", "company_id", "queued_at"}).
+		AddRow("file-id", "company-id", queuedAt)
+
+	mock.ExpectQuery(`UPDATE file_contents SET status = 'running'.+FOR UPDATE SKIP LOCKED \) RETURNING file_id, company_id, queued_at`).
+		WithArgs(staleBefore, 10).
+		WillReturnRows(rows)
+
+	tasks, err := repo.ClaimTasks(context.Background(), staleBefore, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "file-id", tasks[0].FileID)
+	assert.Equal(t, queuedAt, tasks[0].QueuedAt)
+	assert.Equal(t, domain.ContentIndexRunning, tasks[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveTask_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	content := "quarterly report"
+	task := &domain.ContentIndexTask{
+		FileID:   "file-id",
+		QueuedAt: time.Now(),
+		Status:   domain.ContentIndexIndexed,
+		Content:  &content,
+	}
+
+	mock.ExpectExec(`UPDATE file_contents SET status = \$3, content = \$4::TEXT, search_vector = .+to_tsvector\(\$5::REGCONFIG, \$4::TEXT\).+WHERE file_id = \$1 AND queued_at = \$2 AND status = 'running'`).
+		WithArgs("file-id", task.QueuedAt, domain.ContentIndexIndexed, &content, "english", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveTask(context.Background(), task, "english")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStats_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"status", "count"}).
+		AddRow("pending", 3).
+		AddRow("running", 1).
+		AddRow("indexed", 10).
+		AddRow("skipped", 4).
+		AddRow("failed", 2)
+
+	mock.ExpectQuery(`SELECT c.status, COUNT\(\*\) FROM file_contents c`).
+		WithArgs("company-id").
+		WillReturnRows(rows)
+
+	stats, err := repo.GetStats(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.ContentIndexStats{Pending: 4, Indexed: 10, Skipped: 4, Failed: 2}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStats_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT c.status`).WillReturnError(sql.ErrConnDone)
+
+	_, err := repo.GetStats(context.Background(), "company-id")
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
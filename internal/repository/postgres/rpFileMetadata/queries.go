@@ -0,0 +1,51 @@
+package rpFileMetadata
+
+const QueryGetMetadata = `
+SELECT file_id, company_id, tags, metadata, updated_at
+FROM file_metadata
+WHERE file_id = $1 AND company_id = $2
+`
+
+const QuerySaveMetadata = `
+INSERT INTO file_metadata (file_id, company_id, tags, metadata, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (file_id) DO UPDATE
+SET tags = EXCLUDED.tags, metadata = EXCLUDED.metadata, updated_at = EXCLUDED.updated_at
+`
+
+const QueryDeleteMetadata = `
+DELETE FROM file_metadata
+WHERE file_id = $1 AND company_id = $2
+`
+
+const QueryCopyMetadata = `
+INSERT INTO file_metadata (file_id, company_id, tags, metadata, updated_at)
+SELECT $3, company_id, tags, metadata, $4
+FROM file_metadata
+WHERE file_id = $2 AND company_id = $1
+ON CONFLICT (file_id) DO NOTHING
+`
+
+// QueryUpdateTags adds the tags $3 to and removes the tags $4 from the active
+// items $2 in one statement. Items that would end up with more than $6 tags
+// are left unchanged.
+const QueryUpdateTags = `
+INSERT INTO file_metadata (file_id, company_id, tags, updated_at)
+SELECT id, company_id, ARRAY(SELECT unnest($3::TEXT[]) EXCEPT SELECT unnest($4::TEXT[]) ORDER BY 1), $5
+FROM files
+WHERE company_id = $1 AND id = ANY($2::UUID[]) AND is_active = true
+ON CONFLICT (file_id) DO UPDATE
+SET tags = ARRAY(SELECT unnest(file_metadata.tags || $3::TEXT[]) EXCEPT SELECT unnest($4::TEXT[]) ORDER BY 1),
+    updated_at = EXCLUDED.updated_at
+WHERE cardinality(ARRAY(SELECT unnest(file_metadata.tags || $3::TEXT[]) EXCEPT SELECT unnest($4::TEXT[]))) <= $6
+`
+
+const QueryListTags = `
+SELECT tag, COUNT(*)
+FROM file_metadata m
+JOIN files f ON f.id = m.file_id AND f.is_active = true
+CROSS JOIN LATERAL unnest(m.tags) AS tag
+WHERE m.company_id = $1
+GROUP BY tag
+ORDER BY COUNT(*) DESC, tag ASC
+`
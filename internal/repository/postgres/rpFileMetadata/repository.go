@@ -0,0 +1,125 @@
+package rpFileMetadata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stdErrors "errors"
+	"time"
+
+	"github.com/lib/pq"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryFileMetadata struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFileMetadata {
+	return &RepositoryFileMetadata{db: db}
+}
+
+// GetMetadata returns the metadata of an item, empty when none was set.
+func (r *RepositoryFileMetadata) GetMetadata(ctx context.Context, companyID, fileID string) (*domain.FileMetadata, error) {
+	metadata := &domain.FileMetadata{}
+	var values []byte
+
+	err := r.db.QueryRowContext(ctx, QueryGetMetadata, fileID, companyID).Scan(
+		&metadata.FileID, &metadata.CompanyID, pq.Array(&metadata.Tags), &values, &metadata.UpdatedAt,
+	)
+	if stdErrors.Is(err, sql.ErrNoRows) {
+		return &domain.FileMetadata{
+			FileID:    fileID,
+			CompanyID: companyID,
+			Tags:      []string{},
+			Metadata:  map[string]string{},
+		}, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get file metadata")
+	}
+
+	if err := json.Unmarshal(values, &metadata.Metadata); err != nil {
+		return nil, pkgErrors.Database("unable to read file metadata")
+	}
+	if metadata.Tags == nil {
+		metadata.Tags = []string{}
+	}
+
+	return metadata, nil
+}
+
+// SaveMetadata replaces the tags and key/value pairs of an item.
+func (r *RepositoryFileMetadata) SaveMetadata(ctx context.Context, metadata *domain.FileMetadata) error {
+	values, err := json.Marshal(metadata.Metadata)
+	if err != nil {
+		return pkgErrors.InternalServer("unable to encode file metadata")
+	}
+
+	_, err = r.db.ExecContext(ctx, QuerySaveMetadata,
+		metadata.FileID, metadata.CompanyID, pq.Array(metadata.Tags), values, metadata.UpdatedAt,
+	)
+	if err != nil {
+		return pkgErrors.Database("unable to save file metadata")
+	}
+
+	return nil
+}
+
+func (r *RepositoryFileMetadata) DeleteMetadata(ctx context.Context, companyID, fileID string) error {
+	_, err := r.db.ExecContext(ctx, QueryDeleteMetadata, fileID, companyID)
+	if err != nil {
+		return pkgErrors.Database("unable to delete file metadata")
+	}
+
+	return nil
+}
+
+// CopyMetadata gives targetID the metadata of sourceID, if it has any.
+func (r *RepositoryFileMetadata) CopyMetadata(ctx context.Context, companyID, sourceID, targetID string) error {
+	_, err := r.db.ExecContext(ctx, QueryCopyMetadata, companyID, sourceID, targetID, time.Now())
+	if err != nil {
+		return pkgErrors.Database("unable to copy file metadata")
+	}
+
+	return nil
+}
+
+// UpdateTags adds and removes tags on several items at once and returns how
+// many were updated. Items that would exceed maxTags are skipped.
+func (r *RepositoryFileMetadata) UpdateTags(ctx context.Context, companyID string, fileIDs, add, remove []string, maxTags int) (int, error) {
+	res, err := r.db.ExecContext(ctx, QueryUpdateTags,
+		companyID, pq.Array(fileIDs), pq.Array(add), pq.Array(remove), time.Now(), maxTags,
+	)
+	if err != nil {
+		return 0, pkgErrors.Database("unable to update tags")
+	}
+
+	affected, _ := res.RowsAffected()
+	return int(affected), nil
+}
+
+// ListTags returns the tags used in the company, most used first.
+func (r *RepositoryFileMetadata) ListTags(ctx context.Context, companyID string) ([]*domain.TagCount, error) {
+	rows, err := r.db.QueryContext(ctx, QueryListTags, companyID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to list tags")
+	}
+	defer rows.Close()
+
+	tags := []*domain.TagCount{}
+	for rows.Next() {
+		var tag domain.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, pkgErrors.Database("unable to scan tag")
+		}
+		tags = append(tags, &tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to list tags")
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,130 @@
+package rpFileMetadata
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFileMetadata) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func TestGetMetadata_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	updatedAt := time.Now()
+	rows := sqlmock.NewRows([]string{"file_id", "company_id", "tags", "metadata", "updated_at"}).
+		AddRow("file-id", "company-id", "{q3,review}", []byte(`{"project":"PRJ-42"}`), updatedAt)
+
+	mock.ExpectQuery(`SELECT file_id, company_id, tags, metadata, updated_at FROM file_metadata WHERE file_id = \$1 AND company_id = \$2`).
+		WithArgs("file-id", "company-id").
+		WillReturnRows(rows)
+
+	metadata, err := repo.GetMetadata(context.Background(), "company-id", "file-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"q3", "review"}, metadata.Tags)
+	assert.Equal(t, map[string]string{"project": "PRJ-42"}, metadata.Metadata)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMetadata_NotSet(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT .+ FROM file_metadata`).
+		WithArgs("file-id", "company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	metadata, err := repo.GetMetadata(context.Background(), "company-id", "file-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file-id", metadata.FileID)
+	assert.Empty(t, metadata.Tags)
+	assert.Empty(t, metadata.Metadata)
+}
+
+func TestSaveMetadata_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	metadata := &domain.FileMetadata{
+		FileID:    "file-id",
+		CompanyID: "company-id",
+		Tags:      []string{"q3"},
+		Metadata:  map[string]string{"status": "draft"},
+		UpdatedAt: time.Now(),
+	}
+
+	mock.ExpectExec(`INSERT INTO file_metadata .+ ON CONFLICT \(file_id\) DO UPDATE`).
+		WithArgs("file-id", "company-id", pq.Array([]string{"q3"}), []byte(`{"status":"draft"}`), metadata.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveMetadata(context.Background(), metadata)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCopyMetadata_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO file_metadata .+ SELECT \$3, company_id, tags, metadata, \$4 FROM file_metadata WHERE file_id = \$2 AND company_id = \$1`).
+		WithArgs("company-id", "source-id", "target-id", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.CopyMetadata(context.Background(), "company-id", "source-id", "target-id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTags_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO file_metadata .+ FROM files WHERE company_id = \$1 AND id = ANY\(\$2::UUID\[\]\) AND is_active = true ON CONFLICT \(file_id\) DO UPDATE .+ WHERE cardinality`).
+		WithArgs("company-id", pq.Array([]string{"a", "b"}), pq.Array([]string{"q3"}), pq.Array([]string{"draft"}), sqlmock.AnyArg(), 50).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	updated, err := repo.UpdateTags(context.Background(), "company-id", []string{"a", "b"}, []string{"q3"}, []string{"draft"}, 50)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListTags_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"tag", "count"}).
+		AddRow("q3", 12).
+		AddRow("review", 3)
+
+	mock.ExpectQuery(`SELECT tag, COUNT\(\*\) FROM file_metadata m JOIN files f .+ GROUP BY tag`).
+		WithArgs("company-id").
+		WillReturnRows(rows)
+
+	tags, err := repo.ListTags(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, &domain.TagCount{Tag: "q3", Count: 12}, tags[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,16 @@
+package rpFileRenditions
+
+const QueryGetRendition = `
+SELECT file_id, company_id, size, storage_path, mime_type, width, height, byte_size, hash, source_path, created_at
+FROM file_renditions
+WHERE file_id = $1 AND company_id = $2 AND size = $3
+`
+
+const QuerySaveRendition = `
+INSERT INTO file_renditions (file_id, company_id, size, storage_path, mime_type, width, height, byte_size, hash, source_path, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (file_id, size) DO UPDATE
+SET storage_path = EXCLUDED.storage_path, mime_type = EXCLUDED.mime_type, width = EXCLUDED.width,
+    height = EXCLUDED.height, byte_size = EXCLUDED.byte_size, hash = EXCLUDED.hash,
+    source_path = EXCLUDED.source_path, created_at = EXCLUDED.created_at
+`
@@ -0,0 +1,49 @@
+package rpFileRenditions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryFileRenditions struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFileRenditions {
+	return &RepositoryFileRenditions{db: db}
+}
+
+func (r *RepositoryFileRenditions) GetRendition(ctx context.Context, companyID, fileID string, size domain.RenditionSize) (*domain.FileRendition, error) {
+	rendition := &domain.FileRendition{}
+
+	err := r.db.QueryRowContext(ctx, QueryGetRendition, fileID, companyID, size).Scan(
+		&rendition.FileID, &rendition.CompanyID, &rendition.Size, &rendition.StoragePath, &rendition.MimeType,
+		&rendition.Width, &rendition.Height, &rendition.ByteSize, &rendition.Hash, &rendition.SourcePath, &rendition.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, pkgErrors.NotFound("rendition not found")
+	}
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get rendition")
+	}
+
+	return rendition, nil
+}
+
+// SaveRendition records a rendition, replacing the one of the same size
+// generated from an earlier version of the file.
+func (r *RepositoryFileRenditions) SaveRendition(ctx context.Context, rendition *domain.FileRendition) error {
+	_, err := r.db.ExecContext(ctx, QuerySaveRendition,
+		rendition.FileID, rendition.CompanyID, rendition.Size, rendition.StoragePath, rendition.MimeType,
+		rendition.Width, rendition.Height, rendition.ByteSize, rendition.Hash, rendition.SourcePath, rendition.CreatedAt,
+	)
+	if err != nil {
+		return pkgErrors.Database("unable to save rendition")
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package rpFileRenditions
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFileRenditions) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func TestGetRendition_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"file_id", "company_id", "size", "storage_path", "mime_type", "width", "height", "byte_size", "hash", "source_path", "created_at"}).
+		AddRow("file-id", "company-id", "medium", "companies/company-id/renditions/file-id/medium", "image/jpeg", 320, 240, 18432, "abc", "companies/company-id/files/file-id/photo.jpg", createdAt)
+
+	mock.ExpectQuery(`SELECT .+ FROM file_renditions WHERE file_id = \$1 AND company_id = \$2 AND size = \$3`).
+		WithArgs("file-id", "company-id", domain.RenditionMedium).
+		WillReturnRows(rows)
+
+	rendition, err := repo.GetRendition(context.Background(), "company-id", "file-id", domain.RenditionMedium)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RenditionMedium, rendition.Size)
+	assert.Equal(t, 320, rendition.Width)
+	assert.Equal(t, "companies/company-id/files/file-id/photo.jpg", rendition.SourcePath)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRendition_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT .+ FROM file_renditions`).
+		WithArgs("file-id", "company-id", domain.RenditionSmall).
+		WillReturnError(sql.ErrNoRows)
+
+	rendition, err := repo.GetRendition(context.Background(), "company-id", "file-id", domain.RenditionSmall)
+
+	assert.Error(t, err)
+	assert.Nil(t, rendition)
+}
+
+func TestSaveRendition_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rendition := &domain.FileRendition{
+		FileID:      "file-id",
+		CompanyID:   "company-id",
+		Size:        domain.RenditionSmall,
+		StoragePath: "companies/company-id/renditions/file-id/small",
+		MimeType:    "image/png",
+		Width:       128,
+		Height:      64,
+		ByteSize:    2048,
+		Hash:        "abc",
+		SourcePath:  "companies/company-id/files/file-id/logo.png",
+		CreatedAt:   time.Now(),
+	}
+
+	mock.ExpectExec(`INSERT INTO file_renditions .+ ON CONFLICT \(file_id, size\) DO UPDATE`).
+		WithArgs("file-id", "company-id", domain.RenditionSmall, rendition.StoragePath, "image/png", 128, 64, int64(2048), "abc", rendition.SourcePath, rendition.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveRendition(context.Background(), rendition)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
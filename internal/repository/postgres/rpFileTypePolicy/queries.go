@@ -0,0 +1,13 @@
+package rpFileTypePolicy
+
+const QueryGetPolicy = `
+SELECT id, allowed_mime_types, denied_mime_types, allowed_extensions, denied_extensions
+FROM companies
+WHERE id = $1 AND is_active = true
+`
+
+const QueryUpdatePolicy = `
+UPDATE companies
+SET allowed_mime_types = $2, denied_mime_types = $3, allowed_extensions = $4, denied_extensions = $5
+WHERE id = $1 AND is_active = true
+`
@@ -0,0 +1,53 @@
+package rpFileTypePolicy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryFileTypePolicy struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFileTypePolicy {
+	return &RepositoryFileTypePolicy{db: db}
+}
+
+func (r *RepositoryFileTypePolicy) GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error) {
+	var policy domain.FileTypePolicy
+
+	err := r.db.QueryRowContext(ctx, QueryGetPolicy, companyID).Scan(
+		&policy.CompanyID,
+		pq.Array(&policy.AllowedMimeTypes), pq.Array(&policy.DeniedMimeTypes),
+		pq.Array(&policy.AllowedExtensions), pq.Array(&policy.DeniedExtensions),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("company not found")
+		}
+		return nil, pkgErrors.Database("unable to get file type policy")
+	}
+
+	return &policy, nil
+}
+
+func (r *RepositoryFileTypePolicy) UpdatePolicy(ctx context.Context, policy *domain.FileTypePolicy) (*domain.FileTypePolicy, error) {
+	res, err := r.db.ExecContext(ctx, QueryUpdatePolicy, policy.CompanyID,
+		pq.Array(policy.AllowedMimeTypes), pq.Array(policy.DeniedMimeTypes),
+		pq.Array(policy.AllowedExtensions), pq.Array(policy.DeniedExtensions),
+	)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to update file type policy")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, pkgErrors.NotFound("company not found")
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,93 @@
+package rpFileTypePolicy
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFileTypePolicy) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func TestGetPolicy_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "allowed_mime_types", "denied_mime_types", "allowed_extensions", "denied_extensions"}).
+		AddRow("company-id", "{image/*,application/pdf}", "{}", "{}", "{exe,msi}")
+
+	mock.ExpectQuery(`SELECT id, allowed_mime_types, denied_mime_types, allowed_extensions, denied_extensions FROM companies WHERE id = \$1`).
+		WithArgs("company-id").
+		WillReturnRows(rows)
+
+	policy, err := repo.GetPolicy(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"image/*", "application/pdf"}, policy.AllowedMimeTypes)
+	assert.Empty(t, policy.DeniedMimeTypes)
+	assert.Equal(t, []string{"exe", "msi"}, policy.DeniedExtensions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPolicy_CompanyNotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT .+ FROM companies`).
+		WithArgs("company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	policy, err := repo.GetPolicy(context.Background(), "company-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, policy)
+	assert.Contains(t, err.Error(), "company not found")
+}
+
+func TestUpdatePolicy_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	policy := &domain.FileTypePolicy{
+		CompanyID:        "company-id",
+		DeniedMimeTypes:  []string{"application/x-elf"},
+		DeniedExtensions: []string{"exe"},
+	}
+
+	mock.ExpectExec(`UPDATE companies SET allowed_mime_types = \$2, denied_mime_types = \$3, allowed_extensions = \$4, denied_extensions = \$5`).
+		WithArgs("company-id", pq.Array([]string(nil)), pq.Array([]string{"application/x-elf"}), pq.Array([]string(nil)), pq.Array([]string{"exe"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	updated, err := repo.UpdatePolicy(context.Background(), policy)
+
+	assert.NoError(t, err)
+	assert.Equal(t, policy, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdatePolicy_CompanyNotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE companies`).
+		WithArgs("company-id", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := repo.UpdatePolicy(context.Background(), &domain.FileTypePolicy{CompanyID: "company-id"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "company not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,49 @@
+package rpFileVersions
+
+const QueryCreateVersion = `
+INSERT INTO file_versions (
+    id, file_id, version, company_id, user_created,
+    mime_type, size, hash, storage_path, created_at
+)
+SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6, $7, $8, $9
+FROM file_versions
+WHERE file_id = $2
+RETURNING version
+`
+
+// QueryPromoteVersion points the file at the content of a version created in
+// the same transaction. Like a regular update, the scan starts over and new
+// content is stored on the hot tier.
+const QueryPromoteVersion = `
+UPDATE files
+SET mime_type = $3, size = $4, hash = $5, storage_path = $6, updated_at = $7,
+    scan_status = $8, scan_detail = NULL, scan_claimed_at = NULL, scanned_at = NULL,
+    storage_class = CASE WHEN storage_path IS DISTINCT FROM $6 THEN 'hot' ELSE storage_class END
+WHERE id = $1 AND company_id = $2 AND is_active = true
+`
+
+const QueryGetVersions = `
+SELECT id, file_id, version, company_id, user_created,
+       mime_type, size, hash, storage_path, created_at
+FROM file_versions
+WHERE file_id = $1 AND company_id = $2
+ORDER BY version DESC
+`
+
+const QueryGetVersion = `
+SELECT id, file_id, version, company_id, user_created,
+       mime_type, size, hash, storage_path, created_at
+FROM file_versions
+WHERE file_id = $1 AND company_id = $2 AND version = $3
+`
+
+const QueryDeleteVersion = `
+DELETE FROM file_versions
+WHERE id = $1 AND company_id = $2
+`
+
+const QueryCountStoragePathRefs = `
+SELECT (SELECT COUNT(*) FROM file_versions WHERE storage_path = $1)
+     + (SELECT COUNT(*) FROM files WHERE storage_path = $1)
+     + (SELECT COUNT(*) FROM file_renditions WHERE storage_path = $1)
+`
@@ -0,0 +1,193 @@
+package rpFileVersions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+// createVersionAttempts bounds the retries of CreateVersion when concurrent
+// uploads to the same file pick the same version number.
+const createVersionAttempts = 5
+
+// versionNumberIndex keeps version numbers unique per file.
+const versionNumberIndex = "idx_file_versions_file_version"
+
+type RepositoryFileVersions struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFileVersions {
+	return &RepositoryFileVersions{db: db}
+}
+
+func (r *RepositoryFileVersions) CreateVersion(ctx context.Context, version *domain.FileVersion) (*domain.FileVersion, error) {
+	if version.ID == "" {
+		version.ID = uuid.NewString()
+	}
+
+	// The next number is read and inserted in one statement, but two uploads
+	// can still read the same MAX. The unique index rejects the later one,
+	// which then takes the number after the winner's.
+	for attempt := 1; ; attempt++ {
+		err := insertVersion(ctx, r.db, version)
+		if err == nil {
+			return version, nil
+		}
+		if !isVersionConflict(err) {
+			return nil, pkgErrors.Database("unable to create file version")
+		}
+		if attempt == createVersionAttempts {
+			return nil, pkgErrors.Conflict("file is being updated concurrently, try again")
+		}
+	}
+}
+
+// PromoteVersion appends the version and makes it the current content of the
+// file in one transaction, so a failed update leaves no version behind that
+// the file does not point at. Version number conflicts are retried like in
+// CreateVersion.
+func (r *RepositoryFileVersions) PromoteVersion(ctx context.Context, file *domain.File, version *domain.FileVersion) (*domain.File, error) {
+	if version.ID == "" {
+		version.ID = uuid.NewString()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := r.promoteVersion(ctx, file, version)
+		if err == nil {
+			return file, nil
+		}
+		if !isVersionConflict(err) {
+			return nil, err
+		}
+		if attempt == createVersionAttempts {
+			return nil, pkgErrors.Conflict("file is being updated concurrently, try again")
+		}
+	}
+}
+
+// promoteVersion returns version number conflicts as they are, for the caller
+// to retry in a new transaction.
+func (r *RepositoryFileVersions) promoteVersion(ctx context.Context, file *domain.File, version *domain.FileVersion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkgErrors.Database("unable to create file version")
+	}
+	defer tx.Rollback()
+
+	if err := insertVersion(ctx, tx, version); err != nil {
+		if isVersionConflict(err) {
+			return err
+		}
+		return pkgErrors.Database("unable to create file version")
+	}
+
+	res, err := tx.ExecContext(ctx, QueryPromoteVersion,
+		file.ID, file.CompanyId, file.MimeType, file.Size, file.Hash, file.StoragePath, file.UpdatedAt, file.ScanStatus,
+	)
+	if err != nil {
+		return pkgErrors.Database("unable to update file")
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("file not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return pkgErrors.Database("unable to create file version")
+	}
+	return nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// insertVersion stores the version under the next free number of its file.
+func insertVersion(ctx context.Context, q queryRower, version *domain.FileVersion) error {
+	row := q.QueryRowContext(ctx, QueryCreateVersion,
+		version.ID, version.FileID, version.CompanyID, version.UserCreateID,
+		version.MimeType, version.Size, version.Hash, version.StoragePath, version.CreatedAt,
+	)
+	return row.Scan(&version.Version)
+}
+
+func isVersionConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == versionNumberIndex
+}
+
+func (r *RepositoryFileVersions) GetVersions(ctx context.Context, companyID, fileID string) ([]*domain.FileVersion, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetVersions, fileID, companyID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get file versions")
+	}
+	defer rows.Close()
+
+	var versions []*domain.FileVersion
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file version")
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get file versions")
+	}
+
+	return versions, nil
+}
+
+func (r *RepositoryFileVersions) GetVersion(ctx context.Context, companyID, fileID string, version int) (*domain.FileVersion, error) {
+	row := r.db.QueryRowContext(ctx, QueryGetVersion, fileID, companyID, version)
+
+	result, err := scanVersion(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("file version not found")
+		}
+		return nil, pkgErrors.Database("unable to get file version")
+	}
+
+	return result, nil
+}
+
+func (r *RepositoryFileVersions) DeleteVersion(ctx context.Context, companyID, versionID string) error {
+	_, err := r.db.ExecContext(ctx, QueryDeleteVersion, versionID, companyID)
+	if err != nil {
+		return pkgErrors.Database("unable to delete file version")
+	}
+	return nil
+}
+
+func (r *RepositoryFileVersions) CountStoragePathRefs(ctx context.Context, storagePath string) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, QueryCountStoragePathRefs, storagePath).Scan(&count); err != nil {
+		return 0, pkgErrors.Database("unable to count storage path references")
+	}
+	return count, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVersion(row scanner) (*domain.FileVersion, error) {
+	var version domain.FileVersion
+
+	err := row.Scan(
+		&version.ID, &version.FileID, &version.Version, &version.CompanyID, &version.UserCreateID,
+		&version.MimeType, &version.Size, &version.Hash, &version.StoragePath, &version.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
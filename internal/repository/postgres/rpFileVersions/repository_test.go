@@ -0,0 +1,270 @@
+package rpFileVersions
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFileVersions) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func versionColumns() []string {
+	return []string{
+		"id", "file_id", "version", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path", "created_at",
+	}
+}
+
+func TestCreateVersion_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	version := &domain.FileVersion{
+		FileID:       "file-id",
+		CompanyID:    "company-id",
+		UserCreateID: "user-id",
+		MimeType:     "text/plain",
+		Size:         1024,
+		StoragePath:  "companies/company-id/files/file-id/versions/v2/test.txt",
+		CreatedAt:    time.Now(),
+	}
+
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WithArgs(
+			sqlmock.AnyArg(), version.FileID, version.CompanyID, version.UserCreateID,
+			version.MimeType, version.Size, version.Hash, version.StoragePath, version.CreatedAt,
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+
+	result, err := repo.CreateVersion(context.Background(), version)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.ID)
+	assert.Equal(t, 2, result.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateVersion_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WillReturnError(sql.ErrConnDone)
+
+	result, err := repo.CreateVersion(context.Background(), &domain.FileVersion{FileID: "file-id"})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "unable to create file version")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateVersion_RetriesVersionConflict(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	conflict := &pq.Error{Code: "23505", Constraint: versionNumberIndex}
+	mock.ExpectQuery(`INSERT INTO file_versions`).WillReturnError(conflict)
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+	result, err := repo.CreateVersion(context.Background(), &domain.FileVersion{FileID: "file-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateVersion_GivesUpAfterRepeatedConflicts(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	conflict := &pq.Error{Code: "23505", Constraint: versionNumberIndex}
+	for i := 0; i < createVersionAttempts; i++ {
+		mock.ExpectQuery(`INSERT INTO file_versions`).WillReturnError(conflict)
+	}
+
+	result, err := repo.CreateVersion(context.Background(), &domain.FileVersion{FileID: "file-id"})
+
+	assert.ErrorIs(t, err, pkgErrors.ErrConflict)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateVersion_OtherUniqueViolation(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "file_versions_pkey"})
+
+	result, err := repo.CreateVersion(context.Background(), &domain.FileVersion{FileID: "file-id"})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "unable to create file version")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func promotedFile() (*domain.File, *domain.FileVersion) {
+	mimeType := "text/plain"
+	size := int64(2048)
+	storagePath := "companies/company-id/files/file-id/versions/v3/test.txt"
+	file := &domain.File{
+		ID: "file-id", CompanyId: "company-id", MimeType: &mimeType, Size: &size,
+		StoragePath: &storagePath, UpdatedAt: time.Now(), ScanStatus: domain.ScanStatusPending,
+	}
+	version := &domain.FileVersion{
+		ID: "version-id", FileID: "file-id", CompanyID: "company-id", UserCreateID: "user-id",
+		MimeType: mimeType, Size: size, StoragePath: storagePath, CreatedAt: file.UpdatedAt,
+	}
+	return file, version
+}
+
+func TestPromoteVersion_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	file, version := promotedFile()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`UPDATE files SET mime_type = \$3`).
+		WithArgs("file-id", "company-id", file.MimeType, file.Size, file.Hash, file.StoragePath, file.UpdatedAt, domain.ScanStatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.PromoteVersion(context.Background(), file, version)
+
+	assert.NoError(t, err)
+	assert.Equal(t, file, result)
+	assert.Equal(t, 3, version.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteVersion_RetriesVersionConflict(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	file, version := promotedFile()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: versionNumberIndex})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+	mock.ExpectExec(`UPDATE files SET mime_type = \$3`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, err := repo.PromoteVersion(context.Background(), file, version)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, version.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteVersion_FileGone(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	file, version := promotedFile()
+
+	// The version is rolled back with the update it belongs to.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO file_versions`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`UPDATE files SET mime_type = \$3`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	result, err := repo.PromoteVersion(context.Background(), file, version)
+
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetVersions_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(versionColumns()).
+		AddRow("v2", "file-id", 2, "company-id", "user-id", "text/plain", 2048, "hash2", "path/v2", time.Now()).
+		AddRow("v1", "file-id", 1, "company-id", "user-id", "text/plain", 1024, nil, "path/v1", time.Now())
+
+	mock.ExpectQuery(`SELECT .+ FROM file_versions WHERE file_id = \$1 AND company_id = \$2 ORDER BY version DESC`).
+		WithArgs("file-id", "company-id").
+		WillReturnRows(rows)
+
+	result, err := repo.GetVersions(context.Background(), "company-id", "file-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, 2, result[0].Version)
+	assert.Nil(t, result[1].Hash)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetVersion_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT .+ FROM file_versions WHERE file_id = \$1 AND company_id = \$2 AND version = \$3`).
+		WithArgs("file-id", "company-id", 5).
+		WillReturnError(sql.ErrNoRows)
+
+	result, err := repo.GetVersion(context.Background(), "company-id", "file-id", 5)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "file version not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteVersion_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM file_versions`).
+		WithArgs("version-id", "company-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.DeleteVersion(context.Background(), "company-id", "version-id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountStoragePathRefs_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM file_versions`).
+		WithArgs("path/v1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := repo.CountStoragePathRefs(context.Background(), "path/v1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
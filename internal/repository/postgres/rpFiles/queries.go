@@ -3,15 +3,15 @@ package rpFiles
 const QueryCreateFile = `
 INSERT INTO files (
     id, name, type, full_path, parent_id, company_id, user_created,
-    mime_type, size, hash, storage_path,
+    mime_type, size, hash, storage_path, scan_status,
     created_at, updated_at, is_active
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 `
 
 const QueryGetFile = `
 SELECT id, name, type, full_path, parent_id, company_id, user_created,
        mime_type, size, hash, storage_path,
-       created_at, updated_at, is_active
+       created_at, updated_at, is_active, scan_status, storage_class
 FROM files 
 WHERE id = $1 AND company_id = $2 AND is_active = true
 `
@@ -19,7 +19,7 @@ WHERE id = $1 AND company_id = $2 AND is_active = true
 const QueryGetFileByPath = `
 SELECT id, name, type, full_path, parent_id, company_id, user_created,
        mime_type, size, hash, storage_path,
-       created_at, updated_at, is_active
+       created_at, updated_at, is_active, scan_status, storage_class
 FROM files 
 WHERE full_path = $1 AND company_id = $2 AND is_active = true
 `
@@ -27,7 +27,7 @@ WHERE full_path = $1 AND company_id = $2 AND is_active = true
 const QueryGetFolderContents = `
 SELECT id, name, type, full_path, parent_id, company_id, user_created,
        mime_type, size, hash, storage_path,
-       created_at, updated_at, is_active
+       created_at, updated_at, is_active, scan_status, storage_class
 FROM files 
 WHERE parent_id = $1 AND company_id = $2 AND is_active = true
 ORDER BY type DESC, name ASC
@@ -36,26 +36,105 @@ ORDER BY type DESC, name ASC
 const QueryGetFolderContentsByPath = `
 SELECT id, name, type, full_path, parent_id, company_id, user_created,
        mime_type, size, hash, storage_path,
-       created_at, updated_at, is_active
+       created_at, updated_at, is_active, scan_status, storage_class
 FROM files 
 WHERE full_path LIKE $1 AND company_id = $2 AND is_active = true
   AND full_path != $3
 ORDER BY type DESC, name ASC
 `
 
+// QueryGetFolderTree returns the active items matching the escaped subtree
+// pattern $2.
+const QueryGetFolderTree = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class
+FROM files
+WHERE company_id = $1 AND is_active = true AND full_path LIKE $2
+ORDER BY full_path ASC
+`
+
+// QueryListFolder is completed by ListFolder with the scope, filters, keyset
+// condition, order and limit of the requested page.
+const QueryListFolder = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class
+FROM files
+`
+
+// QuerySearchFiles scores exact matches above prefix matches above substring
+// matches, with the trigram similarity ordering items within each group. $2 is
+// the search text, $3 and $4 its prefix and substring LIKE patterns. SearchFiles
+// adds the conditions, order and page.
+const QuerySearchFiles = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class,
+       CASE
+           WHEN lower(name) = lower($2) THEN 3
+           WHEN name ILIKE $3 THEN 2
+           WHEN name ILIKE $4 THEN 1
+           ELSE 0
+       END + similarity(name, $2) AS score
+FROM files
+`
+
+// QuerySearchContent ranks files whose extracted text matches the web-style
+// search text $3, parsed with the text search configuration $2. Snippets are
+// built for the requested page only, ts_headline being the costly part.
+// SearchContent fills in the conditions and the page.
+const QuerySearchContent = `
+WITH query AS (SELECT websearch_to_tsquery($2::REGCONFIG, $3) AS q)
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class,
+       score, ts_headline($2::REGCONFIG, content, (SELECT q FROM query), $4) AS snippet
+FROM (
+    SELECT files.*, c.content, ts_rank_cd(c.search_vector, query.q) AS score
+    FROM files
+    JOIN (
+        SELECT file_id, content, search_vector FROM file_contents WHERE search_vector IS NOT NULL
+    ) AS c ON c.file_id = files.id
+    CROSS JOIN query
+    WHERE c.search_vector @@ query.q AND %s
+    ORDER BY score DESC, name ASC, id ASC
+    LIMIT %s OFFSET %s
+) AS hits
+ORDER BY score DESC, name ASC, id ASC
+`
+
+// ConditionVisibleTo keeps the items the user %[1]s created or was granted
+// access to, the items below them and the folders above them.
+const ConditionVisibleTo = `EXISTS (
+    SELECT 1 FROM files g
+    WHERE g.company_id = files.company_id AND g.is_active = true
+      AND (g.user_created = %[1]s OR g.id IN (SELECT file_id FROM file_access WHERE user_id = %[1]s OR user_id IS NULL))
+      AND (g.full_path = files.full_path
+           OR starts_with(files.full_path, g.full_path || '/')
+           OR starts_with(g.full_path, files.full_path || '/'))
+)`
+
+// ContentSnippetOptions wraps matches in <mark> tags and joins up to three passages.
+const ContentSnippetOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=25, MinWords=10, FragmentDelimiter=" … "`
+
 const QueryGetFolderContentsByType = `
 SELECT id, name, type, full_path, parent_id, company_id, user_created,
        mime_type, size, hash, storage_path,
-       created_at, updated_at, is_active
+       created_at, updated_at, is_active, scan_status, storage_class
 FROM files 
 WHERE parent_id = $1 AND company_id = $2 AND type = $3 AND is_active = true
 ORDER BY name ASC
 `
 
+// QueryUpdateFile replaces the content of a file, whose scan starts over. New
+// content is stored on the hot tier.
 const QueryUpdateFile = `
 UPDATE files 
 SET name = $2, full_path = $3, parent_id = $4, mime_type = $5, 
-    size = $6, hash = $7, storage_path = $8, updated_at = $9
+    size = $6, hash = $7, storage_path = $8, updated_at = $9,
+    scan_status = $11, scan_detail = NULL, scan_claimed_at = NULL, scanned_at = NULL,
+    storage_class = CASE WHEN storage_path IS DISTINCT FROM $8 THEN 'hot' ELSE storage_class END
 WHERE id = $1 AND company_id = $10 AND is_active = true
 `
 
@@ -85,7 +164,8 @@ WHERE full_path = $1 AND company_id = $2 AND is_active = true
 
 const QueryMoveFolderAndContents = `
 UPDATE files 
-SET full_path = REPLACE(full_path, $1, $2), updated_at = $5
+SET full_path = REPLACE(full_path, $1, $2), updated_at = $5,
+    parent_id = CASE WHEN full_path = $1 THEN $6::UUID ELSE parent_id END
 WHERE full_path LIKE $3 AND company_id = $4 AND is_active = true
 `
 
@@ -99,7 +179,162 @@ INSERT INTO files (
 const QueryGetFolder = `
 SELECT id, name, type, full_path, parent_id, company_id, user_created,
        mime_type, size, hash, storage_path,
-       created_at, updated_at, is_active
+       created_at, updated_at, is_active, scan_status, storage_class
 FROM files 
 WHERE full_path = $1 AND company_id = $2 AND type = 'folder' AND is_active = true
 `
+
+const QueryGetStorageRefs = `
+SELECT 'file', id, storage_path, COALESCE(size, 0)
+FROM files
+WHERE company_id = $1 AND storage_path IS NOT NULL
+UNION ALL
+SELECT 'version', id, storage_path, size
+FROM file_versions
+WHERE company_id = $1
+UNION ALL
+SELECT 'presigned_upload', id::text, storage_key, size
+FROM presigned_uploads
+WHERE company_id = $1
+UNION ALL
+SELECT 'chunked_upload', id, storage_key, total_size
+FROM chunked_uploads
+WHERE company_id = $1 AND status = 'active' AND COALESCE(storage_key, '') <> ''
+UNION ALL
+SELECT 'rendition', file_id::text || '/' || size, storage_path, byte_size
+FROM file_renditions
+WHERE company_id = $1
+`
+
+const QueryCountFolderTree = `
+SELECT COUNT(*)
+FROM files
+WHERE company_id = $1 AND is_active = true AND (full_path = $2 OR full_path LIKE $3)
+`
+
+const QuerySumFolderTreeFiles = `
+SELECT COALESCE(SUM(size), 0)::BIGINT, COUNT(*)
+FROM files
+WHERE company_id = $1 AND is_active = true AND type = 'file' AND (full_path = $2 OR full_path LIKE $3)
+`
+
+const QueryDeleteFolderTree = `
+UPDATE files
+SET is_active = false, updated_at = $4
+WHERE company_id = $1 AND is_active = true AND (full_path = $2 OR full_path LIKE $3)
+`
+
+const QueryGetDeletedFolderTreeFiles = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class
+FROM files
+WHERE company_id = $1 AND is_active = false AND updated_at = $4 AND type = 'file'
+  AND (full_path = $2 OR full_path LIKE $3)
+ORDER BY full_path ASC
+LIMIT $5
+`
+
+// QueryClaimScans claims pending files whose previous claim, if any, is older
+// than $1. Trashed files are scanned too, they can still be restored.
+const QueryClaimScans = `
+UPDATE files
+SET scan_claimed_at = NOW()
+WHERE id IN (
+    SELECT id FROM files
+    WHERE scan_status = 'pending' AND type = 'file' AND storage_path IS NOT NULL
+      AND (scan_claimed_at IS NULL OR scan_claimed_at < $1)
+    ORDER BY updated_at ASC
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, name, type, full_path, parent_id, company_id, user_created,
+          mime_type, size, hash, storage_path,
+          created_at, updated_at, is_active, scan_status, storage_class
+`
+
+// QuerySaveScanResult only applies to the scanned object: a file whose content
+// was replaced meanwhile is left pending for its new content.
+const QuerySaveScanResult = `
+UPDATE files
+SET scan_status = $4, scan_detail = $5, scanned_at = $6, scan_claimed_at = NULL
+WHERE id = $1 AND company_id = $2 AND storage_path = $3
+`
+
+// QueryMoveScannedFile points a file at the quarantined or released copy of
+// its content, which is stored on the hot tier, and records the result that
+// led to the move.
+const QueryMoveScannedFile = `
+UPDATE files
+SET storage_path = $4, scan_status = $5, scan_detail = $6, scanned_at = $7, scan_claimed_at = NULL,
+    storage_class = 'hot'
+WHERE id = $1 AND company_id = $2 AND storage_path = $3
+`
+
+// QueryMoveScannedVersions moves the versions sharing the object along, so no
+// older version keeps serving it.
+const QueryMoveScannedVersions = `
+UPDATE file_versions
+SET storage_path = $3
+WHERE company_id = $1 AND storage_path = $2
+`
+
+const QueryGetScanFindings = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class,
+       scan_detail, scanned_at
+FROM files
+WHERE company_id = $1 AND scan_status = $2 AND type = 'file'
+ORDER BY scanned_at DESC NULLS LAST, updated_at DESC
+LIMIT $3
+`
+
+const QueryRequeueScan = `
+UPDATE files
+SET scan_status = 'pending', scan_detail = NULL, scan_claimed_at = NULL
+WHERE id = $1 AND company_id = $2 AND type = 'file' AND storage_path IS NOT NULL
+`
+
+// QueryTouchFile records a download, at most once an hour per file.
+const QueryTouchFile = `
+UPDATE files
+SET last_accessed_at = $3
+WHERE id = $1 AND company_id = $2 AND type = 'file'
+  AND (last_accessed_at IS NULL OR last_accessed_at < $3 - INTERVAL '1 hour')
+`
+
+// QueryGetTransitionCandidates returns hot files, below the folder pattern $2
+// unless it is NULL, that were neither downloaded nor changed since $3.
+// Content that was not found clean stays where the scanner can reach it.
+const QueryGetTransitionCandidates = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class
+FROM files
+WHERE company_id = $1 AND type = 'file' AND is_active = true
+  AND storage_class = 'hot' AND scan_status = 'clean' AND storage_path IS NOT NULL
+  AND ($2::TEXT IS NULL OR full_path LIKE $2)
+  AND GREATEST(last_accessed_at, updated_at) < $3
+ORDER BY GREATEST(last_accessed_at, updated_at) ASC
+LIMIT $4
+`
+
+// QuerySetStorageClass only applies to the moved object: a file whose content
+// was replaced meanwhile keeps the class of its new content.
+const QuerySetStorageClass = `
+UPDATE files
+SET storage_class = $4
+WHERE id = $1 AND company_id = $2 AND storage_path = $3
+`
+
+// QueryTrashExpiredFiles moves the files below the folder pattern $2, or all
+// files of the company when it is NULL, that were not changed since $3 to the
+// trash.
+const QueryTrashExpiredFiles = `
+UPDATE files
+SET is_active = false, updated_at = $4
+WHERE company_id = $1 AND type = 'file' AND is_active = true
+  AND ($2::TEXT IS NULL OR full_path LIKE $2)
+  AND updated_at < $3
+`
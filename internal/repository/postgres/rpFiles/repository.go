@@ -3,11 +3,15 @@ package rpFiles
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"go-storage/internal/domain"
+	"go-storage/pkg/db"
 	pkgErrors "go-storage/pkg/errors"
 )
 
@@ -22,7 +26,7 @@ func NewRepository(db *sql.DB) *RepositoryFiles {
 func (r *RepositoryFiles) CreateFile(ctx context.Context, file *domain.File) (*domain.File, error) {
 	_, err := r.db.ExecContext(ctx, QueryCreateFile,
 		file.ID, file.Name, file.Type, file.FullPath.String(), file.ParentID, file.CompanyId, file.UserCreateID,
-		file.MimeType, file.Size, file.Hash, file.StoragePath,
+		file.MimeType, file.Size, file.Hash, file.StoragePath, file.ScanStatus,
 		file.CreatedAt, file.UpdatedAt, file.IsActive,
 	)
 	if err != nil {
@@ -43,7 +47,7 @@ func (r *RepositoryFiles) GetFile(ctx context.Context, companyID, fileID string)
 	err := row.Scan(
 		&file.ID, &file.Name, &file.Type, &fullPathStr, &file.ParentID, &file.CompanyId, &file.UserCreateID,
 		&file.MimeType, &file.Size, &file.Hash, &file.StoragePath,
-		&file.CreatedAt, &file.UpdatedAt, &file.IsActive,
+		&file.CreatedAt, &file.UpdatedAt, &file.IsActive, &file.ScanStatus, &file.StorageClass,
 	)
 
 	if err != nil {
@@ -71,7 +75,7 @@ func (r *RepositoryFiles) GetFileByPath(ctx context.Context, companyID string, p
 	err := row.Scan(
 		&file.ID, &file.Name, &file.Type, &fullPathStr, &file.ParentID, &file.CompanyId, &file.UserCreateID,
 		&file.MimeType, &file.Size, &file.Hash, &file.StoragePath,
-		&file.CreatedAt, &file.UpdatedAt, &file.IsActive,
+		&file.CreatedAt, &file.UpdatedAt, &file.IsActive, &file.ScanStatus, &file.StorageClass,
 	)
 
 	if err != nil {
@@ -101,8 +105,8 @@ func (r *RepositoryFiles) GetFolderContents(ctx context.Context, companyID strin
 		}
 		rows, err = r.db.QueryContext(ctx, QueryGetFolderContentsByType, parentFolder.ID, companyID, *fileType)
 	} else {
-		pathPattern := path.String()
-		if pathPattern == "/" {
+		pathPattern := db.EscapeLike(path.String())
+		if path.IsRoot() {
 			pathPattern = ""
 		}
 		pathPattern += "/%"
@@ -131,12 +135,284 @@ func (r *RepositoryFiles) GetFolderContents(ctx context.Context, companyID strin
 	return files, nil
 }
 
+// GetFolderTree returns the active items below the folder at any depth, the
+// folder itself excluded.
+func (r *RepositoryFiles) GetFolderTree(ctx context.Context, companyID string, folderPath *domain.Path) ([]*domain.File, error) {
+	pattern := "/%"
+	if !folderPath.IsRoot() {
+		pattern = db.EscapeLike(folderPath.String()) + "/%"
+	}
+
+	rows, err := r.db.QueryContext(ctx, QueryGetFolderTree, companyID, pattern)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get folder tree")
+	}
+	defer rows.Close()
+
+	var files []*domain.File
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get folder tree")
+	}
+
+	return files, nil
+}
+
+// ListFolder returns one page of a folder listing ordered by the query sort,
+// with the item ID as tie-breaker so the keyset cursor is stable. Direct
+// children are matched on parentID, nil meaning the root.
+func (r *RepositoryFiles) ListFolder(ctx context.Context, companyID string, parentID *string, query *domain.FolderListQuery) ([]*domain.File, error) {
+	args := []any{companyID}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"company_id = $1", "is_active = true"}
+
+	switch {
+	case query.Recursive:
+		if !query.Path.IsRoot() {
+			conditions = append(conditions, "full_path LIKE "+arg(db.EscapeLike(query.Path.String())+"/%"))
+		}
+	case parentID != nil:
+		conditions = append(conditions, "parent_id = "+arg(*parentID))
+	default:
+		// Items stored under a folder that does not exist have no parent either.
+		conditions = append(conditions, "parent_id IS NULL", "full_path NOT LIKE '/%/%'")
+	}
+
+	conditions = append(conditions, fileFilterConditions(&query.FileFilter, arg)...)
+
+	keys := folderSortKeys(query.Sort)
+	direction, operator := "ASC", ">"
+	if query.Order == domain.SortOrderDesc {
+		direction, operator = "DESC", "<"
+	}
+
+	if query.Cursor != nil {
+		values := folderCursorValues(query.Sort, query.Cursor)
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			placeholders[i] = arg(value)
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s) %s (%s)",
+			strings.Join(keys, ", "), operator, strings.Join(placeholders, ", ")))
+	}
+
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		order[i] = key + " " + direction
+	}
+
+	statement := QueryListFolder +
+		"WHERE " + strings.Join(conditions, " AND ") +
+		"\nORDER BY " + strings.Join(order, ", ") +
+		"\nLIMIT " + arg(query.Limit)
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to list folder")
+	}
+	defer rows.Close()
+
+	var files []*domain.File
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to list folder")
+	}
+
+	return files, nil
+}
+
+// SearchFiles returns one page of items whose name matches the query exactly,
+// by prefix, as a substring or by trigram similarity, best matches first.
+func (r *RepositoryFiles) SearchFiles(ctx context.Context, companyID string, query *domain.SearchQuery) ([]*domain.SearchHit, error) {
+	pattern := db.EscapeLike(query.Text)
+	args := []any{companyID, query.Text, pattern + "%", "%" + pattern + "%"}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"company_id = $1", "is_active = true", "(name ILIKE $4 OR name % $2)"}
+	if !query.Path.IsRoot() {
+		conditions = append(conditions, "full_path LIKE "+arg(db.EscapeLike(query.Path.String())+"/%"))
+	}
+	conditions = append(conditions, fileFilterConditions(&query.FileFilter, arg)...)
+
+	statement := QuerySearchFiles +
+		"WHERE " + strings.Join(conditions, " AND ") +
+		"\nORDER BY score DESC, name ASC, id ASC" +
+		"\nLIMIT " + arg(query.Limit) + " OFFSET " + arg(query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to search files")
+	}
+	defer rows.Close()
+
+	var hits []*domain.SearchHit
+	for rows.Next() {
+		var hit domain.SearchHit
+		file, err := scanFile(rows, &hit.Score)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		hit.File = file
+		hits = append(hits, &hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to search files")
+	}
+
+	return hits, nil
+}
+
+// SearchContent returns one page of files whose extracted text matches the
+// query, best ranked first, with the matching passages as snippet. language
+// is the text search configuration the contents were indexed with.
+func (r *RepositoryFiles) SearchContent(ctx context.Context, companyID, language string, query *domain.SearchQuery) ([]*domain.SearchHit, error) {
+	args := []any{companyID, language, query.Text, ContentSnippetOptions}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"company_id = $1", "is_active = true"}
+	if !query.Path.IsRoot() {
+		conditions = append(conditions, "full_path LIKE "+arg(db.EscapeLike(query.Path.String())+"/%"))
+	}
+	conditions = append(conditions, fileFilterConditions(&query.FileFilter, arg)...)
+
+	statement := fmt.Sprintf(QuerySearchContent, strings.Join(conditions, " AND "), arg(query.Limit), arg(query.Offset))
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to search file contents")
+	}
+	defer rows.Close()
+
+	var hits []*domain.SearchHit
+	for rows.Next() {
+		var hit domain.SearchHit
+		file, err := scanFile(rows, &hit.Score, &hit.Snippet)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		hit.File = file
+		hits = append(hits, &hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to search file contents")
+	}
+
+	return hits, nil
+}
+
+// fileFilterConditions returns the WHERE conditions of a filter, adding their
+// values through arg.
+func fileFilterConditions(filter *domain.FileFilter, arg func(any) string) []string {
+	var conditions []string
+
+	if filter.Type != nil {
+		conditions = append(conditions, "type = "+arg(*filter.Type))
+	}
+	if filter.MimeType != "" {
+		if prefix, ok := strings.CutSuffix(filter.MimeType, "/*"); ok {
+			conditions = append(conditions, "mime_type LIKE "+arg(db.EscapeLike(prefix)+"/%"))
+		} else {
+			conditions = append(conditions, "mime_type = "+arg(filter.MimeType))
+		}
+	}
+	if filter.MinSize != nil {
+		conditions = append(conditions, "size >= "+arg(*filter.MinSize))
+	}
+	if filter.MaxSize != nil {
+		conditions = append(conditions, "size <= "+arg(*filter.MaxSize))
+	}
+	if filter.UserCreated != "" {
+		conditions = append(conditions, "user_created = "+arg(filter.UserCreated))
+	}
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < "+arg(*filter.CreatedBefore))
+	}
+	if filter.ModifiedAfter != nil {
+		conditions = append(conditions, "updated_at >= "+arg(*filter.ModifiedAfter))
+	}
+	if filter.ModifiedBefore != nil {
+		conditions = append(conditions, "updated_at < "+arg(*filter.ModifiedBefore))
+	}
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, "id IN (SELECT file_id FROM file_metadata WHERE tags @> "+arg(pq.Array(filter.Tags))+"::TEXT[])")
+	}
+	if len(filter.Metadata) > 0 {
+		// Marshalling a map of strings cannot fail.
+		values, _ := json.Marshal(filter.Metadata)
+		conditions = append(conditions, "id IN (SELECT file_id FROM file_metadata WHERE metadata @> "+arg(string(values))+"::JSONB)")
+	}
+	if filter.VisibleTo != "" {
+		conditions = append(conditions, fmt.Sprintf(ConditionVisibleTo, arg(filter.VisibleTo)))
+	}
+
+	return conditions
+}
+
+func folderSortKeys(sort domain.FolderSort) []string {
+	switch sort {
+	case domain.FolderSortSize:
+		return []string{"COALESCE(size, 0)", "id"}
+	case domain.FolderSortUpdatedAt:
+		return []string{"updated_at", "id"}
+	case domain.FolderSortType:
+		return []string{"CASE WHEN type = 'folder' THEN 0 ELSE 1 END", "name", "id"}
+	default:
+		return []string{"name", "id"}
+	}
+}
+
+func folderCursorValues(sort domain.FolderSort, cursor *domain.FolderCursor) []any {
+	switch sort {
+	case domain.FolderSortSize:
+		return []any{cursor.Size, cursor.ID}
+	case domain.FolderSortUpdatedAt:
+		return []any{cursor.UpdatedAt, cursor.ID}
+	case domain.FolderSortType:
+		rank := 1
+		if cursor.Type == domain.FileTypeFolder {
+			rank = 0
+		}
+		return []any{rank, cursor.Name, cursor.ID}
+	default:
+		return []any{cursor.Name, cursor.ID}
+	}
+}
+
 func (r *RepositoryFiles) UpdateFile(ctx context.Context, file *domain.File) (*domain.File, error) {
 	file.UpdatedAt = time.Now()
 
 	_, err := r.db.ExecContext(ctx, QueryUpdateFile,
 		file.ID, file.Name, file.FullPath.String(), file.ParentID, file.MimeType,
-		file.Size, file.Hash, file.StoragePath, file.UpdatedAt, file.CompanyId,
+		file.Size, file.Hash, file.StoragePath, file.UpdatedAt, file.CompanyId, file.ScanStatus,
 	)
 	if err != nil {
 		return nil, pkgErrors.Database("unable to update file")
@@ -236,7 +512,7 @@ func (r *RepositoryFiles) GetFolder(ctx context.Context, companyID string, path
 	err := row.Scan(
 		&folder.ID, &folder.Name, &folder.Type, &fullPathStr, &folder.ParentID, &folder.CompanyId, &folder.UserCreateID,
 		&folder.MimeType, &folder.Size, &folder.Hash, &folder.StoragePath,
-		&folder.CreatedAt, &folder.UpdatedAt, &folder.IsActive,
+		&folder.CreatedAt, &folder.UpdatedAt, &folder.IsActive, &folder.ScanStatus, &folder.StorageClass,
 	)
 
 	if err != nil {
@@ -261,12 +537,21 @@ func (r *RepositoryFiles) MoveFolder(ctx context.Context, companyID string, oldP
 		return nil, err
 	}
 
+	var newParentID *string
+	if newParent := newPath.GetParent(); !newParent.IsRoot() {
+		parent, err := r.GetFolder(ctx, companyID, &newParent)
+		if err != nil {
+			return nil, pkgErrors.BadRequest("destination folder not found")
+		}
+		newParentID = &parent.ID
+	}
+
 	oldPathStr := oldPath.String()
 	newPathStr := newPath.String()
 	pathPattern := oldPathStr + "%"
 
 	_, err = r.db.ExecContext(ctx, QueryMoveFolderAndContents,
-		oldPathStr, newPathStr, pathPattern, companyID, time.Now(),
+		oldPathStr, newPathStr, pathPattern, companyID, time.Now(), newParentID,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "idx_unique_name_in_folder") {
@@ -286,15 +571,219 @@ func (r *RepositoryFiles) DeleteFolder(ctx context.Context, companyID string, fo
 	return nil
 }
 
-func scanFile(rows *sql.Rows) (*domain.File, error) {
+// CountFolderTree returns how many active items the folder and its descendants hold, the folder included.
+func (r *RepositoryFiles) CountFolderTree(ctx context.Context, companyID string, folderPath *domain.Path) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, QueryCountFolderTree, companyID, folderPath.String(), db.EscapeLike(folderPath.String())+"/%").Scan(&count)
+	if err != nil {
+		return 0, pkgErrors.Database("unable to count folder contents")
+	}
+	return count, nil
+}
+
+// SumFolderTreeFiles returns the total size and number of the files below the folder.
+func (r *RepositoryFiles) SumFolderTreeFiles(ctx context.Context, companyID string, folderPath *domain.Path) (int64, int64, error) {
+	var size, count int64
+	err := r.db.QueryRowContext(ctx, QuerySumFolderTreeFiles, companyID, folderPath.String(), db.EscapeLike(folderPath.String())+"/%").Scan(&size, &count)
+	if err != nil {
+		return 0, 0, pkgErrors.Database("unable to sum folder contents")
+	}
+	return size, count, nil
+}
+
+// DeleteFolderTree moves the folder and everything below it to the trash in a
+// single statement. All items share deletedAt so the trash restores them together.
+func (r *RepositoryFiles) DeleteFolderTree(ctx context.Context, companyID string, folderPath *domain.Path, deletedAt time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, QueryDeleteFolderTree, companyID, folderPath.String(), db.EscapeLike(folderPath.String())+"/%", deletedAt)
+	if err != nil {
+		return 0, pkgErrors.Database("unable to delete folder")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, pkgErrors.Database("unable to delete folder")
+	}
+	return int(affected), nil
+}
+
+// GetDeletedFolderTreeFiles returns up to limit files that were deleted together with the folder at deletedAt.
+func (r *RepositoryFiles) GetDeletedFolderTreeFiles(ctx context.Context, companyID string, folderPath *domain.Path, deletedAt time.Time, limit int) ([]*domain.File, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetDeletedFolderTreeFiles, companyID, folderPath.String(), db.EscapeLike(folderPath.String())+"/%", deletedAt, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get deleted folder contents")
+	}
+	defer rows.Close()
+
+	var files []*domain.File
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get deleted folder contents")
+	}
+
+	return files, nil
+}
+
+// ClaimScans marks up to limit files waiting for a malware scan as claimed and
+// returns them. Claims older than staleBefore are taken over, their worker is gone.
+func (r *RepositoryFiles) ClaimScans(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.File, error) {
+	rows, err := r.db.QueryContext(ctx, QueryClaimScans, staleBefore, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to claim file scans")
+	}
+	defer rows.Close()
+
+	var files []*domain.File
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to claim file scans")
+	}
+
+	return files, nil
+}
+
+// SaveScanResult records the result for the file if it still points at the
+// scanned object, and reports whether it did.
+func (r *RepositoryFiles) SaveScanResult(ctx context.Context, file *domain.File, result *domain.ScanResult) (bool, error) {
+	res, err := r.db.ExecContext(ctx, QuerySaveScanResult,
+		file.ID, file.CompanyId, result.StoragePath, result.Status, result.Detail, result.ScannedAt,
+	)
+	if err != nil {
+		return false, pkgErrors.Database("unable to save scan result")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, pkgErrors.Database("unable to save scan result")
+	}
+	return affected > 0, nil
+}
+
+// MoveScannedObject points the file and the versions sharing its object at
+// newPath, the quarantined or released copy of the scanned object. Versions
+// are moved even when the file got new content meanwhile; the returned flag
+// tells whether the file itself was updated.
+func (r *RepositoryFiles) MoveScannedObject(ctx context.Context, file *domain.File, newPath string, result *domain.ScanResult) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, pkgErrors.Database("unable to move scanned object")
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, QueryMoveScannedFile,
+		file.ID, file.CompanyId, result.StoragePath, newPath, result.Status, result.Detail, result.ScannedAt,
+	)
+	if err != nil {
+		return false, pkgErrors.Database("unable to move scanned object")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, pkgErrors.Database("unable to move scanned object")
+	}
+
+	if _, err := tx.ExecContext(ctx, QueryMoveScannedVersions, file.CompanyId, result.StoragePath, newPath); err != nil {
+		return false, pkgErrors.Database("unable to move scanned versions")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, pkgErrors.Database("unable to move scanned object")
+	}
+
+	return affected > 0, nil
+}
+
+// GetScanFindings returns up to limit files of the company with the given scan
+// status, most recently scanned first.
+func (r *RepositoryFiles) GetScanFindings(ctx context.Context, companyID string, status domain.ScanStatus, limit int) ([]*domain.ScanFinding, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetScanFindings, companyID, status, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get scan findings")
+	}
+	defer rows.Close()
+
+	var findings []*domain.ScanFinding
+	for rows.Next() {
+		var finding domain.ScanFinding
+		file, err := scanFile(rows, &finding.Detail, &finding.ScannedAt)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		finding.File = file
+		findings = append(findings, &finding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get scan findings")
+	}
+
+	return findings, nil
+}
+
+// RequeueScan sets the file back to pending so its content is scanned again.
+func (r *RepositoryFiles) RequeueScan(ctx context.Context, companyID, fileID string) error {
+	res, err := r.db.ExecContext(ctx, QueryRequeueScan, fileID, companyID)
+	if err != nil {
+		return pkgErrors.Database("unable to queue file scan")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("file not found")
+	}
+	return nil
+}
+
+// GetStorageRefs returns every row of the company that points at a storage
+// object: files (trashed ones included), versions and pending uploads.
+func (r *RepositoryFiles) GetStorageRefs(ctx context.Context, companyID string) ([]*domain.StorageRef, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetStorageRefs, companyID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get storage references")
+	}
+	defer rows.Close()
+
+	var refs []*domain.StorageRef
+	for rows.Next() {
+		var ref domain.StorageRef
+		if err := rows.Scan(&ref.Source, &ref.ID, &ref.Key, &ref.Size); err != nil {
+			return nil, pkgErrors.Database("unable to scan storage reference")
+		}
+		refs = append(refs, &ref)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get storage references")
+	}
+
+	return refs, nil
+}
+
+// scanFile reads the file columns of a row; extra receives the columns
+// selected after them.
+func scanFile(rows *sql.Rows, extra ...any) (*domain.File, error) {
 	var file domain.File
 	var fullPathStr string
 
-	err := rows.Scan(
+	dest := []any{
 		&file.ID, &file.Name, &file.Type, &fullPathStr, &file.ParentID, &file.CompanyId, &file.UserCreateID,
 		&file.MimeType, &file.Size, &file.Hash, &file.StoragePath,
-		&file.CreatedAt, &file.UpdatedAt, &file.IsActive,
-	)
+		&file.CreatedAt, &file.UpdatedAt, &file.IsActive, &file.ScanStatus, &file.StorageClass,
+	}
+
+	err := rows.Scan(append(dest, extra...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -307,3 +796,78 @@ func scanFile(rows *sql.Rows) (*domain.File, error) {
 
 	return &file, nil
 }
+
+// TouchFile records that the file was downloaded at accessedAt.
+func (r *RepositoryFiles) TouchFile(ctx context.Context, companyID, fileID string, accessedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, QueryTouchFile, fileID, companyID, accessedAt)
+	if err != nil {
+		return pkgErrors.Database("unable to record file access")
+	}
+	return nil
+}
+
+// GetTransitionCandidates returns up to limit hot files of the company, below
+// folderPath when it is set, that were last downloaded or changed before
+// accessedBefore, least recently used first.
+func (r *RepositoryFiles) GetTransitionCandidates(ctx context.Context, companyID string, folderPath *domain.Path, accessedBefore time.Time, limit int) ([]*domain.File, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetTransitionCandidates, companyID, treePattern(folderPath), accessedBefore, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get lifecycle candidates")
+	}
+	defer rows.Close()
+
+	var files []*domain.File
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan file")
+		}
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get lifecycle candidates")
+	}
+
+	return files, nil
+}
+
+// SetStorageClass records the tier now holding the file's object. It reports
+// false when the file no longer points at that object.
+func (r *RepositoryFiles) SetStorageClass(ctx context.Context, file *domain.File, class domain.StorageClass) (bool, error) {
+	res, err := r.db.ExecContext(ctx, QuerySetStorageClass, file.ID, file.CompanyId, file.StoragePath, class)
+	if err != nil {
+		return false, pkgErrors.Database("unable to update storage class")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, pkgErrors.Database("unable to update storage class")
+	}
+	return affected > 0, nil
+}
+
+// TrashExpiredFiles moves the files of the company, below folderPath when it
+// is set, that were last changed before modifiedBefore to the trash and
+// returns how many were moved.
+func (r *RepositoryFiles) TrashExpiredFiles(ctx context.Context, companyID string, folderPath *domain.Path, modifiedBefore, deletedAt time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, QueryTrashExpiredFiles, companyID, treePattern(folderPath), modifiedBefore, deletedAt)
+	if err != nil {
+		return 0, pkgErrors.Database("unable to trash expired files")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, pkgErrors.Database("unable to trash expired files")
+	}
+	return int(affected), nil
+}
+
+// treePattern matches everything below the folder, or is nil for no folder.
+func treePattern(folderPath *domain.Path) *string {
+	if folderPath == nil {
+		return nil
+	}
+	pattern := db.EscapeLike(folderPath.String()) + "/%"
+	return &pattern
+}
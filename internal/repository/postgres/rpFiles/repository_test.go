@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"go-storage/internal/domain"
 )
@@ -47,6 +48,7 @@ func TestCreateFile_Success(t *testing.T) {
 		Size:         int64Ptr(1024),
 		Hash:         stringPtr("hash123"),
 		StoragePath:  stringPtr("storage/path"),
+		ScanStatus:   domain.ScanStatusPending,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 		IsActive:     true,
@@ -55,7 +57,7 @@ func TestCreateFile_Success(t *testing.T) {
 	mock.ExpectExec(`INSERT INTO files`).
 		WithArgs(
 			file.ID, file.Name, file.Type, file.FullPath.String(), file.ParentID, file.CompanyId, file.UserCreateID,
-			file.MimeType, file.Size, file.Hash, file.StoragePath,
+			file.MimeType, file.Size, file.Hash, file.StoragePath, file.ScanStatus,
 			file.CreatedAt, file.UpdatedAt, file.IsActive,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -86,7 +88,7 @@ func TestCreateFile_UniqueConstraintViolation(t *testing.T) {
 	mock.ExpectExec(`INSERT INTO files`).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnError(&mockError{message: "idx_unique_name_in_folder constraint violated"})
 
 	result, err := repo.CreateFile(context.Background(), file)
@@ -108,11 +110,11 @@ func TestGetFile_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
 		"mime_type", "size", "hash", "storage_path",
-		"created_at", "updated_at", "is_active",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
 	}).AddRow(
 		fileID, "test.txt", domain.FileTypeFile, path, nil, companyID, "user-id",
 		"text/plain", 1024, "hash123", "storage/path",
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM files WHERE id = \$1 AND company_id = \$2 AND is_active = true`).
@@ -157,11 +159,11 @@ func TestGetFileByPath_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
 		"mime_type", "size", "hash", "storage_path",
-		"created_at", "updated_at", "is_active",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
 	}).AddRow(
 		"file-id", "test.txt", domain.FileTypeFile, "/test.txt", nil, companyID, "user-id",
 		"text/plain", 1024, "hash123", "storage/path",
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM files WHERE full_path = \$1 AND company_id = \$2 AND is_active = true`).
@@ -186,25 +188,25 @@ func TestGetFolderContents_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
 		"mime_type", "size", "hash", "storage_path",
-		"created_at", "updated_at", "is_active",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
 	}).AddRow(
 		"file1", "file1.txt", domain.FileTypeFile, "/file1.txt", "parent-id", companyID, "user-id",
 		"text/plain", 1024, "hash1", "storage/path1",
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	).AddRow(
 		"folder1", "folder1", domain.FileTypeFolder, "/folder1", "parent-id", companyID, "user-id",
 		nil, nil, nil, nil,
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	)
 
 	parentRows := sqlmock.NewRows([]string{
 		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
 		"mime_type", "size", "hash", "storage_path",
-		"created_at", "updated_at", "is_active",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
 	}).AddRow(
 		"parent-id", "root", domain.FileTypeFolder, "/", nil, companyID, "user-id",
 		nil, nil, nil, nil,
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM files WHERE full_path = \$1 AND company_id = \$2 AND is_active = true`).
@@ -224,6 +226,55 @@ func TestGetFolderContents_Success(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetFolderContents_EscapesWildcards(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	path := domain.Path("/a_b")
+
+	mock.ExpectQuery(`SELECT .+ FROM files WHERE full_path LIKE \$1`).
+		WithArgs(`/a\_b/%`, "company-id", "/a_b").
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	_, err := repo.GetFolderContents(context.Background(), "company-id", &path, nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFolderTree_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	path := domain.Path("/a_b")
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+	}).AddRow(
+		"folder-id", "docs", domain.FileTypeFolder, "/a_b/docs", "root-id", "company-id", "user-id",
+		nil, nil, nil, nil,
+		now, now, true, "clean", "hot",
+	).AddRow(
+		"file-id", "a.txt", domain.FileTypeFile, "/a_b/docs/a.txt", "folder-id", "company-id", "user-id",
+		"text/plain", 10, "hash", "storage/path",
+		now, now, true, "clean", "hot",
+	)
+
+	// The underscore is escaped, so /aXb is not part of the tree.
+	mock.ExpectQuery(`SELECT .+ FROM files WHERE company_id = \$1 AND is_active = true AND full_path LIKE \$2 ORDER BY full_path ASC`).
+		WithArgs("company-id", `/a\_b/%`).
+		WillReturnRows(rows)
+
+	files, err := repo.GetFolderTree(context.Background(), "company-id", &path)
+
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdateFile_Success(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
@@ -239,12 +290,13 @@ func TestUpdateFile_Success(t *testing.T) {
 		Hash:        stringPtr("newhash"),
 		StoragePath: stringPtr("new/storage/path"),
 		CompanyId:   "company-id",
+		ScanStatus:  domain.ScanStatusPending,
 	}
 
 	mock.ExpectExec(`UPDATE files SET`).
 		WithArgs(
 			file.ID, file.Name, file.FullPath.String(), file.ParentID, file.MimeType,
-			file.Size, file.Hash, file.StoragePath, sqlmock.AnyArg(), file.CompanyId,
+			file.Size, file.Hash, file.StoragePath, sqlmock.AnyArg(), file.CompanyId, file.ScanStatus,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -267,11 +319,11 @@ func TestRenameFile_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
 		"mime_type", "size", "hash", "storage_path",
-		"created_at", "updated_at", "is_active",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
 	}).AddRow(
 		fileID, "old.txt", domain.FileTypeFile, "/old.txt", nil, companyID, "user-id",
 		"text/plain", 1024, "hash123", "storage/path",
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM files WHERE id = \$1 AND company_id = \$2 AND is_active = true`).
@@ -350,11 +402,11 @@ func TestGetFolder_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
 		"mime_type", "size", "hash", "storage_path",
-		"created_at", "updated_at", "is_active",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
 	}).AddRow(
 		"folder-id", "test-folder", domain.FileTypeFolder, "/test-folder", nil, companyID, "user-id",
 		nil, nil, nil, nil,
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM files WHERE full_path = \$1 AND company_id = \$2 AND type = 'folder' AND is_active = true`).
@@ -381,19 +433,19 @@ func TestMoveFolder_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
 		"mime_type", "size", "hash", "storage_path",
-		"created_at", "updated_at", "is_active",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
 	}).AddRow(
 		"folder-id", "old-folder", domain.FileTypeFolder, "/old-folder", nil, companyID, "user-id",
 		nil, nil, nil, nil,
-		time.Now(), time.Now(), true,
+		time.Now(), time.Now(), true, "clean", "hot",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM files WHERE full_path = \$1 AND company_id = \$2 AND type = 'folder' AND is_active = true`).
 		WithArgs(oldPath.String(), companyID).
 		WillReturnRows(rows)
 
-	mock.ExpectExec(`UPDATE files SET full_path = REPLACE\(full_path, \$1, \$2\), updated_at = \$5 WHERE full_path LIKE \$3 AND company_id = \$4 AND is_active = true`).
-		WithArgs(oldPath.String(), newPath.String(), "/old-folder%", companyID, sqlmock.AnyArg()).
+	mock.ExpectExec(`UPDATE files SET full_path = REPLACE\(full_path, \$1, \$2\), updated_at = \$5,\s+parent_id = CASE WHEN full_path = \$1 THEN \$6::UUID ELSE parent_id END WHERE full_path LIKE \$3 AND company_id = \$4 AND is_active = true`).
+		WithArgs(oldPath.String(), newPath.String(), "/old-folder%", companyID, sqlmock.AnyArg(), nil).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	result, err := repo.MoveFolder(context.Background(), companyID, &oldPath, &newPath)
@@ -404,6 +456,275 @@ func TestMoveFolder_Success(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestMoveFolder_IntoSubfolder(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	oldPath, _ := domain.NewPath("/old-folder")
+	newPath, _ := domain.NewPath("/parent/old-folder")
+	companyID := "company-id"
+	columns := []string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+	}
+
+	mock.ExpectQuery(`SELECT .+ FROM files WHERE full_path = \$1 AND company_id = \$2 AND type = 'folder' AND is_active = true`).
+		WithArgs(oldPath.String(), companyID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			"folder-id", "old-folder", domain.FileTypeFolder, "/old-folder", nil, companyID, "user-id",
+			nil, nil, nil, nil, time.Now(), time.Now(), true, "clean", "hot",
+		))
+
+	mock.ExpectQuery(`SELECT .+ FROM files WHERE full_path = \$1 AND company_id = \$2 AND type = 'folder' AND is_active = true`).
+		WithArgs("/parent", companyID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			"parent-id", "parent", domain.FileTypeFolder, "/parent", nil, companyID, "user-id",
+			nil, nil, nil, nil, time.Now(), time.Now(), true, "clean", "hot",
+		))
+
+	mock.ExpectExec(`UPDATE files SET full_path = REPLACE`).
+		WithArgs(oldPath.String(), newPath.String(), "/old-folder%", companyID, sqlmock.AnyArg(), "parent-id").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	result, err := repo.MoveFolder(context.Background(), companyID, &oldPath, &newPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, newPath.String(), result.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFolder_RootChildren(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	companyID := "company-id"
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+	}).AddRow(
+		"folder-id", "docs", domain.FileTypeFolder, "/docs", nil, companyID, "user-id",
+		nil, nil, nil, nil, time.Now(), time.Now(), true, "clean", "hot",
+	)
+
+	mock.ExpectQuery(`FROM files WHERE company_id = \$1 AND is_active = true AND parent_id IS NULL AND full_path NOT LIKE '/%/%' ORDER BY CASE WHEN type = 'folder' THEN 0 ELSE 1 END ASC, name ASC, id ASC LIMIT \$2`).
+		WithArgs(companyID, 101).
+		WillReturnRows(rows)
+
+	files, err := repo.ListFolder(context.Background(), companyID, nil, &domain.FolderListQuery{
+		Path:  domain.Path("/"),
+		Sort:  domain.FolderSortType,
+		Order: domain.SortOrderAsc,
+		Limit: 101,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "docs", files[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFolder_ParentWithCursor(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	companyID := "company-id"
+	parentID := "parent-id"
+	cursor := &domain.FolderCursor{ID: "file-id", Name: "b.txt", Size: 2048}
+
+	mock.ExpectQuery(`FROM files WHERE company_id = \$1 AND is_active = true AND parent_id = \$2 AND \(COALESCE\(size, 0\), id\) < \(\$3, \$4\) ORDER BY COALESCE\(size, 0\) DESC, id DESC LIMIT \$5`).
+		WithArgs(companyID, parentID, int64(2048), "file-id", 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	files, err := repo.ListFolder(context.Background(), companyID, &parentID, &domain.FolderListQuery{
+		Path:   domain.Path("/docs"),
+		Sort:   domain.FolderSortSize,
+		Order:  domain.SortOrderDesc,
+		Limit:  11,
+		Cursor: cursor,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFolder_RecursiveWithFilters(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	companyID := "company-id"
+	fileType := domain.FileTypeFile
+	minSize := int64(1024)
+
+	mock.ExpectQuery(`FROM files WHERE company_id = \$1 AND is_active = true AND full_path LIKE \$2 AND type = \$3 AND mime_type LIKE \$4 AND size >= \$5 ORDER BY name ASC, id ASC LIMIT \$6`).
+		WithArgs(companyID, "/docs/%", fileType, "image/%", minSize, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.ListFolder(context.Background(), companyID, nil, &domain.FolderListQuery{
+		Path:      domain.Path("/docs"),
+		Recursive: true,
+		FileFilter: domain.FileFilter{
+			Type:     &fileType,
+			MimeType: "image/*",
+			MinSize:  &minSize,
+		},
+		Sort:  domain.FolderSortName,
+		Order: domain.SortOrderAsc,
+		Limit: 50,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFolder_TagAndMetadataFilters(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	companyID := "company-id"
+
+	mock.ExpectQuery(`FROM files WHERE company_id = \$1 AND is_active = true AND parent_id IS NULL AND full_path NOT LIKE '/%/%' AND id IN \(SELECT file_id FROM file_metadata WHERE tags @> \$2::TEXT\[\]\) AND id IN \(SELECT file_id FROM file_metadata WHERE metadata @> \$3::JSONB\)`).
+		WithArgs(companyID, pq.Array([]string{"q3", "review"}), `{"project":"PRJ-42"}`, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.ListFolder(context.Background(), companyID, nil, &domain.FolderListQuery{
+		Path: domain.Path("/"),
+		FileFilter: domain.FileFilter{
+			Tags:     []string{"q3", "review"},
+			Metadata: map[string]string{"project": "PRJ-42"},
+		},
+		Sort:  domain.FolderSortName,
+		Order: domain.SortOrderAsc,
+		Limit: 10,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFolder_VisibleTo(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	companyID := "company-id"
+
+	mock.ExpectQuery(`AND EXISTS \(\s+SELECT 1 FROM files g\s+WHERE g.company_id = files.company_id AND g.is_active = true\s+AND \(g.user_created = \$2 OR g.id IN \(SELECT file_id FROM file_access WHERE user_id = \$2 OR user_id IS NULL\)\)`).
+		WithArgs(companyID, "user-id", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.ListFolder(context.Background(), companyID, nil, &domain.FolderListQuery{
+		Path:       domain.Path("/"),
+		FileFilter: domain.FileFilter{VisibleTo: "user-id"},
+		Sort:       domain.FolderSortName,
+		Order:      domain.SortOrderAsc,
+		Limit:      10,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFolder_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM files WHERE`).WillReturnError(sql.ErrConnDone)
+
+	_, err := repo.ListFolder(context.Background(), "company-id", nil, &domain.FolderListQuery{
+		Path:  domain.Path("/"),
+		Sort:  domain.FolderSortName,
+		Order: domain.SortOrderAsc,
+		Limit: 10,
+	})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchFiles_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	companyID := "company-id"
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class", "score",
+	}).AddRow(
+		"file-id", "report_2025.pdf", domain.FileTypeFile, "/my_docs/report_2025.pdf", "folder-id", companyID, "user-id",
+		"application/pdf", 1024, "hash", "storage/path", time.Now(), time.Now(), true, "clean", "hot", 2.45,
+	)
+
+	mock.ExpectQuery(`similarity\(name, \$2\) AS score FROM files WHERE company_id = \$1 AND is_active = true AND \(name ILIKE \$4 OR name % \$2\) AND full_path LIKE \$5 AND user_created = \$6 ORDER BY score DESC, name ASC, id ASC LIMIT \$7 OFFSET \$8`).
+		WithArgs(companyID, "report_", `report\_%`, `%report\_%`, `/my\_docs/%`, "user-id", 21, 20).
+		WillReturnRows(rows)
+
+	hits, err := repo.SearchFiles(context.Background(), companyID, &domain.SearchQuery{
+		Text:       "report_",
+		Path:       domain.Path("/my_docs"),
+		FileFilter: domain.FileFilter{UserCreated: "user-id"},
+		Limit:      21,
+		Offset:     20,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, "report_2025.pdf", hits[0].File.Name)
+	assert.Equal(t, 2.45, hits[0].Score)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchFiles_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM files WHERE`).WillReturnError(sql.ErrConnDone)
+
+	_, err := repo.SearchFiles(context.Background(), "company-id", &domain.SearchQuery{
+		Text:  "report",
+		Path:  domain.Path("/"),
+		Limit: 10,
+	})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchContent_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	companyID := "company-id"
+	fileType := domain.FileTypeFile
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class", "score", "snippet",
+	}).AddRow(
+		"file-id", "notes.md", domain.FileTypeFile, "/docs/notes.md", "folder-id", companyID, "user-id",
+		"text/markdown", 512, "hash", "storage/path", time.Now(), time.Now(), true, "clean", "hot", 0.8, "the <mark>budget</mark> for 2025",
+	)
+
+	mock.ExpectQuery(`websearch_to_tsquery\(\$2::REGCONFIG, \$3\).+WHERE c.search_vector @@ query.q AND company_id = \$1 AND is_active = true AND full_path LIKE \$5 AND type = \$6 ORDER BY score DESC, name ASC, id ASC LIMIT \$7 OFFSET \$8 \) AS hits`).
+		WithArgs(companyID, "english", "budget 2025", ContentSnippetOptions, "/docs/%", fileType, 11, 0).
+		WillReturnRows(rows)
+
+	hits, err := repo.SearchContent(context.Background(), companyID, "english", &domain.SearchQuery{
+		Text:       "budget 2025",
+		Path:       domain.Path("/docs"),
+		FileFilter: domain.FileFilter{Type: &fileType},
+		Limit:      11,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, 0.8, hits[0].Score)
+	assert.Equal(t, "the <mark>budget</mark> for 2025", hits[0].Snippet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteFolder_Success(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
@@ -421,6 +742,159 @@ func TestDeleteFolder_Success(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCountFolderTree_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	folderPath := domain.Path("/projects")
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM files WHERE company_id = \$1 AND is_active = true`).
+		WithArgs("company-id", "/projects", "/projects/%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repo.CountFolderTree(context.Background(), "company-id", &folderPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSumFolderTreeFiles_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	folderPath := domain.Path("/projects")
+
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(size\), 0\)::BIGINT, COUNT\(\*\) FROM files .+ AND type = 'file'`).
+		WithArgs("company-id", "/projects", "/projects/%").
+		WillReturnRows(sqlmock.NewRows([]string{"sum", "count"}).AddRow(int64(4096), int64(3)))
+
+	size, count, err := repo.SumFolderTreeFiles(context.Background(), "company-id", &folderPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4096), size)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteFolderTree_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	folderPath := domain.Path("/projects")
+	deletedAt := time.Now()
+
+	mock.ExpectExec(`UPDATE files SET is_active = false, updated_at = \$4`).
+		WithArgs("company-id", "/projects", "/projects/%", deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 17))
+
+	deleted, err := repo.DeleteFolderTree(context.Background(), "company-id", &folderPath, deletedAt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 17, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedFolderTreeFiles_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	folderPath := domain.Path("/projects")
+	deletedAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+	}).AddRow(
+		"file-id", "a.txt", "file", "/projects/a.txt", "folder-id", "company-id", "user-id",
+		"text/plain", 10, "hash", "companies/company-id/files/file-id/a.txt",
+		deletedAt, deletedAt, false, "clean", "hot",
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM files WHERE company_id = \$1 AND is_active = false AND updated_at = \$4`).
+		WithArgs("company-id", "/projects", "/projects/%", deletedAt, 100).
+		WillReturnRows(rows)
+
+	files, err := repo.GetDeletedFolderTreeFiles(context.Background(), "company-id", &folderPath, deletedAt, 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, domain.Path("/projects/a.txt"), files[0].FullPath)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFolderTree_EscapesWildcards(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	// Without escaping, /a_b would also cover /aXb and /a-b.
+	folderPath := domain.Path("/a_b%")
+	pattern := `/a\_b\%/%`
+	deletedAt := time.Now()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM files`).
+		WithArgs("company-id", "/a_b%", pattern).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(size\), 0\)::BIGINT, COUNT\(\*\) FROM files`).
+		WithArgs("company-id", "/a_b%", pattern).
+		WillReturnRows(sqlmock.NewRows([]string{"sum", "count"}).AddRow(int64(0), int64(0)))
+	mock.ExpectExec(`UPDATE files SET is_active = false, updated_at = \$4`).
+		WithArgs("company-id", "/a_b%", pattern, deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT (.+) FROM files WHERE company_id = \$1 AND is_active = false AND updated_at = \$4`).
+		WithArgs("company-id", "/a_b%", pattern, deletedAt, 100).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	_, err := repo.CountFolderTree(context.Background(), "company-id", &folderPath)
+	assert.NoError(t, err)
+	_, _, err = repo.SumFolderTreeFiles(context.Background(), "company-id", &folderPath)
+	assert.NoError(t, err)
+	_, err = repo.DeleteFolderTree(context.Background(), "company-id", &folderPath, deletedAt)
+	assert.NoError(t, err)
+	_, err = repo.GetDeletedFolderTreeFiles(context.Background(), "company-id", &folderPath, deletedAt, 100)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStorageRefs_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"source", "id", "storage_path", "size"}).
+		AddRow("file", "file-id", "companies/company-id/files/file-id/a.txt", 10).
+		AddRow("version", "version-id", "companies/company-id/files/file-id/a.txt", 10).
+		AddRow("presigned_upload", "upload-id", "companies/company-id/files/upload-id/b.txt", 20)
+
+	mock.ExpectQuery(`SELECT 'file', id, storage_path, COALESCE\(size, 0\) FROM files`).
+		WithArgs("company-id").
+		WillReturnRows(rows)
+
+	refs, err := repo.GetStorageRefs(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, refs, 3)
+	assert.Equal(t, domain.StorageRefFile, refs[0].Source)
+	assert.Equal(t, domain.StorageRefPresignedUpload, refs[2].Source)
+	assert.Equal(t, int64(20), refs[2].Size)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStorageRefs_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT 'file', id, storage_path`).
+		WithArgs("company-id").
+		WillReturnError(&mockError{message: "database error"})
+
+	refs, err := repo.GetStorageRefs(context.Background(), "company-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, refs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -436,3 +910,236 @@ type mockError struct {
 func (e *mockError) Error() string {
 	return e.message
 }
+
+func TestClaimScans_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	staleBefore := time.Now().Add(-15 * time.Minute)
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+	}).AddRow(
+		"file-id", "a.txt", "file", "/a.txt", nil, "company-id", "user-id",
+		"text/plain", 10, "hash", "companies/company-id/files/file-id/a.txt",
+		time.Now(), time.Now(), true, "pending", "hot",
+	)
+
+	mock.ExpectQuery(`UPDATE files SET scan_claimed_at = NOW\(\) WHERE id IN \(\s+SELECT id FROM files\s+WHERE scan_status = 'pending'`).
+		WithArgs(staleBefore, 5).
+		WillReturnRows(rows)
+
+	files, err := repo.ClaimScans(context.Background(), staleBefore, 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, domain.ScanStatusPending, files[0].ScanStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveScanResult_ContentReplaced(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	file := &domain.File{ID: "file-id", CompanyId: "company-id"}
+	result := &domain.ScanResult{
+		StoragePath: "companies/company-id/files/file-id/a.txt",
+		Status:      domain.ScanStatusClean,
+		ScannedAt:   time.Now(),
+	}
+
+	mock.ExpectExec(`UPDATE files SET scan_status = \$4, scan_detail = \$5, scanned_at = \$6, scan_claimed_at = NULL WHERE id = \$1 AND company_id = \$2 AND storage_path = \$3`).
+		WithArgs("file-id", "company-id", result.StoragePath, domain.ScanStatusClean, nil, result.ScannedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	saved, err := repo.SaveScanResult(context.Background(), file, result)
+
+	assert.NoError(t, err)
+	assert.False(t, saved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoveScannedObject_Quarantine(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	signature := "Eicar-Test-Signature"
+	file := &domain.File{ID: "file-id", CompanyId: "company-id"}
+	result := &domain.ScanResult{
+		StoragePath: "companies/company-id/files/file-id/a.txt",
+		Status:      domain.ScanStatusInfected,
+		Detail:      &signature,
+		ScannedAt:   time.Now(),
+	}
+	quarantined := "quarantine/companies/company-id/files/file-id/a.txt"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE files SET storage_path = \$4, scan_status = \$5`).
+		WithArgs("file-id", "company-id", result.StoragePath, quarantined, domain.ScanStatusInfected, &signature, result.ScannedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE file_versions SET storage_path = \$3 WHERE company_id = \$1 AND storage_path = \$2`).
+		WithArgs("company-id", result.StoragePath, quarantined).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	moved, err := repo.MoveScannedObject(context.Background(), file, quarantined, result)
+
+	assert.NoError(t, err)
+	assert.True(t, moved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetScanFindings_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	scannedAt := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+		"scan_detail", "scanned_at",
+	}).AddRow(
+		"file-id", "a.exe", "file", "/a.exe", nil, "company-id", "user-id",
+		"application/octet-stream", 68, "hash", "quarantine/companies/company-id/files/file-id/a.exe",
+		time.Now(), time.Now(), true, "infected", "hot",
+		"Eicar-Test-Signature", scannedAt,
+	)
+
+	mock.ExpectQuery(`FROM files WHERE company_id = \$1 AND scan_status = \$2 AND type = 'file' ORDER BY scanned_at DESC NULLS LAST`).
+		WithArgs("company-id", domain.ScanStatusInfected, 100).
+		WillReturnRows(rows)
+
+	findings, err := repo.GetScanFindings(context.Background(), "company-id", domain.ScanStatusInfected, 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "/a.exe", findings[0].File.FullPath.String())
+	assert.Equal(t, "Eicar-Test-Signature", *findings[0].Detail)
+	assert.NotNil(t, findings[0].ScannedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequeueScan_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE files SET scan_status = 'pending'`).
+		WithArgs("file-id", "company-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.RequeueScan(context.Background(), "company-id", "file-id")
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTouchFile_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	accessedAt := time.Now()
+	mock.ExpectExec(`UPDATE files SET last_accessed_at = \$3 WHERE id = \$1 AND company_id = \$2 AND type = 'file' AND \(last_accessed_at IS NULL OR last_accessed_at < \$3 - INTERVAL '1 hour'\)`).
+		WithArgs("file-id", "company-id", accessedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.TouchFile(context.Background(), "company-id", "file-id", accessedAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTransitionCandidates_Folder(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	folderPath := domain.Path("/archive")
+	accessedBefore := time.Now().AddDate(0, 0, -90)
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+	}).AddRow(
+		"file-id", "old.pdf", "file", "/archive/old.pdf", "folder-id", "company-id", "user-id",
+		"application/pdf", 10, "hash", "companies/company-id/files/file-id/old.pdf",
+		time.Now(), time.Now(), true, "clean", "hot",
+	)
+
+	mock.ExpectQuery(`FROM files WHERE company_id = \$1 AND type = 'file' AND is_active = true AND storage_class = 'hot' AND scan_status = 'clean'`).
+		WithArgs("company-id", "/archive/%", accessedBefore, 50).
+		WillReturnRows(rows)
+
+	files, err := repo.GetTransitionCandidates(context.Background(), "company-id", &folderPath, accessedBefore, 50)
+
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, domain.StorageClassHot, files[0].StorageClass)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTransitionCandidates_Company(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	accessedBefore := time.Now().AddDate(0, 0, -90)
+	mock.ExpectQuery(`FROM files WHERE company_id = \$1`).
+		WithArgs("company-id", nil, accessedBefore, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	files, err := repo.GetTransitionCandidates(context.Background(), "company-id", nil, accessedBefore, 50)
+
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetStorageClass_ContentReplaced(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	storagePath := "companies/company-id/files/file-id/a.txt"
+	file := &domain.File{ID: "file-id", CompanyId: "company-id", StoragePath: &storagePath}
+
+	mock.ExpectExec(`UPDATE files SET storage_class = \$4 WHERE id = \$1 AND company_id = \$2 AND storage_path = \$3`).
+		WithArgs("file-id", "company-id", storagePath, domain.StorageClassCold).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	updated, err := repo.SetStorageClass(context.Background(), file, domain.StorageClassCold)
+
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrashExpiredFiles_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	folderPath := domain.Path("/tmp")
+	modifiedBefore := time.Now().AddDate(0, 0, -7)
+	deletedAt := time.Now()
+
+	mock.ExpectExec(`UPDATE files SET is_active = false, updated_at = \$4 WHERE company_id = \$1 AND type = 'file' AND is_active = true`).
+		WithArgs("company-id", "/tmp/%", modifiedBefore, deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	trashed, err := repo.TrashExpiredFiles(context.Background(), "company-id", &folderPath, modifiedBefore, deletedAt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, trashed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrashExpiredFiles_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE files SET is_active = false`).
+		WillReturnError(sql.ErrConnDone)
+
+	_, err := repo.TrashExpiredFiles(context.Background(), "company-id", nil, time.Now(), time.Now())
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,48 @@
+package rpFolderCopyJobs
+
+const jobColumns = `
+id, company_id, user_created, source_id, source_path, target_path, target_id, status,
+total_items, copied_items, error, created_at, updated_at, finished_at
+`
+
+const QueryCreateJob = `
+INSERT INTO folder_copy_jobs (` + jobColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+`
+
+const QueryGetJob = `
+SELECT ` + jobColumns + `
+FROM folder_copy_jobs
+WHERE id = $1 AND company_id = $2
+`
+
+const QueryClaimJobs = `
+UPDATE folder_copy_jobs
+SET status = 'running', updated_at = NOW()
+WHERE id IN (
+    SELECT id FROM folder_copy_jobs
+    WHERE status = 'pending' OR (status = 'running' AND updated_at < $1)
+    ORDER BY created_at ASC
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + jobColumns
+
+const QueryUpdateJobProgress = `
+UPDATE folder_copy_jobs
+SET target_id = $2, total_items = $3, copied_items = $4, updated_at = $5
+WHERE id = $1
+RETURNING status
+`
+
+const QueryFinishJob = `
+UPDATE folder_copy_jobs
+SET status = $2, error = $3, updated_at = $4, finished_at = $5
+WHERE id = $1 AND status = 'running'
+`
+
+const QueryCancelJob = `
+UPDATE folder_copy_jobs
+SET status = 'cancelled', updated_at = $3, finished_at = $3
+WHERE id = $1 AND company_id = $2 AND status IN ('pending', 'running')
+`
@@ -0,0 +1,146 @@
+package rpFolderCopyJobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryFolderCopyJobs struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFolderCopyJobs {
+	return &RepositoryFolderCopyJobs{db: db}
+}
+
+func (r *RepositoryFolderCopyJobs) CreateJob(ctx context.Context, job *domain.FolderCopyJob) (*domain.FolderCopyJob, error) {
+	_, err := r.db.ExecContext(ctx, QueryCreateJob,
+		job.ID, job.CompanyID, job.UserCreateID, job.SourceID, job.SourcePath.String(), job.TargetPath.String(), job.TargetID, job.Status,
+		job.TotalItems, job.CopiedItems, job.Error, job.CreatedAt, job.UpdatedAt, job.FinishedAt,
+	)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to create folder copy job")
+	}
+
+	return job, nil
+}
+
+func (r *RepositoryFolderCopyJobs) GetJob(ctx context.Context, companyID, jobID string) (*domain.FolderCopyJob, error) {
+	job, err := scanJob(r.db.QueryRowContext(ctx, QueryGetJob, jobID, companyID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("folder copy job not found")
+		}
+		return nil, pkgErrors.Database("unable to get folder copy job")
+	}
+
+	return job, nil
+}
+
+// ClaimJobs marks up to limit pending jobs as running and returns them. Running
+// jobs without progress since staleBefore are claimed again, their worker is gone.
+func (r *RepositoryFolderCopyJobs) ClaimJobs(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.FolderCopyJob, error) {
+	rows, err := r.db.QueryContext(ctx, QueryClaimJobs, staleBefore, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to claim folder copy jobs")
+	}
+	defer rows.Close()
+
+	var jobs []*domain.FolderCopyJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan folder copy job")
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to claim folder copy jobs")
+	}
+
+	return jobs, nil
+}
+
+// UpdateJobProgress stores the counters of the job and returns its current
+// status, which tells the worker whether the job was cancelled meanwhile.
+func (r *RepositoryFolderCopyJobs) UpdateJobProgress(ctx context.Context, job *domain.FolderCopyJob) (domain.JobStatus, error) {
+	var status domain.JobStatus
+
+	err := r.db.QueryRowContext(ctx, QueryUpdateJobProgress,
+		job.ID, job.TargetID, job.TotalItems, job.CopiedItems, job.UpdatedAt,
+	).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", pkgErrors.NotFound("folder copy job not found")
+		}
+		return "", pkgErrors.Database("unable to update folder copy job")
+	}
+
+	return status, nil
+}
+
+// FinishJob records the final status of a running job. A job cancelled in the
+// meantime keeps its cancelled status.
+func (r *RepositoryFolderCopyJobs) FinishJob(ctx context.Context, job *domain.FolderCopyJob) error {
+	_, err := r.db.ExecContext(ctx, QueryFinishJob, job.ID, job.Status, job.Error, job.UpdatedAt, job.FinishedAt)
+	if err != nil {
+		return pkgErrors.Database("unable to finish folder copy job")
+	}
+
+	return nil
+}
+
+func (r *RepositoryFolderCopyJobs) CancelJob(ctx context.Context, companyID, jobID string) error {
+	res, err := r.db.ExecContext(ctx, QueryCancelJob, jobID, companyID, time.Now())
+	if err != nil {
+		return pkgErrors.Database("unable to cancel folder copy job")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.Conflict("folder copy job is already finished")
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row scanner) (*domain.FolderCopyJob, error) {
+	var job domain.FolderCopyJob
+	var sourcePathStr, targetPathStr string
+	var finishedAt sql.NullTime
+
+	err := row.Scan(
+		&job.ID, &job.CompanyID, &job.UserCreateID, &job.SourceID, &sourcePathStr, &targetPathStr, &job.TargetID, &job.Status,
+		&job.TotalItems, &job.CopiedItems, &job.Error, &job.CreatedAt, &job.UpdatedAt, &finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sourcePath, err := domain.NewPath(sourcePathStr)
+	if err != nil {
+		return nil, err
+	}
+	job.SourcePath = sourcePath
+
+	targetPath, err := domain.NewPath(targetPathStr)
+	if err != nil {
+		return nil, err
+	}
+	job.TargetPath = targetPath
+
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,191 @@
+package rpFolderCopyJobs
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFolderCopyJobs) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func jobColumnNames() []string {
+	return []string{
+		"id", "company_id", "user_created", "source_id", "source_path", "target_path", "target_id", "status",
+		"total_items", "copied_items", "error", "created_at", "updated_at", "finished_at",
+	}
+}
+
+func TestCreateJob_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	job := &domain.FolderCopyJob{
+		ID:           "job-id",
+		CompanyID:    "company-id",
+		UserCreateID: "user-id",
+		SourceID:     "folder-id",
+		SourcePath:   domain.Path("/projects"),
+		TargetPath:   domain.Path("/archive/projects"),
+		Status:       domain.JobStatusPending,
+		TotalItems:   500,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	mock.ExpectExec(`INSERT INTO folder_copy_jobs`).
+		WithArgs(
+			job.ID, job.CompanyID, job.UserCreateID, job.SourceID, "/projects", "/archive/projects", job.TargetID, job.Status,
+			500, 0, job.Error, now, now, job.FinishedAt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	result, err := repo.CreateJob(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.Equal(t, job, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJob_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(jobColumnNames()).AddRow(
+		"job-id", "company-id", "user-id", "folder-id", "/projects", "/archive/projects", "copy-id", "running",
+		500, 120, nil, now, now, nil,
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM folder_copy_jobs WHERE id = \$1 AND company_id = \$2`).
+		WithArgs("job-id", "company-id").
+		WillReturnRows(rows)
+
+	job, err := repo.GetJob(context.Background(), "company-id", "job-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobStatusRunning, job.Status)
+	assert.Equal(t, domain.Path("/archive/projects"), job.TargetPath)
+	assert.Equal(t, "copy-id", *job.TargetID)
+	assert.Equal(t, 120, job.CopiedItems)
+	assert.Nil(t, job.FinishedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM folder_copy_jobs`).
+		WithArgs("job-id", "company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	job, err := repo.GetJob(context.Background(), "company-id", "job-id")
+
+	assert.Nil(t, job)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimJobs_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	staleBefore := now.Add(-10 * time.Minute)
+	rows := sqlmock.NewRows(jobColumnNames()).AddRow(
+		"job-id", "company-id", "user-id", "folder-id", "/projects", "/archive/projects", nil, "running",
+		500, 0, nil, now, now, nil,
+	)
+
+	mock.ExpectQuery(`UPDATE folder_copy_jobs SET status = 'running'`).
+		WithArgs(staleBefore, 5).
+		WillReturnRows(rows)
+
+	jobs, err := repo.ClaimJobs(context.Background(), staleBefore, 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Nil(t, jobs[0].TargetID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateJobProgress_ReturnsCurrentStatus(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	targetID := "copy-id"
+	job := &domain.FolderCopyJob{ID: "job-id", TargetID: &targetID, TotalItems: 10, CopiedItems: 4, UpdatedAt: now}
+
+	mock.ExpectQuery(`UPDATE folder_copy_jobs SET target_id = \$2`).
+		WithArgs("job-id", job.TargetID, 10, 4, now).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("cancelled"))
+
+	status, err := repo.UpdateJobProgress(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFinishJob_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	job := &domain.FolderCopyJob{ID: "job-id"}
+	job.Finish(domain.JobStatusCompleted, nil)
+
+	mock.ExpectExec(`UPDATE folder_copy_jobs SET status = \$2, error = \$3`).
+		WithArgs("job-id", domain.JobStatusCompleted, job.Error, job.UpdatedAt, job.FinishedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.FinishJob(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelJob(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, repo := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE folder_copy_jobs SET status = 'cancelled'`).
+			WithArgs("job-id", "company-id", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.CancelJob(context.Background(), "company-id", "job-id")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already finished", func(t *testing.T) {
+		db, mock, repo := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE folder_copy_jobs SET status = 'cancelled'`).
+			WithArgs("job-id", "company-id", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.CancelJob(context.Background(), "company-id", "job-id")
+
+		assert.ErrorIs(t, err, pkgErrors.ErrConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,49 @@
+package rpFolderDeleteJobs
+
+const jobColumns = `
+id, company_id, user_created, folder_id, folder_path, permanent, status,
+total_items, deleted_items, purged_items, error, deleted_at,
+created_at, updated_at, finished_at
+`
+
+const QueryCreateJob = `
+INSERT INTO folder_delete_jobs (` + jobColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+`
+
+const QueryGetJob = `
+SELECT ` + jobColumns + `
+FROM folder_delete_jobs
+WHERE id = $1 AND company_id = $2
+`
+
+const QueryClaimJobs = `
+UPDATE folder_delete_jobs
+SET status = 'running', updated_at = NOW()
+WHERE id IN (
+    SELECT id FROM folder_delete_jobs
+    WHERE status = 'pending' OR (status = 'running' AND updated_at < $1)
+    ORDER BY created_at ASC
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + jobColumns
+
+const QueryUpdateJobProgress = `
+UPDATE folder_delete_jobs
+SET total_items = $2, deleted_items = $3, purged_items = $4, deleted_at = $5, updated_at = $6
+WHERE id = $1
+RETURNING status
+`
+
+const QueryFinishJob = `
+UPDATE folder_delete_jobs
+SET status = $2, error = $3, updated_at = $4, finished_at = $5
+WHERE id = $1 AND status = 'running'
+`
+
+const QueryCancelJob = `
+UPDATE folder_delete_jobs
+SET status = 'cancelled', updated_at = $3, finished_at = $3
+WHERE id = $1 AND company_id = $2 AND status IN ('pending', 'running')
+`
@@ -0,0 +1,145 @@
+package rpFolderDeleteJobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryFolderDeleteJobs struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryFolderDeleteJobs {
+	return &RepositoryFolderDeleteJobs{db: db}
+}
+
+func (r *RepositoryFolderDeleteJobs) CreateJob(ctx context.Context, job *domain.FolderDeleteJob) (*domain.FolderDeleteJob, error) {
+	_, err := r.db.ExecContext(ctx, QueryCreateJob,
+		job.ID, job.CompanyID, job.UserCreateID, job.FolderID, job.FolderPath.String(), job.Permanent, job.Status,
+		job.TotalItems, job.DeletedItems, job.PurgedItems, job.Error, job.DeletedAt,
+		job.CreatedAt, job.UpdatedAt, job.FinishedAt,
+	)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to create folder delete job")
+	}
+
+	return job, nil
+}
+
+func (r *RepositoryFolderDeleteJobs) GetJob(ctx context.Context, companyID, jobID string) (*domain.FolderDeleteJob, error) {
+	job, err := scanJob(r.db.QueryRowContext(ctx, QueryGetJob, jobID, companyID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("folder delete job not found")
+		}
+		return nil, pkgErrors.Database("unable to get folder delete job")
+	}
+
+	return job, nil
+}
+
+// ClaimJobs marks up to limit pending jobs as running and returns them. Running
+// jobs without progress since staleBefore are claimed again, their worker is gone.
+func (r *RepositoryFolderDeleteJobs) ClaimJobs(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.FolderDeleteJob, error) {
+	rows, err := r.db.QueryContext(ctx, QueryClaimJobs, staleBefore, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to claim folder delete jobs")
+	}
+	defer rows.Close()
+
+	var jobs []*domain.FolderDeleteJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan folder delete job")
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to claim folder delete jobs")
+	}
+
+	return jobs, nil
+}
+
+// UpdateJobProgress stores the counters of the job and returns its current
+// status, which tells the worker whether the job was cancelled meanwhile.
+func (r *RepositoryFolderDeleteJobs) UpdateJobProgress(ctx context.Context, job *domain.FolderDeleteJob) (domain.JobStatus, error) {
+	var status domain.JobStatus
+
+	err := r.db.QueryRowContext(ctx, QueryUpdateJobProgress,
+		job.ID, job.TotalItems, job.DeletedItems, job.PurgedItems, job.DeletedAt, job.UpdatedAt,
+	).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", pkgErrors.NotFound("folder delete job not found")
+		}
+		return "", pkgErrors.Database("unable to update folder delete job")
+	}
+
+	return status, nil
+}
+
+// FinishJob records the final status of a running job. A job cancelled in the
+// meantime keeps its cancelled status.
+func (r *RepositoryFolderDeleteJobs) FinishJob(ctx context.Context, job *domain.FolderDeleteJob) error {
+	_, err := r.db.ExecContext(ctx, QueryFinishJob, job.ID, job.Status, job.Error, job.UpdatedAt, job.FinishedAt)
+	if err != nil {
+		return pkgErrors.Database("unable to finish folder delete job")
+	}
+
+	return nil
+}
+
+func (r *RepositoryFolderDeleteJobs) CancelJob(ctx context.Context, companyID, jobID string) error {
+	res, err := r.db.ExecContext(ctx, QueryCancelJob, jobID, companyID, time.Now())
+	if err != nil {
+		return pkgErrors.Database("unable to cancel folder delete job")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.Conflict("folder delete job is already finished")
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row scanner) (*domain.FolderDeleteJob, error) {
+	var job domain.FolderDeleteJob
+	var folderPathStr string
+	var deletedAt, finishedAt sql.NullTime
+
+	err := row.Scan(
+		&job.ID, &job.CompanyID, &job.UserCreateID, &job.FolderID, &folderPathStr, &job.Permanent, &job.Status,
+		&job.TotalItems, &job.DeletedItems, &job.PurgedItems, &job.Error, &deletedAt,
+		&job.CreatedAt, &job.UpdatedAt, &finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	folderPath, err := domain.NewPath(folderPathStr)
+	if err != nil {
+		return nil, err
+	}
+	job.FolderPath = folderPath
+
+	if deletedAt.Valid {
+		job.DeletedAt = &deletedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return &job, nil
+}
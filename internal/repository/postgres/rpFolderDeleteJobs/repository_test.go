@@ -0,0 +1,193 @@
+package rpFolderDeleteJobs
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryFolderDeleteJobs) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func jobColumnNames() []string {
+	return []string{
+		"id", "company_id", "user_created", "folder_id", "folder_path", "permanent", "status",
+		"total_items", "deleted_items", "purged_items", "error", "deleted_at",
+		"created_at", "updated_at", "finished_at",
+	}
+}
+
+func TestCreateJob_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	job := &domain.FolderDeleteJob{
+		ID:           "job-id",
+		CompanyID:    "company-id",
+		UserCreateID: "user-id",
+		FolderID:     "folder-id",
+		FolderPath:   domain.Path("/projects"),
+		Status:       domain.JobStatusPending,
+		TotalItems:   1500,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	mock.ExpectExec(`INSERT INTO folder_delete_jobs`).
+		WithArgs(
+			job.ID, job.CompanyID, job.UserCreateID, job.FolderID, "/projects", false, job.Status,
+			1500, 0, 0, job.Error, job.DeletedAt,
+			now, now, job.FinishedAt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	result, err := repo.CreateJob(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.Equal(t, job, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJob_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(jobColumnNames()).AddRow(
+		"job-id", "company-id", "user-id", "folder-id", "/projects", true, "running",
+		1500, 1500, 300, nil, now,
+		now, now, nil,
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM folder_delete_jobs WHERE id = \$1 AND company_id = \$2`).
+		WithArgs("job-id", "company-id").
+		WillReturnRows(rows)
+
+	job, err := repo.GetJob(context.Background(), "company-id", "job-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobStatusRunning, job.Status)
+	assert.Equal(t, domain.Path("/projects"), job.FolderPath)
+	assert.Equal(t, 300, job.PurgedItems)
+	assert.NotNil(t, job.DeletedAt)
+	assert.Nil(t, job.FinishedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM folder_delete_jobs`).
+		WithArgs("job-id", "company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	job, err := repo.GetJob(context.Background(), "company-id", "job-id")
+
+	assert.Nil(t, job)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimJobs_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	staleBefore := now.Add(-10 * time.Minute)
+	rows := sqlmock.NewRows(jobColumnNames()).AddRow(
+		"job-id", "company-id", "user-id", "folder-id", "/projects", false, "running",
+		1500, 0, 0, nil, nil,
+		now, now, nil,
+	)
+
+	mock.ExpectQuery(`UPDATE folder_delete_jobs SET status = 'running'`).
+		WithArgs(staleBefore, 5).
+		WillReturnRows(rows)
+
+	jobs, err := repo.ClaimJobs(context.Background(), staleBefore, 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Nil(t, jobs[0].DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateJobProgress_ReturnsCurrentStatus(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	job := &domain.FolderDeleteJob{ID: "job-id", TotalItems: 10, DeletedItems: 10, PurgedItems: 4, DeletedAt: &now, UpdatedAt: now}
+
+	mock.ExpectQuery(`UPDATE folder_delete_jobs SET total_items = \$2`).
+		WithArgs("job-id", 10, 10, 4, job.DeletedAt, now).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("cancelled"))
+
+	status, err := repo.UpdateJobProgress(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFinishJob_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	job := &domain.FolderDeleteJob{ID: "job-id"}
+	job.Finish(domain.JobStatusCompleted, nil)
+
+	mock.ExpectExec(`UPDATE folder_delete_jobs SET status = \$2, error = \$3`).
+		WithArgs("job-id", domain.JobStatusCompleted, job.Error, job.UpdatedAt, job.FinishedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.FinishJob(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelJob(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, repo := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE folder_delete_jobs SET status = 'cancelled'`).
+			WithArgs("job-id", "company-id", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.CancelJob(context.Background(), "company-id", "job-id")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already finished", func(t *testing.T) {
+		db, mock, repo := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE folder_delete_jobs SET status = 'cancelled'`).
+			WithArgs("job-id", "company-id", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.CancelJob(context.Background(), "company-id", "job-id")
+
+		assert.ErrorIs(t, err, pkgErrors.ErrConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,55 @@
+package rpLifecycleRules
+
+const QueryListRules = `
+SELECT r.id, r.company_id, r.folder_id, f.full_path, r.action, r.days, r.created_by, r.created_at, r.updated_at
+FROM lifecycle_rules r
+LEFT JOIN files f ON f.id = r.folder_id
+WHERE r.company_id = $1
+ORDER BY f.full_path ASC NULLS FIRST, r.action ASC
+`
+
+const QueryGetRule = `
+SELECT r.id, r.company_id, r.folder_id, f.full_path, r.action, r.days, r.created_by, r.created_at, r.updated_at
+FROM lifecycle_rules r
+LEFT JOIN files f ON f.id = r.folder_id
+WHERE r.id = $1 AND r.company_id = $2
+`
+
+// QueryGetActiveRules returns the rules of every company, leaving out those of
+// folders in the trash.
+const QueryGetActiveRules = `
+SELECT r.id, r.company_id, r.folder_id, f.full_path, r.action, r.days, r.created_by, r.created_at, r.updated_at
+FROM lifecycle_rules r
+LEFT JOIN files f ON f.id = r.folder_id
+WHERE r.folder_id IS NULL OR f.is_active = true
+ORDER BY r.company_id ASC, r.action ASC, r.id ASC
+`
+
+// QueryCreateFolderRule creates nothing when the folder already has a rule
+// for the action.
+const QueryCreateFolderRule = `
+INSERT INTO lifecycle_rules (id, company_id, folder_id, action, days, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (folder_id, action) WHERE folder_id IS NOT NULL DO NOTHING
+RETURNING id
+`
+
+// QueryCreateCompanyRule creates nothing when the company already has a rule
+// for the action.
+const QueryCreateCompanyRule = `
+INSERT INTO lifecycle_rules (id, company_id, folder_id, action, days, created_by, created_at, updated_at)
+VALUES ($1, $2, NULL, $3, $4, $5, $6, $7)
+ON CONFLICT (company_id, action) WHERE folder_id IS NULL DO NOTHING
+RETURNING id
+`
+
+const QueryUpdateRule = `
+UPDATE lifecycle_rules
+SET days = $3, updated_at = $4
+WHERE id = $1 AND company_id = $2
+`
+
+const QueryDeleteRule = `
+DELETE FROM lifecycle_rules
+WHERE id = $1 AND company_id = $2
+`
@@ -0,0 +1,127 @@
+package rpLifecycleRules
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryLifecycleRules struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryLifecycleRules {
+	return &RepositoryLifecycleRules{db: db}
+}
+
+func (r *RepositoryLifecycleRules) ListRules(ctx context.Context, companyID string) ([]*domain.LifecycleRule, error) {
+	return r.queryRules(ctx, QueryListRules, companyID)
+}
+
+// GetActiveRules returns the rules of every company the lifecycle worker
+// applies, which leaves out the rules of folders in the trash.
+func (r *RepositoryLifecycleRules) GetActiveRules(ctx context.Context) ([]*domain.LifecycleRule, error) {
+	return r.queryRules(ctx, QueryGetActiveRules)
+}
+
+func (r *RepositoryLifecycleRules) GetRule(ctx context.Context, companyID, ruleID string) (*domain.LifecycleRule, error) {
+	rule, err := scanRule(r.db.QueryRowContext(ctx, QueryGetRule, ruleID, companyID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("lifecycle rule not found")
+		}
+		return nil, pkgErrors.Database("unable to get lifecycle rule")
+	}
+
+	return rule, nil
+}
+
+// CreateRule stores the rule unless its folder, or the company for a rule
+// without folder, already has one for the action.
+func (r *RepositoryLifecycleRules) CreateRule(ctx context.Context, rule *domain.LifecycleRule) (*domain.LifecycleRule, error) {
+	var row *sql.Row
+	if rule.FolderID == nil {
+		row = r.db.QueryRowContext(ctx, QueryCreateCompanyRule,
+			rule.ID, rule.CompanyID, rule.Action, rule.Days, rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt)
+	} else {
+		row = r.db.QueryRowContext(ctx, QueryCreateFolderRule,
+			rule.ID, rule.CompanyID, *rule.FolderID, rule.Action, rule.Days, rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt)
+	}
+
+	if err := row.Scan(&rule.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.Conflict("a lifecycle rule with this action already exists here")
+		}
+		return nil, pkgErrors.Database("unable to create lifecycle rule")
+	}
+
+	return rule, nil
+}
+
+func (r *RepositoryLifecycleRules) UpdateRule(ctx context.Context, rule *domain.LifecycleRule) error {
+	res, err := r.db.ExecContext(ctx, QueryUpdateRule, rule.ID, rule.CompanyID, rule.Days, rule.UpdatedAt)
+	if err != nil {
+		return pkgErrors.Database("unable to update lifecycle rule")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("lifecycle rule not found")
+	}
+
+	return nil
+}
+
+func (r *RepositoryLifecycleRules) DeleteRule(ctx context.Context, companyID, ruleID string) error {
+	res, err := r.db.ExecContext(ctx, QueryDeleteRule, ruleID, companyID)
+	if err != nil {
+		return pkgErrors.Database("unable to delete lifecycle rule")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("lifecycle rule not found")
+	}
+
+	return nil
+}
+
+func (r *RepositoryLifecycleRules) queryRules(ctx context.Context, query string, args ...any) ([]*domain.LifecycleRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to list lifecycle rules")
+	}
+	defer rows.Close()
+
+	rules := []*domain.LifecycleRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan lifecycle rule")
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to list lifecycle rules")
+	}
+
+	return rules, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRule(row scanner) (*domain.LifecycleRule, error) {
+	var rule domain.LifecycleRule
+	err := row.Scan(
+		&rule.ID, &rule.CompanyID, &rule.FolderID, &rule.FolderPath, &rule.Action, &rule.Days,
+		&rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
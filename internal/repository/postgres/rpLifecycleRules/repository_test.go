@@ -0,0 +1,152 @@
+package rpLifecycleRules
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+var ruleColumns = []string{
+	"id", "company_id", "folder_id", "full_path", "action", "days", "created_by", "created_at", "updated_at",
+}
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryLifecycleRules) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func TestListRules_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(ruleColumns).
+		AddRow("rule-1", "company-id", nil, nil, "transition", 90, "user-id", now, now).
+		AddRow("rule-2", "company-id", "folder-id", "/tmp", "delete", 7, "user-id", now, now)
+
+	mock.ExpectQuery(`FROM lifecycle_rules r LEFT JOIN files f ON f.id = r.folder_id WHERE r.company_id = \$1`).
+		WithArgs("company-id").
+		WillReturnRows(rows)
+
+	rules, err := repo.ListRules(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Nil(t, rules[0].FolderID)
+	assert.Nil(t, rules[0].FolderPath)
+	assert.Equal(t, domain.LifecycleActionTransition, rules[0].Action)
+	assert.Equal(t, "folder-id", *rules[1].FolderID)
+	assert.Equal(t, domain.Path("/tmp"), *rules[1].FolderPath)
+	assert.Equal(t, 7, rules[1].Days)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetActiveRules_SkipsTrashedFolders(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`WHERE r.folder_id IS NULL OR f.is_active = true`).
+		WillReturnRows(sqlmock.NewRows(ruleColumns))
+
+	rules, err := repo.GetActiveRules(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRule_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`WHERE r.id = \$1 AND r.company_id = \$2`).
+		WithArgs("rule-id", "company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetRule(context.Background(), "company-id", "rule-id")
+
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRule_Folder(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	folderID := "folder-id"
+	now := time.Now()
+	rule := &domain.LifecycleRule{
+		ID: "rule-id", CompanyID: "company-id", FolderID: &folderID,
+		Action: domain.LifecycleActionDelete, Days: 7, CreatedBy: "user-id", CreatedAt: now, UpdatedAt: now,
+	}
+
+	mock.ExpectQuery(`INSERT INTO lifecycle_rules .+ ON CONFLICT \(folder_id, action\) WHERE folder_id IS NOT NULL DO NOTHING`).
+		WithArgs("rule-id", "company-id", folderID, domain.LifecycleActionDelete, 7, "user-id", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rule-id"))
+
+	created, err := repo.CreateRule(context.Background(), rule)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "rule-id", created.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRule_CompanyConflict(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	rule := &domain.LifecycleRule{
+		ID: "rule-id", CompanyID: "company-id",
+		Action: domain.LifecycleActionTransition, Days: 90, CreatedBy: "user-id", CreatedAt: now, UpdatedAt: now,
+	}
+
+	mock.ExpectQuery(`ON CONFLICT \(company_id, action\) WHERE folder_id IS NULL DO NOTHING`).
+		WithArgs("rule-id", "company-id", domain.LifecycleActionTransition, 90, "user-id", now, now).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.CreateRule(context.Background(), rule)
+
+	assert.ErrorIs(t, err, pkgErrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRule_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`UPDATE lifecycle_rules SET days = \$3, updated_at = \$4 WHERE id = \$1 AND company_id = \$2`).
+		WithArgs("rule-id", "company-id", 30, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.UpdateRule(context.Background(), &domain.LifecycleRule{ID: "rule-id", CompanyID: "company-id", Days: 30, UpdatedAt: now})
+
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRule_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM lifecycle_rules WHERE id = \$1 AND company_id = \$2`).
+		WithArgs("rule-id", "company-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.DeleteRule(context.Background(), "company-id", "rule-id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,20 @@
+package rpPresignedUploads
+
+const QueryCreatePresignedUpload = `
+INSERT INTO presigned_uploads (
+    id, file_name, company_id, user_created, target_path,
+    storage_key, mime_type, size, checksum, created_at, expires_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+`
+
+const QueryGetPresignedUpload = `
+SELECT id, file_name, company_id, user_created, target_path,
+       storage_key, mime_type, size, checksum, created_at, expires_at
+FROM presigned_uploads
+WHERE id = $1 AND company_id = $2
+`
+
+const QueryDeletePresignedUpload = `
+DELETE FROM presigned_uploads
+WHERE id = $1 AND company_id = $2
+`
@@ -0,0 +1,65 @@
+package rpPresignedUploads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryPresignedUploads struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryPresignedUploads {
+	return &RepositoryPresignedUploads{db: db}
+}
+
+func (r *RepositoryPresignedUploads) CreatePresignedUpload(ctx context.Context, upload *domain.PresignedUpload) (*domain.PresignedUpload, error) {
+	_, err := r.db.ExecContext(ctx, QueryCreatePresignedUpload,
+		upload.ID, upload.FileName, upload.CompanyID, upload.UserCreateID, upload.TargetPath.String(),
+		upload.StorageKey, upload.MimeType, upload.Size, upload.Checksum, upload.CreatedAt, upload.ExpiresAt,
+	)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to create presigned upload session")
+	}
+
+	return upload, nil
+}
+
+func (r *RepositoryPresignedUploads) GetPresignedUpload(ctx context.Context, companyID, uploadID string) (*domain.PresignedUpload, error) {
+	var upload domain.PresignedUpload
+	var targetPathStr string
+
+	row := r.db.QueryRowContext(ctx, QueryGetPresignedUpload, uploadID, companyID)
+
+	err := row.Scan(
+		&upload.ID, &upload.FileName, &upload.CompanyID, &upload.UserCreateID, &targetPathStr,
+		&upload.StorageKey, &upload.MimeType, &upload.Size, &upload.Checksum, &upload.CreatedAt, &upload.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("presigned upload session not found")
+		}
+		return nil, pkgErrors.Database("unable to get presigned upload session")
+	}
+
+	targetPath, err := domain.NewPath(targetPathStr)
+	if err != nil {
+		return nil, pkgErrors.Database("invalid target path")
+	}
+	upload.TargetPath = targetPath
+
+	return &upload, nil
+}
+
+func (r *RepositoryPresignedUploads) DeletePresignedUpload(ctx context.Context, companyID, uploadID string) error {
+	_, err := r.db.ExecContext(ctx, QueryDeletePresignedUpload, uploadID, companyID)
+	if err != nil {
+		return pkgErrors.Database("unable to delete presigned upload session")
+	}
+
+	return nil
+}
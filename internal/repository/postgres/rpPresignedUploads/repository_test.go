@@ -0,0 +1,145 @@
+package rpPresignedUploads
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryPresignedUploads) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func uploadColumns() []string {
+	return []string{
+		"id", "file_name", "company_id", "user_created", "target_path",
+		"storage_key", "mime_type", "size", "checksum", "created_at", "expires_at",
+	}
+}
+
+func TestCreatePresignedUpload_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	upload := &domain.PresignedUpload{
+		ID:           "upload-id",
+		FileName:     "test.txt",
+		CompanyID:    "company-id",
+		UserCreateID: "user-id",
+		TargetPath:   domain.Path("/docs/test.txt"),
+		StorageKey:   "companies/company-id/files/file-id/test.txt",
+		MimeType:     "text/plain",
+		Size:         1024,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(15 * time.Minute),
+	}
+
+	mock.ExpectExec(`INSERT INTO presigned_uploads`).
+		WithArgs(
+			upload.ID, upload.FileName, upload.CompanyID, upload.UserCreateID, "/docs/test.txt",
+			upload.StorageKey, upload.MimeType, upload.Size, upload.Checksum, upload.CreatedAt, upload.ExpiresAt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	result, err := repo.CreatePresignedUpload(context.Background(), upload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, upload, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePresignedUpload_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO presigned_uploads`).
+		WillReturnError(sql.ErrConnDone)
+
+	result, err := repo.CreatePresignedUpload(context.Background(), &domain.PresignedUpload{ID: "upload-id"})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "unable to create presigned upload session")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPresignedUpload_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	checksum := "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+	rows := sqlmock.NewRows(uploadColumns()).
+		AddRow("upload-id", "test.txt", "company-id", "user-id", "/docs/test.txt",
+			"companies/company-id/files/file-id/test.txt", "text/plain", 1024, checksum, now, now.Add(time.Minute))
+
+	mock.ExpectQuery(`SELECT (.+) FROM presigned_uploads`).
+		WithArgs("upload-id", "company-id").
+		WillReturnRows(rows)
+
+	result, err := repo.GetPresignedUpload(context.Background(), "company-id", "upload-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "upload-id", result.ID)
+	assert.Equal(t, domain.Path("/docs/test.txt"), result.TargetPath)
+	assert.Equal(t, int64(1024), result.Size)
+	assert.Equal(t, checksum, *result.Checksum)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPresignedUpload_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM presigned_uploads`).
+		WithArgs("missing", "company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	result, err := repo.GetPresignedUpload(context.Background(), "company-id", "missing")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "presigned upload session not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePresignedUpload_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM presigned_uploads`).
+		WithArgs("upload-id", "company-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.DeletePresignedUpload(context.Background(), "company-id", "upload-id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePresignedUpload_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM presigned_uploads`).
+		WithArgs("upload-id", "company-id").
+		WillReturnError(sql.ErrConnDone)
+
+	err := repo.DeletePresignedUpload(context.Background(), "company-id", "upload-id")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to delete presigned upload session")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
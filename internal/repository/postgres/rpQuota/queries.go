@@ -0,0 +1,50 @@
+package rpQuota
+
+// Upload sessions that have not expired yet reserve their whole size and one
+// file until they complete or are abandoned.
+
+const QueryGetCompanyUsage = `
+WITH reserved AS (
+    SELECT total_size AS size FROM chunked_uploads
+    WHERE company_id = $1 AND status = 'active' AND expires_at > NOW()
+    UNION ALL
+    SELECT size FROM presigned_uploads
+    WHERE company_id = $1 AND expires_at > NOW()
+)
+SELECT c.quota_bytes, c.quota_files,
+       (SELECT COALESCE(SUM(used_bytes), 0)::BIGINT FROM storage_usage WHERE company_id = c.id),
+       (SELECT COALESCE(SUM(used_files), 0)::BIGINT FROM storage_usage WHERE company_id = c.id),
+       (SELECT COALESCE(SUM(size), 0)::BIGINT FROM reserved),
+       (SELECT COUNT(*) FROM reserved)
+FROM companies c
+WHERE c.id = $1 AND c.is_active = true
+`
+
+const QueryGetUserUsage = `
+WITH reserved AS (
+    SELECT total_size AS size FROM chunked_uploads
+    WHERE company_id = $1 AND user_created = $2 AND status = 'active' AND expires_at > NOW()
+    UNION ALL
+    SELECT size FROM presigned_uploads
+    WHERE company_id = $1 AND user_created = $2 AND expires_at > NOW()
+)
+SELECT u.quota_bytes, u.quota_files,
+       COALESCE(s.used_bytes, 0), COALESCE(s.used_files, 0),
+       (SELECT COALESCE(SUM(size), 0)::BIGINT FROM reserved),
+       (SELECT COUNT(*) FROM reserved)
+FROM users u
+LEFT JOIN storage_usage s ON s.company_id = $1 AND s.user_id = u.id
+WHERE u.id = $2
+`
+
+const QuerySetCompanyQuota = `
+UPDATE companies
+SET quota_bytes = $2, quota_files = $3
+WHERE id = $1 AND is_active = true
+`
+
+const QuerySetUserQuota = `
+UPDATE users
+SET quota_bytes = $3, quota_files = $4
+WHERE id = $2 AND company_id = $1
+`
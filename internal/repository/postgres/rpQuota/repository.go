@@ -0,0 +1,85 @@
+package rpQuota
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryQuota struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryQuota {
+	return &RepositoryQuota{db: db}
+}
+
+func (r *RepositoryQuota) GetCompanyUsage(ctx context.Context, companyID string) (*domain.StorageUsage, error) {
+	usage, err := scanUsage(r.db.QueryRowContext(ctx, QueryGetCompanyUsage, companyID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("company not found")
+		}
+		return nil, pkgErrors.Database("unable to get company storage usage")
+	}
+
+	return usage, nil
+}
+
+// GetUserUsage returns what a user has stored in the company and the user's own
+// quota. The user does not have to belong to the company anymore.
+func (r *RepositoryQuota) GetUserUsage(ctx context.Context, companyID, userID string) (*domain.StorageUsage, error) {
+	usage, err := scanUsage(r.db.QueryRowContext(ctx, QueryGetUserUsage, companyID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("user not found")
+		}
+		return nil, pkgErrors.Database("unable to get user storage usage")
+	}
+
+	return usage, nil
+}
+
+func (r *RepositoryQuota) SetCompanyQuota(ctx context.Context, companyID string, quota domain.StorageQuota) error {
+	res, err := r.db.ExecContext(ctx, QuerySetCompanyQuota, companyID, quota.MaxBytes, quota.MaxFiles)
+	if err != nil {
+		return pkgErrors.Database("unable to update company quota")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("company not found")
+	}
+
+	return nil
+}
+
+func (r *RepositoryQuota) SetUserQuota(ctx context.Context, companyID, userID string, quota domain.StorageQuota) error {
+	res, err := r.db.ExecContext(ctx, QuerySetUserQuota, companyID, userID, quota.MaxBytes, quota.MaxFiles)
+	if err != nil {
+		return pkgErrors.Database("unable to update user quota")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("user not found")
+	}
+
+	return nil
+}
+
+func scanUsage(row *sql.Row) (*domain.StorageUsage, error) {
+	var usage domain.StorageUsage
+
+	err := row.Scan(
+		&usage.Quota.MaxBytes, &usage.Quota.MaxFiles,
+		&usage.UsedBytes, &usage.UsedFiles,
+		&usage.ReservedBytes, &usage.ReservedFiles,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
@@ -0,0 +1,109 @@
+package rpQuota
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryQuota) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func usageColumns() []string {
+	return []string{"quota_bytes", "quota_files", "used_bytes", "used_files", "reserved_bytes", "reserved_files"}
+}
+
+func TestGetCompanyUsage_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`WITH reserved AS .+ FROM companies c WHERE c.id = \$1`).
+		WithArgs("company-id").
+		WillReturnRows(sqlmock.NewRows(usageColumns()).AddRow(int64(1000), nil, int64(600), int64(3), int64(200), int64(1)))
+
+	usage, err := repo.GetCompanyUsage(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), *usage.Quota.MaxBytes)
+	assert.Nil(t, usage.Quota.MaxFiles)
+	assert.Equal(t, int64(600), usage.UsedBytes)
+	assert.Equal(t, int64(3), usage.UsedFiles)
+	assert.Equal(t, int64(200), usage.ReservedBytes)
+	assert.Equal(t, int64(1), usage.ReservedFiles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCompanyUsage_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM companies c`).
+		WithArgs("company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	usage, err := repo.GetCompanyUsage(context.Background(), "company-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, usage)
+	assert.Contains(t, err.Error(), "company not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserUsage_NoFilesYet(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM users u LEFT JOIN storage_usage s`).
+		WithArgs("company-id", "user-id").
+		WillReturnRows(sqlmock.NewRows(usageColumns()).AddRow(nil, int64(10), int64(0), int64(0), int64(0), int64(0)))
+
+	usage, err := repo.GetUserUsage(context.Background(), "company-id", "user-id")
+
+	assert.NoError(t, err)
+	assert.Nil(t, usage.Quota.MaxBytes)
+	assert.Equal(t, int64(10), *usage.Quota.MaxFiles)
+	assert.Zero(t, usage.UsedBytes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetCompanyQuota_Unlimited(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	maxBytes := int64(1 << 30)
+	mock.ExpectExec(`UPDATE companies SET quota_bytes = \$2, quota_files = \$3`).
+		WithArgs("company-id", maxBytes, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SetCompanyQuota(context.Background(), "company-id", domain.StorageQuota{MaxBytes: &maxBytes})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetUserQuota_UserOfOtherCompany(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	maxFiles := int64(100)
+	mock.ExpectExec(`UPDATE users SET quota_bytes = \$3, quota_files = \$4 WHERE id = \$2 AND company_id = \$1`).
+		WithArgs("company-id", "user-id", nil, maxFiles).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.SetUserQuota(context.Background(), "company-id", "user-id", domain.StorageQuota{MaxFiles: &maxFiles})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "user not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,39 @@
+package rpShareLinks
+
+const QueryCreateLink = `
+INSERT INTO share_links (
+    id, token_hash, company_id, user_created, file_id,
+    password_hash, expires_at, max_downloads, allowed_ips, created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+const QueryGetLinkByToken = `
+SELECT sl.id, sl.token_hash, sl.company_id, sl.user_created, sl.file_id,
+       sl.password_hash, sl.expires_at, sl.max_downloads, sl.download_count,
+       sl.allowed_ips, sl.created_at
+FROM share_links sl
+JOIN companies c ON c.id = sl.company_id AND c.is_active = true
+WHERE sl.token_hash = $1
+`
+
+const QueryListLinks = `
+SELECT id, token_hash, company_id, user_created, file_id,
+       password_hash, expires_at, max_downloads, download_count,
+       allowed_ips, created_at
+FROM share_links
+WHERE company_id = $1 AND user_created = $2
+ORDER BY created_at DESC
+`
+
+const QueryDeleteLink = `
+DELETE FROM share_links
+WHERE id = $1 AND company_id = $2 AND user_created = $3
+`
+
+// QueryRecordDownload counts a download unless the limit is already reached,
+// so concurrent downloads cannot go over it.
+const QueryRecordDownload = `
+UPDATE share_links
+SET download_count = download_count + 1
+WHERE id = $1 AND (max_downloads IS NULL OR download_count < max_downloads)
+`
@@ -0,0 +1,119 @@
+package rpShareLinks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryShareLinks struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryShareLinks {
+	return &RepositoryShareLinks{db: db}
+}
+
+func (r *RepositoryShareLinks) CreateLink(ctx context.Context, link *domain.ShareLink) (*domain.ShareLink, error) {
+	_, err := r.db.ExecContext(ctx, QueryCreateLink,
+		link.ID, link.TokenHash, link.CompanyID, link.UserCreateID, link.FileID,
+		link.PasswordHash, link.ExpiresAt, link.MaxDownloads, pq.Array(link.AllowedIPs), link.CreatedAt,
+	)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to create share link")
+	}
+
+	return link, nil
+}
+
+// GetLinkByToken finds a link by the hash of its token. Links of deactivated
+// companies are not found.
+func (r *RepositoryShareLinks) GetLinkByToken(ctx context.Context, tokenHash string) (*domain.ShareLink, error) {
+	link, err := scanLink(r.db.QueryRowContext(ctx, QueryGetLinkByToken, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("share link not found")
+		}
+		return nil, pkgErrors.Database("unable to get share link")
+	}
+
+	return link, nil
+}
+
+func (r *RepositoryShareLinks) ListLinks(ctx context.Context, companyID, userID string) ([]*domain.ShareLink, error) {
+	rows, err := r.db.QueryContext(ctx, QueryListLinks, companyID, userID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to list share links")
+	}
+	defer rows.Close()
+
+	links := []*domain.ShareLink{}
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan share link")
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to list share links")
+	}
+
+	return links, nil
+}
+
+func (r *RepositoryShareLinks) DeleteLink(ctx context.Context, companyID, userID, linkID string) error {
+	res, err := r.db.ExecContext(ctx, QueryDeleteLink, linkID, companyID, userID)
+	if err != nil {
+		return pkgErrors.Database("unable to delete share link")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.NotFound("share link not found")
+	}
+
+	return nil
+}
+
+// RecordDownload counts a download of the link and fails once its download
+// limit is reached.
+func (r *RepositoryShareLinks) RecordDownload(ctx context.Context, linkID string) error {
+	res, err := r.db.ExecContext(ctx, QueryRecordDownload, linkID)
+	if err != nil {
+		return pkgErrors.Database("unable to record share link download")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return pkgErrors.Gone("share link download limit reached")
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLink(row scanner) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+
+	err := row.Scan(
+		&link.ID, &link.TokenHash, &link.CompanyID, &link.UserCreateID, &link.FileID,
+		&link.PasswordHash, &link.ExpiresAt, &link.MaxDownloads, &link.DownloadCount,
+		pq.Array(&link.AllowedIPs), &link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.AllowedIPs == nil {
+		link.AllowedIPs = []string{}
+	}
+
+	return &link, nil
+}
@@ -0,0 +1,148 @@
+package rpShareLinks
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryShareLinks) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func linkColumns() []string {
+	return []string{
+		"id", "token_hash", "company_id", "user_created", "file_id",
+		"password_hash", "expires_at", "max_downloads", "download_count",
+		"allowed_ips", "created_at",
+	}
+}
+
+func TestCreateLink_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	maxDownloads := 5
+	link := &domain.ShareLink{
+		ID:           "link-id",
+		TokenHash:    "token-hash",
+		CompanyID:    "company-id",
+		UserCreateID: "user-id",
+		FileID:       "file-id",
+		MaxDownloads: &maxDownloads,
+		AllowedIPs:   []string{"10.0.0.0/8"},
+		CreatedAt:    now,
+	}
+
+	mock.ExpectExec(`INSERT INTO share_links`).
+		WithArgs("link-id", "token-hash", "company-id", "user-id", "file-id",
+			nil, nil, 5, pq.Array([]string{"10.0.0.0/8"}), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := repo.CreateLink(context.Background(), link)
+
+	assert.NoError(t, err)
+	assert.Equal(t, link, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLinkByToken_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM share_links sl JOIN companies c ON c.id = sl.company_id AND c.is_active = true WHERE sl.token_hash = \$1`).
+		WithArgs("token-hash").
+		WillReturnRows(sqlmock.NewRows(linkColumns()).
+			AddRow("link-id", "token-hash", "company-id", "user-id", "file-id",
+				"$2a$12$hash", now.Add(time.Hour), nil, 2, "{10.0.0.0/8,192.168.1.5/32}", now))
+
+	link, err := repo.GetLinkByToken(context.Background(), "token-hash")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file-id", link.FileID)
+	assert.True(t, link.HasPassword())
+	assert.Nil(t, link.MaxDownloads)
+	assert.Equal(t, 2, link.DownloadCount)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.5/32"}, link.AllowedIPs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLinkByToken_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM share_links sl`).
+		WithArgs("token-hash").
+		WillReturnError(sql.ErrNoRows)
+
+	link, err := repo.GetLinkByToken(context.Background(), "token-hash")
+
+	assert.Error(t, err)
+	assert.Nil(t, link)
+	assert.Contains(t, err.Error(), "share link not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListLinks_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM share_links WHERE company_id = \$1 AND user_created = \$2 ORDER BY created_at DESC`).
+		WithArgs("company-id", "user-id").
+		WillReturnRows(sqlmock.NewRows(linkColumns()).
+			AddRow("link-id", "token-hash", "company-id", "user-id", "file-id",
+				nil, nil, 3, 0, "{}", now))
+
+	links, err := repo.ListLinks(context.Background(), "company-id", "user-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+	assert.Equal(t, 3, *links[0].MaxDownloads)
+	assert.Empty(t, links[0].AllowedIPs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteLink_NotOwned(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM share_links WHERE id = \$1 AND company_id = \$2 AND user_created = \$3`).
+		WithArgs("link-id", "company-id", "user-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteLink(context.Background(), "company-id", "user-id", "link-id")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "share link not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordDownload_LimitReached(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE share_links SET download_count = download_count \+ 1`).
+		WithArgs("link-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.RecordDownload(context.Background(), "link-id")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "download limit reached")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
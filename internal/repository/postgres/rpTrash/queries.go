@@ -0,0 +1,114 @@
+package rpTrash
+
+// QueryGetDeletedItems returns the deleted items user $2 had access to: the
+// items they created or were granted access to, and the items below them.
+// Folders above an item count while they exist or were deleted together with it.
+const QueryGetDeletedItems = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class
+FROM files
+WHERE company_id = $1 AND is_active = false
+  AND EXISTS (
+      SELECT 1 FROM files g
+      WHERE g.company_id = files.company_id
+        AND (g.id = files.id OR (starts_with(files.full_path, g.full_path || '/')
+                                 AND (g.is_active = true OR g.updated_at = files.updated_at)))
+        AND (g.user_created = $2 OR g.id IN (SELECT file_id FROM file_access WHERE user_id = $2 OR user_id IS NULL))
+  )
+ORDER BY updated_at DESC, name ASC
+`
+
+// QueryGetDeletedItemAccessRank returns the highest role, ranked 0 to 3, that
+// user $2 had on the deleted item $3 through the item itself and the folders
+// at the paths $4 above it that exist or were deleted together with it at $5.
+const QueryGetDeletedItemAccessRank = `
+SELECT COALESCE(MAX(rank), 0) FROM (
+    SELECT 3 AS rank
+    FROM files g
+    WHERE g.company_id = $1 AND g.user_created = $2
+      AND (g.id = $3 OR (g.full_path = ANY($4) AND (g.is_active = true OR g.updated_at = $5)))
+    UNION ALL
+    SELECT CASE a.role WHEN 'owner' THEN 3 WHEN 'editor' THEN 2 ELSE 1 END
+    FROM file_access a
+    JOIN files g ON g.id = a.file_id
+    WHERE g.company_id = $1 AND (a.user_id = $2 OR a.user_id IS NULL)
+      AND (g.id = $3 OR (g.full_path = ANY($4) AND (g.is_active = true OR g.updated_at = $5)))
+) AS grants
+`
+
+const QueryGetDeletedItem = `
+SELECT id, name, type, full_path, parent_id, company_id, user_created,
+       mime_type, size, hash, storage_path,
+       created_at, updated_at, is_active, scan_status, storage_class
+FROM files
+WHERE id = $1 AND company_id = $2 AND is_active = false
+`
+
+const QueryRestoreItem = `
+UPDATE files
+SET is_active = true, name = $3, full_path = $4, parent_id = $5, updated_at = $6
+WHERE id = $1 AND company_id = $2 AND is_active = false
+`
+
+// QueryRestoreDescendants restores the items below the folder $2 that were
+// deleted together with it.
+const QueryRestoreDescendants = `
+UPDATE files
+SET is_active = true, full_path = $3 || SUBSTRING(full_path FROM $4), updated_at = $6
+WHERE company_id = $1 AND starts_with(full_path, $2 || '/') AND is_active = false AND updated_at = $5
+`
+
+// purgeSubtree selects the deleted item $1 and the items below it, through
+// parent_id and, for rows that were stored without one, through their path.
+const purgeSubtree = `
+WITH RECURSIVE subtree AS (
+    SELECT id, full_path, updated_at, storage_path
+    FROM files WHERE id = $1 AND company_id = $2 AND is_active = false
+    UNION ALL
+    SELECT f.id, f.full_path, f.updated_at, f.storage_path
+    FROM files f JOIN subtree s
+      ON f.parent_id = s.id
+      OR (f.parent_id IS NULL AND f.company_id = $2 AND f.is_active = false
+          AND f.updated_at = s.updated_at AND f.full_path = s.full_path || '/' || f.name)
+)
+`
+
+const QueryGetPurgeObjects = purgeSubtree + `
+SELECT storage_path FROM subtree WHERE storage_path IS NOT NULL
+UNION
+SELECT v.storage_path FROM file_versions v JOIN subtree s ON v.file_id = s.id
+UNION
+SELECT r.storage_path FROM file_renditions r JOIN subtree s ON r.file_id = s.id
+`
+
+// QueryPurgeItem deletes the whole subtree, the cascade on parent_id does not
+// reach the rows linked through their path only.
+const QueryPurgeItem = purgeSubtree + `
+DELETE FROM files
+WHERE id IN (SELECT id FROM subtree)
+`
+
+const QueryGetExpiredItems = `
+SELECT f.id, f.name, f.type, f.full_path, f.parent_id, f.company_id, f.user_created,
+       f.mime_type, f.size, f.hash, f.storage_path,
+       f.created_at, f.updated_at, f.is_active, f.scan_status, f.storage_class
+FROM files f
+JOIN companies c ON c.id = f.company_id
+WHERE f.is_active = false
+  AND f.updated_at < NOW() - make_interval(days => COALESCE(c.trash_retention_days, $1))
+ORDER BY f.updated_at ASC
+LIMIT $2
+`
+
+const QueryGetTrashSettings = `
+SELECT id, COALESCE(trash_retention_days, $2)
+FROM companies
+WHERE id = $1 AND is_active = true
+`
+
+const QueryUpdateTrashSettings = `
+UPDATE companies
+SET trash_retention_days = $2
+WHERE id = $1 AND is_active = true
+`
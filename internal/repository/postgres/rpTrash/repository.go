@@ -0,0 +1,243 @@
+package rpTrash
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go-storage/internal/domain"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+type RepositoryTrash struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *RepositoryTrash {
+	return &RepositoryTrash{db: db}
+}
+
+// GetDeletedItems returns the deleted items of the company the user had access to.
+func (r *RepositoryTrash) GetDeletedItems(ctx context.Context, companyID, userID string) ([]*domain.File, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetDeletedItems, companyID, userID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get deleted items")
+	}
+	defer rows.Close()
+
+	var items []*domain.File
+	for rows.Next() {
+		item, err := scanFile(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan deleted item")
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get deleted items")
+	}
+
+	return items, nil
+}
+
+func (r *RepositoryTrash) GetDeletedItem(ctx context.Context, companyID, itemID string) (*domain.File, error) {
+	row := r.db.QueryRowContext(ctx, QueryGetDeletedItem, itemID, companyID)
+
+	item, err := scanFile(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("item not found in trash")
+		}
+		return nil, pkgErrors.Database("unable to get deleted item")
+	}
+
+	return item, nil
+}
+
+// GetDeletedItemRole returns the role the user had on the deleted item, taking
+// the roles granted on the folders above it into account.
+func (r *RepositoryTrash) GetDeletedItemRole(ctx context.Context, userID string, item *domain.File) (domain.AccessRole, error) {
+	var rank int
+	err := r.db.QueryRowContext(ctx, QueryGetDeletedItemAccessRank,
+		item.CompanyId, userID, item.ID, pq.Array(item.FullPath.GetParent().Lineage()), item.UpdatedAt,
+	).Scan(&rank)
+	if err != nil {
+		return domain.AccessNone, pkgErrors.Database("unable to get access role")
+	}
+
+	return domain.AccessRoleFromRank(rank), nil
+}
+
+// RestoreItem reactivates the item under its new name and location. Items that
+// were deleted together with a folder share its deletion time and come back with it.
+func (r *RepositoryTrash) RestoreItem(ctx context.Context, item *domain.File, newPath domain.Path, newParentID *string) (*domain.File, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to restore item")
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	res, err := tx.ExecContext(ctx, QueryRestoreItem,
+		item.ID, item.CompanyId, newPath.GetName(), newPath.String(), newParentID, now,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_unique_name_in_folder") {
+			return nil, pkgErrors.BadRequest("item with this name already exists in the folder")
+		}
+		return nil, pkgErrors.Database("unable to restore item")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, pkgErrors.NotFound("item not found in trash")
+	}
+
+	if item.Type == domain.FileTypeFolder {
+		oldPath := item.FullPath.String()
+		_, err = tx.ExecContext(ctx, QueryRestoreDescendants,
+			item.CompanyId, oldPath, newPath.String(), len(oldPath)+1, item.UpdatedAt, now,
+		)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to restore folder contents")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, pkgErrors.Database("unable to restore item")
+	}
+
+	restored := *item
+	restored.Name = newPath.GetName()
+	restored.FullPath = newPath
+	restored.ParentID = newParentID
+	restored.UpdatedAt = now
+	restored.IsActive = true
+
+	return &restored, nil
+}
+
+// PurgeItem permanently deletes the item together with everything below it and
+// returns the storage paths that were referenced by the removed rows.
+func (r *RepositoryTrash) PurgeItem(ctx context.Context, companyID, itemID string) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to purge item")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, QueryGetPurgeObjects, itemID, companyID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get item objects")
+	}
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			rows.Close()
+			return nil, pkgErrors.Database("unable to scan item object")
+		}
+		paths = append(paths, path)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get item objects")
+	}
+
+	res, err := tx.ExecContext(ctx, QueryPurgeItem, itemID, companyID)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to purge item")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, pkgErrors.NotFound("item not found in trash")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, pkgErrors.Database("unable to purge item")
+	}
+
+	return paths, nil
+}
+
+func (r *RepositoryTrash) GetExpiredItems(ctx context.Context, defaultRetentionDays, limit int) ([]*domain.File, error) {
+	rows, err := r.db.QueryContext(ctx, QueryGetExpiredItems, defaultRetentionDays, limit)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to get expired items")
+	}
+	defer rows.Close()
+
+	var items []*domain.File
+	for rows.Next() {
+		item, err := scanFile(rows)
+		if err != nil {
+			return nil, pkgErrors.Database("unable to scan expired item")
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, pkgErrors.Database("unable to get expired items")
+	}
+
+	return items, nil
+}
+
+func (r *RepositoryTrash) GetSettings(ctx context.Context, companyID string, defaultRetentionDays int) (*domain.TrashSettings, error) {
+	var settings domain.TrashSettings
+
+	err := r.db.QueryRowContext(ctx, QueryGetTrashSettings, companyID, defaultRetentionDays).
+		Scan(&settings.CompanyID, &settings.RetentionDays)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NotFound("company not found")
+		}
+		return nil, pkgErrors.Database("unable to get trash settings")
+	}
+
+	return &settings, nil
+}
+
+func (r *RepositoryTrash) UpdateSettings(ctx context.Context, settings *domain.TrashSettings) (*domain.TrashSettings, error) {
+	res, err := r.db.ExecContext(ctx, QueryUpdateTrashSettings, settings.CompanyID, settings.RetentionDays)
+	if err != nil {
+		return nil, pkgErrors.Database("unable to update trash settings")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, pkgErrors.NotFound("company not found")
+	}
+
+	return settings, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFile(row scanner) (*domain.File, error) {
+	var file domain.File
+	var fullPathStr string
+
+	err := row.Scan(
+		&file.ID, &file.Name, &file.Type, &fullPathStr, &file.ParentID, &file.CompanyId, &file.UserCreateID,
+		&file.MimeType, &file.Size, &file.Hash, &file.StoragePath,
+		&file.CreatedAt, &file.UpdatedAt, &file.IsActive, &file.ScanStatus, &file.StorageClass,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath, err := domain.NewPath(fullPathStr)
+	if err != nil {
+		return nil, err
+	}
+	file.FullPath = fullPath
+
+	return &file, nil
+}
@@ -0,0 +1,288 @@
+package rpTrash
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go-storage/internal/domain"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *RepositoryTrash) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewRepository(db)
+	return db, mock, repo
+}
+
+func fileColumns() []string {
+	return []string{
+		"id", "name", "type", "full_path", "parent_id", "company_id", "user_created",
+		"mime_type", "size", "hash", "storage_path",
+		"created_at", "updated_at", "is_active", "scan_status", "storage_class",
+	}
+}
+
+func TestGetDeletedItems_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(fileColumns()).
+		AddRow("file-id", "test.txt", "file", "/test.txt", nil, "company-id", "user-id",
+			"text/plain", 1024, nil, "companies/company-id/files/test.txt", now, now, false, "clean", "hot").
+		AddRow("folder-id", "docs", "folder", "/docs", nil, "company-id", "user-id",
+			nil, nil, nil, nil, now, now, false, "clean", "hot")
+
+	mock.ExpectQuery(`SELECT (.+) FROM files WHERE company_id = \$1 AND is_active = false`).
+		WithArgs("company-id", "user-id").
+		WillReturnRows(rows)
+
+	result, err := repo.GetDeletedItems(context.Background(), "company-id", "user-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "test.txt", result[0].Name)
+	assert.Equal(t, domain.Path("/docs"), result[1].FullPath)
+	assert.False(t, result[0].IsActive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedItems_DatabaseError(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM files`).
+		WithArgs("company-id", "user-id").
+		WillReturnError(sql.ErrConnDone)
+
+	result, err := repo.GetDeletedItems(context.Background(), "company-id", "user-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "unable to get deleted items")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedItemRole_ThroughFolders(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	deletedAt := time.Now()
+	item := &domain.File{ID: "file-id", CompanyId: "company-id", FullPath: "/team/docs/plan.txt", UpdatedAt: deletedAt}
+
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), 0\)`).
+		WithArgs("company-id", "user-id", "file-id", pq.Array([]string{"/team", "/team/docs"}), deletedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow(2))
+
+	role, err := repo.GetDeletedItemRole(context.Background(), "user-id", item)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AccessEditor, role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedItem_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM files WHERE id = \$1 AND company_id = \$2 AND is_active = false`).
+		WithArgs("file-id", "company-id").
+		WillReturnError(sql.ErrNoRows)
+
+	result, err := repo.GetDeletedItem(context.Background(), "company-id", "file-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "item not found in trash")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreItem_File(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	item := &domain.File{
+		ID:        "file-id",
+		Name:      "test.txt",
+		Type:      domain.FileTypeFile,
+		FullPath:  domain.Path("/docs/test.txt"),
+		CompanyId: "company-id",
+		UpdatedAt: time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE files SET is_active = true`).
+		WithArgs("file-id", "company-id", "test (1).txt", "/test (1).txt", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.RestoreItem(context.Background(), item, domain.Path("/test (1).txt"), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test (1).txt", result.Name)
+	assert.Equal(t, domain.Path("/test (1).txt"), result.FullPath)
+	assert.True(t, result.IsActive)
+	assert.Nil(t, result.ParentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreItem_FolderRestoresContents(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	deletedAt := time.Now().Add(-time.Hour)
+	item := &domain.File{
+		ID:        "folder-id",
+		Name:      "docs",
+		Type:      domain.FileTypeFolder,
+		FullPath:  domain.Path("/docs"),
+		CompanyId: "company-id",
+		UpdatedAt: deletedAt,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE files SET is_active = true`).
+		WithArgs("folder-id", "company-id", "docs", "/docs", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Matched by prefix, not LIKE, so _ and % in folder names are literal.
+	mock.ExpectExec(`UPDATE files SET is_active = true, full_path = \$3 \|\| SUBSTRING\(full_path FROM \$4\), updated_at = \$6 WHERE company_id = \$1 AND starts_with\(full_path, \$2 \|\| '/'\)`).
+		WithArgs("company-id", "/docs", "/docs", 6, deletedAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	result, err := repo.RestoreItem(context.Background(), item, domain.Path("/docs"), nil)
+
+	assert.NoError(t, err)
+	assert.True(t, result.IsActive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreItem_NameConflict(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	item := &domain.File{ID: "file-id", Type: domain.FileTypeFile, FullPath: domain.Path("/test.txt"), CompanyId: "company-id"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE files SET is_active = true`).
+		WillReturnError(&mockError{message: `duplicate key value violates unique constraint "idx_unique_name_in_folder"`})
+	mock.ExpectRollback()
+
+	result, err := repo.RestoreItem(context.Background(), item, domain.Path("/test.txt"), nil)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "item with this name already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeItem_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WITH RECURSIVE subtree`).
+		WithArgs("file-id", "company-id").
+		WillReturnRows(sqlmock.NewRows([]string{"storage_path"}).
+			AddRow("companies/company-id/files/test.txt").
+			AddRow("companies/company-id/files/file-id/versions/v2/test.txt"))
+	mock.ExpectExec(`WITH RECURSIVE subtree (.+) DELETE FROM files`).
+		WithArgs("file-id", "company-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	paths, err := repo.PurgeItem(context.Background(), "company-id", "file-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, paths, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeItem_NotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WITH RECURSIVE subtree`).
+		WithArgs("file-id", "company-id").
+		WillReturnRows(sqlmock.NewRows([]string{"storage_path"}))
+	mock.ExpectExec(`WITH RECURSIVE subtree (.+) DELETE FROM files`).
+		WithArgs("file-id", "company-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	paths, err := repo.PurgeItem(context.Background(), "company-id", "file-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, paths)
+	assert.Contains(t, err.Error(), "item not found in trash")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExpiredItems_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(fileColumns()).
+		AddRow("file-id", "old.txt", "file", "/old.txt", nil, "company-id", "user-id",
+			"text/plain", 10, nil, "companies/company-id/files/old.txt", now, now, false, "clean", "hot")
+
+	mock.ExpectQuery(`SELECT (.+) FROM files f JOIN companies c`).
+		WithArgs(30, 100).
+		WillReturnRows(rows)
+
+	result, err := repo.GetExpiredItems(context.Background(), 30, 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "old.txt", result[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSettings_Success(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, COALESCE\(trash_retention_days, \$2\) FROM companies`).
+		WithArgs("company-id", 30).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "trash_retention_days"}).AddRow("company-id", 7))
+
+	result, err := repo.GetSettings(context.Background(), "company-id", 30)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.RetentionDays)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSettings_CompanyNotFound(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE companies SET trash_retention_days`).
+		WithArgs("company-id", 14).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	result, err := repo.UpdateSettings(context.Background(), &domain.TrashSettings{CompanyID: "company-id", RetentionDays: 14})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "company not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type mockError struct {
+	message string
+}
+
+func (e *mockError) Error() string {
+	return e.message
+}
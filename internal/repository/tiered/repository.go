@@ -0,0 +1,183 @@
+// Package tiered keeps objects on a hot and a cold storage repository. New
+// objects are written to the hot tier and moved between the tiers by the
+// lifecycle worker, while reads find them on whichever tier holds them.
+package tiered
+
+import (
+	"context"
+	stdErrors "errors"
+	"io"
+	"net/http"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+// Storage is the storage repository of a tier.
+type Storage interface {
+	StoreFile(ctx context.Context, key string, reader io.Reader, size int64, mimeType string) (string, error)
+	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
+	GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error)
+	CopyFile(ctx context.Context, srcKey, dstKey string) error
+	DeleteFile(ctx context.Context, key string) error
+	GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error)
+	ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error)
+
+	InitChunkedUpload(ctx context.Context, key string, mimeType string) (string, error)
+	UploadChunk(ctx context.Context, uploadID, key string, chunkIndex int, reader io.Reader, size int64) (string, error)
+	CompleteChunkedUpload(ctx context.Context, uploadID, key string, parts []string) error
+	AbortChunkedUpload(ctx context.Context, uploadID, key string) error
+	AbortStaleChunkedUploads(ctx context.Context, olderThan time.Time) (int, error)
+
+	GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error)
+	GetPresignedUploadURL(ctx context.Context, key string, expiry time.Duration, headers http.Header) (string, error)
+}
+
+// StorageRepository writes to the hot tier, which it embeds, and reads from
+// the hot tier first. Objects missing there are read from the cold tier, so a
+// read takes a lookup on the hot tier first.
+type StorageRepository struct {
+	Storage
+	cold Storage
+}
+
+func NewStorageRepository(hot, cold Storage) *StorageRepository {
+	return &StorageRepository{Storage: hot, cold: cold}
+}
+
+func (r *StorageRepository) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	tier, err := r.locate(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return tier.GetFile(ctx, key)
+}
+
+func (r *StorageRepository) GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	tier, err := r.locate(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return tier.GetFileRange(ctx, key, start, end)
+}
+
+func (r *StorageRepository) GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error) {
+	info, err := r.Storage.GetFileInfo(ctx, key)
+	if stdErrors.Is(err, errors.ErrNotFound) {
+		return r.cold.GetFileInfo(ctx, key)
+	}
+	return info, err
+}
+
+// ListFiles lists the objects of both tiers. An object found on both, while
+// it is being moved, is listed once.
+func (r *StorageRepository) ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error) {
+	files, err := r.Storage.ListFiles(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	coldFiles, err := r.cold.ListFiles(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[string]bool, len(files))
+	for _, info := range files {
+		listed[info.Key] = true
+	}
+	for _, info := range coldFiles {
+		if !listed[info.Key] {
+			files = append(files, info)
+		}
+	}
+
+	return files, nil
+}
+
+// CopyFile copies the object to dstKey on the hot tier, where copies start
+// out like any new object.
+func (r *StorageRepository) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	err := r.Storage.CopyFile(ctx, srcKey, dstKey)
+	if !stdErrors.Is(err, errors.ErrNotFound) {
+		return err
+	}
+
+	return transfer(ctx, r.cold, r.Storage, srcKey, dstKey)
+}
+
+// DeleteFile removes the object from both tiers.
+func (r *StorageRepository) DeleteFile(ctx context.Context, key string) error {
+	hotErr := r.Storage.DeleteFile(ctx, key)
+	coldErr := r.cold.DeleteFile(ctx, key)
+	if hotErr != nil {
+		return hotErr
+	}
+	return coldErr
+}
+
+func (r *StorageRepository) GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error) {
+	tier, err := r.locate(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return tier.GetPresignedURL(ctx, key, expiry, filename)
+}
+
+// MoveObject moves the object to the tier of class. An object already found
+// there is only removed from the other tier, so an interrupted move can be
+// repeated.
+func (r *StorageRepository) MoveObject(ctx context.Context, key string, class domain.StorageClass) error {
+	from, to := r.Storage, r.cold
+	if class == domain.StorageClassHot {
+		from, to = r.cold, r.Storage
+	}
+
+	if _, err := to.GetFileInfo(ctx, key); err != nil {
+		if !stdErrors.Is(err, errors.ErrNotFound) {
+			return err
+		}
+		if err := transfer(ctx, from, to, key, key); err != nil {
+			return err
+		}
+	}
+
+	return from.DeleteFile(ctx, key)
+}
+
+// locate returns the tier holding the object.
+func (r *StorageRepository) locate(ctx context.Context, key string) (Storage, error) {
+	_, err := r.Storage.GetFileInfo(ctx, key)
+	if err == nil {
+		return r.Storage, nil
+	}
+	if !stdErrors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+
+	if _, err := r.cold.GetFileInfo(ctx, key); err != nil {
+		return nil, err
+	}
+	return r.cold, nil
+}
+
+// transfer streams an object from one tier to another.
+func transfer(ctx context.Context, from, to Storage, srcKey, dstKey string) error {
+	info, err := from.GetFileInfo(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+
+	reader, err := from.GetFile(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if _, err := to.StoreFile(ctx, dstKey, reader, info.Size, info.MimeType); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package tiered
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-storage/internal/domain"
+	"go-storage/internal/repository/localfs"
+	pkgErrors "go-storage/pkg/errors"
+)
+
+const testKey = "companies/c1/files/f1/report.txt"
+
+func newTestRepository(t *testing.T) (*StorageRepository, *localfs.StorageRepository, *localfs.StorageRepository) {
+	hot, err := localfs.NewStorageRepository(t.TempDir())
+	require.NoError(t, err)
+	cold, err := localfs.NewStorageRepository(t.TempDir())
+	require.NoError(t, err)
+
+	return NewStorageRepository(hot, cold), hot, cold
+}
+
+func readObject(t *testing.T, storage Storage, key string) string {
+	object, err := storage.GetFile(context.Background(), key)
+	require.NoError(t, err)
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestStoreFile_WritesHot(t *testing.T) {
+	repo, hot, cold := newTestRepository(t)
+
+	_, err := repo.StoreFile(context.Background(), testKey, strings.NewReader("content"), 7, "text/plain")
+	require.NoError(t, err)
+
+	assert.Equal(t, "content", readObject(t, hot, testKey))
+	_, err = cold.GetFileInfo(context.Background(), testKey)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestMoveObject_ReadsFromColdTier(t *testing.T) {
+	repo, hot, cold := newTestRepository(t)
+	ctx := context.Background()
+
+	_, err := repo.StoreFile(ctx, testKey, strings.NewReader("0123456789"), 10, "text/plain")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.MoveObject(ctx, testKey, domain.StorageClassCold))
+
+	_, err = hot.GetFileInfo(ctx, testKey)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+	assert.Equal(t, "0123456789", readObject(t, cold, testKey))
+
+	assert.Equal(t, "0123456789", readObject(t, repo, testKey))
+
+	object, err := repo.GetFileRange(ctx, testKey, 2, 4)
+	require.NoError(t, err)
+	data, err := io.ReadAll(object)
+	require.NoError(t, err)
+	require.NoError(t, object.Close())
+	assert.Equal(t, "234", string(data))
+
+	info, err := repo.GetFileInfo(ctx, testKey)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), info.Size)
+}
+
+func TestMoveObject_Repeated(t *testing.T) {
+	repo, hot, cold := newTestRepository(t)
+	ctx := context.Background()
+
+	// An earlier move copied the object but stopped before removing it.
+	_, err := hot.StoreFile(ctx, testKey, strings.NewReader("content"), 7, "text/plain")
+	require.NoError(t, err)
+	_, err = cold.StoreFile(ctx, testKey, strings.NewReader("content"), 7, "text/plain")
+	require.NoError(t, err)
+
+	files, err := repo.ListFiles(ctx, "companies/c1/")
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	require.NoError(t, repo.MoveObject(ctx, testKey, domain.StorageClassCold))
+	require.NoError(t, repo.MoveObject(ctx, testKey, domain.StorageClassCold))
+
+	_, err = hot.GetFileInfo(ctx, testKey)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+	assert.Equal(t, "content", readObject(t, repo, testKey))
+
+	require.NoError(t, repo.MoveObject(ctx, testKey, domain.StorageClassHot))
+	assert.Equal(t, "content", readObject(t, hot, testKey))
+	_, err = cold.GetFileInfo(ctx, testKey)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestMoveObject_NotFound(t *testing.T) {
+	repo, _, _ := newTestRepository(t)
+
+	err := repo.MoveObject(context.Background(), testKey, domain.StorageClassCold)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestCopyFile_FromColdTier(t *testing.T) {
+	repo, hot, cold := newTestRepository(t)
+	ctx := context.Background()
+
+	_, err := cold.StoreFile(ctx, testKey, strings.NewReader("content"), 7, "text/plain")
+	require.NoError(t, err)
+
+	copyKey := "companies/c1/files/f2/report.txt"
+	require.NoError(t, repo.CopyFile(ctx, testKey, copyKey))
+	assert.Equal(t, "content", readObject(t, hot, copyKey))
+
+	err = repo.CopyFile(ctx, "companies/c1/files/f9/missing.txt", copyKey)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestDeleteFile_BothTiers(t *testing.T) {
+	repo, hot, cold := newTestRepository(t)
+	ctx := context.Background()
+
+	_, err := hot.StoreFile(ctx, testKey, strings.NewReader("content"), 7, "text/plain")
+	require.NoError(t, err)
+	_, err = cold.StoreFile(ctx, testKey, strings.NewReader("content"), 7, "text/plain")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteFile(ctx, testKey))
+
+	_, err = repo.GetFile(ctx, testKey)
+	assert.ErrorIs(t, err, pkgErrors.ErrNotFound)
+}
+
+func TestListFiles_BothTiers(t *testing.T) {
+	repo, hot, cold := newTestRepository(t)
+	ctx := context.Background()
+
+	_, err := hot.StoreFile(ctx, "companies/c1/files/f1/a.txt", strings.NewReader("a"), 1, "text/plain")
+	require.NoError(t, err)
+	_, err = cold.StoreFile(ctx, "companies/c1/files/f2/b.txt", strings.NewReader("b"), 1, "text/plain")
+	require.NoError(t, err)
+
+	files, err := repo.ListFiles(ctx, "companies/c1/")
+	require.NoError(t, err)
+
+	keys := make([]string, len(files))
+	for i, info := range files {
+		keys[i] = info.Key
+	}
+	assert.ElementsMatch(t, []string{"companies/c1/files/f1/a.txt", "companies/c1/files/f2/b.txt"}, keys)
+}
+
+func TestGetPresignedURL_ColdTier(t *testing.T) {
+	repo, _, cold := newTestRepository(t)
+	ctx := context.Background()
+
+	_, err := cold.StoreFile(ctx, testKey, strings.NewReader("content"), 7, "text/plain")
+	require.NoError(t, err)
+
+	// The cold tier holding the object is asked, which has no presigned URLs
+	// being local, rather than the hot tier answering not found.
+	_, err = repo.GetPresignedURL(ctx, testKey, time.Minute, "report.txt")
+	assert.ErrorIs(t, err, pkgErrors.ErrInvalidOperation)
+}
@@ -0,0 +1,20 @@
+package ucFileAccess
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+)
+
+type AccessRepository interface {
+	GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error)
+	ListEntries(ctx context.Context, companyID, fileID string) ([]*domain.AccessEntry, error)
+	GrantAccess(ctx context.Context, entry *domain.AccessEntry) (*domain.AccessEntry, error)
+	RevokeAccess(ctx context.Context, companyID, fileID string, userID *string) error
+	GetSettings(ctx context.Context, companyID string) (*domain.SharingSettings, error)
+	UpdateSettings(ctx context.Context, settings *domain.SharingSettings) (*domain.SharingSettings, error)
+}
+
+type FileRepository interface {
+	GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
+}
@@ -0,0 +1,125 @@
+package ucFileAccess
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type UseCaseFileAccess struct {
+	accessRepo AccessRepository
+	fileRepo   FileRepository
+}
+
+func NewUseCaseFileAccess(accessRepo AccessRepository, fileRepo FileRepository) *UseCaseFileAccess {
+	return &UseCaseFileAccess{
+		accessRepo: accessRepo,
+		fileRepo:   fileRepo,
+	}
+}
+
+// ListAccess returns the entries granted on the item itself. Roles inherited
+// from the folders above it are listed on those folders.
+func (uc *UseCaseFileAccess) ListAccess(ctx context.Context, companyID, userID, fileID string) ([]*domain.AccessEntry, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if _, err := uc.getItem(ctx, companyID, userID, fileID, domain.AccessViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.accessRepo.ListEntries(ctx, companyID, fileID)
+}
+
+// GrantAccess gives granteeID, or the whole company when it is nil, a role on
+// the item. Granting again replaces the previous role of the grantee.
+func (uc *UseCaseFileAccess) GrantAccess(ctx context.Context, companyID, userID, fileID string, granteeID *string, role domain.AccessRole) (*domain.AccessEntry, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if !role.IsValid() {
+		return nil, errors.BadRequest("role must be viewer, editor or owner")
+	}
+
+	if _, err := uc.getItem(ctx, companyID, userID, fileID, domain.AccessOwner); err != nil {
+		return nil, err
+	}
+
+	return uc.accessRepo.GrantAccess(ctx, &domain.AccessEntry{
+		ID:        uuid.NewString(),
+		FileID:    fileID,
+		CompanyID: companyID,
+		UserID:    granteeID,
+		Role:      role,
+		GrantedBy: userID,
+		CreatedAt: time.Now(),
+	})
+}
+
+// RevokeAccess removes the entry of granteeID, or the company-wide entry when
+// it is nil. The creator of an item keeps owning it.
+func (uc *UseCaseFileAccess) RevokeAccess(ctx context.Context, companyID, userID, fileID string, granteeID *string) error {
+	if companyID == "" {
+		return errors.BadRequest("company ID is required")
+	}
+
+	if _, err := uc.getItem(ctx, companyID, userID, fileID, domain.AccessOwner); err != nil {
+		return err
+	}
+
+	return uc.accessRepo.RevokeAccess(ctx, companyID, fileID, granteeID)
+}
+
+func (uc *UseCaseFileAccess) GetSettings(ctx context.Context, companyID string) (*domain.SharingSettings, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.accessRepo.GetSettings(ctx, companyID)
+}
+
+// UpdateSettings only applies to items created afterwards, existing items keep
+// their entries.
+func (uc *UseCaseFileAccess) UpdateSettings(ctx context.Context, companyID string, shareNewFiles bool) (*domain.SharingSettings, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.accessRepo.UpdateSettings(ctx, &domain.SharingSettings{
+		CompanyID:     companyID,
+		ShareNewFiles: shareNewFiles,
+	})
+}
+
+// getItem returns the item when the user has at least the required role on
+// it. Items the user has no access to at all are not found.
+func (uc *UseCaseFileAccess) getItem(ctx context.Context, companyID, userID, fileID string, required domain.AccessRole) (*domain.File, error) {
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	file, err := uc.fileRepo.GetFile(ctx, companyID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := uc.accessRepo.GetAccessRole(ctx, companyID, userID, file.FullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == domain.AccessNone {
+		return nil, errors.NotFound("file not found")
+	}
+	if !role.Allows(required) {
+		return nil, errors.Forbidden(fmt.Sprintf("%s access is required", required))
+	}
+
+	return file, nil
+}
@@ -0,0 +1,179 @@
+package ucFileAccess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	customErrors "go-storage/pkg/errors"
+)
+
+type accessRepoMock struct {
+	mock.Mock
+}
+
+func (m *accessRepoMock) GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error) {
+	args := m.Called(ctx, companyID, userID, path)
+	return args.Get(0).(domain.AccessRole), args.Error(1)
+}
+
+func (m *accessRepoMock) ListEntries(ctx context.Context, companyID, fileID string) ([]*domain.AccessEntry, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessEntry), args.Error(1)
+}
+
+func (m *accessRepoMock) GrantAccess(ctx context.Context, entry *domain.AccessEntry) (*domain.AccessEntry, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessEntry), args.Error(1)
+}
+
+func (m *accessRepoMock) RevokeAccess(ctx context.Context, companyID, fileID string, userID *string) error {
+	return m.Called(ctx, companyID, fileID, userID).Error(0)
+}
+
+func (m *accessRepoMock) GetSettings(ctx context.Context, companyID string) (*domain.SharingSettings, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SharingSettings), args.Error(1)
+}
+
+func (m *accessRepoMock) UpdateSettings(ctx context.Context, settings *domain.SharingSettings) (*domain.SharingSettings, error) {
+	args := m.Called(ctx, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SharingSettings), args.Error(1)
+}
+
+type fileRepoMock struct {
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func setupUseCase() (*UseCaseFileAccess, *accessRepoMock, *fileRepoMock) {
+	accessRepo := new(accessRepoMock)
+	fileRepo := new(fileRepoMock)
+	return NewUseCaseFileAccess(accessRepo, fileRepo), accessRepo, fileRepo
+}
+
+func reportFile() *domain.File {
+	return &domain.File{ID: "file-id", Name: "report.pdf", Type: domain.FileTypeFile, FullPath: "/docs/report.pdf", CompanyId: "company-id"}
+}
+
+func appErrorCode(t *testing.T, err error) int {
+	var appErr *customErrors.AppError
+	if !assert.ErrorAs(t, err, &appErr) {
+		return 0
+	}
+	return appErr.Code
+}
+
+func TestUseCaseFileAccess_GrantAccess(t *testing.T) {
+	t.Run("owner grants a role to a user", func(t *testing.T) {
+		uc, access, files := setupUseCase()
+
+		granteeID := "grantee-id"
+		files.On("GetFile", mock.Anything, "company-id", "file-id").Return(reportFile(), nil)
+		access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/docs/report.pdf")).Return(domain.AccessOwner, nil)
+		access.On("GrantAccess", mock.Anything, mock.MatchedBy(func(entry *domain.AccessEntry) bool {
+			return entry.FileID == "file-id" && *entry.UserID == granteeID && entry.Role == domain.AccessViewer && entry.GrantedBy == "user-id"
+		})).Return(&domain.AccessEntry{ID: "entry-id"}, nil)
+
+		entry, err := uc.GrantAccess(context.Background(), "company-id", "user-id", "file-id", &granteeID, domain.AccessViewer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "entry-id", entry.ID)
+		access.AssertExpectations(t)
+	})
+
+	t.Run("editor cannot grant", func(t *testing.T) {
+		uc, access, files := setupUseCase()
+
+		files.On("GetFile", mock.Anything, "company-id", "file-id").Return(reportFile(), nil)
+		access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/docs/report.pdf")).Return(domain.AccessEditor, nil)
+
+		entry, err := uc.GrantAccess(context.Background(), "company-id", "user-id", "file-id", nil, domain.AccessEditor)
+
+		assert.Nil(t, entry)
+		assert.Equal(t, 403, appErrorCode(t, err))
+		access.AssertNotCalled(t, "GrantAccess", mock.Anything, mock.Anything)
+	})
+
+	t.Run("item without access is not found", func(t *testing.T) {
+		uc, access, files := setupUseCase()
+
+		files.On("GetFile", mock.Anything, "company-id", "file-id").Return(reportFile(), nil)
+		access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/docs/report.pdf")).Return(domain.AccessNone, nil)
+
+		entry, err := uc.GrantAccess(context.Background(), "company-id", "user-id", "file-id", nil, domain.AccessEditor)
+
+		assert.Nil(t, entry)
+		assert.Equal(t, 404, appErrorCode(t, err))
+	})
+
+	t.Run("rejects an unknown role", func(t *testing.T) {
+		uc, _, files := setupUseCase()
+
+		entry, err := uc.GrantAccess(context.Background(), "company-id", "user-id", "file-id", nil, domain.AccessRole("admin"))
+
+		assert.Nil(t, entry)
+		assert.Equal(t, 400, appErrorCode(t, err))
+		files.AssertNotCalled(t, "GetFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUseCaseFileAccess_ListAccess(t *testing.T) {
+	uc, access, files := setupUseCase()
+
+	entries := []*domain.AccessEntry{{ID: "entry-id", FileID: "file-id", Role: domain.AccessEditor}}
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(reportFile(), nil)
+	access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/docs/report.pdf")).Return(domain.AccessViewer, nil)
+	access.On("ListEntries", mock.Anything, "company-id", "file-id").Return(entries, nil)
+
+	result, err := uc.ListAccess(context.Background(), "company-id", "user-id", "file-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entries, result)
+}
+
+func TestUseCaseFileAccess_RevokeAccess(t *testing.T) {
+	uc, access, files := setupUseCase()
+
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(reportFile(), nil)
+	access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/docs/report.pdf")).Return(domain.AccessOwner, nil)
+	access.On("RevokeAccess", mock.Anything, "company-id", "file-id", (*string)(nil)).Return(nil)
+
+	err := uc.RevokeAccess(context.Background(), "company-id", "user-id", "file-id", nil)
+
+	assert.NoError(t, err)
+	access.AssertExpectations(t)
+}
+
+func TestUseCaseFileAccess_UpdateSettings(t *testing.T) {
+	uc, access, _ := setupUseCase()
+
+	settings := &domain.SharingSettings{CompanyID: "company-id", ShareNewFiles: true}
+	access.On("UpdateSettings", mock.Anything, settings).Return(settings, nil)
+
+	result, err := uc.UpdateSettings(context.Background(), "company-id", true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.ShareNewFiles)
+}
@@ -0,0 +1,99 @@
+package ucFileFolder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+// requireAccess fails unless the user has at least the required role on the
+// item at path. Items the user has no access to at all are reported as
+// notFound, so their existence is not revealed.
+func (uc *UseCaseFileFolder) requireAccess(ctx context.Context, companyID, userID string, path domain.Path, required domain.AccessRole, notFound string) error {
+	role, err := uc.accessRepo.GetAccessRole(ctx, companyID, userID, path)
+	if err != nil {
+		return err
+	}
+
+	if role.Allows(required) {
+		return nil
+	}
+	if role == domain.AccessNone {
+		return errors.NotFound(notFound)
+	}
+	return errors.Forbidden(fmt.Sprintf("%s access is required", required))
+}
+
+// requireWriteInto fails unless the user may create items in the folder at
+// parentPath. Anyone may create items at the top level.
+func (uc *UseCaseFileFolder) requireWriteInto(ctx context.Context, companyID, userID string, parentPath domain.Path) error {
+	if parentPath.IsRoot() {
+		return nil
+	}
+
+	return uc.requireAccess(ctx, companyID, userID, parentPath, domain.AccessEditor, "folder not found")
+}
+
+// grantedPathsIn returns nil when the user may view the whole folder at path.
+// Otherwise it returns the items inside it the user has access to, and fails
+// when there are none below a folder other than the root.
+func (uc *UseCaseFileFolder) grantedPathsIn(ctx context.Context, companyID, userID string, path domain.Path) ([]domain.Path, error) {
+	role, err := uc.accessRepo.GetAccessRole(ctx, companyID, userID, path)
+	if err != nil {
+		return nil, err
+	}
+	if role.Allows(domain.AccessViewer) {
+		return nil, nil
+	}
+
+	granted, err := uc.accessRepo.ListGrantedPaths(ctx, companyID, userID, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(granted) == 0 && !path.IsRoot() {
+		return nil, errors.NotFound("folder not found")
+	}
+
+	return granted, nil
+}
+
+// isVisibleThrough reports whether the item at path is one of the granted
+// items, lies below one of them, or is a folder leading to one of them.
+func isVisibleThrough(path domain.Path, granted []domain.Path) bool {
+	for _, g := range granted {
+		if path == g ||
+			strings.HasPrefix(path.String(), g.String()+"/") ||
+			strings.HasPrefix(g.String(), path.String()+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// grantDefaultAccess shares a new top-level item with the whole company when
+// the company opted in; items further down inherit the access of their folder.
+// It is best effort, an item that misses it stays private to its creator.
+func (uc *UseCaseFileFolder) grantDefaultAccess(ctx context.Context, item *domain.File) {
+	if !item.FullPath.GetParent().IsRoot() {
+		return
+	}
+
+	settings, err := uc.accessRepo.GetSettings(ctx, item.CompanyId)
+	if err != nil || !settings.ShareNewFiles {
+		return
+	}
+
+	_, _ = uc.accessRepo.GrantAccess(ctx, &domain.AccessEntry{
+		ID:        uuid.NewString(),
+		FileID:    item.ID,
+		CompanyID: item.CompanyId,
+		Role:      domain.AccessEditor,
+		GrantedBy: item.UserCreateID,
+		CreatedAt: time.Now(),
+	})
+}
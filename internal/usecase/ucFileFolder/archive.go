@@ -0,0 +1,203 @@
+package ucFileFolder
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+const rootArchiveName = "files"
+
+// PrepareFolderArchive collects the subtree of a folder so it can be streamed
+// with WriteFolderArchive. Nothing is read from storage yet, which lets callers
+// report errors before the response starts. Without access to the folder
+// itself, only the items inside it the user has access to are included.
+func (uc *UseCaseFileFolder) PrepareFolderArchive(ctx context.Context, companyID, userID string, folderPath *domain.Path, format domain.ArchiveFormat) (*domain.FolderArchive, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+	if !format.IsValid() {
+		return nil, errors.BadRequest("archive format must be zip or tar.gz")
+	}
+
+	archive := &domain.FolderArchive{Name: rootArchiveName, Format: format}
+
+	if !folderPath.IsRoot() {
+		folder, err := uc.fileRepo.GetFileByPath(ctx, companyID, folderPath)
+		if err != nil {
+			return nil, errors.NotFound("folder not found")
+		}
+		if folder.Type != domain.FileTypeFolder {
+			return nil, errors.BadRequest("specified path is not a folder")
+		}
+		archive.Name = folder.Name
+	}
+
+	granted, err := uc.grantedPathsIn(ctx, companyID, userID, *folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := uc.fileRepo.GetFolderTree(ctx, companyID, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := folderPath.String()
+	if !folderPath.IsRoot() {
+		prefix += "/"
+	}
+
+	for _, item := range contents {
+		// Nothing outside the folder may end up in the archive under its name.
+		if !strings.HasPrefix(item.FullPath.String(), prefix) {
+			continue
+		}
+		if granted != nil && !isVisibleThrough(item.FullPath, granted) {
+			continue
+		}
+
+		if item.Type == domain.FileTypeFile {
+			// Files that may not be downloaded are left out rather than failing the archive.
+			if item.StoragePath == nil || uc.checkScanStatus(item) != nil {
+				continue
+			}
+			if item.Size != nil {
+				archive.TotalSize += *item.Size
+			}
+		}
+
+		archive.Entries = append(archive.Entries, &domain.ArchiveEntry{
+			Name: archive.Name + "/" + strings.TrimPrefix(item.FullPath.String(), prefix),
+			File: item,
+		})
+	}
+
+	if archive.TotalSize > uc.config.ArchiveMaxSize {
+		return nil, errors.FileTooLarge(fmt.Sprintf("folder exceeds the archive limit of %d bytes", uc.config.ArchiveMaxSize))
+	}
+
+	// Parents sort before their children, so every folder entry precedes its contents.
+	sort.Slice(archive.Entries, func(i, j int) bool {
+		return archive.Entries[i].Name < archive.Entries[j].Name
+	})
+
+	return archive, nil
+}
+
+// WriteFolderArchive streams the archive to w, reading one file at a time from storage.
+func (uc *UseCaseFileFolder) WriteFolderArchive(ctx context.Context, archive *domain.FolderArchive, w io.Writer) error {
+	if archive.Format == domain.ArchiveFormatTarGz {
+		return uc.writeTarGz(ctx, archive, w)
+	}
+	return uc.writeZip(ctx, archive, w)
+}
+
+func (uc *UseCaseFileFolder) writeZip(ctx context.Context, archive *domain.FolderArchive, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	if _, err := zw.CreateHeader(&zip.FileHeader{Name: archive.Name + "/", Method: zip.Store}); err != nil {
+		return err
+	}
+
+	for _, entry := range archive.Entries {
+		header := &zip.FileHeader{
+			Name:     entry.Name,
+			Method:   zip.Deflate,
+			Modified: entry.File.UpdatedAt,
+		}
+		if entry.File.Type == domain.FileTypeFolder {
+			header.Name += "/"
+			header.Method = zip.Store
+		}
+
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if entry.File.Type == domain.FileTypeFolder {
+			continue
+		}
+
+		if err := uc.copyObject(ctx, dst, entry.File); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (uc *UseCaseFileFolder) writeTarGz(ctx context.Context, archive *domain.FolderArchive, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := tw.WriteHeader(&tar.Header{Name: archive.Name + "/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		return err
+	}
+
+	for _, entry := range archive.Entries {
+		header := &tar.Header{
+			Name:    entry.Name,
+			ModTime: entry.File.UpdatedAt,
+		}
+		if entry.File.Type == domain.FileTypeFolder {
+			header.Name += "/"
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0o755
+		} else {
+			header.Typeflag = tar.TypeReg
+			header.Mode = 0o644
+			if entry.File.Size != nil {
+				header.Size = *entry.File.Size
+			}
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if entry.File.Type == domain.FileTypeFolder {
+			continue
+		}
+
+		if err := uc.copyObject(ctx, tw, entry.File); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (uc *UseCaseFileFolder) copyObject(ctx context.Context, dst io.Writer, file *domain.File) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	reader, err := uc.storageRepo.GetFile(ctx, *file.StoragePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	var size int64
+	if file.Size != nil {
+		size = *file.Size
+	}
+
+	// Tar headers carry the size up front, so the content must match it exactly.
+	if _, err := io.CopyN(dst, reader, size); err != nil {
+		return fmt.Errorf("copy %s: %w", file.FullPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package ucFileFolder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+)
+
+func (m *fileRepoMock) GetFolderTree(ctx context.Context, companyID string, folderPath *domain.Path) ([]*domain.File, error) {
+	args := m.Called(ctx, companyID, folderPath)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.File), args.Error(1)
+}
+
+func archiveFile(path domain.Path) *domain.File {
+	storagePath := "companies/company-id/files/" + path.GetName()
+	size := int64(4)
+	return &domain.File{
+		Name: path.GetName(), Type: domain.FileTypeFile, FullPath: path, CompanyId: "company-id",
+		StoragePath: &storagePath, Size: &size, ScanStatus: domain.ScanStatusClean,
+	}
+}
+
+func TestPrepareFolderArchive_KeepsToTheFolder(t *testing.T) {
+	fileRepo := new(fileRepoMock)
+	accessRepo := new(accessRepoMock)
+	uc := NewUseCaseFileFolder(fileRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, accessRepo, nil, nil, nil, &config.FileServer{ArchiveMaxSize: 1 << 20})
+
+	folderPath := domain.Path("/a_b")
+	fileRepo.On("GetFileByPath", mock.Anything, "company-id", &folderPath).Return(&domain.File{
+		Name: "a_b", Type: domain.FileTypeFolder, FullPath: folderPath, CompanyId: "company-id",
+	}, nil)
+	accessRepo.On("GetAccessRole", mock.Anything, "company-id", "user-id", folderPath).Return(domain.AccessViewer, nil)
+	fileRepo.On("GetFolderTree", mock.Anything, "company-id", &folderPath).Return([]*domain.File{
+		archiveFile("/a_b/report.txt"),
+		// A sibling a wildcard match would let through.
+		archiveFile("/aXb/secret.txt"),
+	}, nil)
+
+	archive, err := uc.PrepareFolderArchive(context.Background(), "company-id", "user-id", &folderPath, domain.ArchiveFormatZip)
+
+	require.NoError(t, err)
+	require.Len(t, archive.Entries, 1)
+	assert.Equal(t, "a_b/report.txt", archive.Entries[0].Name)
+	assert.Equal(t, int64(4), archive.TotalSize)
+}
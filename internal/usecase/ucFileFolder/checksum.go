@@ -0,0 +1,90 @@
+package ucFileFolder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+
+	"go-storage/pkg/errors"
+)
+
+// hashingReader computes the SHA-256 of everything read through it, so the
+// digest is ready as soon as the storage backend has consumed the stream.
+type hashingReader struct {
+	reader io.Reader
+	hash   hash.Hash
+}
+
+func newHashingReader(reader io.Reader) *hashingReader {
+	h := sha256.New()
+	return &hashingReader{reader: io.TeeReader(reader, h), hash: h}
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	return r.reader.Read(p)
+}
+
+func (r *hashingReader) Sum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// verifyingReader fails the final read when the streamed content does not match
+// the stored digest, which aborts the download instead of serving corrupted data.
+type verifyingReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+func newVerifyingReader(reader io.ReadCloser, expected *string) io.ReadCloser {
+	if expected == nil || *expected == "" {
+		return reader
+	}
+	return &verifyingReader{ReadCloser: reader, hash: sha256.New(), expected: *expected}
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+
+	if err == io.EOF && hex.EncodeToString(r.hash.Sum(nil)) != r.expected {
+		return n, errors.InternalServer("file integrity check failed")
+	}
+
+	return n, err
+}
+
+// normalizeChecksum validates a client supplied hex encoded SHA-256 digest.
+func normalizeChecksum(checksum string) (string, error) {
+	if checksum == "" {
+		return "", nil
+	}
+
+	checksum = strings.ToLower(checksum)
+	decoded, err := hex.DecodeString(checksum)
+	if err != nil || len(decoded) != sha256.Size {
+		return "", errors.BadRequest("checksum must be a hex encoded SHA-256 digest")
+	}
+
+	return checksum, nil
+}
+
+// hashObject reads an object back from storage and returns its SHA-256. It is
+// used for content assembled by the storage backend, such as multipart uploads.
+func (uc *UseCaseFileFolder) hashObject(ctx context.Context, storageKey string) (string, error) {
+	reader, err := uc.storageRepo.GetFile(ctx, storageKey)
+	if err != nil {
+		return "", errors.InternalServer("failed to read stored file")
+	}
+	defer reader.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", errors.InternalServer("failed to hash stored file")
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,489 @@
+package ucFileFolder
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+const (
+	folderCopyBatchSize   = 50
+	maxCopyRenameAttempts = 100
+)
+
+// CopyFile duplicates a file into parentPath, under name or the original name
+// when name is empty. The object is copied inside storage, the content never
+// passes through the service.
+func (uc *UseCaseFileFolder) CopyFile(ctx context.Context, companyID, userID, fileID string, parentPath *domain.Path, name string, policy domain.ConflictPolicy) (*domain.File, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+	if !policy.IsValid() {
+		return nil, errors.BadRequest("conflict policy must be fail or rename")
+	}
+
+	source, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessViewer)
+	if err != nil {
+		return nil, err
+	}
+	if source.Type != domain.FileTypeFile {
+		return nil, errors.BadRequest("specified ID is not a file")
+	}
+	if source.StoragePath == nil {
+		return nil, errors.InternalServer("file storage path not found")
+	}
+
+	if name == "" {
+		name = source.Name
+	}
+	if !domain.IsValidName(name) {
+		return nil, errors.BadRequest("name must not contain path separators or be a dot entry")
+	}
+
+	if err := uc.requireWriteInto(ctx, companyID, userID, *parentPath); err != nil {
+		return nil, err
+	}
+
+	parentID, err := uc.resolveFolderID(ctx, companyID, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPath, err := uc.resolveCopyTarget(ctx, companyID, parentPath, name, domain.FileTypeFile, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkQuota(ctx, companyID, userID, fileSize(source), 1); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.copyFileTo(ctx, source, userID, targetPath, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.grantDefaultAccess(ctx, created)
+
+	return created, nil
+}
+
+// CopyFolder duplicates a folder tree into parentPath. Trees of up to
+// FolderCopyAsyncThreshold items are copied before returning, larger ones are
+// queued for StartFolderJobWorker and come back pending.
+func (uc *UseCaseFileFolder) CopyFolder(ctx context.Context, companyID, userID string, folderPath, parentPath *domain.Path, name string, policy domain.ConflictPolicy) (*domain.FolderCopyJob, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+	if !policy.IsValid() {
+		return nil, errors.BadRequest("conflict policy must be fail or rename")
+	}
+	if folderPath.IsRoot() {
+		return nil, errors.BadRequest("root folder cannot be copied")
+	}
+	if *parentPath == *folderPath || strings.HasPrefix(parentPath.String(), folderPath.String()+"/") {
+		return nil, errors.BadRequest("folder cannot be copied into itself")
+	}
+
+	folder, err := uc.fileRepo.GetFileByPath(ctx, companyID, folderPath)
+	if err != nil {
+		return nil, errors.NotFound("folder not found")
+	}
+	if folder.Type != domain.FileTypeFolder {
+		return nil, errors.BadRequest("specified path is not a folder")
+	}
+
+	if err := uc.requireAccess(ctx, companyID, userID, *folderPath, domain.AccessViewer, "folder not found"); err != nil {
+		return nil, err
+	}
+	if err := uc.requireWriteInto(ctx, companyID, userID, *parentPath); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = folder.Name
+	}
+	if !domain.IsValidName(name) {
+		return nil, errors.BadRequest("name must not contain path separators or be a dot entry")
+	}
+
+	if _, err := uc.resolveFolderID(ctx, companyID, parentPath); err != nil {
+		return nil, err
+	}
+
+	targetPath, err := uc.resolveCopyTarget(ctx, companyID, parentPath, name, domain.FileTypeFolder, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.fileRepo.CountFolderTree(ctx, companyID, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	treeBytes, treeFiles, err := uc.fileRepo.SumFolderTreeFiles(ctx, companyID, folderPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkQuota(ctx, companyID, userID, treeBytes, treeFiles); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &domain.FolderCopyJob{
+		ID:           uuid.New().String(),
+		CompanyID:    companyID,
+		UserCreateID: userID,
+		SourceID:     folder.ID,
+		SourcePath:   *folderPath,
+		TargetPath:   targetPath,
+		Status:       domain.JobStatusPending,
+		TotalItems:   count,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	async := count > uc.config.FolderCopyAsyncThreshold
+	if !async {
+		job.Status = domain.JobStatusRunning
+	}
+
+	if _, err := uc.copyJobRepo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if async {
+		uc.wakeFolderJobWorker()
+		return job, nil
+	}
+
+	// The client going away must not leave a partial copy behind.
+	if err := uc.runFolderCopyJob(context.WithoutCancel(ctx), job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (uc *UseCaseFileFolder) GetFolderCopyJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderCopyJob, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.getFolderCopyJob(ctx, companyID, userID, jobID)
+}
+
+// CancelFolderCopyJob stops a job. A pending job never creates anything; a
+// running one stops after the current batch and moves the partial copy to the trash.
+func (uc *UseCaseFileFolder) CancelFolderCopyJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderCopyJob, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	job, err := uc.getFolderCopyJob(ctx, companyID, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return nil, errors.Conflict("folder copy job is already finished")
+	}
+
+	if err := uc.copyJobRepo.CancelJob(ctx, companyID, jobID); err != nil {
+		return nil, err
+	}
+
+	return uc.copyJobRepo.GetJob(ctx, companyID, jobID)
+}
+
+// ProcessFolderCopyJobs runs queued jobs until none are left and returns how many were processed.
+func (uc *UseCaseFileFolder) ProcessFolderCopyJobs(ctx context.Context, log logger.Logger) int {
+	processed := 0
+
+	for {
+		jobs, err := uc.copyJobRepo.ClaimJobs(ctx, time.Now().Add(-folderJobStaleAfter), folderJobClaimLimit)
+		if err != nil {
+			log.Error("func ProcessFolderCopyJobs: failed to claim jobs", "func", "ProcessFolderCopyJobs", "err", err)
+			return processed
+		}
+		if len(jobs) == 0 {
+			return processed
+		}
+
+		for _, job := range jobs {
+			if err := uc.runFolderCopyJob(ctx, job); err != nil {
+				log.Error("func ProcessFolderCopyJobs: folder copy job failed", "func", "ProcessFolderCopyJobs", "job", job.ID, "err", err)
+			}
+			processed++
+		}
+	}
+}
+
+// runFolderCopyJob copies the tree parents first. A job claimed again after a
+// restart moves its partial copy to the trash and starts over.
+func (uc *UseCaseFileFolder) runFolderCopyJob(ctx context.Context, job *domain.FolderCopyJob) error {
+	if job.TargetID != nil {
+		uc.trashPartialCopy(ctx, job)
+		job.TargetID = nil
+		job.CopiedItems = 0
+	}
+
+	// The folder could have been replaced while the job was queued.
+	source, err := uc.fileRepo.GetFileByPath(ctx, job.CompanyID, &job.SourcePath)
+	if err != nil || source.ID != job.SourceID {
+		return uc.finishFolderCopyJob(ctx, job, domain.JobStatusFailed,
+			errors.Conflict("folder was moved or deleted before the job started"))
+	}
+
+	contents, err := uc.fileRepo.GetFolderTree(ctx, job.CompanyID, &job.SourcePath)
+	if err != nil {
+		return uc.finishFolderCopyJob(ctx, job, domain.JobStatusFailed, err)
+	}
+
+	// A parent path is a prefix of its children, sorting puts it first.
+	sort.Slice(contents, func(i, j int) bool {
+		return contents[i].FullPath < contents[j].FullPath
+	})
+	job.TotalItems = len(contents) + 1
+
+	targetParent := job.TargetPath.GetParent()
+	parentID, err := uc.resolveFolderID(ctx, job.CompanyID, &targetParent)
+	if err != nil {
+		return uc.finishFolderCopyJob(ctx, job, domain.JobStatusFailed, err)
+	}
+
+	root, err := uc.fileRepo.CreateFolder(ctx, newFolderCopy(source, job.UserCreateID, job.TargetPath, parentID))
+	if err != nil {
+		return uc.finishFolderCopyJob(ctx, job, domain.JobStatusFailed, err)
+	}
+
+	job.TargetID = &root.ID
+	job.CopiedItems = 1
+	uc.grantDefaultAccess(ctx, root)
+
+	if err := uc.metadataRepo.CopyMetadata(ctx, job.CompanyID, source.ID, root.ID); err != nil {
+		return uc.failFolderCopyJob(ctx, job, err)
+	}
+
+	cancelled, err := uc.saveFolderCopyProgress(ctx, job)
+	if err != nil {
+		return err
+	}
+	if cancelled {
+		uc.trashPartialCopy(ctx, job)
+		return nil
+	}
+
+	folderIDs := map[domain.Path]string{job.TargetPath: root.ID}
+	for _, item := range contents {
+		targetPath := domain.Path(job.TargetPath.String() + strings.TrimPrefix(item.FullPath.String(), job.SourcePath.String()))
+
+		parentID, ok := folderIDs[targetPath.GetParent()]
+		if !ok {
+			return uc.failFolderCopyJob(ctx, job, errors.InternalServer(fmt.Sprintf("parent of %s was not copied", item.FullPath)))
+		}
+
+		if item.Type == domain.FileTypeFolder {
+			created, err := uc.fileRepo.CreateFolder(ctx, newFolderCopy(item, job.UserCreateID, targetPath, &parentID))
+			if err != nil {
+				return uc.failFolderCopyJob(ctx, job, err)
+			}
+			if err := uc.metadataRepo.CopyMetadata(ctx, job.CompanyID, item.ID, created.ID); err != nil {
+				return uc.failFolderCopyJob(ctx, job, err)
+			}
+			folderIDs[targetPath] = created.ID
+		} else if item.StoragePath != nil {
+			if _, err := uc.copyFileTo(ctx, item, job.UserCreateID, targetPath, &parentID); err != nil {
+				return uc.failFolderCopyJob(ctx, job, err)
+			}
+		}
+
+		job.CopiedItems++
+		if job.CopiedItems%folderCopyBatchSize != 0 {
+			continue
+		}
+
+		cancelled, err := uc.saveFolderCopyProgress(ctx, job)
+		if err != nil {
+			return err
+		}
+		if cancelled {
+			uc.trashPartialCopy(ctx, job)
+			return nil
+		}
+	}
+
+	return uc.finishFolderCopyJob(ctx, job, domain.JobStatusCompleted, nil)
+}
+
+// getFolderCopyJob returns a job started by the user. Jobs of other users are not found.
+func (uc *UseCaseFileFolder) getFolderCopyJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderCopyJob, error) {
+	job, err := uc.copyJobRepo.GetJob(ctx, companyID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.UserCreateID != userID {
+		return nil, errors.NotFound("folder copy job not found")
+	}
+
+	return job, nil
+}
+
+// copyFileTo copies the object of source to a new key and creates a file row
+// for it. The object is removed again when the row cannot be created.
+func (uc *UseCaseFileFolder) copyFileTo(ctx context.Context, source *domain.File, userID string, targetPath domain.Path, parentID *string) (*domain.File, error) {
+	now := time.Now()
+
+	file := *source
+	file.ID = uuid.NewString()
+	file.Name = targetPath.GetName()
+	file.FullPath = targetPath
+	file.ParentID = parentID
+	file.UserCreateID = userID
+	file.CreatedAt = now
+	file.UpdatedAt = now
+	file.IsActive = true
+
+	// The copy keeps the scan status of the source, a copy of quarantined
+	// content stays in quarantine.
+	storageKey := generateStorageKey(file.CompanyId, file.ID, file.Name)
+	if domain.IsQuarantineKey(*source.StoragePath) {
+		storageKey = domain.QuarantineKey(storageKey)
+	}
+	if err := uc.storageRepo.CopyFile(ctx, *source.StoragePath, storageKey); err != nil {
+		return nil, err
+	}
+	file.StoragePath = &storageKey
+
+	created, err := uc.fileRepo.CreateFile(ctx, &file)
+	if err != nil {
+		_ = uc.storageRepo.DeleteFile(ctx, storageKey)
+		return nil, err
+	}
+
+	// Without its tags the copy is incomplete, it goes to the trash like a failed folder copy.
+	if err := uc.metadataRepo.CopyMetadata(ctx, created.CompanyId, source.ID, created.ID); err != nil {
+		_ = uc.fileRepo.DeleteFile(ctx, created.CompanyId, created.ID)
+		return nil, err
+	}
+
+	_, _ = uc.versionRepo.CreateVersion(ctx, domain.NewFileVersion(created, userID))
+	uc.queueScan(ctx, created)
+
+	return created, nil
+}
+
+// resolveFolderID returns the ID of the folder at path, nil for the root.
+func (uc *UseCaseFileFolder) resolveFolderID(ctx context.Context, companyID string, path *domain.Path) (*string, error) {
+	if path.IsRoot() {
+		return nil, nil
+	}
+
+	folder, err := uc.fileRepo.GetFileByPath(ctx, companyID, path)
+	if err != nil {
+		return nil, errors.NotFound("destination folder not found")
+	}
+	if folder.Type != domain.FileTypeFolder {
+		return nil, errors.BadRequest("destination is not a folder")
+	}
+
+	return &folder.ID, nil
+}
+
+// resolveCopyTarget returns the path the copy is created at. With the rename
+// policy a taken name gets a numbered suffix, like items restored from the trash.
+func (uc *UseCaseFileFolder) resolveCopyTarget(ctx context.Context, companyID string, parentPath *domain.Path, name string, fileType domain.FileType, policy domain.ConflictPolicy) (domain.Path, error) {
+	ext := ""
+	if fileType == domain.FileTypeFile {
+		ext = filepath.Ext(name)
+	}
+	base := strings.TrimSuffix(name, ext)
+
+	candidate := name
+	for i := 1; i <= maxCopyRenameAttempts; i++ {
+		path := parentPath.Join(candidate)
+		_, err := uc.fileRepo.GetFileByPath(ctx, companyID, &path)
+		if err != nil {
+			if stdErrors.Is(err, errors.ErrNotFound) {
+				return path, nil
+			}
+			return "", err
+		}
+		if policy == domain.ConflictPolicyFail {
+			return "", errors.FileExists("an item with this name already exists at the destination")
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+
+	return "", errors.Conflict("unable to find a free name for the copy")
+}
+
+func (uc *UseCaseFileFolder) saveFolderCopyProgress(ctx context.Context, job *domain.FolderCopyJob) (bool, error) {
+	job.UpdatedAt = time.Now()
+
+	status, err := uc.copyJobRepo.UpdateJobProgress(ctx, job)
+	if err != nil {
+		return false, err
+	}
+	if status == domain.JobStatusCancelled {
+		job.Status = status
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// failFolderCopyJob moves the partial copy to the trash and marks the job failed.
+func (uc *UseCaseFileFolder) failFolderCopyJob(ctx context.Context, job *domain.FolderCopyJob, cause error) error {
+	uc.trashPartialCopy(ctx, job)
+	return uc.finishFolderCopyJob(ctx, job, domain.JobStatusFailed, cause)
+}
+
+func (uc *UseCaseFileFolder) finishFolderCopyJob(ctx context.Context, job *domain.FolderCopyJob, status domain.JobStatus, cause error) error {
+	job.Finish(status, cause)
+
+	if err := uc.copyJobRepo.FinishJob(ctx, job); err != nil {
+		return err
+	}
+
+	return cause
+}
+
+func (uc *UseCaseFileFolder) trashPartialCopy(ctx context.Context, job *domain.FolderCopyJob) {
+	if job.TargetID == nil {
+		return
+	}
+
+	folder, err := uc.fileRepo.GetFileByPath(ctx, job.CompanyID, &job.TargetPath)
+	if err != nil || folder.ID != *job.TargetID {
+		return
+	}
+
+	_, _ = uc.fileRepo.DeleteFolderTree(ctx, job.CompanyID, &job.TargetPath, time.Now().Truncate(time.Microsecond))
+}
+
+func newFolderCopy(source *domain.File, userID string, path domain.Path, parentID *string) *domain.File {
+	now := time.Now()
+
+	return &domain.File{
+		ID:           uuid.NewString(),
+		Name:         path.GetName(),
+		Type:         domain.FileTypeFolder,
+		FullPath:     path,
+		ParentID:     parentID,
+		CompanyId:    source.CompanyId,
+		UserCreateID: userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		IsActive:     true,
+	}
+}
@@ -0,0 +1,52 @@
+package ucFileFolder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+func (m *fileRepoMock) GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func TestCopy_RejectsNamesLeavingTheFolder(t *testing.T) {
+	fileRepo := new(fileRepoMock)
+	accessRepo := new(accessRepoMock)
+	uc := NewUseCaseFileFolder(fileRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, accessRepo, nil, nil, nil, &config.FileServer{})
+
+	storagePath := "companies/company-id/files/file-id/report.txt"
+	fileRepo.On("GetFile", mock.Anything, "company-id", "file-id").Return(&domain.File{
+		ID: "file-id", Name: "report.txt", Type: domain.FileTypeFile, FullPath: "/src/report.txt",
+		CompanyId: "company-id", StoragePath: &storagePath,
+	}, nil)
+	fileRepo.On("GetFileByPath", mock.Anything, "company-id", mock.Anything).Return(&domain.File{
+		ID: "folder-id", Name: "src", Type: domain.FileTypeFolder, FullPath: "/src", CompanyId: "company-id",
+	}, nil)
+	accessRepo.On("GetAccessRole", mock.Anything, "company-id", "user-id", mock.Anything).Return(domain.AccessEditor, nil)
+
+	source := domain.Path("/src")
+	parent := domain.Path("/mine")
+
+	for _, name := range []string{"../secret/x", "sub/x", `sub\x`, ".."} {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.CopyFile(context.Background(), "company-id", "user-id", "file-id", &parent, name, domain.ConflictPolicyFail)
+			assert.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+			_, err = uc.CopyFolder(context.Background(), "company-id", "user-id", &source, &parent, name, domain.ConflictPolicyFail)
+			assert.ErrorIs(t, err, errors.ErrInvalidRequest)
+		})
+	}
+
+	// Only the source and the destination folder were checked, nothing was looked up at the target.
+	accessRepo.AssertNotCalled(t, "GetAccessRole", mock.Anything, mock.Anything, mock.Anything, domain.Path("/secret"))
+}
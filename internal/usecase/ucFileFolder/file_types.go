@@ -0,0 +1,61 @@
+package ucFileFolder
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+	"go-storage/pkg/mimetype"
+)
+
+// resolveFileType settles on the MIME type of an upload from its name, the
+// type declared by the client and its leading bytes, and checks it against the
+// company's file type policy. head is nil when the content is not there yet.
+// Rejected uploads are logged.
+func (uc *UseCaseFileFolder) resolveFileType(ctx context.Context, companyID, userID, filename, declared string, head []byte) (string, error) {
+	mimeType := mimetype.Resolve(filename, declared, head)
+
+	policy, err := uc.policyRepo.GetPolicy(ctx, companyID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := policy.Check(filename, mimeType, mimetype.Normalize(declared)); err != nil {
+		logger.FromContext(ctx).Warn("func resolveFileType: Upload rejected by file type policy", "func", "resolveFileType",
+			"company_id", companyID, "user_id", userID, "filename", filename,
+			"mime_type", mimeType, "declared_type", declared, "err", err.Error())
+		return "", errors.InvalidFileType(err.Error())
+	}
+
+	return mimeType, nil
+}
+
+// peekHead returns the leading bytes of an upload stream together with a
+// reader that still yields the whole content.
+func peekHead(reader io.Reader) ([]byte, io.Reader) {
+	buffered := bufio.NewReaderSize(reader, mimetype.SniffLen)
+	head, _ := buffered.Peek(mimetype.SniffLen)
+	return head, buffered
+}
+
+// objectHead reads the leading bytes of an object already in storage.
+func (uc *UseCaseFileFolder) objectHead(ctx context.Context, storageKey string, size int64) ([]byte, error) {
+	if size <= 0 {
+		return []byte{}, nil
+	}
+
+	reader, err := uc.storageRepo.GetFileRange(ctx, storageKey, 0, min(size, mimetype.SniffLen)-1)
+	if err != nil {
+		return nil, errors.InternalServer("failed to read file from storage")
+	}
+	defer reader.Close()
+
+	head, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.InternalServer("failed to read file from storage")
+	}
+
+	return head, nil
+}
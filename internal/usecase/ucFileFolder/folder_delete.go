@@ -0,0 +1,267 @@
+package ucFileFolder
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+const folderDeleteBatchSize = 100
+
+// DeleteFolderRecursive moves a folder with its whole subtree to the trash.
+// Trees of up to FolderDeleteAsyncThreshold items are handled before returning,
+// larger ones are queued for StartFolderJobWorker and come back pending.
+// A permanent job then purges the items and their storage objects right away
+// instead of leaving them to the trash retention.
+func (uc *UseCaseFileFolder) DeleteFolderRecursive(ctx context.Context, companyID, userID string, folderPath *domain.Path, permanent bool) (*domain.FolderDeleteJob, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+	if folderPath.IsRoot() {
+		return nil, errors.BadRequest("root folder cannot be deleted")
+	}
+
+	folder, err := uc.fileRepo.GetFileByPath(ctx, companyID, folderPath)
+	if err != nil {
+		return nil, errors.NotFound("folder not found")
+	}
+	if folder.Type != domain.FileTypeFolder {
+		return nil, errors.BadRequest("specified path is not a folder")
+	}
+
+	if err := uc.requireAccess(ctx, companyID, userID, *folderPath, domain.AccessEditor, "folder not found"); err != nil {
+		return nil, err
+	}
+
+	count, err := uc.fileRepo.CountFolderTree(ctx, companyID, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &domain.FolderDeleteJob{
+		ID:           uuid.New().String(),
+		CompanyID:    companyID,
+		UserCreateID: userID,
+		FolderID:     folder.ID,
+		FolderPath:   *folderPath,
+		Permanent:    permanent,
+		Status:       domain.JobStatusPending,
+		TotalItems:   count,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	async := count > uc.config.FolderDeleteAsyncThreshold
+	if !async {
+		job.Status = domain.JobStatusRunning
+	}
+
+	if _, err := uc.deleteJobRepo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if async {
+		uc.wakeFolderJobWorker()
+		return job, nil
+	}
+
+	// The client going away must not leave the tree half purged.
+	if err := uc.runFolderDeleteJob(context.WithoutCancel(ctx), job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (uc *UseCaseFileFolder) GetFolderDeleteJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderDeleteJob, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.getFolderDeleteJob(ctx, companyID, userID, jobID)
+}
+
+// CancelFolderDeleteJob stops a job. A pending job never touches the folder;
+// a running one stops purging after the current batch and leaves the rest in the trash.
+func (uc *UseCaseFileFolder) CancelFolderDeleteJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderDeleteJob, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	job, err := uc.getFolderDeleteJob(ctx, companyID, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return nil, errors.Conflict("folder delete job is already finished")
+	}
+
+	if err := uc.deleteJobRepo.CancelJob(ctx, companyID, jobID); err != nil {
+		return nil, err
+	}
+
+	return uc.deleteJobRepo.GetJob(ctx, companyID, jobID)
+}
+
+// getFolderDeleteJob returns a job started by the user. Jobs of other users are not found.
+func (uc *UseCaseFileFolder) getFolderDeleteJob(ctx context.Context, companyID, userID, jobID string) (*domain.FolderDeleteJob, error) {
+	job, err := uc.deleteJobRepo.GetJob(ctx, companyID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.UserCreateID != userID {
+		return nil, errors.NotFound("folder delete job not found")
+	}
+
+	return job, nil
+}
+
+// ProcessFolderDeleteJobs runs queued jobs until none are left and returns how many were processed.
+func (uc *UseCaseFileFolder) ProcessFolderDeleteJobs(ctx context.Context, log logger.Logger) int {
+	processed := 0
+
+	for {
+		jobs, err := uc.deleteJobRepo.ClaimJobs(ctx, time.Now().Add(-folderJobStaleAfter), folderJobClaimLimit)
+		if err != nil {
+			log.Error("func ProcessFolderDeleteJobs: failed to claim jobs", "func", "ProcessFolderDeleteJobs", "err", err)
+			return processed
+		}
+		if len(jobs) == 0 {
+			return processed
+		}
+
+		for _, job := range jobs {
+			if err := uc.runFolderDeleteJob(ctx, job); err != nil {
+				log.Error("func ProcessFolderDeleteJobs: folder delete job failed", "func", "ProcessFolderDeleteJobs", "job", job.ID, "err", err)
+			}
+			processed++
+		}
+	}
+}
+
+// runFolderDeleteJob picks up where the job stopped, so a job claimed again
+// after a restart does not delete the tree twice.
+func (uc *UseCaseFileFolder) runFolderDeleteJob(ctx context.Context, job *domain.FolderDeleteJob) error {
+	if job.DeletedAt == nil {
+		// The folder could have been replaced while the job was queued.
+		folder, err := uc.fileRepo.GetFileByPath(ctx, job.CompanyID, &job.FolderPath)
+		if err != nil || folder.ID != job.FolderID {
+			return uc.finishFolderDeleteJob(ctx, job, domain.JobStatusFailed,
+				errors.Conflict("folder was moved or deleted before the job started"))
+		}
+
+		// Truncated to the database precision, later queries match the subtree on it.
+		deletedAt := time.Now().Truncate(time.Microsecond)
+		deleted, err := uc.fileRepo.DeleteFolderTree(ctx, job.CompanyID, &job.FolderPath, deletedAt)
+		if err != nil {
+			return uc.finishFolderDeleteJob(ctx, job, domain.JobStatusFailed, err)
+		}
+
+		job.TotalItems = deleted
+		job.DeletedItems = deleted
+		job.DeletedAt = &deletedAt
+
+		cancelled, err := uc.saveFolderDeleteProgress(ctx, job)
+		if err != nil || cancelled {
+			return err
+		}
+	}
+
+	if job.Permanent {
+		cancelled, err := uc.purgeFolderTree(ctx, job)
+		if err != nil {
+			return uc.finishFolderDeleteJob(ctx, job, domain.JobStatusFailed, err)
+		}
+		if cancelled {
+			return nil
+		}
+	}
+
+	return uc.finishFolderDeleteJob(ctx, job, domain.JobStatusCompleted, nil)
+}
+
+func (uc *UseCaseFileFolder) purgeFolderTree(ctx context.Context, job *domain.FolderDeleteJob) (bool, error) {
+	for {
+		files, err := uc.fileRepo.GetDeletedFolderTreeFiles(ctx, job.CompanyID, &job.FolderPath, *job.DeletedAt, folderDeleteBatchSize)
+		if err != nil {
+			return false, err
+		}
+
+		for _, file := range files {
+			paths, err := uc.trashRepo.PurgeItem(ctx, job.CompanyID, file.ID)
+			if err != nil {
+				// Restored from the trash in the meantime.
+				if stdErrors.Is(err, errors.ErrNotFound) {
+					continue
+				}
+				return false, err
+			}
+			uc.deleteUnreferencedObjects(ctx, paths)
+			job.PurgedItems++
+		}
+
+		cancelled, err := uc.saveFolderDeleteProgress(ctx, job)
+		if err != nil || cancelled {
+			return cancelled, err
+		}
+
+		if len(files) < folderDeleteBatchSize {
+			break
+		}
+	}
+
+	// Only folders are left, removing the top one removes them all.
+	paths, err := uc.trashRepo.PurgeItem(ctx, job.CompanyID, job.FolderID)
+	if err != nil && !stdErrors.Is(err, errors.ErrNotFound) {
+		return false, err
+	}
+	uc.deleteUnreferencedObjects(ctx, paths)
+	job.PurgedItems = job.DeletedItems
+
+	return false, nil
+}
+
+// saveFolderDeleteProgress stores the job counters and reports whether the job was cancelled.
+func (uc *UseCaseFileFolder) saveFolderDeleteProgress(ctx context.Context, job *domain.FolderDeleteJob) (bool, error) {
+	job.UpdatedAt = time.Now()
+
+	status, err := uc.deleteJobRepo.UpdateJobProgress(ctx, job)
+	if err != nil {
+		return false, err
+	}
+	if status == domain.JobStatusCancelled {
+		job.Status = status
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (uc *UseCaseFileFolder) finishFolderDeleteJob(ctx context.Context, job *domain.FolderDeleteJob, status domain.JobStatus, cause error) error {
+	job.Finish(status, cause)
+
+	if err := uc.deleteJobRepo.FinishJob(ctx, job); err != nil {
+		return err
+	}
+
+	return cause
+}
+
+// deleteUnreferencedObjects removes storage objects no file or version row points at anymore.
+func (uc *UseCaseFileFolder) deleteUnreferencedObjects(ctx context.Context, paths []string) {
+	for _, path := range paths {
+		refs, err := uc.versionRepo.CountStoragePathRefs(ctx, path)
+		if err != nil || refs > 0 {
+			continue
+		}
+
+		_ = uc.storageRepo.DeleteFile(ctx, path)
+	}
+}
@@ -0,0 +1,47 @@
+package ucFileFolder
+
+import (
+	"context"
+	"time"
+
+	"go-storage/pkg/logger"
+)
+
+const (
+	folderJobClaimLimit = 5
+	folderJobStaleAfter = 10 * time.Minute
+)
+
+// StartFolderJobWorker processes queued folder delete and copy jobs every
+// FolderJobWorkerInterval and whenever a new job is queued, until ctx is done.
+func (uc *UseCaseFileFolder) StartFolderJobWorker(ctx context.Context, log logger.Logger) {
+	if uc.config.FolderJobWorkerInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.config.FolderJobWorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uc.folderJobWake:
+		}
+
+		if processed := uc.ProcessFolderDeleteJobs(ctx, log); processed > 0 {
+			log.Info("processed folder delete jobs", "count", processed)
+		}
+		if processed := uc.ProcessFolderCopyJobs(ctx, log); processed > 0 {
+			log.Info("processed folder copy jobs", "count", processed)
+		}
+	}
+}
+
+func (uc *UseCaseFileFolder) wakeFolderJobWorker() {
+	select {
+	case uc.folderJobWake <- struct{}{}:
+	default:
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"go-storage/internal/domain"
 	"io"
+	"net/http"
+	"time"
 )
 
 type RepositoryFileFolder interface {
@@ -11,8 +13,11 @@ type RepositoryFileFolder interface {
 	CreateFile(ctx context.Context, file *domain.File) (*domain.File, error)
 	GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
 	GetFolderContents(ctx context.Context, companyID string, path *domain.Path, fileType *domain.FileType) ([]*domain.File, error)
+	GetFolderTree(ctx context.Context, companyID string, folderPath *domain.Path) ([]*domain.File, error)
+	ListFolder(ctx context.Context, companyID string, parentID *string, query *domain.FolderListQuery) ([]*domain.File, error)
 	UpdateFile(ctx context.Context, file *domain.File) (*domain.File, error)
 	DeleteFile(ctx context.Context, companyID, fileID string) error
+	TouchFile(ctx context.Context, companyID, fileID string, accessedAt time.Time) error
 
 	// File path operations
 	GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error)
@@ -24,12 +29,25 @@ type RepositoryFileFolder interface {
 	GetFolder(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error)
 	MoveFolder(ctx context.Context, companyID string, oldPath, newPath *domain.Path) (*domain.Path, error)
 	DeleteFolder(ctx context.Context, companyID string, path *domain.Path) error
+	CountFolderTree(ctx context.Context, companyID string, folderPath *domain.Path) (int, error)
+	SumFolderTreeFiles(ctx context.Context, companyID string, folderPath *domain.Path) (int64, int64, error)
+	DeleteFolderTree(ctx context.Context, companyID string, folderPath *domain.Path, deletedAt time.Time) (int, error)
+	GetDeletedFolderTreeFiles(ctx context.Context, companyID string, folderPath *domain.Path, deletedAt time.Time, limit int) ([]*domain.File, error)
+
+	// Malware scanning
+	ClaimScans(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.File, error)
+	SaveScanResult(ctx context.Context, file *domain.File, result *domain.ScanResult) (bool, error)
+	MoveScannedObject(ctx context.Context, file *domain.File, newPath string, result *domain.ScanResult) (bool, error)
+	GetScanFindings(ctx context.Context, companyID string, status domain.ScanStatus, limit int) ([]*domain.ScanFinding, error)
+	RequeueScan(ctx context.Context, companyID, fileID string) error
 }
 
 type StorageRepository interface {
 	// File storage operations
 	StoreFile(ctx context.Context, key string, reader io.Reader, size int64, mimeType string) (string, error)
 	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
+	GetFileRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error)
+	CopyFile(ctx context.Context, srcKey, dstKey string) error
 	DeleteFile(ctx context.Context, key string) error
 
 	// Chunked upload operations
@@ -37,9 +55,15 @@ type StorageRepository interface {
 	UploadChunk(ctx context.Context, uploadID, key string, chunkIndex int, reader io.Reader, size int64) (string, error)
 	CompleteChunkedUpload(ctx context.Context, uploadID, key string, parts []string) error
 	AbortChunkedUpload(ctx context.Context, uploadID, key string) error
+	AbortStaleChunkedUploads(ctx context.Context, olderThan time.Time) (int, error)
 
 	// File info operations
 	GetFileInfo(ctx context.Context, key string) (*domain.StorageFileInfo, error)
+	ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error)
+
+	// Presigned URL operations
+	GetPresignedURL(ctx context.Context, key string, expiry time.Duration, filename string) (string, error)
+	GetPresignedUploadURL(ctx context.Context, key string, expiry time.Duration, headers http.Header) (string, error)
 }
 
 type ChunkedUploadRepository interface {
@@ -50,9 +74,85 @@ type ChunkedUploadRepository interface {
 	DeleteChunkedUpload(ctx context.Context, companyID, uploadID string) error
 
 	// Chunk tracking
-	AddChunk(ctx context.Context, uploadID string, chunkIndex int, etag string, size int64) error
+	AddChunk(ctx context.Context, uploadID string, chunk *domain.ChunkInfo) error
 	GetUploadProgress(ctx context.Context, uploadID string) (*domain.ChunkedUpload, error)
 
 	// Cleanup operations
-	CleanupExpiredUploads(ctx context.Context) error
+	GetExpiredUploads(ctx context.Context, limit int) ([]*domain.ChunkedUpload, error)
+	CleanupExpiredUploads(ctx context.Context) (int64, error)
+	GetUnsweptUploads(ctx context.Context, limit int) ([]*domain.ChunkedUpload, error)
+	MarkChunksSwept(ctx context.Context, uploadID string) error
+}
+
+type FileVersionRepository interface {
+	CreateVersion(ctx context.Context, version *domain.FileVersion) (*domain.FileVersion, error)
+	PromoteVersion(ctx context.Context, file *domain.File, version *domain.FileVersion) (*domain.File, error)
+	GetVersions(ctx context.Context, companyID, fileID string) ([]*domain.FileVersion, error)
+	GetVersion(ctx context.Context, companyID, fileID string, version int) (*domain.FileVersion, error)
+	DeleteVersion(ctx context.Context, companyID, versionID string) error
+	CountStoragePathRefs(ctx context.Context, storagePath string) (int, error)
+}
+
+type PresignedUploadRepository interface {
+	CreatePresignedUpload(ctx context.Context, upload *domain.PresignedUpload) (*domain.PresignedUpload, error)
+	GetPresignedUpload(ctx context.Context, companyID, uploadID string) (*domain.PresignedUpload, error)
+	DeletePresignedUpload(ctx context.Context, companyID, uploadID string) error
+}
+
+type FolderDeleteJobRepository interface {
+	CreateJob(ctx context.Context, job *domain.FolderDeleteJob) (*domain.FolderDeleteJob, error)
+	GetJob(ctx context.Context, companyID, jobID string) (*domain.FolderDeleteJob, error)
+	ClaimJobs(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.FolderDeleteJob, error)
+	UpdateJobProgress(ctx context.Context, job *domain.FolderDeleteJob) (domain.JobStatus, error)
+	FinishJob(ctx context.Context, job *domain.FolderDeleteJob) error
+	CancelJob(ctx context.Context, companyID, jobID string) error
+}
+
+type FolderCopyJobRepository interface {
+	CreateJob(ctx context.Context, job *domain.FolderCopyJob) (*domain.FolderCopyJob, error)
+	GetJob(ctx context.Context, companyID, jobID string) (*domain.FolderCopyJob, error)
+	ClaimJobs(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.FolderCopyJob, error)
+	UpdateJobProgress(ctx context.Context, job *domain.FolderCopyJob) (domain.JobStatus, error)
+	FinishJob(ctx context.Context, job *domain.FolderCopyJob) error
+	CancelJob(ctx context.Context, companyID, jobID string) error
+}
+
+type TrashRepository interface {
+	PurgeItem(ctx context.Context, companyID, itemID string) ([]string, error)
+}
+
+type ContentIndexRepository interface {
+	EnqueueFile(ctx context.Context, companyID, fileID string) error
+}
+
+type FileMetadataRepository interface {
+	CopyMetadata(ctx context.Context, companyID, sourceID, targetID string) error
+}
+
+type QuotaRepository interface {
+	GetCompanyUsage(ctx context.Context, companyID string) (*domain.StorageUsage, error)
+	GetUserUsage(ctx context.Context, companyID, userID string) (*domain.StorageUsage, error)
+}
+
+type AccessRepository interface {
+	GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error)
+	HasAccessBelow(ctx context.Context, companyID, userID string, path domain.Path) (bool, error)
+	ListGrantedPaths(ctx context.Context, companyID, userID string, path domain.Path) ([]domain.Path, error)
+	GrantAccess(ctx context.Context, entry *domain.AccessEntry) (*domain.AccessEntry, error)
+	GetSettings(ctx context.Context, companyID string) (*domain.SharingSettings, error)
+}
+
+type RenditionRepository interface {
+	GetRendition(ctx context.Context, companyID, fileID string, size domain.RenditionSize) (*domain.FileRendition, error)
+	SaveRendition(ctx context.Context, rendition *domain.FileRendition) error
+}
+
+type FileTypePolicyRepository interface {
+	GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error)
+}
+
+// Scanner checks content for malware and returns the name of what it found,
+// or an empty string for clean content.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (string, error)
 }
@@ -0,0 +1,180 @@
+package ucFileFolder
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/logger"
+)
+
+const janitorBatchSize = 100
+
+// CleanupChunkedUploads expires chunked sessions past their expiry, aborts
+// their multipart uploads in storage, removes abandoned multipart uploads and
+// leftover chunk objects, and deletes finished sessions.
+func (uc *UseCaseFileFolder) CleanupChunkedUploads(ctx context.Context) domain.JanitorRun {
+	run := domain.JanitorRun{StartedAt: time.Now()}
+
+	for {
+		uploads, err := uc.chunkedRepo.GetExpiredUploads(ctx, janitorBatchSize)
+		if err != nil {
+			run.Errors++
+			break
+		}
+
+		expired := 0
+		for _, upload := range uploads {
+			if upload.StorageUploadID != "" {
+				if err := uc.storageRepo.AbortChunkedUpload(ctx, upload.StorageUploadID, upload.StorageKey); err != nil {
+					run.Errors++
+					continue
+				}
+				run.AbortedUploads++
+			}
+
+			upload.Status = domain.ChunkedUploadStatusExpired
+			if _, err := uc.chunkedRepo.UpdateChunkedUpload(ctx, upload); err != nil {
+				run.Errors++
+				continue
+			}
+			expired++
+		}
+		run.ExpiredSessions += expired
+
+		if len(uploads) < janitorBatchSize || expired == 0 {
+			break
+		}
+	}
+
+	// Multipart uploads older than any session can live have lost their session.
+	aborted, err := uc.storageRepo.AbortStaleChunkedUploads(ctx, time.Now().Add(-uc.config.ChunkedSessionTTL))
+	run.AbortedUploads += aborted
+	if err != nil {
+		run.Errors++
+	}
+
+	uc.sweepChunkObjects(ctx, &run)
+
+	deleted, err := uc.chunkedRepo.CleanupExpiredUploads(ctx)
+	run.DeletedSessions = deleted
+	if err != nil {
+		run.Errors++
+	}
+
+	run.Duration = time.Since(run.StartedAt)
+
+	uc.janitorMu.Lock()
+	uc.janitorStats.Record(run)
+	uc.janitorMu.Unlock()
+
+	return run
+}
+
+// sweepChunkObjects removes the key.chunk.N objects of sessions started before
+// chunked uploads were backed by multipart uploads. Sessions are marked once
+// swept, so the sweep ends when the last of them is done.
+func (uc *UseCaseFileFolder) sweepChunkObjects(ctx context.Context, run *domain.JanitorRun) {
+	for {
+		uploads, err := uc.chunkedRepo.GetUnsweptUploads(ctx, janitorBatchSize)
+		if err != nil {
+			run.Errors++
+			return
+		}
+
+		swept := 0
+		for _, upload := range uploads {
+			deleted, err := uc.deleteChunkObjects(ctx, upload)
+			run.DeletedChunkObjects += deleted
+			if err != nil {
+				run.Errors++
+				continue
+			}
+
+			if err := uc.chunkedRepo.MarkChunksSwept(ctx, upload.ID); err != nil {
+				run.Errors++
+				continue
+			}
+			swept++
+		}
+
+		if len(uploads) < janitorBatchSize || swept == 0 {
+			return
+		}
+	}
+}
+
+// deleteChunkObjects removes the chunk objects of a single session. Only keys
+// of the form key.chunk.N next to the session's own key are considered, and
+// objects still referenced by a file, version or thumbnail are kept.
+func (uc *UseCaseFileFolder) deleteChunkObjects(ctx context.Context, upload *domain.ChunkedUpload) (int, error) {
+	key := generateStorageKey(upload.CompanyID, upload.ID, upload.FileName)
+	chunkKey := regexp.MustCompile("^" + regexp.QuoteMeta(key) + `\.chunk\.\d+$`)
+
+	objects, err := uc.storageRepo.ListFiles(ctx, key+".chunk.")
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, object := range objects {
+		if !chunkKey.MatchString(object.Key) {
+			continue
+		}
+
+		refs, err := uc.versionRepo.CountStoragePathRefs(ctx, object.Key)
+		if err != nil {
+			return deleted, err
+		}
+		if refs > 0 {
+			continue
+		}
+
+		if err := uc.storageRepo.DeleteFile(ctx, object.Key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// StartJanitor runs CleanupChunkedUploads every JanitorInterval until ctx is done.
+func (uc *UseCaseFileFolder) StartJanitor(ctx context.Context, log logger.Logger) {
+	if uc.config.JanitorInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.config.JanitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run := uc.CleanupChunkedUploads(ctx)
+			if run.Errors > 0 {
+				log.Error("func StartJanitor: chunked upload cleanup finished with errors", "func", "StartJanitor", "errors", run.Errors)
+			}
+			if run.Cleaned() {
+				log.Info("cleaned up chunked uploads",
+					"expired_sessions", run.ExpiredSessions,
+					"deleted_sessions", run.DeletedSessions,
+					"aborted_uploads", run.AbortedUploads,
+					"deleted_chunk_objects", run.DeletedChunkObjects,
+					"duration", run.Duration,
+				)
+			}
+		}
+	}
+}
+
+func (uc *UseCaseFileFolder) GetJanitorStats(ctx context.Context) (*domain.JanitorStats, error) {
+	uc.janitorMu.Lock()
+	defer uc.janitorMu.Unlock()
+
+	stats := uc.janitorStats
+	return &stats, nil
+}
@@ -0,0 +1,140 @@
+package ucFileFolder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/internal/repository/localfs"
+	"go-storage/pkg/errors"
+)
+
+type chunkedRepoMock struct {
+	ChunkedUploadRepository
+	mock.Mock
+}
+
+func (m *chunkedRepoMock) GetUnsweptUploads(ctx context.Context, limit int) ([]*domain.ChunkedUpload, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ChunkedUpload), args.Error(1)
+}
+
+func (m *chunkedRepoMock) MarkChunksSwept(ctx context.Context, uploadID string) error {
+	args := m.Called(ctx, uploadID)
+	return args.Error(0)
+}
+
+type versionRepoMock struct {
+	FileVersionRepository
+	mock.Mock
+}
+
+func (m *versionRepoMock) CountStoragePathRefs(ctx context.Context, storagePath string) (int, error) {
+	args := m.Called(ctx, storagePath)
+	return args.Int(0), args.Error(1)
+}
+
+func storeObjects(t *testing.T, storage *localfs.StorageRepository, keys ...string) {
+	for _, key := range keys {
+		_, err := storage.StoreFile(context.Background(), key, strings.NewReader("data"), 4, "application/octet-stream")
+		require.NoError(t, err)
+	}
+}
+
+func TestSweepChunkObjects_KeepsUserFiles(t *testing.T) {
+	storage, err := localfs.NewStorageRepository(t.TempDir())
+	require.NoError(t, err)
+
+	chunkedRepo := new(chunkedRepoMock)
+	versionRepo := new(versionRepoMock)
+	uc := NewUseCaseFileFolder(nil, storage, chunkedRepo, versionRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &config.FileServer{})
+
+	storeObjects(t, storage,
+		// Chunks of the legacy session.
+		"companies/c1/files/upload-1/video.mp4.chunk.0",
+		"companies/c1/files/upload-1/video.mp4.chunk.1",
+		// A user file named like a chunk, stored with its own file ID.
+		"companies/c1/files/file-9/backup.chunk.1",
+		// A user file below the session's key that a file row points at.
+		"companies/c1/files/upload-1/video.mp4.chunk.2",
+		"companies/c1/files/upload-1/video.mp4.chunk.final",
+	)
+
+	chunkedRepo.On("GetUnsweptUploads", mock.Anything, janitorBatchSize).
+		Return([]*domain.ChunkedUpload{{ID: "upload-1", CompanyID: "c1", FileName: "video.mp4"}}, nil)
+	chunkedRepo.On("MarkChunksSwept", mock.Anything, "upload-1").Return(nil)
+	versionRepo.On("CountStoragePathRefs", mock.Anything, "companies/c1/files/upload-1/video.mp4.chunk.2").Return(1, nil)
+	versionRepo.On("CountStoragePathRefs", mock.Anything, mock.Anything).Return(0, nil)
+
+	var run domain.JanitorRun
+	uc.sweepChunkObjects(context.Background(), &run)
+
+	assert.Equal(t, 2, run.DeletedChunkObjects)
+	assert.Zero(t, run.Errors)
+	chunkedRepo.AssertExpectations(t)
+
+	for _, key := range []string{
+		"companies/c1/files/upload-1/video.mp4.chunk.0",
+		"companies/c1/files/upload-1/video.mp4.chunk.1",
+	} {
+		_, err := storage.GetFileInfo(context.Background(), key)
+		assert.ErrorIs(t, err, errors.ErrNotFound, key)
+	}
+	for _, key := range []string{
+		"companies/c1/files/file-9/backup.chunk.1",
+		"companies/c1/files/upload-1/video.mp4.chunk.2",
+		"companies/c1/files/upload-1/video.mp4.chunk.final",
+	} {
+		_, err := storage.GetFileInfo(context.Background(), key)
+		assert.NoError(t, err, key)
+	}
+}
+
+func TestSweepChunkObjects_NothingLeft(t *testing.T) {
+	storage, err := localfs.NewStorageRepository(t.TempDir())
+	require.NoError(t, err)
+
+	chunkedRepo := new(chunkedRepoMock)
+	uc := NewUseCaseFileFolder(nil, storage, chunkedRepo, new(versionRepoMock), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &config.FileServer{})
+
+	storeObjects(t, storage, "companies/c1/files/file-9/backup.chunk.1")
+	chunkedRepo.On("GetUnsweptUploads", mock.Anything, janitorBatchSize).Return([]*domain.ChunkedUpload{}, nil)
+
+	var run domain.JanitorRun
+	uc.sweepChunkObjects(context.Background(), &run)
+
+	assert.Zero(t, run.DeletedChunkObjects)
+	chunkedRepo.AssertNotCalled(t, "MarkChunksSwept", mock.Anything, mock.Anything)
+	_, err = storage.GetFileInfo(context.Background(), "companies/c1/files/file-9/backup.chunk.1")
+	assert.NoError(t, err)
+}
+
+func TestSweepChunkObjects_KeepsSessionOnError(t *testing.T) {
+	storage, err := localfs.NewStorageRepository(t.TempDir())
+	require.NoError(t, err)
+
+	chunkedRepo := new(chunkedRepoMock)
+	versionRepo := new(versionRepoMock)
+	uc := NewUseCaseFileFolder(nil, storage, chunkedRepo, versionRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &config.FileServer{})
+
+	storeObjects(t, storage, "companies/c1/files/upload-1/video.mp4.chunk.0")
+	chunkedRepo.On("GetUnsweptUploads", mock.Anything, janitorBatchSize).
+		Return([]*domain.ChunkedUpload{{ID: "upload-1", CompanyID: "c1", FileName: "video.mp4"}}, nil)
+	versionRepo.On("CountStoragePathRefs", mock.Anything, mock.Anything).Return(0, errors.Database("unable to count storage path references"))
+
+	var run domain.JanitorRun
+	uc.sweepChunkObjects(context.Background(), &run)
+
+	assert.Equal(t, 1, run.Errors)
+	chunkedRepo.AssertNotCalled(t, "MarkChunksSwept", mock.Anything, mock.Anything)
+	_, err = storage.GetFileInfo(context.Background(), "companies/c1/files/upload-1/video.mp4.chunk.0")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,218 @@
+package ucFileFolder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stdErrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+const headerAmzChecksumSHA256 = "X-Amz-Checksum-Sha256"
+
+// GetDownloadURL returns a short-lived URL from which the file can be fetched
+// directly from storage.
+func (uc *UseCaseFileFolder) GetDownloadURL(ctx context.Context, companyID, userID, fileID string) (*domain.PresignedURL, error) {
+	file, err := uc.StatFile(ctx, companyID, userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(uc.config.PresignedURLExpiry)
+	url, err := uc.storageRepo.GetPresignedURL(ctx, *file.StoragePath, uc.config.PresignedURLExpiry, file.Name)
+	if err != nil {
+		return nil, err
+	}
+	uc.touchFile(ctx, file)
+
+	return &domain.PresignedURL{URL: url, ExpiresAt: expiresAt}, nil
+}
+
+// InitPresignedUpload opens an upload session and returns a short-lived URL to
+// which the client PUTs the content directly. When checksum is set, storage
+// itself rejects content that does not match it.
+func (uc *UseCaseFileFolder) InitPresignedUpload(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, checksum string) (*domain.PresignedUpload, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	if err := validateFilename(filename); err != nil {
+		return nil, err
+	}
+
+	if size < 0 {
+		return nil, errors.BadRequest("file size must not be negative")
+	}
+
+	if size > uc.config.MaxFileSize {
+		return nil, errors.BadRequest("file size exceeds maximum allowed size")
+	}
+
+	checksum, err := normalizeChecksum(checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.requireWriteInto(ctx, companyID, userID, *parentPath); err != nil {
+		return nil, err
+	}
+
+	targetPath := parentPath.Join(filename)
+	if existing, err := uc.fileRepo.GetFileByPath(ctx, companyID, &targetPath); err == nil && existing != nil {
+		return nil, errors.FileExists("file with this name already exists")
+	}
+
+	mimeType, err := uc.resolveFileType(ctx, companyID, userID, filename, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkQuota(ctx, companyID, userID, size, 1); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	upload := &domain.PresignedUpload{
+		ID:           uuid.NewString(),
+		FileName:     filename,
+		CompanyID:    companyID,
+		UserCreateID: userID,
+		TargetPath:   targetPath,
+		MimeType:     mimeType,
+		Size:         size,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(uc.config.PresignedURLExpiry),
+	}
+	upload.StorageKey = generateStorageKey(companyID, upload.ID, filename)
+
+	headers := http.Header{}
+	headers.Set("Content-Type", upload.MimeType)
+	if checksum != "" {
+		upload.Checksum = &checksum
+		digest, _ := hex.DecodeString(checksum)
+		headers.Set(headerAmzChecksumSHA256, base64.StdEncoding.EncodeToString(digest))
+	}
+
+	upload.URL, err = uc.storageRepo.GetPresignedUploadURL(ctx, upload.StorageKey, uc.config.PresignedURLExpiry, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	upload.Headers = make(map[string]string, len(headers))
+	for name := range headers {
+		upload.Headers[name] = headers.Get(name)
+	}
+
+	return uc.presignedRepo.CreatePresignedUpload(ctx, upload)
+}
+
+// FinalizePresignedUpload verifies the object uploaded through a presigned URL
+// and creates the file for it.
+func (uc *UseCaseFileFolder) FinalizePresignedUpload(ctx context.Context, companyID, userID, uploadID string) (*domain.File, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	upload, err := uc.presignedRepo.GetPresignedUpload(ctx, companyID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.UserCreateID != userID {
+		return nil, errors.NotFound("presigned upload session not found")
+	}
+
+	if err := uc.requireWriteInto(ctx, companyID, userID, upload.TargetPath.GetParent()); err != nil {
+		return nil, err
+	}
+
+	info, err := uc.storageRepo.GetFileInfo(ctx, upload.StorageKey)
+	if err != nil {
+		if !stdErrors.Is(err, errors.ErrNotFound) {
+			return nil, err
+		}
+		if upload.IsExpired() {
+			_ = uc.presignedRepo.DeletePresignedUpload(ctx, companyID, uploadID)
+			return nil, errors.BadRequest("upload session has expired")
+		}
+		return nil, errors.BadRequest("file has not been uploaded to storage yet")
+	}
+
+	if info.Size != upload.Size {
+		_ = uc.storageRepo.DeleteFile(ctx, upload.StorageKey)
+		return nil, errors.BadRequest("uploaded file size does not match the declared size")
+	}
+
+	hash, err := uc.presignedObjectHash(ctx, upload.StorageKey, info)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Checksum != nil && hash != *upload.Checksum {
+		_ = uc.storageRepo.DeleteFile(ctx, upload.StorageKey)
+		return nil, errors.BadRequest("checksum mismatch: uploaded content is corrupted")
+	}
+
+	head, err := uc.objectHead(ctx, upload.StorageKey, info.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name was checked when the URL was issued, the content only now.
+	upload.MimeType, err = uc.resolveFileType(ctx, companyID, userID, upload.FileName, upload.MimeType, head)
+	if err != nil {
+		_ = uc.storageRepo.DeleteFile(ctx, upload.StorageKey)
+		_ = uc.presignedRepo.DeletePresignedUpload(ctx, companyID, uploadID)
+		return nil, err
+	}
+
+	file := &domain.File{
+		ID:           upload.ID,
+		Name:         upload.FileName,
+		Type:         domain.FileTypeFile,
+		FullPath:     upload.TargetPath,
+		ParentID:     uc.parentFolderID(ctx, upload.CompanyID, upload.TargetPath.GetParent()),
+		CompanyId:    upload.CompanyID,
+		UserCreateID: upload.UserCreateID,
+		MimeType:     &upload.MimeType,
+		Size:         &upload.Size,
+		Hash:         &hash,
+		StoragePath:  &upload.StorageKey,
+		ScanStatus:   uc.initialScanStatus(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		IsActive:     true,
+	}
+
+	created, err := uc.fileRepo.CreateFile(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = uc.versionRepo.CreateVersion(ctx, domain.NewFileVersion(created, upload.UserCreateID))
+	_ = uc.presignedRepo.DeletePresignedUpload(ctx, companyID, uploadID)
+	uc.queueScan(ctx, created)
+	uc.grantDefaultAccess(ctx, created)
+
+	return created, nil
+}
+
+// presignedObjectHash prefers the SHA-256 already verified by storage and only
+// reads the object back when there is none.
+func (uc *UseCaseFileFolder) presignedObjectHash(ctx context.Context, storageKey string, info *domain.StorageFileInfo) (string, error) {
+	if digest, err := base64.StdEncoding.DecodeString(info.ChecksumSHA256); err == nil && len(digest) == sha256.Size {
+		return hex.EncodeToString(digest), nil
+	}
+
+	return uc.hashObject(ctx, storageKey)
+}
@@ -0,0 +1,39 @@
+package ucFileFolder
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+// checkQuota rejects an upload of bytes more storage and files more files when
+// it does not fit within the quota of the company or of the user. Upload
+// sessions in progress count as used, so a chunked or presigned upload holds
+// its whole size from the moment it is started.
+func (uc *UseCaseFileFolder) checkQuota(ctx context.Context, companyID, userID string, bytes, files int64) error {
+	usage, err := uc.quotaRepo.GetCompanyUsage(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	if !usage.Allows(bytes, files) {
+		return errors.QuotaExceeded("company storage quota exceeded")
+	}
+
+	usage, err = uc.quotaRepo.GetUserUsage(ctx, companyID, userID)
+	if err != nil {
+		return err
+	}
+	if !usage.Allows(bytes, files) {
+		return errors.QuotaExceeded("user storage quota exceeded")
+	}
+
+	return nil
+}
+
+func fileSize(file *domain.File) int64 {
+	if file.Size == nil {
+		return 0
+	}
+	return *file.Size
+}
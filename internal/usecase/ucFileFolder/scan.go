@@ -0,0 +1,276 @@
+package ucFileFolder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+const (
+	scanStaleAfter    = 30 * time.Minute
+	scanFindingsLimit = 500
+)
+
+// StartScanWorker scans pending files every ScanWorkerInterval and whenever new
+// content is stored, until ctx is done. Without a scanner it does nothing.
+func (uc *UseCaseFileFolder) StartScanWorker(ctx context.Context, log logger.Logger) {
+	if uc.scanner == nil || uc.config.ScanWorkerInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.config.ScanWorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uc.scanWake:
+		}
+
+		if scanned := uc.ProcessScans(ctx, log); scanned > 0 {
+			log.Info("scanned files", "count", scanned)
+		}
+	}
+}
+
+// ProcessScans scans pending files, up to ScanWorkers at a time, until none are
+// left and returns how many were processed.
+func (uc *UseCaseFileFolder) ProcessScans(ctx context.Context, log logger.Logger) int {
+	processed := 0
+
+	for {
+		files, err := uc.fileRepo.ClaimScans(ctx, time.Now().Add(-scanStaleAfter), max(1, uc.config.ScanWorkers))
+		if err != nil {
+			log.Error("func ProcessScans: failed to claim scans", "func", "ProcessScans", "err", err)
+			return processed
+		}
+		if len(files) == 0 {
+			return processed
+		}
+
+		var wg sync.WaitGroup
+		for _, file := range files {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := uc.scanFile(ctx, log, file); err != nil {
+					log.Error("func ProcessScans: failed to scan file", "func", "ProcessScans", "file", file.ID, "err", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		processed += len(files)
+	}
+}
+
+// scanFile scans the current object of the file and records the result.
+// Infected content goes to quarantine, quarantined content found clean on a
+// rescan comes out again. Scanner failures mark the file as error; when the
+// object cannot be read, the file stays claimed and is retried once stale.
+func (uc *UseCaseFileFolder) scanFile(ctx context.Context, log logger.Logger, file *domain.File) error {
+	reader, err := uc.storageRepo.GetFile(ctx, *file.StoragePath)
+	if err != nil {
+		return errors.StorageError("failed to retrieve file from storage")
+	}
+
+	signature, err := uc.scanner.Scan(ctx, reader)
+	reader.Close()
+
+	result := &domain.ScanResult{StoragePath: *file.StoragePath, ScannedAt: time.Now()}
+	switch {
+	case err != nil:
+		reason := err.Error()
+		result.Status, result.Detail = domain.ScanStatusError, &reason
+	case signature != "":
+		result.Status, result.Detail = domain.ScanStatusInfected, &signature
+		log.Warn("func scanFile: Malware found, file quarantined", "func", "scanFile",
+			"company_id", file.CompanyId, "file_id", file.ID, "path", file.FullPath.String(), "signature", signature)
+		_, err := uc.moveScannedObject(ctx, file, domain.QuarantineKey(result.StoragePath), result)
+		return err
+	default:
+		result.Status = domain.ScanStatusClean
+	}
+
+	var saved bool
+	if result.Status == domain.ScanStatusClean && domain.IsQuarantineKey(result.StoragePath) {
+		saved, err = uc.moveScannedObject(ctx, file, domain.ReleasedKey(result.StoragePath), result)
+	} else {
+		saved, err = uc.fileRepo.SaveScanResult(ctx, file, result)
+	}
+	if err != nil || !saved || result.Status != domain.ScanStatusClean {
+		return err
+	}
+
+	uc.queueContentIndex(ctx, file)
+	uc.queueRenditions(ctx, file)
+
+	return nil
+}
+
+// moveScannedObject copies the scanned object to newKey, points the file and
+// its versions at the copy and removes the original. It reports whether the
+// file itself still had that content.
+func (uc *UseCaseFileFolder) moveScannedObject(ctx context.Context, file *domain.File, newKey string, result *domain.ScanResult) (bool, error) {
+	if newKey == result.StoragePath {
+		return uc.fileRepo.MoveScannedObject(ctx, file, newKey, result)
+	}
+
+	if err := uc.storageRepo.CopyFile(ctx, result.StoragePath, newKey); err != nil {
+		return false, errors.StorageError("failed to move scanned file")
+	}
+
+	moved, err := uc.fileRepo.MoveScannedObject(ctx, file, newKey, result)
+	if err != nil {
+		_ = uc.storageRepo.DeleteFile(ctx, newKey)
+		return false, err
+	}
+
+	_ = uc.storageRepo.DeleteFile(ctx, result.StoragePath)
+	file.StoragePath = &newKey
+
+	return moved, nil
+}
+
+// GetScanFindings lists the company files with the given scan status for
+// review, the infected ones when status is empty.
+func (uc *UseCaseFileFolder) GetScanFindings(ctx context.Context, companyID string, status domain.ScanStatus) ([]*domain.ScanFinding, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if status == "" {
+		status = domain.ScanStatusInfected
+	}
+	if !status.IsValid() || status == domain.ScanStatusClean {
+		return nil, errors.BadRequest("status must be infected, error or pending")
+	}
+
+	return uc.fileRepo.GetScanFindings(ctx, companyID, status, scanFindingsLimit)
+}
+
+// RescanFile queues the file for another scan, after a scan error or once the
+// scanner knows new signatures. Quarantined content found clean is released.
+func (uc *UseCaseFileFolder) RescanFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if uc.scanner == nil {
+		return nil, errors.InvalidOperation("malware scanning is not enabled")
+	}
+
+	if err := uc.fileRepo.RequeueScan(ctx, companyID, fileID); err != nil {
+		return nil, err
+	}
+
+	uc.wakeScanWorker()
+
+	return uc.fileRepo.GetFile(ctx, companyID, fileID)
+}
+
+// ReleaseFile clears an infected file or a failed scan after review. The
+// content leaves quarantine and is served again; who released it is recorded.
+func (uc *UseCaseFileFolder) ReleaseFile(ctx context.Context, companyID, userID, fileID string) (*domain.File, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	file, err := uc.fileRepo.GetFile(ctx, companyID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.ScanStatus != domain.ScanStatusInfected && file.ScanStatus != domain.ScanStatusError {
+		return nil, errors.BadRequest("only infected files and failed scans can be released")
+	}
+	if file.StoragePath == nil {
+		return nil, errors.InternalServer("file storage path not found")
+	}
+
+	detail := fmt.Sprintf("released by user %s", userID)
+	result := &domain.ScanResult{
+		StoragePath: *file.StoragePath,
+		Status:      domain.ScanStatusClean,
+		Detail:      &detail,
+		ScannedAt:   time.Now(),
+	}
+
+	moved, err := uc.moveScannedObject(ctx, file, domain.ReleasedKey(result.StoragePath), result)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, errors.Conflict("file content changed during the review")
+	}
+
+	logger.FromContext(ctx).Warn("func ReleaseFile: File released from quarantine", "func", "ReleaseFile",
+		"company_id", companyID, "user_id", userID, "file_id", fileID, "path", file.FullPath.String())
+
+	file.ScanStatus = domain.ScanStatusClean
+	uc.queueContentIndex(ctx, file)
+	uc.queueRenditions(ctx, file)
+
+	return file, nil
+}
+
+// initialScanStatus is the status of newly stored content: pending until the
+// scan worker got to it, or clean when no scanner is configured.
+func (uc *UseCaseFileFolder) initialScanStatus() domain.ScanStatus {
+	if uc.scanner == nil {
+		return domain.ScanStatusClean
+	}
+	return domain.ScanStatusPending
+}
+
+// queueScan hands newly stored content to the scan worker. Content indexing
+// and thumbnails wait until the content is found clean.
+func (uc *UseCaseFileFolder) queueScan(ctx context.Context, file *domain.File) {
+	if file.ScanStatus == domain.ScanStatusClean {
+		uc.queueContentIndex(ctx, file)
+		uc.queueRenditions(ctx, file)
+		return
+	}
+
+	uc.wakeScanWorker()
+}
+
+// checkScanStatus refuses content that must not be served: infected content
+// always, content not found clean yet when ScanBlockDownloads is set.
+func (uc *UseCaseFileFolder) checkScanStatus(file *domain.File) error {
+	if file.ScanStatus.Downloadable(!uc.config.ScanBlockDownloads) {
+		return nil
+	}
+
+	if file.ScanStatus == domain.ScanStatusInfected {
+		return errors.Forbidden("file is quarantined: malware was detected")
+	}
+	return errors.Conflict("file has not passed the malware scan yet")
+}
+
+// checkVersionScanStatus refuses quarantined versions, and the current version
+// under the same rules as the file.
+func (uc *UseCaseFileFolder) checkVersionScanStatus(file *domain.File, version *domain.FileVersion) error {
+	if domain.IsQuarantineKey(version.StoragePath) {
+		return errors.Forbidden("version is quarantined: malware was detected")
+	}
+
+	if file.StoragePath != nil && *file.StoragePath == version.StoragePath {
+		return uc.checkScanStatus(file)
+	}
+	return nil
+}
+
+func (uc *UseCaseFileFolder) wakeScanWorker() {
+	select {
+	case uc.scanWake <- struct{}{}:
+	default:
+	}
+}
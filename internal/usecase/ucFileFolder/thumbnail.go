@@ -0,0 +1,194 @@
+package ucFileFolder
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stdErrors "errors"
+	"io"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/thumbnail"
+)
+
+// GetThumbnail returns the rendition of an image file in the given size. A
+// rendition that is missing or was generated from an older version is
+// generated again before returning.
+func (uc *UseCaseFileFolder) GetThumbnail(ctx context.Context, companyID, userID, fileID string, size domain.RenditionSize) (*domain.FileRendition, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if !size.IsValid() {
+		return nil, errors.BadRequest("size must be small, medium or large")
+	}
+
+	file, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Type != domain.FileTypeFile {
+		return nil, errors.BadRequest("specified ID is not a file")
+	}
+
+	if !domain.IsRenderableImage(file.MimeType) {
+		return nil, errors.InvalidFileType("thumbnails are only available for JPEG, PNG and GIF images")
+	}
+
+	if err := uc.checkScanStatus(file); err != nil {
+		return nil, err
+	}
+
+	rendition, err := uc.renditionRepo.GetRendition(ctx, companyID, fileID, size)
+	if err == nil && rendition.IsCurrent(file) {
+		return rendition, nil
+	}
+
+	return uc.regenerateThumbnail(ctx, file, size)
+}
+
+// OpenThumbnail opens the content of a rendition returned by GetThumbnail.
+// When the object has gone missing from storage it is generated again.
+func (uc *UseCaseFileFolder) OpenThumbnail(ctx context.Context, rendition *domain.FileRendition) (io.ReadCloser, error) {
+	reader, err := uc.storageRepo.GetFile(ctx, rendition.StoragePath)
+	if err == nil {
+		return reader, nil
+	}
+
+	file, err := uc.fileRepo.GetFile(ctx, rendition.CompanyID, rendition.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	regenerated, err := uc.regenerateThumbnail(ctx, file, rendition.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err = uc.storageRepo.GetFile(ctx, regenerated.StoragePath)
+	if err != nil {
+		return nil, errors.InternalServer("failed to retrieve thumbnail from storage")
+	}
+
+	return reader, nil
+}
+
+// regenerateThumbnail renders all sizes of the file while the caller waits
+// and returns the one asked for.
+func (uc *UseCaseFileFolder) regenerateThumbnail(ctx context.Context, file *domain.File, size domain.RenditionSize) (*domain.FileRendition, error) {
+	select {
+	case uc.renderSlots <- struct{}{}:
+		defer func() { <-uc.renderSlots }()
+	case <-ctx.Done():
+		return nil, errors.TooManyRequests("thumbnail generation is busy, try again later")
+	}
+
+	renditions, err := uc.renderFile(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rendition := range renditions {
+		if rendition.Size == size {
+			return rendition, nil
+		}
+	}
+
+	return nil, errors.InternalServer("failed to generate thumbnail")
+}
+
+// queueRenditions generates the thumbnails of an uploaded image in the
+// background. Like content indexing it is best effort: when all workers are
+// busy the upload is skipped and its thumbnails are generated on first request.
+func (uc *UseCaseFileFolder) queueRenditions(ctx context.Context, file *domain.File) {
+	if !domain.IsRenderableImage(file.MimeType) || fileSize(file) > uc.config.ThumbnailMaxSourceSize {
+		return
+	}
+
+	select {
+	case uc.renderSlots <- struct{}{}:
+	default:
+		return
+	}
+
+	go func() {
+		defer func() { <-uc.renderSlots }()
+		_, _ = uc.renderFile(context.WithoutCancel(ctx), file)
+	}()
+}
+
+// renderFile decodes the current content of an image file once, stores every
+// rendition size and records them.
+func (uc *UseCaseFileFolder) renderFile(ctx context.Context, file *domain.File) ([]*domain.FileRendition, error) {
+	if file.StoragePath == nil {
+		return nil, errors.InternalServer("file storage path not found")
+	}
+
+	if fileSize(file) > uc.config.ThumbnailMaxSourceSize {
+		return nil, errors.InvalidOperation("image is too large to generate a thumbnail")
+	}
+
+	reader, err := uc.storageRepo.GetFile(ctx, *file.StoragePath)
+	if err != nil {
+		return nil, errors.InternalServer("failed to retrieve file from storage")
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, uc.config.ThumbnailMaxSourceSize+1))
+	if err != nil {
+		return nil, errors.InternalServer("failed to read file from storage")
+	}
+	if int64(len(data)) > uc.config.ThumbnailMaxSourceSize {
+		return nil, errors.InvalidOperation("image is too large to generate a thumbnail")
+	}
+
+	img, err := thumbnail.Decode(data, *file.MimeType, uc.config.ThumbnailMaxPixels)
+	if stdErrors.Is(err, thumbnail.ErrTooLarge) {
+		return nil, errors.InvalidOperation("image is too large to generate a thumbnail")
+	}
+	if err != nil {
+		return nil, errors.InvalidOperation("image could not be decoded")
+	}
+
+	renditions := make([]*domain.FileRendition, 0, len(domain.RenditionSizes))
+	for _, size := range domain.RenditionSizes {
+		scaled := thumbnail.Fit(img, size.MaxEdge())
+
+		var buf bytes.Buffer
+		mimeType, err := thumbnail.Encode(&buf, scaled)
+		if err != nil {
+			return nil, errors.InternalServer("failed to encode thumbnail")
+		}
+
+		sum := sha256.Sum256(buf.Bytes())
+		rendition := &domain.FileRendition{
+			FileID:      file.ID,
+			CompanyID:   file.CompanyId,
+			Size:        size,
+			StoragePath: generateRenditionKey(file.CompanyId, file.ID, size),
+			MimeType:    mimeType,
+			Width:       scaled.Bounds().Dx(),
+			Height:      scaled.Bounds().Dy(),
+			ByteSize:    int64(buf.Len()),
+			Hash:        hex.EncodeToString(sum[:]),
+			SourcePath:  *file.StoragePath,
+			CreatedAt:   time.Now(),
+		}
+
+		if _, err := uc.storageRepo.StoreFile(ctx, rendition.StoragePath, &buf, rendition.ByteSize, mimeType); err != nil {
+			return nil, errors.StorageError("failed to store thumbnail")
+		}
+
+		if err := uc.renditionRepo.SaveRendition(ctx, rendition); err != nil {
+			return nil, err
+		}
+
+		renditions = append(renditions, rendition)
+	}
+
+	return renditions, nil
+}
@@ -4,8 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"path/filepath"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,15 +17,46 @@ type UseCaseFileFolder struct {
 	fileRepo         RepositoryFileFolder
 	storageRepo      StorageRepository
 	chunkedRepo      ChunkedUploadRepository
+	versionRepo      FileVersionRepository
+	presignedRepo    PresignedUploadRepository
+	deleteJobRepo    FolderDeleteJobRepository
+	copyJobRepo      FolderCopyJobRepository
+	trashRepo        TrashRepository
+	contentRepo      ContentIndexRepository
+	metadataRepo     FileMetadataRepository
+	quotaRepo        QuotaRepository
+	accessRepo       AccessRepository
+	renditionRepo    RenditionRepository
+	policyRepo       FileTypePolicyRepository
+	scanner          Scanner
 	resourceMonitor  *domain.ResourceMonitor
 	strategySelector *domain.UploadStrategySelector
 	config           *config.FileServer
+
+	janitorMu    sync.Mutex
+	janitorStats domain.JanitorStats
+
+	folderJobWake chan struct{}
+	renderSlots   chan struct{}
+	scanWake      chan struct{}
 }
 
 func NewUseCaseFileFolder(
 	fileRepo RepositoryFileFolder,
 	storageRepo StorageRepository,
 	chunkedRepo ChunkedUploadRepository,
+	versionRepo FileVersionRepository,
+	presignedRepo PresignedUploadRepository,
+	deleteJobRepo FolderDeleteJobRepository,
+	copyJobRepo FolderCopyJobRepository,
+	trashRepo TrashRepository,
+	contentRepo ContentIndexRepository,
+	metadataRepo FileMetadataRepository,
+	quotaRepo QuotaRepository,
+	accessRepo AccessRepository,
+	renditionRepo RenditionRepository,
+	policyRepo FileTypePolicyRepository,
+	scanner Scanner,
 	config *config.FileServer,
 ) *UseCaseFileFolder {
 	resourceMonitor := domain.NewResourceMonitor(config)
@@ -36,9 +66,24 @@ func NewUseCaseFileFolder(
 		fileRepo:         fileRepo,
 		storageRepo:      storageRepo,
 		chunkedRepo:      chunkedRepo,
+		versionRepo:      versionRepo,
+		presignedRepo:    presignedRepo,
+		deleteJobRepo:    deleteJobRepo,
+		copyJobRepo:      copyJobRepo,
+		trashRepo:        trashRepo,
+		contentRepo:      contentRepo,
+		metadataRepo:     metadataRepo,
+		quotaRepo:        quotaRepo,
+		accessRepo:       accessRepo,
+		renditionRepo:    renditionRepo,
+		policyRepo:       policyRepo,
+		scanner:          scanner,
 		resourceMonitor:  resourceMonitor,
 		strategySelector: strategySelector,
 		config:           config,
+		folderJobWake:    make(chan struct{}, 1),
+		renderSlots:      make(chan struct{}, max(1, config.ThumbnailWorkers)),
+		scanWake:         make(chan struct{}, 1),
 	}
 }
 
@@ -51,6 +96,10 @@ func (uc *UseCaseFileFolder) CreateFolder(ctx context.Context, folder *domain.Fi
 		return nil, errors.BadRequest("company ID is required")
 	}
 
+	if folder.UserCreateID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
 	if folder.ParentID != nil {
 		parent, err := uc.fileRepo.GetFile(ctx, folder.CompanyId, *folder.ParentID)
 		if err != nil {
@@ -61,29 +110,105 @@ func (uc *UseCaseFileFolder) CreateFolder(ctx context.Context, folder *domain.Fi
 		}
 	}
 
+	if err := uc.requireWriteInto(ctx, folder.CompanyId, folder.UserCreateID, folder.FullPath.GetParent()); err != nil {
+		return nil, err
+	}
+
 	existingFile, err := uc.fileRepo.GetFileByPath(ctx, folder.CompanyId, &folder.FullPath)
 	if err == nil && existingFile != nil {
 		return nil, errors.BadRequest("folder with this name already exists")
 	}
 
+	if folder.ParentID == nil {
+		folder.ParentID = uc.parentFolderID(ctx, folder.CompanyId, folder.FullPath.GetParent())
+	}
+
 	folder.ID = uuid.NewString()
 	folder.Type = domain.FileTypeFolder
 	folder.CreatedAt = time.Now()
 	folder.UpdatedAt = time.Now()
 	folder.IsActive = true
 
-	return uc.fileRepo.CreateFolder(ctx, folder)
+	created, err := uc.fileRepo.CreateFolder(ctx, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.grantDefaultAccess(ctx, created)
+
+	return created, nil
 }
 
-func (uc *UseCaseFileFolder) GetFolderContents(ctx context.Context, companyID string, path *domain.Path, fileType *domain.FileType) ([]*domain.File, error) {
+// ListFolder returns one page of the folder contents. The next page starts
+// after FolderPage.NextCursor, which is empty on the last page. Users without
+// access to the folder only see the items they have access to, and the
+// folders leading to them.
+func (uc *UseCaseFileFolder) ListFolder(ctx context.Context, companyID, userID string, query *domain.FolderListQuery) (*domain.FolderPage, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+	if !query.Sort.IsValid() || !query.Order.IsValid() {
+		return nil, errors.BadRequest("invalid sort")
+	}
+	if query.Cursor != nil && (query.Cursor.Sort != query.Sort || query.Cursor.Order != query.Order) {
+		return nil, errors.BadRequest("cursor does not match the requested sort")
+	}
+	if query.Limit <= 0 || query.Limit > domain.MaxFolderListLimit {
+		query.Limit = domain.DefaultFolderListLimit
+	}
+
+	var parentID *string
+	if !query.Path.IsRoot() {
+		folder, err := uc.fileRepo.GetFileByPath(ctx, companyID, &query.Path)
+		if err != nil {
+			return nil, errors.NotFound("folder not found")
+		}
+		if folder.Type != domain.FileTypeFolder {
+			return nil, errors.BadRequest("specified path is not a folder")
+		}
+		parentID = &folder.ID
+	}
 
-	return uc.fileRepo.GetFolderContents(ctx, companyID, path, fileType)
+	role, err := uc.accessRepo.GetAccessRole(ctx, companyID, userID, query.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra item tells whether another page follows.
+	pageQuery := *query
+	pageQuery.Limit++
+
+	if !role.Allows(domain.AccessViewer) {
+		if !query.Path.IsRoot() {
+			below, err := uc.accessRepo.HasAccessBelow(ctx, companyID, userID, query.Path)
+			if err != nil {
+				return nil, err
+			}
+			if !below {
+				return nil, errors.NotFound("folder not found")
+			}
+		}
+		pageQuery.VisibleTo = userID
+	}
+
+	items, err := uc.fileRepo.ListFolder(ctx, companyID, parentID, &pageQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.FolderPage{Items: items}
+	if len(items) > query.Limit {
+		page.Items = items[:query.Limit]
+		page.NextCursor = domain.NewFolderCursor(page.Items[query.Limit-1], query.Sort, query.Order).Encode()
+	}
+
+	return page, nil
 }
 
-func (uc *UseCaseFileFolder) MoveFolder(ctx context.Context, companyID string, folderPath *domain.Path, newPath *domain.Path) (*domain.Path, error) {
+func (uc *UseCaseFileFolder) MoveFolder(ctx context.Context, companyID, userID string, folderPath *domain.Path, newPath *domain.Path) (*domain.Path, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
@@ -93,6 +218,14 @@ func (uc *UseCaseFileFolder) MoveFolder(ctx context.Context, companyID string, f
 		return nil, errors.NotFound("folder not found")
 	}
 
+	if err := uc.requireAccess(ctx, companyID, userID, folder.FullPath, domain.AccessEditor, "folder not found"); err != nil {
+		return nil, err
+	}
+
+	if err := uc.requireWriteInto(ctx, companyID, userID, newPath.GetParent()); err != nil {
+		return nil, err
+	}
+
 	if folder.Type != domain.FileTypeFolder {
 		return nil, errors.BadRequest("specified path is not a folder")
 	}
@@ -110,7 +243,7 @@ func (uc *UseCaseFileFolder) MoveFolder(ctx context.Context, companyID string, f
 	return result, nil
 }
 
-func (uc *UseCaseFileFolder) DeleteFolder(ctx context.Context, companyID string, folderPath *domain.Path) error {
+func (uc *UseCaseFileFolder) DeleteFolder(ctx context.Context, companyID, userID string, folderPath *domain.Path) error {
 	if companyID == "" {
 		return errors.BadRequest("company ID is required")
 	}
@@ -120,6 +253,10 @@ func (uc *UseCaseFileFolder) DeleteFolder(ctx context.Context, companyID string,
 		return errors.NotFound("folder not found")
 	}
 
+	if err := uc.requireAccess(ctx, companyID, userID, folder.FullPath, domain.AccessEditor, "folder not found"); err != nil {
+		return err
+	}
+
 	if folder.Type != domain.FileTypeFolder {
 		return errors.BadRequest("specified path is not a folder")
 	}
@@ -130,13 +267,15 @@ func (uc *UseCaseFileFolder) DeleteFolder(ctx context.Context, companyID string,
 	}
 
 	if len(contents) > 0 {
-		return errors.BadRequest("folder is not empty")
+		return errors.BadRequest("folder is not empty, use recursive deletion to remove its contents")
 	}
 
 	return uc.fileRepo.DeleteFolder(ctx, companyID, folderPath)
 }
 
-func (uc *UseCaseFileFolder) UploadFile(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader) (*domain.File, error) {
+// UploadFile stores a new file. When checksum is set, the upload is rejected
+// unless the SHA-256 of the received content matches it.
+func (uc *UseCaseFileFolder) UploadFile(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader, checksum string) (*domain.File, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
@@ -145,59 +284,106 @@ func (uc *UseCaseFileFolder) UploadFile(ctx context.Context, companyID, userID s
 		return nil, errors.BadRequest("user ID is required")
 	}
 
-	if filename == "" {
-		return nil, errors.BadRequest("filename is required")
+	if err := validateFilename(filename); err != nil {
+		return nil, err
 	}
 
 	if size > uc.config.MaxFileSize {
 		return nil, errors.BadRequest("file size exceeds maximum allowed size")
 	}
 
-	if err := uc.resourceMonitor.AcquireUploadSlot(ctx); err != nil {
-		return nil, errors.TooManyRequests("too many concurrent uploads")
+	checksum, err := normalizeChecksum(checksum)
+	if err != nil {
+		return nil, err
 	}
-	defer uc.resourceMonitor.ReleaseUploadSlot()
 
-	strategy, err := uc.strategySelector.SelectStrategy(size)
+	if err := uc.requireWriteInto(ctx, companyID, userID, *parentPath); err != nil {
+		return nil, err
+	}
+
+	head, reader := peekHead(reader)
+	mimeType, err := uc.resolveFileType(ctx, companyID, userID, filename, "", head)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := uc.checkQuota(ctx, companyID, userID, size, 1); err != nil {
+		return nil, err
+	}
+
 	file := &domain.File{
 		ID:           uuid.NewString(),
 		Name:         filename,
 		Type:         domain.FileTypeFile,
 		FullPath:     parentPath.Join(filename),
+		ParentID:     uc.parentFolderID(ctx, companyID, *parentPath),
 		CompanyId:    companyID,
 		UserCreateID: userID,
 		Size:         &size,
+		ScanStatus:   uc.initialScanStatus(),
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 		IsActive:     true,
 	}
 
-	mimeType := determineMimeType(filename)
 	file.MimeType = &mimeType
 
 	storageKey := generateStorageKey(companyID, file.ID, filename)
+	hash, err := uc.storeObject(ctx, file, userID, reader, size, storageKey, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	file.StoragePath = &storageKey
+	file.Hash = &hash
+
+	created, err := uc.fileRepo.CreateFile(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = uc.versionRepo.CreateVersion(ctx, domain.NewFileVersion(created, userID))
+	uc.queueScan(ctx, created)
+	uc.grantDefaultAccess(ctx, created)
+
+	return created, nil
+}
+
+// storeObject uploads the content under storageKey and returns its SHA-256. An
+// object whose digest does not match a non-empty checksum is removed again.
+func (uc *UseCaseFileFolder) storeObject(ctx context.Context, file *domain.File, userID string, reader io.Reader, size int64, storageKey, checksum string) (string, error) {
+	if err := uc.resourceMonitor.AcquireUploadSlot(ctx); err != nil {
+		return "", errors.TooManyRequests("too many concurrent uploads")
+	}
+	defer uc.resourceMonitor.ReleaseUploadSlot()
+
+	strategy, err := uc.strategySelector.SelectStrategy(size)
+	if err != nil {
+		return "", err
+	}
 
 	uploadCtx := &domain.FileUploadContext{
 		File:      file,
 		Strategy:  strategy.GetStrategy(),
-		CompanyID: companyID,
+		CompanyID: file.CompanyId,
 		UserID:    userID,
 	}
 
-	storagePath, err := uc.uploadWithStrategy(ctx, uploadCtx, reader, size, mimeType, storageKey)
-	if err != nil {
+	hashing := newHashingReader(reader)
+	if _, err := uc.uploadWithStrategy(ctx, uploadCtx, hashing, size, *file.MimeType, storageKey); err != nil {
 		uc.resourceMonitor.RecordFailure()
-		return nil, err
+		return "", err
 	}
 
-	file.StoragePath = &storagePath
 	uc.resourceMonitor.RecordSuccess()
 
-	return uc.fileRepo.CreateFile(ctx, file)
+	hash := hashing.Sum()
+	if checksum != "" && hash != checksum {
+		_ = uc.storageRepo.DeleteFile(ctx, storageKey)
+		return "", errors.BadRequest("checksum mismatch: uploaded content is corrupted")
+	}
+
+	return hash, nil
 }
 
 func (uc *UseCaseFileFolder) uploadWithStrategy(ctx context.Context, uploadCtx *domain.FileUploadContext, reader io.Reader, size int64, mimeType, storageKey string) (string, error) {
@@ -226,41 +412,77 @@ func (uc *UseCaseFileFolder) uploadStreamStrategy(ctx context.Context, reader io
 	return uc.storageRepo.StoreFile(ctx, storageKey, reader, size, mimeType)
 }
 
-func (uc *UseCaseFileFolder) DownloadFile(ctx context.Context, companyID, fileID string) (io.ReadCloser, *domain.File, error) {
+// StatFile returns the metadata of a downloadable file without touching storage,
+// so callers can evaluate conditional and range requests first.
+func (uc *UseCaseFileFolder) StatFile(ctx context.Context, companyID, userID, fileID string) (*domain.File, error) {
 	if companyID == "" {
-		return nil, nil, errors.BadRequest("company ID is required")
+		return nil, errors.BadRequest("company ID is required")
 	}
 
-	file, err := uc.fileRepo.GetFile(ctx, companyID, fileID)
+	file, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessViewer)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	if file.Type != domain.FileTypeFile {
-		return nil, nil, errors.BadRequest("specified ID is not a file")
+		return nil, errors.BadRequest("specified ID is not a file")
 	}
 
 	if file.StoragePath == nil {
-		return nil, nil, errors.InternalServer("file storage path not found")
+		return nil, errors.InternalServer("file storage path not found")
+	}
+
+	if err := uc.checkScanStatus(file); err != nil {
+		return nil, err
 	}
 
-	reader, err := uc.storageRepo.GetFile(ctx, *file.StoragePath)
+	return file, nil
+}
+
+// OpenFile opens the content of a file returned by StatFile. A nil byteRange
+// reads the whole object and verifies it against the stored hash.
+func (uc *UseCaseFileFolder) OpenFile(ctx context.Context, file *domain.File, byteRange *domain.ByteRange) (io.ReadCloser, error) {
+	if file.StoragePath == nil {
+		return nil, errors.InternalServer("file storage path not found")
+	}
+
+	if byteRange == nil {
+		reader, err := uc.storageRepo.GetFile(ctx, *file.StoragePath)
+		if err != nil {
+			return nil, errors.InternalServer("failed to retrieve file from storage")
+		}
+		uc.touchFile(ctx, file)
+		return newVerifyingReader(reader, file.Hash), nil
+	}
+
+	if byteRange.Start < 0 || byteRange.End < byteRange.Start || (file.Size != nil && byteRange.End >= *file.Size) {
+		return nil, errors.BadRequest("invalid byte range")
+	}
+
+	reader, err := uc.storageRepo.GetFileRange(ctx, *file.StoragePath, byteRange.Start, byteRange.End)
 	if err != nil {
-		return nil, nil, errors.InternalServer("failed to retrieve file from storage")
+		return nil, errors.InternalServer("failed to retrieve file range from storage")
 	}
+	uc.touchFile(ctx, file)
+
+	return reader, nil
+}
 
-	return reader, file, nil
+// touchFile records a download for the lifecycle rules. It is best effort,
+// as the download goes ahead either way.
+func (uc *UseCaseFileFolder) touchFile(ctx context.Context, file *domain.File) {
+	_ = uc.fileRepo.TouchFile(ctx, file.CompanyId, file.ID, time.Now())
 }
 
-func (uc *UseCaseFileFolder) GetFileInfo(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+func (uc *UseCaseFileFolder) GetFileInfo(ctx context.Context, companyID, userID, fileID string) (*domain.File, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
 
-	return uc.fileRepo.GetFile(ctx, companyID, fileID)
+	return uc.getFile(ctx, companyID, userID, fileID, domain.AccessViewer)
 }
 
-func (uc *UseCaseFileFolder) RenameFile(ctx context.Context, companyID, fileID, newName string) (*domain.File, error) {
+func (uc *UseCaseFileFolder) RenameFile(ctx context.Context, companyID, userID, fileID, newName string) (*domain.File, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
@@ -269,24 +491,39 @@ func (uc *UseCaseFileFolder) RenameFile(ctx context.Context, companyID, fileID,
 		return nil, errors.BadRequest("new name is required")
 	}
 
+	if !domain.IsValidName(newName) {
+		return nil, errors.BadRequest("new name must not contain path separators or be a dot entry")
+	}
+
+	if _, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessEditor); err != nil {
+		return nil, err
+	}
+
 	return uc.fileRepo.RenameFile(ctx, companyID, fileID, newName)
 }
 
-func (uc *UseCaseFileFolder) MoveFile(ctx context.Context, companyID, fileID string, newParentPath *domain.Path) (*domain.File, error) {
+func (uc *UseCaseFileFolder) MoveFile(ctx context.Context, companyID, userID, fileID string, newParentPath *domain.Path) (*domain.File, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
 
+	if _, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessEditor); err != nil {
+		return nil, err
+	}
+
+	if err := uc.requireWriteInto(ctx, companyID, userID, *newParentPath); err != nil {
+		return nil, err
+	}
+
 	return uc.fileRepo.MoveFile(ctx, companyID, fileID, newParentPath)
 }
 
-func (uc *UseCaseFileFolder) DeleteFile(ctx context.Context, companyID, fileID string) error {
+func (uc *UseCaseFileFolder) DeleteFile(ctx context.Context, companyID, userID, fileID string) error {
 	if companyID == "" {
 		return errors.BadRequest("company ID is required")
 	}
 
-	_, err := uc.fileRepo.GetFile(ctx, companyID, fileID)
-	if err != nil {
+	if _, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessEditor); err != nil {
 		return err
 	}
 
@@ -306,8 +543,8 @@ func (uc *UseCaseFileFolder) InitChunkedUpload(ctx context.Context, companyID, u
 		return nil, errors.BadRequest("user ID is required")
 	}
 
-	if filename == "" {
-		return nil, errors.BadRequest("filename is required")
+	if err := validateFilename(filename); err != nil {
+		return nil, err
 	}
 
 	if fileSize > uc.config.MaxFileSize {
@@ -318,13 +555,35 @@ func (uc *UseCaseFileFolder) InitChunkedUpload(ctx context.Context, companyID, u
 		return nil, errors.BadRequest("file is too small for chunked upload")
 	}
 
+	chunkSize := max(uc.config.ChunkSize, domain.MinMultipartPartSize)
+	totalChunks := int((fileSize + chunkSize - 1) / chunkSize)
+	if totalChunks > domain.MaxMultipartParts {
+		return nil, errors.BadRequest("file requires too many chunks")
+	}
+
+	if err := uc.requireWriteInto(ctx, companyID, userID, *parentPath); err != nil {
+		return nil, err
+	}
+
+	// The content is only checked on completion; the name and the declared
+	// type can be rejected before any chunk is sent.
+	mimeType, err := uc.resolveFileType(ctx, companyID, userID, filename, mimeType, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session reserves the whole file until it completes or expires.
+	if err := uc.checkQuota(ctx, companyID, userID, fileSize, 1); err != nil {
+		return nil, err
+	}
+
 	targetPath := parentPath.Join(filename)
 	upload := &domain.ChunkedUpload{
 		ID:             uuid.NewString(),
 		FileName:       filename,
 		TotalSize:      fileSize,
-		ChunkSize:      uc.config.ChunkSize,
-		TotalChunks:    int((fileSize + uc.config.ChunkSize - 1) / uc.config.ChunkSize),
+		ChunkSize:      chunkSize,
+		TotalChunks:    totalChunks,
 		UploadedChunks: 0,
 		UploadedSize:   0,
 		Status:         domain.ChunkedUploadStatusActive,
@@ -339,23 +598,37 @@ func (uc *UseCaseFileFolder) InitChunkedUpload(ctx context.Context, companyID, u
 		Chunks:         make(map[int]*domain.ChunkInfo),
 	}
 
-	storageKey := generateStorageKey(companyID, upload.ID, filename)
-	storageUploadID, err := uc.storageRepo.InitChunkedUpload(ctx, storageKey, mimeType)
+	upload.StorageKey = generateStorageKey(companyID, upload.ID, filename)
+	storageUploadID, err := uc.storageRepo.InitChunkedUpload(ctx, upload.StorageKey, mimeType)
 	if err != nil {
 		return nil, errors.InternalServer("failed to initialize storage upload")
 	}
 
-	upload.ID = storageUploadID
+	upload.StorageUploadID = storageUploadID
 
-	return uc.chunkedRepo.CreateChunkedUpload(ctx, upload)
+	created, err := uc.chunkedRepo.CreateChunkedUpload(ctx, upload)
+	if err != nil {
+		_ = uc.storageRepo.AbortChunkedUpload(ctx, upload.StorageUploadID, upload.StorageKey)
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (uc *UseCaseFileFolder) UploadChunk(ctx context.Context, companyID, uploadID string, chunkIndex int, chunkData io.Reader, chunkSize int64) (*domain.ChunkedUpload, error) {
+// UploadChunk uploads one chunk as a part of the multipart upload and records
+// it in the session. When checksum is set, the chunk must match it; a chunk
+// that is already uploaded with the same content is not uploaded again.
+func (uc *UseCaseFileFolder) UploadChunk(ctx context.Context, companyID, userID, uploadID string, chunkIndex int, chunkData io.Reader, chunkSize int64, checksum string) (*domain.ChunkedUpload, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
 
-	upload, err := uc.chunkedRepo.GetChunkedUpload(ctx, companyID, uploadID)
+	checksum, err := normalizeChecksum(checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := uc.getChunkedUpload(ctx, companyID, userID, uploadID)
 	if err != nil {
 		return nil, err
 	}
@@ -372,39 +645,74 @@ func (uc *UseCaseFileFolder) UploadChunk(ctx context.Context, companyID, uploadI
 		return nil, errors.BadRequest("invalid chunk index")
 	}
 
-	if chunk, exists := upload.Chunks[chunkIndex]; exists && chunk.Uploaded {
+	if chunk, exists := upload.Chunks[chunkIndex]; exists && chunk.Uploaded && (checksum == "" || checksum == chunk.Checksum) {
 		return upload, nil // Already uploaded
 	}
 
-	storageKey := generateStorageKey(companyID, upload.ID, upload.FileName)
-	etag, err := uc.storageRepo.UploadChunk(ctx, uploadID, storageKey, chunkIndex, chunkData, chunkSize)
+	if chunkSize != upload.ExpectedChunkSize(chunkIndex) {
+		return nil, errors.BadRequest("chunk size does not match the upload session")
+	}
+
+	failed := &domain.ChunkInfo{Index: chunkIndex, Size: chunkSize}
+
+	hashing := newHashingReader(chunkData)
+	etag, err := uc.storageRepo.UploadChunk(ctx, upload.StorageUploadID, upload.StorageKey, chunkIndex, hashing, chunkSize)
 	if err != nil {
+		_ = uc.chunkedRepo.AddChunk(ctx, upload.ID, failed)
 		return nil, errors.InternalServer("failed to upload chunk to storage")
 	}
 
-	upload.AddChunk(chunkIndex, chunkSize, etag)
+	hash := hashing.Sum()
+	if checksum != "" && hash != checksum {
+		_ = uc.chunkedRepo.AddChunk(ctx, upload.ID, failed)
+		return nil, errors.BadRequest("checksum mismatch: uploaded chunk is corrupted")
+	}
 
-	return uc.chunkedRepo.UpdateChunkedUpload(ctx, upload)
+	err = uc.chunkedRepo.AddChunk(ctx, upload.ID, &domain.ChunkInfo{
+		Index:      chunkIndex,
+		Size:       chunkSize,
+		ETag:       etag,
+		Checksum:   hash,
+		Uploaded:   true,
+		UploadedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.chunkedRepo.GetChunkedUpload(ctx, companyID, uploadID)
 }
 
-func (uc *UseCaseFileFolder) GetChunkedUploadStatus(ctx context.Context, companyID, uploadID string) (*domain.ChunkedUpload, error) {
+func (uc *UseCaseFileFolder) GetChunkedUploadStatus(ctx context.Context, companyID, userID, uploadID string) (*domain.ChunkedUpload, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
 
-	return uc.chunkedRepo.GetChunkedUpload(ctx, companyID, uploadID)
+	return uc.getChunkedUpload(ctx, companyID, userID, uploadID)
 }
 
-func (uc *UseCaseFileFolder) CompleteChunkedUpload(ctx context.Context, companyID, uploadID string) (*domain.File, error) {
+// CompleteChunkedUpload assembles the uploaded chunks into the final file. When
+// checksum is set, the assembled content must match it.
+func (uc *UseCaseFileFolder) CompleteChunkedUpload(ctx context.Context, companyID, userID, uploadID, checksum string) (*domain.File, error) {
 	if companyID == "" {
 		return nil, errors.BadRequest("company ID is required")
 	}
 
-	upload, err := uc.chunkedRepo.GetChunkedUpload(ctx, companyID, uploadID)
+	checksum, err := normalizeChecksum(checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := uc.getChunkedUpload(ctx, companyID, userID, uploadID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Access to the folder may have been withdrawn while the chunks were uploading.
+	if err := uc.requireWriteInto(ctx, companyID, userID, upload.TargetPath.GetParent()); err != nil {
+		return nil, err
+	}
+
 	if !upload.IsComplete() {
 		return nil, errors.BadRequest("upload is not complete")
 	}
@@ -418,22 +726,51 @@ func (uc *UseCaseFileFolder) CompleteChunkedUpload(ctx context.Context, companyI
 		parts[i] = chunk.ETag
 	}
 
-	storageKey := generateStorageKey(companyID, upload.ID, upload.FileName)
-	err = uc.storageRepo.CompleteChunkedUpload(ctx, uploadID, storageKey, parts)
+	storageKey := upload.StorageKey
+	err = uc.storageRepo.CompleteChunkedUpload(ctx, upload.StorageUploadID, storageKey, parts)
 	if err != nil {
 		return nil, errors.InternalServer("failed to complete storage upload")
 	}
 
+	hash, err := uc.hashObject(ctx, storageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum != "" && hash != checksum {
+		_ = uc.storageRepo.DeleteFile(ctx, storageKey)
+		upload.MarkAsFailed()
+		_, _ = uc.chunkedRepo.UpdateChunkedUpload(ctx, upload)
+		return nil, errors.BadRequest("checksum mismatch: uploaded content is corrupted")
+	}
+
+	head, err := uc.objectHead(ctx, storageKey, upload.TotalSize)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType, err := uc.resolveFileType(ctx, companyID, userID, upload.FileName, upload.MimeType, head)
+	if err != nil {
+		_ = uc.storageRepo.DeleteFile(ctx, storageKey)
+		upload.MarkAsFailed()
+		_, _ = uc.chunkedRepo.UpdateChunkedUpload(ctx, upload)
+		return nil, err
+	}
+	upload.MimeType = mimeType
+
 	file := &domain.File{
 		ID:           uuid.NewString(),
 		Name:         upload.FileName,
 		Type:         domain.FileTypeFile,
 		FullPath:     upload.TargetPath,
+		ParentID:     uc.parentFolderID(ctx, upload.CompanyID, upload.TargetPath.GetParent()),
 		CompanyId:    upload.CompanyID,
 		UserCreateID: upload.UserCreateID,
 		MimeType:     &upload.MimeType,
 		Size:         &upload.TotalSize,
+		Hash:         &hash,
 		StoragePath:  &storageKey,
+		ScanStatus:   uc.initialScanStatus(),
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 		IsActive:     true,
@@ -442,20 +779,33 @@ func (uc *UseCaseFileFolder) CompleteChunkedUpload(ctx context.Context, companyI
 	upload.MarkAsCompleted()
 	_, _ = uc.chunkedRepo.UpdateChunkedUpload(ctx, upload)
 
-	return uc.fileRepo.CreateFile(ctx, file)
+	created, err := uc.fileRepo.CreateFile(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = uc.versionRepo.CreateVersion(ctx, domain.NewFileVersion(created, upload.UserCreateID))
+	uc.queueScan(ctx, created)
+	uc.grantDefaultAccess(ctx, created)
+
+	return created, nil
 }
 
-func (uc *UseCaseFileFolder) AbortChunkedUpload(ctx context.Context, companyID, uploadID string) error {
+func (uc *UseCaseFileFolder) AbortChunkedUpload(ctx context.Context, companyID, userID, uploadID string) error {
 	if companyID == "" {
 		return errors.BadRequest("company ID is required")
 	}
 
-	upload, err := uc.chunkedRepo.GetChunkedUpload(ctx, companyID, uploadID)
+	upload, err := uc.getChunkedUpload(ctx, companyID, userID, uploadID)
 	if err != nil {
 		return err
 	}
 
-	_ = generateStorageKey(companyID, upload.ID, upload.FileName)
+	if upload.Status == domain.ChunkedUploadStatusActive && upload.StorageUploadID != "" {
+		if err := uc.storageRepo.AbortChunkedUpload(ctx, upload.StorageUploadID, upload.StorageKey); err != nil {
+			return err
+		}
+	}
 
 	return uc.chunkedRepo.DeleteChunkedUpload(ctx, companyID, uploadID)
 }
@@ -464,29 +814,73 @@ func (uc *UseCaseFileFolder) GetResourceStats(ctx context.Context) (*domain.Reso
 	return uc.resourceMonitor.GetResourceStats(), nil
 }
 
-func determineMimeType(filename string) string {
-	ext := strings.ToLower(filepath.Ext(filename))
+// getFile returns a file or folder the user has at least the required role on.
+func (uc *UseCaseFileFolder) getFile(ctx context.Context, companyID, userID, fileID string, required domain.AccessRole) (*domain.File, error) {
+	file, err := uc.fileRepo.GetFile(ctx, companyID, fileID)
+	if err != nil {
+		return nil, err
+	}
 
-	mimeTypes := map[string]string{
-		".txt":  "text/plain",
-		".pdf":  "application/pdf",
-		".jpg":  "image/jpeg",
-		".jpeg": "image/jpeg",
-		".png":  "image/png",
-		".gif":  "image/gif",
-		".mp4":  "video/mp4",
-		".zip":  "application/zip",
-		".json": "application/json",
-		".xml":  "application/xml",
+	if err := uc.requireAccess(ctx, companyID, userID, file.FullPath, required, "file not found"); err != nil {
+		return nil, err
 	}
 
-	if mimeType, exists := mimeTypes[ext]; exists {
-		return mimeType
+	return file, nil
+}
+
+// getChunkedUpload returns an upload session started by the user. Sessions
+// of other users are not found.
+func (uc *UseCaseFileFolder) getChunkedUpload(ctx context.Context, companyID, userID, uploadID string) (*domain.ChunkedUpload, error) {
+	upload, err := uc.chunkedRepo.GetChunkedUpload(ctx, companyID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.UserCreateID != userID {
+		return nil, errors.NotFound("chunked upload session not found")
 	}
 
-	return "application/octet-stream"
+	return upload, nil
+}
+
+// parentFolderID returns the ID of the folder at path, or nil for the root and
+// for paths no folder exists at.
+func (uc *UseCaseFileFolder) parentFolderID(ctx context.Context, companyID string, path domain.Path) *string {
+	if path.IsRoot() {
+		return nil
+	}
+
+	folder, err := uc.fileRepo.GetFileByPath(ctx, companyID, &path)
+	if err != nil || folder.Type != domain.FileTypeFolder {
+		return nil
+	}
+
+	return &folder.ID
+}
+
+// queueContentIndex asks for the text of a file to be extracted for content
+// search. Indexing is best effort and never fails the upload; a company
+// reindex catches up on anything missed.
+func (uc *UseCaseFileFolder) queueContentIndex(ctx context.Context, file *domain.File) {
+	_ = uc.contentRepo.EnqueueFile(ctx, file.CompanyId, file.ID)
+}
+
+// validateFilename rejects names that would place the file outside the folder
+// it is uploaded to.
+func validateFilename(filename string) error {
+	if filename == "" {
+		return errors.BadRequest("filename is required")
+	}
+	if !domain.IsValidName(filename) {
+		return errors.BadRequest("filename must not contain path separators or be a dot entry")
+	}
+	return nil
 }
 
 func generateStorageKey(companyID, fileID, filename string) string {
 	return fmt.Sprintf("companies/%s/files/%s/%s", companyID, fileID, filename)
 }
+
+func generateRenditionKey(companyID, fileID string, size domain.RenditionSize) string {
+	return fmt.Sprintf("companies/%s/renditions/%s/%s", companyID, fileID, size)
+}
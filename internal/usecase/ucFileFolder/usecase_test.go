@@ -0,0 +1,75 @@
+package ucFileFolder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type accessRepoMock struct {
+	AccessRepository
+	mock.Mock
+}
+
+func (m *accessRepoMock) GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error) {
+	args := m.Called(ctx, companyID, userID, path)
+	return args.Get(0).(domain.AccessRole), args.Error(1)
+}
+
+func (m *chunkedRepoMock) GetChunkedUpload(ctx context.Context, companyID, uploadID string) (*domain.ChunkedUpload, error) {
+	args := m.Called(ctx, companyID, uploadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ChunkedUpload), args.Error(1)
+}
+
+func TestUpload_RejectsNamesLeavingTheFolder(t *testing.T) {
+	uc := NewUseCaseFileFolder(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &config.FileServer{
+		MaxFileSize:         1 << 30,
+		MediumFileThreshold: 1,
+	})
+	parent := domain.Path("/mine")
+
+	for _, name := range []string{"../secret/x", "sub/x", `sub\x`, "..", ".", "/x"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.InitChunkedUpload(context.Background(), "company-id", "user-id", name, 1<<20, &parent, "")
+			assert.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+			_, err = uc.UploadFile(context.Background(), "company-id", "user-id", &parent, name, 4, strings.NewReader("data"), "")
+			assert.ErrorIs(t, err, errors.ErrInvalidRequest)
+
+			_, err = uc.InitPresignedUpload(context.Background(), "company-id", "user-id", &parent, name, 4, "")
+			assert.ErrorIs(t, err, errors.ErrInvalidRequest)
+		})
+	}
+}
+
+func TestCompleteChunkedUpload_RechecksTargetFolder(t *testing.T) {
+	chunkedRepo := new(chunkedRepoMock)
+	accessRepo := new(accessRepoMock)
+	uc := NewUseCaseFileFolder(nil, nil, chunkedRepo, nil, nil, nil, nil, nil, nil, nil, nil, accessRepo, nil, nil, nil, &config.FileServer{})
+
+	// A session started before names were validated, whose file lands
+	// outside the folder it was started in.
+	chunkedRepo.On("GetChunkedUpload", mock.Anything, "company-id", "upload-id").Return(&domain.ChunkedUpload{
+		ID:           "upload-id",
+		CompanyID:    "company-id",
+		UserCreateID: "user-id",
+		ParentPath:   domain.Path("/mine"),
+		TargetPath:   domain.Path("/secret/x"),
+	}, nil)
+	accessRepo.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/secret")).Return(domain.AccessNone, nil)
+
+	_, err := uc.CompleteChunkedUpload(context.Background(), "company-id", "user-id", "upload-id", "")
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	accessRepo.AssertExpectations(t)
+	accessRepo.AssertNotCalled(t, "GetAccessRole", mock.Anything, mock.Anything, mock.Anything, domain.Path("/mine"))
+}
@@ -0,0 +1,253 @@
+package ucFileFolder
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+// UploadFileVersion stores the upload as a new version of the file at parentPath/filename.
+// When no such file exists yet, it behaves like a regular UploadFile.
+func (uc *UseCaseFileFolder) UploadFileVersion(ctx context.Context, companyID, userID string, parentPath *domain.Path, filename string, size int64, reader io.Reader, checksum string) (*domain.File, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	if err := validateFilename(filename); err != nil {
+		return nil, err
+	}
+
+	targetPath := parentPath.Join(filename)
+	file, err := uc.fileRepo.GetFileByPath(ctx, companyID, &targetPath)
+	if stdErrors.Is(err, errors.ErrNotFound) {
+		return uc.UploadFile(ctx, companyID, userID, parentPath, filename, size, reader, checksum)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.requireAccess(ctx, companyID, userID, file.FullPath, domain.AccessEditor, "file not found"); err != nil {
+		return nil, err
+	}
+
+	if file.Type != domain.FileTypeFile {
+		return nil, errors.BadRequest("folder with this name already exists")
+	}
+
+	if size > uc.config.MaxFileSize {
+		return nil, errors.BadRequest("file size exceeds maximum allowed size")
+	}
+
+	checksum, err = normalizeChecksum(checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	head, reader := peekHead(reader)
+	mimeType, err := uc.resolveFileType(ctx, companyID, userID, filename, "", head)
+	if err != nil {
+		return nil, err
+	}
+
+	// The file keeps its creator, so the new size counts against that user.
+	if err := uc.checkQuota(ctx, companyID, file.UserCreateID, size-fileSize(file), 0); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureVersionHistory(ctx, file); err != nil {
+		return nil, err
+	}
+
+	version := &domain.FileVersion{
+		ID:           uuid.NewString(),
+		FileID:       file.ID,
+		CompanyID:    companyID,
+		UserCreateID: userID,
+		MimeType:     mimeType,
+		Size:         size,
+	}
+	version.StoragePath = generateVersionStorageKey(companyID, file.ID, version.ID, file.Name)
+
+	pending := *file
+	version.ApplyTo(&pending)
+	hash, err := uc.storeObject(ctx, &pending, userID, reader, size, version.StoragePath, checksum)
+	if err != nil {
+		return nil, err
+	}
+	version.Hash = &hash
+
+	updated, err := uc.promoteVersion(ctx, file, version)
+	if err != nil {
+		// Nothing refers to the object once the version was not recorded.
+		_ = uc.storageRepo.DeleteFile(ctx, version.StoragePath)
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (uc *UseCaseFileFolder) GetFileVersions(ctx context.Context, companyID, userID, fileID string) ([]*domain.FileVersion, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	file, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Type != domain.FileTypeFile {
+		return nil, errors.BadRequest("specified ID is not a file")
+	}
+
+	return uc.versionRepo.GetVersions(ctx, companyID, fileID)
+}
+
+// DownloadFileVersion returns the content of a specific version together with
+// a copy of the file whose size, type and storage path describe that version.
+func (uc *UseCaseFileFolder) DownloadFileVersion(ctx context.Context, companyID, userID, fileID string, version int) (io.ReadCloser, *domain.File, error) {
+	if companyID == "" {
+		return nil, nil, errors.BadRequest("company ID is required")
+	}
+
+	file, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessViewer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fileVersion, err := uc.versionRepo.GetVersion(ctx, companyID, fileID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.checkVersionScanStatus(file, fileVersion); err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := uc.storageRepo.GetFile(ctx, fileVersion.StoragePath)
+	if err != nil {
+		return nil, nil, errors.InternalServer("failed to retrieve file version from storage")
+	}
+
+	versioned := *file
+	fileVersion.ApplyTo(&versioned)
+	versioned.UpdatedAt = fileVersion.CreatedAt
+
+	return newVerifyingReader(reader, fileVersion.Hash), &versioned, nil
+}
+
+// RestoreFileVersion makes an old version current again by appending it as the
+// newest version, so the history itself is never rewritten.
+func (uc *UseCaseFileFolder) RestoreFileVersion(ctx context.Context, companyID, userID, fileID string, version int) (*domain.File, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	file, err := uc.getFile(ctx, companyID, userID, fileID, domain.AccessEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := uc.versionRepo.GetVersion(ctx, companyID, fileID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.IsQuarantineKey(source.StoragePath) {
+		return nil, errors.Forbidden("version is quarantined: malware was detected")
+	}
+
+	if err := uc.checkQuota(ctx, companyID, file.UserCreateID, source.Size-fileSize(file), 0); err != nil {
+		return nil, err
+	}
+
+	restored := &domain.FileVersion{
+		ID:           uuid.NewString(),
+		FileID:       file.ID,
+		CompanyID:    companyID,
+		UserCreateID: userID,
+		MimeType:     source.MimeType,
+		Size:         source.Size,
+		Hash:         source.Hash,
+		StoragePath:  source.StoragePath,
+	}
+
+	return uc.promoteVersion(ctx, file, restored)
+}
+
+func (uc *UseCaseFileFolder) promoteVersion(ctx context.Context, file *domain.File, version *domain.FileVersion) (*domain.File, error) {
+	version.CreatedAt = time.Now()
+
+	promoted := *file
+	version.ApplyTo(&promoted)
+	promoted.UpdatedAt = version.CreatedAt
+	promoted.ScanStatus = uc.initialScanStatus()
+
+	updated, err := uc.versionRepo.PromoteVersion(ctx, &promoted, version)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.pruneVersions(ctx, file.CompanyId, file.ID)
+	uc.queueScan(ctx, updated)
+
+	return updated, nil
+}
+
+// ensureVersionHistory records the current content of files uploaded before
+// versioning existed, so that appending a version never loses it.
+func (uc *UseCaseFileFolder) ensureVersionHistory(ctx context.Context, file *domain.File) error {
+	versions, err := uc.versionRepo.GetVersions(ctx, file.CompanyId, file.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) > 0 || file.StoragePath == nil {
+		return nil
+	}
+
+	_, err = uc.versionRepo.CreateVersion(ctx, domain.NewFileVersion(file, file.UserCreateID))
+	return err
+}
+
+func (uc *UseCaseFileFolder) pruneVersions(ctx context.Context, companyID, fileID string) {
+	if uc.config.MaxFileVersions <= 0 {
+		return
+	}
+
+	versions, err := uc.versionRepo.GetVersions(ctx, companyID, fileID)
+	if err != nil || len(versions) <= uc.config.MaxFileVersions {
+		return
+	}
+
+	for _, version := range versions[uc.config.MaxFileVersions:] {
+		if err := uc.versionRepo.DeleteVersion(ctx, companyID, version.ID); err != nil {
+			continue
+		}
+
+		refs, err := uc.versionRepo.CountStoragePathRefs(ctx, version.StoragePath)
+		if err != nil || refs > 0 {
+			continue
+		}
+
+		_ = uc.storageRepo.DeleteFile(ctx, version.StoragePath)
+	}
+}
+
+func generateVersionStorageKey(companyID, fileID, versionID, filename string) string {
+	return fmt.Sprintf("companies/%s/files/%s/versions/%s/%s", companyID, fileID, versionID, filename)
+}
@@ -0,0 +1,114 @@
+package ucFileFolder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/internal/repository/localfs"
+	"go-storage/pkg/errors"
+)
+
+type fileRepoMock struct {
+	RepositoryFileFolder
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error) {
+	args := m.Called(ctx, companyID, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func TestUploadFileVersion_LookupError(t *testing.T) {
+	fileRepo := new(fileRepoMock)
+	uc := NewUseCaseFileFolder(fileRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &config.FileServer{})
+
+	fileRepo.On("GetFileByPath", mock.Anything, "company-id", mock.Anything).
+		Return(nil, errors.Database("unable to get file by path"))
+
+	root, err := domain.NewPath("/")
+	require.NoError(t, err)
+	file, err := uc.UploadFileVersion(context.Background(), "company-id", "user-id", &root, "report.txt", 4, strings.NewReader("data"), "")
+
+	// A failed lookup must not be taken for a missing file and start a new upload.
+	assert.ErrorIs(t, err, errors.ErrDatabase)
+	assert.Nil(t, file)
+	fileRepo.AssertNumberOfCalls(t, "GetFileByPath", 1)
+}
+
+type quotaRepoMock struct {
+	QuotaRepository
+}
+
+func (m *quotaRepoMock) GetCompanyUsage(ctx context.Context, companyID string) (*domain.StorageUsage, error) {
+	return &domain.StorageUsage{}, nil
+}
+
+func (m *quotaRepoMock) GetUserUsage(ctx context.Context, companyID, userID string) (*domain.StorageUsage, error) {
+	return &domain.StorageUsage{}, nil
+}
+
+type policyRepoMock struct {
+	FileTypePolicyRepository
+}
+
+func (m *policyRepoMock) GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error) {
+	return &domain.FileTypePolicy{}, nil
+}
+
+func (m *versionRepoMock) GetVersions(ctx context.Context, companyID, fileID string) ([]*domain.FileVersion, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FileVersion), args.Error(1)
+}
+
+func (m *versionRepoMock) PromoteVersion(ctx context.Context, file *domain.File, version *domain.FileVersion) (*domain.File, error) {
+	args := m.Called(ctx, file, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func TestUploadFileVersion_DeletesObjectWhenNotRecorded(t *testing.T) {
+	storage, err := localfs.NewStorageRepository(t.TempDir())
+	require.NoError(t, err)
+
+	fileRepo := new(fileRepoMock)
+	versionRepo := new(versionRepoMock)
+	accessRepo := new(accessRepoMock)
+	uc := NewUseCaseFileFolder(fileRepo, storage, nil, versionRepo, nil, nil, nil, nil, nil, nil, new(quotaRepoMock), accessRepo, nil, new(policyRepoMock), nil, &config.FileServer{
+		MaxFileSize:          1 << 20,
+		MediumFileThreshold:  1 << 20,
+		MaxConcurrentUploads: 1,
+	})
+
+	fileRepo.On("GetFileByPath", mock.Anything, "company-id", mock.Anything).Return(&domain.File{
+		ID: "file-id", Name: "report.txt", Type: domain.FileTypeFile, FullPath: "/report.txt",
+		CompanyId: "company-id", UserCreateID: "user-id",
+	}, nil)
+	accessRepo.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/report.txt")).Return(domain.AccessOwner, nil)
+	versionRepo.On("GetVersions", mock.Anything, "company-id", "file-id").Return([]*domain.FileVersion{{Version: 1}}, nil)
+	versionRepo.On("PromoteVersion", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.Conflict("file is being updated concurrently, try again"))
+
+	root := domain.Path("/")
+	file, err := uc.UploadFileVersion(context.Background(), "company-id", "user-id", &root, "report.txt", 4, strings.NewReader("data"), "")
+
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.Nil(t, file)
+
+	version := versionRepo.Calls[1].Arguments.Get(2).(*domain.FileVersion)
+	_, err = storage.GetFileInfo(context.Background(), version.StoragePath)
+	assert.ErrorIs(t, err, errors.ErrNotFound, "the stored object is deleted")
+}
@@ -0,0 +1,22 @@
+package ucFileMetadata
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type FileRepository interface {
+	GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
+}
+
+type MetadataRepository interface {
+	GetMetadata(ctx context.Context, companyID, fileID string) (*domain.FileMetadata, error)
+	SaveMetadata(ctx context.Context, metadata *domain.FileMetadata) error
+	DeleteMetadata(ctx context.Context, companyID, fileID string) error
+	UpdateTags(ctx context.Context, companyID string, fileIDs, add, remove []string, maxTags int) (int, error)
+	ListTags(ctx context.Context, companyID string) ([]*domain.TagCount, error)
+}
+
+type AccessRepository interface {
+	GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error)
+}
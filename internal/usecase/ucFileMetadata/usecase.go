@@ -0,0 +1,202 @@
+package ucFileMetadata
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+// maxBulkTagItems bounds the number of items tagged by one request.
+const maxBulkTagItems = 1000
+
+type UseCaseFileMetadata struct {
+	fileRepo     FileRepository
+	metadataRepo MetadataRepository
+	accessRepo   AccessRepository
+}
+
+func NewUseCaseFileMetadata(fileRepo FileRepository, metadataRepo MetadataRepository, accessRepo AccessRepository) *UseCaseFileMetadata {
+	return &UseCaseFileMetadata{
+		fileRepo:     fileRepo,
+		metadataRepo: metadataRepo,
+		accessRepo:   accessRepo,
+	}
+}
+
+// GetMetadata returns the tags and key/value pairs of a file or folder.
+func (uc *UseCaseFileMetadata) GetMetadata(ctx context.Context, companyID, userID, fileID string) (*domain.FileMetadata, error) {
+	if err := uc.checkFile(ctx, companyID, userID, fileID, domain.AccessViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.metadataRepo.GetMetadata(ctx, companyID, fileID)
+}
+
+// ReplaceMetadata sets the tags and key/value pairs of an item, dropping the previous ones.
+func (uc *UseCaseFileMetadata) ReplaceMetadata(ctx context.Context, companyID, userID, fileID string, metadata *domain.FileMetadata) (*domain.FileMetadata, error) {
+	if err := uc.checkFile(ctx, companyID, userID, fileID, domain.AccessEditor); err != nil {
+		return nil, err
+	}
+
+	metadata.FileID = fileID
+	metadata.CompanyID = companyID
+	if err := metadata.Normalize(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	return uc.save(ctx, metadata)
+}
+
+// UpdateMetadata adds and removes tags and sets or removes keys, leaving the rest as is.
+func (uc *UseCaseFileMetadata) UpdateMetadata(ctx context.Context, companyID, userID, fileID string, patch *domain.MetadataPatch) (*domain.FileMetadata, error) {
+	if err := uc.checkFile(ctx, companyID, userID, fileID, domain.AccessEditor); err != nil {
+		return nil, err
+	}
+
+	metadata, err := uc.metadataRepo.GetMetadata(ctx, companyID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := metadata.Apply(patch); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	return uc.save(ctx, metadata)
+}
+
+func (uc *UseCaseFileMetadata) DeleteMetadata(ctx context.Context, companyID, userID, fileID string) error {
+	if err := uc.checkFile(ctx, companyID, userID, fileID, domain.AccessEditor); err != nil {
+		return err
+	}
+
+	return uc.metadataRepo.DeleteMetadata(ctx, companyID, fileID)
+}
+
+// BulkUpdateTags adds and removes tags on several items and returns how many
+// were updated. Unknown IDs, items the user may not edit and items that would
+// exceed MaxFileTags are skipped.
+func (uc *UseCaseFileMetadata) BulkUpdateTags(ctx context.Context, companyID, userID string, fileIDs, add, remove []string) (int, error) {
+	if companyID == "" {
+		return 0, errors.BadRequest("company ID is required")
+	}
+	if userID == "" {
+		return 0, errors.BadRequest("user ID is required")
+	}
+	if len(fileIDs) == 0 {
+		return 0, errors.BadRequest("at least one item is required")
+	}
+	if len(fileIDs) > maxBulkTagItems {
+		return 0, errors.BadRequest("too many items in one request")
+	}
+
+	add, err := domain.NormalizeTags(add)
+	if err != nil {
+		return 0, errors.BadRequest(err.Error())
+	}
+	remove, err = domain.NormalizeTags(remove)
+	if err != nil {
+		return 0, errors.BadRequest(err.Error())
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, errors.BadRequest("no tags to add or remove")
+	}
+	if len(add) > domain.MaxFileTags {
+		return 0, errors.BadRequest("too many tags to add")
+	}
+	for _, tag := range add {
+		for _, removed := range remove {
+			if tag == removed {
+				return 0, errors.BadRequest("tag " + tag + " is both added and removed")
+			}
+		}
+	}
+
+	editable, err := uc.editableFiles(ctx, companyID, userID, fileIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(editable) == 0 {
+		return 0, nil
+	}
+
+	return uc.metadataRepo.UpdateTags(ctx, companyID, editable, add, remove, domain.MaxFileTags)
+}
+
+// ListTags returns the tags in use in the company with the number of items carrying them.
+func (uc *UseCaseFileMetadata) ListTags(ctx context.Context, companyID string) ([]*domain.TagCount, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.metadataRepo.ListTags(ctx, companyID)
+}
+
+// checkFile fails unless the user has at least the required role on the item.
+// Items the user has no access to at all are not found.
+func (uc *UseCaseFileMetadata) checkFile(ctx context.Context, companyID, userID, fileID string, required domain.AccessRole) error {
+	if companyID == "" {
+		return errors.BadRequest("company ID is required")
+	}
+	if userID == "" {
+		return errors.BadRequest("user ID is required")
+	}
+
+	role, err := uc.getAccessRole(ctx, companyID, userID, fileID)
+	if err != nil {
+		return err
+	}
+
+	if role == domain.AccessNone {
+		return errors.NotFound("file not found")
+	}
+	if !role.Allows(required) {
+		return errors.Forbidden(fmt.Sprintf("%s access is required", required))
+	}
+
+	return nil
+}
+
+// editableFiles returns the IDs of the items the user may edit, dropping
+// unknown IDs along with the rest.
+func (uc *UseCaseFileMetadata) editableFiles(ctx context.Context, companyID, userID string, fileIDs []string) ([]string, error) {
+	editable := make([]string, 0, len(fileIDs))
+	for _, fileID := range fileIDs {
+		role, err := uc.getAccessRole(ctx, companyID, userID, fileID)
+		if err != nil {
+			if stdErrors.Is(err, errors.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		if role.Allows(domain.AccessEditor) {
+			editable = append(editable, fileID)
+		}
+	}
+
+	return editable, nil
+}
+
+func (uc *UseCaseFileMetadata) getAccessRole(ctx context.Context, companyID, userID, fileID string) (domain.AccessRole, error) {
+	file, err := uc.fileRepo.GetFile(ctx, companyID, fileID)
+	if err != nil {
+		return domain.AccessNone, err
+	}
+
+	return uc.accessRepo.GetAccessRole(ctx, companyID, userID, file.FullPath)
+}
+
+func (uc *UseCaseFileMetadata) save(ctx context.Context, metadata *domain.FileMetadata) (*domain.FileMetadata, error) {
+	metadata.UpdatedAt = time.Now()
+
+	if err := uc.metadataRepo.SaveMetadata(ctx, metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,284 @@
+package ucFileMetadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	customErrors "go-storage/pkg/errors"
+)
+
+type fileRepoMock struct {
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+type accessRepoMock struct {
+	mock.Mock
+}
+
+func (m *accessRepoMock) GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error) {
+	args := m.Called(ctx, companyID, userID, path)
+	return args.Get(0).(domain.AccessRole), args.Error(1)
+}
+
+type metadataRepoMock struct {
+	mock.Mock
+}
+
+func (m *metadataRepoMock) GetMetadata(ctx context.Context, companyID, fileID string) (*domain.FileMetadata, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileMetadata), args.Error(1)
+}
+
+func (m *metadataRepoMock) SaveMetadata(ctx context.Context, metadata *domain.FileMetadata) error {
+	args := m.Called(ctx, metadata)
+	return args.Error(0)
+}
+
+func (m *metadataRepoMock) DeleteMetadata(ctx context.Context, companyID, fileID string) error {
+	args := m.Called(ctx, companyID, fileID)
+	return args.Error(0)
+}
+
+func (m *metadataRepoMock) UpdateTags(ctx context.Context, companyID string, fileIDs, add, remove []string, maxTags int) (int, error) {
+	args := m.Called(ctx, companyID, fileIDs, add, remove, maxTags)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *metadataRepoMock) ListTags(ctx context.Context, companyID string) ([]*domain.TagCount, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TagCount), args.Error(1)
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := err.(*customErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, status, appErr.Code)
+}
+
+func strPtr(s string) *string { return &s }
+
+// ownerAccess grants the owner role on every item.
+func ownerAccess() *accessRepoMock {
+	access := new(accessRepoMock)
+	access.On("GetAccessRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(domain.AccessOwner, nil)
+	return access
+}
+
+func TestReplaceMetadata_NormalizesTags(t *testing.T) {
+	files := new(fileRepoMock)
+	metadata := new(metadataRepoMock)
+	uc := NewUseCaseFileMetadata(files, metadata, ownerAccess())
+
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(&domain.File{ID: "file-id"}, nil)
+	metadata.On("SaveMetadata", mock.Anything, mock.MatchedBy(func(m *domain.FileMetadata) bool {
+		return m.FileID == "file-id" && m.CompanyID == "company-id" &&
+			assert.ObjectsAreEqual([]string{"q3", "review"}, m.Tags) && m.Metadata["project"] == "PRJ-42"
+	})).Return(nil)
+
+	result, err := uc.ReplaceMetadata(context.Background(), "company-id", "user-id", "file-id", &domain.FileMetadata{
+		Tags:     []string{" Review", "Q3", "q3"},
+		Metadata: map[string]string{"project": "PRJ-42"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"q3", "review"}, result.Tags)
+	metadata.AssertExpectations(t)
+}
+
+func TestReplaceMetadata_TooLongTag(t *testing.T) {
+	files := new(fileRepoMock)
+	metadata := new(metadataRepoMock)
+	uc := NewUseCaseFileMetadata(files, metadata, ownerAccess())
+
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(&domain.File{ID: "file-id"}, nil)
+
+	longTag := make([]byte, domain.MaxTagLength+1)
+	for i := range longTag {
+		longTag[i] = 'a'
+	}
+	_, err := uc.ReplaceMetadata(context.Background(), "company-id", "user-id", "file-id", &domain.FileMetadata{Tags: []string{string(longTag)}})
+
+	assertStatus(t, err, 400)
+	metadata.AssertNotCalled(t, "SaveMetadata", mock.Anything, mock.Anything)
+}
+
+func TestUpdateMetadata_MergesChanges(t *testing.T) {
+	files := new(fileRepoMock)
+	metadata := new(metadataRepoMock)
+	uc := NewUseCaseFileMetadata(files, metadata, ownerAccess())
+
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(&domain.File{ID: "file-id"}, nil)
+	metadata.On("GetMetadata", mock.Anything, "company-id", "file-id").Return(&domain.FileMetadata{
+		FileID:    "file-id",
+		CompanyID: "company-id",
+		Tags:      []string{"draft", "q3"},
+		Metadata:  map[string]string{"status": "draft", "owner": "finance"},
+	}, nil)
+	metadata.On("SaveMetadata", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := uc.UpdateMetadata(context.Background(), "company-id", "user-id", "file-id", &domain.MetadataPatch{
+		AddTags:    []string{"Final"},
+		RemoveTags: []string{"DRAFT"},
+		Metadata:   map[string]*string{"status": strPtr("final"), "owner": nil},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"final", "q3"}, result.Tags)
+	assert.Equal(t, map[string]string{"status": "final"}, result.Metadata)
+}
+
+func TestGetMetadata_FileNotFound(t *testing.T) {
+	files := new(fileRepoMock)
+	metadata := new(metadataRepoMock)
+	uc := NewUseCaseFileMetadata(files, metadata, ownerAccess())
+
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(nil, customErrors.NotFound("file not found"))
+
+	_, err := uc.GetMetadata(context.Background(), "company-id", "user-id", "file-id")
+
+	assertStatus(t, err, 404)
+	metadata.AssertNotCalled(t, "GetMetadata", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkUpdateTags_Success(t *testing.T) {
+	files := new(fileRepoMock)
+	metadata := new(metadataRepoMock)
+	uc := NewUseCaseFileMetadata(files, metadata, ownerAccess())
+
+	files.On("GetFile", mock.Anything, "company-id", "a").Return(&domain.File{ID: "a"}, nil)
+	files.On("GetFile", mock.Anything, "company-id", "b").Return(&domain.File{ID: "b"}, nil)
+	metadata.On("UpdateTags", mock.Anything, "company-id", []string{"a", "b"}, []string{"q3"}, []string{"draft"}, domain.MaxFileTags).
+		Return(2, nil)
+
+	updated, err := uc.BulkUpdateTags(context.Background(), "company-id", "user-id", []string{"a", "b"}, []string{"Q3"}, []string{"draft"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated)
+}
+
+func TestBulkUpdateTags_SkipsItemsWithoutEditAccess(t *testing.T) {
+	files := new(fileRepoMock)
+	metadata := new(metadataRepoMock)
+	access := new(accessRepoMock)
+	uc := NewUseCaseFileMetadata(files, metadata, access)
+
+	editable, _ := domain.NewPath("/team/plan.txt")
+	viewable, _ := domain.NewPath("/board/minutes.txt")
+	files.On("GetFile", mock.Anything, "company-id", "editable").Return(&domain.File{ID: "editable", FullPath: editable}, nil)
+	files.On("GetFile", mock.Anything, "company-id", "viewable").Return(&domain.File{ID: "viewable", FullPath: viewable}, nil)
+	files.On("GetFile", mock.Anything, "company-id", "missing").Return(nil, customErrors.NotFound("file not found"))
+	access.On("GetAccessRole", mock.Anything, "company-id", "user-id", editable).Return(domain.AccessEditor, nil)
+	access.On("GetAccessRole", mock.Anything, "company-id", "user-id", viewable).Return(domain.AccessViewer, nil)
+	metadata.On("UpdateTags", mock.Anything, "company-id", []string{"editable"}, []string{"q3"}, []string{}, domain.MaxFileTags).
+		Return(1, nil)
+
+	updated, err := uc.BulkUpdateTags(context.Background(), "company-id", "user-id", []string{"editable", "viewable", "missing"}, []string{"q3"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	metadata.AssertExpectations(t)
+}
+
+func TestMetadata_RequiresAccess(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   domain.AccessRole
+		call   func(uc *UseCaseFileMetadata) error
+		status int
+	}{
+		{
+			name: "no access to read",
+			role: domain.AccessNone,
+			call: func(uc *UseCaseFileMetadata) error {
+				_, err := uc.GetMetadata(context.Background(), "company-id", "user-id", "file-id")
+				return err
+			},
+			status: 404,
+		},
+		{
+			name: "viewer replaces",
+			role: domain.AccessViewer,
+			call: func(uc *UseCaseFileMetadata) error {
+				_, err := uc.ReplaceMetadata(context.Background(), "company-id", "user-id", "file-id", &domain.FileMetadata{Tags: []string{"q3"}})
+				return err
+			},
+			status: 403,
+		},
+		{
+			name: "viewer updates",
+			role: domain.AccessViewer,
+			call: func(uc *UseCaseFileMetadata) error {
+				_, err := uc.UpdateMetadata(context.Background(), "company-id", "user-id", "file-id", &domain.MetadataPatch{AddTags: []string{"q3"}})
+				return err
+			},
+			status: 403,
+		},
+		{
+			name: "viewer deletes",
+			role: domain.AccessViewer,
+			call: func(uc *UseCaseFileMetadata) error {
+				return uc.DeleteMetadata(context.Background(), "company-id", "user-id", "file-id")
+			},
+			status: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := new(fileRepoMock)
+			metadata := new(metadataRepoMock)
+			access := new(accessRepoMock)
+			uc := NewUseCaseFileMetadata(files, metadata, access)
+
+			files.On("GetFile", mock.Anything, "company-id", "file-id").Return(&domain.File{ID: "file-id"}, nil)
+			access.On("GetAccessRole", mock.Anything, "company-id", "user-id", mock.Anything).Return(tt.role, nil)
+
+			assertStatus(t, tt.call(uc), tt.status)
+			assert.Empty(t, metadata.Calls)
+		})
+	}
+}
+
+func TestBulkUpdateTags_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		fileIDs []string
+		add     []string
+		remove  []string
+	}{
+		{name: "no items", add: []string{"q3"}},
+		{name: "no tags", fileIDs: []string{"a"}},
+		{name: "added and removed", fileIDs: []string{"a"}, add: []string{"q3"}, remove: []string{"Q3"}},
+		{name: "empty tag", fileIDs: []string{"a"}, add: []string{" "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := new(metadataRepoMock)
+			uc := NewUseCaseFileMetadata(new(fileRepoMock), metadata, ownerAccess())
+
+			_, err := uc.BulkUpdateTags(context.Background(), "company-id", "user-id", tt.fileIDs, tt.add, tt.remove)
+
+			assertStatus(t, err, 400)
+			metadata.AssertNotCalled(t, "UpdateTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
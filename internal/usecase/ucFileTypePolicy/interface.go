@@ -0,0 +1,12 @@
+package ucFileTypePolicy
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+)
+
+type PolicyRepository interface {
+	GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error)
+	UpdatePolicy(ctx context.Context, policy *domain.FileTypePolicy) (*domain.FileTypePolicy, error)
+}
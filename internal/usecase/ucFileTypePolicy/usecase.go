@@ -0,0 +1,40 @@
+package ucFileTypePolicy
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type UseCaseFileTypePolicy struct {
+	policyRepo PolicyRepository
+}
+
+func NewUseCaseFileTypePolicy(policyRepo PolicyRepository) *UseCaseFileTypePolicy {
+	return &UseCaseFileTypePolicy{
+		policyRepo: policyRepo,
+	}
+}
+
+func (uc *UseCaseFileTypePolicy) GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.policyRepo.GetPolicy(ctx, companyID)
+}
+
+// UpdatePolicy replaces the whole policy. It applies to uploads started
+// afterwards; files already stored are kept.
+func (uc *UseCaseFileTypePolicy) UpdatePolicy(ctx context.Context, policy *domain.FileTypePolicy) (*domain.FileTypePolicy, error) {
+	if policy.CompanyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if err := policy.Normalize(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	return uc.policyRepo.UpdatePolicy(ctx, policy)
+}
@@ -0,0 +1,126 @@
+package ucFileTypePolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+)
+
+type policyRepoMock struct {
+	mock.Mock
+}
+
+func (m *policyRepoMock) GetPolicy(ctx context.Context, companyID string) (*domain.FileTypePolicy, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileTypePolicy), args.Error(1)
+}
+
+func (m *policyRepoMock) UpdatePolicy(ctx context.Context, policy *domain.FileTypePolicy) (*domain.FileTypePolicy, error) {
+	args := m.Called(ctx, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileTypePolicy), args.Error(1)
+}
+
+func TestUseCaseFileTypePolicy_UpdatePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *domain.FileTypePolicy
+		want    *domain.FileTypePolicy
+		wantErr bool
+	}{
+		{
+			name: "normalizes entries",
+			policy: &domain.FileTypePolicy{
+				CompanyID:        "company-id",
+				AllowedMimeTypes: []string{"Image/*", "application/pdf", "image/*"},
+				DeniedExtensions: []string{".EXE", "msi"},
+			},
+			want: &domain.FileTypePolicy{
+				CompanyID:         "company-id",
+				AllowedMimeTypes:  []string{"application/pdf", "image/*"},
+				DeniedMimeTypes:   []string{},
+				AllowedExtensions: []string{},
+				DeniedExtensions:  []string{"exe", "msi"},
+			},
+		},
+		{
+			name:    "invalid MIME type",
+			policy:  &domain.FileTypePolicy{CompanyID: "company-id", DeniedMimeTypes: []string{"executable"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid extension",
+			policy:  &domain.FileTypePolicy{CompanyID: "company-id", DeniedExtensions: []string{"tar.gz"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing company",
+			policy:  &domain.FileTypePolicy{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(policyRepoMock)
+			uc := NewUseCaseFileTypePolicy(repo)
+
+			if !tt.wantErr {
+				repo.On("UpdatePolicy", mock.Anything, tt.want).Return(tt.want, nil)
+			}
+
+			result, err := uc.UpdatePolicy(context.Background(), tt.policy)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "UpdatePolicy", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileTypePolicy_Check(t *testing.T) {
+	policy := &domain.FileTypePolicy{
+		AllowedMimeTypes: []string{"application/pdf", "image/*", "text/plain"},
+		DeniedMimeTypes:  []string{"image/svg+xml"},
+		DeniedExtensions: []string{"exe"},
+	}
+
+	tests := []struct {
+		name      string
+		filename  string
+		mimeTypes []string
+		wantErr   bool
+	}{
+		{name: "allowed type", filename: "report.pdf", mimeTypes: []string{"application/pdf"}},
+		{name: "allowed family", filename: "photo.JPG", mimeTypes: []string{"image/jpeg"}},
+		{name: "type not allowed", filename: "data.zip", mimeTypes: []string{"application/zip"}, wantErr: true},
+		{name: "denied in family", filename: "logo.svg", mimeTypes: []string{"image/svg+xml"}, wantErr: true},
+		{name: "denied extension", filename: "setup.EXE", mimeTypes: []string{"text/plain"}, wantErr: true},
+		{name: "denied declared type", filename: "notes.txt", mimeTypes: []string{"text/plain", "image/svg+xml"}, wantErr: true},
+		{name: "declared type outside allow list", filename: "notes.txt", mimeTypes: []string{"text/plain", "application/octet-stream"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.filename, tt.mimeTypes...)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package ucLifecycle
+
+import (
+	"context"
+	"time"
+
+	"go-storage/internal/domain"
+)
+
+type RuleRepository interface {
+	ListRules(ctx context.Context, companyID string) ([]*domain.LifecycleRule, error)
+	GetActiveRules(ctx context.Context) ([]*domain.LifecycleRule, error)
+	GetRule(ctx context.Context, companyID, ruleID string) (*domain.LifecycleRule, error)
+	CreateRule(ctx context.Context, rule *domain.LifecycleRule) (*domain.LifecycleRule, error)
+	UpdateRule(ctx context.Context, rule *domain.LifecycleRule) error
+	DeleteRule(ctx context.Context, companyID, ruleID string) error
+}
+
+type FileRepository interface {
+	GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
+	GetTransitionCandidates(ctx context.Context, companyID string, folderPath *domain.Path, accessedBefore time.Time, limit int) ([]*domain.File, error)
+	SetStorageClass(ctx context.Context, file *domain.File, class domain.StorageClass) (bool, error)
+	TrashExpiredFiles(ctx context.Context, companyID string, folderPath *domain.Path, modifiedBefore, deletedAt time.Time) (int, error)
+}
+
+// TierStorage moves objects between the hot and the cold storage tier.
+type TierStorage interface {
+	MoveObject(ctx context.Context, key string, class domain.StorageClass) error
+}
@@ -0,0 +1,228 @@
+package ucLifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+const transitionBatchSize = 100
+
+type UseCaseLifecycle struct {
+	ruleRepo RuleRepository
+	fileRepo FileRepository
+	// tiers is nil when no cold storage is configured.
+	tiers  TierStorage
+	config *config.FileServer
+}
+
+func NewUseCaseLifecycle(
+	ruleRepo RuleRepository,
+	fileRepo FileRepository,
+	tiers TierStorage,
+	config *config.FileServer,
+) *UseCaseLifecycle {
+	return &UseCaseLifecycle{
+		ruleRepo: ruleRepo,
+		fileRepo: fileRepo,
+		tiers:    tiers,
+		config:   config,
+	}
+}
+
+func (uc *UseCaseLifecycle) ListRules(ctx context.Context, companyID string) ([]*domain.LifecycleRule, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.ruleRepo.ListRules(ctx, companyID)
+}
+
+// CreateRule adds a rule for the whole company, or for the folder at
+// rule.FolderID and everything below it.
+func (uc *UseCaseLifecycle) CreateRule(ctx context.Context, rule *domain.LifecycleRule) (*domain.LifecycleRule, error) {
+	if rule.CompanyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if rule.CreatedBy == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if rule.Action == domain.LifecycleActionTransition && uc.tiers == nil {
+		return nil, errors.BadRequest("cold storage is not configured")
+	}
+
+	if rule.FolderID != nil {
+		folder, err := uc.fileRepo.GetFile(ctx, rule.CompanyID, *rule.FolderID)
+		if err != nil {
+			return nil, errors.BadRequest("folder not found")
+		}
+		if !folder.IsFolder() {
+			return nil, errors.BadRequest("specified path is not a folder")
+		}
+		rule.FolderPath = &folder.FullPath
+	}
+
+	now := time.Now()
+	rule.ID = uuid.New().String()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	return uc.ruleRepo.CreateRule(ctx, rule)
+}
+
+func (uc *UseCaseLifecycle) UpdateRule(ctx context.Context, companyID, ruleID string, days int) (*domain.LifecycleRule, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	rule, err := uc.ruleRepo.GetRule(ctx, companyID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Days = days
+	if err := rule.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := uc.ruleRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (uc *UseCaseLifecycle) DeleteRule(ctx context.Context, companyID, ruleID string) error {
+	if companyID == "" {
+		return errors.BadRequest("company ID is required")
+	}
+
+	return uc.ruleRepo.DeleteRule(ctx, companyID, ruleID)
+}
+
+// ApplyRules runs every rule once. A rule that fails does not stop the
+// others; the first error is returned along with what the pass did.
+func (uc *UseCaseLifecycle) ApplyRules(ctx context.Context) (*domain.LifecycleStats, error) {
+	rules, err := uc.ruleRepo.GetActiveRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.LifecycleStats{}
+	now := time.Now()
+	var firstErr error
+
+	for _, rule := range rules {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		switch rule.Action {
+		case domain.LifecycleActionDelete:
+			err = uc.trashExpired(ctx, rule, now, stats)
+		case domain.LifecycleActionTransition:
+			err = uc.transition(ctx, rule, now, stats)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return stats, firstErr
+}
+
+// StartWorker runs ApplyRules on every LifecycleInterval until ctx is done.
+func (uc *UseCaseLifecycle) StartWorker(ctx context.Context, log logger.Logger) {
+	if uc.config.LifecycleInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.config.LifecycleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats, err := uc.ApplyRules(ctx)
+			if err != nil {
+				log.Error("func StartWorker: failed to apply lifecycle rules", "func", "StartWorker", "err", err)
+			}
+			if stats != nil && stats.Transitioned+stats.Trashed+stats.Failed > 0 {
+				log.Info("applied lifecycle rules",
+					"transitioned", stats.Transitioned, "trashed", stats.Trashed, "failed", stats.Failed)
+			}
+		}
+	}
+}
+
+// trashExpired moves the files the rule matches to the trash, where they
+// wait out the company's retention like files deleted by hand.
+func (uc *UseCaseLifecycle) trashExpired(ctx context.Context, rule *domain.LifecycleRule, now time.Time, stats *domain.LifecycleStats) error {
+	trashed, err := uc.fileRepo.TrashExpiredFiles(ctx, rule.CompanyID, rule.FolderPath, rule.Cutoff(now), now)
+	if err != nil {
+		return err
+	}
+
+	stats.Trashed += trashed
+	return nil
+}
+
+// transition moves the content of the files the rule matches to the cold
+// tier. The object is moved before the row is marked cold, so reads keep
+// finding it on either tier, and a move interrupted in between is finished
+// on the next pass.
+func (uc *UseCaseLifecycle) transition(ctx context.Context, rule *domain.LifecycleRule, now time.Time, stats *domain.LifecycleStats) error {
+	// Rules created while cold storage was configured wait for it to return.
+	if uc.tiers == nil {
+		return nil
+	}
+
+	for {
+		files, err := uc.fileRepo.GetTransitionCandidates(ctx, rule.CompanyID, rule.FolderPath, rule.Cutoff(now), transitionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		batchDone := 0
+		for _, file := range files {
+			if file.StoragePath == nil {
+				continue
+			}
+
+			if err := uc.tiers.MoveObject(ctx, *file.StoragePath, domain.StorageClassCold); err != nil {
+				stats.Failed++
+				continue
+			}
+
+			// A file whose content was replaced meanwhile stays hot; its
+			// old content, now cold, is kept only by its versions.
+			updated, err := uc.fileRepo.SetStorageClass(ctx, file, domain.StorageClassCold)
+			if err != nil {
+				stats.Failed++
+				continue
+			}
+			batchDone++
+			if updated {
+				stats.Transitioned++
+			}
+		}
+
+		if len(files) < transitionBatchSize || batchDone == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package ucLifecycle
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type ruleRepoMock struct {
+	mock.Mock
+}
+
+func (m *ruleRepoMock) ListRules(ctx context.Context, companyID string) ([]*domain.LifecycleRule, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.LifecycleRule), args.Error(1)
+}
+
+func (m *ruleRepoMock) GetActiveRules(ctx context.Context) ([]*domain.LifecycleRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.LifecycleRule), args.Error(1)
+}
+
+func (m *ruleRepoMock) GetRule(ctx context.Context, companyID, ruleID string) (*domain.LifecycleRule, error) {
+	args := m.Called(ctx, companyID, ruleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LifecycleRule), args.Error(1)
+}
+
+func (m *ruleRepoMock) CreateRule(ctx context.Context, rule *domain.LifecycleRule) (*domain.LifecycleRule, error) {
+	args := m.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LifecycleRule), args.Error(1)
+}
+
+func (m *ruleRepoMock) UpdateRule(ctx context.Context, rule *domain.LifecycleRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *ruleRepoMock) DeleteRule(ctx context.Context, companyID, ruleID string) error {
+	args := m.Called(ctx, companyID, ruleID)
+	return args.Error(0)
+}
+
+type fileRepoMock struct {
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *fileRepoMock) GetTransitionCandidates(ctx context.Context, companyID string, folderPath *domain.Path, accessedBefore time.Time, limit int) ([]*domain.File, error) {
+	args := m.Called(ctx, companyID, folderPath, accessedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.File), args.Error(1)
+}
+
+func (m *fileRepoMock) SetStorageClass(ctx context.Context, file *domain.File, class domain.StorageClass) (bool, error) {
+	args := m.Called(ctx, file, class)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *fileRepoMock) TrashExpiredFiles(ctx context.Context, companyID string, folderPath *domain.Path, modifiedBefore, deletedAt time.Time) (int, error) {
+	args := m.Called(ctx, companyID, folderPath, modifiedBefore, deletedAt)
+	return args.Int(0), args.Error(1)
+}
+
+type tierStorageMock struct {
+	mock.Mock
+}
+
+func (m *tierStorageMock) MoveObject(ctx context.Context, key string, class domain.StorageClass) error {
+	args := m.Called(ctx, key, class)
+	return args.Error(0)
+}
+
+func TestUseCaseLifecycle_CreateRule(t *testing.T) {
+	folderID := "folder-id"
+
+	tests := []struct {
+		name     string
+		rule     *domain.LifecycleRule
+		noTiers  bool
+		folder   *domain.File
+		wantPath *domain.Path
+		wantErr  bool
+	}{
+		{
+			name: "company rule",
+			rule: &domain.LifecycleRule{CompanyID: "company-id", CreatedBy: "user-id", Action: domain.LifecycleActionTransition, Days: 90},
+		},
+		{
+			name:     "folder rule",
+			rule:     &domain.LifecycleRule{CompanyID: "company-id", CreatedBy: "user-id", FolderID: &folderID, Action: domain.LifecycleActionDelete, Days: 7},
+			folder:   &domain.File{ID: folderID, Type: domain.FileTypeFolder, FullPath: "/tmp"},
+			wantPath: func() *domain.Path { p := domain.Path("/tmp"); return &p }(),
+		},
+		{
+			name:    "folder is a file",
+			rule:    &domain.LifecycleRule{CompanyID: "company-id", CreatedBy: "user-id", FolderID: &folderID, Action: domain.LifecycleActionDelete, Days: 7},
+			folder:  &domain.File{ID: folderID, Type: domain.FileTypeFile, FullPath: "/tmp"},
+			wantErr: true,
+		},
+		{
+			name:    "delete rule without cold storage",
+			rule:    &domain.LifecycleRule{CompanyID: "company-id", CreatedBy: "user-id", Action: domain.LifecycleActionDelete, Days: 7},
+			noTiers: true,
+		},
+		{
+			name:    "transition rule without cold storage",
+			rule:    &domain.LifecycleRule{CompanyID: "company-id", CreatedBy: "user-id", Action: domain.LifecycleActionTransition, Days: 90},
+			noTiers: true,
+			wantErr: true,
+		},
+		{
+			name:    "days out of range",
+			rule:    &domain.LifecycleRule{CompanyID: "company-id", CreatedBy: "user-id", Action: domain.LifecycleActionDelete, Days: 0},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			rule:    &domain.LifecycleRule{CompanyID: "company-id", CreatedBy: "user-id", Action: "archive", Days: 7},
+			wantErr: true,
+		},
+		{
+			name:    "missing company",
+			rule:    &domain.LifecycleRule{CreatedBy: "user-id", Action: domain.LifecycleActionDelete, Days: 7},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleRepo := new(ruleRepoMock)
+			fileRepo := new(fileRepoMock)
+			var tiers TierStorage
+			if !tt.noTiers {
+				tiers = new(tierStorageMock)
+			}
+			uc := NewUseCaseLifecycle(ruleRepo, fileRepo, tiers, &config.FileServer{})
+
+			if tt.folder != nil {
+				fileRepo.On("GetFile", mock.Anything, "company-id", folderID).Return(tt.folder, nil)
+			}
+			ruleRepo.On("CreateRule", mock.Anything, mock.Anything).Return(tt.rule, nil).Maybe()
+
+			result, err := uc.CreateRule(context.Background(), tt.rule)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errors.ErrInvalidRequest)
+				ruleRepo.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, result.ID)
+			assert.False(t, result.CreatedAt.IsZero())
+			assert.Equal(t, tt.wantPath, result.FolderPath)
+			ruleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUseCaseLifecycle_UpdateRule_InvalidDays(t *testing.T) {
+	ruleRepo := new(ruleRepoMock)
+	uc := NewUseCaseLifecycle(ruleRepo, new(fileRepoMock), nil, &config.FileServer{})
+
+	ruleRepo.On("GetRule", mock.Anything, "company-id", "rule-id").
+		Return(&domain.LifecycleRule{ID: "rule-id", CompanyID: "company-id", Action: domain.LifecycleActionDelete, Days: 7}, nil)
+
+	_, err := uc.UpdateRule(context.Background(), "company-id", "rule-id", domain.MaxLifecycleDays+1)
+
+	assert.ErrorIs(t, err, errors.ErrInvalidRequest)
+	ruleRepo.AssertNotCalled(t, "UpdateRule", mock.Anything, mock.Anything)
+}
+
+func TestUseCaseLifecycle_ApplyRules(t *testing.T) {
+	ruleRepo := new(ruleRepoMock)
+	fileRepo := new(fileRepoMock)
+	tiers := new(tierStorageMock)
+	uc := NewUseCaseLifecycle(ruleRepo, fileRepo, tiers, &config.FileServer{})
+
+	tmp := domain.Path("/tmp")
+	ruleRepo.On("GetActiveRules", mock.Anything).Return([]*domain.LifecycleRule{
+		{ID: "rule-1", CompanyID: "company-id", FolderPath: &tmp, Action: domain.LifecycleActionDelete, Days: 7},
+		{ID: "rule-2", CompanyID: "company-id", Action: domain.LifecycleActionTransition, Days: 90},
+	}, nil)
+
+	fileRepo.On("TrashExpiredFiles", mock.Anything, "company-id", &tmp, mock.Anything, mock.Anything).Return(3, nil)
+
+	movedPath, failedPath, replacedPath := "companies/c/files/1/a.txt", "companies/c/files/2/b.txt", "companies/c/files/3/c.txt"
+	moved := &domain.File{ID: "file-1", StoragePath: &movedPath}
+	failed := &domain.File{ID: "file-2", StoragePath: &failedPath}
+	replaced := &domain.File{ID: "file-3", StoragePath: &replacedPath}
+	fileRepo.On("GetTransitionCandidates", mock.Anything, "company-id", (*domain.Path)(nil), mock.Anything, transitionBatchSize).
+		Return([]*domain.File{moved, failed, replaced}, nil)
+
+	tiers.On("MoveObject", mock.Anything, movedPath, domain.StorageClassCold).Return(nil)
+	tiers.On("MoveObject", mock.Anything, failedPath, domain.StorageClassCold).Return(stdErrors.New("storage unavailable"))
+	tiers.On("MoveObject", mock.Anything, replacedPath, domain.StorageClassCold).Return(nil)
+	fileRepo.On("SetStorageClass", mock.Anything, moved, domain.StorageClassCold).Return(true, nil)
+	fileRepo.On("SetStorageClass", mock.Anything, replaced, domain.StorageClassCold).Return(false, nil)
+
+	stats, err := uc.ApplyRules(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.LifecycleStats{Transitioned: 1, Trashed: 3, Failed: 1}, stats)
+	fileRepo.AssertExpectations(t)
+	tiers.AssertExpectations(t)
+}
+
+func TestUseCaseLifecycle_ApplyRules_ContinuesAfterFailedRule(t *testing.T) {
+	ruleRepo := new(ruleRepoMock)
+	fileRepo := new(fileRepoMock)
+	uc := NewUseCaseLifecycle(ruleRepo, fileRepo, nil, &config.FileServer{})
+
+	ruleRepo.On("GetActiveRules", mock.Anything).Return([]*domain.LifecycleRule{
+		{ID: "rule-1", CompanyID: "company-1", Action: domain.LifecycleActionDelete, Days: 7},
+		{ID: "rule-2", CompanyID: "company-2", Action: domain.LifecycleActionDelete, Days: 7},
+		{ID: "rule-3", CompanyID: "company-3", Action: domain.LifecycleActionTransition, Days: 90},
+	}, nil)
+
+	fileRepo.On("TrashExpiredFiles", mock.Anything, "company-1", (*domain.Path)(nil), mock.Anything, mock.Anything).
+		Return(0, errors.Database("unable to trash expired files"))
+	fileRepo.On("TrashExpiredFiles", mock.Anything, "company-2", (*domain.Path)(nil), mock.Anything, mock.Anything).Return(2, nil)
+
+	stats, err := uc.ApplyRules(context.Background())
+
+	assert.ErrorIs(t, err, errors.ErrDatabase)
+	assert.Equal(t, 2, stats.Trashed)
+	fileRepo.AssertNotCalled(t, "GetTransitionCandidates", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,13 @@
+package ucQuota
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type QuotaRepository interface {
+	GetCompanyUsage(ctx context.Context, companyID string) (*domain.StorageUsage, error)
+	GetUserUsage(ctx context.Context, companyID, userID string) (*domain.StorageUsage, error)
+	SetCompanyQuota(ctx context.Context, companyID string, quota domain.StorageQuota) error
+	SetUserQuota(ctx context.Context, companyID, userID string, quota domain.StorageQuota) error
+}
@@ -0,0 +1,90 @@
+package ucQuota
+
+import (
+	"context"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type UseCaseQuota struct {
+	quotaRepo QuotaRepository
+}
+
+func NewUseCaseQuota(quotaRepo QuotaRepository) *UseCaseQuota {
+	return &UseCaseQuota{
+		quotaRepo: quotaRepo,
+	}
+}
+
+// GetUsage returns the storage usage of the company along with that of the user.
+func (uc *UseCaseQuota) GetUsage(ctx context.Context, companyID, userID string) (*domain.UsageReport, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	company, err := uc.quotaRepo.GetCompanyUsage(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.quotaRepo.GetUserUsage(ctx, companyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UsageReport{Company: company, User: user}, nil
+}
+
+// SetCompanyQuota changes the limits of a company. Lowering them below the
+// current usage only blocks new uploads, nothing stored is removed.
+func (uc *UseCaseQuota) SetCompanyQuota(ctx context.Context, companyID string, quota domain.StorageQuota) (*domain.StorageUsage, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if err := validateQuota(quota); err != nil {
+		return nil, err
+	}
+
+	if err := uc.quotaRepo.SetCompanyQuota(ctx, companyID, quota); err != nil {
+		return nil, err
+	}
+
+	return uc.quotaRepo.GetCompanyUsage(ctx, companyID)
+}
+
+// SetUserQuota changes the limits of a user of the company.
+func (uc *UseCaseQuota) SetUserQuota(ctx context.Context, companyID, userID string, quota domain.StorageQuota) (*domain.StorageUsage, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	if err := validateQuota(quota); err != nil {
+		return nil, err
+	}
+
+	if err := uc.quotaRepo.SetUserQuota(ctx, companyID, userID, quota); err != nil {
+		return nil, err
+	}
+
+	return uc.quotaRepo.GetUserUsage(ctx, companyID, userID)
+}
+
+func validateQuota(quota domain.StorageQuota) error {
+	if quota.MaxBytes != nil && *quota.MaxBytes < 0 {
+		return errors.BadRequest("byte quota must not be negative")
+	}
+	if quota.MaxFiles != nil && *quota.MaxFiles < 0 {
+		return errors.BadRequest("file quota must not be negative")
+	}
+	return nil
+}
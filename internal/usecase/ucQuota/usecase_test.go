@@ -0,0 +1,132 @@
+package ucQuota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	customErrors "go-storage/pkg/errors"
+)
+
+type quotaRepoMock struct {
+	mock.Mock
+}
+
+func (m *quotaRepoMock) GetCompanyUsage(ctx context.Context, companyID string) (*domain.StorageUsage, error) {
+	args := m.Called(ctx, companyID)
+	var usage *domain.StorageUsage
+	if args.Get(0) != nil {
+		usage = args.Get(0).(*domain.StorageUsage)
+	}
+	return usage, args.Error(1)
+}
+
+func (m *quotaRepoMock) GetUserUsage(ctx context.Context, companyID, userID string) (*domain.StorageUsage, error) {
+	args := m.Called(ctx, companyID, userID)
+	var usage *domain.StorageUsage
+	if args.Get(0) != nil {
+		usage = args.Get(0).(*domain.StorageUsage)
+	}
+	return usage, args.Error(1)
+}
+
+func (m *quotaRepoMock) SetCompanyQuota(ctx context.Context, companyID string, quota domain.StorageQuota) error {
+	return m.Called(ctx, companyID, quota).Error(0)
+}
+
+func (m *quotaRepoMock) SetUserQuota(ctx context.Context, companyID, userID string, quota domain.StorageQuota) error {
+	return m.Called(ctx, companyID, userID, quota).Error(0)
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestUseCaseQuota_GetUsage(t *testing.T) {
+	repo := new(quotaRepoMock)
+	uc := NewUseCaseQuota(repo)
+
+	company := &domain.StorageUsage{UsedBytes: 500, UsedFiles: 5, Quota: domain.StorageQuota{MaxBytes: int64Ptr(1000)}}
+	user := &domain.StorageUsage{UsedBytes: 100, UsedFiles: 1}
+	repo.On("GetCompanyUsage", mock.Anything, "company-id").Return(company, nil)
+	repo.On("GetUserUsage", mock.Anything, "company-id", "user-id").Return(user, nil)
+
+	report, err := uc.GetUsage(context.Background(), "company-id", "user-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, company, report.Company)
+	assert.Equal(t, user, report.User)
+}
+
+func TestUseCaseQuota_GetUsage_CompanyNotFound(t *testing.T) {
+	repo := new(quotaRepoMock)
+	uc := NewUseCaseQuota(repo)
+
+	repo.On("GetCompanyUsage", mock.Anything, "company-id").Return(nil, customErrors.NotFound("company not found"))
+
+	report, err := uc.GetUsage(context.Background(), "company-id", "user-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, report)
+	repo.AssertNotCalled(t, "GetUserUsage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUseCaseQuota_SetCompanyQuota(t *testing.T) {
+	t.Run("stores the quota and returns the usage", func(t *testing.T) {
+		repo := new(quotaRepoMock)
+		uc := NewUseCaseQuota(repo)
+
+		quota := domain.StorageQuota{MaxBytes: int64Ptr(1 << 30), MaxFiles: int64Ptr(1000)}
+		usage := &domain.StorageUsage{UsedBytes: 10, Quota: quota}
+		repo.On("SetCompanyQuota", mock.Anything, "company-id", quota).Return(nil)
+		repo.On("GetCompanyUsage", mock.Anything, "company-id").Return(usage, nil)
+
+		result, err := uc.SetCompanyQuota(context.Background(), "company-id", quota)
+
+		assert.NoError(t, err)
+		assert.Equal(t, usage, result)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects negative limits", func(t *testing.T) {
+		repo := new(quotaRepoMock)
+		uc := NewUseCaseQuota(repo)
+
+		result, err := uc.SetCompanyQuota(context.Background(), "company-id", domain.StorageQuota{MaxFiles: int64Ptr(-1)})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		repo.AssertNotCalled(t, "SetCompanyQuota", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUseCaseQuota_SetUserQuota(t *testing.T) {
+	repo := new(quotaRepoMock)
+	uc := NewUseCaseQuota(repo)
+
+	quota := domain.StorageQuota{MaxBytes: int64Ptr(0)}
+	repo.On("SetUserQuota", mock.Anything, "company-id", "user-id", quota).Return(customErrors.NotFound("user not found"))
+
+	result, err := uc.SetUserQuota(context.Background(), "company-id", "user-id", quota)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	repo.AssertNotCalled(t, "GetUserUsage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStorageUsage_Allows(t *testing.T) {
+	usage := &domain.StorageUsage{
+		UsedBytes:     600,
+		UsedFiles:     8,
+		ReservedBytes: 300,
+		ReservedFiles: 1,
+		Quota:         domain.StorageQuota{MaxBytes: int64Ptr(1000), MaxFiles: int64Ptr(10)},
+	}
+
+	assert.True(t, usage.Allows(100, 1))
+	assert.False(t, usage.Allows(101, 1))
+	assert.False(t, usage.Allows(0, 2))
+	assert.True(t, (&domain.StorageUsage{UsedBytes: 1 << 40}).Allows(1<<40, 1000))
+}
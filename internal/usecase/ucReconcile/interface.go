@@ -0,0 +1,16 @@
+package ucReconcile
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type FileRepository interface {
+	GetStorageRefs(ctx context.Context, companyID string) ([]*domain.StorageRef, error)
+}
+
+type StorageRepository interface {
+	ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error)
+	CopyFile(ctx context.Context, srcKey, dstKey string) error
+	DeleteFile(ctx context.Context, key string) error
+}
@@ -0,0 +1,149 @@
+package ucReconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+type UseCaseReconcile struct {
+	fileRepo    FileRepository
+	storageRepo StorageRepository
+	config      *config.FileServer
+}
+
+func NewUseCaseReconcile(
+	fileRepo FileRepository,
+	storageRepo StorageRepository,
+	config *config.FileServer,
+) *UseCaseReconcile {
+	return &UseCaseReconcile{
+		fileRepo:    fileRepo,
+		storageRepo: storageRepo,
+		config:      config,
+	}
+}
+
+// Reconcile compares the objects stored for the company with the rows that
+// reference them. Objects nobody references are orphans; in apply mode they are
+// deleted or moved under the quarantine prefix. Objects younger than
+// ReconcileGracePeriod are skipped because an upload may still be finishing.
+// Quarantined objects are only checked against the rows pointing at them:
+// unreferenced ones were put there on purpose and are never orphans.
+func (uc *UseCaseReconcile) Reconcile(ctx context.Context, companyID string, dryRun bool, action domain.OrphanAction) (*domain.ReconcileReport, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+	if !action.IsValid() {
+		return nil, errors.BadRequest("action must be delete or quarantine")
+	}
+
+	report := &domain.ReconcileReport{
+		CompanyID:      companyID,
+		DryRun:         dryRun,
+		Action:         action,
+		StartedAt:      time.Now(),
+		Orphans:        []*domain.OrphanObject{},
+		DanglingRows:   []*domain.DanglingRow{},
+		SizeMismatches: []*domain.SizeMismatch{},
+	}
+
+	// Refs are loaded before listing so an object written in between is
+	// at worst reported as an orphan and then protected by the grace period.
+	refs, err := uc.fileRepo.GetStorageRefs(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := uc.storageRepo.ListFiles(ctx, companyPrefix(companyID))
+	if err != nil {
+		return nil, err
+	}
+
+	quarantined, err := uc.storageRepo.ListFiles(ctx, domain.QuarantineKey(companyPrefix(companyID)))
+	if err != nil {
+		return nil, err
+	}
+
+	report.ScannedRefs = len(refs)
+	report.ScannedObjects = len(objects) + len(quarantined)
+
+	stored := make(map[string]*domain.StorageFileInfo, len(objects)+len(quarantined))
+	for _, object := range objects {
+		stored[object.Key] = object
+	}
+	for _, object := range quarantined {
+		stored[object.Key] = object
+	}
+
+	referenced := make(map[string]struct{}, len(refs))
+	for _, ref := range refs {
+		referenced[ref.Key] = struct{}{}
+		if !ref.ExpectsObject() {
+			continue
+		}
+
+		object, ok := stored[ref.Key]
+		if !ok {
+			report.DanglingRows = append(report.DanglingRows, &domain.DanglingRow{Source: ref.Source, ID: ref.ID, Key: ref.Key})
+			continue
+		}
+		if object.Size != ref.Size {
+			report.SizeMismatches = append(report.SizeMismatches, &domain.SizeMismatch{
+				Source:       ref.Source,
+				ID:           ref.ID,
+				Key:          ref.Key,
+				ExpectedSize: ref.Size,
+				ActualSize:   object.Size,
+			})
+		}
+	}
+
+	cutoff := report.StartedAt.Add(-uc.config.ReconcileGracePeriod)
+	for _, object := range objects {
+		if _, ok := referenced[object.Key]; ok {
+			continue
+		}
+		if object.LastModified.After(cutoff) {
+			report.SkippedRecent++
+			continue
+		}
+
+		orphan := &domain.OrphanObject{Key: object.Key, Size: object.Size, LastModified: object.LastModified}
+		report.Orphans = append(report.Orphans, orphan)
+
+		if dryRun {
+			continue
+		}
+
+		if err := uc.resolveOrphan(ctx, object.Key, action); err != nil {
+			orphan.Error = err.Error()
+			report.Errors++
+			continue
+		}
+		orphan.Resolved = true
+		report.Resolved++
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+
+	return report, nil
+}
+
+func (uc *UseCaseReconcile) resolveOrphan(ctx context.Context, key string, action domain.OrphanAction) error {
+	if action == domain.OrphanActionQuarantine {
+		if err := uc.storageRepo.CopyFile(ctx, key, domain.QuarantineKey(key)); err != nil {
+			return err
+		}
+	}
+
+	return uc.storageRepo.DeleteFile(ctx, key)
+}
+
+func companyPrefix(companyID string) string {
+	return fmt.Sprintf("companies/%s/files/", companyID)
+}
@@ -0,0 +1,185 @@
+package ucReconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	customErrors "go-storage/pkg/errors"
+)
+
+type fileRepoMock struct {
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetStorageRefs(ctx context.Context, companyID string) ([]*domain.StorageRef, error) {
+	args := m.Called(ctx, companyID)
+	var refs []*domain.StorageRef
+	if args.Get(0) != nil {
+		refs = args.Get(0).([]*domain.StorageRef)
+	}
+	return refs, args.Error(1)
+}
+
+type storageRepoMock struct {
+	mock.Mock
+}
+
+func (m *storageRepoMock) ListFiles(ctx context.Context, prefix string) ([]*domain.StorageFileInfo, error) {
+	args := m.Called(ctx, prefix)
+	var files []*domain.StorageFileInfo
+	if args.Get(0) != nil {
+		files = args.Get(0).([]*domain.StorageFileInfo)
+	}
+	return files, args.Error(1)
+}
+
+func (m *storageRepoMock) CopyFile(ctx context.Context, srcKey, dstKey string) error {
+	args := m.Called(ctx, srcKey, dstKey)
+	return args.Error(0)
+}
+
+func (m *storageRepoMock) DeleteFile(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+const (
+	prefix           = "companies/company-id/files/"
+	quarantinePrefix = "quarantine/" + prefix
+)
+
+func setupUseCase() (*UseCaseReconcile, *fileRepoMock, *storageRepoMock) {
+	files := new(fileRepoMock)
+	storage := new(storageRepoMock)
+	cnf := &config.FileServer{ReconcileGracePeriod: time.Hour}
+	return NewUseCaseReconcile(files, storage, cnf), files, storage
+}
+
+func fixtures() ([]*domain.StorageRef, []*domain.StorageFileInfo) {
+	old := time.Now().Add(-2 * time.Hour)
+
+	refs := []*domain.StorageRef{
+		{Source: domain.StorageRefFile, ID: "ok", Key: prefix + "ok/a.txt", Size: 10},
+		{Source: domain.StorageRefVersion, ID: "v-ok", Key: prefix + "ok/a.txt", Size: 10},
+		{Source: domain.StorageRefFile, ID: "missing", Key: prefix + "missing/b.txt", Size: 5},
+		{Source: domain.StorageRefFile, ID: "resized", Key: prefix + "resized/c.txt", Size: 7},
+		{Source: domain.StorageRefPresignedUpload, ID: "pending", Key: prefix + "pending/d.txt", Size: 3},
+		{Source: domain.StorageRefFile, ID: "infected", Key: quarantinePrefix + "infected/g.txt", Size: 6},
+	}
+	objects := []*domain.StorageFileInfo{
+		{Key: prefix + "ok/a.txt", Size: 10, LastModified: old},
+		{Key: prefix + "resized/c.txt", Size: 9, LastModified: old},
+		{Key: prefix + "orphan/e.txt", Size: 4, LastModified: old},
+		{Key: prefix + "fresh/f.txt", Size: 4, LastModified: time.Now()},
+	}
+
+	return refs, objects
+}
+
+func quarantineFixtures() []*domain.StorageFileInfo {
+	old := time.Now().Add(-2 * time.Hour)
+
+	return []*domain.StorageFileInfo{
+		{Key: quarantinePrefix + "infected/g.txt", Size: 6, LastModified: old},
+		{Key: quarantinePrefix + "orphan/h.txt", Size: 2, LastModified: old},
+	}
+}
+
+func TestUseCaseReconcile_DryRun(t *testing.T) {
+	uc, files, storage := setupUseCase()
+	refs, objects := fixtures()
+
+	files.On("GetStorageRefs", mock.Anything, "company-id").Return(refs, nil)
+	storage.On("ListFiles", mock.Anything, prefix).Return(objects, nil)
+	storage.On("ListFiles", mock.Anything, quarantinePrefix).Return(quarantineFixtures(), nil)
+
+	report, err := uc.Reconcile(context.Background(), "company-id", true, domain.OrphanActionDelete)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.ScannedObjects)
+	assert.Equal(t, 6, report.ScannedRefs)
+	assert.Equal(t, 1, report.SkippedRecent)
+
+	assert.Len(t, report.Orphans, 1)
+	assert.Equal(t, prefix+"orphan/e.txt", report.Orphans[0].Key)
+	assert.False(t, report.Orphans[0].Resolved)
+
+	assert.Len(t, report.DanglingRows, 1)
+	assert.Equal(t, "missing", report.DanglingRows[0].ID)
+
+	assert.Len(t, report.SizeMismatches, 1)
+	assert.Equal(t, int64(7), report.SizeMismatches[0].ExpectedSize)
+	assert.Equal(t, int64(9), report.SizeMismatches[0].ActualSize)
+
+	storage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
+}
+
+func TestUseCaseReconcile_Apply(t *testing.T) {
+	t.Run("deletes orphans", func(t *testing.T) {
+		uc, files, storage := setupUseCase()
+		refs, objects := fixtures()
+
+		files.On("GetStorageRefs", mock.Anything, "company-id").Return(refs, nil)
+		storage.On("ListFiles", mock.Anything, prefix).Return(objects, nil)
+		storage.On("ListFiles", mock.Anything, quarantinePrefix).Return(quarantineFixtures(), nil)
+		storage.On("DeleteFile", mock.Anything, prefix+"orphan/e.txt").Return(nil)
+
+		report, err := uc.Reconcile(context.Background(), "company-id", false, domain.OrphanActionDelete)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Resolved)
+		assert.True(t, report.Orphans[0].Resolved)
+		storage.AssertExpectations(t)
+		storage.AssertNotCalled(t, "CopyFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("quarantines orphans", func(t *testing.T) {
+		uc, files, storage := setupUseCase()
+		refs, objects := fixtures()
+
+		files.On("GetStorageRefs", mock.Anything, "company-id").Return(refs, nil)
+		storage.On("ListFiles", mock.Anything, prefix).Return(objects, nil)
+		storage.On("ListFiles", mock.Anything, quarantinePrefix).Return(quarantineFixtures(), nil)
+		storage.On("CopyFile", mock.Anything, prefix+"orphan/e.txt", quarantinePrefix+"orphan/e.txt").Return(nil)
+		storage.On("DeleteFile", mock.Anything, prefix+"orphan/e.txt").Return(nil)
+
+		report, err := uc.Reconcile(context.Background(), "company-id", false, domain.OrphanActionQuarantine)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Resolved)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("keeps orphan when quarantine copy fails", func(t *testing.T) {
+		uc, files, storage := setupUseCase()
+		refs, objects := fixtures()
+
+		files.On("GetStorageRefs", mock.Anything, "company-id").Return(refs, nil)
+		storage.On("ListFiles", mock.Anything, prefix).Return(objects, nil)
+		storage.On("ListFiles", mock.Anything, quarantinePrefix).Return(quarantineFixtures(), nil)
+		storage.On("CopyFile", mock.Anything, mock.Anything, mock.Anything).
+			Return(customErrors.InternalServer("failed to copy file in storage"))
+
+		report, err := uc.Reconcile(context.Background(), "company-id", false, domain.OrphanActionQuarantine)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Resolved)
+		assert.Equal(t, 1, report.Errors)
+		assert.NotEmpty(t, report.Orphans[0].Error)
+		storage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUseCaseReconcile_InvalidAction(t *testing.T) {
+	uc, _, _ := setupUseCase()
+
+	_, err := uc.Reconcile(context.Background(), "company-id", true, domain.OrphanAction("archive"))
+	appErr, ok := err.(*customErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+}
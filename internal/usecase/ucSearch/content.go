@@ -0,0 +1,177 @@
+package ucSearch
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+	"go-storage/pkg/textextract"
+)
+
+const (
+	contentIndexClaimLimit = 10
+	contentIndexStaleAfter = 10 * time.Minute
+	// maxIndexedText keeps the text well below the 1MB limit of a tsvector.
+	maxIndexedText = 512 * 1024
+)
+
+// SearchContent returns one page of the company files whose text matches the
+// query, with the matching passages highlighted. The query accepts the web
+// search syntax: quoted phrases, "or" and a leading "-" to exclude a word.
+func (uc *UseCaseSearch) SearchContent(ctx context.Context, companyID, userID string, query *domain.SearchQuery) (*domain.SearchPage, error) {
+	if err := uc.prepareQuery(ctx, companyID, userID, query); err != nil {
+		return nil, err
+	}
+
+	pageQuery := *query
+	pageQuery.Limit++
+
+	hits, err := uc.fileRepo.SearchContent(ctx, companyID, uc.config.ContentIndexLanguage, &pageQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(hits, query.Limit), nil
+}
+
+// ReindexCompany queues every file of the company for text extraction and
+// returns how many were queued. Files stay searchable by their current text
+// until they are indexed again.
+func (uc *UseCaseSearch) ReindexCompany(ctx context.Context, companyID string) (int, error) {
+	if companyID == "" {
+		return 0, errors.BadRequest("company ID is required")
+	}
+
+	queued, err := uc.contentRepo.EnqueueCompany(ctx, companyID)
+	if err != nil {
+		return 0, err
+	}
+
+	uc.wakeIndexer()
+
+	return queued, nil
+}
+
+func (uc *UseCaseSearch) GetContentIndexStats(ctx context.Context, companyID string) (*domain.ContentIndexStats, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.contentRepo.GetStats(ctx, companyID)
+}
+
+// StartContentIndexer extracts the text of queued files every
+// ContentIndexInterval and after a reindex is requested, until ctx is done.
+func (uc *UseCaseSearch) StartContentIndexer(ctx context.Context, log logger.Logger) {
+	if uc.config.ContentIndexInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.config.ContentIndexInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uc.indexerWake:
+		}
+
+		if indexed := uc.ProcessContentIndex(ctx, log); indexed > 0 {
+			log.Info("indexed file contents", "count", indexed)
+		}
+	}
+}
+
+// ProcessContentIndex handles queued files until none are left and returns how many were processed.
+func (uc *UseCaseSearch) ProcessContentIndex(ctx context.Context, log logger.Logger) int {
+	processed := 0
+
+	for {
+		tasks, err := uc.contentRepo.ClaimTasks(ctx, time.Now().Add(-contentIndexStaleAfter), contentIndexClaimLimit)
+		if err != nil {
+			log.Error("func ProcessContentIndex: failed to claim tasks", "func", "ProcessContentIndex", "err", err)
+			return processed
+		}
+		if len(tasks) == 0 {
+			return processed
+		}
+
+		for _, task := range tasks {
+			if err := uc.indexFile(ctx, task); err != nil {
+				log.Error("func ProcessContentIndex: failed to index file", "func", "ProcessContentIndex", "file", task.FileID, "err", err)
+			}
+			processed++
+		}
+	}
+}
+
+func (uc *UseCaseSearch) indexFile(ctx context.Context, task *domain.ContentIndexTask) error {
+	file, err := uc.fileRepo.GetFile(ctx, task.CompanyID, task.FileID)
+	switch {
+	case stdErrors.Is(err, errors.ErrNotFound):
+		// Deleted after it was queued, the row goes away with the file.
+		return uc.finishTask(ctx, task, domain.ContentIndexSkipped, nil, "file no longer exists")
+	case err != nil:
+		// Left running, the task is claimed again once stale.
+		return err
+	case file.StoragePath == nil || file.MimeType == nil:
+		return uc.finishTask(ctx, task, domain.ContentIndexSkipped, nil, "file has no content")
+	case !textextract.Supports(*file.MimeType, file.Name):
+		return uc.finishTask(ctx, task, domain.ContentIndexSkipped, nil, "file type is not indexed")
+	case file.Size != nil && *file.Size > uc.config.ContentIndexMaxSize:
+		return uc.finishTask(ctx, task, domain.ContentIndexSkipped, nil, "file is too large to index")
+	}
+
+	text, err := uc.extractText(ctx, file)
+	switch {
+	case stdErrors.Is(err, textextract.ErrUnsupported):
+		return uc.finishTask(ctx, task, domain.ContentIndexSkipped, nil, err.Error())
+	case err != nil:
+		return uc.finishTask(ctx, task, domain.ContentIndexFailed, nil, err.Error())
+	}
+
+	return uc.finishTask(ctx, task, domain.ContentIndexIndexed, &text, "")
+}
+
+// extractText reads the text of a stored file. Documents are untrusted input,
+// so a parser panic fails the file instead of the worker.
+func (uc *UseCaseSearch) extractText(ctx context.Context, file *domain.File) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("text extraction failed: %v", r)
+		}
+	}()
+
+	reader, err := uc.storageRepo.GetFile(ctx, *file.StoragePath)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	return textextract.Extract(io.LimitReader(reader, uc.config.ContentIndexMaxSize), *file.MimeType, file.Name, maxIndexedText)
+}
+
+func (uc *UseCaseSearch) finishTask(ctx context.Context, task *domain.ContentIndexTask, status domain.ContentIndexStatus, content *string, reason string) error {
+	task.Status = status
+	task.Content = content
+	task.Error = nil
+	if reason != "" {
+		task.Error = &reason
+	}
+
+	return uc.contentRepo.SaveTask(ctx, task, uc.config.ContentIndexLanguage)
+}
+
+func (uc *UseCaseSearch) wakeIndexer() {
+	select {
+	case uc.indexerWake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,26 @@
+package ucSearch
+
+import (
+	"context"
+	"go-storage/internal/domain"
+	"io"
+	"time"
+)
+
+type FileRepository interface {
+	GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
+	GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error)
+	SearchFiles(ctx context.Context, companyID string, query *domain.SearchQuery) ([]*domain.SearchHit, error)
+	SearchContent(ctx context.Context, companyID, language string, query *domain.SearchQuery) ([]*domain.SearchHit, error)
+}
+
+type ContentRepository interface {
+	EnqueueCompany(ctx context.Context, companyID string) (int, error)
+	ClaimTasks(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.ContentIndexTask, error)
+	SaveTask(ctx context.Context, task *domain.ContentIndexTask, language string) error
+	GetStats(ctx context.Context, companyID string) (*domain.ContentIndexStats, error)
+}
+
+type StorageRepository interface {
+	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
+}
@@ -0,0 +1,105 @@
+package ucSearch
+
+import (
+	"context"
+	"strings"
+
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+)
+
+const maxSearchTextLength = 255
+
+type UseCaseSearch struct {
+	fileRepo    FileRepository
+	contentRepo ContentRepository
+	storageRepo StorageRepository
+	config      *config.FileServer
+
+	indexerWake chan struct{}
+}
+
+func NewUseCaseSearch(
+	fileRepo FileRepository,
+	contentRepo ContentRepository,
+	storageRepo StorageRepository,
+	config *config.FileServer,
+) *UseCaseSearch {
+	return &UseCaseSearch{
+		fileRepo:    fileRepo,
+		contentRepo: contentRepo,
+		storageRepo: storageRepo,
+		config:      config,
+		indexerWake: make(chan struct{}, 1),
+	}
+}
+
+// Search returns one page of the company items whose name matches the query,
+// limited to the subtree under query.Path and to the items the user can see.
+func (uc *UseCaseSearch) Search(ctx context.Context, companyID, userID string, query *domain.SearchQuery) (*domain.SearchPage, error) {
+	if err := uc.prepareQuery(ctx, companyID, userID, query); err != nil {
+		return nil, err
+	}
+
+	// One extra hit tells whether another page follows.
+	pageQuery := *query
+	pageQuery.Limit++
+
+	hits, err := uc.fileRepo.SearchFiles(ctx, companyID, &pageQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(hits, query.Limit), nil
+}
+
+// prepareQuery validates the query, applies the paging defaults and checks
+// that the folder searched in exists. Results are limited to what the user
+// could reach by listing folders.
+func (uc *UseCaseSearch) prepareQuery(ctx context.Context, companyID, userID string, query *domain.SearchQuery) error {
+	if companyID == "" {
+		return errors.BadRequest("company ID is required")
+	}
+	if userID == "" {
+		return errors.BadRequest("user ID is required")
+	}
+	query.VisibleTo = userID
+
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return errors.BadRequest("search query is required")
+	}
+	if len(query.Text) > maxSearchTextLength {
+		return errors.BadRequest("search query is too long")
+	}
+	if query.Limit <= 0 || query.Limit > domain.MaxSearchLimit {
+		query.Limit = domain.DefaultSearchLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	if !query.Path.IsRoot() {
+		folder, err := uc.fileRepo.GetFileByPath(ctx, companyID, &query.Path)
+		if err != nil {
+			return errors.NotFound("folder not found")
+		}
+		if folder.Type != domain.FileTypeFolder {
+			return errors.BadRequest("specified path is not a folder")
+		}
+	}
+
+	return nil
+}
+
+// newPage trims hits fetched with one extra item to limit.
+func newPage(hits []*domain.SearchHit, limit int) *domain.SearchPage {
+	page := &domain.SearchPage{Hits: hits}
+	if len(hits) > limit {
+		page.Hits = hits[:limit]
+		page.HasMore = true
+	}
+
+	return page
+}
@@ -0,0 +1,281 @@
+package ucSearch
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	customErrors "go-storage/pkg/errors"
+)
+
+type fileRepoMock struct {
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *fileRepoMock) GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error) {
+	args := m.Called(ctx, companyID, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *fileRepoMock) SearchFiles(ctx context.Context, companyID string, query *domain.SearchQuery) ([]*domain.SearchHit, error) {
+	args := m.Called(ctx, companyID, query)
+	var hits []*domain.SearchHit
+	if args.Get(0) != nil {
+		hits = args.Get(0).([]*domain.SearchHit)
+	}
+	return hits, args.Error(1)
+}
+
+func (m *fileRepoMock) SearchContent(ctx context.Context, companyID, language string, query *domain.SearchQuery) ([]*domain.SearchHit, error) {
+	args := m.Called(ctx, companyID, language, query)
+	var hits []*domain.SearchHit
+	if args.Get(0) != nil {
+		hits = args.Get(0).([]*domain.SearchHit)
+	}
+	return hits, args.Error(1)
+}
+
+type contentRepoMock struct {
+	mock.Mock
+}
+
+func (m *contentRepoMock) EnqueueCompany(ctx context.Context, companyID string) (int, error) {
+	args := m.Called(ctx, companyID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *contentRepoMock) ClaimTasks(ctx context.Context, staleBefore time.Time, limit int) ([]*domain.ContentIndexTask, error) {
+	args := m.Called(ctx, staleBefore, limit)
+	var tasks []*domain.ContentIndexTask
+	if args.Get(0) != nil {
+		tasks = args.Get(0).([]*domain.ContentIndexTask)
+	}
+	return tasks, args.Error(1)
+}
+
+func (m *contentRepoMock) SaveTask(ctx context.Context, task *domain.ContentIndexTask, language string) error {
+	args := m.Called(ctx, task, language)
+	return args.Error(0)
+}
+
+func (m *contentRepoMock) GetStats(ctx context.Context, companyID string) (*domain.ContentIndexStats, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ContentIndexStats), args.Error(1)
+}
+
+type storageRepoMock struct {
+	mock.Mock
+}
+
+func (m *storageRepoMock) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func newTestUseCase(files *fileRepoMock, contents *contentRepoMock, storage *storageRepoMock) *UseCaseSearch {
+	return NewUseCaseSearch(files, contents, storage, &config.FileServer{
+		ContentIndexMaxSize:  1024,
+		ContentIndexLanguage: "simple",
+	})
+}
+
+func hits(n int) []*domain.SearchHit {
+	result := make([]*domain.SearchHit, n)
+	for i := range result {
+		result[i] = &domain.SearchHit{File: &domain.File{ID: "file-id", Name: "report.pdf"}, Score: 2.5}
+	}
+	return result
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	appErr, ok := err.(*customErrors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, status, appErr.Code)
+}
+
+func TestUseCaseSearch_DefaultsAndNextPage(t *testing.T) {
+	files := new(fileRepoMock)
+	uc := newTestUseCase(files, new(contentRepoMock), new(storageRepoMock))
+
+	files.On("SearchFiles", mock.Anything, "company-id", mock.MatchedBy(func(query *domain.SearchQuery) bool {
+		return query.Text == "report" && query.Limit == domain.DefaultSearchLimit+1
+	})).Return(hits(domain.DefaultSearchLimit+1), nil)
+
+	page, err := uc.Search(context.Background(), "company-id", "user-id", &domain.SearchQuery{Text: "  report ", Path: domain.Path("/")})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Hits, domain.DefaultSearchLimit)
+	assert.True(t, page.HasMore)
+	files.AssertNotCalled(t, "GetFileByPath", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUseCaseSearch_LastPage(t *testing.T) {
+	files := new(fileRepoMock)
+	uc := newTestUseCase(files, new(contentRepoMock), new(storageRepoMock))
+
+	files.On("GetFileByPath", mock.Anything, "company-id", mock.Anything).
+		Return(&domain.File{ID: "folder-id", Type: domain.FileTypeFolder}, nil)
+	files.On("SearchFiles", mock.Anything, "company-id", mock.Anything).Return(hits(3), nil)
+
+	page, err := uc.Search(context.Background(), "company-id", "user-id", &domain.SearchQuery{Text: "report", Path: domain.Path("/docs"), Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Hits, 3)
+	assert.False(t, page.HasMore)
+}
+
+func TestUseCaseSearch_EmptyQuery(t *testing.T) {
+	uc := newTestUseCase(new(fileRepoMock), new(contentRepoMock), new(storageRepoMock))
+
+	_, err := uc.Search(context.Background(), "company-id", "user-id", &domain.SearchQuery{Text: "   ", Path: domain.Path("/")})
+
+	assertStatus(t, err, 400)
+}
+
+func TestUseCaseSearch_FolderNotFound(t *testing.T) {
+	files := new(fileRepoMock)
+	uc := newTestUseCase(files, new(contentRepoMock), new(storageRepoMock))
+
+	files.On("GetFileByPath", mock.Anything, "company-id", mock.Anything).Return(nil, customErrors.NotFound("file not found"))
+
+	_, err := uc.Search(context.Background(), "company-id", "user-id", &domain.SearchQuery{Text: "report", Path: domain.Path("/missing")})
+
+	assertStatus(t, err, 404)
+	files.AssertNotCalled(t, "SearchFiles", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func strPtr(s string) *string { return &s }
+
+func int64Ptr(n int64) *int64 { return &n }
+
+func TestUseCaseSearch_SearchContent(t *testing.T) {
+	files := new(fileRepoMock)
+	uc := newTestUseCase(files, new(contentRepoMock), new(storageRepoMock))
+
+	files.On("SearchContent", mock.Anything, "company-id", "simple", mock.MatchedBy(func(query *domain.SearchQuery) bool {
+		return query.Text == "quarterly report" && query.Limit == 3
+	})).Return(hits(3), nil)
+
+	page, err := uc.SearchContent(context.Background(), "company-id", "user-id", &domain.SearchQuery{Text: "quarterly report", Path: domain.Path("/"), Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Hits, 2)
+	assert.True(t, page.HasMore)
+}
+
+func TestUseCaseSearch_ReindexCompany(t *testing.T) {
+	contents := new(contentRepoMock)
+	uc := newTestUseCase(new(fileRepoMock), contents, new(storageRepoMock))
+
+	contents.On("EnqueueCompany", mock.Anything, "company-id").Return(12, nil)
+
+	queued, err := uc.ReindexCompany(context.Background(), "company-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, queued)
+	assert.Len(t, uc.indexerWake, 1)
+}
+
+func TestUseCaseSearch_IndexFile(t *testing.T) {
+	files := new(fileRepoMock)
+	contents := new(contentRepoMock)
+	storage := new(storageRepoMock)
+	uc := newTestUseCase(files, contents, storage)
+
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(&domain.File{
+		ID: "file-id", Name: "notes.txt", Type: domain.FileTypeFile,
+		MimeType: strPtr("text/plain"), Size: int64Ptr(21), StoragePath: strPtr("company-id/file-id"),
+	}, nil)
+	storage.On("GetFile", mock.Anything, "company-id/file-id").Return(io.NopCloser(strings.NewReader("  budget for 2025\x00 ")), nil)
+	contents.On("SaveTask", mock.Anything, mock.MatchedBy(func(task *domain.ContentIndexTask) bool {
+		return task.Status == domain.ContentIndexIndexed && *task.Content == "budget for 2025" && task.Error == nil
+	}), "simple").Return(nil)
+
+	err := uc.indexFile(context.Background(), &domain.ContentIndexTask{FileID: "file-id", CompanyID: "company-id"})
+
+	assert.NoError(t, err)
+	contents.AssertExpectations(t)
+}
+
+func TestUseCaseSearch_IndexFileSkipped(t *testing.T) {
+	tests := []struct {
+		name string
+		file *domain.File
+		err  error
+	}{
+		{name: "deleted", err: customErrors.NotFound("file not found")},
+		{name: "unsupported type", file: &domain.File{
+			Name: "photo.png", Type: domain.FileTypeFile,
+			MimeType: strPtr("image/png"), Size: int64Ptr(10), StoragePath: strPtr("key"),
+		}},
+		{name: "too large", file: &domain.File{
+			Name: "big.txt", Type: domain.FileTypeFile,
+			MimeType: strPtr("text/plain"), Size: int64Ptr(4096), StoragePath: strPtr("key"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := new(fileRepoMock)
+			contents := new(contentRepoMock)
+			storage := new(storageRepoMock)
+			uc := newTestUseCase(files, contents, storage)
+
+			files.On("GetFile", mock.Anything, "company-id", "file-id").Return(tt.file, tt.err)
+			contents.On("SaveTask", mock.Anything, mock.MatchedBy(func(task *domain.ContentIndexTask) bool {
+				return task.Status == domain.ContentIndexSkipped && task.Content == nil && task.Error != nil
+			}), "simple").Return(nil)
+
+			err := uc.indexFile(context.Background(), &domain.ContentIndexTask{FileID: "file-id", CompanyID: "company-id"})
+
+			assert.NoError(t, err)
+			contents.AssertExpectations(t)
+			storage.AssertNotCalled(t, "GetFile", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUseCaseSearch_IndexFileFailed(t *testing.T) {
+	files := new(fileRepoMock)
+	contents := new(contentRepoMock)
+	storage := new(storageRepoMock)
+	uc := newTestUseCase(files, contents, storage)
+
+	files.On("GetFile", mock.Anything, "company-id", "file-id").Return(&domain.File{
+		Name: "data.json", Type: domain.FileTypeFile,
+		MimeType: strPtr("application/json"), Size: int64Ptr(8), StoragePath: strPtr("key"),
+	}, nil)
+	storage.On("GetFile", mock.Anything, "key").Return(io.NopCloser(strings.NewReader(`{"a": }`)), nil)
+	contents.On("SaveTask", mock.Anything, mock.MatchedBy(func(task *domain.ContentIndexTask) bool {
+		return task.Status == domain.ContentIndexFailed && task.Error != nil
+	}), "simple").Return(nil)
+
+	err := uc.indexFile(context.Background(), &domain.ContentIndexTask{FileID: "file-id", CompanyID: "company-id"})
+
+	assert.NoError(t, err)
+	contents.AssertExpectations(t)
+}
@@ -0,0 +1,32 @@
+package ucShare
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go-storage/internal/domain"
+)
+
+type ShareLinkRepository interface {
+	CreateLink(ctx context.Context, link *domain.ShareLink) (*domain.ShareLink, error)
+	GetLinkByToken(ctx context.Context, tokenHash string) (*domain.ShareLink, error)
+	ListLinks(ctx context.Context, companyID, userID string) ([]*domain.ShareLink, error)
+	DeleteLink(ctx context.Context, companyID, userID, linkID string) error
+	RecordDownload(ctx context.Context, linkID string) error
+}
+
+type FileRepository interface {
+	GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
+	GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error)
+	ListFolder(ctx context.Context, companyID string, parentID *string, query *domain.FolderListQuery) ([]*domain.File, error)
+	TouchFile(ctx context.Context, companyID, fileID string, accessedAt time.Time) error
+}
+
+type AccessRepository interface {
+	GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error)
+}
+
+type StorageRepository interface {
+	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
+}
@@ -0,0 +1,255 @@
+package ucShare
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go-storage/internal/domain"
+	"go-storage/pkg/auth"
+	"go-storage/pkg/errors"
+)
+
+type UseCaseShare struct {
+	linkRepo    ShareLinkRepository
+	fileRepo    FileRepository
+	accessRepo  AccessRepository
+	storageRepo StorageRepository
+}
+
+func NewUseCaseShare(linkRepo ShareLinkRepository, fileRepo FileRepository, accessRepo AccessRepository, storageRepo StorageRepository) *UseCaseShare {
+	return &UseCaseShare{
+		linkRepo:    linkRepo,
+		fileRepo:    fileRepo,
+		accessRepo:  accessRepo,
+		storageRepo: storageRepo,
+	}
+}
+
+// CreateLink creates a share link for a file or folder the user may edit. The
+// returned link carries its token, which cannot be retrieved again later.
+func (uc *UseCaseShare) CreateLink(ctx context.Context, companyID, userID, fileID string, options *domain.ShareLinkOptions) (*domain.ShareLink, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	file, err := uc.fileRepo.GetFile(ctx, companyID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := uc.accessRepo.GetAccessRole(ctx, companyID, userID, file.FullPath)
+	if err != nil {
+		return nil, err
+	}
+	if role == domain.AccessNone {
+		return nil, errors.NotFound("file not found")
+	}
+	if !role.Allows(domain.AccessEditor) {
+		return nil, errors.Forbidden("editor access is required to share an item")
+	}
+
+	now := time.Now()
+	if options.ExpiresAt != nil && !options.ExpiresAt.After(now) {
+		return nil, errors.BadRequest("expiry must be in the future")
+	}
+
+	if options.MaxDownloads != nil && *options.MaxDownloads < 1 {
+		return nil, errors.BadRequest("max downloads must be at least 1")
+	}
+
+	allowedIPs, err := domain.NormalizeAllowedIPs(options.AllowedIPs)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	link := &domain.ShareLink{
+		ID:           uuid.NewString(),
+		CompanyID:    companyID,
+		UserCreateID: userID,
+		FileID:       fileID,
+		ExpiresAt:    options.ExpiresAt,
+		MaxDownloads: options.MaxDownloads,
+		AllowedIPs:   allowedIPs,
+		CreatedAt:    now,
+	}
+
+	if options.Password != "" {
+		if len(options.Password) > domain.MaxSharePasswordBytes {
+			return nil, errors.BadRequest("password is too long")
+		}
+		hash, err := auth.Hash(options.Password)
+		if err != nil {
+			return nil, errors.InternalServer("unable to hash password")
+		}
+		link.PasswordHash = &hash
+	}
+
+	link.Token, link.TokenHash, err = domain.NewShareToken()
+	if err != nil {
+		return nil, errors.InternalServer("unable to generate share token")
+	}
+
+	return uc.linkRepo.CreateLink(ctx, link)
+}
+
+func (uc *UseCaseShare) ListLinks(ctx context.Context, companyID, userID string) ([]*domain.ShareLink, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	return uc.linkRepo.ListLinks(ctx, companyID, userID)
+}
+
+// RevokeLink deletes one of the user's links, it stops working immediately.
+func (uc *UseCaseShare) RevokeLink(ctx context.Context, companyID, userID, linkID string) error {
+	if companyID == "" {
+		return errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return errors.BadRequest("user ID is required")
+	}
+
+	return uc.linkRepo.DeleteLink(ctx, companyID, userID, linkID)
+}
+
+// AccessLink checks that the link may be opened by the visitor and returns it
+// along with the shared item. The address is checked before the password, so
+// passwords cannot be guessed from addresses that are not allowed.
+func (uc *UseCaseShare) AccessLink(ctx context.Context, access *domain.ShareAccess) (*domain.ShareLink, *domain.File, error) {
+	if access.Token == "" {
+		return nil, nil, errors.NotFound("share link not found")
+	}
+
+	link, err := uc.linkRepo.GetLinkByToken(ctx, domain.HashShareToken(access.Token))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if link.IsExpired(time.Now()) {
+		return nil, nil, errors.Gone("share link has expired")
+	}
+
+	if !link.AllowsIP(access.ClientIP) {
+		return nil, nil, errors.Forbidden("share link cannot be opened from this address")
+	}
+
+	if link.HasPassword() {
+		if access.Password == "" {
+			return nil, nil, errors.Unauthorized("share link requires a password")
+		}
+		if !auth.CheckPasswordHash(access.Password, *link.PasswordHash) {
+			return nil, nil, errors.Unauthorized("incorrect share link password")
+		}
+	}
+
+	// An item in the trash is not shared until it is restored.
+	target, err := uc.fileRepo.GetFile(ctx, link.CompanyID, link.FileID)
+	if err != nil {
+		return nil, nil, errors.NotFound("shared item not found")
+	}
+
+	return link, target, nil
+}
+
+// ListSharedFolder lists one page of a shared folder or of a folder below it.
+// The query path is relative to the shared folder.
+func (uc *UseCaseShare) ListSharedFolder(ctx context.Context, link *domain.ShareLink, target *domain.File, query *domain.FolderListQuery) (*domain.SharedFolder, error) {
+	if target.Type != domain.FileTypeFolder {
+		return nil, errors.BadRequest("shared item is not a folder")
+	}
+	if query.Cursor != nil && (query.Cursor.Sort != query.Sort || query.Cursor.Order != query.Order) {
+		return nil, errors.BadRequest("cursor does not match the requested sort")
+	}
+	if query.Limit <= 0 || query.Limit > domain.MaxFolderListLimit {
+		query.Limit = domain.DefaultFolderListLimit
+	}
+
+	folder := target
+	if !query.Path.IsRoot() {
+		fullPath := domain.Path(target.FullPath.String() + query.Path.String())
+		found, err := uc.fileRepo.GetFileByPath(ctx, link.CompanyID, &fullPath)
+		if err != nil {
+			return nil, errors.NotFound("folder not found")
+		}
+		if found.Type != domain.FileTypeFolder {
+			return nil, errors.BadRequest("specified path is not a folder")
+		}
+		folder = found
+	}
+
+	// One extra item tells whether another page follows.
+	pageQuery := *query
+	pageQuery.Path = folder.FullPath
+	pageQuery.Recursive = false
+	pageQuery.Limit++
+
+	items, err := uc.fileRepo.ListFolder(ctx, link.CompanyID, &folder.ID, &pageQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.FolderPage{Items: items}
+	if len(items) > query.Limit {
+		page.Items = items[:query.Limit]
+		page.NextCursor = domain.NewFolderCursor(page.Items[query.Limit-1], query.Sort, query.Order).Encode()
+	}
+
+	return &domain.SharedFolder{Root: target, Folder: folder, Page: page}, nil
+}
+
+// OpenSharedFile opens the shared file, or the file fileID inside a shared
+// folder, and counts the download against the link's limit.
+func (uc *UseCaseShare) OpenSharedFile(ctx context.Context, link *domain.ShareLink, target *domain.File, fileID string) (*domain.File, io.ReadCloser, error) {
+	file := target
+	if fileID != "" && fileID != target.ID {
+		if target.Type != domain.FileTypeFolder {
+			return nil, nil, errors.NotFound("file not found")
+		}
+
+		found, err := uc.fileRepo.GetFile(ctx, link.CompanyID, fileID)
+		if err != nil || !strings.HasPrefix(found.FullPath.String(), target.FullPath.String()+"/") {
+			return nil, nil, errors.NotFound("file not found")
+		}
+		file = found
+	}
+
+	if file.Type != domain.FileTypeFile {
+		return nil, nil, errors.BadRequest("specified item is not a file")
+	}
+
+	// Public links only serve content the malware scan found clean.
+	if !file.ScanStatus.Downloadable(false) {
+		return nil, nil, errors.Forbidden("file is not available for download")
+	}
+
+	if file.StoragePath == nil {
+		return nil, nil, errors.InternalServer("file storage path not found")
+	}
+
+	reader, err := uc.storageRepo.GetFile(ctx, *file.StoragePath)
+	if err != nil {
+		return nil, nil, errors.InternalServer("failed to retrieve file from storage")
+	}
+
+	if err := uc.linkRepo.RecordDownload(ctx, link.ID); err != nil {
+		reader.Close()
+		return nil, nil, err
+	}
+
+	// Recording the access for the lifecycle rules is best effort.
+	_ = uc.fileRepo.TouchFile(ctx, link.CompanyID, file.ID, time.Now())
+
+	return file, reader, nil
+}
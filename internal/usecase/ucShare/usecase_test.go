@@ -0,0 +1,328 @@
+package ucShare
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/domain"
+	"go-storage/pkg/auth"
+	customErrors "go-storage/pkg/errors"
+)
+
+type linkRepoMock struct {
+	mock.Mock
+}
+
+func (m *linkRepoMock) CreateLink(ctx context.Context, link *domain.ShareLink) (*domain.ShareLink, error) {
+	args := m.Called(ctx, link)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ShareLink), args.Error(1)
+}
+
+func (m *linkRepoMock) GetLinkByToken(ctx context.Context, tokenHash string) (*domain.ShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ShareLink), args.Error(1)
+}
+
+func (m *linkRepoMock) ListLinks(ctx context.Context, companyID, userID string) ([]*domain.ShareLink, error) {
+	args := m.Called(ctx, companyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ShareLink), args.Error(1)
+}
+
+func (m *linkRepoMock) DeleteLink(ctx context.Context, companyID, userID, linkID string) error {
+	return m.Called(ctx, companyID, userID, linkID).Error(0)
+}
+
+func (m *linkRepoMock) RecordDownload(ctx context.Context, linkID string) error {
+	return m.Called(ctx, linkID).Error(0)
+}
+
+type fileRepoMock struct {
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *fileRepoMock) GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error) {
+	args := m.Called(ctx, companyID, *path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.File), args.Error(1)
+}
+
+func (m *fileRepoMock) ListFolder(ctx context.Context, companyID string, parentID *string, query *domain.FolderListQuery) ([]*domain.File, error) {
+	args := m.Called(ctx, companyID, parentID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.File), args.Error(1)
+}
+
+func (m *fileRepoMock) TouchFile(ctx context.Context, companyID, fileID string, accessedAt time.Time) error {
+	args := m.Called(ctx, companyID, fileID, accessedAt)
+	return args.Error(0)
+}
+
+type accessRepoMock struct {
+	mock.Mock
+}
+
+func (m *accessRepoMock) GetAccessRole(ctx context.Context, companyID, userID string, path domain.Path) (domain.AccessRole, error) {
+	args := m.Called(ctx, companyID, userID, path)
+	return args.Get(0).(domain.AccessRole), args.Error(1)
+}
+
+type storageRepoMock struct {
+	mock.Mock
+}
+
+func (m *storageRepoMock) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+type mocks struct {
+	links   *linkRepoMock
+	files   *fileRepoMock
+	access  *accessRepoMock
+	storage *storageRepoMock
+}
+
+func setupUseCase() (*UseCaseShare, *mocks) {
+	m := &mocks{links: new(linkRepoMock), files: new(fileRepoMock), access: new(accessRepoMock), storage: new(storageRepoMock)}
+	return NewUseCaseShare(m.links, m.files, m.access, m.storage), m
+}
+
+func sharedFolder() *domain.File {
+	return &domain.File{ID: "folder-id", Name: "public", Type: domain.FileTypeFolder, FullPath: "/projects/public", CompanyId: "company-id"}
+}
+
+func sharedFile(id string, path domain.Path) *domain.File {
+	storagePath := "companies/company-id/files/" + id
+	return &domain.File{ID: id, Name: path.GetName(), Type: domain.FileTypeFile, FullPath: path, CompanyId: "company-id", StoragePath: &storagePath, ScanStatus: domain.ScanStatusClean}
+}
+
+func TestUseCaseShare_CreateLink(t *testing.T) {
+	t.Run("hashes the password and token", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		expiresAt := time.Now().Add(24 * time.Hour)
+		m.files.On("GetFile", mock.Anything, "company-id", "file-id").Return(sharedFile("file-id", "/report.pdf"), nil)
+		m.access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/report.pdf")).Return(domain.AccessOwner, nil)
+		var link *domain.ShareLink
+		m.links.On("CreateLink", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { link = args.Get(1).(*domain.ShareLink) }).
+			Return(&domain.ShareLink{}, nil)
+
+		_, err := uc.CreateLink(context.Background(), "company-id", "user-id", "file-id", &domain.ShareLinkOptions{
+			Password:   "s3cret",
+			ExpiresAt:  &expiresAt,
+			AllowedIPs: []string{"10.1.2.3", "192.168.0.0/16"},
+		})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, link.Token)
+		assert.Equal(t, domain.HashShareToken(link.Token), link.TokenHash)
+		assert.True(t, auth.CheckPasswordHash("s3cret", *link.PasswordHash))
+		assert.Equal(t, []string{"10.1.2.3/32", "192.168.0.0/16"}, link.AllowedIPs)
+	})
+
+	t.Run("rejects an expiry in the past", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		expiresAt := time.Now().Add(-time.Minute)
+		m.files.On("GetFile", mock.Anything, "company-id", "file-id").Return(sharedFile("file-id", "/report.pdf"), nil)
+		m.access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/report.pdf")).Return(domain.AccessOwner, nil)
+
+		link, err := uc.CreateLink(context.Background(), "company-id", "user-id", "file-id", &domain.ShareLinkOptions{ExpiresAt: &expiresAt})
+
+		assert.Error(t, err)
+		assert.Nil(t, link)
+		m.links.AssertNotCalled(t, "CreateLink", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an invalid allowed IP", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		m.files.On("GetFile", mock.Anything, "company-id", "file-id").Return(sharedFile("file-id", "/report.pdf"), nil)
+		m.access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/report.pdf")).Return(domain.AccessOwner, nil)
+
+		link, err := uc.CreateLink(context.Background(), "company-id", "user-id", "file-id", &domain.ShareLinkOptions{AllowedIPs: []string{"example.com"}})
+
+		assert.Error(t, err)
+		assert.Nil(t, link)
+	})
+
+	t.Run("viewer cannot share", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		m.files.On("GetFile", mock.Anything, "company-id", "file-id").Return(sharedFile("file-id", "/report.pdf"), nil)
+		m.access.On("GetAccessRole", mock.Anything, "company-id", "user-id", domain.Path("/report.pdf")).Return(domain.AccessViewer, nil)
+
+		link, err := uc.CreateLink(context.Background(), "company-id", "user-id", "file-id", &domain.ShareLinkOptions{})
+
+		assert.Nil(t, link)
+		var appErr *customErrors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 403, appErr.Code)
+		m.links.AssertNotCalled(t, "CreateLink", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUseCaseShare_AccessLink(t *testing.T) {
+	hash, _ := auth.Hash("s3cret")
+	expired := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name     string
+		link     *domain.ShareLink
+		access   domain.ShareAccess
+		wantCode int
+	}{
+		{"expired", &domain.ShareLink{ExpiresAt: &expired}, domain.ShareAccess{ClientIP: "1.2.3.4"}, 410},
+		{"address not allowed", &domain.ShareLink{AllowedIPs: []string{"10.0.0.0/8"}}, domain.ShareAccess{ClientIP: "1.2.3.4"}, 403},
+		{"password missing", &domain.ShareLink{PasswordHash: &hash}, domain.ShareAccess{ClientIP: "1.2.3.4"}, 401},
+		{"password wrong", &domain.ShareLink{PasswordHash: &hash}, domain.ShareAccess{ClientIP: "1.2.3.4", Password: "guess"}, 401},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc, m := setupUseCase()
+
+			tc.access.Token = "token"
+			m.links.On("GetLinkByToken", mock.Anything, domain.HashShareToken("token")).Return(tc.link, nil)
+
+			link, target, err := uc.AccessLink(context.Background(), &tc.access)
+
+			assert.Nil(t, link)
+			assert.Nil(t, target)
+			var appErr *customErrors.AppError
+			assert.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tc.wantCode, appErr.Code)
+			m.files.AssertNotCalled(t, "GetFile", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("opens with the right password from an allowed address", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		stored := &domain.ShareLink{ID: "link-id", CompanyID: "company-id", FileID: "folder-id", PasswordHash: &hash, AllowedIPs: []string{"10.0.0.0/8"}}
+		m.links.On("GetLinkByToken", mock.Anything, domain.HashShareToken("token")).Return(stored, nil)
+		m.files.On("GetFile", mock.Anything, "company-id", "folder-id").Return(sharedFolder(), nil)
+
+		link, target, err := uc.AccessLink(context.Background(), &domain.ShareAccess{Token: "token", Password: "s3cret", ClientIP: "10.20.30.40"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored, link)
+		assert.Equal(t, "folder-id", target.ID)
+	})
+}
+
+func TestUseCaseShare_ListSharedFolder(t *testing.T) {
+	uc, m := setupUseCase()
+
+	link := &domain.ShareLink{ID: "link-id", CompanyID: "company-id"}
+	sub := &domain.File{ID: "sub-id", Name: "2025", Type: domain.FileTypeFolder, FullPath: "/projects/public/2025"}
+	items := []*domain.File{sharedFile("a", "/projects/public/2025/a.txt"), sharedFile("b", "/projects/public/2025/b.txt")}
+
+	m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/projects/public/2025")).Return(sub, nil)
+	m.files.On("ListFolder", mock.Anything, "company-id", &sub.ID, mock.MatchedBy(func(q *domain.FolderListQuery) bool {
+		return q.Path == "/projects/public/2025" && q.Limit == 2
+	})).Return(items, nil)
+
+	result, err := uc.ListSharedFolder(context.Background(), link, sharedFolder(), &domain.FolderListQuery{
+		Path: "/2025", Sort: domain.FolderSortName, Order: domain.SortOrderAsc, Limit: 1,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Page.Items, 1)
+	assert.NotEmpty(t, result.Page.NextCursor)
+	assert.Equal(t, domain.Path("/2025/a.txt"), result.RelativePath(result.Page.Items[0].FullPath))
+}
+
+func TestUseCaseShare_OpenSharedFile(t *testing.T) {
+	t.Run("file inside the shared folder", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		link := &domain.ShareLink{ID: "link-id", CompanyID: "company-id"}
+		file := sharedFile("file-id", "/projects/public/a.txt")
+		m.files.On("GetFile", mock.Anything, "company-id", "file-id").Return(file, nil)
+		m.storage.On("GetFile", mock.Anything, *file.StoragePath).Return(io.NopCloser(strings.NewReader("content")), nil)
+		m.links.On("RecordDownload", mock.Anything, "link-id").Return(nil)
+		m.files.On("TouchFile", mock.Anything, "company-id", "file-id", mock.Anything).Return(nil)
+
+		opened, reader, err := uc.OpenSharedFile(context.Background(), link, sharedFolder(), "file-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, file, opened)
+		assert.NotNil(t, reader)
+		m.links.AssertExpectations(t)
+	})
+
+	t.Run("file outside the shared folder", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		link := &domain.ShareLink{ID: "link-id", CompanyID: "company-id"}
+		m.files.On("GetFile", mock.Anything, "company-id", "other-id").Return(sharedFile("other-id", "/projects/public-old/a.txt"), nil)
+
+		opened, reader, err := uc.OpenSharedFile(context.Background(), link, sharedFolder(), "other-id")
+
+		assert.Error(t, err)
+		assert.Nil(t, opened)
+		assert.Nil(t, reader)
+		m.links.AssertNotCalled(t, "RecordDownload", mock.Anything, mock.Anything)
+	})
+
+	t.Run("download limit reached", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		link := &domain.ShareLink{ID: "link-id", CompanyID: "company-id"}
+		file := sharedFile("file-id", "/report.pdf")
+		m.storage.On("GetFile", mock.Anything, *file.StoragePath).Return(io.NopCloser(strings.NewReader("content")), nil)
+		m.links.On("RecordDownload", mock.Anything, "link-id").Return(customErrors.Gone("share link download limit reached"))
+
+		opened, reader, err := uc.OpenSharedFile(context.Background(), link, file, "")
+
+		assert.Error(t, err)
+		assert.Nil(t, opened)
+		assert.Nil(t, reader)
+	})
+	t.Run("file not scanned yet", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		link := &domain.ShareLink{ID: "link-id", CompanyID: "company-id"}
+		file := sharedFile("file-id", "/report.pdf")
+		file.ScanStatus = domain.ScanStatusPending
+
+		opened, reader, err := uc.OpenSharedFile(context.Background(), link, file, "")
+
+		assert.Error(t, err)
+		assert.Nil(t, opened)
+		assert.Nil(t, reader)
+		m.storage.AssertNotCalled(t, "GetFile", mock.Anything, mock.Anything)
+		m.links.AssertNotCalled(t, "RecordDownload", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,31 @@
+package ucTrash
+
+import (
+	"context"
+	"go-storage/internal/domain"
+)
+
+type TrashRepository interface {
+	GetDeletedItems(ctx context.Context, companyID, userID string) ([]*domain.File, error)
+	GetDeletedItem(ctx context.Context, companyID, itemID string) (*domain.File, error)
+	GetDeletedItemRole(ctx context.Context, userID string, item *domain.File) (domain.AccessRole, error)
+	RestoreItem(ctx context.Context, item *domain.File, newPath domain.Path, newParentID *string) (*domain.File, error)
+	PurgeItem(ctx context.Context, companyID, itemID string) ([]string, error)
+	GetExpiredItems(ctx context.Context, defaultRetentionDays, limit int) ([]*domain.File, error)
+
+	GetSettings(ctx context.Context, companyID string, defaultRetentionDays int) (*domain.TrashSettings, error)
+	UpdateSettings(ctx context.Context, settings *domain.TrashSettings) (*domain.TrashSettings, error)
+}
+
+type FileRepository interface {
+	GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error)
+	GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error)
+}
+
+type StorageRepository interface {
+	DeleteFile(ctx context.Context, key string) error
+}
+
+type VersionRepository interface {
+	CountStoragePathRefs(ctx context.Context, storagePath string) (int, error)
+}
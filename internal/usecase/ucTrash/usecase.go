@@ -0,0 +1,315 @@
+package ucTrash
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	"go-storage/pkg/errors"
+	"go-storage/pkg/logger"
+)
+
+const (
+	purgeBatchSize     = 100
+	maxRestoreAttempts = 100
+	maxRestoreDepth    = 64
+	maxRetentionDays   = 3650
+)
+
+type UseCaseTrash struct {
+	trashRepo   TrashRepository
+	fileRepo    FileRepository
+	storageRepo StorageRepository
+	versionRepo VersionRepository
+	config      *config.FileServer
+}
+
+func NewUseCaseTrash(
+	trashRepo TrashRepository,
+	fileRepo FileRepository,
+	storageRepo StorageRepository,
+	versionRepo VersionRepository,
+	config *config.FileServer,
+) *UseCaseTrash {
+	return &UseCaseTrash{
+		trashRepo:   trashRepo,
+		fileRepo:    fileRepo,
+		storageRepo: storageRepo,
+		versionRepo: versionRepo,
+		config:      config,
+	}
+}
+
+// ListTrash returns the deleted items the user had access to.
+func (uc *UseCaseTrash) ListTrash(ctx context.Context, companyID, userID string) ([]*domain.TrashItem, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	settings, err := uc.trashRepo.GetSettings(ctx, companyID, uc.config.TrashRetentionDays)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := uc.trashRepo.GetDeletedItems(ctx, companyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*domain.TrashItem, 0, len(files))
+	for _, file := range files {
+		items = append(items, domain.NewTrashItem(file, settings.Retention()))
+	}
+
+	return items, nil
+}
+
+// RestoreItem brings an item back to its original folder. A parent that is itself
+// in the trash is restored first, a parent that no longer exists puts the item in
+// the root, and a name taken in the meantime gets a numeric suffix. The user
+// needs the editor role on the item, and on a deleted parent to restore it too.
+func (uc *UseCaseTrash) RestoreItem(ctx context.Context, companyID, userID, itemID string) (*domain.File, error) {
+	item, err := uc.getItem(ctx, companyID, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.restore(ctx, userID, item, 0)
+}
+
+func (uc *UseCaseTrash) restore(ctx context.Context, userID string, item *domain.File, depth int) (*domain.File, error) {
+	if depth > maxRestoreDepth {
+		return nil, errors.InternalServer("folder hierarchy is too deep to restore")
+	}
+
+	parentPath := domain.Path("/")
+	var parentID *string
+
+	if item.ParentID != nil {
+		parent, err := uc.fileRepo.GetFile(ctx, item.CompanyId, *item.ParentID)
+		switch {
+		case err == nil:
+			parentPath = parent.FullPath
+			parentID = &parent.ID
+		case !stdErrors.Is(err, errors.ErrNotFound):
+			return nil, err
+		default:
+			deletedParent, err := uc.trashRepo.GetDeletedItem(ctx, item.CompanyId, *item.ParentID)
+			if err != nil && !stdErrors.Is(err, errors.ErrNotFound) {
+				return nil, err
+			}
+
+			// A deleted parent the user may not edit stays in the trash.
+			if deletedParent != nil {
+				role, err := uc.trashRepo.GetDeletedItemRole(ctx, userID, deletedParent)
+				if err != nil {
+					return nil, err
+				}
+				if !role.Allows(domain.AccessEditor) {
+					deletedParent = nil
+				}
+			}
+
+			if deletedParent != nil {
+				parent, err := uc.restore(ctx, userID, deletedParent, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				parentPath = parent.FullPath
+				parentID = &parent.ID
+
+				// Items deleted together with the folder come back with it.
+				if restored, err := uc.fileRepo.GetFile(ctx, item.CompanyId, item.ID); err == nil {
+					return restored, nil
+				}
+			}
+		}
+	} else if !item.FullPath.GetParent().IsRoot() {
+		// Rows stored without parent_id only know their folder by path.
+		folderPath := item.FullPath.GetParent()
+		parent, err := uc.fileRepo.GetFileByPath(ctx, item.CompanyId, &folderPath)
+		switch {
+		case err == nil && parent.Type == domain.FileTypeFolder:
+			parentPath = parent.FullPath
+			parentID = &parent.ID
+		case err != nil && !stdErrors.Is(err, errors.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	name, err := uc.availableName(ctx, item, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.trashRepo.RestoreItem(ctx, item, parentPath.Join(name), parentID)
+}
+
+func (uc *UseCaseTrash) availableName(ctx context.Context, item *domain.File, parentPath domain.Path) (string, error) {
+	ext := ""
+	if item.Type == domain.FileTypeFile {
+		ext = filepath.Ext(item.Name)
+	}
+	base := strings.TrimSuffix(item.Name, ext)
+
+	candidate := item.Name
+	for i := 1; i <= maxRestoreAttempts; i++ {
+		path := parentPath.Join(candidate)
+		_, err := uc.fileRepo.GetFileByPath(ctx, item.CompanyId, &path)
+		if err != nil {
+			if stdErrors.Is(err, errors.ErrNotFound) {
+				return candidate, nil
+			}
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+
+	return "", errors.Conflict("unable to find a free name to restore the item")
+}
+
+// PurgeItem permanently deletes an item the user may edit, along with its contents.
+func (uc *UseCaseTrash) PurgeItem(ctx context.Context, companyID, userID, itemID string) error {
+	if _, err := uc.getItem(ctx, companyID, userID, itemID); err != nil {
+		return err
+	}
+
+	paths, err := uc.trashRepo.PurgeItem(ctx, companyID, itemID)
+	if err != nil {
+		return err
+	}
+
+	uc.deleteObjects(ctx, paths)
+
+	return nil
+}
+
+// PurgeExpired permanently deletes every item that outlived its company's
+// retention window and returns how many items were removed.
+func (uc *UseCaseTrash) PurgeExpired(ctx context.Context) (int, error) {
+	purged := 0
+
+	for {
+		items, err := uc.trashRepo.GetExpiredItems(ctx, uc.config.TrashRetentionDays, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		batchPurged := 0
+		for _, item := range items {
+			paths, err := uc.trashRepo.PurgeItem(ctx, item.CompanyId, item.ID)
+			if err != nil {
+				continue
+			}
+			uc.deleteObjects(ctx, paths)
+			batchPurged++
+		}
+		purged += batchPurged
+
+		if len(items) < purgeBatchSize || batchPurged == 0 {
+			return purged, nil
+		}
+	}
+}
+
+// StartPurger runs PurgeExpired on every TrashPurgeInterval until ctx is done.
+func (uc *UseCaseTrash) StartPurger(ctx context.Context, log logger.Logger) {
+	if uc.config.TrashPurgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.config.TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := uc.PurgeExpired(ctx)
+			if err != nil {
+				log.Error("func StartPurger: failed to purge expired trash", "func", "StartPurger", "err", err)
+				continue
+			}
+			if purged > 0 {
+				log.Info("purged expired trash items", "count", purged)
+			}
+		}
+	}
+}
+
+func (uc *UseCaseTrash) GetSettings(ctx context.Context, companyID string) (*domain.TrashSettings, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	return uc.trashRepo.GetSettings(ctx, companyID, uc.config.TrashRetentionDays)
+}
+
+func (uc *UseCaseTrash) UpdateSettings(ctx context.Context, companyID string, retentionDays int) (*domain.TrashSettings, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if retentionDays < 1 || retentionDays > maxRetentionDays {
+		return nil, errors.BadRequest(fmt.Sprintf("retention days must be between 1 and %d", maxRetentionDays))
+	}
+
+	return uc.trashRepo.UpdateSettings(ctx, &domain.TrashSettings{
+		CompanyID:     companyID,
+		RetentionDays: retentionDays,
+	})
+}
+
+// getItem returns the deleted item when the user had the editor role on it.
+// Items the user had no access to at all are not found.
+func (uc *UseCaseTrash) getItem(ctx context.Context, companyID, userID, itemID string) (*domain.File, error) {
+	if companyID == "" {
+		return nil, errors.BadRequest("company ID is required")
+	}
+
+	if userID == "" {
+		return nil, errors.BadRequest("user ID is required")
+	}
+
+	item, err := uc.trashRepo.GetDeletedItem(ctx, companyID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := uc.trashRepo.GetDeletedItemRole(ctx, userID, item)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == domain.AccessNone {
+		return nil, errors.NotFound("item not found in trash")
+	}
+	if !role.Allows(domain.AccessEditor) {
+		return nil, errors.Forbidden("editor access is required")
+	}
+
+	return item, nil
+}
+
+// deleteObjects removes storage objects that are no longer referenced by any
+// file or version row. Storage errors are ignored since the rows are already gone.
+func (uc *UseCaseTrash) deleteObjects(ctx context.Context, paths []string) {
+	for _, path := range paths {
+		refs, err := uc.versionRepo.CountStoragePathRefs(ctx, path)
+		if err != nil || refs > 0 {
+			continue
+		}
+
+		_ = uc.storageRepo.DeleteFile(ctx, path)
+	}
+}
@@ -0,0 +1,395 @@
+package ucTrash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-storage/internal/config"
+	"go-storage/internal/domain"
+	customErrors "go-storage/pkg/errors"
+)
+
+type trashRepoMock struct {
+	mock.Mock
+}
+
+func (m *trashRepoMock) GetDeletedItems(ctx context.Context, companyID, userID string) ([]*domain.File, error) {
+	args := m.Called(ctx, companyID, userID)
+	var items []*domain.File
+	if args.Get(0) != nil {
+		items = args.Get(0).([]*domain.File)
+	}
+	return items, args.Error(1)
+}
+
+func (m *trashRepoMock) GetDeletedItem(ctx context.Context, companyID, itemID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, itemID)
+	var item *domain.File
+	if args.Get(0) != nil {
+		item = args.Get(0).(*domain.File)
+	}
+	return item, args.Error(1)
+}
+
+func (m *trashRepoMock) GetDeletedItemRole(ctx context.Context, userID string, item *domain.File) (domain.AccessRole, error) {
+	args := m.Called(ctx, userID, item)
+	return args.Get(0).(domain.AccessRole), args.Error(1)
+}
+
+func (m *trashRepoMock) RestoreItem(ctx context.Context, item *domain.File, newPath domain.Path, newParentID *string) (*domain.File, error) {
+	args := m.Called(ctx, item, newPath, newParentID)
+	var restored *domain.File
+	if args.Get(0) != nil {
+		restored = args.Get(0).(*domain.File)
+	}
+	return restored, args.Error(1)
+}
+
+func (m *trashRepoMock) PurgeItem(ctx context.Context, companyID, itemID string) ([]string, error) {
+	args := m.Called(ctx, companyID, itemID)
+	var paths []string
+	if args.Get(0) != nil {
+		paths = args.Get(0).([]string)
+	}
+	return paths, args.Error(1)
+}
+
+func (m *trashRepoMock) GetExpiredItems(ctx context.Context, defaultRetentionDays, limit int) ([]*domain.File, error) {
+	args := m.Called(ctx, defaultRetentionDays, limit)
+	var items []*domain.File
+	if args.Get(0) != nil {
+		items = args.Get(0).([]*domain.File)
+	}
+	return items, args.Error(1)
+}
+
+func (m *trashRepoMock) GetSettings(ctx context.Context, companyID string, defaultRetentionDays int) (*domain.TrashSettings, error) {
+	args := m.Called(ctx, companyID, defaultRetentionDays)
+	var settings *domain.TrashSettings
+	if args.Get(0) != nil {
+		settings = args.Get(0).(*domain.TrashSettings)
+	}
+	return settings, args.Error(1)
+}
+
+func (m *trashRepoMock) UpdateSettings(ctx context.Context, settings *domain.TrashSettings) (*domain.TrashSettings, error) {
+	args := m.Called(ctx, settings)
+	var updated *domain.TrashSettings
+	if args.Get(0) != nil {
+		updated = args.Get(0).(*domain.TrashSettings)
+	}
+	return updated, args.Error(1)
+}
+
+type fileRepoMock struct {
+	mock.Mock
+}
+
+func (m *fileRepoMock) GetFile(ctx context.Context, companyID, fileID string) (*domain.File, error) {
+	args := m.Called(ctx, companyID, fileID)
+	var file *domain.File
+	if args.Get(0) != nil {
+		file = args.Get(0).(*domain.File)
+	}
+	return file, args.Error(1)
+}
+
+func (m *fileRepoMock) GetFileByPath(ctx context.Context, companyID string, path *domain.Path) (*domain.File, error) {
+	args := m.Called(ctx, companyID, *path)
+	var file *domain.File
+	if args.Get(0) != nil {
+		file = args.Get(0).(*domain.File)
+	}
+	return file, args.Error(1)
+}
+
+type storageRepoMock struct {
+	mock.Mock
+}
+
+func (m *storageRepoMock) DeleteFile(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+type versionRepoMock struct {
+	mock.Mock
+}
+
+func (m *versionRepoMock) CountStoragePathRefs(ctx context.Context, storagePath string) (int, error) {
+	args := m.Called(ctx, storagePath)
+	return args.Int(0), args.Error(1)
+}
+
+type mocks struct {
+	trash   *trashRepoMock
+	files   *fileRepoMock
+	storage *storageRepoMock
+	version *versionRepoMock
+}
+
+func setupUseCase() (*UseCaseTrash, *mocks) {
+	m := &mocks{
+		trash:   new(trashRepoMock),
+		files:   new(fileRepoMock),
+		storage: new(storageRepoMock),
+		version: new(versionRepoMock),
+	}
+	cnf := &config.FileServer{TrashRetentionDays: 30, TrashPurgeInterval: time.Hour}
+	return NewUseCaseTrash(m.trash, m.files, m.storage, m.version, cnf), m
+}
+
+func TestUseCaseTrash_ListTrash(t *testing.T) {
+	uc, m := setupUseCase()
+
+	deletedAt := time.Now().Add(-24 * time.Hour)
+	files := []*domain.File{{ID: "file-id", Name: "test.txt", UpdatedAt: deletedAt}}
+
+	m.trash.On("GetSettings", mock.Anything, "company-id", 30).
+		Return(&domain.TrashSettings{CompanyID: "company-id", RetentionDays: 7}, nil)
+	m.trash.On("GetDeletedItems", mock.Anything, "company-id", "user-id").Return(files, nil)
+
+	result, err := uc.ListTrash(context.Background(), "company-id", "user-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, deletedAt, result[0].DeletedAt)
+	assert.Equal(t, deletedAt.Add(7*24*time.Hour), result[0].ExpiresAt)
+}
+
+func TestUseCaseTrash_RestoreItem(t *testing.T) {
+	t.Run("restores into active parent", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		parentID := "folder-id"
+		item := &domain.File{ID: "file-id", Name: "test.txt", Type: domain.FileTypeFile, ParentID: &parentID, CompanyId: "company-id"}
+		parent := &domain.File{ID: parentID, Name: "docs", Type: domain.FileTypeFolder, FullPath: "/docs", CompanyId: "company-id"}
+		restored := &domain.File{ID: "file-id", Name: "test.txt", FullPath: "/docs/test.txt", IsActive: true}
+
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+		m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", mock.Anything).Return(domain.AccessEditor, nil)
+		m.files.On("GetFile", mock.Anything, "company-id", parentID).Return(parent, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/docs/test.txt")).
+			Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("RestoreItem", mock.Anything, item, domain.Path("/docs/test.txt"), &parentID).Return(restored, nil)
+
+		result, err := uc.RestoreItem(context.Background(), "company-id", "user-id", "file-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, restored, result)
+		m.trash.AssertExpectations(t)
+	})
+
+	t.Run("renames on collision", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		item := &domain.File{ID: "file-id", Name: "test.txt", Type: domain.FileTypeFile, CompanyId: "company-id"}
+		restored := &domain.File{ID: "file-id", Name: "test (2).txt"}
+
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+		m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", mock.Anything).Return(domain.AccessEditor, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/test.txt")).Return(&domain.File{ID: "other"}, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/test (1).txt")).Return(&domain.File{ID: "other-1"}, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/test (2).txt")).
+			Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("RestoreItem", mock.Anything, item, domain.Path("/test (2).txt"), (*string)(nil)).Return(restored, nil)
+
+		result, err := uc.RestoreItem(context.Background(), "company-id", "user-id", "file-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test (2).txt", result.Name)
+	})
+
+	t.Run("falls back to root when parent is gone", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		parentID := "folder-id"
+		item := &domain.File{ID: "file-id", Name: "test.txt", Type: domain.FileTypeFile, ParentID: &parentID, CompanyId: "company-id"}
+
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+		m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", mock.Anything).Return(domain.AccessEditor, nil)
+		m.files.On("GetFile", mock.Anything, "company-id", parentID).Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", parentID).Return(nil, customErrors.NotFound("item not found in trash"))
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/test.txt")).
+			Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("RestoreItem", mock.Anything, item, domain.Path("/test.txt"), (*string)(nil)).
+			Return(&domain.File{ID: "file-id", FullPath: "/test.txt"}, nil)
+
+		result, err := uc.RestoreItem(context.Background(), "company-id", "user-id", "file-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Path("/test.txt"), result.FullPath)
+	})
+
+	t.Run("restores deleted parent first", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		parentID := "folder-id"
+		item := &domain.File{ID: "file-id", Name: "test.txt", Type: domain.FileTypeFile, ParentID: &parentID, CompanyId: "company-id"}
+		deletedParent := &domain.File{ID: parentID, Name: "docs", Type: domain.FileTypeFolder, FullPath: "/docs", CompanyId: "company-id"}
+		restoredParent := &domain.File{ID: parentID, Name: "docs", FullPath: "/docs", IsActive: true}
+
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+		m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", mock.Anything).Return(domain.AccessEditor, nil)
+		m.files.On("GetFile", mock.Anything, "company-id", parentID).Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", parentID).Return(deletedParent, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/docs")).
+			Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("RestoreItem", mock.Anything, deletedParent, domain.Path("/docs"), (*string)(nil)).Return(restoredParent, nil)
+		m.files.On("GetFile", mock.Anything, "company-id", "file-id").Return(nil, customErrors.NotFound("file not found"))
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/docs/test.txt")).
+			Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("RestoreItem", mock.Anything, item, domain.Path("/docs/test.txt"), &parentID).
+			Return(&domain.File{ID: "file-id", FullPath: "/docs/test.txt"}, nil)
+
+		result, err := uc.RestoreItem(context.Background(), "company-id", "user-id", "file-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Path("/docs/test.txt"), result.FullPath)
+		m.trash.AssertExpectations(t)
+	})
+
+	t.Run("leaves a deleted parent the user may not edit", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		parentID := "folder-id"
+		item := &domain.File{ID: "file-id", Name: "test.txt", Type: domain.FileTypeFile, ParentID: &parentID, CompanyId: "company-id"}
+		deletedParent := &domain.File{ID: parentID, Name: "docs", Type: domain.FileTypeFolder, FullPath: "/docs", CompanyId: "company-id"}
+
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+		m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", item).Return(domain.AccessEditor, nil)
+		m.files.On("GetFile", mock.Anything, "company-id", parentID).Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", parentID).Return(deletedParent, nil)
+		m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", deletedParent).Return(domain.AccessViewer, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/test.txt")).
+			Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("RestoreItem", mock.Anything, item, domain.Path("/test.txt"), (*string)(nil)).
+			Return(&domain.File{ID: "file-id", FullPath: "/test.txt"}, nil)
+
+		result, err := uc.RestoreItem(context.Background(), "company-id", "user-id", "file-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Path("/test.txt"), result.FullPath)
+		m.trash.AssertNotCalled(t, "RestoreItem", mock.Anything, deletedParent, mock.Anything, mock.Anything)
+	})
+
+	t.Run("finds the parent by path without parent_id", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		item := &domain.File{ID: "file-id", Name: "test.txt", Type: domain.FileTypeFile, FullPath: "/docs/test.txt", CompanyId: "company-id"}
+		parent := &domain.File{ID: "folder-id", Name: "docs", Type: domain.FileTypeFolder, FullPath: "/docs", CompanyId: "company-id"}
+		parentID := parent.ID
+
+		m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+		m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", item).Return(domain.AccessEditor, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/docs")).Return(parent, nil)
+		m.files.On("GetFileByPath", mock.Anything, "company-id", domain.Path("/docs/test.txt")).
+			Return(nil, customErrors.NotFound("file not found"))
+		m.trash.On("RestoreItem", mock.Anything, item, domain.Path("/docs/test.txt"), &parentID).
+			Return(&domain.File{ID: "file-id", FullPath: "/docs/test.txt"}, nil)
+
+		result, err := uc.RestoreItem(context.Background(), "company-id", "user-id", "file-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Path("/docs/test.txt"), result.FullPath)
+		m.trash.AssertExpectations(t)
+	})
+}
+
+func TestUseCaseTrash_PurgeItem(t *testing.T) {
+	uc, m := setupUseCase()
+
+	item := &domain.File{ID: "file-id", CompanyId: "company-id"}
+	m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+	m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", item).Return(domain.AccessOwner, nil)
+	m.trash.On("PurgeItem", mock.Anything, "company-id", "file-id").Return([]string{"path/a", "path/shared"}, nil)
+	m.version.On("CountStoragePathRefs", mock.Anything, "path/a").Return(0, nil)
+	m.version.On("CountStoragePathRefs", mock.Anything, "path/shared").Return(1, nil)
+	m.storage.On("DeleteFile", mock.Anything, "path/a").Return(nil)
+
+	err := uc.PurgeItem(context.Background(), "company-id", "user-id", "file-id")
+
+	assert.NoError(t, err)
+	m.storage.AssertExpectations(t)
+	m.storage.AssertNotCalled(t, "DeleteFile", mock.Anything, "path/shared")
+}
+
+func TestUseCaseTrash_RequiresEditor(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   domain.AccessRole
+		status int
+	}{
+		{name: "no access", role: domain.AccessNone, status: 404},
+		{name: "viewer", role: domain.AccessViewer, status: 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := setupUseCase()
+
+			item := &domain.File{ID: "file-id", Name: "test.txt", Type: domain.FileTypeFile, CompanyId: "company-id"}
+			m.trash.On("GetDeletedItem", mock.Anything, "company-id", "file-id").Return(item, nil)
+			m.trash.On("GetDeletedItemRole", mock.Anything, "user-id", item).Return(tt.role, nil)
+
+			_, err := uc.RestoreItem(context.Background(), "company-id", "user-id", "file-id")
+			appErr, ok := err.(*customErrors.AppError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.status, appErr.Code)
+
+			err = uc.PurgeItem(context.Background(), "company-id", "user-id", "file-id")
+			appErr, ok = err.(*customErrors.AppError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.status, appErr.Code)
+
+			m.trash.AssertNotCalled(t, "RestoreItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			m.trash.AssertNotCalled(t, "PurgeItem", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUseCaseTrash_PurgeExpired(t *testing.T) {
+	uc, m := setupUseCase()
+
+	items := []*domain.File{
+		{ID: "file-1", CompanyId: "company-id"},
+		{ID: "file-2", CompanyId: "company-id"},
+	}
+
+	m.trash.On("GetExpiredItems", mock.Anything, 30, purgeBatchSize).Return(items, nil)
+	m.trash.On("PurgeItem", mock.Anything, "company-id", "file-1").Return([]string{"path/1"}, nil)
+	m.trash.On("PurgeItem", mock.Anything, "company-id", "file-2").Return(nil, customErrors.NotFound("item not found in trash"))
+	m.version.On("CountStoragePathRefs", mock.Anything, "path/1").Return(0, nil)
+	m.storage.On("DeleteFile", mock.Anything, "path/1").Return(nil)
+
+	purged, err := uc.PurgeExpired(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
+
+func TestUseCaseTrash_UpdateSettings(t *testing.T) {
+	t.Run("invalid retention", func(t *testing.T) {
+		uc, _ := setupUseCase()
+
+		_, err := uc.UpdateSettings(context.Background(), "company-id", 0)
+		appErr, ok := err.(*customErrors.AppError)
+		assert.True(t, ok)
+		assert.Equal(t, 400, appErr.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		uc, m := setupUseCase()
+
+		expected := &domain.TrashSettings{CompanyID: "company-id", RetentionDays: 14}
+		m.trash.On("UpdateSettings", mock.Anything, expected).Return(expected, nil)
+
+		result, err := uc.UpdateSettings(context.Background(), "company-id", 14)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 14, result.RetentionDays)
+	})
+}
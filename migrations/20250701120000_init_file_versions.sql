@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE file_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    company_id UUID NOT NULL,
+    user_created UUID NOT NULL,
+
+    mime_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    hash VARCHAR(64),
+    storage_path VARCHAR(500) NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_created) REFERENCES users(id) ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_file_versions_file_version ON file_versions(file_id, version);
+CREATE INDEX idx_file_versions_storage_path ON file_versions(storage_path);
+
+INSERT INTO file_versions (file_id, version, company_id, user_created, mime_type, size, hash, storage_path, created_at)
+SELECT id, 1, company_id, user_created, mime_type, size, hash, storage_path, updated_at
+FROM files
+WHERE type = 'file' AND storage_path IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_file_versions_file_version;
+DROP INDEX IF EXISTS idx_file_versions_storage_path;
+DROP TABLE IF EXISTS file_versions;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE companies ADD COLUMN trash_retention_days INTEGER CHECK (trash_retention_days > 0);
+
+CREATE INDEX idx_files_trash ON files(company_id, updated_at) WHERE is_active = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_files_trash;
+ALTER TABLE companies DROP COLUMN IF EXISTS trash_retention_days;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS presigned_uploads (
+    id UUID PRIMARY KEY,
+    file_name VARCHAR(255) NOT NULL,
+    company_id UUID NOT NULL,
+    user_created UUID NOT NULL,
+    target_path VARCHAR(1000) NOT NULL,
+    storage_key VARCHAR(500) NOT NULL,
+    mime_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_created) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_presigned_uploads_company ON presigned_uploads(company_id);
+CREATE INDEX IF NOT EXISTS idx_presigned_uploads_expires ON presigned_uploads(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS presigned_uploads;
+-- +goose StatementEnd
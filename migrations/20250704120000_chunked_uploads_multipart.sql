@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chunked_uploads
+    ADD COLUMN storage_key VARCHAR(1000),
+    ADD COLUMN storage_upload_id VARCHAR(1024);
+
+-- Sessions started before multipart uploads stored their chunks as separate
+-- objects and cannot be completed anymore.
+UPDATE chunked_uploads SET status = 'expired', updated_at = NOW() WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE chunked_uploads
+    DROP COLUMN IF EXISTS storage_upload_id,
+    DROP COLUMN IF EXISTS storage_key;
+-- +goose StatementEnd
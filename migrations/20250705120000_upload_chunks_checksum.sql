@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE upload_chunks ADD COLUMN checksum VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE upload_chunks DROP COLUMN IF EXISTS checksum;
+-- +goose StatementEnd
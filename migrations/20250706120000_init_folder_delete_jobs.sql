@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS folder_delete_jobs (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL,
+    user_created UUID NOT NULL,
+    folder_id UUID NOT NULL,
+    folder_path VARCHAR(1000) NOT NULL,
+    permanent BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    total_items INTEGER NOT NULL DEFAULT 0,
+    deleted_items INTEGER NOT NULL DEFAULT 0,
+    purged_items INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_created) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_folder_delete_jobs_company ON folder_delete_jobs(company_id);
+CREATE INDEX IF NOT EXISTS idx_folder_delete_jobs_pending ON folder_delete_jobs(created_at) WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS folder_delete_jobs;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS folder_copy_jobs (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL,
+    user_created UUID NOT NULL,
+    source_id UUID NOT NULL,
+    source_path VARCHAR(1000) NOT NULL,
+    target_path VARCHAR(1000) NOT NULL,
+    target_id UUID,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    total_items INTEGER NOT NULL DEFAULT 0,
+    copied_items INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_created) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_folder_copy_jobs_company ON folder_copy_jobs(company_id);
+CREATE INDEX IF NOT EXISTS idx_folder_copy_jobs_pending ON folder_copy_jobs(created_at) WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS folder_copy_jobs;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Uploads used to be stored without parent_id, link them to their folder.
+UPDATE files AS child
+SET parent_id = parent.id
+FROM files AS parent
+WHERE child.parent_id IS NULL
+  AND child.is_active = true
+  AND parent.is_active = true
+  AND parent.type = 'folder'
+  AND parent.company_id = child.company_id
+  AND parent.full_path = regexp_replace(child.full_path, '/[^/]+$', '');
+
+CREATE INDEX IF NOT EXISTS idx_files_parent_name ON files(company_id, parent_id, name, id) WHERE is_active = true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_files_parent_name;
+-- +goose StatementEnd
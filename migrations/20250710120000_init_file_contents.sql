@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS file_contents (
+    file_id UUID PRIMARY KEY,
+    company_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'indexed', 'skipped', 'failed')),
+    content TEXT,
+    search_vector TSVECTOR,
+    error TEXT,
+    queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    indexed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_contents_company ON file_contents(company_id, status);
+CREATE INDEX IF NOT EXISTS idx_file_contents_queue ON file_contents(queued_at) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS idx_file_contents_search ON file_contents USING gin (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS file_contents;
+-- +goose StatementEnd
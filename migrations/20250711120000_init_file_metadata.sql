@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS file_metadata (
+    file_id UUID PRIMARY KEY,
+    company_id UUID NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_metadata_company ON file_metadata(company_id);
+CREATE INDEX IF NOT EXISTS idx_file_metadata_tags ON file_metadata USING gin (tags);
+CREATE INDEX IF NOT EXISTS idx_file_metadata_metadata ON file_metadata USING gin (metadata jsonb_path_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS file_metadata;
+-- +goose StatementEnd
@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE companies
+    ADD COLUMN quota_bytes BIGINT CHECK (quota_bytes >= 0),
+    ADD COLUMN quota_files BIGINT CHECK (quota_files >= 0);
+
+ALTER TABLE users
+    ADD COLUMN quota_bytes BIGINT CHECK (quota_bytes >= 0),
+    ADD COLUMN quota_files BIGINT CHECK (quota_files >= 0);
+
+-- Usage of active files per company and uploader. Items in the trash and old
+-- versions do not count against a quota.
+CREATE TABLE IF NOT EXISTS storage_usage (
+    company_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    used_bytes BIGINT NOT NULL DEFAULT 0,
+    used_files BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (company_id, user_id),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO storage_usage (company_id, user_id, used_bytes, used_files)
+SELECT company_id, user_created, COALESCE(SUM(size), 0), COUNT(*)
+FROM files
+WHERE type = 'file' AND is_active = true
+GROUP BY company_id, user_created;
+
+-- Keeping the counters in a trigger covers every statement that creates,
+-- trashes, restores, purges or resizes files.
+CREATE OR REPLACE FUNCTION track_storage_usage() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.type = 'file' AND OLD.is_active THEN
+        UPDATE storage_usage
+        SET used_bytes = used_bytes - COALESCE(OLD.size, 0),
+            used_files = used_files - 1
+        WHERE company_id = OLD.company_id AND user_id = OLD.user_created;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.type = 'file' AND NEW.is_active THEN
+        INSERT INTO storage_usage (company_id, user_id, used_bytes, used_files)
+        VALUES (NEW.company_id, NEW.user_created, COALESCE(NEW.size, 0), 1)
+        ON CONFLICT (company_id, user_id) DO UPDATE
+        SET used_bytes = storage_usage.used_bytes + EXCLUDED.used_bytes,
+            used_files = storage_usage.used_files + EXCLUDED.used_files;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_files_storage_usage
+    AFTER INSERT OR DELETE OR UPDATE OF is_active, size, company_id, user_created ON files
+    FOR EACH ROW EXECUTE FUNCTION track_storage_usage();
+
+CREATE INDEX IF NOT EXISTS idx_chunked_uploads_reserved ON chunked_uploads(company_id, user_created) WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_chunked_uploads_reserved;
+DROP TRIGGER IF EXISTS trg_files_storage_usage ON files;
+DROP FUNCTION IF EXISTS track_storage_usage();
+DROP TABLE IF EXISTS storage_usage;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS quota_files,
+    DROP COLUMN IF EXISTS quota_bytes;
+ALTER TABLE companies
+    DROP COLUMN IF EXISTS quota_files,
+    DROP COLUMN IF EXISTS quota_bytes;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL,
+    company_id UUID NOT NULL,
+    user_created UUID NOT NULL,
+    file_id UUID NOT NULL,
+    password_hash VARCHAR(255),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    max_downloads INTEGER CHECK (max_downloads > 0),
+    download_count INTEGER NOT NULL DEFAULT 0,
+    allowed_ips TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_created) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_share_links_token ON share_links(token_hash);
+CREATE INDEX IF NOT EXISTS idx_share_links_user ON share_links(company_id, user_created);
+CREATE INDEX IF NOT EXISTS idx_share_links_file ON share_links(file_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS share_links;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Roles granted on a file or folder, inherited by everything below it. A NULL
+-- user_id grants the role to everyone in the company. The creator of an item
+-- owns it without an entry.
+CREATE TABLE IF NOT EXISTS file_access (
+    id UUID PRIMARY KEY,
+    file_id UUID NOT NULL,
+    company_id UUID NOT NULL,
+    user_id UUID,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+    granted_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (granted_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_file_access_user ON file_access(file_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_file_access_company ON file_access(file_id) WHERE user_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_file_access_grantee ON file_access(company_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_files_user_created ON files(company_id, user_created) WHERE is_active = true;
+
+ALTER TABLE companies ADD COLUMN share_new_files BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Everything stored so far was visible to the whole company and stays so.
+INSERT INTO file_access (id, file_id, company_id, user_id, role, granted_by, created_at)
+SELECT gen_random_uuid(), id, company_id, NULL, 'editor', user_created, NOW()
+FROM files
+WHERE full_path NOT LIKE '/%/%';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE companies DROP COLUMN IF EXISTS share_new_files;
+DROP INDEX IF EXISTS idx_files_user_created;
+DROP TABLE IF EXISTS file_access;
+-- +goose StatementEnd
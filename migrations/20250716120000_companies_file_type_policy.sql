@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE companies
+    ADD COLUMN allowed_mime_types TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN denied_mime_types TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN allowed_extensions TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN denied_extensions TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE companies
+    DROP COLUMN IF EXISTS allowed_mime_types,
+    DROP COLUMN IF EXISTS denied_mime_types,
+    DROP COLUMN IF EXISTS allowed_extensions,
+    DROP COLUMN IF EXISTS denied_extensions;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Files stored before scanning existed count as clean; new content is
+-- inserted as pending when a scanner is configured.
+ALTER TABLE files
+    ADD COLUMN scan_status VARCHAR(16) NOT NULL DEFAULT 'clean',
+    ADD COLUMN scan_detail TEXT,
+    ADD COLUMN scan_claimed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_files_scan_status ON files(company_id, scan_status) WHERE scan_status <> 'clean';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_files_scan_status;
+
+ALTER TABLE files
+    DROP COLUMN IF EXISTS scan_status,
+    DROP COLUMN IF EXISTS scan_detail,
+    DROP COLUMN IF EXISTS scan_claimed_at,
+    DROP COLUMN IF EXISTS scanned_at;
+-- +goose StatementEnd
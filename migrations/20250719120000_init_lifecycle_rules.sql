@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every object stored so far sits on the primary storage, the hot tier.
+-- Downloads record last_accessed_at, which cold storage transitions go by.
+ALTER TABLE files
+    ADD COLUMN storage_class VARCHAR(8) NOT NULL DEFAULT 'hot' CHECK (storage_class IN ('hot', 'cold')),
+    ADD COLUMN last_accessed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_files_storage_class ON files(company_id, storage_class) WHERE type = 'file' AND is_active = true;
+
+-- Rules without folder_id apply to the whole company. Rules of a folder in
+-- the trash wait for it to be restored and go when it is purged.
+CREATE TABLE IF NOT EXISTS lifecycle_rules (
+    id UUID PRIMARY KEY,
+    company_id UUID NOT NULL,
+    folder_id UUID,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('transition', 'delete')),
+    days INTEGER NOT NULL CHECK (days > 0),
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (folder_id) REFERENCES files(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lifecycle_rules_folder ON lifecycle_rules(folder_id, action) WHERE folder_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lifecycle_rules_company ON lifecycle_rules(company_id, action) WHERE folder_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lifecycle_rules;
+
+DROP INDEX IF EXISTS idx_files_storage_class;
+
+ALTER TABLE files
+    DROP COLUMN IF EXISTS storage_class,
+    DROP COLUMN IF EXISTS last_accessed_at;
+-- +goose StatementEnd
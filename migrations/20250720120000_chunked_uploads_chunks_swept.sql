@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sessions started before multipart uploads, the ones without storage_key,
+-- left their chunks behind as key.chunk.N objects. They are kept until the
+-- janitor has removed those objects.
+ALTER TABLE chunked_uploads ADD COLUMN chunks_swept BOOLEAN NOT NULL DEFAULT true;
+
+UPDATE chunked_uploads SET chunks_swept = false WHERE storage_key IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chunked_uploads_unswept ON chunked_uploads(created_at) WHERE chunks_swept = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_chunked_uploads_unswept;
+
+ALTER TABLE chunked_uploads DROP COLUMN IF EXISTS chunks_swept;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The parent_id backfill skipped deleted rows, link them to their folder too.
+-- A folder deleted together with the item takes precedence over an active one.
+UPDATE files AS child
+SET parent_id = parent.id
+FROM files AS parent
+WHERE child.parent_id IS NULL
+  AND child.is_active = false
+  AND parent.is_active = false
+  AND parent.updated_at = child.updated_at
+  AND parent.type = 'folder'
+  AND parent.company_id = child.company_id
+  AND parent.full_path = regexp_replace(child.full_path, '/[^/]+$', '');
+
+UPDATE files AS child
+SET parent_id = parent.id
+FROM files AS parent
+WHERE child.parent_id IS NULL
+  AND child.is_active = false
+  AND parent.is_active = true
+  AND parent.type = 'folder'
+  AND parent.company_id = child.company_id
+  AND parent.full_path = regexp_replace(child.full_path, '/[^/]+$', '');
+-- +goose StatementEnd
+
+-- +goose Down
+-- The links are correct either way, there is nothing to undo.
@@ -0,0 +1,11 @@
+package db
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike makes value match literally inside a LIKE pattern, so that % and
+// _ in names are not taken for wildcards.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
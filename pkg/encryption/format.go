@@ -0,0 +1,205 @@
+// Package encryption seals stored objects with AES-256-GCM in fixed-size
+// segments, so that any byte range can be decrypted without reading the object
+// from its start, and wraps the keys doing so under master keys.
+//
+// An encrypted object starts with a header, followed by one or more parts, as
+// uploaded by multipart uploads. Every part is a sequence of segments holding
+// SegmentSize bytes of plaintext each, except the last one of the part. A
+// segment is stored as its random nonce, the ciphertext and the GCM tag. The
+// position of the segment, and whether it ends its part, is authenticated with
+// it, so segments cannot be reordered or cut off unnoticed.
+package encryption
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// SegmentSize is the plaintext held by every segment but the last of a part.
+	SegmentSize = 64 * 1024
+
+	// HeaderSize is the length of the header every encrypted object starts with.
+	HeaderSize = len(magic) + 4 + 8 + saltSize
+
+	// SegmentOverhead is the nonce and tag stored with every segment.
+	SegmentOverhead = nonceSize + tagSize
+
+	saltSize  = 16
+	nonceSize = 12
+	tagSize   = 16
+
+	maxSegmentSize = 16 * 1024 * 1024
+)
+
+// magic starts with a zero byte so that text files are never taken for
+// encrypted objects.
+const magic = "\x00GSENC\x00\x01"
+
+// ErrCorrupt is returned for encrypted objects that fail to decrypt.
+var ErrCorrupt = errors.New("encrypted object is corrupt or has been tampered with")
+
+// Header describes the layout of an encrypted object. The salt makes the key of
+// every object a different one.
+type Header struct {
+	SegmentSize int64
+	// PartSize is the plaintext held by every part but the last, or 0 when the
+	// object is a single part.
+	PartSize int64
+	Salt     [saltSize]byte
+}
+
+// NewHeader returns the header of an object stored in a single part.
+func NewHeader() (*Header, error) {
+	h := &Header{SegmentSize: SegmentSize}
+	if _, err := rand.Read(h.Salt[:]); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// NewMultipartHeader returns the header of an object uploaded in parts of
+// partSize bytes. The salt is derived from the upload ID, so that parts sent
+// in separate requests are sealed with the same object key.
+func NewMultipartHeader(uploadID string, partSize int64) *Header {
+	h := &Header{SegmentSize: SegmentSize, PartSize: partSize}
+	digest := sha256.Sum256([]byte(uploadID))
+	copy(h.Salt[:], digest[:])
+	return h
+}
+
+// ParseHeader reads the header at the start of data. It reports false when
+// data is not the start of an encrypted object.
+func ParseHeader(data []byte) (*Header, bool) {
+	if len(data) < HeaderSize || string(data[:len(magic)]) != magic {
+		return nil, false
+	}
+
+	data = data[len(magic):]
+	h := &Header{
+		SegmentSize: int64(binary.BigEndian.Uint32(data[0:4])),
+		PartSize:    int64(binary.BigEndian.Uint64(data[4:12])),
+	}
+	copy(h.Salt[:], data[12:12+saltSize])
+
+	if h.SegmentSize <= 0 || h.SegmentSize > maxSegmentSize || h.PartSize < 0 {
+		return nil, false
+	}
+	return h, true
+}
+
+func (h *Header) MarshalBinary() []byte {
+	data := make([]byte, 0, HeaderSize)
+	data = append(data, magic...)
+	data = binary.BigEndian.AppendUint32(data, uint32(h.SegmentSize))
+	data = binary.BigEndian.AppendUint64(data, uint64(h.PartSize))
+	return append(data, h.Salt[:]...)
+}
+
+// PartCiphertextSize is the stored size of a part holding plaintextSize bytes,
+// without the header. Empty parts still hold one empty segment.
+func (h *Header) PartCiphertextSize(plaintextSize int64) int64 {
+	segments := max(1, (plaintextSize+h.SegmentSize-1)/h.SegmentSize)
+	return plaintextSize + segments*SegmentOverhead
+}
+
+// CiphertextSize is the stored size of an object holding plaintextSize bytes.
+func (h *Header) CiphertextSize(plaintextSize int64) int64 {
+	if h.PartSize == 0 || plaintextSize <= h.PartSize {
+		return int64(HeaderSize) + h.PartCiphertextSize(plaintextSize)
+	}
+
+	fullParts := (plaintextSize - 1) / h.PartSize
+	last := plaintextSize - fullParts*h.PartSize
+	return int64(HeaderSize) + fullParts*h.PartCiphertextSize(h.PartSize) + h.PartCiphertextSize(last)
+}
+
+// PlaintextSize is the size of the plaintext in an object stored with
+// ciphertextSize bytes.
+func (h *Header) PlaintextSize(ciphertextSize int64) (int64, error) {
+	body := ciphertextSize - int64(HeaderSize)
+	if body <= 0 {
+		return 0, ErrCorrupt
+	}
+
+	var plaintext int64
+	if h.PartSize > 0 {
+		fullPart := h.PartCiphertextSize(h.PartSize)
+		if body%fullPart == 0 {
+			return body / fullPart * h.PartSize, nil
+		}
+		plaintext = body / fullPart * h.PartSize
+		body %= fullPart
+	}
+
+	segment := h.SegmentSize + SegmentOverhead
+	rest := body % segment
+	plaintext += body / segment * h.SegmentSize
+	if rest == 0 {
+		return plaintext, nil
+	}
+	if rest < SegmentOverhead {
+		return 0, ErrCorrupt
+	}
+	return plaintext + rest - SegmentOverhead, nil
+}
+
+// CiphertextRange returns the stored byte range, inclusive, to read for the
+// plaintext from offset from to offset to. It reaches one byte past the last
+// segment needed, which tells whether the object ends with that segment.
+func (h *Header) CiphertextRange(from, to int64) (int64, int64) {
+	part, segment, _ := h.locate(from)
+	start := h.segmentOffset(part, segment)
+
+	part, segment, segmentStart := h.locate(to)
+	end := h.segmentOffset(part, segment) + h.segmentLimit(part, segmentStart) + SegmentOverhead
+
+	return start, end
+}
+
+// locate returns the part and the segment within it holding plaintext offset
+// offset, and the plaintext offset the segment starts at.
+func (h *Header) locate(offset int64) (part, segment, start int64) {
+	if h.PartSize > 0 {
+		part = offset / h.PartSize
+	}
+	segment = (offset - part*h.PartSize) / h.SegmentSize
+	return part, segment, part*h.PartSize + segment*h.SegmentSize
+}
+
+// segmentOffset is the stored offset of a segment.
+func (h *Header) segmentOffset(part, segment int64) int64 {
+	offset := int64(HeaderSize) + segment*(h.SegmentSize+SegmentOverhead)
+	if part > 0 {
+		offset += part * h.PartCiphertextSize(h.PartSize)
+	}
+	return offset
+}
+
+// segmentLimit is the most plaintext the segment of part starting at plaintext
+// offset start can hold.
+func (h *Header) segmentLimit(part, start int64) int64 {
+	if h.PartSize == 0 {
+		return h.SegmentSize
+	}
+	return min(h.SegmentSize, (part+1)*h.PartSize-start)
+}
+
+// endsPart reports whether the segment of part starting at plaintext offset
+// start is the last one of a full part.
+func (h *Header) endsPart(part, start int64) bool {
+	return h.PartSize > 0 && start+h.segmentLimit(part, start) == (part+1)*h.PartSize
+}
+
+// segmentData is the additional data authenticated with a segment.
+func segmentData(part, segment int64, final bool) []byte {
+	data := make([]byte, 17)
+	binary.BigEndian.PutUint64(data[0:8], uint64(part))
+	binary.BigEndian.PutUint64(data[8:16], uint64(segment))
+	if final {
+		data[16] = 1
+	}
+	return data
+}
@@ -0,0 +1,100 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the size of master and data keys, for AES-256.
+const KeySize = 32
+
+// MasterKey wraps the data keys. The ID is stored with every wrapped key, so
+// that keys wrapped by an older master key can still be unwrapped.
+type MasterKey struct {
+	ID  string
+	Key []byte
+}
+
+// ParseMasterKeys reads master keys given as id:base64-key. The first one is
+// current and wraps new data keys, the others are only used to unwrap.
+func ParseMasterKeys(values []string) ([]MasterKey, error) {
+	keys := make([]MasterKey, 0, len(values))
+	seen := make(map[string]bool, len(values))
+
+	for _, value := range values {
+		id, encoded, found := strings.Cut(value, ":")
+		if !found || id == "" {
+			return nil, errors.New("master keys must be given as id:base64-key")
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("master key %q is given twice", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != KeySize {
+			return nil, fmt.Errorf("master key %q must be 32 bytes, base64 encoded", id)
+		}
+
+		seen[id] = true
+		keys = append(keys, MasterKey{ID: id, Key: key})
+	}
+
+	return keys, nil
+}
+
+// NewDataKey returns a random data key.
+func NewDataKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// WrapKey encrypts a data key with the master key. The owner, such as the
+// company ID, is authenticated with it, so a wrapped key cannot be moved to
+// another owner.
+func WrapKey(master MasterKey, dataKey []byte, owner string) ([]byte, error) {
+	aead, err := keyCipher(master)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte(owner)), nil
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey for the same owner.
+func UnwrapKey(master MasterKey, wrapped []byte, owner string) ([]byte, error) {
+	aead, err := keyCipher(master)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < aead.NonceSize() {
+		return nil, ErrCorrupt
+	}
+
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(owner))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key with master key %q: %w", master.ID, ErrCorrupt)
+	}
+	return dataKey, nil
+}
+
+func keyCipher(master MasterKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(master.Key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
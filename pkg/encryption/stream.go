@@ -0,0 +1,240 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+// NewEncryptReader returns a reader of the stored form of one part of an
+// object, the plaintext of which is read from r. The first part starts with
+// the header.
+func NewEncryptReader(r io.Reader, dataKey []byte, h *Header, part int64) (io.Reader, error) {
+	aead, err := objectCipher(dataKey, h)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &encryptReader{
+		src:    bufio.NewReaderSize(r, int(h.SegmentSize)),
+		aead:   aead,
+		part:   part,
+		plain:  make([]byte, h.SegmentSize),
+		sealed: make([]byte, 0, h.SegmentSize+SegmentOverhead),
+	}
+	if part == 0 {
+		e.pending = h.MarshalBinary()
+	}
+	return e, nil
+}
+
+type encryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	part    int64
+	segment int64
+
+	plain   []byte
+	sealed  []byte
+	pending []byte
+	done    bool
+	err     error
+}
+
+func (e *encryptReader) Read(p []byte) (int, error) {
+	for len(e.pending) == 0 {
+		if e.err != nil {
+			return 0, e.err
+		}
+		if e.done {
+			return 0, io.EOF
+		}
+		e.err = e.seal()
+	}
+
+	n := copy(p, e.pending)
+	e.pending = e.pending[n:]
+	return n, nil
+}
+
+// seal encrypts the next segment. A segment is final when the plaintext ends
+// with it, which for a full segment takes a look at the next byte.
+func (e *encryptReader) seal() error {
+	n, err := io.ReadFull(e.src, e.plain)
+	final := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		return err
+	default:
+		if _, err := e.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+
+	e.sealed = append(e.sealed[:0], nonce[:]...)
+	e.pending = e.aead.Seal(e.sealed, nonce[:], e.plain[:n], segmentData(e.part, e.segment, final))
+	e.segment++
+	e.done = final
+
+	return nil
+}
+
+// NewDecryptReader returns a reader of the plaintext from offset from to offset
+// to, inclusive, or to the end of the object when to is negative. r must start
+// at the stored offset CiphertextRange returns for from, after the header.
+func NewDecryptReader(r io.Reader, dataKey []byte, h *Header, from, to int64) (io.Reader, error) {
+	aead, err := objectCipher(dataKey, h)
+	if err != nil {
+		return nil, err
+	}
+
+	part, segment, start := h.locate(from)
+
+	remaining := int64(-1)
+	if to >= 0 {
+		remaining = to - from + 1
+	}
+
+	return &decryptReader{
+		src:       bufio.NewReaderSize(r, int(h.SegmentSize+SegmentOverhead)),
+		aead:      aead,
+		header:    h,
+		part:      part,
+		segment:   segment,
+		offset:    start,
+		skip:      from - start,
+		remaining: remaining,
+		sealed:    make([]byte, h.SegmentSize+SegmentOverhead),
+	}, nil
+}
+
+type decryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	header  *Header
+	part    int64
+	segment int64
+	// offset is the plaintext offset of the next segment.
+	offset int64
+
+	skip      int64
+	remaining int64
+
+	sealed  []byte
+	pending []byte
+	done    bool
+	err     error
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	if d.remaining == 0 {
+		return 0, io.EOF
+	}
+
+	for len(d.pending) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		d.err = d.open()
+	}
+
+	if d.remaining >= 0 && int64(len(p)) > d.remaining {
+		p = p[:d.remaining]
+	}
+
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	if d.remaining > 0 {
+		d.remaining -= int64(n)
+	}
+	return n, nil
+}
+
+// open decrypts the next segment. The last segment of a full part is known to
+// be final from its position; any other segment is final when the stored
+// object ends with it.
+func (d *decryptReader) open() error {
+	limit := d.header.segmentLimit(d.part, d.offset)
+	endsPart := d.header.endsPart(d.part, d.offset)
+
+	n, err := io.ReadFull(d.src, d.sealed[:limit+SegmentOverhead])
+	final := endsPart
+	switch {
+	case err == io.EOF && d.segment == 0 && d.part > 0:
+		// The previous part was the last one.
+		d.done = true
+		return nil
+	case err == io.EOF:
+		return ErrCorrupt
+	case err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		return err
+	case !endsPart:
+		if _, err := d.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if n < SegmentOverhead {
+		return ErrCorrupt
+	}
+
+	plain, err := d.aead.Open(d.sealed[nonceSize:nonceSize], d.sealed[:nonceSize], d.sealed[nonceSize:n], segmentData(d.part, d.segment, final))
+	if err != nil {
+		return ErrCorrupt
+	}
+
+	d.offset += int64(len(plain))
+	if endsPart {
+		d.part, d.segment = d.part+1, 0
+	} else {
+		d.segment++
+		d.done = final
+	}
+
+	if d.skip > 0 {
+		skipped := min(d.skip, int64(len(plain)))
+		plain, d.skip = plain[skipped:], d.skip-skipped
+	}
+	d.pending = plain
+
+	return nil
+}
+
+// objectCipher derives the key of a single object from the data key and the
+// salt in its header.
+func objectCipher(dataKey []byte, h *Header) (cipher.AEAD, error) {
+	if len(dataKey) != KeySize {
+		return nil, errors.New("data key must be 32 bytes")
+	}
+
+	mac := hmac.New(sha256.New, dataKey)
+	mac.Write([]byte("go-storage object key"))
+	mac.Write(h.Salt[:])
+
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
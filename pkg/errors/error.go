@@ -28,6 +28,10 @@ func (e *AppError) Error() string {
 	return fmt.Sprintf("code: %d, error: %v, message: %s, time: %s", e.Code, e.Err, e.Message, e.Time)
 }
 
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	ErrInvalidRequest = errors.New("invalid request")
 	ErrEmptyParameter = errors.New("empty parameter")
@@ -49,6 +53,7 @@ var (
 	ErrStorageError     = errors.New("storage error")
 	ErrPermissionDenied = errors.New("permission denied")
 	ErrInvalidOperation = errors.New("invalid operation")
+	ErrQuotaExceeded    = errors.New("quota exceeded")
 )
 
 func NewAppError(code int, err error, msg string) *AppError {
@@ -156,6 +161,18 @@ func InvalidOperation(msg string) *AppError {
 	return NewAppError(http.StatusBadRequest, ErrInvalidOperation, msg)
 }
 
+func QuotaExceeded(msg string) *AppError {
+	return NewAppError(http.StatusInsufficientStorage, ErrQuotaExceeded, msg)
+}
+
 func TooManyRequests(msg string) *AppError {
 	return NewAppError(http.StatusTooManyRequests, errors.New("too many requests"), msg)
 }
+
+func RangeNotSatisfiable(msg string) *AppError {
+	return NewAppError(http.StatusRequestedRangeNotSatisfiable, errors.New("range not satisfiable"), msg)
+}
+
+func Gone(msg string) *AppError {
+	return NewAppError(http.StatusGone, errors.New("gone"), msg)
+}
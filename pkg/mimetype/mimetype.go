@@ -0,0 +1,235 @@
+// Package mimetype tells the type of a file from its leading bytes and its
+// name, and settles on one type when the two disagree.
+package mimetype
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// SniffLen is the number of leading bytes Detect looks at.
+const SniffLen = 512
+
+const OctetStream = "application/octet-stream"
+
+// signatures are checked before the detection of net/http, which does not
+// know executables and compound documents.
+var signatures = []struct {
+	magic    []byte
+	mimeType string
+}{
+	{[]byte("MZ"), "application/vnd.microsoft.portable-executable"},
+	{[]byte("\x7fELF"), "application/x-elf"},
+	{[]byte("\xfe\xed\xfa\xce"), "application/x-mach-binary"},
+	{[]byte("\xfe\xed\xfa\xcf"), "application/x-mach-binary"},
+	{[]byte("\xce\xfa\xed\xfe"), "application/x-mach-binary"},
+	{[]byte("\xcf\xfa\xed\xfe"), "application/x-mach-binary"},
+	{[]byte("#!"), "text/x-shellscript"},
+	{[]byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"), "application/x-ole-storage"},
+	{[]byte("7z\xbc\xaf\x27\x1c"), "application/x-7z-compressed"},
+	{[]byte("{\\rtf"), "application/rtf"},
+}
+
+// extensions maps lower case file name extensions to MIME types.
+var extensions = map[string]string{
+	".txt":  "text/plain",
+	".csv":  "text/csv",
+	".tsv":  "text/tab-separated-values",
+	".md":   "text/markdown",
+	".html": "text/html",
+	".htm":  "text/html",
+	".css":  "text/css",
+	".js":   "text/javascript",
+	".json": "application/json",
+	".xml":  "application/xml",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".ics":  "text/calendar",
+	".rtf":  "application/rtf",
+	".pdf":  "application/pdf",
+
+	".doc":  "application/msword",
+	".xls":  "application/vnd.ms-excel",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".msg":  "application/vnd.ms-outlook",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".odp":  "application/vnd.oasis.opendocument.presentation",
+	".epub": "application/epub+zip",
+
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".bmp":  "image/bmp",
+	".ico":  "image/x-icon",
+	".svg":  "image/svg+xml",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wave",
+	".ogg":  "application/ogg",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".avi":  "video/avi",
+
+	".zip": "application/zip",
+	".gz":  "application/x-gzip",
+	".rar": "application/x-rar-compressed",
+	".7z":  "application/x-7z-compressed",
+	".jar": "application/java-archive",
+	".apk": "application/vnd.android.package-archive",
+
+	".exe":  "application/vnd.microsoft.portable-executable",
+	".dll":  "application/vnd.microsoft.portable-executable",
+	".msi":  "application/x-ole-storage",
+	".sh":   "text/x-shellscript",
+	".wasm": "application/wasm",
+	".woff": "font/woff",
+}
+
+// refinements lists, for a detected container or text type, the more specific
+// types a file of that content may be named as.
+var refinements = map[string][]string{
+	"application/zip": {
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+		"application/vnd.oasis.opendocument.text",
+		"application/vnd.oasis.opendocument.spreadsheet",
+		"application/vnd.oasis.opendocument.presentation",
+		"application/epub+zip",
+		"application/java-archive",
+		"application/vnd.android.package-archive",
+	},
+	"application/x-ole-storage": {
+		"application/msword",
+		"application/vnd.ms-excel",
+		"application/vnd.ms-powerpoint",
+		"application/vnd.ms-outlook",
+	},
+	"text/plain": {
+		"text/csv", "text/tab-separated-values", "text/markdown", "text/html", "text/css",
+		"text/javascript", "text/calendar", "application/json", "application/xml",
+		"application/yaml", "image/svg+xml",
+	},
+	"text/xml":  {"application/xml", "image/svg+xml"},
+	"text/html": {"image/svg+xml"},
+}
+
+// Detect returns the media type of content starting with head, without
+// parameters. Unknown binary content is OctetStream.
+func Detect(head []byte) string {
+	if len(head) > SniffLen {
+		head = head[:SniffLen]
+	}
+
+	for _, signature := range signatures {
+		if bytes.HasPrefix(head, signature.magic) {
+			return signature.mimeType
+		}
+	}
+
+	return Normalize(http.DetectContentType(head))
+}
+
+// ByExtension returns the MIME type for the extension of filename, or an
+// empty string for unknown extensions.
+func ByExtension(filename string) string {
+	return extensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// Normalize lower cases a media type and drops its parameters. Invalid types,
+// including those without a subtype, become an empty string.
+func Normalize(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil || !strings.Contains(mediaType, "/") {
+		return ""
+	}
+	return mediaType
+}
+
+// Resolve settles on the type of a file from its name, the type declared by
+// the client and its leading bytes; head is nil when the content has not been
+// seen yet. Empty content tells nothing either. Recognised content wins over
+// the name, so a renamed executable is still an executable, but a name may
+// refine what the content allows: a ZIP named .docx is a Word document and
+// text named .csv is CSV.
+func Resolve(filename, declared string, head []byte) string {
+	candidates := make([]string, 0, 2)
+	for _, candidate := range []string{ByExtension(filename), Normalize(declared)} {
+		if candidate != "" && candidate != OctetStream {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	if len(head) == 0 {
+		if len(candidates) > 0 {
+			return candidates[0]
+		}
+		return OctetStream
+	}
+
+	detected := Detect(head)
+	if detected == OctetStream {
+		// Content without a known signature is only trusted to be of a type
+		// that has none either.
+		for _, candidate := range candidates {
+			if !hasSignature(candidate) {
+				return candidate
+			}
+		}
+		return OctetStream
+	}
+
+	for _, candidate := range candidates {
+		if candidate == detected || refines(detected, candidate) {
+			return candidate
+		}
+	}
+
+	return detected
+}
+
+func refines(detected, candidate string) bool {
+	for _, refinement := range refinements[detected] {
+		if refinement == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffed lists the binary types net/http recognises by their leading bytes.
+var sniffed = []string{
+	"image/x-icon", "image/bmp", "image/gif", "image/webp", "image/png", "image/jpeg",
+	"audio/basic", "audio/aiff", "audio/mpeg", "audio/midi", "audio/wave", "application/ogg",
+	"video/avi", "video/mp4", "video/webm", "font/ttf", "font/otf", "font/collection",
+	"font/woff", "font/woff2", "application/vnd.ms-fontobject", "application/pdf",
+	"application/postscript", "application/x-gzip", "application/zip",
+	"application/x-rar-compressed", "application/wasm",
+}
+
+// hasSignature reports whether Detect recognises content of this type, so
+// content it did not recognise cannot be of it.
+func hasSignature(mimeType string) bool {
+	for _, signature := range signatures {
+		if signature.mimeType == mimeType {
+			return true
+		}
+	}
+	for _, known := range sniffed {
+		if known == mimeType {
+			return true
+		}
+	}
+	return refines("application/zip", mimeType) || refines("application/x-ole-storage", mimeType)
+}
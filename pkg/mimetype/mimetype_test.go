@@ -0,0 +1,125 @@
+package mimetype
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	pngHead  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegHead = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	pdfHead  = []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	zipHead  = []byte("PK\x03\x04\x14\x00\x06\x00")
+	oleHead  = []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1\x00\x00")
+	exeHead  = []byte("MZ\x90\x00\x03\x00\x00\x00")
+	elfHead  = []byte("\x7fELF\x02\x01\x01\x00")
+	textHead = []byte("name,amount\nalice,10\n")
+	htmlHead = []byte("<!DOCTYPE html><html><body>hi</body></html>")
+	binHead  = []byte("\x00\x01\x02\x03\xfe\xfd\xfc")
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		declared string
+		head     []byte
+		want     string
+	}{
+		// Name, declared type and content agree.
+		{name: "all agree", filename: "photo.png", declared: "image/png", head: pngHead, want: "image/png"},
+		{name: "declared with parameters", filename: "doc.pdf", declared: "Application/PDF; name=doc.pdf", head: pdfHead, want: "application/pdf"},
+
+		// Content wins over a spoofed name or declared type.
+		{name: "executable named pdf", filename: "invoice.pdf", declared: "application/pdf", head: exeHead, want: "application/vnd.microsoft.portable-executable"},
+		{name: "executable named jpg", filename: "cat.JPG", declared: "", head: exeHead, want: "application/vnd.microsoft.portable-executable"},
+		{name: "elf named txt", filename: "readme.txt", declared: "text/plain", head: elfHead, want: "application/x-elf"},
+		{name: "png named jpg", filename: "photo.jpg", declared: "image/jpeg", head: pngHead, want: "image/png"},
+		{name: "html named png", filename: "image.png", declared: "image/png", head: htmlHead, want: "text/html"},
+		{name: "declared type disagrees with name and content", filename: "photo.jpg", declared: "application/pdf", head: jpegHead, want: "image/jpeg"},
+
+		// The name refines what the content allows.
+		{name: "zip named docx", filename: "report.docx", declared: OctetStream, head: zipHead, want: "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{name: "zip declared as xlsx", filename: "upload", declared: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", head: zipHead, want: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{name: "ole named doc", filename: "letter.doc", declared: "", head: oleHead, want: "application/msword"},
+		{name: "text named csv", filename: "data.csv", declared: "text/plain", head: textHead, want: "text/csv"},
+		{name: "zip named pdf", filename: "report.pdf", declared: "", head: zipHead, want: "application/zip"},
+		{name: "text named docx", filename: "report.docx", declared: "", head: textHead, want: "text/plain"},
+
+		// The name wins when the extension is first and both refine the content.
+		{name: "extension before declared type", filename: "data.csv", declared: "application/json", head: textHead, want: "text/csv"},
+
+		// Unrecognised content is only trusted to be of a type without a signature.
+		{name: "unknown binary with unsigned type", filename: "model.bin", declared: "application/x-custom", head: binHead, want: "application/x-custom"},
+		{name: "unknown binary named png", filename: "photo.png", declared: "image/png", head: binHead, want: OctetStream},
+		{name: "unknown binary named docx", filename: "report.docx", declared: "", head: binHead, want: OctetStream},
+		{name: "unknown binary without hints", filename: "blob", declared: "", head: binHead, want: OctetStream},
+
+		// Without content, or with empty content, the hints are all there is.
+		{name: "not seen yet", filename: "photo.png", declared: "image/jpeg", head: nil, want: "image/png"},
+		{name: "not seen yet declared only", filename: "upload", declared: "image/jpeg", head: nil, want: "image/jpeg"},
+		{name: "not seen yet invalid declared", filename: "upload", declared: "garbage", head: nil, want: OctetStream},
+		{name: "empty content", filename: "report.pdf", declared: "", head: []byte{}, want: "application/pdf"},
+		{name: "empty content declared only", filename: "upload", declared: "text/csv", head: []byte{}, want: "text/csv"},
+		{name: "empty content without hints", filename: "upload", declared: OctetStream, head: []byte{}, want: OctetStream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Resolve(tt.filename, tt.declared, tt.head))
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		want     string
+	}{
+		{mimeType: "image/png", want: "image/png"},
+		{mimeType: "Text/HTML; charset=UTF-8", want: "text/html"},
+		{mimeType: "  application/json ", want: "application/json"},
+		{mimeType: "image/svg+xml", want: "image/svg+xml"},
+		{mimeType: "", want: ""},
+		{mimeType: "garbage", want: ""},
+		{mimeType: "text/", want: ""},
+		{mimeType: "/plain", want: ""},
+		{mimeType: "text/plain; charset", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.mimeType))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{name: "empty", head: []byte{}, want: "text/plain"},
+		{name: "executable", head: exeHead, want: "application/vnd.microsoft.portable-executable"},
+		{name: "shell script", head: []byte("#!/bin/sh\nrm -rf /\n"), want: "text/x-shellscript"},
+		{name: "png", head: pngHead, want: "image/png"},
+		{name: "binary", head: binHead, want: OctetStream},
+		{name: "signature past the sniffed bytes", head: append(bytes.Repeat([]byte(" "), SniffLen), exeHead...), want: "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Detect(tt.head))
+		})
+	}
+}
+
+func TestByExtension(t *testing.T) {
+	assert.Equal(t, "application/pdf", ByExtension("Report.PDF"))
+	assert.Equal(t, "application/x-gzip", ByExtension("archive.tar.gz"))
+	assert.Equal(t, "", ByExtension("Makefile"))
+	assert.Equal(t, "", ByExtension("photo.png.unknown"))
+}
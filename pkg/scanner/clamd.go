@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize stays well below the StreamMaxLength of clamd, which limits
+// the whole stream rather than single chunks.
+const clamdChunkSize = 64 * 1024
+
+// ClamdScanner streams content to a clamd daemon with the INSTREAM command.
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner returns a scanner for the clamd listening at address, a unix
+// socket as unix:///path or /path, or a TCP address as tcp://host:port or
+// host:port. timeout bounds every read and write on the connection.
+func NewClamdScanner(address string, timeout time.Duration) (*ClamdScanner, error) {
+	network, addr, err := parseClamdAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return &ClamdScanner{network: network, address: addr, timeout: timeout}, nil
+}
+
+func parseClamdAddress(address string) (string, string, error) {
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		return "unix", strings.TrimPrefix(address, "unix://"), nil
+	case strings.HasPrefix(address, "tcp://"):
+		return "tcp", strings.TrimPrefix(address, "tcp://"), nil
+	case strings.HasPrefix(address, "/"):
+		return "unix", address, nil
+	}
+
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return "tcp", address, nil
+	}
+	return "", "", fmt.Errorf("invalid clamd address %q", address)
+}
+
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (string, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return "", fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if err := s.stream(conn, r); err != nil {
+		if !isConnError(err) {
+			return "", err
+		}
+		// clamd hangs up on streams over its size limit, the reply says why.
+		if signature, replyErr := s.reply(conn); !isConnError(replyErr) {
+			return signature, replyErr
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+
+	signature, err := s.reply(conn)
+	if err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return signature, err
+}
+
+// stream sends the content as length prefixed chunks, ended by a zero length.
+func (s *ClamdScanner) stream(conn net.Conn, r io.Reader) error {
+	if err := s.write(conn, []byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, err := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if err := s.write(conn, buf[:4+n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read content: %w", err)
+		}
+	}
+
+	return s.write(conn, []byte{0, 0, 0, 0})
+}
+
+func (s *ClamdScanner) write(conn net.Conn, data []byte) error {
+	if s.timeout > 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	}
+	if _, err := conn.Write(data); err != nil {
+		return &connError{fmt.Errorf("send to clamd: %w", err)}
+	}
+	return nil
+}
+
+// reply reads the answer to INSTREAM: "stream: OK", "stream: <name> FOUND" or
+// "<reason> ERROR", terminated by a zero byte.
+func (s *ClamdScanner) reply(conn net.Conn) (string, error) {
+	if s.timeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(s.timeout))
+	}
+
+	line, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && (err != io.EOF || line == "") {
+		return "", &connError{fmt.Errorf("read clamd reply: %w", err)}
+	}
+
+	line = strings.TrimSpace(strings.TrimRight(line, "\x00"))
+	line = strings.TrimPrefix(line, "stream: ")
+
+	switch {
+	case line == "OK":
+		return "", nil
+	case strings.HasSuffix(line, " FOUND"):
+		return strings.TrimSuffix(line, " FOUND"), nil
+	case strings.HasSuffix(line, " ERROR"):
+		return "", fmt.Errorf("clamd: %s", strings.TrimSuffix(line, " ERROR"))
+	}
+	return "", fmt.Errorf("clamd: unexpected reply %q", line)
+}
+
+// connError marks failures to talk to clamd, as opposed to errors clamd reported.
+type connError struct {
+	err error
+}
+
+func (e *connError) Error() string { return e.err.Error() }
+
+func (e *connError) Unwrap() error { return e.err }
+
+func isConnError(err error) bool {
+	var target *connError
+	return errors.As(err, &target)
+}
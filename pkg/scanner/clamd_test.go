@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClamd accepts a single INSTREAM session and answers it with reply.
+type fakeClamd struct {
+	address string
+	command chan string
+	chunks  chan []int
+	content chan []byte
+}
+
+func startFakeClamd(t *testing.T, reply string) *fakeClamd {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	f := &fakeClamd{
+		address: listener.Addr().String(),
+		command: make(chan string, 1),
+		chunks:  make(chan []int, 1),
+		content: make(chan []byte, 1),
+	}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		command, err := r.ReadString(0)
+		f.command <- command
+		if err != nil {
+			return
+		}
+
+		var chunks []int
+		var content bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			chunks = append(chunks, int(size))
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&content, r, int64(size)); err != nil {
+				return
+			}
+		}
+		f.chunks <- chunks
+		f.content <- content.Bytes()
+
+		_, _ = conn.Write([]byte(reply))
+	}()
+
+	return f
+}
+
+func TestClamdScanner_Stream(t *testing.T) {
+	clamd := startFakeClamd(t, "stream: OK\x00")
+	content := bytes.Repeat([]byte("0123456789abcdef"), (2*clamdChunkSize+100)/16)
+
+	s, err := NewClamdScanner(clamd.address, time.Second)
+	require.NoError(t, err)
+
+	name, err := s.Scan(context.Background(), bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Empty(t, name)
+
+	assert.Equal(t, "zINSTREAM\x00", <-clamd.command)
+	chunks := <-clamd.chunks
+	require.NotEmpty(t, chunks)
+	assert.Equal(t, 0, chunks[len(chunks)-1], "the stream ends with a zero length chunk")
+	for _, size := range chunks {
+		assert.LessOrEqual(t, size, clamdChunkSize)
+	}
+	assert.Equal(t, content, <-clamd.content)
+}
+
+func TestClamdScanner_Reply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    string
+		wantErr string
+	}{
+		{name: "clean", reply: "stream: OK\x00", want: ""},
+		{name: "infected", reply: "stream: Eicar-Test-Signature FOUND\x00", want: "Eicar-Test-Signature"},
+		{name: "infected without terminator", reply: "stream: Win.Test.EICAR_HDB-1 FOUND", want: "Win.Test.EICAR_HDB-1"},
+		{name: "error", reply: "INSTREAM size limit exceeded. ERROR\x00", wantErr: "clamd: INSTREAM size limit exceeded."},
+		{name: "unexpected", reply: "UNKNOWN COMMAND\x00", wantErr: `clamd: unexpected reply "UNKNOWN COMMAND"`},
+		{name: "no reply", reply: "", wantErr: "read clamd reply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamd := startFakeClamd(t, tt.reply)
+			s, err := NewClamdScanner("tcp://"+clamd.address, time.Second)
+			require.NoError(t, err)
+
+			name, err := s.Scan(context.Background(), bytes.NewReader([]byte("content")))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, name)
+		})
+	}
+}
+
+func TestClamdScanner_ContextCanceled(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// Accepts and never answers.
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			_, _ = io.Copy(io.Discard, conn)
+		}
+	}()
+
+	s, err := NewClamdScanner(listener.Addr().String(), 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = s.Scan(ctx, bytes.NewReader([]byte("content")))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParseClamdAddress(t *testing.T) {
+	tests := []struct {
+		address     string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{address: "unix:///run/clamav/clamd.ctl", wantNetwork: "unix", wantAddress: "/run/clamav/clamd.ctl"},
+		{address: "/run/clamav/clamd.ctl", wantNetwork: "unix", wantAddress: "/run/clamav/clamd.ctl"},
+		{address: "tcp://clamav:3310", wantNetwork: "tcp", wantAddress: "clamav:3310"},
+		{address: "clamav:3310", wantNetwork: "tcp", wantAddress: "clamav:3310"},
+		{address: "clamav", wantErr: true},
+		{address: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			network, address, err := parseClamdAddress(tt.address)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNetwork, network)
+			assert.Equal(t, tt.wantAddress, address)
+		})
+	}
+}
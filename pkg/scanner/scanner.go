@@ -0,0 +1,36 @@
+// Package scanner checks file contents for malware, either against a list of
+// byte signatures or by streaming them to a clamd daemon.
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	BackendNone      = "none"
+	BackendSignature = "signature"
+	BackendClamd     = "clamd"
+)
+
+// Scanner reads the content to its end and returns the name of the malware
+// found in it, or an empty string when the content is clean.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (string, error)
+}
+
+// New returns the scanner of the named backend, or nil when scanning is
+// turned off. clamdAddress and timeout only apply to the clamd backend.
+func New(backend, clamdAddress string, timeout time.Duration) (Scanner, error) {
+	switch backend {
+	case "", BackendNone:
+		return nil, nil
+	case BackendSignature:
+		return NewSignatureScanner(), nil
+	case BackendClamd:
+		return NewClamdScanner(clamdAddress, timeout)
+	}
+	return nil, fmt.Errorf("unknown scan backend %q", backend)
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+)
+
+const signatureReadSize = 64 * 1024
+
+// Signature is a byte sequence that marks content containing it as infected.
+type Signature struct {
+	Name    string
+	Pattern []byte
+}
+
+// EICAR is the standard antivirus test file. It is joined at run time so this
+// source file and the binary are not reported by scanners themselves.
+var EICAR = Signature{
+	Name: "Eicar-Test-Signature",
+	Pattern: []byte(strings.Join([]string{
+		`X5O!P%@AP[4\PZX54(P^)7CC)7}$`,
+		`EICAR-STANDARD-ANTIVIRUS-`,
+		`TEST-FILE!$H+H*`,
+	}, "")),
+}
+
+// SignatureScanner reports content containing one of its signatures anywhere.
+// It knows no real malware and is meant for tests and development setups.
+type SignatureScanner struct {
+	signatures []Signature
+	longest    int
+}
+
+// NewSignatureScanner matches the given signatures, or only EICAR when none
+// are given.
+func NewSignatureScanner(signatures ...Signature) *SignatureScanner {
+	if len(signatures) == 0 {
+		signatures = []Signature{EICAR}
+	}
+
+	s := &SignatureScanner{}
+	for _, signature := range signatures {
+		if len(signature.Pattern) == 0 {
+			continue
+		}
+		s.signatures = append(s.signatures, signature)
+		s.longest = max(s.longest, len(signature.Pattern))
+	}
+	return s
+}
+
+// Scan reads the content in blocks, carrying the tail of each block over so
+// that signatures spanning two reads are found as well.
+func (s *SignatureScanner) Scan(ctx context.Context, r io.Reader) (string, error) {
+	carry := max(s.longest-1, 0)
+	buf := make([]byte, carry+signatureReadSize)
+	kept := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		n, err := r.Read(buf[kept:])
+		data := buf[:kept+n]
+
+		for _, signature := range s.signatures {
+			if bytes.Contains(data, signature.Pattern) {
+				return signature.Name, nil
+			}
+		}
+
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		kept = min(len(data), carry)
+		copy(buf, data[len(data)-kept:])
+	}
+}
@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkReader hands out its data at most size bytes per read, like a network
+// body does, so reads end exactly where a test wants them to.
+type chunkReader struct {
+	data []byte
+	size int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p[:min(len(p), r.size)], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestSignatureScanner_Scan(t *testing.T) {
+	eicar := EICAR.Pattern
+	padding := bytes.Repeat([]byte("a"), signatureReadSize)
+
+	tests := []struct {
+		name    string
+		content io.Reader
+		want    string
+	}{
+		{name: "empty", content: bytes.NewReader(nil), want: ""},
+		{name: "clean", content: bytes.NewReader(padding), want: ""},
+		{name: "eicar", content: bytes.NewReader(eicar), want: EICAR.Name},
+		{name: "eicar after other content", content: bytes.NewReader(append(padding, eicar...)), want: EICAR.Name},
+		{name: "eicar one byte per read", content: iotest.OneByteReader(bytes.NewReader(eicar)), want: EICAR.Name},
+		{
+			name:    "eicar across two reads",
+			content: &chunkReader{data: append(padding[:signatureReadSize-10], eicar...), size: signatureReadSize},
+			want:    EICAR.Name,
+		},
+		{
+			name:    "eicar across many reads",
+			content: &chunkReader{data: append(bytes.Repeat(padding, 3)[:3*signatureReadSize-1], eicar...), size: signatureReadSize},
+			want:    EICAR.Name,
+		},
+		{name: "eicar with read error", content: iotest.DataErrReader(bytes.NewReader(eicar)), want: EICAR.Name},
+		{name: "eicar cut short", content: bytes.NewReader(eicar[:len(eicar)-1]), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := NewSignatureScanner().Scan(context.Background(), tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, name)
+		})
+	}
+}
+
+func TestSignatureScanner_CustomSignatures(t *testing.T) {
+	s := NewSignatureScanner(
+		Signature{Name: "Empty"},
+		Signature{Name: "Short", Pattern: []byte("bad")},
+		Signature{Name: "Long", Pattern: []byte("much worse")},
+	)
+
+	name, err := s.Scan(context.Background(), strings.NewReader(string(EICAR.Pattern)))
+	require.NoError(t, err)
+	assert.Empty(t, name, "EICAR is only the default")
+
+	name, err = s.Scan(context.Background(), iotest.OneByteReader(strings.NewReader("this is much worse")))
+	require.NoError(t, err)
+	assert.Equal(t, "Long", name)
+
+	name, err = s.Scan(context.Background(), strings.NewReader("nothing to see"))
+	require.NoError(t, err)
+	assert.Empty(t, name)
+}
+
+func TestSignatureScanner_Errors(t *testing.T) {
+	readErr := errors.New("connection reset")
+	_, err := NewSignatureScanner().Scan(context.Background(), iotest.ErrReader(readErr))
+	assert.ErrorIs(t, err, readErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewSignatureScanner().Scan(ctx, bytes.NewReader(EICAR.Pattern))
+	assert.ErrorIs(t, err, context.Canceled)
+}
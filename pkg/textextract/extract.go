@@ -0,0 +1,200 @@
+// Package textextract pulls the readable text out of documents for indexing.
+package textextract
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupported is returned for formats no text can be extracted from.
+var ErrUnsupported = errors.New("unsupported format")
+
+type format int
+
+const (
+	formatUnknown format = iota
+	formatText
+	formatJSON
+	formatXML
+	formatPDF
+)
+
+var extensionFormats = map[string]format{
+	".txt":      formatText,
+	".text":     formatText,
+	".md":       formatText,
+	".markdown": formatText,
+	".json":     formatJSON,
+	".xml":      formatXML,
+	".pdf":      formatPDF,
+}
+
+func detect(mimeType, filename string) format {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(mimeType))
+	}
+
+	switch {
+	case mediaType == "text/plain", mediaType == "text/markdown", mediaType == "text/x-markdown":
+		return formatText
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		return formatJSON
+	case mediaType == "application/xml", mediaType == "text/xml", strings.HasSuffix(mediaType, "+xml"):
+		return formatXML
+	case mediaType == "application/pdf":
+		return formatPDF
+	}
+
+	// Uploads of unknown types are stored as application/octet-stream.
+	return extensionFormats[strings.ToLower(filepath.Ext(filename))]
+}
+
+// Supports reports whether Extract handles a file of this type.
+func Supports(mimeType, filename string) bool {
+	return detect(mimeType, filename) != formatUnknown
+}
+
+// Extract returns at most limit bytes of the text of a plain text, Markdown,
+// JSON, XML or PDF document. The format is taken from the MIME type, or the
+// file extension when the type is generic.
+func Extract(r io.Reader, mimeType, filename string, limit int) (string, error) {
+	var text string
+	var err error
+
+	switch detect(mimeType, filename) {
+	case formatText:
+		text, err = extractText(r, limit)
+	case formatJSON:
+		text, err = extractJSON(r, limit)
+	case formatXML:
+		text, err = extractXML(r, limit)
+	case formatPDF:
+		text, err = extractPDF(r, limit)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return clean(text, limit), nil
+}
+
+func extractText(r io.Reader, limit int) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// extractJSON keeps keys and scalar values, which is what people search for.
+func extractJSON(r io.Reader, limit int) (string, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	text := newTextBuilder(limit)
+	for !text.full() {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch value := token.(type) {
+		case string:
+			text.word(value)
+		case json.Number:
+			text.word(value.String())
+		case bool:
+			if value {
+				text.word("true")
+			} else {
+				text.word("false")
+			}
+		}
+	}
+
+	return text.String(), nil
+}
+
+// extractXML keeps character data and attribute values.
+func extractXML(r io.Reader, limit int) (string, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	text := newTextBuilder(limit)
+	for !text.full() {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch value := token.(type) {
+		case xml.StartElement:
+			for _, attr := range value.Attr {
+				text.word(attr.Value)
+			}
+		case xml.CharData:
+			text.word(string(bytes.TrimSpace(value)))
+		}
+	}
+
+	return text.String(), nil
+}
+
+// clean makes the text storable: valid UTF-8, no NUL bytes, at most limit bytes.
+func clean(text string, limit int) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\x00", "")
+
+	if len(text) > limit {
+		text = text[:limit]
+		for !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+
+	return strings.TrimSpace(text)
+}
+
+// textBuilder collects text up to a size limit.
+type textBuilder struct {
+	strings.Builder
+	limit int
+}
+
+func newTextBuilder(limit int) *textBuilder {
+	return &textBuilder{limit: limit}
+}
+
+func (b *textBuilder) full() bool {
+	return b.Len() >= b.limit
+}
+
+// word appends s separated from the previous text by a space.
+func (b *textBuilder) word(s string) {
+	if s == "" || b.full() {
+		return
+	}
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(s)
+}
@@ -0,0 +1,685 @@
+package textextract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// maxStreamSize caps a single decompressed stream against zip bombs, and
+	// maxDecodedSize all the stream data read from one document.
+	maxStreamSize  = 64 << 20
+	maxDecodedSize = 256 << 20
+	maxPDFDepth    = 32
+	maxCMapCodes   = 1 << 17
+	// maxFormCalls bounds the form XObjects shown per document, since forms
+	// may show each other any number of times.
+	maxFormCalls = 1024
+)
+
+var objectHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// extractPDF reads the text shown on each page. It handles what text-based
+// PDFs are made of: uncompressed and Flate streams, object streams, simple
+// fonts and fonts with a ToUnicode map. Scanned pages have no text to read.
+func extractPDF(r io.Reader, limit int) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	header := data[:min(len(data), 1024)]
+	if !bytes.Contains(header, []byte("%PDF-")) {
+		return "", errors.New("not a PDF file")
+	}
+
+	doc := parsePDF(data)
+	if doc.encrypted {
+		return "", fmt.Errorf("%w: encrypted PDF", ErrUnsupported)
+	}
+
+	text := &pageText{limit: limit}
+	for _, page := range doc.pages() {
+		if text.full() {
+			break
+		}
+		doc.showPage(text, page)
+		text.newline()
+	}
+
+	return text.String(), nil
+}
+
+type pdfObject struct {
+	value any
+	// stream holds the raw stream data, nil for objects without one.
+	stream []byte
+}
+
+type pdfDocument struct {
+	objects   map[int]*pdfObject
+	cmaps     map[int]*cmap
+	encrypted bool
+	// decoded and formCalls count the work spent against the limits above.
+	decoded   int
+	formCalls int
+}
+
+func parsePDF(data []byte) *pdfDocument {
+	doc := &pdfDocument{
+		objects: make(map[int]*pdfObject),
+		cmaps:   make(map[int]*cmap),
+	}
+
+	// Objects are located by their headers rather than the xref table, which
+	// copes with broken tables. Later definitions replace earlier ones like
+	// incremental updates do. Headers inside the previous object, in a string
+	// or stream, are not objects of their own.
+	skipUntil := 0
+	for _, match := range objectHeader.FindAllSubmatchIndex(data, -1) {
+		if match[0] < skipUntil {
+			continue
+		}
+		num, err := strconv.Atoi(string(data[match[2]:match[3]]))
+		if err != nil {
+			continue
+		}
+
+		l := &lexer{data: data, pos: match[1]}
+		value, _ := l.object()
+		object := &pdfObject{value: value}
+
+		afterValue := l.pos
+		skipUntil = afterValue
+		if tok := l.next(); tok.kind == tokenKeyword && tok.text == "stream" {
+			start, end := streamBounds(data, l.pos, value)
+			object.stream = data[start:end]
+			skipUntil = end
+		}
+
+		doc.objects[num] = object
+	}
+
+	for _, num := range doc.sortedObjects() {
+		dict, _ := doc.objects[num].value.(pdfDict)
+		if dict["Type"] == pdfName("ObjStm") {
+			doc.loadObjectStream(doc.objects[num])
+		}
+		if dict["Encrypt"] != nil {
+			doc.encrypted = true
+		}
+	}
+	if bytes.Contains(data, []byte("/Encrypt")) && trailerEncrypted(data) {
+		doc.encrypted = true
+	}
+
+	return doc
+}
+
+// trailerEncrypted looks for an encryption dictionary in classic trailers,
+// cross-reference streams are checked with the other objects.
+func trailerEncrypted(data []byte) bool {
+	for offset := 0; ; {
+		index := bytes.Index(data[offset:], []byte("trailer"))
+		if index < 0 {
+			return false
+		}
+		offset += index + len("trailer")
+
+		l := &lexer{data: data, pos: offset}
+		value, _ := l.object()
+		if dict, ok := value.(pdfDict); ok && dict["Encrypt"] != nil {
+			return true
+		}
+		offset = max(offset, l.pos)
+	}
+}
+
+// streamBounds finds the data following the stream keyword at pos. The
+// declared length is trusted only when endstream follows it.
+func streamBounds(data []byte, pos int, value any) (int, int) {
+	if pos < len(data) && data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(data) && data[pos] == '\n' {
+		pos++
+	}
+
+	if dict, ok := value.(pdfDict); ok {
+		if length, ok := dict["Length"].(float64); ok && length >= 0 && length <= float64(len(data)-pos) {
+			end := pos + int(length)
+			rest := bytes.TrimLeft(data[end:min(len(data), end+32)], "\r\n \t")
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				return pos, end
+			}
+		}
+	}
+
+	index := bytes.Index(data[pos:], []byte("endstream"))
+	if index < 0 {
+		return pos, len(data)
+	}
+	end := pos + index
+	if end > pos && data[end-1] == '\n' {
+		end--
+	}
+	if end > pos && data[end-1] == '\r' {
+		end--
+	}
+	return pos, end
+}
+
+func (doc *pdfDocument) sortedObjects() []int {
+	nums := make([]int, 0, len(doc.objects))
+	for num := range doc.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// loadObjectStream adds the objects packed into an object stream. Objects
+// defined directly in the file take precedence.
+func (doc *pdfDocument) loadObjectStream(object *pdfObject) {
+	dict, _ := object.value.(pdfDict)
+	count, _ := dict["N"].(float64)
+	first, _ := dict["First"].(float64)
+
+	data := doc.decode(object)
+	if data == nil || first < 0 || first > float64(len(data)) {
+		return
+	}
+
+	header := &lexer{data: data[:int(first)]}
+	for i := 0; i < int(count); i++ {
+		num, offset := header.next(), header.next()
+		if num.kind != tokenNumber || offset.kind != tokenNumber {
+			return
+		}
+		if _, exists := doc.objects[int(num.num)]; exists {
+			continue
+		}
+
+		if offset.num < 0 || offset.num >= float64(len(data)-int(first)) {
+			continue
+		}
+		l := &lexer{data: data, pos: int(first) + int(offset.num)}
+		value, _ := l.object()
+		doc.objects[int(num.num)] = &pdfObject{value: value}
+	}
+}
+
+// decode returns the stream data of an object, or nil when it uses a filter
+// other than Flate or the document has used up maxDecodedSize.
+func (doc *pdfDocument) decode(object *pdfObject) []byte {
+	if object == nil || object.stream == nil {
+		return nil
+	}
+
+	remaining := maxDecodedSize - doc.decoded
+	if remaining <= 0 {
+		return nil
+	}
+
+	dict, _ := object.value.(pdfDict)
+	var filters []any
+	switch filter := doc.resolve(dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{filter}
+	case pdfArray:
+		filters = filter
+	}
+
+	data := object.stream
+	for _, filter := range filters {
+		if filter != pdfName("FlateDecode") && filter != pdfName("Fl") {
+			return nil
+		}
+
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil
+		}
+		// Truncated streams still yield their readable beginning.
+		data, _ = io.ReadAll(io.LimitReader(reader, int64(min(maxStreamSize, remaining))))
+		reader.Close()
+	}
+
+	doc.decoded += len(data)
+	if doc.decoded > maxDecodedSize {
+		return nil
+	}
+
+	return data
+}
+
+func (doc *pdfDocument) resolve(value any) any {
+	for i := 0; i < maxPDFDepth; i++ {
+		ref, ok := value.(pdfRef)
+		if !ok {
+			return value
+		}
+		object := doc.objects[int(ref)]
+		if object == nil {
+			return nil
+		}
+		value = object.value
+	}
+	return nil
+}
+
+func (doc *pdfDocument) dict(value any) pdfDict {
+	dict, _ := doc.resolve(value).(pdfDict)
+	return dict
+}
+
+// pages returns the pages in reading order from the page tree, or in object
+// order when the tree cannot be followed.
+func (doc *pdfDocument) pages() []pdfDict {
+	var pages []pdfDict
+
+	for _, num := range doc.sortedObjects() {
+		catalog, _ := doc.objects[num].value.(pdfDict)
+		if catalog["Type"] != pdfName("Catalog") {
+			continue
+		}
+		doc.collectPages(doc.dict(catalog["Pages"]), &pages, make(map[pdfRef]bool), 0)
+		if len(pages) > 0 {
+			return pages
+		}
+	}
+
+	for _, num := range doc.sortedObjects() {
+		page, _ := doc.objects[num].value.(pdfDict)
+		if page["Type"] == pdfName("Page") {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// collectPages follows each reference once, so a tree whose nodes list the
+// same kids over and over does not grow without end.
+func (doc *pdfDocument) collectPages(node pdfDict, pages *[]pdfDict, seen map[pdfRef]bool, depth int) {
+	if node == nil || depth > maxPDFDepth {
+		return
+	}
+
+	if node["Type"] == pdfName("Page") {
+		*pages = append(*pages, node)
+		return
+	}
+
+	kids, _ := doc.resolve(node["Kids"]).(pdfArray)
+	for _, kid := range kids {
+		if ref, ok := kid.(pdfRef); ok {
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+		}
+		doc.collectPages(doc.dict(kid), pages, seen, depth+1)
+	}
+}
+
+// showPage appends the text of a page, resources being inherited from the page tree.
+func (doc *pdfDocument) showPage(text *pageText, page pdfDict) {
+	resources := page
+	for i := 0; resources != nil && resources["Resources"] == nil && i < maxPDFDepth; i++ {
+		resources = doc.dict(resources["Parent"])
+	}
+	if resources != nil {
+		resources = doc.dict(resources["Resources"])
+	}
+
+	var content [][]byte
+	switch contents := doc.resolve(page["Contents"]).(type) {
+	case pdfArray:
+		for _, part := range contents {
+			if ref, ok := part.(pdfRef); ok {
+				content = append(content, doc.decode(doc.objects[int(ref)]))
+			}
+		}
+	default:
+		if ref, ok := page["Contents"].(pdfRef); ok {
+			content = append(content, doc.decode(doc.objects[int(ref)]))
+		}
+	}
+
+	// Content may be split anywhere, even inside an operator.
+	doc.showContent(text, bytes.Join(content, []byte("\n")), resources, 0)
+}
+
+// showContent runs the text operators of a content stream.
+func (doc *pdfDocument) showContent(text *pageText, content []byte, resources pdfDict, depth int) {
+	if depth > maxPDFDepth/4 {
+		return
+	}
+
+	fonts := doc.fonts(resources)
+	var font *pdfFont
+	var operands []any
+
+	l := &lexer{data: content}
+	for !text.full() {
+		value, ok := l.object()
+		if !ok {
+			return
+		}
+
+		operator, ok := value.(pdfKeyword)
+		if !ok {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch operator {
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					font = fonts[string(name)]
+				}
+			}
+		case "Tj":
+			if s, ok := lastOperand(operands).(pdfString); ok {
+				text.write(font.decode(string(s)))
+			}
+		case "'", "\"":
+			text.newline()
+			if s, ok := lastOperand(operands).(pdfString); ok {
+				text.write(font.decode(string(s)))
+			}
+		case "TJ":
+			parts, _ := lastOperand(operands).(pdfArray)
+			for _, part := range parts {
+				switch part := part.(type) {
+				case pdfString:
+					text.write(font.decode(string(part)))
+				case float64:
+					// Large negative adjustments are how many generators space words.
+					if part < -200 {
+						text.space()
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[len(operands)-1].(float64); ok && ty != 0 {
+					text.newline()
+					break
+				}
+			}
+			text.space()
+		case "T*", "ET":
+			text.newline()
+		case "Tm":
+			text.space()
+		case "ID":
+			l.skipInlineImage()
+		case "Do":
+			if name, ok := lastOperand(operands).(pdfName); ok {
+				doc.showForm(text, resources, string(name), depth)
+			}
+		}
+
+		operands = operands[:0]
+	}
+}
+
+// showForm shows the text of a form XObject, used for repeated content such
+// as headers.
+func (doc *pdfDocument) showForm(text *pageText, resources pdfDict, name string, depth int) {
+	ref, ok := doc.dict(resources["XObject"])[name].(pdfRef)
+	if !ok || doc.formCalls >= maxFormCalls {
+		return
+	}
+	doc.formCalls++
+
+	object := doc.objects[int(ref)]
+	if object == nil {
+		return
+	}
+	form, _ := object.value.(pdfDict)
+	if form["Subtype"] != pdfName("Form") {
+		return
+	}
+
+	formResources := doc.dict(form["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+
+	doc.showContent(text, doc.decode(object), formResources, depth+1)
+}
+
+func lastOperand(operands []any) any {
+	if len(operands) == 0 {
+		return nil
+	}
+	return operands[len(operands)-1]
+}
+
+type pdfFont struct {
+	// composite fonts use multi-byte codes that mean nothing without a ToUnicode map.
+	composite bool
+	toUnicode *cmap
+}
+
+func (doc *pdfDocument) fonts(resources pdfDict) map[string]*pdfFont {
+	fonts := make(map[string]*pdfFont)
+
+	for name, value := range doc.dict(resources["Font"]) {
+		dict := doc.dict(value)
+		if dict == nil {
+			continue
+		}
+
+		font := &pdfFont{composite: dict["Subtype"] == pdfName("Type0")}
+		if ref, ok := dict["ToUnicode"].(pdfRef); ok {
+			font.toUnicode = doc.cmap(int(ref))
+		}
+		fonts[name] = font
+	}
+
+	return fonts
+}
+
+func (doc *pdfDocument) cmap(num int) *cmap {
+	if cached, ok := doc.cmaps[num]; ok {
+		return cached
+	}
+
+	var result *cmap
+	if data := doc.decode(doc.objects[num]); data != nil {
+		result = parseCMap(data)
+	}
+	doc.cmaps[num] = result
+
+	return result
+}
+
+// decode turns the bytes of a shown string into text.
+func (f *pdfFont) decode(s string) string {
+	if f != nil && f.toUnicode != nil {
+		return f.toUnicode.decode(s, f.composite)
+	}
+	if f != nil && f.composite {
+		return ""
+	}
+	if strings.HasPrefix(s, "\xfe\xff") {
+		return decodeUTF16(s[2:])
+	}
+
+	// Simple fonts mostly use WinAnsi or a standard encoding, which agree
+	// with Latin-1 on letters and digits.
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
+
+// cmap maps character codes to Unicode text, as found in ToUnicode streams.
+type cmap struct {
+	codeLength int
+	chars      map[int]string
+}
+
+func parseCMap(data []byte) *cmap {
+	result := &cmap{chars: make(map[int]string)}
+	var operands []any
+
+	l := &lexer{data: data}
+	for {
+		value, ok := l.object()
+		if !ok {
+			return result
+		}
+
+		operator, ok := value.(pdfKeyword)
+		if !ok {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch operator {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if low, ok := operands[0].(pdfString); ok {
+					result.codeLength = len(low)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, okSrc := operands[i].(pdfString)
+				dst, okDst := operands[i+1].(pdfString)
+				if okSrc && okDst && len(result.chars) < maxCMapCodes {
+					result.chars[codeOf(string(src))] = decodeUTF16(string(dst))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, okLow := operands[i].(pdfString)
+				high, okHigh := operands[i+1].(pdfString)
+				if okLow && okHigh {
+					result.addRange(codeOf(string(low)), codeOf(string(high)), operands[i+2])
+				}
+			}
+		}
+
+		operands = operands[:0]
+	}
+}
+
+func (c *cmap) addRange(low, high int, dst any) {
+	switch dst := dst.(type) {
+	case pdfString:
+	case pdfArray:
+		// Codes past the end of the array have no text.
+		high = min(high, low+len(dst)-1)
+	default:
+		return
+	}
+
+	for code := low; code <= high && len(c.chars) < maxCMapCodes; code++ {
+		switch dst := dst.(type) {
+		case pdfString:
+			// The last UTF-16 unit is incremented along the range.
+			units := []byte(dst)
+			if len(units) < 2 {
+				units = append([]byte{0}, units...)
+			}
+			last := int(units[len(units)-2])<<8 | int(units[len(units)-1])
+			last += code - low
+			units[len(units)-2], units[len(units)-1] = byte(last>>8), byte(last)
+			c.chars[code] = decodeUTF16(string(units))
+		case pdfArray:
+			if index := code - low; index < len(dst) {
+				if s, ok := dst[index].(pdfString); ok {
+					c.chars[code] = decodeUTF16(string(s))
+				}
+			}
+		}
+	}
+}
+
+func (c *cmap) decode(s string, composite bool) string {
+	length := c.codeLength
+	if length == 0 {
+		length = 1
+		if composite {
+			length = 2
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i+length <= len(s); i += length {
+		code := codeOf(s[i : i+length])
+		if text, ok := c.chars[code]; ok {
+			out.WriteString(text)
+		} else if !composite {
+			out.WriteRune(rune(code))
+		}
+	}
+	return out.String()
+}
+
+func codeOf(s string) int {
+	code := 0
+	for i := 0; i < len(s) && i < 4; i++ {
+		code = code<<8 | int(s[i])
+	}
+	return code
+}
+
+func decodeUTF16(s string) string {
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// pageText collects page text up to a size limit, folding runs of spacing.
+type pageText struct {
+	strings.Builder
+	limit int
+}
+
+func (t *pageText) full() bool {
+	return t.Len() >= t.limit
+}
+
+func (t *pageText) write(s string) {
+	if !t.full() {
+		t.WriteString(s)
+	}
+}
+
+func (t *pageText) space() {
+	t.separate(' ')
+}
+
+func (t *pageText) newline() {
+	t.separate('\n')
+}
+
+func (t *pageText) separate(c byte) {
+	if t.Len() == 0 || t.full() {
+		return
+	}
+	s := t.String()
+	last := s[len(s)-1]
+	if last == '\n' || (last == ' ' && c == ' ') {
+		return
+	}
+	t.WriteByte(c)
+}
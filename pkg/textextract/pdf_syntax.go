@@ -0,0 +1,365 @@
+package textextract
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PDF objects as produced by lexer.object. Numbers are float64, references
+// keep only the object number since generations are not tracked.
+type (
+	pdfName    string
+	pdfString  string
+	pdfKeyword string
+	pdfRef     int
+	pdfDict    map[string]any
+	pdfArray   []any
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenName
+	tokenString
+	tokenKeyword
+	tokenDictStart
+	tokenDictEnd
+	tokenArrayStart
+	tokenArrayEnd
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+// maxNesting stops malformed files from nesting arrays and dictionaries without
+// end. An unclosed array or dictionary ends at the endobj keyword.
+const maxNesting = 64
+
+// lexer reads the PDF object syntax, shared by file bodies, content streams and CMaps.
+type lexer struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func isWhite(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *lexer) peek(offset int) byte {
+	if l.pos+offset < len(l.data) {
+		return l.data[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isWhite(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) next() token {
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return token{kind: tokenEOF}
+		}
+
+		switch c := l.data[l.pos]; c {
+		case '(':
+			l.pos++
+			return token{kind: tokenString, text: l.literalString()}
+		case '<':
+			if l.peek(1) == '<' {
+				l.pos += 2
+				return token{kind: tokenDictStart}
+			}
+			l.pos++
+			return token{kind: tokenString, text: l.hexString()}
+		case '>':
+			if l.peek(1) == '>' {
+				l.pos += 2
+				return token{kind: tokenDictEnd}
+			}
+			l.pos++
+		case '[':
+			l.pos++
+			return token{kind: tokenArrayStart}
+		case ']':
+			l.pos++
+			return token{kind: tokenArrayEnd}
+		case '/':
+			l.pos++
+			return token{kind: tokenName, text: l.name()}
+		case '{', '}', ')':
+			l.pos++
+		default:
+			start := l.pos
+			for l.pos < len(l.data) && !isWhite(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			word := string(l.data[start:l.pos])
+			if strings.IndexByte("+-.0123456789", c) >= 0 {
+				if num, err := strconv.ParseFloat(word, 64); err == nil {
+					return token{kind: tokenNumber, text: word, num: num}
+				}
+			}
+			return token{kind: tokenKeyword, text: word}
+		}
+	}
+}
+
+func (l *lexer) literalString() string {
+	var out []byte
+	depth := 1
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(out)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return string(out)
+			}
+			c = l.data[l.pos]
+			l.pos++
+
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.peek(0) == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					value := int(c - '0')
+					for i := 0; i < 2 && l.peek(0) >= '0' && l.peek(0) <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(value)
+				}
+			}
+		}
+
+		out = append(out, c)
+	}
+
+	return string(out)
+}
+
+func (l *lexer) hexString() string {
+	var out []byte
+	var high byte
+	half := false
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+
+		value, ok := unhex(c)
+		if !ok {
+			continue
+		}
+		if half {
+			out = append(out, high<<4|value)
+		} else {
+			high = value
+		}
+		half = !half
+	}
+
+	// A missing final digit counts as 0.
+	if half {
+		out = append(out, high<<4)
+	}
+
+	return string(out)
+}
+
+func (l *lexer) name() string {
+	var out []byte
+
+	for l.pos < len(l.data) && !isWhite(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '#' && l.pos+1 < len(l.data) {
+			high, okHigh := unhex(l.data[l.pos])
+			low, okLow := unhex(l.data[l.pos+1])
+			if okHigh && okLow {
+				c = high<<4 | low
+				l.pos += 2
+			}
+		}
+		out = append(out, c)
+	}
+
+	return string(out)
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// object reads the next object, reporting false at the end of the data.
+// Stray closing brackets are skipped.
+func (l *lexer) object() (any, bool) {
+	for {
+		tok := l.next()
+		switch tok.kind {
+		case tokenEOF:
+			return nil, false
+		case tokenDictEnd, tokenArrayEnd:
+			continue
+		}
+		return l.value(tok), true
+	}
+}
+
+func (l *lexer) value(tok token) any {
+	switch tok.kind {
+	case tokenNumber:
+		return l.numberOrRef(tok)
+	case tokenName:
+		return pdfName(tok.text)
+	case tokenString:
+		return pdfString(tok.text)
+	case tokenKeyword:
+		return pdfKeyword(tok.text)
+	case tokenDictStart:
+		return l.dict()
+	case tokenArrayStart:
+		return l.array()
+	}
+	return nil
+}
+
+// numberOrRef turns "12 0 R" into a reference and anything else into a number.
+func (l *lexer) numberOrRef(tok token) any {
+	start := l.pos
+
+	generation := l.next()
+	if generation.kind == tokenNumber {
+		if keyword := l.next(); keyword.kind == tokenKeyword && keyword.text == "R" {
+			return pdfRef(int(tok.num))
+		}
+	}
+
+	l.pos = start
+	return tok.num
+}
+
+func (l *lexer) dict() pdfDict {
+	dict := pdfDict{}
+
+	l.depth++
+	defer func() { l.depth-- }()
+
+	for {
+		key := l.next()
+		switch key.kind {
+		case tokenEOF, tokenDictEnd:
+			return dict
+		case tokenKeyword:
+			if key.text == "endobj" {
+				return dict
+			}
+		case tokenName:
+			value := l.next()
+			if value.kind == tokenDictEnd || value.kind == tokenEOF {
+				return dict
+			}
+			if l.depth < maxNesting || (value.kind != tokenDictStart && value.kind != tokenArrayStart) {
+				dict[key.text] = l.value(value)
+			}
+		}
+	}
+}
+
+func (l *lexer) array() pdfArray {
+	var array pdfArray
+
+	l.depth++
+	defer func() { l.depth-- }()
+
+	for {
+		tok := l.next()
+		switch tok.kind {
+		case tokenEOF, tokenArrayEnd:
+			return array
+		case tokenDictEnd:
+			continue
+		case tokenKeyword:
+			if tok.text == "endobj" {
+				return array
+			}
+		}
+		if l.depth < maxNesting || (tok.kind != tokenDictStart && tok.kind != tokenArrayStart) {
+			array = append(array, l.value(tok))
+		}
+	}
+}
+
+// skipInlineImage moves past the binary data of an inline image, which
+// follows the ID operator and ends with EI.
+func (l *lexer) skipInlineImage() {
+	for i := l.pos + 1; i+1 < len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && isWhite(l.data[i-1]) &&
+			(i+2 == len(l.data) || isWhite(l.data[i+2]) || isDelimiter(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
@@ -0,0 +1,362 @@
+package textextract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLimit = 1 << 20
+
+// pdfFile numbers the objects from 1 and points the trailer at the first one.
+func pdfFile(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	for i, object := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func stream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func deflate(data []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+// onePage is a document with a single page showing content with font F1.
+func onePage(content string) []byte {
+	return pdfFile(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		stream("", []byte(content)),
+	)
+}
+
+// extractWithin fails the test when extraction does not finish in time, the
+// parser must not loop on hostile input.
+func extractWithin(t *testing.T, data []byte) (string, error) {
+	t.Helper()
+
+	type result struct {
+		text string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		text, err := Extract(bytes.NewReader(data), "application/pdf", "file.pdf", testLimit)
+		done <- result{text, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.text, r.err
+	case <-time.After(5 * time.Second):
+		t.Fatal("extraction did not finish")
+		return "", nil
+	}
+}
+
+func TestExtractPDF(t *testing.T) {
+	content := []byte("BT /F1 12 Tf 72 700 Td (Hello world) Tj ET")
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "uncompressed stream",
+			data: onePage(string(content)),
+			want: "Hello world",
+		},
+		{
+			name: "flate stream",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				stream("/Filter /FlateDecode", deflate(content)),
+			),
+			want: "Hello world",
+		},
+		{
+			name: "filter array",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				stream("/Filter [/FlateDecode /Fl]", deflate(deflate(content))),
+			),
+			want: "Hello world",
+		},
+		{
+			name: "unsupported filter",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				stream("/Filter /LZWDecode", content),
+			),
+			want: "",
+		},
+		{
+			name: "escapes and octal codes",
+			data: onePage(`BT (Line\(1\)\nsecond \101\102C) Tj ET`),
+			want: "Line(1)\nsecond ABC",
+		},
+		{
+			name: "hex string and word spacing",
+			data: onePage("BT [<48656c6c6f> -300 (world)] TJ ET"),
+			want: "Hello world",
+		},
+		{
+			name: "pages in order across nested page tree",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R 6 0 R] /Count 2 /Resources 4 0 R >>",
+				"<< /Type /Pages /Parent 2 0 R /Kids [5 0 R] /Count 1 >>",
+				"<< /Font << /F1 << /Type /Font /Subtype /Type1 >> >> >>",
+				"<< /Type /Page /Parent 3 0 R /Contents 7 0 R >>",
+				"<< /Type /Page /Parent 2 0 R /Contents [8 0 R 9 0 R] >>",
+				stream("", []byte("BT /F1 9 Tf (first) Tj ET")),
+				stream("", []byte("BT /F1 9 Tf (second)")),
+				stream("", []byte("Tj ET")),
+			),
+			want: "first\nsecond",
+		},
+		{
+			name: "object stream",
+			data: func() []byte {
+				pages := "<< /Type /Pages /Kids [7 0 R] /Count 1 >> "
+				packed := pages + "<< /Type /Page /Parent 6 0 R /Contents 3 0 R >>"
+				header := fmt.Sprintf("6 0 7 %d ", len(pages))
+				return pdfFile(
+					"<< /Type /Catalog /Pages 6 0 R >>",
+					stream(fmt.Sprintf("/Type /ObjStm /N 2 /First %d /Filter /FlateDecode", len(header)), deflate([]byte(header+packed))),
+					stream("", content),
+				)
+			}(),
+			want: "Hello world",
+		},
+		{
+			name: "composite font with ToUnicode map",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 6 0 R >>",
+				"<< /Type /Font /Subtype /Type0 /ToUnicode 5 0 R >>",
+				stream("", []byte("begincmap 1 begincodespacerange <0000> <FFFF> endcodespacerange "+
+					"1 beginbfchar <0001> <0048> endbfchar 1 beginbfrange <0002> <0003> <0069> endbfrange endcmap")),
+				stream("", []byte("BT /F1 12 Tf <000100020003> Tj ET")),
+			),
+			want: "Hij",
+		},
+		{
+			name: "form xobject",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Hd 4 0 R >> >> /Contents 5 0 R >>",
+				stream("/Type /XObject /Subtype /Form", []byte("BT (Header) Tj ET")),
+				stream("", []byte("/Hd Do BT (Body) Tj ET")),
+			),
+			want: "Header\nBody",
+		},
+		{
+			name: "inline image is skipped",
+			data: onePage("BI /W 2 /H 2 ID \x00(\xff)EI\x01 EI BT (after) Tj ET"),
+			want: "after",
+		},
+		{
+			name: "pages without a catalog",
+			data: pdfFile(
+				"<< /Type /Page /Contents 2 0 R >>",
+				stream("", []byte("BT (orphan page) Tj ET")),
+			),
+			want: "orphan page",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := extractWithin(t, tt.data)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, text)
+		})
+	}
+}
+
+func TestExtractPDF_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		unsupported bool
+	}{
+		{name: "empty", data: nil},
+		{name: "not a PDF", data: []byte("GIF89a")},
+		{name: "encrypted object", data: pdfFile("<< /Type /Catalog /Encrypt << /Filter /Standard >> >>"), unsupported: true},
+		{name: "encrypted trailer", data: []byte("%PDF-1.4\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R >>\n"), unsupported: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extractWithin(t, tt.data)
+
+			require.Error(t, err)
+			assert.Equal(t, tt.unsupported, strings.Contains(err.Error(), ErrUnsupported.Error()))
+		})
+	}
+}
+
+// TestExtractPDF_Hostile feeds input crafted to crash the parser, make it loop
+// or do work out of proportion to the file size. Any text is fine as long as
+// extraction returns in time.
+func TestExtractPDF_Hostile(t *testing.T) {
+	deepPages := []string{"<< /Type /Catalog /Pages 2 0 R >>"}
+	for i := 2; i < 40; i++ {
+		deepPages = append(deepPages, fmt.Sprintf("<< /Type /Pages /Kids [%d 0 R %d 0 R %d 0 R] >>", i+1, i+1, i+1))
+	}
+	deepPages = append(deepPages, "<< /Type /Page >>")
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "header only", data: []byte("%PDF-1.7")},
+		{name: "header without objects", data: []byte("%PDF-1.7\n%%EOF\n")},
+		{name: "unterminated string", data: []byte("%PDF-1.4\n1 0 obj\n(never closed \\")},
+		{name: "unterminated hex string", data: []byte("%PDF-1.4\n1 0 obj\n<4142")},
+		{name: "unterminated dictionary", data: []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages")},
+		{name: "stray closing brackets", data: []byte("%PDF-1.4\n1 0 obj\n] >> ) } ] >>\nendobj\n")},
+		{name: "deeply nested arrays", data: []byte("%PDF-1.4\n1 0 obj\n" + strings.Repeat("[", 1<<20))},
+		{name: "deeply nested dictionaries", data: []byte("%PDF-1.4\n1 0 obj\n" + strings.Repeat("<< /A ", 1<<18))},
+		{name: "headers inside unterminated strings", data: []byte("%PDF-1.4\n" + strings.Repeat("1 0 obj (", 1<<18))},
+		{name: "headers inside unterminated arrays", data: []byte("%PDF-1.4\n" + strings.Repeat("1 0 obj [", 1<<18))},
+		{name: "unterminated trailers", data: []byte("%PDF-1.4\n/Encrypt\n" + strings.Repeat("trailer [", 1<<18))},
+		{name: "stream without endstream", data: []byte("%PDF-1.4\n1 0 obj\n<< /Length 10 >>\nstream\nabc")},
+		{name: "huge stream length", data: []byte("%PDF-1.4\n1 0 obj\n<< /Length 1e300 >>\nstream\nabc\nendstream\nendobj\n")},
+		{name: "infinite stream length", data: []byte("%PDF-1.4\n1 0 obj\n<< /Length +Inf >>\nstream\nabc\nendstream\nendobj\n")},
+		{name: "negative stream length", data: []byte("%PDF-1.4\n1 0 obj\n<< /Length -5 >>\nstream\nabc\nendstream\nendobj\n")},
+		{name: "truncated flate stream", data: onePage(string(deflate([]byte("BT (cut) Tj ET"))[:6]))},
+		{
+			name: "object stream with negative first",
+			data: pdfFile(stream("/Type /ObjStm /N 1 /First -5", []byte("2 0 << >>"))),
+		},
+		{
+			name: "object stream with infinite first",
+			data: pdfFile(stream("/Type /ObjStm /N 1 /First -Inf", []byte("2 0 << >>"))),
+		},
+		{
+			name: "object stream with negative offset",
+			data: pdfFile(stream("/Type /ObjStm /N 1000000000 /First 6", []byte("2 -99 << >>"))),
+		},
+		{
+			name: "object stream with offset past the end",
+			data: pdfFile(stream("/Type /ObjStm /N 1 /First 7", []byte("2 1e18 << >>"))),
+		},
+		{
+			name: "page tree cycle",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [2 0 R 1 0 R 2 0 R] >>",
+			),
+		},
+		{name: "page tree with shared kids", data: pdfFile(deepPages...)},
+		{
+			name: "self referencing object",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"2 0 R",
+			),
+		},
+		{
+			name: "resources inherited from a parent cycle",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] >>",
+				"<< /Type /Page /Parent 3 0 R /Contents 4 0 R >>",
+				stream("", []byte("BT (text) Tj ET")),
+			),
+		},
+		{
+			name: "form showing itself",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] >>",
+				"<< /Type /Page /Resources 4 0 R /Contents 6 0 R >>",
+				"<< /XObject << /X 5 0 R >> >>",
+				stream("/Subtype /Form /Resources 4 0 R", []byte(strings.Repeat("/X Do ", 64))),
+				stream("", []byte("/X Do")),
+			),
+		},
+		{
+			name: "cmap range over every code",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] >>",
+				"<< /Type /Page /Resources << /Font << /F1 4 0 R >> >> /Contents 6 0 R >>",
+				"<< /Subtype /Type0 /ToUnicode 5 0 R >>",
+				stream("", []byte("4 beginbfrange <00000000> <FFFFFFFF> [<0041>] "+
+					"<00000000> <FFFFFFFF> 7 <00000000> <FFFFFFFF> /Name <00000000> <FFFFFFFF> <0041> endbfrange")),
+				stream("", []byte("BT /F1 1 Tf <00000000> Tj ET")),
+			),
+		},
+		{
+			name: "same content repeated",
+			data: pdfFile(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] >>",
+				"<< /Type /Page /Contents ["+strings.Repeat("4 0 R ", 1000)+"] >>",
+				stream("", bytes.Repeat([]byte("1 0 0 1 0 0 cm "), 1000)),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractWithin(t, tt.data)
+		})
+	}
+}
+
+func TestExtractPDF_Truncated(t *testing.T) {
+	data := pdfFile(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Subtype /Type0 /ToUnicode 6 0 R >>",
+		stream("/Filter /FlateDecode", deflate([]byte("BT /F1 12 Tf <0001> Tj [(a) -300 (b)] TJ ET"))),
+		stream("", []byte("1 begincodespacerange <0000> <FFFF> endcodespacerange 1 beginbfchar <0001> <0048> endbfchar")),
+	)
+
+	for size := range len(data) {
+		extractWithin(t, data[:size])
+	}
+}
+
+func TestDecode_StopsAtDocumentBudget(t *testing.T) {
+	doc := parsePDF(onePage("BT (Hello) Tj ET"))
+	object := doc.objects[5]
+
+	assert.NotNil(t, doc.decode(object))
+
+	doc.decoded = maxDecodedSize - 1
+	assert.Nil(t, doc.decode(object))
+}
@@ -0,0 +1,151 @@
+// Package thumbnail scales JPEG, PNG and GIF images down to preview sizes
+// using the decoders of the standard library.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"strings"
+)
+
+const jpegQuality = 85
+
+var (
+	// ErrUnsupported is returned for formats other than JPEG, PNG and GIF.
+	ErrUnsupported = errors.New("unsupported image format")
+
+	// ErrTooLarge is returned for images with more pixels than allowed, which
+	// are refused before their pixels are decoded.
+	ErrTooLarge = errors.New("image is too large")
+)
+
+var decoders = map[string]func(io.Reader) (image.Image, error){
+	"jpeg": jpeg.Decode,
+	"png":  png.Decode,
+	"gif":  gif.Decode,
+}
+
+var configDecoders = map[string]func(io.Reader) (image.Config, error){
+	"jpeg": jpeg.DecodeConfig,
+	"png":  png.DecodeConfig,
+	"gif":  gif.DecodeConfig,
+}
+
+func formatOf(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(mimeType))
+	}
+
+	switch mediaType {
+	case "image/jpeg", "image/jpg", "image/pjpeg":
+		return "jpeg"
+	case "image/png":
+		return "png"
+	case "image/gif":
+		return "gif"
+	}
+	return ""
+}
+
+// Decode decodes an image of the given MIME type. Images with more than
+// maxPixels pixels fail with ErrTooLarge. Only the first frame of a GIF is used.
+func Decode(data []byte, mimeType string, maxPixels int) (image.Image, error) {
+	format := formatOf(mimeType)
+	if format == "" {
+		return nil, ErrUnsupported
+	}
+
+	config, err := configDecoders[format](bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("read image header: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("invalid image dimensions %dx%d", config.Width, config.Height)
+	}
+	if config.Width > maxPixels/config.Height {
+		return nil, ErrTooLarge
+	}
+
+	img, err := decoders[format](bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	return img, nil
+}
+
+// Fit scales img down so that neither side exceeds maxEdge, keeping the aspect
+// ratio. Every target pixel is the average of the source pixels it covers.
+// Images that already fit are returned as they are.
+func Fit(img image.Image, maxEdge int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= maxEdge && srcH <= maxEdge {
+		return img
+	}
+
+	dstW, dstH := maxEdge, maxEdge
+	if srcW > srcH {
+		dstH = max(1, srcH*maxEdge/srcW)
+	} else {
+		dstW = max(1, srcW*maxEdge/srcH)
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := span(y, srcH, dstH)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := span(x, srcW, dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			p := dst.Pix[y*dst.Stride+x*4:]
+			p[0], p[1], p[2], p[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+
+	return dst
+}
+
+// span returns the source pixels [from, to) covered by target pixel i.
+func span(i, srcSize, dstSize int) (int, int) {
+	from := i * srcSize / dstSize
+	to := (i + 1) * srcSize / dstSize
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+// Encode writes img as JPEG, or as PNG when it has transparent pixels, and
+// returns the MIME type written.
+func Encode(w io.Writer, img image.Image) (string, error) {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && !opaque.Opaque() {
+		return "image/png", png.Encode(w, img)
+	}
+
+	return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
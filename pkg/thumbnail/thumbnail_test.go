@@ -0,0 +1,224 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const maxPixels = 40_000_000
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encoded(t *testing.T, format string, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+// pngHeader is a PNG that ends after its IHDR chunk, claiming any dimensions
+// without carrying their pixels.
+func pngHeader(w, h uint32) []byte {
+	ihdr := make([]byte, 4+13)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], w)
+	binary.BigEndian.PutUint32(ihdr[8:], h)
+	ihdr[12] = 8 // bit depth
+	ihdr[13] = 6 // RGBA
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	_ = binary.Write(&buf, binary.BigEndian, uint32(13))
+	buf.Write(ihdr)
+	_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+	return buf.Bytes()
+}
+
+// gifHeader is a GIF that ends after its logical screen descriptor.
+func gifHeader(w, h uint16) []byte {
+	data := []byte("GIF89a")
+	data = binary.LittleEndian.AppendUint16(data, w)
+	data = binary.LittleEndian.AppendUint16(data, h)
+	return append(data, 0, 0, 0)
+}
+
+// jpegHeader is a JFIF file that ends after its frame header.
+func jpegHeader(w, h uint16) []byte {
+	data := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10}
+	data = append(data, "JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"...)
+	data = append(data, 0xff, 0xc0, 0x00, 0x0b, 0x08)
+	data = binary.BigEndian.AppendUint16(data, h)
+	data = binary.BigEndian.AppendUint16(data, w)
+	return append(data, 0x01, 0x01, 0x11, 0x00)
+}
+
+func TestDecode(t *testing.T) {
+	img := solid(4, 3, color.RGBA{R: 200, A: 255})
+
+	for _, tt := range []struct {
+		format   string
+		mimeType string
+	}{
+		{format: "png", mimeType: "image/png"},
+		{format: "jpeg", mimeType: "image/jpeg"},
+		{format: "jpeg", mimeType: "image/pjpeg"},
+		{format: "gif", mimeType: "IMAGE/GIF; charset=binary"},
+	} {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			decoded, err := Decode(encoded(t, tt.format, img), tt.mimeType, maxPixels)
+			require.NoError(t, err)
+			assert.Equal(t, image.Rect(0, 0, 4, 3), decoded.Bounds())
+		})
+	}
+}
+
+func TestDecode_Unsupported(t *testing.T) {
+	data := encoded(t, "png", solid(1, 1, color.White))
+
+	for _, mimeType := range []string{"", "image/webp", "image/svg+xml", "image/bmp", "image/tiff", "application/pdf", "png"} {
+		t.Run(mimeType, func(t *testing.T) {
+			_, err := Decode(data, mimeType, maxPixels)
+			assert.ErrorIs(t, err, ErrUnsupported)
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		mimeType string
+	}{
+		{name: "empty", data: nil, mimeType: "image/png"},
+		{name: "not an image", data: []byte("hello"), mimeType: "image/jpeg"},
+		{name: "png declared as jpeg", data: encoded(t, "png", solid(1, 1, color.White)), mimeType: "image/jpeg"},
+		{name: "png without pixels", data: pngHeader(2, 2), mimeType: "image/png"},
+		{name: "truncated jpeg", data: encoded(t, "jpeg", solid(16, 16, color.White))[:100], mimeType: "image/jpeg"},
+		{name: "zero width png", data: pngHeader(0, 10), mimeType: "image/png"},
+		{name: "zero height png", data: pngHeader(10, 0), mimeType: "image/png"},
+		{name: "zero size gif", data: gifHeader(0, 0), mimeType: "image/gif"},
+		{name: "zero width jpeg", data: jpegHeader(0, 10), mimeType: "image/jpeg"},
+		{name: "png beyond int32", data: pngHeader(1<<31-1, 1<<31-1), mimeType: "image/png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Decode(tt.data, tt.mimeType, maxPixels)
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, ErrUnsupported)
+			assert.NotErrorIs(t, err, ErrTooLarge)
+			assert.Nil(t, img)
+		})
+	}
+}
+
+func TestDecode_TooLarge(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		mimeType string
+	}{
+		{name: "png", data: pngHeader(8000, 5001), mimeType: "image/png"},
+		{name: "png one pixel high", data: pngHeader(maxPixels+1, 1), mimeType: "image/png"},
+		{name: "gif", data: gifHeader(65535, 65535), mimeType: "image/gif"},
+		{name: "jpeg", data: jpegHeader(65535, 65535), mimeType: "image/jpeg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The headers carry no pixels, so anything but ErrTooLarge means
+			// the decoder went past the header.
+			_, err := Decode(tt.data, tt.mimeType, maxPixels)
+			assert.ErrorIs(t, err, ErrTooLarge)
+		})
+	}
+
+	t.Run("at the limit", func(t *testing.T) {
+		data := encoded(t, "png", solid(10, 10, color.White))
+
+		_, err := Decode(data, "image/png", 100)
+		assert.NoError(t, err)
+
+		_, err = Decode(data, "image/png", 99)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name    string
+		w, h    int
+		maxEdge int
+		want    image.Rectangle
+	}{
+		{name: "already fits", w: 100, h: 50, maxEdge: 100, want: image.Rect(0, 0, 100, 50)},
+		{name: "landscape", w: 400, h: 200, maxEdge: 100, want: image.Rect(0, 0, 100, 50)},
+		{name: "portrait", w: 200, h: 400, maxEdge: 100, want: image.Rect(0, 0, 50, 100)},
+		{name: "square", w: 300, h: 300, maxEdge: 64, want: image.Rect(0, 0, 64, 64)},
+		{name: "thin line", w: 1000, h: 1, maxEdge: 10, want: image.Rect(0, 0, 10, 1)},
+		{name: "empty", w: 0, h: 0, maxEdge: 10, want: image.Rect(0, 0, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Fit(solid(tt.w, tt.h, color.White), tt.maxEdge).Bounds())
+		})
+	}
+
+	t.Run("averages the covered pixels", func(t *testing.T) {
+		img := solid(2, 2, color.RGBA{A: 255})
+		img.Set(0, 0, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+		img.Set(1, 1, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+		assert.Equal(t, color.RGBA{R: 127, G: 127, B: 127, A: 255}, Fit(img, 1).At(0, 0))
+	})
+
+	t.Run("offset bounds", func(t *testing.T) {
+		img := solid(20, 20, color.White).SubImage(image.Rect(10, 10, 20, 20))
+		assert.Equal(t, image.Rect(0, 0, 5, 5), Fit(img, 5).Bounds())
+	})
+}
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	mimeType, err := Encode(&buf, solid(2, 2, color.RGBA{G: 255, A: 255}))
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", mimeType)
+	_, err = jpeg.Decode(&buf)
+	assert.NoError(t, err)
+
+	buf.Reset()
+	mimeType, err = Encode(&buf, solid(2, 2, color.RGBA{G: 255, A: 128}))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", mimeType)
+	_, err = png.Decode(&buf)
+	assert.NoError(t, err)
+}